@@ -6,6 +6,7 @@ package model
 
 import (
 	"encoding/json"
+	"time"
 
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/orm"
@@ -17,10 +18,21 @@ func init() {
 
 // BankAccount model for bank_account table.
 type BankAccount struct {
-	ID         int64  `orm:"column(id);auto" json:"-"`
-	BankName   string `orm:"column(bank_name);size(45);null" json:"bank_name"`
-	BankNumber string `orm:"column(bank_number);size(45);null" json:"bank_number"`
-	IsDefault  int8   `orm:"column(is_default);null" json:"is_default"`
+	ID             int64     `orm:"column(id);auto" json:"-"`
+	AccountType    string    `orm:"column(account_type);options(bank,cash)" json:"account_type"`
+	BankName       string    `orm:"column(bank_name);size(45);null" json:"bank_name"`
+	BankNumber     string    `orm:"column(bank_number);size(45);null" json:"bank_number"`
+	BankHolder     string    `orm:"column(bank_holder);size(100);null" json:"bank_holder"`
+	OpeningBalance float64   `orm:"column(opening_balance);digits(20);decimals(2)" json:"opening_balance"`
+	OpeningDate    time.Time `orm:"column(opening_date);type(date);null" json:"opening_date"`
+	IsDefault      int8      `orm:"column(is_default);null" json:"is_default"`
+	Note           string    `orm:"column(note);null" json:"note"`
+	IsArchived     int8      `orm:"column(is_archived)" json:"is_archived"`
+	CreatedBy      *User     `orm:"column(created_by);null;rel(fk)" json:"created_by,omitempty"`
+	CreatedAt      time.Time `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedBy      *User     `orm:"column(updated_by);null;rel(fk)" json:"updated_by,omitempty"`
+	UpdatedAt      time.Time `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	Balance        float64   `orm:"-" json:"balance"`
 }
 
 // MarshalJSON customized data struct when marshaling data
@@ -28,13 +40,33 @@ type BankAccount struct {
 func (m *BankAccount) MarshalJSON() ([]byte, error) {
 	type Alias BankAccount
 
-	return json.Marshal(&struct {
-		ID string `json:"id"`
+	alias := &struct {
+		ID          string `json:"id"`
+		CreatedByID string `json:"created_by_id"`
+		UpdatedByID string `json:"updated_by_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
 		Alias: (*Alias)(m),
-	})
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
 }
 
 // Save inserting or updating BankAccount struct into bank_account table.
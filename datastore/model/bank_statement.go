@@ -0,0 +1,107 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(BankStatement))
+}
+
+// BankStatement model for bank_statement table.
+type BankStatement struct {
+	ID                 int64                `orm:"column(id);auto" json:"-"`
+	BankAccount        *BankAccount         `orm:"column(bank_account_id);rel(fk)" json:"bank_account,omitempty"`
+	Code               string               `orm:"column(code);size(45)" json:"code"`
+	FileName           string               `orm:"column(file_name);size(100);null" json:"file_name"`
+	Format             string               `orm:"column(format);options(bca,mandiri,generic)" json:"format"`
+	PeriodStart        time.Time            `orm:"column(period_start);type(date);null" json:"period_start"`
+	PeriodEnd          time.Time            `orm:"column(period_end);type(date);null" json:"period_end"`
+	TotalDebit         float64              `orm:"column(total_debit);digits(20);decimals(2)" json:"total_debit"`
+	TotalCredit        float64              `orm:"column(total_credit);digits(20);decimals(2)" json:"total_credit"`
+	LineCount          int                  `orm:"column(line_count);null" json:"line_count"`
+	MatchedCount       int                  `orm:"column(matched_count);null" json:"matched_count"`
+	Note               string               `orm:"column(note);null" json:"note"`
+	CreatedBy          *User                `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	CreatedAt          time.Time            `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	BankStatementLines []*BankStatementLine `orm:"reverse(many)" json:"bank_statement_lines,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *BankStatement) MarshalJSON() ([]byte, error) {
+	type Alias BankStatement
+
+	alias := &struct {
+		ID            string `json:"id"`
+		BankAccountID string `json:"bank_account_id"`
+		CreatedByID   string `json:"created_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.BankAccountID when m.BankAccount not nill
+	// and the ID is setted
+	if m.BankAccount != nil && m.BankAccount.ID != int64(0) {
+		alias.BankAccountID = common.Encrypt(m.BankAccount.ID)
+	} else {
+		alias.BankAccount = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating BankStatement struct into bank_statement table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to bank_statement.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *BankStatement) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting bank_statement data
+// this also will truncated all data from all table
+// that have relation with this bank_statement.
+func (m *BankStatement) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *BankStatement) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
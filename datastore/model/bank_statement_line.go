@@ -0,0 +1,105 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(BankStatementLine))
+}
+
+// BankStatementLine model for bank_statement_line table.
+type BankStatementLine struct {
+	ID              int64          `orm:"column(id);auto" json:"-"`
+	BankStatement   *BankStatement `orm:"column(bank_statement_id);rel(fk)" json:"bank_statement,omitempty"`
+	TransactionDate time.Time      `orm:"column(transaction_date);type(date)" json:"transaction_date"`
+	Description     string         `orm:"column(description);null" json:"description"`
+	Reference       string         `orm:"column(reference);size(100);null" json:"reference"`
+	Debit           float64        `orm:"column(debit);digits(20);decimals(2)" json:"debit"`
+	Credit          float64        `orm:"column(credit);digits(20);decimals(2)" json:"credit"`
+	Balance         float64        `orm:"column(balance);null;digits(20);decimals(2)" json:"balance"`
+	Status          string         `orm:"column(status);options(unmatched,matched,ignored)" json:"status"`
+	RefType         string         `orm:"column(ref_type);size(45);null" json:"ref_type"`
+	RefID           uint64         `orm:"column(ref_id);null" json:"ref_id"`
+	MatchedBy       *User          `orm:"column(matched_by);null;rel(fk)" json:"matched_by"`
+	MatchedAt       time.Time      `orm:"column(matched_at);type(timestamp);null" json:"matched_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *BankStatementLine) MarshalJSON() ([]byte, error) {
+	type Alias BankStatementLine
+
+	alias := &struct {
+		ID              string `json:"id"`
+		BankStatementID string `json:"bank_statement_id"`
+		MatchedByID     string `json:"matched_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.BankStatementID when m.BankStatement not nill
+	// and the ID is setted
+	if m.BankStatement != nil && m.BankStatement.ID != int64(0) {
+		alias.BankStatementID = common.Encrypt(m.BankStatement.ID)
+	} else {
+		alias.BankStatement = nil
+	}
+
+	// Encrypt alias.MatchedByID when m.MatchedBy not nill
+	// and the ID is setted
+	if m.MatchedBy != nil && m.MatchedBy.ID != int64(0) {
+		alias.MatchedByID = common.Encrypt(m.MatchedBy.ID)
+	} else {
+		alias.MatchedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating BankStatementLine struct into bank_statement_line table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to bank_statement_line.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *BankStatementLine) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting bank_statement_line data
+// this also will truncated all data from all table
+// that have relation with this bank_statement_line.
+func (m *BankStatementLine) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *BankStatementLine) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
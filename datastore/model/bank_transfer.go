@@ -0,0 +1,122 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(BankTransfer))
+}
+
+// BankTransfer model for bank_transfer table.
+type BankTransfer struct {
+	ID              int64        `orm:"column(id);auto" json:"-"`
+	FromBankAccount *BankAccount `orm:"column(from_bank_account_id);rel(fk)" json:"from_bank_account,omitempty"`
+	ToBankAccount   *BankAccount `orm:"column(to_bank_account_id);rel(fk)" json:"to_bank_account,omitempty"`
+	Code            string       `orm:"column(code);size(45)" json:"code"`
+	TransferDate    time.Time    `orm:"column(transfer_date);type(date)" json:"transfer_date"`
+	Amount          float64      `orm:"column(amount);digits(20);decimals(2)" json:"amount"`
+	Note            string       `orm:"column(note);null" json:"note"`
+	DocumentStatus  string       `orm:"column(document_status);options(active,cancelled)" json:"document_status"`
+	CreatedBy       *User        `orm:"column(created_by);null;rel(fk)" json:"created_by,omitempty"`
+	CreatedAt       time.Time    `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	CancelledBy     *User        `orm:"column(cancelled_by);null;rel(fk)" json:"cancelled_by,omitempty"`
+	CancelledAt     time.Time    `orm:"column(cancelled_at);type(timestamp);null" json:"cancelled_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *BankTransfer) MarshalJSON() ([]byte, error) {
+	type Alias BankTransfer
+
+	alias := &struct {
+		ID                string `json:"id"`
+		FromBankAccountID string `json:"from_bank_account_id"`
+		ToBankAccountID   string `json:"to_bank_account_id"`
+		CreatedByID       string `json:"created_by_id"`
+		CancelledByID     string `json:"cancelled_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.FromBankAccountID when m.FromBankAccount not nill
+	// and the ID is setted
+	if m.FromBankAccount != nil && m.FromBankAccount.ID != int64(0) {
+		alias.FromBankAccountID = common.Encrypt(m.FromBankAccount.ID)
+	} else {
+		alias.FromBankAccount = nil
+	}
+
+	// Encrypt alias.ToBankAccountID when m.ToBankAccount not nill
+	// and the ID is setted
+	if m.ToBankAccount != nil && m.ToBankAccount.ID != int64(0) {
+		alias.ToBankAccountID = common.Encrypt(m.ToBankAccount.ID)
+	} else {
+		alias.ToBankAccount = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.CancelledByID when m.CancelledBy not nill
+	// and the ID is setted
+	if m.CancelledBy != nil && m.CancelledBy.ID != int64(0) {
+		alias.CancelledByID = common.Encrypt(m.CancelledBy.ID)
+	} else {
+		alias.CancelledBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating BankTransfer struct into bank_transfer table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to bank_transfer.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *BankTransfer) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting bank_transfer data
+// this also will truncated all data from all table
+// that have relation with this bank_transfer.
+func (m *BankTransfer) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *BankTransfer) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
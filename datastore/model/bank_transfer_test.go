@@ -0,0 +1,78 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBankTransfer_Save(t *testing.T) {
+	var m model.BankTransfer
+	faker.Fill(&m, "ID")
+
+	m.FromBankAccount = model.DummyBankAccount()
+
+	m.ToBankAccount = model.DummyBankAccount()
+
+	m.DocumentStatus = "active"
+
+	m.CreatedBy = model.DummyUser()
+
+	m.CancelledBy = nil
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestBankTransfer_Delete(t *testing.T) {
+	m := model.DummyBankTransfer()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.BankTransfer)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.BankTransfer)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestBankTransfer_Read(t *testing.T) {
+	var m model.BankTransfer
+
+	mn := model.DummyBankTransfer()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestBankTransfer_MarshalJSON(t *testing.T) {
+	mn := model.DummyBankTransfer()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(ChartOfAccount))
+}
+
+// ChartOfAccount model for chart_of_account table.
+type ChartOfAccount struct {
+	ID            int64           `orm:"column(id);auto" json:"-"`
+	Parent        *ChartOfAccount `orm:"column(parent_id);null;rel(fk)" json:"parent,omitempty"`
+	Code          string          `orm:"column(code);size(20)" json:"code"`
+	Name          string          `orm:"column(name);size(100)" json:"name"`
+	AccountType   string          `orm:"column(account_type);options(asset,liability,equity,revenue,expense)" json:"account_type"`
+	NormalBalance string          `orm:"column(normal_balance);options(debit,credit)" json:"normal_balance"`
+	IsArchived    int8            `orm:"column(is_archived);null" json:"is_archived"`
+	Note          string          `orm:"column(note);null" json:"note"`
+	CreatedAt     time.Time       `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt     time.Time       `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *ChartOfAccount) MarshalJSON() ([]byte, error) {
+	type Alias ChartOfAccount
+
+	alias := &struct {
+		ID       string `json:"id"`
+		ParentID string `json:"parent_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.ParentID when m.Parent not nill
+	// and the ID is setted
+	if m.Parent != nil && m.Parent.ID != int64(0) {
+		alias.ParentID = common.Encrypt(m.Parent.ID)
+	} else {
+		alias.Parent = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating ChartOfAccount struct into chart_of_account table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to chart_of_account.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *ChartOfAccount) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting chart_of_account data
+// this also will truncated all data from all table
+// that have relation with this chart_of_account.
+func (m *ChartOfAccount) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *ChartOfAccount) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
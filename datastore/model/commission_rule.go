@@ -0,0 +1,122 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(CommissionRule))
+}
+
+// CommissionRule model for commission_rule table.
+type CommissionRule struct {
+	ID           int64                 `orm:"column(id);auto" json:"-"`
+	Name         string                `orm:"column(name);size(45)" json:"name"`
+	Basis        string                `orm:"column(basis);options(revenue,margin)" json:"basis"`
+	SalesPerson  *User                 `orm:"column(sales_person_id);null;rel(fk)" json:"sales_person,omitempty"`
+	ItemCategory *ItemCategory         `orm:"column(item_category_id);null;rel(fk)" json:"item_category,omitempty"`
+	IsActive     int8                  `orm:"column(is_active);null" json:"is_active"`
+	Note         string                `orm:"column(note);null" json:"note"`
+	CreatedBy    *User                 `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	UpdatedBy    *User                 `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt    time.Time             `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt    time.Time             `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	Tiers        []*CommissionRuleTier `orm:"reverse(many)" json:"tiers,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *CommissionRule) MarshalJSON() ([]byte, error) {
+	type Alias CommissionRule
+
+	alias := &struct {
+		ID             string `json:"id"`
+		SalesPersonID  string `json:"sales_person_id"`
+		ItemCategoryID string `json:"item_category_id"`
+		CreatedByID    string `json:"created_by_id"`
+		UpdatedByID    string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.SalesPersonID when m.SalesPerson not nill
+	// and the ID is setted
+	if m.SalesPerson != nil && m.SalesPerson.ID != int64(0) {
+		alias.SalesPersonID = common.Encrypt(m.SalesPerson.ID)
+	} else {
+		alias.SalesPerson = nil
+	}
+
+	// Encrypt alias.ItemCategoryID when m.ItemCategory not nill
+	// and the ID is setted
+	if m.ItemCategory != nil && m.ItemCategory.ID != int64(0) {
+		alias.ItemCategoryID = common.Encrypt(m.ItemCategory.ID)
+	} else {
+		alias.ItemCategory = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating CommissionRule struct into commission_rule table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to commission_rule.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *CommissionRule) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting commission_rule data
+// this also will truncated all data from all table
+// that have relation with this commission_rule.
+func (m *CommissionRule) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *CommissionRule) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommissionRule_Save(t *testing.T) {
+	var m model.CommissionRule
+	faker.Fill(&m, "ID")
+
+	m.SalesPerson = model.DummyUser()
+
+	m.ItemCategory = model.DummyItemCategory()
+
+	m.CreatedBy = model.DummyUser()
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestCommissionRule_Delete(t *testing.T) {
+	m := model.DummyCommissionRule()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.CommissionRule)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.CommissionRule)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestCommissionRule_Read(t *testing.T) {
+	var m model.CommissionRule
+
+	mn := model.DummyCommissionRule()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestCommissionRule_MarshalJSON(t *testing.T) {
+	mn := model.DummyCommissionRule()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(CommissionRuleTier))
+}
+
+// CommissionRuleTier model for commission_rule_tier table.
+type CommissionRuleTier struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	CommissionRule *CommissionRule `orm:"column(commission_rule_id);rel(fk)" json:"commission_rule,omitempty"`
+	MinTarget      float64         `orm:"column(min_target);digits(20);decimals(0)" json:"min_target"`
+	Rate           float64         `orm:"column(rate);digits(5);decimals(2)" json:"rate"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *CommissionRuleTier) MarshalJSON() ([]byte, error) {
+	type Alias CommissionRuleTier
+
+	alias := &struct {
+		ID               string `json:"id"`
+		CommissionRuleID string `json:"commission_rule_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CommissionRuleID when m.CommissionRule not nill
+	// and the ID is setted
+	if m.CommissionRule != nil && m.CommissionRule.ID != int64(0) {
+		alias.CommissionRuleID = common.Encrypt(m.CommissionRule.ID)
+	} else {
+		alias.CommissionRule = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating CommissionRuleTier struct into commission_rule_tier table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to commission_rule_tier.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *CommissionRuleTier) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting commission_rule_tier data
+// this also will truncated all data from all table
+// that have relation with this commission_rule_tier.
+func (m *CommissionRuleTier) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *CommissionRuleTier) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(CommissionStatement))
+}
+
+// CommissionStatement model for commission_statement table.
+type CommissionStatement struct {
+	ID                       int64                      `orm:"column(id);auto" json:"-"`
+	SalesPerson              *User                      `orm:"column(sales_person_id);rel(fk)" json:"sales_person,omitempty"`
+	Period                   time.Time                  `orm:"column(period);type(date)" json:"period"`
+	TotalRevenue             float64                    `orm:"column(total_revenue);null;digits(20);decimals(0)" json:"total_revenue"`
+	TotalMargin              float64                    `orm:"column(total_margin);null;digits(20);decimals(0)" json:"total_margin"`
+	TotalReturn              float64                    `orm:"column(total_return);null;digits(20);decimals(0)" json:"total_return"`
+	TotalCommission          float64                    `orm:"column(total_commission);null;digits(20);decimals(0)" json:"total_commission"`
+	DocumentStatus           string                     `orm:"column(document_status);options(new,approved)" json:"document_status"`
+	Note                     string                     `orm:"column(note);null" json:"note"`
+	CreatedBy                *User                      `orm:"column(created_by);rel(fk)" json:"created_by"`
+	ApprovedBy               *User                      `orm:"column(approved_by);null;rel(fk)" json:"approved_by"`
+	CreatedAt                time.Time                  `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	ApprovedAt               time.Time                  `orm:"column(approved_at);type(timestamp);null" json:"approved_at"`
+	CommissionStatementItems []*CommissionStatementItem `orm:"reverse(many)" json:"commission_statement_items,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *CommissionStatement) MarshalJSON() ([]byte, error) {
+	type Alias CommissionStatement
+
+	alias := &struct {
+		ID            string `json:"id"`
+		SalesPersonID string `json:"sales_person_id"`
+		CreatedByID   string `json:"created_by_id"`
+		ApprovedByID  string `json:"approved_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.SalesPersonID when m.SalesPerson not nill
+	// and the ID is setted
+	if m.SalesPerson != nil && m.SalesPerson.ID != int64(0) {
+		alias.SalesPersonID = common.Encrypt(m.SalesPerson.ID)
+	} else {
+		alias.SalesPerson = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.ApprovedByID when m.ApprovedBy not nill
+	// and the ID is setted
+	if m.ApprovedBy != nil && m.ApprovedBy.ID != int64(0) {
+		alias.ApprovedByID = common.Encrypt(m.ApprovedBy.ID)
+	} else {
+		alias.ApprovedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating CommissionStatement struct into commission_statement table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to commission_statement.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *CommissionStatement) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting commission_statement data
+// this also will truncated all data from all table
+// that have relation with this commission_statement.
+func (m *CommissionStatement) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *CommissionStatement) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(CommissionStatementItem))
+}
+
+// CommissionStatementItem model for commission_statement_item table.
+type CommissionStatementItem struct {
+	ID                  int64                `orm:"column(id);auto" json:"-"`
+	CommissionStatement *CommissionStatement `orm:"column(commission_statement_id);rel(fk)" json:"commission_statement,omitempty"`
+	CommissionRule      *CommissionRule      `orm:"column(commission_rule_id);null;rel(fk)" json:"commission_rule,omitempty"`
+	RefID               uint64               `orm:"column(ref_id)" json:"ref_id"`
+	RefType             string               `orm:"column(ref_type);options(sales_invoice,sales_return)" json:"ref_type"`
+	RefCode             string               `orm:"column(ref_code);size(45);null" json:"ref_code"`
+	Revenue             float64              `orm:"column(revenue);null;digits(20);decimals(0)" json:"revenue"`
+	Cost                float64              `orm:"column(cost);null;digits(20);decimals(0)" json:"cost"`
+	BasisAmount         float64              `orm:"column(basis_amount);null;digits(20);decimals(0)" json:"basis_amount"`
+	Rate                float64              `orm:"column(rate);null;digits(5);decimals(2)" json:"rate"`
+	CommissionAmount    float64              `orm:"column(commission_amount);null;digits(20);decimals(0)" json:"commission_amount"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *CommissionStatementItem) MarshalJSON() ([]byte, error) {
+	type Alias CommissionStatementItem
+
+	alias := &struct {
+		ID                    string `json:"id"`
+		CommissionStatementID string `json:"commission_statement_id"`
+		CommissionRuleID      string `json:"commission_rule_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CommissionStatementID when m.CommissionStatement not nill
+	// and the ID is setted
+	if m.CommissionStatement != nil && m.CommissionStatement.ID != int64(0) {
+		alias.CommissionStatementID = common.Encrypt(m.CommissionStatement.ID)
+	} else {
+		alias.CommissionStatement = nil
+	}
+
+	// Encrypt alias.CommissionRuleID when m.CommissionRule not nill
+	// and the ID is setted
+	if m.CommissionRule != nil && m.CommissionRule.ID != int64(0) {
+		alias.CommissionRuleID = common.Encrypt(m.CommissionRule.ID)
+	} else {
+		alias.CommissionRule = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating CommissionStatementItem struct into commission_statement_item table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to commission_statement_item.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *CommissionStatementItem) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting commission_statement_item data
+// this also will truncated all data from all table
+// that have relation with this commission_statement_item.
+func (m *CommissionStatementItem) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *CommissionStatementItem) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
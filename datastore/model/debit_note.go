@@ -0,0 +1,125 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(DebitNote))
+}
+
+// DebitNote model for debit_note table.
+type DebitNote struct {
+	ID              int64             `orm:"column(id);auto" json:"-"`
+	Code            string            `orm:"column(code);size(45)" json:"code"`
+	Partnership     *Partnership      `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	PurchaseReturn  *PurchaseReturn   `orm:"column(purchase_return_id);null;rel(fk)" json:"purchase_return,omitempty"`
+	RecognitionDate time.Time         `orm:"column(recognition_date);type(date)" json:"recognition_date"`
+	Amount          float64           `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
+	UsedAmount      float64           `orm:"column(used_amount);digits(20);decimals(0)" json:"used_amount"`
+	Balance         float64           `orm:"column(balance);digits(20);decimals(0)" json:"balance"`
+	DocumentStatus  string            `orm:"column(document_status);options(active,used,cancelled)" json:"document_status"`
+	Note            string            `orm:"column(note);null" json:"note"`
+	CreatedBy       *User             `orm:"column(created_by);rel(fk)" json:"created_by"`
+	UpdatedBy       *User             `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt       time.Time         `orm:"column(created_at);type(timestamp)" json:"created_at"`
+	UpdatedAt       time.Time         `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	FinanceExpenses []*FinanceExpense `orm:"-" json:"finance_expenses,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *DebitNote) MarshalJSON() ([]byte, error) {
+	type Alias DebitNote
+
+	alias := &struct {
+		ID               string `json:"id"`
+		PartnershipID    string `json:"partnership_id"`
+		PurchaseReturnID string `json:"purchase_return_id"`
+		CreatedByID      string `json:"created_by_id"`
+		UpdatedByID      string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	// Encrypt alias.PurchaseReturnID when m.PurchaseReturn not nill
+	// and the ID is setted
+	if m.PurchaseReturn != nil && m.PurchaseReturn.ID != int64(0) {
+		alias.PurchaseReturnID = common.Encrypt(m.PurchaseReturn.ID)
+	} else {
+		alias.PurchaseReturn = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating DebitNote struct into debit_note table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to debit_note.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *DebitNote) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting debit_note data
+// this also will truncated all data from all table
+// that have relation with this debit_note.
+func (m *DebitNote) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *DebitNote) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(DiscountLimit))
+}
+
+// DiscountLimit model for discount_limit table.
+type DiscountLimit struct {
+	ID               int64      `orm:"column(id);auto" json:"-"`
+	Usergroup        *Usergroup `orm:"column(usergroup_id);rel(fk)" json:"usergroup,omitempty"`
+	MaxLineDiscount  float32    `orm:"column(max_line_discount)" json:"max_line_discount"`
+	MaxOrderDiscount float32    `orm:"column(max_order_discount)" json:"max_order_discount"`
+	MinMargin        float32    `orm:"column(min_margin)" json:"min_margin"`
+	Note             string     `orm:"column(note);null" json:"note"`
+	CreatedBy        *User      `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	UpdatedBy        *User      `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt        time.Time  `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt        time.Time  `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *DiscountLimit) MarshalJSON() ([]byte, error) {
+	type Alias DiscountLimit
+
+	alias := &struct {
+		ID          string `json:"id"`
+		UsergroupID string `json:"usergroup_id"`
+		CreatedByID string `json:"created_by_id"`
+		UpdatedByID string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.UsergroupID when m.Usergroup not nill
+	// and the ID is setted
+	if m.Usergroup != nil && m.Usergroup.ID != int64(0) {
+		alias.UsergroupID = common.Encrypt(m.Usergroup.ID)
+	} else {
+		alias.Usergroup = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating DiscountLimit struct into discount_limit table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to discount_limit.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *DiscountLimit) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting discount_limit data
+// this also will truncated all data from all table
+// that have relation with this discount_limit.
+func (m *DiscountLimit) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *DiscountLimit) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(DunningLog))
+}
+
+// DunningLog model for dunning_log table.
+type DunningLog struct {
+	ID           int64         `orm:"column(id);auto" json:"-"`
+	SalesInvoice *SalesInvoice `orm:"column(sales_invoice_id);rel(fk)" json:"sales_invoice,omitempty"`
+	Partnership  *Partnership  `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	IntervalDay  int           `orm:"column(interval_day)" json:"interval_day"`
+	DaysOverdue  int           `orm:"column(days_overdue)" json:"days_overdue"`
+	Email        string        `orm:"column(email);size(45);null" json:"email"`
+	Subject      string        `orm:"column(subject);size(255);null" json:"subject"`
+	Outstanding  float64       `orm:"column(outstanding);null;digits(20);decimals(0)" json:"outstanding"`
+	Status       string        `orm:"column(status);options(sent,failed)" json:"status"`
+	Note         string        `orm:"column(note);null" json:"note"`
+	SentAt       time.Time     `orm:"column(sent_at);type(timestamp);null" json:"sent_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *DunningLog) MarshalJSON() ([]byte, error) {
+	type Alias DunningLog
+
+	alias := &struct {
+		ID             string `json:"id"`
+		SalesInvoiceID string `json:"sales_invoice_id"`
+		PartnershipID  string `json:"partnership_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.SalesInvoiceID when m.SalesInvoice not nill
+	// and the ID is setted
+	if m.SalesInvoice != nil && m.SalesInvoice.ID != int64(0) {
+		alias.SalesInvoiceID = common.Encrypt(m.SalesInvoice.ID)
+	} else {
+		alias.SalesInvoice = nil
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating DunningLog struct into dunning_log table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to dunning_log.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *DunningLog) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting dunning_log data
+// this also will truncated all data from all table
+// that have relation with this dunning_log.
+func (m *DunningLog) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *DunningLog) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
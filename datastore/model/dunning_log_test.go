@@ -0,0 +1,74 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDunningLog_Save(t *testing.T) {
+	var m model.DunningLog
+	faker.Fill(&m, "ID")
+
+	m.SalesInvoice = model.DummySalesInvoice()
+
+	m.Partnership = model.DummyPartnership()
+
+	m.Status = "sent"
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestDunningLog_Delete(t *testing.T) {
+	m := model.DummyDunningLog()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.DunningLog)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.DunningLog)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestDunningLog_Read(t *testing.T) {
+	var m model.DunningLog
+
+	mn := model.DummyDunningLog()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestDunningLog_MarshalJSON(t *testing.T) {
+	mn := model.DummyDunningLog()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
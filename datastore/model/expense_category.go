@@ -0,0 +1,90 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(ExpenseCategory))
+}
+
+// ExpenseCategory model for expense_category table.
+type ExpenseCategory struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	ChartOfAccount *ChartOfAccount `orm:"column(chart_of_account_id);rel(fk)" json:"chart_of_account,omitempty"`
+	Name           string          `orm:"column(name);size(100)" json:"name"`
+	Note           string          `orm:"column(note);null" json:"note"`
+	IsArchived     int8            `orm:"column(is_archived);null" json:"is_archived"`
+	CreatedAt      time.Time       `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt      time.Time       `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *ExpenseCategory) MarshalJSON() ([]byte, error) {
+	type Alias ExpenseCategory
+
+	alias := &struct {
+		ID               string `json:"id"`
+		ChartOfAccountID string `json:"chart_of_account_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.ChartOfAccountID when m.ChartOfAccount not nill
+	// and the ID is setted
+	if m.ChartOfAccount != nil && m.ChartOfAccount.ID != int64(0) {
+		alias.ChartOfAccountID = common.Encrypt(m.ChartOfAccount.ID)
+	} else {
+		alias.ChartOfAccount = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating ExpenseCategory struct into expense_category table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to expense_category.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *ExpenseCategory) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting expense_category data
+// this also will truncated all data from all table
+// that have relation with this expense_category.
+func (m *ExpenseCategory) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *ExpenseCategory) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
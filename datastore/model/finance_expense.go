@@ -19,11 +19,14 @@ func init() {
 // FinanceExpense model for finance_expense table.
 type FinanceExpense struct {
 	ID              int64            `orm:"column(id);auto" json:"-"`
+	DebitNote       *DebitNote       `orm:"column(debit_note_id);null;rel(fk)" json:"debit_note,omitempty"`
+	BankAccount     *BankAccount     `orm:"column(bank_account_id);null;rel(fk)" json:"bank_account,omitempty"`
+	Payment         *Payment         `orm:"column(payment_id);null;rel(fk)" json:"payment,omitempty"`
 	RefID           uint64           `orm:"column(ref_id);null" json:"ref_id"`
 	RefType         string           `orm:"column(ref_type);null;options(purchase_invoice,sales_return)" json:"ref_type"`
 	RecognitionDate time.Time        `orm:"column(recognition_date);type(date)" json:"recognition_date"`
 	Amount          float64          `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
-	PaymentMethod   string           `orm:"column(payment_method);null;options(cash,debit_card,credit_card,giro)" json:"payment_method"`
+	PaymentMethod   string           `orm:"column(payment_method);null;options(cash,debit_card,credit_card,giro,debit_note,transfer)" json:"payment_method"`
 	BankName        string           `orm:"column(bank_name);size(45);null" json:"bank_name"`
 	BankNumber      string           `orm:"column(bank_number);size(45);null" json:"bank_number"`
 	BankHolder      string           `orm:"column(bank_holder);size(45);null" json:"bank_holder"`
@@ -44,15 +47,42 @@ func (m *FinanceExpense) MarshalJSON() ([]byte, error) {
 	type Alias FinanceExpense
 
 	alias := &struct {
-		ID          string `json:"id"`
-		CreatedByID string `json:"created_by_id"`
-		UpdatedByID string `json:"updated_by_id"`
+		ID            string `json:"id"`
+		DebitNoteID   string `json:"debit_note_id"`
+		BankAccountID string `json:"bank_account_id"`
+		PaymentID     string `json:"payment_id"`
+		CreatedByID   string `json:"created_by_id"`
+		UpdatedByID   string `json:"updated_by_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
 		Alias: (*Alias)(m),
 	}
 
+	// Encrypt alias.DebitNoteID when m.DebitNote not nill
+	// and the ID is setted
+	if m.DebitNote != nil && m.DebitNote.ID != int64(0) {
+		alias.DebitNoteID = common.Encrypt(m.DebitNote.ID)
+	} else {
+		alias.DebitNote = nil
+	}
+
+	// Encrypt alias.BankAccountID when m.BankAccount not nill
+	// and the ID is setted
+	if m.BankAccount != nil && m.BankAccount.ID != int64(0) {
+		alias.BankAccountID = common.Encrypt(m.BankAccount.ID)
+	} else {
+		alias.BankAccount = nil
+	}
+
+	// Encrypt alias.PaymentID when m.Payment not nill
+	// and the ID is setted
+	if m.Payment != nil && m.Payment.ID != int64(0) {
+		alias.PaymentID = common.Encrypt(m.Payment.ID)
+	} else {
+		alias.Payment = nil
+	}
+
 	// Encrypt alias.CreatedByID when m.CreatedBy not nill
 	// and the ID is setted
 	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
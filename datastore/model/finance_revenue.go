@@ -20,11 +20,13 @@ func init() {
 type FinanceRevenue struct {
 	ID              int64           `orm:"column(id);auto" json:"-"`
 	BankAccount     *BankAccount    `orm:"column(bank_account_id);null;rel(fk)" json:"bank_account,omitempty"`
+	CreditNote      *CreditNote     `orm:"column(credit_note_id);null;rel(fk)" json:"credit_note,omitempty"`
+	Payment         *Payment        `orm:"column(payment_id);null;rel(fk)" json:"payment,omitempty"`
 	RefID           uint64          `orm:"column(ref_id);null" json:"ref_id"`
 	RecognitionDate time.Time       `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
 	RefType         string          `orm:"column(ref_type);null;options(sales_invoice,purchase_return,invoice_receipt)" json:"ref_type"`
 	Amount          float64         `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
-	PaymentMethod   string          `orm:"column(payment_method);null;options(cash,debit_card,credit_card,giro)" json:"payment_method"`
+	PaymentMethod   string          `orm:"column(payment_method);null;options(cash,debit_card,credit_card,giro,transfer,credit_note)" json:"payment_method"`
 	BankName        string          `orm:"column(bank_name);size(45);null" json:"bank_name"`
 	BankNumber      string          `orm:"column(bank_number);size(45);null" json:"bank_number"`
 	BankHolder      string          `orm:"column(bank_holder);size(45);null" json:"bank_holder"`
@@ -48,6 +50,8 @@ func (m *FinanceRevenue) MarshalJSON() ([]byte, error) {
 	alias := &struct {
 		ID            string `json:"id"`
 		BankAccountID string `json:"bank_account_id"`
+		CreditNoteID  string `json:"credit_note_id"`
+		PaymentID     string `json:"payment_id"`
 		CreatedByID   string `json:"created_by_id"`
 		UpdatedByID   string `json:"updated_by_id"`
 		*Alias
@@ -64,6 +68,22 @@ func (m *FinanceRevenue) MarshalJSON() ([]byte, error) {
 		alias.BankAccount = nil
 	}
 
+	// Encrypt alias.CreditNoteID when m.CreditNote not nill
+	// and the ID is setted
+	if m.CreditNote != nil && m.CreditNote.ID != int64(0) {
+		alias.CreditNoteID = common.Encrypt(m.CreditNote.ID)
+	} else {
+		alias.CreditNote = nil
+	}
+
+	// Encrypt alias.PaymentID when m.Payment not nill
+	// and the ID is setted
+	if m.Payment != nil && m.Payment.ID != int64(0) {
+		alias.PaymentID = common.Encrypt(m.Payment.ID)
+	} else {
+		alias.Payment = nil
+	}
+
 	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
 	// and the ID is setted
 	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
@@ -28,6 +28,7 @@ type ItemVariant struct {
 	BasePrice            float64                `orm:"column(base_price);null;digits(20);decimals(0)" json:"base_price"`
 	Note                 string                 `orm:"column(note);null" json:"note"`
 	MinimumStock         float32                `orm:"column(minimum_stock);null" json:"minimum_stock"`
+	Weight               float32                `orm:"column(weight)" json:"weight"`
 	AvailableStock       float32                `orm:"column(available_stock);null" json:"available_stock"`
 	CommitedStock        float32                `orm:"column(commited_stock);null" json:"commited_stock"`
 	HasExternalName      int8                   `orm:"column(has_external_name);null" json:"has_external_name"`
@@ -40,6 +41,7 @@ type ItemVariant struct {
 	ItemVariantPrices    []*ItemVariantPrice    `orm:"reverse(many)" json:"item_variant_prices,omitempty"`
 	ItemVariantStocks    []*ItemVariantStock    `orm:"reverse(many)" json:"item_variant_stocks,omitempty"`
 	ItemVariantStockLogs []*ItemVariantStockLog `orm:"-" json:"item_variant_stock_logs,omitempty"`
+	OpenRequisition      float32                `orm:"-" json:"open_requisition,omitempty"`
 }
 
 // MarshalJSON customized data struct when marshaling data
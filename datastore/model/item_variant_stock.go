@@ -23,6 +23,7 @@ type ItemVariantStock struct {
 	SkuCode        string       `orm:"column(sku_code);size(120)" json:"sku_code"`
 	AvailableStock float32      `orm:"column(available_stock);null" json:"available_stock"`
 	UnitCost       float64      `orm:"column(unit_cost);null;digits(20);decimals(0)" json:"unit_cost"`
+	Location       string       `orm:"column(location);options(warehouse,quarantine)" json:"location"`
 	CreatedBy      *User        `orm:"column(created_by);null;rel(fk)" json:"created_by"`
 	UpdatedBy      *User        `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
 	CreatedAt      time.Time    `orm:"column(created_at);type(timestamp)" json:"created_at"`
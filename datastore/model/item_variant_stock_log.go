@@ -20,7 +20,7 @@ type ItemVariantStockLog struct {
 	ID                   int64                 `orm:"column(id);auto" json:"-"`
 	ItemVariantStock     *ItemVariantStock     `orm:"column(item_variant_stock_id);rel(fk)" json:"item_variant_stock,omitempty"`
 	RefID                uint64                `orm:"column(ref_id)" json:"ref_id"`
-	RefType              string                `orm:"column(ref_type);null;options(workorder_fulfillment,workorder_receiving,stockopname,direct_placement)" json:"ref_type"`
+	RefType              string                `orm:"column(ref_type);null;options(workorder_fulfillment,workorder_receiving,stockopname,direct_placement,sales_return,purchase_return)" json:"ref_type"`
 	LogType              string                `orm:"column(log_type);null;options(in,out)" json:"log_type"`
 	Quantity             float32               `orm:"column(quantity)" json:"quantity"`
 	FinalStock           float32               `orm:"column(final_stock)" json:"final_stock"`
@@ -28,6 +28,8 @@ type ItemVariantStockLog struct {
 	WorkorderFulfillment *WorkorderFulfillment `orm:"-" json:"workorder_fulfillment,omitempty"`
 	WorkorderReceiving   *WorkorderReceiving   `orm:"-" json:"workorder_receiving,omitempty"`
 	DirectPlacement      *DirectPlacement      `orm:"-" json:"direct_placement,omitempty"`
+	SalesReturn          *SalesReturn          `orm:"-" json:"sales_return,omitempty"`
+	PurchaseReturn       *PurchaseReturn       `orm:"-" json:"purchase_return,omitempty"`
 }
 
 // MarshalJSON customized data struct when marshaling data
@@ -69,6 +71,14 @@ func (m *ItemVariantStockLog) MarshalJSON() ([]byte, error) {
 		o.Raw("select * from direct_placement where id = ?", int64(m.RefID)).QueryRow(&m.DirectPlacement)
 	}
 
+	if m.RefType == "sales_return" {
+		o.Raw("select * from sales_return where id = ?", int64(m.RefID)).QueryRow(&m.SalesReturn)
+	}
+
+	if m.RefType == "purchase_return" {
+		o.Raw("select * from purchase_return where id = ?", int64(m.RefID)).QueryRow(&m.PurchaseReturn)
+	}
+
 	m.ItemVariantStock.Read()
 	m.ItemVariantStock.ItemVariant.Read()
 	m.ItemVariantStock.ItemVariant.Measurement.Read()
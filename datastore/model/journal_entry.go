@@ -0,0 +1,94 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(JournalEntry))
+}
+
+// JournalEntry model for journal_entry table.
+type JournalEntry struct {
+	ID                int64               `orm:"column(id);auto" json:"-"`
+	ReversalOf        *JournalEntry       `orm:"column(reversal_of_id);null;rel(fk)" json:"reversal_of,omitempty"`
+	Code              string              `orm:"column(code);size(45)" json:"code"`
+	RecognitionDate   time.Time           `orm:"column(recognition_date);type(date)" json:"recognition_date"`
+	RefType           string              `orm:"column(ref_type);size(45)" json:"ref_type"`
+	RefID             uint64              `orm:"column(ref_id);null" json:"ref_id"`
+	Note              string              `orm:"column(note);null" json:"note"`
+	TotalDebit        float64             `orm:"column(total_debit);digits(20);decimals(2)" json:"total_debit"`
+	TotalCredit       float64             `orm:"column(total_credit);digits(20);decimals(2)" json:"total_credit"`
+	CreatedAt         time.Time           `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	JournalEntryLines []*JournalEntryLine `orm:"reverse(many)" json:"journal_entry_lines,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *JournalEntry) MarshalJSON() ([]byte, error) {
+	type Alias JournalEntry
+
+	alias := &struct {
+		ID           string `json:"id"`
+		ReversalOfID string `json:"reversal_of_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.ReversalOfID when m.ReversalOf not nill
+	// and the ID is setted
+	if m.ReversalOf != nil && m.ReversalOf.ID != int64(0) {
+		alias.ReversalOfID = common.Encrypt(m.ReversalOf.ID)
+	} else {
+		alias.ReversalOf = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating JournalEntry struct into journal_entry table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to journal_entry.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *JournalEntry) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting journal_entry data
+// this also will truncated all data from all table
+// that have relation with this journal_entry.
+func (m *JournalEntry) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *JournalEntry) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
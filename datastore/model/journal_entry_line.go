@@ -0,0 +1,107 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(JournalEntryLine))
+}
+
+// JournalEntryLine model for journal_entry_line table.
+type JournalEntryLine struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	JournalEntry   *JournalEntry   `orm:"column(journal_entry_id);rel(fk)" json:"journal_entry,omitempty"`
+	ChartOfAccount *ChartOfAccount `orm:"column(chart_of_account_id);rel(fk)" json:"chart_of_account,omitempty"`
+	Partnership    *Partnership    `orm:"column(partnership_id);null;rel(fk)" json:"partnership,omitempty"`
+	Debit          float64         `orm:"column(debit);digits(20);decimals(2)" json:"debit"`
+	Credit         float64         `orm:"column(credit);digits(20);decimals(2)" json:"credit"`
+	Note           string          `orm:"column(note);null" json:"note"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *JournalEntryLine) MarshalJSON() ([]byte, error) {
+	type Alias JournalEntryLine
+
+	alias := &struct {
+		ID               string `json:"id"`
+		JournalEntryID   string `json:"journal_entry_id"`
+		ChartOfAccountID string `json:"chart_of_account_id"`
+		PartnershipID    string `json:"partnership_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.JournalEntryID when m.JournalEntry not nill
+	// and the ID is setted
+	if m.JournalEntry != nil && m.JournalEntry.ID != int64(0) {
+		alias.JournalEntryID = common.Encrypt(m.JournalEntry.ID)
+	} else {
+		alias.JournalEntry = nil
+	}
+
+	// Encrypt alias.ChartOfAccountID when m.ChartOfAccount not nill
+	// and the ID is setted
+	if m.ChartOfAccount != nil && m.ChartOfAccount.ID != int64(0) {
+		alias.ChartOfAccountID = common.Encrypt(m.ChartOfAccount.ID)
+	} else {
+		alias.ChartOfAccount = nil
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating JournalEntryLine struct into journal_entry_line table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to journal_entry_line.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *JournalEntryLine) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting journal_entry_line data
+// this also will truncated all data from all table
+// that have relation with this journal_entry_line.
+func (m *JournalEntryLine) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *JournalEntryLine) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
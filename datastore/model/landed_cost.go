@@ -0,0 +1,106 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(LandedCost))
+}
+
+// LandedCost model for landed_cost table.
+type LandedCost struct {
+	ID                    int64                   `orm:"column(id);auto" json:"-"`
+	Code                  string                  `orm:"column(code);size(45)" json:"code"`
+	RecognitionDate       time.Time               `orm:"column(recognition_date);type(date)" json:"recognition_date"`
+	AllocationMethod      string                  `orm:"column(allocation_method);options(value,quantity,weight)" json:"allocation_method"`
+	TotalAmount           float64                 `orm:"column(total_amount);digits(20);decimals(0)" json:"total_amount"`
+	DocumentStatus        string                  `orm:"column(document_status);options(active,cancelled)" json:"document_status"`
+	Note                  string                  `orm:"column(note);null" json:"note"`
+	CreatedBy             *User                   `orm:"column(created_by);rel(fk)" json:"created_by"`
+	UpdatedBy             *User                   `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt             time.Time               `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt             time.Time               `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	LandedCostCharges     []*LandedCostCharge     `orm:"reverse(many)" json:"landed_cost_charges,omitempty"`
+	LandedCostItems       []*LandedCostItem       `orm:"reverse(many)" json:"landed_cost_items,omitempty"`
+	LandedCostAdjustments []*LandedCostAdjustment `orm:"reverse(many)" json:"landed_cost_adjustments,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *LandedCost) MarshalJSON() ([]byte, error) {
+	type Alias LandedCost
+
+	alias := &struct {
+		ID          string `json:"id"`
+		CreatedByID string `json:"created_by_id"`
+		UpdatedByID string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating LandedCost struct into landed_cost table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to landed_cost.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *LandedCost) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting landed_cost data
+// this also will truncated all data from all table
+// that have relation with this landed_cost.
+func (m *LandedCost) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *LandedCost) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(LandedCostAdjustment))
+}
+
+// LandedCostAdjustment model for landed_cost_adjustment table.
+type LandedCostAdjustment struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	LandedCost     *LandedCost     `orm:"column(landed_cost_id);rel(fk)" json:"landed_cost,omitempty"`
+	LandedCostItem *LandedCostItem `orm:"column(landed_cost_item_id);rel(fk)" json:"landed_cost_item,omitempty"`
+	SalesOrder     *SalesOrder     `orm:"column(sales_order_id);rel(fk)" json:"sales_order,omitempty"`
+	Quantity       float32         `orm:"column(quantity)" json:"quantity"`
+	Amount         float64         `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *LandedCostAdjustment) MarshalJSON() ([]byte, error) {
+	type Alias LandedCostAdjustment
+
+	alias := &struct {
+		ID               string `json:"id"`
+		LandedCostID     string `json:"landed_cost_id"`
+		LandedCostItemID string `json:"landed_cost_item_id"`
+		SalesOrderID     string `json:"sales_order_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.LandedCostID when m.LandedCost not nill
+	// and the ID is setted
+	if m.LandedCost != nil && m.LandedCost.ID != int64(0) {
+		alias.LandedCostID = common.Encrypt(m.LandedCost.ID)
+	} else {
+		alias.LandedCost = nil
+	}
+
+	// Encrypt alias.LandedCostItemID when m.LandedCostItem not nill
+	// and the ID is setted
+	if m.LandedCostItem != nil && m.LandedCostItem.ID != int64(0) {
+		alias.LandedCostItemID = common.Encrypt(m.LandedCostItem.ID)
+	} else {
+		alias.LandedCostItem = nil
+	}
+
+	// Encrypt alias.SalesOrderID when m.SalesOrder not nill
+	// and the ID is setted
+	if m.SalesOrder != nil && m.SalesOrder.ID != int64(0) {
+		alias.SalesOrderID = common.Encrypt(m.SalesOrder.ID)
+	} else {
+		alias.SalesOrder = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating LandedCostAdjustment struct into landed_cost_adjustment table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to landed_cost_adjustment.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *LandedCostAdjustment) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting landed_cost_adjustment data
+// this also will truncated all data from all table
+// that have relation with this landed_cost_adjustment.
+func (m *LandedCostAdjustment) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *LandedCostAdjustment) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(LandedCostCharge))
+}
+
+// LandedCostCharge model for landed_cost_charge table.
+type LandedCostCharge struct {
+	ID            int64          `orm:"column(id);auto" json:"-"`
+	LandedCost    *LandedCost    `orm:"column(landed_cost_id);rel(fk)" json:"landed_cost,omitempty"`
+	ChargeType    string         `orm:"column(charge_type);options(shipment_cost,freight,customs,insurance,other)" json:"charge_type"`
+	PurchaseOrder *PurchaseOrder `orm:"column(purchase_order_id);null;rel(fk)" json:"purchase_order,omitempty"`
+	Partnership   *Partnership   `orm:"column(partnership_id);null;rel(fk)" json:"partnership,omitempty"`
+	Reference     string         `orm:"column(reference);size(120);null" json:"reference"`
+	Amount        float64        `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
+	Note          string         `orm:"column(note);null" json:"note"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *LandedCostCharge) MarshalJSON() ([]byte, error) {
+	type Alias LandedCostCharge
+
+	alias := &struct {
+		ID              string `json:"id"`
+		LandedCostID    string `json:"landed_cost_id"`
+		PurchaseOrderID string `json:"purchase_order_id"`
+		PartnershipID   string `json:"partnership_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.LandedCostID when m.LandedCost not nill
+	// and the ID is setted
+	if m.LandedCost != nil && m.LandedCost.ID != int64(0) {
+		alias.LandedCostID = common.Encrypt(m.LandedCost.ID)
+	} else {
+		alias.LandedCost = nil
+	}
+
+	// Encrypt alias.PurchaseOrderID when m.PurchaseOrder not nill
+	// and the ID is setted
+	if m.PurchaseOrder != nil && m.PurchaseOrder.ID != int64(0) {
+		alias.PurchaseOrderID = common.Encrypt(m.PurchaseOrder.ID)
+	} else {
+		alias.PurchaseOrder = nil
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating LandedCostCharge struct into landed_cost_charge table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to landed_cost_charge.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *LandedCostCharge) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting landed_cost_charge data
+// this also will truncated all data from all table
+// that have relation with this landed_cost_charge.
+func (m *LandedCostCharge) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *LandedCostCharge) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(LandedCostItem))
+}
+
+// LandedCostItem model for landed_cost_item table.
+type LandedCostItem struct {
+	ID                     int64                   `orm:"column(id);auto" json:"-"`
+	LandedCost             *LandedCost             `orm:"column(landed_cost_id);rel(fk)" json:"landed_cost,omitempty"`
+	WorkorderReceivingItem *WorkorderReceivingItem `orm:"column(workorder_receiving_item_id);rel(fk)" json:"workorder_receiving_item,omitempty"`
+	ItemVariantStock       *ItemVariantStock       `orm:"column(item_variant_stock_id);rel(fk)" json:"item_variant_stock,omitempty"`
+	Quantity               float32                 `orm:"column(quantity)" json:"quantity"`
+	Basis                  float64                 `orm:"column(basis);digits(20);decimals(2)" json:"basis"`
+	AllocatedAmount        float64                 `orm:"column(allocated_amount);digits(20);decimals(0)" json:"allocated_amount"`
+	UnitCostBefore         float64                 `orm:"column(unit_cost_before);digits(20);decimals(0)" json:"unit_cost_before"`
+	UnitCostAfter          float64                 `orm:"column(unit_cost_after);digits(20);decimals(0)" json:"unit_cost_after"`
+	ConsumedQuantity       float32                 `orm:"column(consumed_quantity)" json:"consumed_quantity"`
+	ConsumedAmount         float64                 `orm:"column(consumed_amount);digits(20);decimals(0)" json:"consumed_amount"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *LandedCostItem) MarshalJSON() ([]byte, error) {
+	type Alias LandedCostItem
+
+	alias := &struct {
+		ID                       string `json:"id"`
+		LandedCostID             string `json:"landed_cost_id"`
+		WorkorderReceivingItemID string `json:"workorder_receiving_item_id"`
+		ItemVariantStockID       string `json:"item_variant_stock_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.LandedCostID when m.LandedCost not nill
+	// and the ID is setted
+	if m.LandedCost != nil && m.LandedCost.ID != int64(0) {
+		alias.LandedCostID = common.Encrypt(m.LandedCost.ID)
+	} else {
+		alias.LandedCost = nil
+	}
+
+	// Encrypt alias.WorkorderReceivingItemID when m.WorkorderReceivingItem not nill
+	// and the ID is setted
+	if m.WorkorderReceivingItem != nil && m.WorkorderReceivingItem.ID != int64(0) {
+		alias.WorkorderReceivingItemID = common.Encrypt(m.WorkorderReceivingItem.ID)
+	} else {
+		alias.WorkorderReceivingItem = nil
+	}
+
+	// Encrypt alias.ItemVariantStockID when m.ItemVariantStock not nill
+	// and the ID is setted
+	if m.ItemVariantStock != nil && m.ItemVariantStock.ID != int64(0) {
+		alias.ItemVariantStockID = common.Encrypt(m.ItemVariantStock.ID)
+	} else {
+		alias.ItemVariantStock = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating LandedCostItem struct into landed_cost_item table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to landed_cost_item.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *LandedCostItem) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting landed_cost_item data
+// this also will truncated all data from all table
+// that have relation with this landed_cost_item.
+func (m *LandedCostItem) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *LandedCostItem) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLandedCostItem_Save(t *testing.T) {
+	var m model.LandedCostItem
+	faker.Fill(&m, "ID")
+
+	m.LandedCost = model.DummyLandedCost()
+
+	m.WorkorderReceivingItem = model.DummyWorkorderReceivingItem()
+
+	m.ItemVariantStock = model.DummyItemVariantStock()
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestLandedCostItem_Delete(t *testing.T) {
+	m := model.DummyLandedCostItem()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.LandedCostItem)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.LandedCostItem)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestLandedCostItem_Read(t *testing.T) {
+	var m model.LandedCostItem
+
+	mn := model.DummyLandedCostItem()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestLandedCostItem_MarshalJSON(t *testing.T) {
+	mn := model.DummyLandedCostItem()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
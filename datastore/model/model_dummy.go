@@ -65,8 +65,15 @@ func DummyApplicationSetting() *ApplicationSetting {
 func DummyBankAccount() *BankAccount {
 	var m BankAccount
 	faker.Fill(&m, "ID")
+	m.AccountType = "bank"
 	m.BankNumber = common.RandomNumeric(10)
 	m.BankName = common.RandomStr(10)
+	m.IsDefault = 0
+	m.IsArchived = 0
+
+	m.CreatedBy = DummyUser()
+
+	m.UpdatedBy = nil
 
 	if e := m.Save(); e != nil {
 		fmt.Printf("error saving %s", e.Error())
@@ -222,6 +229,7 @@ func DummyItemVariantStock() *ItemVariantStock {
 	faker.Fill(&m, "ID")
 
 	m.ItemVariant = DummyItemVariant()
+	m.Location = "warehouse"
 
 	if e := m.Save(); e != nil {
 		fmt.Printf("error saving %s", e.Error())
@@ -347,6 +355,8 @@ func DummyPurchaseReturnItem() *PurchaseReturnItem {
 
 	m.PurchaseOrderItem = DummyPurchaseOrderItem()
 
+	m.ItemVariantStock = DummyItemVariantStock()
+
 	if e := m.Save(); e != nil {
 		fmt.Printf("error saving %s", e.Error())
 	}
@@ -434,6 +444,8 @@ func DummySalesReturn() *SalesReturn {
 	m.SalesOrder = DummySalesOrder()
 
 	m.CreatedBy = DummyUser()
+	m.IsRestocked = 0
+	m.RestockedBy = nil
 
 	if e := m.Save(); e != nil {
 		fmt.Printf("error saving %s", e.Error())
@@ -622,3 +634,726 @@ func DummyInvoiceReceiptReturn() *InvoiceReceiptReturn {
 	}
 	return &m
 }
+
+// DummyDunningLog make a dummy data for model DunningLog
+func DummyDunningLog() *DunningLog {
+	var m DunningLog
+	faker.Fill(&m, "ID")
+
+	m.SalesInvoice = DummySalesInvoice()
+
+	m.Partnership = DummyPartnership()
+
+	m.Status = "sent"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyTaxCode make a dummy data for model TaxCode
+func DummyTaxCode() *TaxCode {
+	var m TaxCode
+	faker.Fill(&m, "ID")
+
+	m.CreatedBy = DummyUser()
+
+	m.Code = common.RandomStr(10)
+	m.Rate = 11
+	m.IsArchived = 0
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyTaxInvoiceRange make a dummy data for model TaxInvoiceRange
+func DummyTaxInvoiceRange() *TaxInvoiceRange {
+	var m TaxInvoiceRange
+	faker.Fill(&m, "ID")
+
+	m.CreatedBy = DummyUser()
+
+	m.StartNumber = 1
+	m.EndNumber = 100
+	m.LastNumber = 0
+	m.IsActive = 1
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyCommissionRule make a dummy data for model CommissionRule
+func DummyCommissionRule() *CommissionRule {
+	var m CommissionRule
+	faker.Fill(&m, "ID")
+
+	m.SalesPerson = DummyUser()
+
+	m.ItemCategory = DummyItemCategory()
+
+	m.CreatedBy = DummyUser()
+
+	m.Basis = "revenue"
+	m.IsActive = 1
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyCommissionRuleTier make a dummy data for model CommissionRuleTier
+func DummyCommissionRuleTier() *CommissionRuleTier {
+	var m CommissionRuleTier
+	faker.Fill(&m, "ID")
+
+	m.CommissionRule = DummyCommissionRule()
+
+	m.MinTarget = 0
+	m.Rate = 2
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyCommissionStatement make a dummy data for model CommissionStatement
+func DummyCommissionStatement() *CommissionStatement {
+	var m CommissionStatement
+	faker.Fill(&m, "ID")
+
+	m.SalesPerson = DummyUser()
+
+	m.CreatedBy = DummyUser()
+
+	m.DocumentStatus = "new"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyCommissionStatementItem make a dummy data for model CommissionStatementItem
+func DummyCommissionStatementItem() *CommissionStatementItem {
+	var m CommissionStatementItem
+	faker.Fill(&m, "ID")
+
+	m.CommissionStatement = DummyCommissionStatement()
+
+	m.CommissionRule = DummyCommissionRule()
+
+	m.RefType = "sales_invoice"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyVisitLog make a dummy data for model VisitLog
+func DummyVisitLog() *VisitLog {
+	var m VisitLog
+	faker.Fill(&m, "ID")
+
+	m.Partnership = DummyPartnership()
+
+	m.SalesPerson = DummyUser()
+
+	m.CreatedBy = DummyUser()
+
+	m.Outcome = "no_order"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyRecurringOrder make a dummy data for model RecurringOrder
+func DummyRecurringOrder() *RecurringOrder {
+	var m RecurringOrder
+	faker.Fill(&m, "ID")
+
+	m.Customer = DummyPartnership()
+
+	m.CreatedBy = DummyUser()
+
+	m.UpdatedBy = DummyUser()
+
+	m.ScheduleType = "weekly"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyRecurringOrderItem make a dummy data for model RecurringOrderItem
+func DummyRecurringOrderItem() *RecurringOrderItem {
+	var m RecurringOrderItem
+	faker.Fill(&m, "ID")
+
+	m.RecurringOrder = DummyRecurringOrder()
+
+	m.ItemVariant = DummyItemVariant()
+
+	m.PricingType = DummyPricingType()
+
+	m.TaxCode = DummyTaxCode()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyRecurringOrderLog make a dummy data for model RecurringOrderLog
+func DummyRecurringOrderLog() *RecurringOrderLog {
+	var m RecurringOrderLog
+	faker.Fill(&m, "ID")
+
+	m.RecurringOrder = DummyRecurringOrder()
+
+	m.SalesOrder = DummySalesOrder()
+
+	m.Status = "failed"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyDiscountLimit make a dummy data for model DiscountLimit
+func DummyDiscountLimit() *DiscountLimit {
+	var m DiscountLimit
+	faker.Fill(&m, "ID")
+
+	m.Usergroup = DummyUsergroup()
+
+	m.CreatedBy = DummyUser()
+
+	m.UpdatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummySalesOrderApproval make a dummy data for model SalesOrderApproval
+func DummySalesOrderApproval() *SalesOrderApproval {
+	var m SalesOrderApproval
+	faker.Fill(&m, "ID")
+
+	m.SalesOrder = DummySalesOrder()
+
+	m.RequestedBy = DummyUser()
+
+	m.DecidedBy = DummyUser()
+
+	m.Status = "pending"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummySalesOrderRevision make a dummy data for model SalesOrderRevision
+func DummySalesOrderRevision() *SalesOrderRevision {
+	var m SalesOrderRevision
+	faker.Fill(&m, "ID")
+
+	m.SalesOrder = DummySalesOrder()
+
+	m.CreatedBy = DummyUser()
+
+	m.Snapshot = "{}"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPosShift make a dummy data for model PosShift
+func DummyPosShift() *PosShift {
+	var m PosShift
+	faker.Fill(&m, "ID")
+
+	m.Cashier = DummyUser()
+
+	m.Status = "open"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPosTransaction make a dummy data for model PosTransaction
+func DummyPosTransaction() *PosTransaction {
+	var m PosTransaction
+	faker.Fill(&m, "ID")
+
+	m.PosShift = DummyPosShift()
+
+	m.SalesOrder = DummySalesOrder()
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPosPayment make a dummy data for model PosPayment
+func DummyPosPayment() *PosPayment {
+	var m PosPayment
+	faker.Fill(&m, "ID")
+
+	m.PosTransaction = DummyPosTransaction()
+
+	m.BankAccount = DummyBankAccount()
+
+	m.FinanceRevenue = DummyFinanceRevenue()
+
+	m.PaymentMethod = "cash"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyCreditNote make a dummy data for model CreditNote
+func DummyCreditNote() *CreditNote {
+	var m CreditNote
+	faker.Fill(&m, "ID")
+
+	m.Partnership = DummyPartnership()
+
+	m.SalesReturn = DummySalesReturn()
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyDebitNote make a dummy data for model DebitNote
+func DummyDebitNote() *DebitNote {
+	var m DebitNote
+	faker.Fill(&m, "ID")
+
+	m.Partnership = DummyPartnership()
+
+	m.PurchaseReturn = DummyPurchaseReturn()
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyRequisitionApprovalLevel make a dummy data for model RequisitionApprovalLevel
+func DummyRequisitionApprovalLevel() *RequisitionApprovalLevel {
+	var m RequisitionApprovalLevel
+	faker.Fill(&m, "ID")
+
+	m.Usergroup = DummyUsergroup()
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchaseRequisition make a dummy data for model PurchaseRequisition
+func DummyPurchaseRequisition() *PurchaseRequisition {
+	var m PurchaseRequisition
+	faker.Fill(&m, "ID")
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchaseRequisitionItem make a dummy data for model PurchaseRequisitionItem
+func DummyPurchaseRequisitionItem() *PurchaseRequisitionItem {
+	var m PurchaseRequisitionItem
+	faker.Fill(&m, "ID")
+
+	m.PurchaseRequisition = DummyPurchaseRequisition()
+
+	m.ItemVariant = DummyItemVariant()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchaseRequisitionApproval make a dummy data for model PurchaseRequisitionApproval
+func DummyPurchaseRequisitionApproval() *PurchaseRequisitionApproval {
+	var m PurchaseRequisitionApproval
+	faker.Fill(&m, "ID")
+
+	m.PurchaseRequisition = DummyPurchaseRequisition()
+
+	m.Usergroup = DummyUsergroup()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchaseInvoiceItem make a dummy data for model PurchaseInvoiceItem
+func DummyPurchaseInvoiceItem() *PurchaseInvoiceItem {
+	var m PurchaseInvoiceItem
+	faker.Fill(&m, "ID")
+
+	m.PurchaseInvoice = DummyPurchaseInvoice()
+
+	m.PurchaseOrderItem = DummyPurchaseOrderItem()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchaseInvoiceMismatch make a dummy data for model PurchaseInvoiceMismatch
+func DummyPurchaseInvoiceMismatch() *PurchaseInvoiceMismatch {
+	var m PurchaseInvoiceMismatch
+	faker.Fill(&m, "ID")
+
+	m.PurchaseInvoiceItem = DummyPurchaseInvoiceItem()
+
+	m.PurchaseInvoice = m.PurchaseInvoiceItem.PurchaseInvoice
+
+	m.ResolvedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyLandedCost make a dummy data for model LandedCost
+func DummyLandedCost() *LandedCost {
+	var m LandedCost
+	faker.Fill(&m, "ID")
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyLandedCostCharge make a dummy data for model LandedCostCharge
+func DummyLandedCostCharge() *LandedCostCharge {
+	var m LandedCostCharge
+	faker.Fill(&m, "ID")
+
+	m.LandedCost = DummyLandedCost()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyLandedCostItem make a dummy data for model LandedCostItem
+func DummyLandedCostItem() *LandedCostItem {
+	var m LandedCostItem
+	faker.Fill(&m, "ID")
+
+	m.LandedCost = DummyLandedCost()
+
+	m.WorkorderReceivingItem = DummyWorkorderReceivingItem()
+
+	m.ItemVariantStock = DummyItemVariantStock()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyLandedCostAdjustment make a dummy data for model LandedCostAdjustment
+func DummyLandedCostAdjustment() *LandedCostAdjustment {
+	var m LandedCostAdjustment
+	faker.Fill(&m, "ID")
+
+	m.LandedCostItem = DummyLandedCostItem()
+
+	m.LandedCost = m.LandedCostItem.LandedCost
+
+	m.SalesOrder = DummySalesOrder()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummySupplierCatalogue make a dummy data for model SupplierCatalogue
+func DummySupplierCatalogue() *SupplierCatalogue {
+	var m SupplierCatalogue
+	faker.Fill(&m, "ID")
+
+	m.Partnership = DummyPartnership()
+
+	m.ItemVariant = DummyItemVariant()
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchasePriceAlert make a dummy data for model PurchasePriceAlert
+func DummyPurchasePriceAlert() *PurchasePriceAlert {
+	var m PurchasePriceAlert
+	faker.Fill(&m, "ID")
+
+	m.PurchaseOrderItem = DummyPurchaseOrderItem()
+
+	m.PurchaseOrder = m.PurchaseOrderItem.PurchaseOrder
+
+	m.LastPurchaseOrderItem = DummyPurchaseOrderItem()
+
+	m.ItemVariant = m.PurchaseOrderItem.ItemVariant
+
+	m.Partnership = DummyPartnership()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPurchaseOrderEmail make a dummy data for model PurchaseOrderEmail
+func DummyPurchaseOrderEmail() *PurchaseOrderEmail {
+	var m PurchaseOrderEmail
+	faker.Fill(&m, "ID")
+
+	m.PurchaseOrder = DummyPurchaseOrder()
+
+	m.Partnership = DummyPartnership()
+
+	m.SentBy = DummyUser()
+
+	m.Status = "sent"
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyChartOfAccount make a dummy data for model ChartOfAccount
+func DummyChartOfAccount() *ChartOfAccount {
+	var m ChartOfAccount
+	faker.Fill(&m, "ID")
+
+	m.Parent = nil
+
+	m.Code = common.RandomStr(10)
+
+	m.AccountType = "asset"
+
+	m.NormalBalance = "debit"
+
+	m.IsArchived = 0
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyJournalEntry make a dummy data for model JournalEntry
+func DummyJournalEntry() *JournalEntry {
+	var m JournalEntry
+	faker.Fill(&m, "ID")
+
+	m.ReversalOf = nil
+
+	m.TotalCredit = m.TotalDebit
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyJournalEntryLine make a dummy data for model JournalEntryLine
+func DummyJournalEntryLine() *JournalEntryLine {
+	var m JournalEntryLine
+	faker.Fill(&m, "ID")
+
+	m.JournalEntry = DummyJournalEntry()
+
+	m.ChartOfAccount = DummyChartOfAccount()
+
+	m.Partnership = DummyPartnership()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyExpenseCategory make a dummy data for model ExpenseCategory
+func DummyExpenseCategory() *ExpenseCategory {
+	var m ExpenseCategory
+	faker.Fill(&m, "ID")
+
+	m.ChartOfAccount = &ChartOfAccount{Code: "6101"}
+	m.ChartOfAccount.Read("Code")
+
+	m.IsArchived = 0
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyOperatingExpense make a dummy data for model OperatingExpense
+func DummyOperatingExpense() *OperatingExpense {
+	var m OperatingExpense
+	faker.Fill(&m, "ID")
+
+	m.RecurringOf = nil
+
+	m.ExpenseCategory = DummyExpenseCategory()
+
+	m.BankAccount = nil
+
+	m.PaymentMethod = "cash"
+
+	m.DocumentStatus = "new"
+
+	m.IsRecurring = 0
+
+	m.RecurringInterval = "monthly"
+
+	m.ApprovedBy = nil
+
+	m.CreatedBy = DummyUser()
+
+	m.UpdatedBy = nil
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyBankStatement make a dummy data for model BankStatement
+func DummyBankStatement() *BankStatement {
+	var m BankStatement
+	faker.Fill(&m, "ID")
+
+	m.BankAccount = DummyBankAccount()
+
+	m.Format = "generic"
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyBankStatementLine make a dummy data for model BankStatementLine
+func DummyBankStatementLine() *BankStatementLine {
+	var m BankStatementLine
+	faker.Fill(&m, "ID")
+
+	m.BankStatement = DummyBankStatement()
+
+	m.Status = "unmatched"
+
+	m.RefType = ""
+
+	m.RefID = 0
+
+	m.MatchedBy = nil
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyBankTransfer make a dummy data for model BankTransfer
+func DummyBankTransfer() *BankTransfer {
+	var m BankTransfer
+	faker.Fill(&m, "ID")
+
+	m.FromBankAccount = DummyBankAccount()
+
+	m.ToBankAccount = DummyBankAccount()
+
+	m.DocumentStatus = "active"
+
+	m.CreatedBy = DummyUser()
+
+	m.CancelledBy = nil
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
+
+// DummyPayment make a dummy data for model Payment
+func DummyPayment() *Payment {
+	var m Payment
+	faker.Fill(&m, "ID")
+
+	m.PaymentType = "receipt"
+
+	m.Partnership = DummyPartnership()
+
+	m.BankAccount = DummyBankAccount()
+
+	m.PaymentMethod = "transfer"
+
+	m.DocumentStatus = "unapplied"
+
+	m.CreatedBy = DummyUser()
+
+	if e := m.Save(); e != nil {
+		fmt.Printf("error saving %s", e.Error())
+	}
+	return &m
+}
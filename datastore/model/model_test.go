@@ -18,6 +18,6 @@ func TestMain(m *testing.M) {
 	res := m.Run()
 
 	// cleanup
-	test.DataCleanUp("bank_account","direct_placement", "direct_placement_item", "finance_expense", "finance_revenue", "invoice_receipt", "invoice_receipt_item", "invoice_receipt_return", "item", "item_category", "item_variant", "item_variant_price", "item_variant_stock", "item_variant_stock_log", "measurement", "partnership", "pricing_type", "purchase_invoice", "purchase_order", "purchase_order_item", "purchase_return", "purchase_return_item", "recap_sales", "recap_sales_item", "sales_invoice", "sales_order", "sales_order_item", "sales_return", "sales_return_item", "stockopname", "stockopname_item", "workorder_fulfillment", "workorder_fulfillment_item", "workorder_receiving", "workorder_receiving_item", "workorder_shipment", "workorder_shipment_item")
+	test.DataCleanUp("bank_account", "bank_statement", "bank_statement_line", "bank_transfer", "commission_rule", "commission_rule_tier", "commission_statement", "commission_statement_item", "credit_note", "debit_note", "direct_placement", "direct_placement_item", "discount_limit", "dunning_log", "finance_expense", "finance_revenue", "invoice_receipt", "invoice_receipt_item", "invoice_receipt_return", "item", "item_category", "item_variant", "item_variant_price", "item_variant_stock", "item_variant_stock_log", "journal_entry", "journal_entry_line", "landed_cost", "landed_cost_adjustment", "landed_cost_charge", "landed_cost_item", "measurement", "operating_expense", "partnership", "payment", "pos_payment", "pos_shift", "pos_transaction", "pricing_type", "purchase_invoice", "purchase_invoice_item", "purchase_invoice_mismatch", "purchase_order", "purchase_order_email", "purchase_order_item", "purchase_price_alert", "purchase_requisition", "purchase_requisition_approval", "purchase_requisition_item", "purchase_return", "purchase_return_item", "recap_sales", "recap_sales_item", "recurring_order", "recurring_order_item", "recurring_order_log", "requisition_approval_level", "sales_invoice", "sales_order", "sales_order_approval", "sales_order_item", "sales_order_revision", "sales_return", "sales_return_item", "stockopname", "stockopname_item", "supplier_catalogue", "tax_invoice_range", "visit_log", "workorder_fulfillment", "workorder_fulfillment_item", "workorder_receiving", "workorder_receiving_item", "workorder_shipment", "workorder_shipment_item")
 	os.Exit(res)
 }
@@ -0,0 +1,149 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(OperatingExpense))
+}
+
+// OperatingExpense model for operating_expense table.
+type OperatingExpense struct {
+	ID                int64             `orm:"column(id);auto" json:"-"`
+	RecurringOf       *OperatingExpense `orm:"column(recurring_of_id);null;rel(fk)" json:"recurring_of,omitempty"`
+	ExpenseCategory   *ExpenseCategory  `orm:"column(expense_category_id);rel(fk)" json:"expense_category,omitempty"`
+	BankAccount       *BankAccount      `orm:"column(bank_account_id);null;rel(fk)" json:"bank_account,omitempty"`
+	Code              string            `orm:"column(code);size(45)" json:"code"`
+	RecognitionDate   time.Time         `orm:"column(recognition_date);type(date)" json:"recognition_date"`
+	Amount            float64           `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
+	PaymentMethod     string            `orm:"column(payment_method);options(cash,bank)" json:"payment_method"`
+	Attachment        string            `orm:"column(attachment);null" json:"attachment"`
+	Note              string            `orm:"column(note);null" json:"note"`
+	DocumentStatus    string            `orm:"column(document_status);options(new,approved,cancelled)" json:"document_status"`
+	IsRecurring       int8              `orm:"column(is_recurring);null" json:"is_recurring"`
+	RecurringInterval string            `orm:"column(recurring_interval);null;size(10)" json:"recurring_interval"`
+	NextRecurringDate time.Time         `orm:"column(next_recurring_date);type(date);null" json:"next_recurring_date"`
+	RecurringEndDate  time.Time         `orm:"column(recurring_end_date);type(date);null" json:"recurring_end_date"`
+	ApprovedBy        *User             `orm:"column(approved_by);null;rel(fk)" json:"approved_by,omitempty"`
+	ApprovedAt        time.Time         `orm:"column(approved_at);type(timestamp);null" json:"approved_at"`
+	CreatedBy         *User             `orm:"column(created_by);null;rel(fk)" json:"created_by,omitempty"`
+	UpdatedBy         *User             `orm:"column(updated_by);null;rel(fk)" json:"updated_by,omitempty"`
+	CreatedAt         time.Time         `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt         time.Time         `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *OperatingExpense) MarshalJSON() ([]byte, error) {
+	type Alias OperatingExpense
+
+	alias := &struct {
+		ID                string `json:"id"`
+		RecurringOfID     string `json:"recurring_of_id"`
+		ExpenseCategoryID string `json:"expense_category_id"`
+		BankAccountID     string `json:"bank_account_id"`
+		ApprovedByID      string `json:"approved_by_id"`
+		CreatedByID       string `json:"created_by_id"`
+		UpdatedByID       string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.RecurringOfID when m.RecurringOf not nill
+	// and the ID is setted
+	if m.RecurringOf != nil && m.RecurringOf.ID != int64(0) {
+		alias.RecurringOfID = common.Encrypt(m.RecurringOf.ID)
+	} else {
+		alias.RecurringOf = nil
+	}
+
+	// Encrypt alias.ExpenseCategoryID when m.ExpenseCategory not nill
+	// and the ID is setted
+	if m.ExpenseCategory != nil && m.ExpenseCategory.ID != int64(0) {
+		alias.ExpenseCategoryID = common.Encrypt(m.ExpenseCategory.ID)
+	} else {
+		alias.ExpenseCategory = nil
+	}
+
+	// Encrypt alias.BankAccountID when m.BankAccount not nill
+	// and the ID is setted
+	if m.BankAccount != nil && m.BankAccount.ID != int64(0) {
+		alias.BankAccountID = common.Encrypt(m.BankAccount.ID)
+	} else {
+		alias.BankAccount = nil
+	}
+
+	// Encrypt alias.ApprovedByID when m.ApprovedBy not nill
+	// and the ID is setted
+	if m.ApprovedBy != nil && m.ApprovedBy.ID != int64(0) {
+		alias.ApprovedByID = common.Encrypt(m.ApprovedBy.ID)
+	} else {
+		alias.ApprovedBy = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating OperatingExpense struct into operating_expense table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to operating_expense.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *OperatingExpense) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting operating_expense data
+// this also will truncated all data from all table
+// that have relation with this operating_expense.
+func (m *OperatingExpense) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *OperatingExpense) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperatingExpense_Save(t *testing.T) {
+	var m model.OperatingExpense
+	faker.Fill(&m, "ID")
+
+	m.RecurringOf = nil
+
+	m.ExpenseCategory = model.DummyExpenseCategory()
+
+	m.BankAccount = model.DummyBankAccount()
+
+	m.PaymentMethod = "bank"
+
+	m.DocumentStatus = "new"
+
+	m.RecurringInterval = "monthly"
+
+	m.ApprovedBy = nil
+
+	m.CreatedBy = model.DummyUser()
+
+	m.UpdatedBy = nil
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestOperatingExpense_Delete(t *testing.T) {
+	m := model.DummyOperatingExpense()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.OperatingExpense)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.OperatingExpense)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestOperatingExpense_Read(t *testing.T) {
+	var m model.OperatingExpense
+
+	mn := model.DummyOperatingExpense()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestOperatingExpense_MarshalJSON(t *testing.T) {
+	mn := model.DummyOperatingExpense()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
@@ -36,12 +36,14 @@ type Partnership struct {
 	TotalCredit      float64   `orm:"column(total_credit);null;digits(20);decimals(0)" json:"total_credit"`
 	TotalSpend       float64   `orm:"column(total_spend);null;digits(20);decimals(0)" json:"total_spend"`
 	TotalExpenditure float64   `orm:"column(total_expenditure);null;digits(20);decimals(0)" json:"total_expenditure"`
-	SalesPerson      string    `orm:"column(sales_person);size(45);null" json:"sales_person"`
+	SalesPerson      *User     `orm:"column(sales_person_id);null;rel(fk)" json:"sales_person,omitempty"`
 	VisitDay         string    `orm:"column(visit_day);size(45);null" json:"visit_day"`
+	Npwp             string    `orm:"column(npwp);size(20);null" json:"npwp"`
 	Note             string    `orm:"column(note);null" json:"note"`
 	IsArchived       int8      `orm:"column(is_archived);null" json:"is_archived"`
 	IsDeleted        int8      `orm:"column(is_deleted);null" json:"is_deleted"`
 	IsDefault        int8      `orm:"column(is_default);null" json:"is_default"`
+	IsDunningPaused  int8      `orm:"column(is_dunning_paused);null" json:"is_dunning_paused"`
 	CreatedBy        *User     `orm:"column(created_by);rel(fk)" json:"created_by"`
 	UpdatedBy        *User     `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
 	CreatedAt        time.Time `orm:"column(created_at);type(timestamp)" json:"created_at"`
@@ -54,15 +56,24 @@ func (m *Partnership) MarshalJSON() ([]byte, error) {
 	type Alias Partnership
 
 	alias := &struct {
-		ID          string `json:"id"`
-		CreatedByID string `json:"created_by_id"`
-		UpdatedByID string `json:"updated_by_id"`
+		ID            string `json:"id"`
+		SalesPersonID string `json:"sales_person_id"`
+		CreatedByID   string `json:"created_by_id"`
+		UpdatedByID   string `json:"updated_by_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
 		Alias: (*Alias)(m),
 	}
 
+	// Encrypt alias.SalesPersonID when m.SalesPerson not nill
+	// and the ID is setted
+	if m.SalesPerson != nil && m.SalesPerson.ID != int64(0) {
+		alias.SalesPersonID = common.Encrypt(m.SalesPerson.ID)
+	} else {
+		alias.SalesPerson = nil
+	}
+
 	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
 	// and the ID is setted
 	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
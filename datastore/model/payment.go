@@ -0,0 +1,129 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(Payment))
+}
+
+// Payment model for payment table.
+type Payment struct {
+	ID              int64             `orm:"column(id);auto" json:"-"`
+	Code            string            `orm:"column(code);size(45)" json:"code"`
+	PaymentType     string            `orm:"column(payment_type);options(receipt,disbursement)" json:"payment_type"`
+	Partnership     *Partnership      `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	BankAccount     *BankAccount      `orm:"column(bank_account_id);null;rel(fk)" json:"bank_account,omitempty"`
+	RecognitionDate time.Time         `orm:"column(recognition_date);type(date)" json:"recognition_date"`
+	PaymentMethod   string            `orm:"column(payment_method);options(cash,transfer,giro)" json:"payment_method"`
+	Reference       string            `orm:"column(reference);size(45);null" json:"reference"`
+	Amount          float64           `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
+	AllocatedAmount float64           `orm:"column(allocated_amount);digits(20);decimals(0)" json:"allocated_amount"`
+	UnappliedAmount float64           `orm:"column(unapplied_amount);digits(20);decimals(0)" json:"unapplied_amount"`
+	DocumentStatus  string            `orm:"column(document_status);options(unapplied,partial,applied)" json:"document_status"`
+	Note            string            `orm:"column(note);null" json:"note"`
+	CreatedBy       *User             `orm:"column(created_by);rel(fk)" json:"created_by"`
+	UpdatedBy       *User             `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt       time.Time         `orm:"column(created_at);type(timestamp)" json:"created_at"`
+	UpdatedAt       time.Time         `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	FinanceRevenues []*FinanceRevenue `orm:"-" json:"finance_revenues,omitempty"`
+	FinanceExpenses []*FinanceExpense `orm:"-" json:"finance_expenses,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *Payment) MarshalJSON() ([]byte, error) {
+	type Alias Payment
+
+	alias := &struct {
+		ID            string `json:"id"`
+		PartnershipID string `json:"partnership_id"`
+		BankAccountID string `json:"bank_account_id"`
+		CreatedByID   string `json:"created_by_id"`
+		UpdatedByID   string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	// Encrypt alias.BankAccountID when m.BankAccount not nill
+	// and the ID is setted
+	if m.BankAccount != nil && m.BankAccount.ID != int64(0) {
+		alias.BankAccountID = common.Encrypt(m.BankAccount.ID)
+	} else {
+		alias.BankAccount = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating Payment struct into payment table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to payment.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *Payment) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting payment data
+// this also will truncated all data from all table
+// that have relation with this payment.
+func (m *Payment) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *Payment) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayment_Save(t *testing.T) {
+	var m model.Payment
+	faker.Fill(&m, "ID")
+
+	m.PaymentType = "receipt"
+
+	m.Partnership = model.DummyPartnership()
+
+	m.BankAccount = model.DummyBankAccount()
+
+	m.PaymentMethod = "transfer"
+
+	m.DocumentStatus = "unapplied"
+
+	m.CreatedBy = model.DummyUser()
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestPayment_Delete(t *testing.T) {
+	m := model.DummyPayment()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.Payment)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.Payment)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestPayment_Read(t *testing.T) {
+	var m model.Payment
+
+	mn := model.DummyPayment()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestPayment_MarshalJSON(t *testing.T) {
+	mn := model.DummyPayment()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PosPayment))
+}
+
+// PosPayment model for pos_payment table.
+type PosPayment struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	PosTransaction *PosTransaction `orm:"column(pos_transaction_id);rel(fk)" json:"pos_transaction,omitempty"`
+	PaymentMethod  string          `orm:"column(payment_method);options(cash,transfer,debit_card,credit_card)" json:"payment_method"`
+	BankAccount    *BankAccount    `orm:"column(bank_account_id);null;rel(fk)" json:"bank_account,omitempty"`
+	Amount         float64         `orm:"column(amount);digits(20);decimals(0)" json:"amount"`
+	FinanceRevenue *FinanceRevenue `orm:"column(finance_revenue_id);null;rel(fk)" json:"finance_revenue,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PosPayment) MarshalJSON() ([]byte, error) {
+	type Alias PosPayment
+
+	alias := &struct {
+		ID               string `json:"id"`
+		PosTransactionID string `json:"pos_transaction_id"`
+		BankAccountID    string `json:"bank_account_id"`
+		FinanceRevenueID string `json:"finance_revenue_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PosTransactionID when m.PosTransaction not nill
+	// and the ID is setted
+	if m.PosTransaction != nil && m.PosTransaction.ID != int64(0) {
+		alias.PosTransactionID = common.Encrypt(m.PosTransaction.ID)
+	} else {
+		alias.PosTransaction = nil
+	}
+
+	// Encrypt alias.BankAccountID when m.BankAccount not nill
+	// and the ID is setted
+	if m.BankAccount != nil && m.BankAccount.ID != int64(0) {
+		alias.BankAccountID = common.Encrypt(m.BankAccount.ID)
+	} else {
+		alias.BankAccount = nil
+	}
+
+	// Encrypt alias.FinanceRevenueID when m.FinanceRevenue not nill
+	// and the ID is setted
+	if m.FinanceRevenue != nil && m.FinanceRevenue.ID != int64(0) {
+		alias.FinanceRevenueID = common.Encrypt(m.FinanceRevenue.ID)
+	} else {
+		alias.FinanceRevenue = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PosPayment struct into pos_payment table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to pos_payment.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PosPayment) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting pos_payment data
+// this also will truncated all data from all table
+// that have relation with this pos_payment.
+func (m *PosPayment) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PosPayment) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PosShift))
+}
+
+// PosShift model for pos_shift table.
+type PosShift struct {
+	ID               int64     `orm:"column(id);auto" json:"-"`
+	Code             string    `orm:"column(code);size(45)" json:"code"`
+	Cashier          *User     `orm:"column(cashier_id);rel(fk)" json:"cashier,omitempty"`
+	OpeningFloat     float64   `orm:"column(opening_float);digits(20);decimals(0)" json:"opening_float"`
+	CashSales        float64   `orm:"column(cash_sales);digits(20);decimals(0)" json:"cash_sales"`
+	NonCashSales     float64   `orm:"column(non_cash_sales);digits(20);decimals(0)" json:"non_cash_sales"`
+	ExpectedCash     float64   `orm:"column(expected_cash);digits(20);decimals(0)" json:"expected_cash"`
+	CountedCash      float64   `orm:"column(counted_cash);digits(20);decimals(0)" json:"counted_cash"`
+	Difference       float64   `orm:"column(difference);digits(20);decimals(0)" json:"difference"`
+	TotalTransaction int       `orm:"column(total_transaction)" json:"total_transaction"`
+	Status           string    `orm:"column(status);options(open,closed)" json:"status"`
+	Note             string    `orm:"column(note);null" json:"note"`
+	OpenedAt         time.Time `orm:"column(opened_at);type(timestamp);null" json:"opened_at"`
+	ClosedAt         time.Time `orm:"column(closed_at);type(timestamp);null" json:"closed_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PosShift) MarshalJSON() ([]byte, error) {
+	type Alias PosShift
+
+	alias := &struct {
+		ID        string `json:"id"`
+		CashierID string `json:"cashier_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CashierID when m.Cashier not nill
+	// and the ID is setted
+	if m.Cashier != nil && m.Cashier.ID != int64(0) {
+		alias.CashierID = common.Encrypt(m.Cashier.ID)
+	} else {
+		alias.Cashier = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PosShift struct into pos_shift table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to pos_shift.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PosShift) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting pos_shift data
+// this also will truncated all data from all table
+// that have relation with this pos_shift.
+func (m *PosShift) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PosShift) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PosTransaction))
+}
+
+// PosTransaction model for pos_transaction table.
+type PosTransaction struct {
+	ID            int64         `orm:"column(id);auto" json:"-"`
+	PosShift      *PosShift     `orm:"column(pos_shift_id);rel(fk)" json:"pos_shift,omitempty"`
+	SalesOrder    *SalesOrder   `orm:"column(sales_order_id);rel(fk)" json:"sales_order,omitempty"`
+	TotalCharge   float64       `orm:"column(total_charge);digits(20);decimals(0)" json:"total_charge"`
+	TotalTendered float64       `orm:"column(total_tendered);digits(20);decimals(0)" json:"total_tendered"`
+	ChangeAmount  float64       `orm:"column(change_amount);digits(20);decimals(0)" json:"change_amount"`
+	CreatedBy     *User         `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	CreatedAt     time.Time     `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	PosPayments   []*PosPayment `orm:"reverse(many)" json:"pos_payments,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PosTransaction) MarshalJSON() ([]byte, error) {
+	type Alias PosTransaction
+
+	alias := &struct {
+		ID           string `json:"id"`
+		PosShiftID   string `json:"pos_shift_id"`
+		SalesOrderID string `json:"sales_order_id"`
+		CreatedByID  string `json:"created_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PosShiftID when m.PosShift not nill
+	// and the ID is setted
+	if m.PosShift != nil && m.PosShift.ID != int64(0) {
+		alias.PosShiftID = common.Encrypt(m.PosShift.ID)
+	} else {
+		alias.PosShift = nil
+	}
+
+	// Encrypt alias.SalesOrderID when m.SalesOrder not nill
+	// and the ID is setted
+	if m.SalesOrder != nil && m.SalesOrder.ID != int64(0) {
+		alias.SalesOrderID = common.Encrypt(m.SalesOrder.ID)
+	} else {
+		alias.SalesOrder = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PosTransaction struct into pos_transaction table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to pos_transaction.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PosTransaction) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting pos_transaction data
+// this also will truncated all data from all table
+// that have relation with this pos_transaction.
+func (m *PosTransaction) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PosTransaction) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
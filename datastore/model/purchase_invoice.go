@@ -18,21 +18,25 @@ func init() {
 
 // PurchaseInvoice model for purchase_invoice table.
 type PurchaseInvoice struct {
-	ID              int64          `orm:"column(id);auto" json:"-"`
-	PurchaseOrder   *PurchaseOrder `orm:"column(purchase_order_id);rel(fk)" json:"purchase_order,omitempty"`
-	Code            string         `orm:"column(code);size(120)" json:"code"`
-	RecognitionDate time.Time      `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
-	DueDate         time.Time      `orm:"column(due_date);type(date);null" json:"due_date"`
-	TotalAmount     float64        `orm:"column(total_amount);null;digits(20);decimals(0)" json:"total_amount"`
-	TotalPaid       float64        `orm:"column(total_paid);null;digits(20);decimals(0)" json:"total_paid"`
-	Note            string         `orm:"column(note);null" json:"note"`
-	DocumentStatus  string         `orm:"column(document_status);null;options(new,active,finished)" json:"document_status"`
-	IsDeleted       int8           `orm:"column(is_deleted);null" json:"is_deleted"`
-	CreatedBy       *User          `orm:"column(created_by);rel(fk)" json:"created_by"`
-	UpdatedBy       *User          `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
-	CreatedAt       time.Time      `orm:"column(created_at);type(timestamp);null" json:"created_at"`
-	UpdatedAt       time.Time      `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
-	BillingAddress  string         `orm:"column(billing_address);null" json:"billing_address"`
+	ID                        int64                      `orm:"column(id);auto" json:"-"`
+	PurchaseOrder             *PurchaseOrder             `orm:"column(purchase_order_id);rel(fk)" json:"purchase_order,omitempty"`
+	Code                      string                     `orm:"column(code);size(120)" json:"code"`
+	TaxInvoiceNumber          string                     `orm:"column(tax_invoice_number);size(45);null" json:"tax_invoice_number"`
+	RecognitionDate           time.Time                  `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
+	DueDate                   time.Time                  `orm:"column(due_date);type(date);null" json:"due_date"`
+	TotalAmount               float64                    `orm:"column(total_amount);null;digits(20);decimals(0)" json:"total_amount"`
+	TotalPaid                 float64                    `orm:"column(total_paid);null;digits(20);decimals(0)" json:"total_paid"`
+	Note                      string                     `orm:"column(note);null" json:"note"`
+	DocumentStatus            string                     `orm:"column(document_status);null;options(new,active,finished)" json:"document_status"`
+	MatchStatus               string                     `orm:"column(match_status);options(matched,mismatch,resolved)" json:"match_status"`
+	IsDeleted                 int8                       `orm:"column(is_deleted);null" json:"is_deleted"`
+	CreatedBy                 *User                      `orm:"column(created_by);rel(fk)" json:"created_by"`
+	UpdatedBy                 *User                      `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt                 time.Time                  `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt                 time.Time                  `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	BillingAddress            string                     `orm:"column(billing_address);null" json:"billing_address"`
+	PurchaseInvoiceItems      []*PurchaseInvoiceItem     `orm:"reverse(many)" json:"purchase_invoice_items,omitempty"`
+	PurchaseInvoiceMismatches []*PurchaseInvoiceMismatch `orm:"reverse(many)" json:"purchase_invoice_mismatches,omitempty"`
 }
 
 // MarshalJSON customized data struct when marshaling data
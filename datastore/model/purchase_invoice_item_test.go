@@ -0,0 +1,72 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurchaseInvoiceItem_Save(t *testing.T) {
+	var m model.PurchaseInvoiceItem
+	faker.Fill(&m, "ID")
+
+	m.PurchaseInvoice = model.DummyPurchaseInvoice()
+
+	m.PurchaseOrderItem = model.DummyPurchaseOrderItem()
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestPurchaseInvoiceItem_Delete(t *testing.T) {
+	m := model.DummyPurchaseInvoiceItem()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.PurchaseInvoiceItem)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.PurchaseInvoiceItem)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestPurchaseInvoiceItem_Read(t *testing.T) {
+	var m model.PurchaseInvoiceItem
+
+	mn := model.DummyPurchaseInvoiceItem()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestPurchaseInvoiceItem_MarshalJSON(t *testing.T) {
+	mn := model.DummyPurchaseInvoiceItem()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
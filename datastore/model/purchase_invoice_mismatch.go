@@ -0,0 +1,113 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PurchaseInvoiceMismatch))
+}
+
+// PurchaseInvoiceMismatch model for purchase_invoice_mismatch table.
+type PurchaseInvoiceMismatch struct {
+	ID                  int64                `orm:"column(id);auto" json:"-"`
+	PurchaseInvoice     *PurchaseInvoice     `orm:"column(purchase_invoice_id);rel(fk)" json:"purchase_invoice,omitempty"`
+	PurchaseInvoiceItem *PurchaseInvoiceItem `orm:"column(purchase_invoice_item_id);null;rel(fk)" json:"purchase_invoice_item,omitempty"`
+	MismatchType        string               `orm:"column(mismatch_type);options(price,quantity,amount)" json:"mismatch_type"`
+	ExpectedValue       float64              `orm:"column(expected_value);digits(20);decimals(2)" json:"expected_value"`
+	InvoicedValue       float64              `orm:"column(invoiced_value);digits(20);decimals(2)" json:"invoiced_value"`
+	Variance            float64              `orm:"column(variance);digits(10);decimals(2)" json:"variance"`
+	Status              string               `orm:"column(status);options(open,resolved)" json:"status"`
+	ResolutionNote      string               `orm:"column(resolution_note);null" json:"resolution_note"`
+	ResolvedBy          *User                `orm:"column(resolved_by);null;rel(fk)" json:"resolved_by"`
+	ResolvedAt          time.Time            `orm:"column(resolved_at);type(timestamp);null" json:"resolved_at"`
+	CreatedAt           time.Time            `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PurchaseInvoiceMismatch) MarshalJSON() ([]byte, error) {
+	type Alias PurchaseInvoiceMismatch
+
+	alias := &struct {
+		ID                    string `json:"id"`
+		PurchaseInvoiceID     string `json:"purchase_invoice_id"`
+		PurchaseInvoiceItemID string `json:"purchase_invoice_item_id"`
+		ResolvedByID          string `json:"resolved_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PurchaseInvoiceID when m.PurchaseInvoice not nill
+	// and the ID is setted
+	if m.PurchaseInvoice != nil && m.PurchaseInvoice.ID != int64(0) {
+		alias.PurchaseInvoiceID = common.Encrypt(m.PurchaseInvoice.ID)
+	} else {
+		alias.PurchaseInvoice = nil
+	}
+
+	// Encrypt alias.PurchaseInvoiceItemID when m.PurchaseInvoiceItem not nill
+	// and the ID is setted
+	if m.PurchaseInvoiceItem != nil && m.PurchaseInvoiceItem.ID != int64(0) {
+		alias.PurchaseInvoiceItemID = common.Encrypt(m.PurchaseInvoiceItem.ID)
+	} else {
+		alias.PurchaseInvoiceItem = nil
+	}
+
+	// Encrypt alias.ResolvedByID when m.ResolvedBy not nill
+	// and the ID is setted
+	if m.ResolvedBy != nil && m.ResolvedBy.ID != int64(0) {
+		alias.ResolvedByID = common.Encrypt(m.ResolvedBy.ID)
+	} else {
+		alias.ResolvedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PurchaseInvoiceMismatch struct into purchase_invoice_mismatch table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to purchase_invoice_mismatch.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PurchaseInvoiceMismatch) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting purchase_invoice_mismatch data
+// this also will truncated all data from all table
+// that have relation with this purchase_invoice_mismatch.
+func (m *PurchaseInvoiceMismatch) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PurchaseInvoiceMismatch) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -20,6 +20,7 @@ func init() {
 type PurchaseOrder struct {
 	ID                  int64                 `orm:"column(id);auto" json:"-"`
 	Reference           *PurchaseOrder        `orm:"column(reference_id);null;rel(fk)" json:"reference,omitempty"`
+	PurchaseRequisition *PurchaseRequisition  `orm:"column(purchase_requisition_id);null;rel(fk)" json:"purchase_requisition,omitempty"`
 	Supplier            *Partnership          `orm:"column(supplier_id);rel(fk)" json:"supplier,omitempty"`
 	Code                string                `orm:"column(code);size(45)" json:"code"`
 	RecognitionDate     time.Time             `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
@@ -38,6 +39,8 @@ type PurchaseOrder struct {
 	AutoInvoiced        int8                  `orm:"column(auto_invoiced);null" json:"auto_invoiced"`
 	IsPercentage        int8                  `orm:"column(is_percentage);null" json:"is_percentage"`
 	IsDeleted           int8                  `orm:"column(is_deleted);null" json:"is_deleted"`
+	IsSent              int8                  `orm:"column(is_sent);null" json:"is_sent"`
+	SentAt              time.Time             `orm:"column(sent_at);type(timestamp);null" json:"sent_at"`
 	CreatedBy           *User                 `orm:"column(created_by);rel(fk)" json:"created_by"`
 	UpdatedBy           *User                 `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
 	CreatedAt           time.Time             `orm:"column(created_at);type(timestamp)" json:"created_at"`
@@ -46,6 +49,8 @@ type PurchaseOrder struct {
 	PurchaseInvoices    []*PurchaseInvoice    `orm:"reverse(many)" json:"purchase_invoices,omitempty"`
 	PurchaseReturns     []*PurchaseReturn     `orm:"reverse(many)" json:"purchase_returns,omitempty"`
 	WorkorderReceivings []*WorkorderReceiving `orm:"reverse(many)" json:"workorder_receivings,omitempty"`
+	PurchasePriceAlerts []*PurchasePriceAlert `orm:"reverse(many)" json:"purchase_price_alerts,omitempty"`
+	PurchaseOrderEmails []*PurchaseOrderEmail `orm:"reverse(many)" json:"purchase_order_emails,omitempty"`
 	CancelledNote       string                `orm:"column(cancelled_note);null" json:"cancelled_note"`
 }
 
@@ -55,11 +60,12 @@ func (m *PurchaseOrder) MarshalJSON() ([]byte, error) {
 	type Alias PurchaseOrder
 
 	alias := &struct {
-		ID          string `json:"id"`
-		ReferenceID string `json:"reference_id"`
-		SupplierID  string `json:"supplier_id"`
-		CreatedByID string `json:"created_by_id"`
-		UpdatedByID string `json:"updated_by_id"`
+		ID                    string `json:"id"`
+		ReferenceID           string `json:"reference_id"`
+		PurchaseRequisitionID string `json:"purchase_requisition_id"`
+		SupplierID            string `json:"supplier_id"`
+		CreatedByID           string `json:"created_by_id"`
+		UpdatedByID           string `json:"updated_by_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
@@ -74,6 +80,14 @@ func (m *PurchaseOrder) MarshalJSON() ([]byte, error) {
 		alias.Reference = nil
 	}
 
+	// Encrypt alias.PurchaseRequisitionID when m.PurchaseRequisition not nill
+	// and the ID is setted
+	if m.PurchaseRequisition != nil && m.PurchaseRequisition.ID != int64(0) {
+		alias.PurchaseRequisitionID = common.Encrypt(m.PurchaseRequisition.ID)
+	} else {
+		alias.PurchaseRequisition = nil
+	}
+
 	// Encrypt alias.SupplierID when m.Supplier not nill
 	// and the ID is setted
 	if m.Supplier != nil && m.Supplier.ID != int64(0) {
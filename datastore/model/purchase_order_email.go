@@ -0,0 +1,110 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PurchaseOrderEmail))
+}
+
+// PurchaseOrderEmail model for purchase_order_email table.
+type PurchaseOrderEmail struct {
+	ID            int64          `orm:"column(id);auto" json:"-"`
+	PurchaseOrder *PurchaseOrder `orm:"column(purchase_order_id);rel(fk)" json:"purchase_order,omitempty"`
+	Partnership   *Partnership   `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	Email         string         `orm:"column(email);size(45)" json:"email"`
+	Subject       string         `orm:"column(subject);size(255);null" json:"subject"`
+	Status        string         `orm:"column(status);options(sent,failed)" json:"status"`
+	Note          string         `orm:"column(note);null" json:"note"`
+	SentBy        *User          `orm:"column(sent_by);rel(fk)" json:"sent_by,omitempty"`
+	SentAt        time.Time      `orm:"column(sent_at);type(timestamp);null" json:"sent_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PurchaseOrderEmail) MarshalJSON() ([]byte, error) {
+	type Alias PurchaseOrderEmail
+
+	alias := &struct {
+		ID              string `json:"id"`
+		PurchaseOrderID string `json:"purchase_order_id"`
+		PartnershipID   string `json:"partnership_id"`
+		SentByID        string `json:"sent_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PurchaseOrderID when m.PurchaseOrder not nill
+	// and the ID is setted
+	if m.PurchaseOrder != nil && m.PurchaseOrder.ID != int64(0) {
+		alias.PurchaseOrderID = common.Encrypt(m.PurchaseOrder.ID)
+	} else {
+		alias.PurchaseOrder = nil
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	// Encrypt alias.SentByID when m.SentBy not nill
+	// and the ID is setted
+	if m.SentBy != nil && m.SentBy.ID != int64(0) {
+		alias.SentByID = common.Encrypt(m.SentBy.ID)
+	} else {
+		alias.SentBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PurchaseOrderEmail struct into purchase_order_email table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to purchase_order_email.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PurchaseOrderEmail) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting purchase_order_email data
+// this also will truncated all data from all table
+// that have relation with this purchase_order_email.
+func (m *PurchaseOrderEmail) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PurchaseOrderEmail) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
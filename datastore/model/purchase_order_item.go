@@ -17,16 +17,35 @@ func init() {
 
 // PurchaseOrderItem model for purchase_order_item table.
 type PurchaseOrderItem struct {
-	ID            int64          `orm:"column(id);auto" json:"-"`
-	PurchaseOrder *PurchaseOrder `orm:"column(purchase_order_id);rel(fk)" json:"purchase_order,omitempty"`
-	ItemVariant   *ItemVariant   `orm:"column(item_variant_id);rel(fk)" json:"item_variant,omitempty"`
-	Quantity      float32        `orm:"column(quantity)" json:"quantity"`
-	UnitPrice     float64        `orm:"column(unit_price);null;digits(20);decimals(0)" json:"unit_price"`
-	Discount      float32        `orm:"column(discount);null" json:"discount"`
-	Subtotal      float64        `orm:"column(subtotal);digits(20);decimals(0)" json:"subtotal"`
-	Note          string         `orm:"column(note);null" json:"note"`
-	CanBeReturn   float32        `orm:"-" json:"can_be_return,omitempty"` // Ketika Receiving Item telah dibuat maka update CanBeReturn Sesuai dengan Quantity Item yang di Receive
-	Partnership   *Partnership   `orm:"-" json:"partnership,omitempty"`
+	ID                      int64                    `orm:"column(id);auto" json:"-"`
+	PurchaseOrder           *PurchaseOrder           `orm:"column(purchase_order_id);rel(fk)" json:"purchase_order,omitempty"`
+	ItemVariant             *ItemVariant             `orm:"column(item_variant_id);rel(fk)" json:"item_variant,omitempty"`
+	PurchaseRequisitionItem *PurchaseRequisitionItem `orm:"column(purchase_requisition_item_id);null;rel(fk)" json:"purchase_requisition_item,omitempty"`
+	Quantity                float32                  `orm:"column(quantity)" json:"quantity"`
+	UnitPrice               float64                  `orm:"column(unit_price);null;digits(20);decimals(0)" json:"unit_price"`
+	Discount                float32                  `orm:"column(discount);null" json:"discount"`
+	TaxCode                 *TaxCode                 `orm:"column(tax_code_id);null;rel(fk)" json:"tax_code,omitempty"`
+	TaxBase                 float64                  `orm:"column(tax_base);null;digits(20);decimals(0)" json:"tax_base"`
+	TaxAmount               float64                  `orm:"column(tax_amount);null;digits(20);decimals(0)" json:"tax_amount"`
+	Subtotal                float64                  `orm:"column(subtotal);digits(20);decimals(0)" json:"subtotal"`
+	Note                    string                   `orm:"column(note);null" json:"note"`
+	CanBeReturn             float32                  `orm:"-" json:"can_be_return,omitempty"` // Ketika Receiving Item telah dibuat maka update CanBeReturn Sesuai dengan Quantity Item yang di Receive
+	Partnership             *Partnership             `orm:"-" json:"partnership,omitempty"`
+}
+
+// NetUnitPrice harga satuan setelah discount item, dibulatkan sesuai unit cost batch stock.
+func (m *PurchaseOrderItem) NetUnitPrice() float64 {
+	return common.FloatPrecision(m.UnitPrice-(m.UnitPrice*float64(m.Discount)/float64(100)), 0)
+}
+
+// LineTax tax code dan subtotal item untuk perhitungan ppn per baris.
+func (m *PurchaseOrderItem) LineTax() (*TaxCode, float64) {
+	return m.TaxCode, m.Subtotal
+}
+
+// SetLineTax menyimpan dpp dan ppn hasil perhitungan ppn per baris.
+func (m *PurchaseOrderItem) SetLineTax(base float64, tax float64) {
+	m.TaxBase, m.TaxAmount = base, tax
 }
 
 // MarshalJSON customized data struct when marshaling data
@@ -35,9 +54,11 @@ func (m *PurchaseOrderItem) MarshalJSON() ([]byte, error) {
 	type Alias PurchaseOrderItem
 
 	alias := &struct {
-		ID              string `json:"id"`
-		PurchaseOrderID string `json:"purchase_order_id"`
-		ItemVariantID   string `json:"item_variant_id"`
+		ID                        string `json:"id"`
+		PurchaseOrderID           string `json:"purchase_order_id"`
+		ItemVariantID             string `json:"item_variant_id"`
+		PurchaseRequisitionItemID string `json:"purchase_requisition_item_id"`
+		TaxCodeID                 string `json:"tax_code_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
@@ -60,6 +81,22 @@ func (m *PurchaseOrderItem) MarshalJSON() ([]byte, error) {
 		alias.ItemVariant = nil
 	}
 
+	// Encrypt alias.PurchaseRequisitionItemID when m.PurchaseRequisitionItem not nill
+	// and the ID is setted
+	if m.PurchaseRequisitionItem != nil && m.PurchaseRequisitionItem.ID != int64(0) {
+		alias.PurchaseRequisitionItemID = common.Encrypt(m.PurchaseRequisitionItem.ID)
+	} else {
+		alias.PurchaseRequisitionItem = nil
+	}
+
+	// Encrypt alias.TaxCodeID when m.TaxCode not nill
+	// and the ID is setted
+	if m.TaxCode != nil && m.TaxCode.ID != int64(0) {
+		alias.TaxCodeID = common.Encrypt(m.TaxCode.ID)
+	} else {
+		alias.TaxCode = nil
+	}
+
 	return json.Marshal(alias)
 }
 
@@ -0,0 +1,130 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PurchasePriceAlert))
+}
+
+// PurchasePriceAlert model for purchase_price_alert table.
+type PurchasePriceAlert struct {
+	ID                    int64              `orm:"column(id);auto" json:"-"`
+	PurchaseOrder         *PurchaseOrder     `orm:"column(purchase_order_id);rel(fk)" json:"purchase_order,omitempty"`
+	PurchaseOrderItem     *PurchaseOrderItem `orm:"column(purchase_order_item_id);rel(fk)" json:"purchase_order_item,omitempty"`
+	LastPurchaseOrderItem *PurchaseOrderItem `orm:"column(last_purchase_order_item_id);rel(fk)" json:"last_purchase_order_item,omitempty"`
+	ItemVariant           *ItemVariant       `orm:"column(item_variant_id);rel(fk)" json:"item_variant,omitempty"`
+	Partnership           *Partnership       `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	LastUnitPrice         float64            `orm:"column(last_unit_price);digits(20);decimals(2)" json:"last_unit_price"`
+	UnitPrice             float64            `orm:"column(unit_price);digits(20);decimals(2)" json:"unit_price"`
+	Deviation             float64            `orm:"column(deviation);digits(10);decimals(2)" json:"deviation"`
+	Threshold             float64            `orm:"column(threshold);digits(10);decimals(2)" json:"threshold"`
+	CreatedAt             time.Time          `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PurchasePriceAlert) MarshalJSON() ([]byte, error) {
+	type Alias PurchasePriceAlert
+
+	alias := &struct {
+		ID                      string `json:"id"`
+		PurchaseOrderID         string `json:"purchase_order_id"`
+		PurchaseOrderItemID     string `json:"purchase_order_item_id"`
+		LastPurchaseOrderItemID string `json:"last_purchase_order_item_id"`
+		ItemVariantID           string `json:"item_variant_id"`
+		PartnershipID           string `json:"partnership_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PurchaseOrderID when m.PurchaseOrder not nill
+	// and the ID is setted
+	if m.PurchaseOrder != nil && m.PurchaseOrder.ID != int64(0) {
+		alias.PurchaseOrderID = common.Encrypt(m.PurchaseOrder.ID)
+	} else {
+		alias.PurchaseOrder = nil
+	}
+
+	// Encrypt alias.PurchaseOrderItemID when m.PurchaseOrderItem not nill
+	// and the ID is setted
+	if m.PurchaseOrderItem != nil && m.PurchaseOrderItem.ID != int64(0) {
+		alias.PurchaseOrderItemID = common.Encrypt(m.PurchaseOrderItem.ID)
+	} else {
+		alias.PurchaseOrderItem = nil
+	}
+
+	// Encrypt alias.LastPurchaseOrderItemID when m.LastPurchaseOrderItem not nill
+	// and the ID is setted
+	if m.LastPurchaseOrderItem != nil && m.LastPurchaseOrderItem.ID != int64(0) {
+		alias.LastPurchaseOrderItemID = common.Encrypt(m.LastPurchaseOrderItem.ID)
+	} else {
+		alias.LastPurchaseOrderItem = nil
+	}
+
+	// Encrypt alias.ItemVariantID when m.ItemVariant not nill
+	// and the ID is setted
+	if m.ItemVariant != nil && m.ItemVariant.ID != int64(0) {
+		alias.ItemVariantID = common.Encrypt(m.ItemVariant.ID)
+	} else {
+		alias.ItemVariant = nil
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PurchasePriceAlert struct into purchase_price_alert table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to purchase_price_alert.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PurchasePriceAlert) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting purchase_price_alert data
+// this also will truncated all data from all table
+// that have relation with this purchase_price_alert.
+func (m *PurchasePriceAlert) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PurchasePriceAlert) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PurchaseRequisitionApproval))
+}
+
+// PurchaseRequisitionApproval model for purchase_requisition_approval table.
+type PurchaseRequisitionApproval struct {
+	ID                  int64                `orm:"column(id);auto" json:"-"`
+	PurchaseRequisition *PurchaseRequisition `orm:"column(purchase_requisition_id);rel(fk)" json:"purchase_requisition,omitempty"`
+	Usergroup           *Usergroup           `orm:"column(usergroup_id);rel(fk)" json:"usergroup,omitempty"`
+	Level               int                  `orm:"column(level)" json:"level"`
+	Status              string               `orm:"column(status);options(pending,approved,rejected)" json:"status"`
+	Note                string               `orm:"column(note);null" json:"note"`
+	DecidedBy           *User                `orm:"column(decided_by);null;rel(fk)" json:"decided_by"`
+	DecidedAt           time.Time            `orm:"column(decided_at);type(timestamp);null" json:"decided_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PurchaseRequisitionApproval) MarshalJSON() ([]byte, error) {
+	type Alias PurchaseRequisitionApproval
+
+	alias := &struct {
+		ID                    string `json:"id"`
+		PurchaseRequisitionID string `json:"purchase_requisition_id"`
+		UsergroupID           string `json:"usergroup_id"`
+		DecidedByID           string `json:"decided_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PurchaseRequisitionID when m.PurchaseRequisition not nill
+	// and the ID is setted
+	if m.PurchaseRequisition != nil && m.PurchaseRequisition.ID != int64(0) {
+		alias.PurchaseRequisitionID = common.Encrypt(m.PurchaseRequisition.ID)
+	} else {
+		alias.PurchaseRequisition = nil
+	}
+
+	// Encrypt alias.UsergroupID when m.Usergroup not nill
+	// and the ID is setted
+	if m.Usergroup != nil && m.Usergroup.ID != int64(0) {
+		alias.UsergroupID = common.Encrypt(m.Usergroup.ID)
+	} else {
+		alias.Usergroup = nil
+	}
+
+	// Encrypt alias.DecidedByID when m.DecidedBy not nill
+	// and the ID is setted
+	if m.DecidedBy != nil && m.DecidedBy.ID != int64(0) {
+		alias.DecidedByID = common.Encrypt(m.DecidedBy.ID)
+	} else {
+		alias.DecidedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PurchaseRequisitionApproval struct into purchase_requisition_approval table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to purchase_requisition_approval.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PurchaseRequisitionApproval) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting purchase_requisition_approval data
+// this also will truncated all data from all table
+// that have relation with this purchase_requisition_approval.
+func (m *PurchaseRequisitionApproval) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PurchaseRequisitionApproval) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(PurchaseRequisitionItem))
+}
+
+// PurchaseRequisitionItem model for purchase_requisition_item table.
+type PurchaseRequisitionItem struct {
+	ID                  int64                `orm:"column(id);auto" json:"-"`
+	PurchaseRequisition *PurchaseRequisition `orm:"column(purchase_requisition_id);rel(fk)" json:"purchase_requisition,omitempty"`
+	ItemVariant         *ItemVariant         `orm:"column(item_variant_id);rel(fk)" json:"item_variant,omitempty"`
+	Quantity            float32              `orm:"column(quantity)" json:"quantity"`
+	OrderedQuantity     float32              `orm:"column(ordered_quantity)" json:"ordered_quantity"`
+	UnitPrice           float64              `orm:"column(unit_price);digits(20);decimals(0)" json:"unit_price"`
+	Subtotal            float64              `orm:"column(subtotal);digits(20);decimals(0)" json:"subtotal"`
+	Note                string               `orm:"column(note);null" json:"note"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PurchaseRequisitionItem) MarshalJSON() ([]byte, error) {
+	type Alias PurchaseRequisitionItem
+
+	alias := &struct {
+		ID                    string `json:"id"`
+		PurchaseRequisitionID string `json:"purchase_requisition_id"`
+		ItemVariantID         string `json:"item_variant_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PurchaseRequisitionID when m.PurchaseRequisition not nill
+	// and the ID is setted
+	if m.PurchaseRequisition != nil && m.PurchaseRequisition.ID != int64(0) {
+		alias.PurchaseRequisitionID = common.Encrypt(m.PurchaseRequisition.ID)
+	} else {
+		alias.PurchaseRequisition = nil
+	}
+
+	// Encrypt alias.ItemVariantID when m.ItemVariant not nill
+	// and the ID is setted
+	if m.ItemVariant != nil && m.ItemVariant.ID != int64(0) {
+		alias.ItemVariantID = common.Encrypt(m.ItemVariant.ID)
+	} else {
+		alias.ItemVariant = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating PurchaseRequisitionItem struct into purchase_requisition_item table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to purchase_requisition_item.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *PurchaseRequisitionItem) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting purchase_requisition_item data
+// this also will truncated all data from all table
+// that have relation with this purchase_requisition_item.
+func (m *PurchaseRequisitionItem) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *PurchaseRequisitionItem) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -20,6 +20,7 @@ type PurchaseReturnItem struct {
 	ID                int64              `orm:"column(id);auto" json:"-"`
 	PurchaseReturn    *PurchaseReturn    `orm:"column(purchase_return_id);rel(fk)" json:"purchase_return,omitempty"`
 	PurchaseOrderItem *PurchaseOrderItem `orm:"column(purchase_order_item_id);rel(fk)" json:"purchase_order_item,omitempty"`
+	ItemVariantStock  *ItemVariantStock  `orm:"column(item_variant_stock_id);null;rel(fk)" json:"item_variant_stock,omitempty"`
 	Quantity          float32            `orm:"column(quantity)" json:"quantity"`
 	Note              string             `orm:"column(note);null" json:"note"`
 }
@@ -33,6 +34,7 @@ func (m *PurchaseReturnItem) MarshalJSON() ([]byte, error) {
 		ID                  string `json:"id"`
 		PurchaseReturnID    string `json:"purchase_return_id"`
 		PurchaseOrderItemID string `json:"purchase_order_item_id"`
+		ItemVariantStockID  string `json:"item_variant_stock_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
@@ -55,6 +57,14 @@ func (m *PurchaseReturnItem) MarshalJSON() ([]byte, error) {
 		alias.PurchaseOrderItem = nil
 	}
 
+	// Encrypt alias.ItemVariantStockID when m.ItemVariantStock not nill
+	// and the ID is setted
+	if m.ItemVariantStock != nil && m.ItemVariantStock.ID != int64(0) {
+		alias.ItemVariantStockID = common.Encrypt(m.ItemVariantStock.ID)
+	} else {
+		alias.ItemVariantStock = nil
+	}
+
 	return json.Marshal(alias)
 }
 
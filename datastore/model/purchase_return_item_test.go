@@ -22,6 +22,8 @@ func TestPurchaseReturnItem_Save(t *testing.T) {
 
 	m.PurchaseOrderItem = model.DummyPurchaseOrderItem()
 
+	m.ItemVariantStock = model.DummyItemVariantStock()
+
 	e := m.Save()
 	assert.NoError(t, e)
 	assert.NotZero(t, m.ID)
@@ -0,0 +1,124 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(RecurringOrder))
+}
+
+// RecurringOrder model for recurring_order table.
+type RecurringOrder struct {
+	ID                   int64                 `orm:"column(id);auto" json:"-"`
+	Code                 string                `orm:"column(code);size(45)" json:"code"`
+	Customer             *Partnership          `orm:"column(customer_id);rel(fk)" json:"customer,omitempty"`
+	ScheduleType         string                `orm:"column(schedule_type);options(weekly,monthly)" json:"schedule_type"`
+	ScheduleDays         string                `orm:"column(schedule_days);null;size(100)" json:"schedule_days"`
+	DayOfMonth           int8                  `orm:"column(day_of_month);null" json:"day_of_month"`
+	StartDate            time.Time             `orm:"column(start_date);type(date)" json:"start_date"`
+	EndDate              time.Time             `orm:"column(end_date);type(date);null" json:"end_date"`
+	LastRunDate          time.Time             `orm:"column(last_run_date);type(date);null" json:"last_run_date"`
+	EtaDays              int8                  `orm:"column(eta_days);null" json:"eta_days"`
+	ShipmentAddress      string                `orm:"column(shipment_address);null" json:"shipment_address"`
+	ShipmentCost         float64               `orm:"column(shipment_cost);null;digits(20);decimals(0)" json:"shipment_cost"`
+	Discount             float32               `orm:"column(discount);null" json:"discount"`
+	DiscountAmount       float64               `orm:"column(discount_amount);null;digits(20);decimals(0)" json:"discount_amount"`
+	IsPercentageDiscount int8                  `orm:"column(is_percentage_discount);null" json:"is_percentage_discount"`
+	Tax                  float32               `orm:"column(tax);null" json:"tax"`
+	Note                 string                `orm:"column(note);null" json:"note"`
+	IsActive             int8                  `orm:"column(is_active);null" json:"is_active"`
+	CreatedBy            *User                 `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	UpdatedBy            *User                 `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt            time.Time             `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt            time.Time             `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	RecurringOrderItems  []*RecurringOrderItem `orm:"reverse(many)" json:"recurring_order_items,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *RecurringOrder) MarshalJSON() ([]byte, error) {
+	type Alias RecurringOrder
+
+	alias := &struct {
+		ID          string `json:"id"`
+		CustomerID  string `json:"customer_id"`
+		CreatedByID string `json:"created_by_id"`
+		UpdatedByID string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CustomerID when m.Customer not nill
+	// and the ID is setted
+	if m.Customer != nil && m.Customer.ID != int64(0) {
+		alias.CustomerID = common.Encrypt(m.Customer.ID)
+	} else {
+		alias.Customer = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating RecurringOrder struct into recurring_order table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to recurring_order.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *RecurringOrder) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting recurring_order data
+// this also will truncated all data from all table
+// that have relation with this recurring_order.
+func (m *RecurringOrder) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *RecurringOrder) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(RecurringOrderItem))
+}
+
+// RecurringOrderItem model for recurring_order_item table.
+type RecurringOrderItem struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	RecurringOrder *RecurringOrder `orm:"column(recurring_order_id);rel(fk)" json:"recurring_order,omitempty"`
+	ItemVariant    *ItemVariant    `orm:"column(item_variant_id);rel(fk)" json:"item_variant,omitempty"`
+	PricingType    *PricingType    `orm:"column(pricing_type_id);rel(fk)" json:"pricing_type,omitempty"`
+	TaxCode        *TaxCode        `orm:"column(tax_code_id);null;rel(fk)" json:"tax_code,omitempty"`
+	Quantity       float32         `orm:"column(quantity)" json:"quantity"`
+	UnitPrice      float64         `orm:"column(unit_price);null;digits(20);decimals(0)" json:"unit_price"`
+	Discount       float32         `orm:"column(discount);null" json:"discount"`
+	Note           string          `orm:"column(note);null" json:"note"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *RecurringOrderItem) MarshalJSON() ([]byte, error) {
+	type Alias RecurringOrderItem
+
+	alias := &struct {
+		ID               string `json:"id"`
+		RecurringOrderID string `json:"recurring_order_id"`
+		ItemVariantID    string `json:"item_variant_id"`
+		PricingTypeID    string `json:"pricing_type_id"`
+		TaxCodeID        string `json:"tax_code_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.RecurringOrderID when m.RecurringOrder not nill
+	// and the ID is setted
+	if m.RecurringOrder != nil && m.RecurringOrder.ID != int64(0) {
+		alias.RecurringOrderID = common.Encrypt(m.RecurringOrder.ID)
+	} else {
+		alias.RecurringOrder = nil
+	}
+
+	// Encrypt alias.ItemVariantID when m.ItemVariant not nill
+	// and the ID is setted
+	if m.ItemVariant != nil && m.ItemVariant.ID != int64(0) {
+		alias.ItemVariantID = common.Encrypt(m.ItemVariant.ID)
+	} else {
+		alias.ItemVariant = nil
+	}
+
+	// Encrypt alias.PricingTypeID when m.PricingType not nill
+	// and the ID is setted
+	if m.PricingType != nil && m.PricingType.ID != int64(0) {
+		alias.PricingTypeID = common.Encrypt(m.PricingType.ID)
+	} else {
+		alias.PricingType = nil
+	}
+
+	// Encrypt alias.TaxCodeID when m.TaxCode not nill
+	// and the ID is setted
+	if m.TaxCode != nil && m.TaxCode.ID != int64(0) {
+		alias.TaxCodeID = common.Encrypt(m.TaxCode.ID)
+	} else {
+		alias.TaxCode = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating RecurringOrderItem struct into recurring_order_item table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to recurring_order_item.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *RecurringOrderItem) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting recurring_order_item data
+// this also will truncated all data from all table
+// that have relation with this recurring_order_item.
+func (m *RecurringOrderItem) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *RecurringOrderItem) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
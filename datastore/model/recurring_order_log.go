@@ -0,0 +1,99 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(RecurringOrderLog))
+}
+
+// RecurringOrderLog model for recurring_order_log table.
+type RecurringOrderLog struct {
+	ID             int64           `orm:"column(id);auto" json:"-"`
+	RecurringOrder *RecurringOrder `orm:"column(recurring_order_id);rel(fk)" json:"recurring_order,omitempty"`
+	RunDate        time.Time       `orm:"column(run_date);type(date)" json:"run_date"`
+	Status         string          `orm:"column(status);options(success,failed)" json:"status"`
+	SalesOrder     *SalesOrder     `orm:"column(sales_order_id);null;rel(fk)" json:"sales_order,omitempty"`
+	Message        string          `orm:"column(message);null" json:"message"`
+	CreatedAt      time.Time       `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *RecurringOrderLog) MarshalJSON() ([]byte, error) {
+	type Alias RecurringOrderLog
+
+	alias := &struct {
+		ID               string `json:"id"`
+		RecurringOrderID string `json:"recurring_order_id"`
+		SalesOrderID     string `json:"sales_order_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.RecurringOrderID when m.RecurringOrder not nill
+	// and the ID is setted
+	if m.RecurringOrder != nil && m.RecurringOrder.ID != int64(0) {
+		alias.RecurringOrderID = common.Encrypt(m.RecurringOrder.ID)
+	} else {
+		alias.RecurringOrder = nil
+	}
+
+	// Encrypt alias.SalesOrderID when m.SalesOrder not nill
+	// and the ID is setted
+	if m.SalesOrder != nil && m.SalesOrder.ID != int64(0) {
+		alias.SalesOrderID = common.Encrypt(m.SalesOrder.ID)
+	} else {
+		alias.SalesOrder = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating RecurringOrderLog struct into recurring_order_log table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to recurring_order_log.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *RecurringOrderLog) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting recurring_order_log data
+// this also will truncated all data from all table
+// that have relation with this recurring_order_log.
+func (m *RecurringOrderLog) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *RecurringOrderLog) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
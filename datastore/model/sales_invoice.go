@@ -18,23 +18,24 @@ func init() {
 
 // SalesInvoice model for sales_invoice table.
 type SalesInvoice struct {
-	ID              int64       `orm:"column(id);auto" json:"-"`
-	SalesOrder      *SalesOrder `orm:"column(sales_order_id);rel(fk)" json:"sales_order,omitempty"`
-	Code            string      `orm:"column(code);size(45)" json:"code"`
-	RecognitionDate time.Time   `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
-	DueDate         time.Time   `orm:"column(due_date);type(date);null" json:"due_date"`
-	BillingAddress  string      `orm:"column(billing_address);null" json:"billing_address"`
-	TotalAmount     float64     `orm:"column(total_amount);null;digits(20);decimals(0)" json:"total_amount"`
-	TotalPaid       float64     `orm:"column(total_paid);null;digits(20);decimals(0)" json:"total_paid"`
-	TotalRevenued   float64     `orm:"column(total_revenued);null;digits(20);decimals(0)" json:"total_revenued"`
-	Note            string      `orm:"column(note);null" json:"note"`
-	DocumentStatus  string      `orm:"column(document_status);null;options(new,active,finished)" json:"document_status"`
-	IsBundled       int8        `orm:"column(is_bundled);null" json:"is_bundled"`
-	IsDeleted       int8        `orm:"column(is_deleted);null" json:"is_deleted"`
-	CreatedBy       *User       `orm:"column(created_by);rel(fk)" json:"created_by"`
-	UpdatedBy       *User       `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
-	CreatedAt       time.Time   `orm:"column(created_at);type(timestamp);null" json:"created_at"`
-	UpdatedAt       time.Time   `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+	ID               int64       `orm:"column(id);auto" json:"-"`
+	SalesOrder       *SalesOrder `orm:"column(sales_order_id);rel(fk)" json:"sales_order,omitempty"`
+	Code             string      `orm:"column(code);size(45)" json:"code"`
+	TaxInvoiceNumber string      `orm:"column(tax_invoice_number);size(45);null" json:"tax_invoice_number"`
+	RecognitionDate  time.Time   `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
+	DueDate          time.Time   `orm:"column(due_date);type(date);null" json:"due_date"`
+	BillingAddress   string      `orm:"column(billing_address);null" json:"billing_address"`
+	TotalAmount      float64     `orm:"column(total_amount);null;digits(20);decimals(0)" json:"total_amount"`
+	TotalPaid        float64     `orm:"column(total_paid);null;digits(20);decimals(0)" json:"total_paid"`
+	TotalRevenued    float64     `orm:"column(total_revenued);null;digits(20);decimals(0)" json:"total_revenued"`
+	Note             string      `orm:"column(note);null" json:"note"`
+	DocumentStatus   string      `orm:"column(document_status);null;options(new,active,finished)" json:"document_status"`
+	IsBundled        int8        `orm:"column(is_bundled);null" json:"is_bundled"`
+	IsDeleted        int8        `orm:"column(is_deleted);null" json:"is_deleted"`
+	CreatedBy        *User       `orm:"column(created_by);rel(fk)" json:"created_by"`
+	UpdatedBy        *User       `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt        time.Time   `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt        time.Time   `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
 }
 
 // MarshalJSON customized data struct when marshaling data
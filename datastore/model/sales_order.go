@@ -21,6 +21,7 @@ type SalesOrder struct {
 	ID                   int64        `orm:"column(id);auto" json:"-"`
 	Reference            *SalesOrder  `orm:"column(reference_id);null;rel(fk)" json:"reference,omitempty"`
 	Customer             *Partnership `orm:"column(customer_id);null;rel(fk)" json:"customer,omitempty"`
+	SalesPerson          *User        `orm:"column(sales_person_id);null;rel(fk)" json:"sales_person,omitempty"`
 	Code                 string       `orm:"column(code);size(45)" json:"code"`
 	RecognitionDate      time.Time    `orm:"column(recognition_date);type(date);null" json:"recognition_date"`
 	EtaDate              time.Time    `orm:"column(eta_date);type(date);null" json:"eta_date"`
@@ -35,7 +36,7 @@ type SalesOrder struct {
 	TotalPaid            float64      `orm:"column(total_paid);null;digits(20);decimals(0)" json:"total_paid"`
 	TotalCost            float64      `orm:"column(total_cost);null;digits(20);decimals(0)" json:"total_cost"`
 	Note                 string       `orm:"column(note);null" json:"note"`
-	DocumentStatus       string       `orm:"column(document_status);null;options(new,active,finished,requested_cancel,approved_cancel)" json:"document_status"`
+	DocumentStatus       string       `orm:"column(document_status);null;options(new,active,finished,requested_cancel,approved_cancel,pending_approval)" json:"document_status"`
 	InvoiceStatus        string       `orm:"column(invoice_status);null;options(new,active,finished)" json:"invoice_status"`
 	FulfillmentStatus    string       `orm:"column(fulfillment_status);null;options(new,active,finished)" json:"fulfillment_status"`
 	ShipmentStatus       string       `orm:"column(shipment_status);null;options(new,active,finished)" json:"shipment_status"`
@@ -77,6 +78,7 @@ func (m *SalesOrder) MarshalJSON() ([]byte, error) {
 		ApproveCancelByID string `json:"approve_cancel_by_id"`
 		ReferenceID       string `json:"reference_id"`
 		CustomerID        string `json:"customer_id"`
+		SalesPersonID     string `json:"sales_person_id"`
 		BankAccountID     string `json:"bank_account_id"`
 		CreatedByID       string `json:"created_by_id"`
 		*Alias
@@ -101,6 +103,14 @@ func (m *SalesOrder) MarshalJSON() ([]byte, error) {
 		alias.Customer = nil
 	}
 
+	// Encrypt alias.SalesPersonID when m.SalesPerson not nill
+	// and the ID is setted
+	if m.SalesPerson != nil && m.SalesPerson.ID != int64(0) {
+		alias.SalesPersonID = common.Encrypt(m.SalesPerson.ID)
+	} else {
+		alias.SalesPerson = nil
+	}
+
 	// Encrypt alias.CreatedByID when m.CreatedBy not nill
 	// and the ID is setted
 	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
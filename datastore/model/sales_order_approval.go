@@ -0,0 +1,114 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(SalesOrderApproval))
+}
+
+// SalesOrderApproval model for sales_order_approval table.
+type SalesOrderApproval struct {
+	ID            int64       `orm:"column(id);auto" json:"-"`
+	SalesOrder    *SalesOrder `orm:"column(sales_order_id);rel(fk)" json:"sales_order,omitempty"`
+	Reason        string      `orm:"column(reason)" json:"reason"`
+	LineDiscount  float32     `orm:"column(line_discount);null" json:"line_discount"`
+	OrderDiscount float32     `orm:"column(order_discount);null" json:"order_discount"`
+	Margin        float32     `orm:"column(margin);null" json:"margin"`
+	TotalCost     float64     `orm:"column(total_cost);null;digits(20);decimals(0)" json:"total_cost"`
+	Status        string      `orm:"column(status);options(pending,approved,rejected)" json:"status"`
+	Note          string      `orm:"column(note);null" json:"note"`
+	RequestedBy   *User       `orm:"column(requested_by);rel(fk)" json:"requested_by"`
+	RequestedAt   time.Time   `orm:"column(requested_at);type(timestamp);null" json:"requested_at"`
+	DecidedBy     *User       `orm:"column(decided_by);null;rel(fk)" json:"decided_by"`
+	DecidedAt     time.Time   `orm:"column(decided_at);type(timestamp);null" json:"decided_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *SalesOrderApproval) MarshalJSON() ([]byte, error) {
+	type Alias SalesOrderApproval
+
+	alias := &struct {
+		ID            string `json:"id"`
+		SalesOrderID  string `json:"sales_order_id"`
+		RequestedByID string `json:"requested_by_id"`
+		DecidedByID   string `json:"decided_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.SalesOrderID when m.SalesOrder not nill
+	// and the ID is setted
+	if m.SalesOrder != nil && m.SalesOrder.ID != int64(0) {
+		alias.SalesOrderID = common.Encrypt(m.SalesOrder.ID)
+	} else {
+		alias.SalesOrder = nil
+	}
+
+	// Encrypt alias.RequestedByID when m.RequestedBy not nill
+	// and the ID is setted
+	if m.RequestedBy != nil && m.RequestedBy.ID != int64(0) {
+		alias.RequestedByID = common.Encrypt(m.RequestedBy.ID)
+	} else {
+		alias.RequestedBy = nil
+	}
+
+	// Encrypt alias.DecidedByID when m.DecidedBy not nill
+	// and the ID is setted
+	if m.DecidedBy != nil && m.DecidedBy.ID != int64(0) {
+		alias.DecidedByID = common.Encrypt(m.DecidedBy.ID)
+	} else {
+		alias.DecidedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating SalesOrderApproval struct into sales_order_approval table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to sales_order_approval.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *SalesOrderApproval) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting sales_order_approval data
+// this also will truncated all data from all table
+// that have relation with this sales_order_approval.
+func (m *SalesOrderApproval) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *SalesOrderApproval) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
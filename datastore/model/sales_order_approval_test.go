@@ -0,0 +1,76 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSalesOrderApproval_Save(t *testing.T) {
+	var m model.SalesOrderApproval
+	faker.Fill(&m, "ID")
+
+	m.SalesOrder = model.DummySalesOrder()
+
+	m.RequestedBy = model.DummyUser()
+
+	m.DecidedBy = model.DummyUser()
+
+	m.Status = "pending"
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestSalesOrderApproval_Delete(t *testing.T) {
+	m := model.DummySalesOrderApproval()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.SalesOrderApproval)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.SalesOrderApproval)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestSalesOrderApproval_Read(t *testing.T) {
+	var m model.SalesOrderApproval
+
+	mn := model.DummySalesOrderApproval()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestSalesOrderApproval_MarshalJSON(t *testing.T) {
+	mn := model.DummySalesOrderApproval()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
@@ -25,6 +25,9 @@ type SalesOrderItem struct {
 	QuantityPrepare     float32              `orm:"-" json:"quantity_prepare"`
 	UnitPrice           float64              `orm:"column(unit_price);null;digits(20);decimals(0)" json:"unit_price"`
 	Discount            float32              `orm:"column(discount);null" json:"discount"`
+	TaxCode             *TaxCode             `orm:"column(tax_code_id);null;rel(fk)" json:"tax_code,omitempty"`
+	TaxBase             float64              `orm:"column(tax_base);null;digits(20);decimals(0)" json:"tax_base"`
+	TaxAmount           float64              `orm:"column(tax_amount);null;digits(20);decimals(0)" json:"tax_amount"`
 	Subtotal            float64              `orm:"column(subtotal);digits(20);decimals(0)" json:"subtotal"`
 	Note                string               `orm:"column(note);null" json:"note"`
 	CanBeReturn         float32              `orm:"-" json:"can_be_return, omitempty"`
@@ -32,6 +35,16 @@ type SalesOrderItem struct {
 	Prefix              float64              `orm:"-" json:"prefix,omitempty"`
 }
 
+// LineTax tax code dan subtotal item untuk perhitungan ppn per baris.
+func (m *SalesOrderItem) LineTax() (*TaxCode, float64) {
+	return m.TaxCode, m.Subtotal
+}
+
+// SetLineTax menyimpan dpp dan ppn hasil perhitungan ppn per baris.
+func (m *SalesOrderItem) SetLineTax(base float64, tax float64) {
+	m.TaxBase, m.TaxAmount = base, tax
+}
+
 // MarshalJSON customized data struct when marshaling data
 // into JSON format, all Primary key & Foreign key will be encrypted.
 func (m *SalesOrderItem) MarshalJSON() ([]byte, error) {
@@ -41,6 +54,7 @@ func (m *SalesOrderItem) MarshalJSON() ([]byte, error) {
 		ID            string `json:"id"`
 		SalesOrderID  string `json:"sales_order_id"`
 		ItemVariantID string `json:"item_variant_id"`
+		TaxCodeID     string `json:"tax_code_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
@@ -63,6 +77,14 @@ func (m *SalesOrderItem) MarshalJSON() ([]byte, error) {
 		alias.ItemVariant = nil
 	}
 
+	// Encrypt alias.TaxCodeID when m.TaxCode not nill
+	// and the ID is setted
+	if m.TaxCode != nil && m.TaxCode.ID != int64(0) {
+		alias.TaxCodeID = common.Encrypt(m.TaxCode.ID)
+	} else {
+		alias.TaxCode = nil
+	}
+
 	if m.ItemVariantStockLog != nil {
 		m.Prefix = m.Subtotal - m.ItemVariantStockLog.ItemVariantStock.UnitCost
 	}
@@ -0,0 +1,101 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(SalesOrderRevision))
+}
+
+// SalesOrderRevision model for sales_order_revision table.
+type SalesOrderRevision struct {
+	ID         int64       `orm:"column(id);auto" json:"-"`
+	SalesOrder *SalesOrder `orm:"column(sales_order_id);rel(fk)" json:"sales_order,omitempty"`
+	Revision   int         `orm:"column(revision)" json:"revision"`
+	Snapshot   string      `orm:"column(snapshot);type(text)" json:"-"`
+	Note       string      `orm:"column(note);null" json:"note"`
+	CreatedBy  *User       `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	CreatedAt  time.Time   `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	Data       interface{} `orm:"-" json:"data,omitempty"`
+	Changes    interface{} `orm:"-" json:"changes,omitempty"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *SalesOrderRevision) MarshalJSON() ([]byte, error) {
+	type Alias SalesOrderRevision
+
+	alias := &struct {
+		ID           string `json:"id"`
+		SalesOrderID string `json:"sales_order_id"`
+		CreatedByID  string `json:"created_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.SalesOrderID when m.SalesOrder not nill
+	// and the ID is setted
+	if m.SalesOrder != nil && m.SalesOrder.ID != int64(0) {
+		alias.SalesOrderID = common.Encrypt(m.SalesOrder.ID)
+	} else {
+		alias.SalesOrder = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating SalesOrderRevision struct into sales_order_revision table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to sales_order_revision.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *SalesOrderRevision) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting sales_order_revision data
+// this also will truncated all data from all table
+// that have relation with this sales_order_revision.
+func (m *SalesOrderRevision) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *SalesOrderRevision) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
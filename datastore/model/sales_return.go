@@ -26,6 +26,10 @@ type SalesReturn struct {
 	Note             string             `orm:"column(note);null" json:"note"`
 	DocumentStatus   string             `orm:"column(document_status);null;options(new,active,finished,cancelled)" json:"document_status"`
 	IsBundled        int8               `orm:"column(is_bundled);null" json:"is_bundled"`
+	IsRestocked      int8               `orm:"column(is_restocked)" json:"is_restocked"`
+	TotalWriteOff    float64            `orm:"column(total_write_off);digits(20);decimals(0)" json:"total_write_off"`
+	RestockedBy      *User              `orm:"column(restocked_by);null;rel(fk)" json:"restocked_by"`
+	RestockedAt      time.Time          `orm:"column(restocked_at);type(timestamp);null" json:"restocked_at"`
 	IsDeleted        int8               `orm:"column(is_deleted);null" json:"is_deleted"`
 	CreatedBy        *User              `orm:"column(created_by);rel(fk)" json:"created_by"`
 	UpdatedBy        *User              `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
@@ -40,10 +44,11 @@ func (m *SalesReturn) MarshalJSON() ([]byte, error) {
 	type Alias SalesReturn
 
 	alias := &struct {
-		ID           string `json:"id"`
-		SalesOrderID string `json:"sales_order_id"`
-		CreatedByID  string `json:"created_by_id"`
-		UpdatedByID  string `json:"updated_by_id"`
+		ID            string `json:"id"`
+		SalesOrderID  string `json:"sales_order_id"`
+		CreatedByID   string `json:"created_by_id"`
+		UpdatedByID   string `json:"updated_by_id"`
+		RestockedByID string `json:"restocked_by_id"`
 		*Alias
 	}{
 		ID:    common.Encrypt(m.ID),
@@ -74,6 +79,14 @@ func (m *SalesReturn) MarshalJSON() ([]byte, error) {
 		alias.UpdatedBy = nil
 	}
 
+	// Encrypt alias.RestockedByID when m.RestockedBy not nill
+	// and the ID is setted
+	if m.RestockedBy != nil && m.RestockedBy.ID != int64(0) {
+		alias.RestockedByID = common.Encrypt(m.RestockedBy.ID)
+	} else {
+		alias.RestockedBy = nil
+	}
+
 	return json.Marshal(alias)
 }
 
@@ -21,6 +21,8 @@ type SalesReturnItem struct {
 	SalesReturn    *SalesReturn    `orm:"column(sales_return_id);rel(fk)" json:"sales_return,omitempty"`
 	SalesOrderItem *SalesOrderItem `orm:"column(sales_order_item_id);rel(fk)" json:"sales_order_item,omitempty"`
 	Quantity       float32         `orm:"column(quantity)" json:"quantity"`
+	Condition      string          `orm:"column(condition);options(ungraded,resellable,damaged,scrap)" json:"condition"`
+	UnitCost       float64         `orm:"column(unit_cost);digits(20);decimals(0)" json:"unit_cost"`
 	Note           string          `orm:"column(note);null" json:"note"`
 }
 
@@ -0,0 +1,121 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(SupplierCatalogue))
+}
+
+// SupplierCatalogue model for supplier_catalogue table.
+type SupplierCatalogue struct {
+	ID               int64        `orm:"column(id);auto" json:"-"`
+	Partnership      *Partnership `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	ItemVariant      *ItemVariant `orm:"column(item_variant_id);rel(fk)" json:"item_variant,omitempty"`
+	SupplierItemCode string       `orm:"column(supplier_item_code);size(45)" json:"supplier_item_code"`
+	SupplierItemName string       `orm:"column(supplier_item_name);size(200);null" json:"supplier_item_name"`
+	Note             string       `orm:"column(note);null" json:"note"`
+	IsDeleted        int8         `orm:"column(is_deleted);null" json:"is_deleted"`
+	CreatedBy        *User        `orm:"column(created_by);rel(fk)" json:"created_by"`
+	UpdatedBy        *User        `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt        time.Time    `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt        time.Time    `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *SupplierCatalogue) MarshalJSON() ([]byte, error) {
+	type Alias SupplierCatalogue
+
+	alias := &struct {
+		ID            string `json:"id"`
+		PartnershipID string `json:"partnership_id"`
+		ItemVariantID string `json:"item_variant_id"`
+		CreatedByID   string `json:"created_by_id"`
+		UpdatedByID   string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	// Encrypt alias.ItemVariantID when m.ItemVariant not nill
+	// and the ID is setted
+	if m.ItemVariant != nil && m.ItemVariant.ID != int64(0) {
+		alias.ItemVariantID = common.Encrypt(m.ItemVariant.ID)
+	} else {
+		alias.ItemVariant = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating SupplierCatalogue struct into supplier_catalogue table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to supplier_catalogue.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *SupplierCatalogue) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting supplier_catalogue data
+// this also will truncated all data from all table
+// that have relation with this supplier_catalogue.
+func (m *SupplierCatalogue) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *SupplierCatalogue) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(TaxCode))
+}
+
+// TaxCode model for tax_code table.
+type TaxCode struct {
+	ID          int64     `orm:"column(id);auto" json:"-"`
+	Code        string    `orm:"column(code);size(20)" json:"code"`
+	Name        string    `orm:"column(name);size(45)" json:"name"`
+	Rate        float64   `orm:"column(rate);digits(5);decimals(2)" json:"rate"`
+	IsInclusive int8      `orm:"column(is_inclusive);null" json:"is_inclusive"`
+	IsExempt    int8      `orm:"column(is_exempt);null" json:"is_exempt"`
+	IsArchived  int8      `orm:"column(is_archived);null" json:"is_archived"`
+	Note        string    `orm:"column(note);null" json:"note"`
+	CreatedBy   *User     `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	UpdatedBy   *User     `orm:"column(updated_by);null;rel(fk)" json:"updated_by"`
+	CreatedAt   time.Time `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+	UpdatedAt   time.Time `orm:"column(updated_at);type(timestamp);null" json:"updated_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *TaxCode) MarshalJSON() ([]byte, error) {
+	type Alias TaxCode
+
+	alias := &struct {
+		ID          string `json:"id"`
+		CreatedByID string `json:"created_by_id"`
+		UpdatedByID string `json:"updated_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	// Encrypt alias.UpdatedByID when m.UpdatedBy not nill
+	// and the ID is setted
+	if m.UpdatedBy != nil && m.UpdatedBy.ID != int64(0) {
+		alias.UpdatedByID = common.Encrypt(m.UpdatedBy.ID)
+	} else {
+		alias.UpdatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating TaxCode struct into tax_code table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to tax_code.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *TaxCode) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting tax_code data
+// this also will truncated all data from all table
+// that have relation with this tax_code.
+func (m *TaxCode) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *TaxCode) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/faker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxCode_Save(t *testing.T) {
+	var m model.TaxCode
+	faker.Fill(&m, "ID")
+
+	m.CreatedBy = model.DummyUser()
+
+	m.Code = common.RandomStr(10)
+	m.Rate = 11
+
+	e := m.Save()
+	assert.NoError(t, e)
+	assert.NotZero(t, m.ID)
+
+	mn := m
+	faker.Fill(&mn, "ID")
+	mn.Code = common.RandomStr(10)
+	mn.Rate = 10
+	e = mn.Save()
+	assert.NoError(t, e)
+
+	mn.ID = 999999
+	e = mn.Save()
+	assert.NoError(t, e)
+}
+
+func TestTaxCode_Delete(t *testing.T) {
+	m := model.DummyTaxCode()
+
+	e := m.Delete()
+	assert.NoError(t, e)
+	assert.Zero(t, m.ID)
+
+	mn := new(model.TaxCode)
+	mn.ID = 90000
+	e = mn.Delete()
+	assert.Error(t, e)
+
+	mn = new(model.TaxCode)
+	e = mn.Delete()
+	assert.Error(t, e)
+}
+
+func TestTaxCode_Read(t *testing.T) {
+	var m model.TaxCode
+
+	mn := model.DummyTaxCode()
+	m.ID = mn.ID
+	m.Read()
+
+	assert.NotZero(t, m.ID)
+}
+
+func TestTaxCode_MarshalJSON(t *testing.T) {
+	mn := model.DummyTaxCode()
+
+	j, e := mn.MarshalJSON()
+	assert.NoError(t, e)
+	assert.Contains(t, string(j), common.Encrypt(mn.ID))
+}
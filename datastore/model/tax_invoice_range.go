@@ -0,0 +1,92 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(TaxInvoiceRange))
+}
+
+// TaxInvoiceRange model for tax_invoice_range table.
+type TaxInvoiceRange struct {
+	ID          int64     `orm:"column(id);auto" json:"-"`
+	CodePrefix  string    `orm:"column(code_prefix);size(20)" json:"code_prefix"`
+	StartNumber int64     `orm:"column(start_number)" json:"start_number"`
+	EndNumber   int64     `orm:"column(end_number)" json:"end_number"`
+	LastNumber  int64     `orm:"column(last_number)" json:"last_number"`
+	IsActive    int8      `orm:"column(is_active);null" json:"is_active"`
+	Note        string    `orm:"column(note);null" json:"note"`
+	CreatedBy   *User     `orm:"column(created_by);null;rel(fk)" json:"created_by"`
+	CreatedAt   time.Time `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *TaxInvoiceRange) MarshalJSON() ([]byte, error) {
+	type Alias TaxInvoiceRange
+
+	alias := &struct {
+		ID          string `json:"id"`
+		CreatedByID string `json:"created_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating TaxInvoiceRange struct into tax_invoice_range table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to tax_invoice_range.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *TaxInvoiceRange) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting tax_invoice_range data
+// this also will truncated all data from all table
+// that have relation with this tax_invoice_range.
+func (m *TaxInvoiceRange) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *TaxInvoiceRange) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+func init() {
+	orm.RegisterModel(new(VisitLog))
+}
+
+// VisitLog model for visit_log table.
+type VisitLog struct {
+	ID              int64        `orm:"column(id);auto" json:"-"`
+	Partnership     *Partnership `orm:"column(partnership_id);rel(fk)" json:"partnership,omitempty"`
+	SalesPerson     *User        `orm:"column(sales_person_id);rel(fk)" json:"sales_person,omitempty"`
+	SalesOrder      *SalesOrder  `orm:"column(sales_order_id);null;rel(fk)" json:"sales_order,omitempty"`
+	VisitDate       time.Time    `orm:"column(visit_date);type(date)" json:"visit_date"`
+	Outcome         string       `orm:"column(outcome);options(order_taken,no_order,payment_collected)" json:"outcome"`
+	AmountCollected float64      `orm:"column(amount_collected);null;digits(20);decimals(0)" json:"amount_collected"`
+	Note            string       `orm:"column(note);null" json:"note"`
+	CreatedBy       *User        `orm:"column(created_by);rel(fk)" json:"created_by"`
+	CreatedAt       time.Time    `orm:"column(created_at);type(timestamp);null" json:"created_at"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *VisitLog) MarshalJSON() ([]byte, error) {
+	type Alias VisitLog
+
+	alias := &struct {
+		ID            string `json:"id"`
+		PartnershipID string `json:"partnership_id"`
+		SalesPersonID string `json:"sales_person_id"`
+		SalesOrderID  string `json:"sales_order_id"`
+		CreatedByID   string `json:"created_by_id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	}
+
+	// Encrypt alias.PartnershipID when m.Partnership not nill
+	// and the ID is setted
+	if m.Partnership != nil && m.Partnership.ID != int64(0) {
+		alias.PartnershipID = common.Encrypt(m.Partnership.ID)
+	} else {
+		alias.Partnership = nil
+	}
+
+	// Encrypt alias.SalesPersonID when m.SalesPerson not nill
+	// and the ID is setted
+	if m.SalesPerson != nil && m.SalesPerson.ID != int64(0) {
+		alias.SalesPersonID = common.Encrypt(m.SalesPerson.ID)
+	} else {
+		alias.SalesPerson = nil
+	}
+
+	// Encrypt alias.SalesOrderID when m.SalesOrder not nill
+	// and the ID is setted
+	if m.SalesOrder != nil && m.SalesOrder.ID != int64(0) {
+		alias.SalesOrderID = common.Encrypt(m.SalesOrder.ID)
+	} else {
+		alias.SalesOrder = nil
+	}
+
+	// Encrypt alias.CreatedByID when m.CreatedBy not nill
+	// and the ID is setted
+	if m.CreatedBy != nil && m.CreatedBy.ID != int64(0) {
+		alias.CreatedByID = common.Encrypt(m.CreatedBy.ID)
+	} else {
+		alias.CreatedBy = nil
+	}
+
+	return json.Marshal(alias)
+}
+
+// Save inserting or updating VisitLog struct into visit_log table.
+// It will updating if this struct has valid Id
+// if not, will inserting a new row to visit_log.
+// The field parameter is an field that will be saved, it is
+// usefull for partial updating data.
+func (m *VisitLog) Save(fields ...string) (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		_, err = o.Update(m, fields...)
+	} else {
+		m.ID, err = o.Insert(m)
+	}
+	return
+}
+
+// Delete permanently deleting visit_log data
+// this also will truncated all data from all table
+// that have relation with this visit_log.
+func (m *VisitLog) Delete() (err error) {
+	o := orm.NewOrm()
+	if m.ID > 0 {
+		var i int64
+		if i, err = o.Delete(m); i == 0 && err == nil {
+			err = orm.ErrNoAffected
+		}
+		return
+	}
+	return orm.ErrNoRows
+}
+
+// Read execute select based on data struct that already
+// assigned.
+func (m *VisitLog) Read(fields ...string) error {
+	o := orm.NewOrm()
+	return o.Read(m, fields...)
+}
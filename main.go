@@ -6,7 +6,11 @@ package main
 
 import (
 	"git.qasico.com/mj/api/engine"
+	"git.qasico.com/mj/api/src/dunning"
 	"git.qasico.com/mj/api/src/finance_revenue"
+	"git.qasico.com/mj/api/src/general_ledger"
+	"git.qasico.com/mj/api/src/operating_expense"
+	"git.qasico.com/mj/api/src/recurring_order"
 
 	"git.qasico.com/cuxs/common/log"
 	"git.qasico.com/cuxs/cuxs"
@@ -41,5 +45,25 @@ func cronTask() {
 		financeRevenue.Cron()
 	})
 
+	// run dunning reminder for overdue sales invoice daily
+	c.AddFunc("0 0 7 * * *", func() {
+		dunning.Cron()
+	})
+
+	// run recurring sales order daily
+	c.AddFunc("0 0 6 * * *", func() {
+		recurringOrder.Cron()
+	})
+
+	// run recurring operating expense daily
+	c.AddFunc("0 30 6 * * *", func() {
+		operatingExpense.Cron()
+	})
+
+	// run posting journal for final document that is not posted yet hourly
+	c.AddFunc("0 0 * * * *", func() {
+		generalLedger.Cron()
+	})
+
 	c.Start()
 }
@@ -0,0 +1,9 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `dunning_log`;
+
+ALTER TABLE `partnership`
+DROP COLUMN `is_dunning_paused`;
+
+DELETE FROM `application_setting` WHERE `id` IN (17, 18, 19);
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 501 AND 506;
+DELETE FROM `application_module` WHERE `id` IN (162, 163, 164);
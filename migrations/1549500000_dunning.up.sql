@@ -0,0 +1,45 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `partnership`
+ADD COLUMN `is_dunning_paused` TINYINT(1) NULL DEFAULT '0' AFTER `is_default`;
+
+CREATE TABLE IF NOT EXISTS `dunning_log` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `sales_invoice_id` BIGINT(20) UNSIGNED NOT NULL,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `interval_day` INT(11) NOT NULL DEFAULT '0',
+  `days_overdue` INT(11) NOT NULL DEFAULT '0',
+  `email` VARCHAR(45) NULL DEFAULT NULL,
+  `subject` VARCHAR(255) NULL DEFAULT NULL,
+  `outstanding` DECIMAL(20,0) NULL DEFAULT '0',
+  `status` ENUM('sent', 'failed') NOT NULL DEFAULT 'sent',
+  `note` TEXT NULL DEFAULT NULL,
+  `sent_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_dunning_log_1_idx` (`sales_invoice_id` ASC),
+  INDEX `fk_dunning_log_2_idx` (`partnership_id` ASC),
+  CONSTRAINT `fk_dunning_log_1`
+    FOREIGN KEY (`sales_invoice_id`)
+    REFERENCES `sales_invoice` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_dunning_log_2`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (17,'dunning_interval','3,7,14');
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (18,'dunning_subject','Pengingat Pembayaran {{.InvoiceCode}}');
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (19,'dunning_template','<p>Yth. {{.CustomerName}},</p><p>Tagihan {{.InvoiceCode}} dengan jatuh tempo {{.DueDate}} telah melewati {{.DaysOverdue}} hari. Sisa tagihan sebesar Rp {{.Outstanding}} dari total Rp {{.TotalAmount}}.</p><p>Mohon segera melakukan pembayaran.</p><p>Terima kasih,<br/>{{.CompanyName}}</p>');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('162','6', 'Dunning', 'finance_dunning', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('163','162', 'Read Dunning Log', 'dunning_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('164','162', 'Pause Dunning', 'dunning_pause', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('501','162', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('502','163', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('503','164', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('504','162', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('505','163', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('506','164', '2');
@@ -0,0 +1,31 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `sales_order_item`
+DROP FOREIGN KEY `fk_sales_order_item_3`;
+ALTER TABLE `sales_order_item`
+DROP INDEX `fk_sales_order_item_3_idx`,
+DROP COLUMN `tax_code_id`,
+DROP COLUMN `tax_base`,
+DROP COLUMN `tax_amount`;
+
+ALTER TABLE `purchase_order_item`
+DROP FOREIGN KEY `fk_purchase_order_item_3`;
+ALTER TABLE `purchase_order_item`
+DROP INDEX `fk_purchase_order_item_3_idx`,
+DROP COLUMN `tax_code_id`,
+DROP COLUMN `tax_base`,
+DROP COLUMN `tax_amount`;
+
+ALTER TABLE `sales_invoice`
+DROP COLUMN `tax_invoice_number`;
+
+ALTER TABLE `purchase_invoice`
+DROP COLUMN `tax_invoice_number`;
+
+ALTER TABLE `partnership`
+DROP COLUMN `npwp`;
+
+DROP TABLE IF EXISTS `tax_invoice_range`;
+DROP TABLE IF EXISTS `tax_code`;
+
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 507 AND 517;
+DELETE FROM `application_module` WHERE `id` BETWEEN 165 AND 169;
@@ -0,0 +1,101 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `tax_code` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(20) NOT NULL,
+  `name` VARCHAR(45) NOT NULL,
+  `rate` DECIMAL(5,2) NOT NULL DEFAULT '0',
+  `is_inclusive` TINYINT(1) NULL DEFAULT '0',
+  `is_exempt` TINYINT(1) NULL DEFAULT '0',
+  `is_archived` TINYINT(1) NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_tax_code_1_idx` (`created_by` ASC),
+  INDEX `fk_tax_code_2_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_tax_code_1`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_tax_code_2`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `tax_invoice_range` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code_prefix` VARCHAR(20) NOT NULL,
+  `start_number` BIGINT(20) UNSIGNED NOT NULL,
+  `end_number` BIGINT(20) UNSIGNED NOT NULL,
+  `last_number` BIGINT(20) UNSIGNED NOT NULL DEFAULT '0',
+  `is_active` TINYINT(1) NULL DEFAULT '1',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_tax_invoice_range_1_idx` (`created_by` ASC),
+  CONSTRAINT `fk_tax_invoice_range_1`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `sales_order_item`
+ADD COLUMN `tax_code_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `discount`,
+ADD COLUMN `tax_base` DECIMAL(20,0) NULL DEFAULT '0' AFTER `tax_code_id`,
+ADD COLUMN `tax_amount` DECIMAL(20,0) NULL DEFAULT '0' AFTER `tax_base`,
+ADD INDEX `fk_sales_order_item_3_idx` (`tax_code_id` ASC),
+ADD CONSTRAINT `fk_sales_order_item_3`
+  FOREIGN KEY (`tax_code_id`)
+  REFERENCES `tax_code` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+ALTER TABLE `purchase_order_item`
+ADD COLUMN `tax_code_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `discount`,
+ADD COLUMN `tax_base` DECIMAL(20,0) NULL DEFAULT '0' AFTER `tax_code_id`,
+ADD COLUMN `tax_amount` DECIMAL(20,0) NULL DEFAULT '0' AFTER `tax_base`,
+ADD INDEX `fk_purchase_order_item_3_idx` (`tax_code_id` ASC),
+ADD CONSTRAINT `fk_purchase_order_item_3`
+  FOREIGN KEY (`tax_code_id`)
+  REFERENCES `tax_code` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+ALTER TABLE `sales_invoice`
+ADD COLUMN `tax_invoice_number` VARCHAR(45) NULL DEFAULT NULL AFTER `code`;
+
+ALTER TABLE `purchase_invoice`
+ADD COLUMN `tax_invoice_number` VARCHAR(45) NULL DEFAULT NULL AFTER `code`;
+
+ALTER TABLE `partnership`
+ADD COLUMN `npwp` VARCHAR(20) NULL DEFAULT NULL AFTER `visit_day`;
+
+INSERT INTO `tax_code` (`id`, `code`, `name`, `rate`, `is_inclusive`, `is_exempt`, `is_archived`) VALUES (1, 'PPN11', 'PPN 11%', '11', '0', '0', '0');
+INSERT INTO `tax_code` (`id`, `code`, `name`, `rate`, `is_inclusive`, `is_exempt`, `is_archived`) VALUES (2, 'PPN11-INC', 'PPN 11% (Harga Termasuk PPN)', '11', '1', '0', '0');
+INSERT INTO `tax_code` (`id`, `code`, `name`, `rate`, `is_inclusive`, `is_exempt`, `is_archived`) VALUES (3, 'EXEMPT', 'Bebas PPN', '0', '0', '1', '0');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('165','6', 'Tax', 'finance_tax', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('166','165', 'Read Tax', 'tax_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('167','165', 'Manage Tax', 'tax_manage', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('168','165', 'Allocate Tax Invoice', 'tax_invoice_allocate', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('169','165', 'Export e-Faktur', 'tax_export', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('507','165', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('508','166', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('509','167', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('510','168', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('511','169', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('512','165', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('513','166', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('514','167', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('515','168', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('516','169', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('517','166', '4');
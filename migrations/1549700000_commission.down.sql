@@ -0,0 +1,18 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `commission_statement_item`;
+DROP TABLE IF EXISTS `commission_statement`;
+DROP TABLE IF EXISTS `commission_rule_tier`;
+DROP TABLE IF EXISTS `commission_rule`;
+
+ALTER TABLE `sales_order`
+DROP FOREIGN KEY `fk_sales_order_8`,
+DROP INDEX `fk_sales_order_8_idx`,
+DROP COLUMN `sales_person_id`;
+
+ALTER TABLE `partnership`
+DROP FOREIGN KEY `fk_partnership_3`,
+DROP INDEX `fk_partnership_3_idx`,
+DROP COLUMN `sales_person_id`;
+
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 518 AND 525;
+DELETE FROM `application_module` WHERE `id` IN (170, 171, 172, 173);
@@ -0,0 +1,151 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `partnership`
+ADD COLUMN `sales_person_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `bank_holder`,
+ADD INDEX `fk_partnership_3_idx` (`sales_person_id` ASC),
+ADD CONSTRAINT `fk_partnership_3`
+  FOREIGN KEY (`sales_person_id`)
+  REFERENCES `user` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+UPDATE `partnership` p
+INNER JOIN (
+  SELECT ps.id AS partnership_id, MIN(u.id) AS user_id
+  FROM `partnership` ps
+  INNER JOIN `user` u ON u.full_name = ps.sales_person OR u.username = ps.sales_person
+  WHERE ps.sales_person IS NOT NULL AND ps.sales_person != ''
+  GROUP BY ps.id
+  HAVING COUNT(DISTINCT u.id) = 1
+) m ON m.partnership_id = p.id
+SET p.sales_person_id = m.user_id;
+
+ALTER TABLE `sales_order`
+ADD COLUMN `sales_person_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `customer_id`,
+ADD INDEX `fk_sales_order_8_idx` (`sales_person_id` ASC),
+ADD CONSTRAINT `fk_sales_order_8`
+  FOREIGN KEY (`sales_person_id`)
+  REFERENCES `user` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+UPDATE `sales_order` so
+INNER JOIN `partnership` p ON p.id = so.customer_id
+SET so.sales_person_id = p.sales_person_id
+WHERE p.sales_person_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS `commission_rule` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(45) NOT NULL,
+  `basis` ENUM('revenue', 'margin') NOT NULL DEFAULT 'revenue',
+  `sales_person_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `item_category_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `is_active` TINYINT(1) NULL DEFAULT '1',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_commission_rule_1_idx` (`sales_person_id` ASC),
+  INDEX `fk_commission_rule_2_idx` (`item_category_id` ASC),
+  CONSTRAINT `fk_commission_rule_1`
+    FOREIGN KEY (`sales_person_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_commission_rule_2`
+    FOREIGN KEY (`item_category_id`)
+    REFERENCES `item_category` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `commission_rule_tier` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `commission_rule_id` BIGINT(20) UNSIGNED NOT NULL,
+  `min_target` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `rate` DECIMAL(5,2) NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  INDEX `fk_commission_rule_tier_1_idx` (`commission_rule_id` ASC),
+  CONSTRAINT `fk_commission_rule_tier_1`
+    FOREIGN KEY (`commission_rule_id`)
+    REFERENCES `commission_rule` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `commission_statement` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `sales_person_id` BIGINT(20) UNSIGNED NOT NULL,
+  `period` DATE NOT NULL,
+  `total_revenue` DECIMAL(20,0) NULL DEFAULT '0',
+  `total_margin` DECIMAL(20,0) NULL DEFAULT '0',
+  `total_return` DECIMAL(20,0) NULL DEFAULT '0',
+  `total_commission` DECIMAL(20,0) NULL DEFAULT '0',
+  `document_status` ENUM('new', 'approved') NOT NULL DEFAULT 'new',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `approved_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `approved_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `commission_statement_period_unique` (`sales_person_id` ASC, `period` ASC),
+  INDEX `fk_commission_statement_2_idx` (`created_by` ASC),
+  INDEX `fk_commission_statement_3_idx` (`approved_by` ASC),
+  CONSTRAINT `fk_commission_statement_1`
+    FOREIGN KEY (`sales_person_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_commission_statement_2`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_commission_statement_3`
+    FOREIGN KEY (`approved_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `commission_statement_item` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `commission_statement_id` BIGINT(20) UNSIGNED NOT NULL,
+  `commission_rule_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `ref_id` BIGINT(20) UNSIGNED NOT NULL,
+  `ref_type` ENUM('sales_invoice', 'sales_return') NOT NULL,
+  `ref_code` VARCHAR(45) NULL DEFAULT NULL,
+  `revenue` DECIMAL(20,0) NULL DEFAULT '0',
+  `cost` DECIMAL(20,0) NULL DEFAULT '0',
+  `basis_amount` DECIMAL(20,0) NULL DEFAULT '0',
+  `rate` DECIMAL(5,2) NULL DEFAULT '0',
+  `commission_amount` DECIMAL(20,0) NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  INDEX `fk_commission_statement_item_1_idx` (`commission_statement_id` ASC),
+  INDEX `fk_commission_statement_item_2_idx` (`commission_rule_id` ASC),
+  CONSTRAINT `fk_commission_statement_item_1`
+    FOREIGN KEY (`commission_statement_id`)
+    REFERENCES `commission_statement` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_commission_statement_item_2`
+    FOREIGN KEY (`commission_rule_id`)
+    REFERENCES `commission_rule` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('170','2', 'Sales Commission', 'sales_commission', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('171','170', 'Read Commission', 'commission_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('172','170', 'Manage Commission Rule', 'commission_manage', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('173','170', 'Generate Commission Statement', 'commission_generate', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('518','170', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('519','171', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('520','172', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('521','173', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('522','170', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('523','171', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('524','172', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('525','173', '2');
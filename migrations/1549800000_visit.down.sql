@@ -0,0 +1,5 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `visit_log`;
+
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 526 AND 533;
+DELETE FROM `application_module` WHERE `id` IN (174, 175, 176, 177);
@@ -0,0 +1,53 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `visit_log` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `sales_person_id` BIGINT(20) UNSIGNED NOT NULL,
+  `sales_order_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `visit_date` DATE NOT NULL,
+  `outcome` ENUM('order_taken', 'no_order', 'payment_collected') NOT NULL,
+  `amount_collected` DECIMAL(20,0) NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_visit_log_1_idx` (`partnership_id` ASC),
+  INDEX `fk_visit_log_2_idx` (`sales_person_id` ASC),
+  INDEX `fk_visit_log_3_idx` (`sales_order_id` ASC),
+  INDEX `fk_visit_log_4_idx` (`created_by` ASC),
+  INDEX `visit_log_date_idx` (`visit_date` ASC),
+  CONSTRAINT `fk_visit_log_1`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_visit_log_2`
+    FOREIGN KEY (`sales_person_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_visit_log_3`
+    FOREIGN KEY (`sales_order_id`)
+    REFERENCES `sales_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_visit_log_4`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('174','2', 'Customer Visit', 'sales_visit', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('175','174', 'Read Visit Plan', 'visit_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('176','174', 'Log Visit', 'visit_log', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('177','174', 'Visit Report', 'visit_report', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('526','174', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('527','175', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('528','176', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('529','177', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('530','174', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('531','175', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('532','176', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('533','177', '2');
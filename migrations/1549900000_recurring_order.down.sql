@@ -0,0 +1,8 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `recurring_order_log`;
+DROP TABLE IF EXISTS `recurring_order_item`;
+DROP TABLE IF EXISTS `recurring_order`;
+
+DELETE FROM `application_setting` WHERE `id` = 20;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 534 AND 541;
+DELETE FROM `application_module` WHERE `id` IN (178, 179, 180, 181);
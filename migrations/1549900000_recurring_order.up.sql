@@ -0,0 +1,121 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `recurring_order` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `customer_id` BIGINT(20) UNSIGNED NOT NULL,
+  `schedule_type` ENUM('weekly', 'monthly') NOT NULL DEFAULT 'weekly',
+  `schedule_days` VARCHAR(100) NULL DEFAULT NULL,
+  `day_of_month` TINYINT(2) NULL DEFAULT NULL,
+  `start_date` DATE NOT NULL,
+  `end_date` DATE NULL DEFAULT NULL,
+  `last_run_date` DATE NULL DEFAULT NULL,
+  `eta_days` TINYINT(3) NULL DEFAULT '0',
+  `shipment_address` TEXT NULL DEFAULT NULL,
+  `shipment_cost` DECIMAL(20,0) NULL DEFAULT '0',
+  `discount` FLOAT NULL DEFAULT '0',
+  `discount_amount` DECIMAL(20,0) NULL DEFAULT '0',
+  `is_percentage_discount` TINYINT(1) NULL DEFAULT '0',
+  `tax` FLOAT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  `is_active` TINYINT(1) NULL DEFAULT '1',
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `recurring_order_code_UNIQUE` (`code` ASC),
+  INDEX `fk_recurring_order_1_idx` (`customer_id` ASC),
+  INDEX `fk_recurring_order_2_idx` (`created_by` ASC),
+  INDEX `fk_recurring_order_3_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_recurring_order_1`
+    FOREIGN KEY (`customer_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_recurring_order_2`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_recurring_order_3`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `recurring_order_item` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `recurring_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `item_variant_id` BIGINT(20) UNSIGNED NOT NULL,
+  `pricing_type_id` BIGINT(20) UNSIGNED NOT NULL,
+  `tax_code_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `quantity` FLOAT NOT NULL DEFAULT '0',
+  `unit_price` DECIMAL(20,0) NULL DEFAULT '0',
+  `discount` FLOAT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_recurring_order_item_1_idx` (`recurring_order_id` ASC),
+  INDEX `fk_recurring_order_item_2_idx` (`item_variant_id` ASC),
+  INDEX `fk_recurring_order_item_3_idx` (`pricing_type_id` ASC),
+  INDEX `fk_recurring_order_item_4_idx` (`tax_code_id` ASC),
+  CONSTRAINT `fk_recurring_order_item_1`
+    FOREIGN KEY (`recurring_order_id`)
+    REFERENCES `recurring_order` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_recurring_order_item_2`
+    FOREIGN KEY (`item_variant_id`)
+    REFERENCES `item_variant` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_recurring_order_item_3`
+    FOREIGN KEY (`pricing_type_id`)
+    REFERENCES `pricing_type` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_recurring_order_item_4`
+    FOREIGN KEY (`tax_code_id`)
+    REFERENCES `tax_code` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `recurring_order_log` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `recurring_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `run_date` DATE NOT NULL,
+  `status` ENUM('success', 'failed') NOT NULL,
+  `sales_order_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `message` TEXT NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_recurring_order_log_1_idx` (`recurring_order_id` ASC),
+  INDEX `fk_recurring_order_log_2_idx` (`sales_order_id` ASC),
+  CONSTRAINT `fk_recurring_order_log_1`
+    FOREIGN KEY (`recurring_order_id`)
+    REFERENCES `recurring_order` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_recurring_order_log_2`
+    FOREIGN KEY (`sales_order_id`)
+    REFERENCES `sales_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (20,'code_recurring_order','{"code_prefix":"S#RO-%5d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('178','2', 'Recurring Order', 'sales_recurring', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('179','178', 'Read Recurring Order', 'recurring_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('180','178', 'Manage Recurring Order', 'recurring_manage', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('181','178', 'Run Recurring Order', 'recurring_run', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('534','178', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('535','179', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('536','180', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('537','181', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('538','178', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('539','179', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('540','180', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('541','181', '2');
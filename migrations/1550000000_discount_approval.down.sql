@@ -0,0 +1,10 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `sales_order_approval`;
+DROP TABLE IF EXISTS `discount_limit`;
+
+UPDATE `sales_order` SET `document_status` = 'new' WHERE `document_status` = 'pending_approval';
+ALTER TABLE `sales_order`
+  MODIFY COLUMN `document_status` ENUM('new', 'active', 'finished', 'requested_cancel', 'approved_cancel') NULL DEFAULT 'new' COMMENT '\'requested_cancel\' by cashier usergroup, \'approved_cancel\' by owner / supervisor usergroup';
+
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 542 AND 545;
+DELETE FROM `application_module` WHERE `id` IN (182, 183);
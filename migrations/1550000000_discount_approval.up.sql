@@ -0,0 +1,78 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `discount_limit` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `usergroup_id` BIGINT(20) UNSIGNED NOT NULL,
+  `max_line_discount` FLOAT NOT NULL DEFAULT '100' COMMENT 'persentase diskon maksimal setiap item',
+  `max_order_discount` FLOAT NOT NULL DEFAULT '100' COMMENT 'persentase diskon maksimal pada order',
+  `min_margin` FLOAT NOT NULL DEFAULT '0' COMMENT 'persentase margin minimal terhadap total cost, 0 berarti tidak dicek',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `discount_limit_usergroup_UNIQUE` (`usergroup_id` ASC),
+  INDEX `fk_discount_limit_2_idx` (`created_by` ASC),
+  INDEX `fk_discount_limit_3_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_discount_limit_1`
+    FOREIGN KEY (`usergroup_id`)
+    REFERENCES `usergroup` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_discount_limit_2`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_discount_limit_3`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `sales_order_approval` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `sales_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `reason` TEXT NOT NULL,
+  `line_discount` FLOAT NULL DEFAULT '0',
+  `order_discount` FLOAT NULL DEFAULT '0',
+  `margin` FLOAT NULL DEFAULT '0',
+  `total_cost` DECIMAL(20,0) NULL DEFAULT '0',
+  `status` ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+  `note` TEXT NULL DEFAULT NULL,
+  `requested_by` BIGINT(20) UNSIGNED NOT NULL,
+  `requested_at` TIMESTAMP NULL DEFAULT NULL,
+  `decided_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `decided_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_sales_order_approval_1_idx` (`sales_order_id` ASC),
+  INDEX `fk_sales_order_approval_2_idx` (`requested_by` ASC),
+  INDEX `fk_sales_order_approval_3_idx` (`decided_by` ASC),
+  CONSTRAINT `fk_sales_order_approval_1`
+    FOREIGN KEY (`sales_order_id`)
+    REFERENCES `sales_order` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_sales_order_approval_2`
+    FOREIGN KEY (`requested_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_sales_order_approval_3`
+    FOREIGN KEY (`decided_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `sales_order`
+  MODIFY COLUMN `document_status` ENUM('new', 'active', 'finished', 'requested_cancel', 'approved_cancel', 'pending_approval') NULL DEFAULT 'new' COMMENT '\'requested_cancel\' by cashier usergroup, \'approved_cancel\' by owner / supervisor usergroup, \'pending_approval\' when discount exceeds usergroup limit';
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('182','11', 'Approve Discount Sales Order', 'sales_order_approve_discount', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('183','9', 'Discount Limit', 'setting_discount_limit', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('542','182', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('543','183', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('544','182', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('545','183', '2');
@@ -0,0 +1,5 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `sales_order_revision`;
+
+DELETE FROM `application_privilege` WHERE `id` IN (546, 547);
+DELETE FROM `application_module` WHERE `id` = 184;
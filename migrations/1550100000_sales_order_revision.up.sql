@@ -0,0 +1,28 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `sales_order_revision` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `sales_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `revision` INT(11) NOT NULL DEFAULT '1',
+  `snapshot` TEXT NOT NULL COMMENT 'json data sales order beserta item pada revisi tersebut',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `sales_order_revision_UNIQUE` (`sales_order_id` ASC, `revision` ASC),
+  INDEX `fk_sales_order_revision_2_idx` (`created_by` ASC),
+  CONSTRAINT `fk_sales_order_revision_1`
+    FOREIGN KEY (`sales_order_id`)
+    REFERENCES `sales_order` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_sales_order_revision_2`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('184','11', 'Amend Sales Order', 'sales_order_amend', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('546','184', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('547','184', '2');
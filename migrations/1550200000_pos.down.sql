@@ -0,0 +1,11 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `pos_payment`;
+DROP TABLE IF EXISTS `pos_transaction`;
+DROP TABLE IF EXISTS `pos_shift`;
+
+UPDATE `finance_revenue` SET `payment_method` = 'debit_card' WHERE `payment_method` = 'transfer';
+ALTER TABLE `finance_revenue` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro') NULL DEFAULT 'cash';
+
+DELETE FROM `application_setting` WHERE `id` = 21;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 548 AND 558;
+DELETE FROM `application_module` WHERE `id` BETWEEN 185 AND 188;
@@ -0,0 +1,104 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `pos_shift` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `cashier_id` BIGINT(20) UNSIGNED NOT NULL,
+  `opening_float` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `cash_sales` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `non_cash_sales` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `expected_cash` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `counted_cash` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `difference` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `total_transaction` INT(11) NOT NULL DEFAULT '0',
+  `status` ENUM('open', 'closed') NOT NULL DEFAULT 'open',
+  `note` TEXT NULL DEFAULT NULL,
+  `opened_at` TIMESTAMP NULL DEFAULT NULL,
+  `closed_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_pos_shift_1_idx` (`cashier_id` ASC),
+  CONSTRAINT `fk_pos_shift_1`
+    FOREIGN KEY (`cashier_id`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `pos_transaction` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `pos_shift_id` BIGINT(20) UNSIGNED NOT NULL,
+  `sales_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `total_charge` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `total_tendered` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `change_amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_pos_transaction_1_idx` (`pos_shift_id` ASC),
+  INDEX `fk_pos_transaction_2_idx` (`sales_order_id` ASC),
+  INDEX `fk_pos_transaction_3_idx` (`created_by` ASC),
+  CONSTRAINT `fk_pos_transaction_1`
+    FOREIGN KEY (`pos_shift_id`)
+    REFERENCES `pos_shift` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_pos_transaction_2`
+    FOREIGN KEY (`sales_order_id`)
+    REFERENCES `sales_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_pos_transaction_3`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `pos_payment` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `pos_transaction_id` BIGINT(20) UNSIGNED NOT NULL,
+  `payment_method` ENUM('cash', 'transfer', 'debit_card', 'credit_card') NOT NULL DEFAULT 'cash',
+  `bank_account_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'jumlah yang diterima dari customer, termasuk kembalian untuk cash',
+  `finance_revenue_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_pos_payment_1_idx` (`pos_transaction_id` ASC),
+  INDEX `fk_pos_payment_2_idx` (`bank_account_id` ASC),
+  INDEX `fk_pos_payment_3_idx` (`finance_revenue_id` ASC),
+  CONSTRAINT `fk_pos_payment_1`
+    FOREIGN KEY (`pos_transaction_id`)
+    REFERENCES `pos_transaction` (`id`)
+    ON DELETE CASCADE
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_pos_payment_2`
+    FOREIGN KEY (`bank_account_id`)
+    REFERENCES `bank_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_pos_payment_3`
+    FOREIGN KEY (`finance_revenue_id`)
+    REFERENCES `finance_revenue` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `finance_revenue` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro', 'transfer') NULL DEFAULT 'cash';
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (21,'code_pos_shift','{"code_prefix":"SHF-%5d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('185','2', 'Point of Sale', 'pos', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('186','185', 'Cashier Shift', 'pos_shift', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('187','185', 'POS Checkout', 'pos_checkout', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('188','185', 'Shift Report', 'pos_report', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('548','185', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('549','186', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('550','187', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('551','188', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('552','185', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('553','186', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('554','187', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('555','188', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('556','185', '4');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('557','186', '4');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('558','187', '4');
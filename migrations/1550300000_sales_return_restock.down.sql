@@ -0,0 +1,22 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `sales_return_item`
+DROP COLUMN `unit_cost`,
+DROP COLUMN `condition`;
+
+ALTER TABLE `sales_return`
+DROP FOREIGN KEY `fk_sales_return_5`;
+ALTER TABLE `sales_return`
+DROP INDEX `fk_sales_return_5_idx`,
+DROP COLUMN `restocked_at`,
+DROP COLUMN `restocked_by`,
+DROP COLUMN `total_write_off`,
+DROP COLUMN `is_restocked`;
+
+DELETE FROM `item_variant_stock_log` WHERE `ref_type` = 'sales_return';
+ALTER TABLE `item_variant_stock_log` CHANGE COLUMN `ref_type` `ref_type` ENUM('workorder_fulfillment', 'workorder_receiving', 'stockopname', 'direct_placement') NULL DEFAULT 'workorder_fulfillment' COMMENT 'referred document type';
+
+ALTER TABLE `item_variant_stock`
+DROP COLUMN `location`;
+
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 559 AND 560;
+DELETE FROM `application_module` WHERE `id` = 189;
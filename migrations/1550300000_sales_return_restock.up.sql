@@ -0,0 +1,26 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `item_variant_stock`
+ADD COLUMN `location` ENUM('warehouse', 'quarantine') NOT NULL DEFAULT 'warehouse' COMMENT 'stock quarantine tidak dihitung sebagai available stock' AFTER `unit_cost`;
+
+ALTER TABLE `item_variant_stock_log` CHANGE COLUMN `ref_type` `ref_type` ENUM('workorder_fulfillment', 'workorder_receiving', 'stockopname', 'direct_placement', 'sales_return') NULL DEFAULT 'workorder_fulfillment' COMMENT 'referred document type';
+
+ALTER TABLE `sales_return`
+ADD COLUMN `is_restocked` TINYINT(1) NOT NULL DEFAULT '0' AFTER `is_bundled`,
+ADD COLUMN `total_write_off` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'nilai cost item scrap yang dihapus' AFTER `is_restocked`,
+ADD COLUMN `restocked_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `total_write_off`,
+ADD COLUMN `restocked_at` TIMESTAMP NULL DEFAULT NULL AFTER `restocked_by`,
+ADD INDEX `fk_sales_return_5_idx` (`restocked_by` ASC),
+ADD CONSTRAINT `fk_sales_return_5`
+  FOREIGN KEY (`restocked_by`)
+  REFERENCES `user` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+ALTER TABLE `sales_return_item`
+ADD COLUMN `condition` ENUM('ungraded', 'resellable', 'damaged', 'scrap') NOT NULL DEFAULT 'ungraded' AFTER `quantity`,
+ADD COLUMN `unit_cost` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'cost rata-rata batch fifo yang dikeluarkan fulfillment' AFTER `condition`;
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('189','13', 'Restock Sales Return', 'sales_return_restock', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('559','189', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('560','189', '2');
@@ -0,0 +1,15 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `finance_revenue`
+DROP FOREIGN KEY `fk_finance_revenue_8`;
+ALTER TABLE `finance_revenue`
+DROP INDEX `fk_finance_revenue_8_idx`,
+DROP COLUMN `credit_note_id`;
+
+UPDATE `finance_revenue` SET `payment_method` = 'cash' WHERE `payment_method` = 'credit_note';
+ALTER TABLE `finance_revenue` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro', 'transfer') NULL DEFAULT 'cash';
+
+DROP TABLE IF EXISTS `credit_note`;
+
+DELETE FROM `application_setting` WHERE `id` = 22;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 561 AND 568;
+DELETE FROM `application_module` WHERE `id` BETWEEN 190 AND 193;
@@ -0,0 +1,69 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `credit_note` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `sales_return_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'kosong apabila credit note diterbitkan manual',
+  `recognition_date` DATE NOT NULL,
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `used_amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'jumlah yang sudah digunakan untuk pembayaran sales invoice',
+  `balance` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `document_status` ENUM('active', 'used', 'cancelled') NOT NULL DEFAULT 'active',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_credit_note_1_idx` (`partnership_id` ASC),
+  INDEX `fk_credit_note_2_idx` (`sales_return_id` ASC),
+  INDEX `fk_credit_note_3_idx` (`created_by` ASC),
+  INDEX `fk_credit_note_4_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_credit_note_1`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_credit_note_2`
+    FOREIGN KEY (`sales_return_id`)
+    REFERENCES `sales_return` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_credit_note_3`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_credit_note_4`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `finance_revenue` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro', 'transfer', 'credit_note') NULL DEFAULT 'cash';
+ALTER TABLE `finance_revenue`
+ADD COLUMN `credit_note_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `bank_account_id`,
+ADD INDEX `fk_finance_revenue_8_idx` (`credit_note_id` ASC),
+ADD CONSTRAINT `fk_finance_revenue_8`
+  FOREIGN KEY (`credit_note_id`)
+  REFERENCES `credit_note` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (22,'code_credit_note','{"code_prefix":"CN-%5d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('190','6', 'Credit Note', 'credit_note', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('191','190', 'Read Credit Note', 'credit_note_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('192','190', 'Create Credit Note', 'credit_note_create', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('193','190', 'Cancel Credit Note', 'credit_note_cancel', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('561','190', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('562','191', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('563','192', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('564','193', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('565','190', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('566','191', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('567','192', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('568','193', '2');
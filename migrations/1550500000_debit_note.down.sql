@@ -0,0 +1,15 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `finance_expense`
+DROP FOREIGN KEY `fk_finance_expense_3`;
+ALTER TABLE `finance_expense`
+DROP INDEX `fk_finance_expense_3_idx`,
+DROP COLUMN `debit_note_id`;
+
+UPDATE `finance_expense` SET `payment_method` = 'cash' WHERE `payment_method` = 'debit_note';
+ALTER TABLE `finance_expense` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro') NULL DEFAULT 'cash';
+
+DROP TABLE IF EXISTS `debit_note`;
+
+DELETE FROM `application_setting` WHERE `id` = 23;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 569 AND 576;
+DELETE FROM `application_module` WHERE `id` BETWEEN 194 AND 197;
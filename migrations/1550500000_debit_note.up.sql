@@ -0,0 +1,69 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `debit_note` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `purchase_return_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'kosong apabila debit note diterbitkan manual',
+  `recognition_date` DATE NOT NULL,
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `used_amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'jumlah yang sudah digunakan untuk pembayaran purchase invoice',
+  `balance` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `document_status` ENUM('active', 'used', 'cancelled') NOT NULL DEFAULT 'active',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_debit_note_1_idx` (`partnership_id` ASC),
+  INDEX `fk_debit_note_2_idx` (`purchase_return_id` ASC),
+  INDEX `fk_debit_note_3_idx` (`created_by` ASC),
+  INDEX `fk_debit_note_4_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_debit_note_1`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_note_2`
+    FOREIGN KEY (`purchase_return_id`)
+    REFERENCES `purchase_return` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_note_3`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_debit_note_4`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `finance_expense` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro', 'debit_note') NULL DEFAULT 'cash';
+ALTER TABLE `finance_expense`
+ADD COLUMN `debit_note_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `id`,
+ADD INDEX `fk_finance_expense_3_idx` (`debit_note_id` ASC),
+ADD CONSTRAINT `fk_finance_expense_3`
+  FOREIGN KEY (`debit_note_id`)
+  REFERENCES `debit_note` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (23,'code_debit_note','{"code_prefix":"DN-%5d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('194','3', 'Debit Note', 'debit_note', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('195','194', 'Read Debit Note', 'debit_note_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('196','194', 'Create Debit Note', 'debit_note_create', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('197','194', 'Cancel Debit Note', 'debit_note_cancel', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('569','194', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('570','195', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('571','196', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('572','197', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('573','194', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('574','195', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('575','196', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('576','197', '2');
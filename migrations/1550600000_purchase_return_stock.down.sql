@@ -0,0 +1,9 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `purchase_return_item`
+DROP FOREIGN KEY `fk_purchase_return_item_3`;
+ALTER TABLE `purchase_return_item`
+DROP INDEX `fk_purchase_return_item_3_idx`,
+DROP COLUMN `item_variant_stock_id`;
+
+DELETE FROM `item_variant_stock_log` WHERE `ref_type` = 'purchase_return';
+ALTER TABLE `item_variant_stock_log` CHANGE COLUMN `ref_type` `ref_type` ENUM('workorder_fulfillment', 'workorder_receiving', 'stockopname', 'direct_placement', 'sales_return') NULL DEFAULT 'workorder_fulfillment' COMMENT 'referred document type';
@@ -0,0 +1,11 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `item_variant_stock_log` CHANGE COLUMN `ref_type` `ref_type` ENUM('workorder_fulfillment', 'workorder_receiving', 'stockopname', 'direct_placement', 'sales_return', 'purchase_return') NULL DEFAULT 'workorder_fulfillment' COMMENT 'referred document type';
+
+ALTER TABLE `purchase_return_item`
+ADD COLUMN `item_variant_stock_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'batch stock hasil receiving yang dikembalikan' AFTER `purchase_order_item_id`,
+ADD INDEX `fk_purchase_return_item_3_idx` (`item_variant_stock_id` ASC),
+ADD CONSTRAINT `fk_purchase_return_item_3`
+  FOREIGN KEY (`item_variant_stock_id`)
+  REFERENCES `item_variant_stock` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
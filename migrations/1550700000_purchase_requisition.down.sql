@@ -0,0 +1,21 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `purchase_order_item`
+DROP FOREIGN KEY `fk_purchase_order_item_4`;
+ALTER TABLE `purchase_order_item`
+DROP INDEX `fk_purchase_order_item_4_idx`,
+DROP COLUMN `purchase_requisition_item_id`;
+
+ALTER TABLE `purchase_order`
+DROP FOREIGN KEY `fk_purchase_order_5`;
+ALTER TABLE `purchase_order`
+DROP INDEX `fk_purchase_order_5_idx`,
+DROP COLUMN `purchase_requisition_id`;
+
+DROP TABLE IF EXISTS `purchase_requisition_approval`;
+DROP TABLE IF EXISTS `purchase_requisition_item`;
+DROP TABLE IF EXISTS `purchase_requisition`;
+DROP TABLE IF EXISTS `requisition_approval_level`;
+
+DELETE FROM `application_setting` WHERE `id` = 24;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 577 AND 590;
+DELETE FROM `application_module` WHERE `id` BETWEEN 198 AND 204;
@@ -0,0 +1,158 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `requisition_approval_level` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `usergroup_id` BIGINT(20) UNSIGNED NOT NULL,
+  `level` INT(11) NOT NULL DEFAULT '1' COMMENT 'urutan approval, level kecil approve terlebih dahulu',
+  `min_amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'level dibutuhkan apabila total requisition >= min_amount',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_requisition_approval_level_1_idx` (`usergroup_id` ASC),
+  INDEX `fk_requisition_approval_level_2_idx` (`created_by` ASC),
+  INDEX `fk_requisition_approval_level_3_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_requisition_approval_level_1`
+    FOREIGN KEY (`usergroup_id`)
+    REFERENCES `usergroup` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_requisition_approval_level_2`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_requisition_approval_level_3`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `purchase_requisition` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `recognition_date` DATE NOT NULL,
+  `needed_by_date` DATE NOT NULL,
+  `justification` TEXT NOT NULL,
+  `total_amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'perkiraan total harga item yang diminta',
+  `document_status` ENUM('pending_approval', 'approved', 'rejected', 'partial', 'finished', 'cancelled') NOT NULL DEFAULT 'pending_approval' COMMENT '\'partial\' sebagian quantity sudah dibuat purchase order, \'finished\' seluruh quantity sudah dibuat purchase order',
+  `note` TEXT NULL DEFAULT NULL,
+  `is_deleted` TINYINT(1) NOT NULL DEFAULT '0',
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_purchase_requisition_1_idx` (`created_by` ASC),
+  INDEX `fk_purchase_requisition_2_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_purchase_requisition_1`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_requisition_2`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `purchase_requisition_item` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `purchase_requisition_id` BIGINT(20) UNSIGNED NOT NULL,
+  `item_variant_id` BIGINT(20) UNSIGNED NOT NULL,
+  `quantity` FLOAT NOT NULL DEFAULT '0',
+  `ordered_quantity` FLOAT NOT NULL DEFAULT '0' COMMENT 'quantity yang sudah dibuat purchase order',
+  `unit_price` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'perkiraan harga satuan',
+  `subtotal` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_purchase_requisition_item_1_idx` (`purchase_requisition_id` ASC),
+  INDEX `fk_purchase_requisition_item_2_idx` (`item_variant_id` ASC),
+  CONSTRAINT `fk_purchase_requisition_item_1`
+    FOREIGN KEY (`purchase_requisition_id`)
+    REFERENCES `purchase_requisition` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_requisition_item_2`
+    FOREIGN KEY (`item_variant_id`)
+    REFERENCES `item_variant` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `purchase_requisition_approval` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `purchase_requisition_id` BIGINT(20) UNSIGNED NOT NULL,
+  `usergroup_id` BIGINT(20) UNSIGNED NOT NULL,
+  `level` INT(11) NOT NULL DEFAULT '1',
+  `status` ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+  `note` TEXT NULL DEFAULT NULL,
+  `decided_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `decided_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_purchase_requisition_approval_1_idx` (`purchase_requisition_id` ASC),
+  INDEX `fk_purchase_requisition_approval_2_idx` (`usergroup_id` ASC),
+  INDEX `fk_purchase_requisition_approval_3_idx` (`decided_by` ASC),
+  CONSTRAINT `fk_purchase_requisition_approval_1`
+    FOREIGN KEY (`purchase_requisition_id`)
+    REFERENCES `purchase_requisition` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_requisition_approval_2`
+    FOREIGN KEY (`usergroup_id`)
+    REFERENCES `usergroup` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_requisition_approval_3`
+    FOREIGN KEY (`decided_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `purchase_order`
+ADD COLUMN `purchase_requisition_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `reference_id`,
+ADD INDEX `fk_purchase_order_5_idx` (`purchase_requisition_id` ASC),
+ADD CONSTRAINT `fk_purchase_order_5`
+  FOREIGN KEY (`purchase_requisition_id`)
+  REFERENCES `purchase_requisition` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+ALTER TABLE `purchase_order_item`
+ADD COLUMN `purchase_requisition_item_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `item_variant_id`,
+ADD INDEX `fk_purchase_order_item_4_idx` (`purchase_requisition_item_id` ASC),
+ADD CONSTRAINT `fk_purchase_order_item_4`
+  FOREIGN KEY (`purchase_requisition_item_id`)
+  REFERENCES `purchase_requisition_item` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (24,'code_purchase_requisition','{"code_prefix":"P#RQ-%5d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('198','3', 'Purchase Requisition', 'purchase_requisition', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('199','198', 'Read Purchase Requisition', 'purchase_requisition_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('200','198', 'Create Purchase Requisition', 'purchase_requisition_create', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('201','198', 'Approve Purchase Requisition', 'purchase_requisition_approve', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('202','198', 'Convert Purchase Requisition', 'purchase_requisition_convert', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('203','198', 'Cancel Purchase Requisition', 'purchase_requisition_cancel', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('204','9', 'Requisition Approval Level', 'setting_requisition_approval', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('577','198', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('578','199', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('579','200', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('580','201', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('581','202', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('582','203', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('583','204', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('584','198', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('585','199', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('586','200', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('587','201', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('588','202', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('589','203', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('590','204', '2');
@@ -0,0 +1,10 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `purchase_invoice`
+DROP COLUMN `match_status`;
+
+DROP TABLE IF EXISTS `purchase_invoice_mismatch`;
+DROP TABLE IF EXISTS `purchase_invoice_item`;
+
+DELETE FROM `application_setting` WHERE `id` IN (25, 26);
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 591 AND 594;
+DELETE FROM `application_module` WHERE `id` BETWEEN 205 AND 206;
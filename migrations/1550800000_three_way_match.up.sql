@@ -0,0 +1,71 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `purchase_invoice_item` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `purchase_invoice_id` BIGINT(20) UNSIGNED NOT NULL,
+  `purchase_order_item_id` BIGINT(20) UNSIGNED NOT NULL,
+  `quantity` FLOAT NOT NULL DEFAULT '0' COMMENT 'quantity yang ditagihkan supplier',
+  `unit_price` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'harga satuan yang ditagihkan supplier',
+  `subtotal` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_purchase_invoice_item_1_idx` (`purchase_invoice_id` ASC),
+  INDEX `fk_purchase_invoice_item_2_idx` (`purchase_order_item_id` ASC),
+  CONSTRAINT `fk_purchase_invoice_item_1`
+    FOREIGN KEY (`purchase_invoice_id`)
+    REFERENCES `purchase_invoice` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_invoice_item_2`
+    FOREIGN KEY (`purchase_order_item_id`)
+    REFERENCES `purchase_order_item` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `purchase_invoice_mismatch` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `purchase_invoice_id` BIGINT(20) UNSIGNED NOT NULL,
+  `purchase_invoice_item_id` BIGINT(20) UNSIGNED NOT NULL,
+  `mismatch_type` ENUM('price', 'quantity') NOT NULL COMMENT '\'price\' harga invoice berbeda dengan harga purchase order, \'quantity\' quantity invoice melebihi quantity yang diterima',
+  `expected_value` DECIMAL(20,2) NOT NULL DEFAULT '0' COMMENT 'harga purchase order setelah discount atau quantity yang sudah diterima',
+  `invoiced_value` DECIMAL(20,2) NOT NULL DEFAULT '0' COMMENT 'harga atau total quantity yang ditagihkan',
+  `variance` DECIMAL(10,2) NOT NULL DEFAULT '0' COMMENT 'selisih dalam persen terhadap expected_value',
+  `status` ENUM('open', 'resolved') NOT NULL DEFAULT 'open',
+  `resolution_note` TEXT NULL DEFAULT NULL,
+  `resolved_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `resolved_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_purchase_invoice_mismatch_1_idx` (`purchase_invoice_id` ASC),
+  INDEX `fk_purchase_invoice_mismatch_2_idx` (`purchase_invoice_item_id` ASC),
+  INDEX `fk_purchase_invoice_mismatch_3_idx` (`resolved_by` ASC),
+  CONSTRAINT `fk_purchase_invoice_mismatch_1`
+    FOREIGN KEY (`purchase_invoice_id`)
+    REFERENCES `purchase_invoice` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_invoice_mismatch_2`
+    FOREIGN KEY (`purchase_invoice_item_id`)
+    REFERENCES `purchase_invoice_item` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_invoice_mismatch_3`
+    FOREIGN KEY (`resolved_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `purchase_invoice`
+ADD COLUMN `match_status` ENUM('matched', 'mismatch', 'resolved') NOT NULL DEFAULT 'matched' COMMENT 'hasil three-way match purchase order, receiving dan invoice' AFTER `document_status`;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (25,'match_price_tolerance','0');
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (26,'match_quantity_tolerance','0');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('205','15', 'Read Invoice Mismatch', 'purchase_invoice_mismatch_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('206','15', 'Resolve Invoice Mismatch', 'purchase_invoice_mismatch_resolve', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('591','205', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('592','206', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('593','205', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('594','206', '2');
@@ -0,0 +1,12 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `landed_cost_adjustment`;
+DROP TABLE IF EXISTS `landed_cost_item`;
+DROP TABLE IF EXISTS `landed_cost_charge`;
+DROP TABLE IF EXISTS `landed_cost`;
+
+ALTER TABLE `item_variant`
+DROP COLUMN `weight`;
+
+DELETE FROM `application_setting` WHERE `id` = 27;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 595 AND 602;
+DELETE FROM `application_module` WHERE `id` BETWEEN 207 AND 210;
@@ -0,0 +1,138 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `item_variant`
+ADD COLUMN `weight` FLOAT NOT NULL DEFAULT '0' COMMENT 'berat per unit dalam kg, digunakan untuk alokasi landed cost' AFTER `minimum_stock`;
+
+CREATE TABLE IF NOT EXISTS `landed_cost` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `recognition_date` DATE NOT NULL,
+  `allocation_method` ENUM('value', 'quantity', 'weight') NOT NULL DEFAULT 'value' COMMENT 'dasar pembagian biaya ke item receiving',
+  `total_amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `document_status` ENUM('active', 'cancelled') NOT NULL DEFAULT 'active',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_landed_cost_1_idx` (`created_by` ASC),
+  INDEX `fk_landed_cost_2_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_landed_cost_1`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_2`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `landed_cost_charge` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `landed_cost_id` BIGINT(20) UNSIGNED NOT NULL,
+  `charge_type` ENUM('shipment_cost', 'freight', 'customs', 'insurance', 'other') NOT NULL,
+  `purchase_order_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'diisi apabila biaya berasal dari shipment_cost purchase order',
+  `partnership_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'vendor yang menagihkan biaya',
+  `reference` VARCHAR(120) NULL DEFAULT NULL COMMENT 'nomor tagihan freight atau dokumen bea cukai',
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_landed_cost_charge_1_idx` (`landed_cost_id` ASC),
+  INDEX `fk_landed_cost_charge_2_idx` (`purchase_order_id` ASC),
+  INDEX `fk_landed_cost_charge_3_idx` (`partnership_id` ASC),
+  CONSTRAINT `fk_landed_cost_charge_1`
+    FOREIGN KEY (`landed_cost_id`)
+    REFERENCES `landed_cost` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_charge_2`
+    FOREIGN KEY (`purchase_order_id`)
+    REFERENCES `purchase_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_charge_3`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `landed_cost_item` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `landed_cost_id` BIGINT(20) UNSIGNED NOT NULL,
+  `workorder_receiving_item_id` BIGINT(20) UNSIGNED NOT NULL,
+  `item_variant_stock_id` BIGINT(20) UNSIGNED NOT NULL COMMENT 'batch yang masuk dari receiving item',
+  `quantity` FLOAT NOT NULL DEFAULT '0' COMMENT 'quantity yang diterima',
+  `basis` DECIMAL(20,2) NOT NULL DEFAULT '0' COMMENT 'nilai, quantity atau berat sesuai allocation_method',
+  `allocated_amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `unit_cost_before` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `unit_cost_after` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `consumed_quantity` FLOAT NOT NULL DEFAULT '0' COMMENT 'quantity batch yang sudah keluar ketika landed cost dibuat',
+  `consumed_amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'selisih cost untuk quantity yang sudah keluar',
+  PRIMARY KEY (`id`),
+  INDEX `fk_landed_cost_item_1_idx` (`landed_cost_id` ASC),
+  INDEX `fk_landed_cost_item_2_idx` (`workorder_receiving_item_id` ASC),
+  INDEX `fk_landed_cost_item_3_idx` (`item_variant_stock_id` ASC),
+  CONSTRAINT `fk_landed_cost_item_1`
+    FOREIGN KEY (`landed_cost_id`)
+    REFERENCES `landed_cost` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_item_2`
+    FOREIGN KEY (`workorder_receiving_item_id`)
+    REFERENCES `workorder_receiving_item` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_item_3`
+    FOREIGN KEY (`item_variant_stock_id`)
+    REFERENCES `item_variant_stock` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `landed_cost_adjustment` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `landed_cost_id` BIGINT(20) UNSIGNED NOT NULL,
+  `landed_cost_item_id` BIGINT(20) UNSIGNED NOT NULL,
+  `sales_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `quantity` FLOAT NOT NULL DEFAULT '0' COMMENT 'quantity batch yang sudah difulfill ke sales order',
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'tambahan total_cost sales order',
+  PRIMARY KEY (`id`),
+  INDEX `fk_landed_cost_adjustment_1_idx` (`landed_cost_id` ASC),
+  INDEX `fk_landed_cost_adjustment_2_idx` (`landed_cost_item_id` ASC),
+  INDEX `fk_landed_cost_adjustment_3_idx` (`sales_order_id` ASC),
+  CONSTRAINT `fk_landed_cost_adjustment_1`
+    FOREIGN KEY (`landed_cost_id`)
+    REFERENCES `landed_cost` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_adjustment_2`
+    FOREIGN KEY (`landed_cost_item_id`)
+    REFERENCES `landed_cost_item` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_landed_cost_adjustment_3`
+    FOREIGN KEY (`sales_order_id`)
+    REFERENCES `sales_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (27,'code_landed_cost','{"code_prefix":"P#LC-%5d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('207','3', 'Landed Cost', 'landed_cost', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('208','207', 'Read Landed Cost', 'landed_cost_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('209','207', 'Create Landed Cost', 'landed_cost_create', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('210','207', 'Cancel Landed Cost', 'landed_cost_cancel', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('595','207', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('596','208', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('597','209', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('598','210', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('599','207', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('600','208', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('601','209', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('602','210', '2');
@@ -0,0 +1,3 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 603 AND 604;
+DELETE FROM `application_module` WHERE `id` = 211;
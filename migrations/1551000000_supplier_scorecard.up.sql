@@ -0,0 +1,6 @@
+SET FOREIGN_KEY_CHECKS = 0;
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('211','7', 'Supplier Scorecard Report', 'report_supplier_scorecard', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('603','211', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('604','211', '2');
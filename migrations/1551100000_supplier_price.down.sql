@@ -0,0 +1,7 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `purchase_price_alert`;
+DROP TABLE IF EXISTS `supplier_catalogue`;
+
+DELETE FROM `application_setting` WHERE `id` = 28;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 605 AND 610;
+DELETE FROM `application_module` WHERE `id` BETWEEN 212 AND 214;
@@ -0,0 +1,98 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `supplier_catalogue` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `item_variant_id` BIGINT(20) UNSIGNED NOT NULL,
+  `supplier_item_code` VARCHAR(45) NOT NULL COMMENT 'kode barang pada katalog supplier',
+  `supplier_item_name` VARCHAR(200) NULL DEFAULT NULL,
+  `note` TEXT NULL DEFAULT NULL,
+  `is_deleted` TINYINT(1) NULL DEFAULT '0',
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_supplier_catalogue_1_idx` (`partnership_id` ASC),
+  INDEX `fk_supplier_catalogue_2_idx` (`item_variant_id` ASC),
+  INDEX `fk_supplier_catalogue_3_idx` (`created_by` ASC),
+  INDEX `fk_supplier_catalogue_4_idx` (`updated_by` ASC),
+  INDEX `supplier_item_code_idx` (`partnership_id` ASC, `supplier_item_code` ASC),
+  CONSTRAINT `fk_supplier_catalogue_1`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_supplier_catalogue_2`
+    FOREIGN KEY (`item_variant_id`)
+    REFERENCES `item_variant` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_supplier_catalogue_3`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_supplier_catalogue_4`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `purchase_price_alert` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `purchase_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `purchase_order_item_id` BIGINT(20) UNSIGNED NOT NULL,
+  `last_purchase_order_item_id` BIGINT(20) UNSIGNED NOT NULL COMMENT 'pembelian terakhir yang menjadi pembanding harga',
+  `item_variant_id` BIGINT(20) UNSIGNED NOT NULL,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `last_unit_price` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `unit_price` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `deviation` DECIMAL(10,2) NOT NULL DEFAULT '0' COMMENT 'selisih harga dalam persen, negatif apabila harga turun',
+  `threshold` DECIMAL(10,2) NOT NULL DEFAULT '0',
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_purchase_price_alert_1_idx` (`purchase_order_id` ASC),
+  INDEX `fk_purchase_price_alert_2_idx` (`purchase_order_item_id` ASC),
+  INDEX `fk_purchase_price_alert_3_idx` (`last_purchase_order_item_id` ASC),
+  INDEX `fk_purchase_price_alert_4_idx` (`item_variant_id` ASC),
+  INDEX `fk_purchase_price_alert_5_idx` (`partnership_id` ASC),
+  CONSTRAINT `fk_purchase_price_alert_1`
+    FOREIGN KEY (`purchase_order_id`)
+    REFERENCES `purchase_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_price_alert_2`
+    FOREIGN KEY (`purchase_order_item_id`)
+    REFERENCES `purchase_order_item` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_price_alert_3`
+    FOREIGN KEY (`last_purchase_order_item_id`)
+    REFERENCES `purchase_order_item` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_price_alert_4`
+    FOREIGN KEY (`item_variant_id`)
+    REFERENCES `item_variant` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_price_alert_5`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (28,'price_deviation_alert','10');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('212','3', 'Supplier Catalogue', 'supplier_catalogue', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('213','212', 'Read Supplier Catalogue', 'supplier_catalogue_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('214','212', 'Manage Supplier Catalogue', 'supplier_catalogue_manage', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('605','212', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('606','213', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('607','214', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('608','212', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('609','213', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('610','214', '2');
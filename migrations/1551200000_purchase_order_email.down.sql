@@ -0,0 +1,10 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `purchase_order_email`;
+
+ALTER TABLE `purchase_order`
+DROP COLUMN `sent_at`,
+DROP COLUMN `is_sent`;
+
+DELETE FROM `application_setting` WHERE `id` BETWEEN 29 AND 30;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 611 AND 612;
+DELETE FROM `application_module` WHERE `id` = 215;
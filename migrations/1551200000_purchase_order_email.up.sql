@@ -0,0 +1,43 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `purchase_order_email` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `purchase_order_id` BIGINT(20) UNSIGNED NOT NULL,
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `email` VARCHAR(45) NOT NULL,
+  `subject` VARCHAR(255) NULL DEFAULT NULL,
+  `status` ENUM('sent', 'failed') NOT NULL DEFAULT 'sent',
+  `note` TEXT NULL DEFAULT NULL COMMENT 'pesan error apabila email gagal dikirim',
+  `sent_by` BIGINT(20) UNSIGNED NOT NULL,
+  `sent_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_purchase_order_email_1_idx` (`purchase_order_id` ASC),
+  INDEX `fk_purchase_order_email_2_idx` (`partnership_id` ASC),
+  INDEX `fk_purchase_order_email_3_idx` (`sent_by` ASC),
+  CONSTRAINT `fk_purchase_order_email_1`
+    FOREIGN KEY (`purchase_order_id`)
+    REFERENCES `purchase_order` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_order_email_2`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_purchase_order_email_3`
+    FOREIGN KEY (`sent_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `purchase_order`
+ADD COLUMN `is_sent` TINYINT(1) NULL DEFAULT '0' AFTER `is_deleted`,
+ADD COLUMN `sent_at` TIMESTAMP NULL DEFAULT NULL AFTER `is_sent`;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (29,'purchase_order_email_subject','Purchase Order {{.OrderCode}} - {{.CompanyName}}');
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (30,'purchase_order_email_template','<p>Yth. {{.SupplierName}},</p><p>Terlampir purchase order {{.OrderCode}} tanggal {{.OrderDate}} dengan total Rp {{.TotalCharge}} dan tanggal kirim {{.EtaDate}}.</p><p>Mohon konfirmasi penerimaan purchase order ini.</p><p>Terima kasih,<br/>{{.CompanyName}}</p>');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('215','14', 'Send Purchase Order', 'purchase_order_send', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('611','215', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('612','215', '2');
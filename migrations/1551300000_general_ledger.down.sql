@@ -0,0 +1,8 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `journal_entry_line`;
+DROP TABLE IF EXISTS `journal_entry`;
+DROP TABLE IF EXISTS `chart_of_account`;
+
+DELETE FROM `application_setting` WHERE `id` = 31;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 613 AND 618;
+DELETE FROM `application_module` WHERE `id` BETWEEN 216 AND 218;
@@ -0,0 +1,110 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `chart_of_account` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `parent_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `code` VARCHAR(20) NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `account_type` ENUM('asset', 'liability', 'equity', 'revenue', 'expense') NOT NULL,
+  `normal_balance` ENUM('debit', 'credit') NOT NULL,
+  `is_archived` TINYINT(1) NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_chart_of_account_1_idx` (`parent_id` ASC),
+  CONSTRAINT `fk_chart_of_account_1`
+    FOREIGN KEY (`parent_id`)
+    REFERENCES `chart_of_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `journal_entry` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `reversal_of_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'journal yang dibalik apabila dokumen sumber dicancel',
+  `code` VARCHAR(45) NOT NULL,
+  `recognition_date` DATE NOT NULL,
+  `ref_type` VARCHAR(45) NOT NULL,
+  `ref_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `note` TEXT NULL DEFAULT NULL,
+  `total_debit` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `total_credit` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_journal_entry_1_idx` (`reversal_of_id` ASC),
+  INDEX `ref_idx` (`ref_type` ASC, `ref_id` ASC),
+  INDEX `recognition_date_idx` (`recognition_date` ASC),
+  CONSTRAINT `fk_journal_entry_1`
+    FOREIGN KEY (`reversal_of_id`)
+    REFERENCES `journal_entry` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `journal_entry_line` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `journal_entry_id` BIGINT(20) UNSIGNED NOT NULL,
+  `chart_of_account_id` BIGINT(20) UNSIGNED NOT NULL,
+  `partnership_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `debit` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `credit` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_journal_entry_line_1_idx` (`journal_entry_id` ASC),
+  INDEX `fk_journal_entry_line_2_idx` (`chart_of_account_id` ASC),
+  INDEX `fk_journal_entry_line_3_idx` (`partnership_id` ASC),
+  CONSTRAINT `fk_journal_entry_line_1`
+    FOREIGN KEY (`journal_entry_id`)
+    REFERENCES `journal_entry` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_journal_entry_line_2`
+    FOREIGN KEY (`chart_of_account_id`)
+    REFERENCES `chart_of_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_journal_entry_line_3`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (1,NULL,'1000','Aset','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (2,NULL,'2000','Kewajiban','liability','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (3,NULL,'3000','Ekuitas','equity','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (4,NULL,'4000','Pendapatan','revenue','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (5,NULL,'5000','Harga Pokok Penjualan','expense','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (6,NULL,'6000','Beban','expense','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (7,1,'1101','Kas','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (8,1,'1102','Bank','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (9,1,'1201','Piutang Usaha','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (10,1,'1202','Piutang Retur Pembelian','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (11,1,'1203','Debit Note Supplier','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (12,1,'1301','Persediaan Barang','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (13,1,'1401','PPN Masukan','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (14,2,'2101','Hutang Usaha','liability','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (15,2,'2102','Barang Diterima Belum Ditagih','liability','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (16,2,'2103','Hutang Retur Penjualan','liability','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (17,2,'2104','Credit Note Customer','liability','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (18,2,'2201','PPN Keluaran','liability','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (19,3,'3101','Modal','equity','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (20,3,'3201','Laba Ditahan','equity','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (21,4,'4101','Penjualan','revenue','credit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (22,4,'4102','Retur Penjualan','revenue','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (23,5,'5101','Harga Pokok Penjualan','expense','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (24,6,'6101','Beban Operasional','expense','debit');
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (31,'code_journal_entry','{"code_prefix":"JE-%6d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('216','6', 'General Ledger', 'general_ledger', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('217','216', 'Read General Ledger', 'general_ledger_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('218','216', 'Manage Chart of Account', 'general_ledger_manage', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('613','216', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('614','217', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('615','218', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('616','216', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('617','217', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('618','218', '2');
@@ -0,0 +1,6 @@
+SET FOREIGN_KEY_CHECKS = 0;
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('219','7', 'Financial Statement Report', 'report_financial_statement', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('619','219', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('620','219', '2');
@@ -0,0 +1,8 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `operating_expense`;
+DROP TABLE IF EXISTS `expense_category`;
+
+DELETE FROM `chart_of_account` WHERE `id` BETWEEN 25 AND 27;
+DELETE FROM `application_setting` WHERE `id` = 32;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 621 AND 630;
+DELETE FROM `application_module` WHERE `id` BETWEEN 220 AND 224;
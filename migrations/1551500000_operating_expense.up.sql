@@ -0,0 +1,108 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `expense_category` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chart_of_account_id` BIGINT(20) UNSIGNED NOT NULL,
+  `name` VARCHAR(100) NOT NULL,
+  `note` TEXT NULL DEFAULT NULL,
+  `is_archived` TINYINT(1) NULL DEFAULT '0',
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_expense_category_1_idx` (`chart_of_account_id` ASC),
+  CONSTRAINT `fk_expense_category_1`
+    FOREIGN KEY (`chart_of_account_id`)
+    REFERENCES `chart_of_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `operating_expense` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `recurring_of_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'operating expense berulang yang membuat expense ini',
+  `expense_category_id` BIGINT(20) UNSIGNED NOT NULL,
+  `bank_account_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `code` VARCHAR(45) NOT NULL,
+  `recognition_date` DATE NOT NULL,
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `payment_method` ENUM('cash', 'bank') NOT NULL DEFAULT 'cash',
+  `attachment` TEXT NULL DEFAULT NULL,
+  `note` TEXT NULL DEFAULT NULL,
+  `document_status` ENUM('new', 'approved', 'cancelled') NOT NULL DEFAULT 'new',
+  `is_recurring` TINYINT(1) NULL DEFAULT '0',
+  `recurring_interval` VARCHAR(10) NULL DEFAULT NULL COMMENT 'weekly, monthly atau yearly',
+  `next_recurring_date` DATE NULL DEFAULT NULL,
+  `recurring_end_date` DATE NULL DEFAULT NULL,
+  `approved_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `approved_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_operating_expense_1_idx` (`recurring_of_id` ASC),
+  INDEX `fk_operating_expense_2_idx` (`expense_category_id` ASC),
+  INDEX `fk_operating_expense_3_idx` (`bank_account_id` ASC),
+  INDEX `fk_operating_expense_4_idx` (`approved_by` ASC),
+  INDEX `fk_operating_expense_5_idx` (`created_by` ASC),
+  INDEX `fk_operating_expense_6_idx` (`updated_by` ASC),
+  INDEX `recognition_date_idx` (`recognition_date` ASC),
+  CONSTRAINT `fk_operating_expense_1`
+    FOREIGN KEY (`recurring_of_id`)
+    REFERENCES `operating_expense` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_operating_expense_2`
+    FOREIGN KEY (`expense_category_id`)
+    REFERENCES `expense_category` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_operating_expense_3`
+    FOREIGN KEY (`bank_account_id`)
+    REFERENCES `bank_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_operating_expense_4`
+    FOREIGN KEY (`approved_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_operating_expense_5`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_operating_expense_6`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (25,6,'6102','Beban Sewa','expense','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (26,6,'6103','Beban Gaji','expense','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (27,6,'6104','Beban Bahan Bakar','expense','debit');
+
+INSERT INTO `expense_category` (`id`,`chart_of_account_id`,`name`) VALUES (1,25,'Sewa');
+INSERT INTO `expense_category` (`id`,`chart_of_account_id`,`name`) VALUES (2,26,'Gaji');
+INSERT INTO `expense_category` (`id`,`chart_of_account_id`,`name`) VALUES (3,27,'Bahan Bakar');
+INSERT INTO `expense_category` (`id`,`chart_of_account_id`,`name`) VALUES (4,24,'Lain-lain');
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (32,'code_operating_expense','{"code_prefix":"OE-%6d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('220','6', 'Operating Expense', 'operating_expense', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('221','220', 'Read Operating Expense', 'operating_expense_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('222','220', 'Manage Operating Expense', 'operating_expense_manage', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('223','220', 'Approve Operating Expense', 'operating_expense_approve', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('224','7', 'Operating Expense Report', 'report_operating_expense', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('621','220', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('622','221', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('623','222', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('624','223', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('625','224', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('626','220', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('627','221', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('628','222', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('629','223', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('630','224', '2');
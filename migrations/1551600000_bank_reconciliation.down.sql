@@ -0,0 +1,7 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `bank_statement_line`;
+DROP TABLE IF EXISTS `bank_statement`;
+
+DELETE FROM `application_setting` WHERE `id` BETWEEN 33 AND 34;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 631 AND 636;
+DELETE FROM `application_module` WHERE `id` BETWEEN 225 AND 227;
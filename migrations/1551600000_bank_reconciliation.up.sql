@@ -0,0 +1,75 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `bank_statement` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `bank_account_id` BIGINT(20) UNSIGNED NOT NULL,
+  `code` VARCHAR(45) NOT NULL,
+  `file_name` VARCHAR(100) NULL DEFAULT NULL,
+  `format` ENUM('bca', 'mandiri', 'generic') NOT NULL DEFAULT 'generic',
+  `period_start` DATE NULL DEFAULT NULL,
+  `period_end` DATE NULL DEFAULT NULL,
+  `total_debit` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `total_credit` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `line_count` INT(11) NULL DEFAULT '0',
+  `matched_count` INT(11) NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_bank_statement_1_idx` (`bank_account_id` ASC),
+  INDEX `fk_bank_statement_2_idx` (`created_by` ASC),
+  CONSTRAINT `fk_bank_statement_1`
+    FOREIGN KEY (`bank_account_id`)
+    REFERENCES `bank_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_statement_2`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `bank_statement_line` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `bank_statement_id` BIGINT(20) UNSIGNED NOT NULL,
+  `transaction_date` DATE NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `reference` VARCHAR(100) NULL DEFAULT NULL,
+  `debit` DECIMAL(20,2) NOT NULL DEFAULT '0' COMMENT 'uang keluar dari rekening',
+  `credit` DECIMAL(20,2) NOT NULL DEFAULT '0' COMMENT 'uang masuk ke rekening',
+  `balance` DECIMAL(20,2) NULL DEFAULT NULL,
+  `status` ENUM('unmatched', 'matched', 'ignored') NOT NULL DEFAULT 'unmatched',
+  `ref_type` VARCHAR(45) NULL DEFAULT NULL COMMENT 'finance_revenue atau finance_expense yang dicocokkan',
+  `ref_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `matched_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `matched_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_bank_statement_line_1_idx` (`bank_statement_id` ASC),
+  INDEX `fk_bank_statement_line_2_idx` (`matched_by` ASC),
+  INDEX `ref_idx` (`ref_type` ASC, `ref_id` ASC),
+  CONSTRAINT `fk_bank_statement_line_1`
+    FOREIGN KEY (`bank_statement_id`)
+    REFERENCES `bank_statement` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_statement_line_2`
+    FOREIGN KEY (`matched_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (33,'code_bank_statement','{"code_prefix":"BS-%6d"}');
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (34,'bank_reconciliation_date_window','3');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('225','6', 'Bank Reconciliation', 'bank_reconciliation', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('226','225', 'Read Bank Reconciliation', 'bank_reconciliation_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('227','225', 'Manage Bank Reconciliation', 'bank_reconciliation_manage', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('631','225', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('632','226', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('633','227', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('634','225', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('635','226', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('636','227', '2');
@@ -0,0 +1,25 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DROP TABLE IF EXISTS `bank_transfer`;
+
+DELETE FROM `bank_account` WHERE `account_type` = 'cash';
+
+ALTER TABLE `finance_expense`
+DROP FOREIGN KEY `fk_finance_expense_bank_account`,
+DROP INDEX `fk_finance_expense_bank_account_idx`,
+DROP `bank_account_id`;
+
+ALTER TABLE `bank_account`
+DROP `account_type`,
+DROP `bank_holder`,
+DROP `opening_balance`,
+DROP `opening_date`,
+DROP `note`,
+DROP `is_archived`,
+DROP `created_by`,
+DROP `created_at`,
+DROP `updated_by`,
+DROP `updated_at`;
+
+DELETE FROM `application_setting` WHERE `id` = 35;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 637 AND 642;
+DELETE FROM `application_module` WHERE `id` BETWEEN 228 AND 230;
@@ -0,0 +1,79 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `bank_account`
+ADD COLUMN `account_type` ENUM('bank', 'cash') NOT NULL DEFAULT 'bank' COMMENT 'cash adalah kas di tangan' AFTER `id`,
+MODIFY COLUMN `bank_name` VARCHAR(45) NOT NULL,
+MODIFY COLUMN `bank_number` VARCHAR(45) NULL DEFAULT NULL,
+ADD COLUMN `bank_holder` VARCHAR(100) NULL DEFAULT NULL AFTER `bank_number`,
+ADD COLUMN `opening_balance` DECIMAL(20,2) NOT NULL DEFAULT '0' AFTER `bank_holder`,
+ADD COLUMN `opening_date` DATE NULL DEFAULT NULL AFTER `opening_balance`,
+ADD COLUMN `note` TEXT NULL DEFAULT NULL AFTER `is_default`,
+ADD COLUMN `is_archived` TINYINT(1) NOT NULL DEFAULT '0' AFTER `note`,
+ADD COLUMN `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `is_archived`,
+ADD COLUMN `created_at` TIMESTAMP NULL DEFAULT NULL AFTER `created_by`,
+ADD COLUMN `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL AFTER `created_at`,
+ADD COLUMN `updated_at` TIMESTAMP NULL DEFAULT NULL AFTER `updated_by`;
+
+ALTER TABLE `finance_expense`
+ADD COLUMN `bank_account_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'rekening sumber pembayaran non tunai',
+ADD INDEX `fk_finance_expense_bank_account_idx` (`bank_account_id` ASC),
+ADD CONSTRAINT `fk_finance_expense_bank_account`
+  FOREIGN KEY (`bank_account_id`)
+  REFERENCES `bank_account` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+CREATE TABLE IF NOT EXISTS `bank_transfer` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `from_bank_account_id` BIGINT(20) UNSIGNED NOT NULL,
+  `to_bank_account_id` BIGINT(20) UNSIGNED NOT NULL,
+  `code` VARCHAR(45) NOT NULL,
+  `transfer_date` DATE NOT NULL,
+  `amount` DECIMAL(20,2) NOT NULL DEFAULT '0',
+  `note` TEXT NULL DEFAULT NULL,
+  `document_status` ENUM('active', 'cancelled') NOT NULL DEFAULT 'active',
+  `created_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `cancelled_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `cancelled_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_bank_transfer_1_idx` (`from_bank_account_id` ASC),
+  INDEX `fk_bank_transfer_2_idx` (`to_bank_account_id` ASC),
+  INDEX `fk_bank_transfer_3_idx` (`created_by` ASC),
+  INDEX `fk_bank_transfer_4_idx` (`cancelled_by` ASC),
+  CONSTRAINT `fk_bank_transfer_1`
+    FOREIGN KEY (`from_bank_account_id`)
+    REFERENCES `bank_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_transfer_2`
+    FOREIGN KEY (`to_bank_account_id`)
+    REFERENCES `bank_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_transfer_3`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_bank_transfer_4`
+    FOREIGN KEY (`cancelled_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+INSERT INTO `bank_account` (`account_type`, `bank_name`, `is_default`, `created_at`) VALUES ('cash', 'Kas', '0', NOW());
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (35,'code_bank_transfer','{"code_prefix":"BT-%6d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('228','6', 'Bank Account', 'bank_account', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('229','228', 'Read Bank Account', 'bank_account_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('230','228', 'Manage Bank Account', 'bank_account_manage', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('637','228', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('638','229', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('639','230', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('640','228', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('641','229', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('642','230', '2');
@@ -0,0 +1,20 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `finance_revenue`
+DROP FOREIGN KEY `fk_finance_revenue_payment`,
+DROP INDEX `fk_finance_revenue_payment_idx`,
+DROP COLUMN `payment_id`;
+
+ALTER TABLE `finance_expense`
+DROP FOREIGN KEY `fk_finance_expense_payment`,
+DROP INDEX `fk_finance_expense_payment_idx`,
+DROP COLUMN `payment_id`;
+
+UPDATE `finance_expense` SET `payment_method` = 'debit_card' WHERE `payment_method` = 'transfer';
+ALTER TABLE `finance_expense` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro', 'debit_note') NULL DEFAULT 'cash';
+
+DROP TABLE IF EXISTS `payment`;
+
+DELETE FROM `chart_of_account` WHERE `id` IN (28, 29);
+DELETE FROM `application_setting` WHERE `id` = 36;
+DELETE FROM `application_privilege` WHERE `id` BETWEEN 643 AND 648;
+DELETE FROM `application_module` WHERE `id` BETWEEN 231 AND 233;
@@ -0,0 +1,81 @@
+SET FOREIGN_KEY_CHECKS = 0;
+CREATE TABLE IF NOT EXISTS `payment` (
+  `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(45) NOT NULL,
+  `payment_type` ENUM('receipt', 'disbursement') NOT NULL COMMENT 'receipt adalah penerimaan dari customer, disbursement adalah pembayaran ke supplier',
+  `partnership_id` BIGINT(20) UNSIGNED NOT NULL,
+  `bank_account_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'kosong apabila pembayaran cash',
+  `recognition_date` DATE NOT NULL,
+  `payment_method` ENUM('cash', 'transfer', 'giro') NOT NULL DEFAULT 'transfer',
+  `reference` VARCHAR(45) NULL DEFAULT NULL COMMENT 'nomor giro atau nomor referensi transfer',
+  `amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `allocated_amount` DECIMAL(20,0) NOT NULL DEFAULT '0' COMMENT 'jumlah yang sudah dialokasikan ke invoice',
+  `unapplied_amount` DECIMAL(20,0) NOT NULL DEFAULT '0',
+  `document_status` ENUM('unapplied', 'partial', 'applied') NOT NULL DEFAULT 'unapplied',
+  `note` TEXT NULL DEFAULT NULL,
+  `created_by` BIGINT(20) UNSIGNED NOT NULL,
+  `updated_by` BIGINT(20) UNSIGNED NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NULL DEFAULT NULL,
+  `updated_at` TIMESTAMP NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `code_UNIQUE` (`code` ASC),
+  INDEX `fk_payment_1_idx` (`partnership_id` ASC),
+  INDEX `fk_payment_2_idx` (`bank_account_id` ASC),
+  INDEX `fk_payment_3_idx` (`created_by` ASC),
+  INDEX `fk_payment_4_idx` (`updated_by` ASC),
+  CONSTRAINT `fk_payment_1`
+    FOREIGN KEY (`partnership_id`)
+    REFERENCES `partnership` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_payment_2`
+    FOREIGN KEY (`bank_account_id`)
+    REFERENCES `bank_account` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_payment_3`
+    FOREIGN KEY (`created_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION,
+  CONSTRAINT `fk_payment_4`
+    FOREIGN KEY (`updated_by`)
+    REFERENCES `user` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+ALTER TABLE `finance_revenue`
+ADD COLUMN `payment_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'alokasi dari dokumen payment' AFTER `credit_note_id`,
+ADD INDEX `fk_finance_revenue_payment_idx` (`payment_id` ASC),
+ADD CONSTRAINT `fk_finance_revenue_payment`
+  FOREIGN KEY (`payment_id`)
+  REFERENCES `payment` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+ALTER TABLE `finance_expense` CHANGE COLUMN `payment_method` `payment_method` ENUM('cash', 'debit_card', 'credit_card', 'giro', 'debit_note', 'transfer') NULL DEFAULT 'cash';
+ALTER TABLE `finance_expense`
+ADD COLUMN `payment_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'alokasi dari dokumen payment' AFTER `bank_account_id`,
+ADD INDEX `fk_finance_expense_payment_idx` (`payment_id` ASC),
+ADD CONSTRAINT `fk_finance_expense_payment`
+  FOREIGN KEY (`payment_id`)
+  REFERENCES `payment` (`id`)
+  ON DELETE NO ACTION
+  ON UPDATE NO ACTION;
+
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (28,1,'1204','Uang Muka Pembelian','asset','debit');
+INSERT INTO `chart_of_account` (`id`,`parent_id`,`code`,`name`,`account_type`,`normal_balance`) VALUES (29,2,'2105','Uang Muka Pelanggan','liability','credit');
+
+INSERT INTO `application_setting` (`id`,`application_setting_name`,`value`) VALUES (36,'code_payment','{"code_prefix":"PAY-%6d"}');
+
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('231','6', 'Payment', 'payment', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('232','231', 'Read Payment', 'payment_read', '1');
+INSERT INTO `application_module` (`id`,`parent_module_id`, `module_name`, `alias`, `is_active`) VALUES ('233','231', 'Manage Payment', 'payment_manage', '1');
+
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('643','231', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('644','232', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('645','233', '1');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('646','231', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('647','232', '2');
+INSERT INTO `application_privilege` (`id`,`application_module_id`, `usergroup_id`) VALUES ('648','233', '2');
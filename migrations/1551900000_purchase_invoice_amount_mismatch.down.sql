@@ -0,0 +1,5 @@
+SET FOREIGN_KEY_CHECKS = 0;
+DELETE FROM `purchase_invoice_mismatch` WHERE `mismatch_type` = 'amount';
+ALTER TABLE `purchase_invoice_mismatch`
+CHANGE COLUMN `purchase_invoice_item_id` `purchase_invoice_item_id` BIGINT(20) UNSIGNED NOT NULL,
+CHANGE COLUMN `mismatch_type` `mismatch_type` ENUM('price', 'quantity') NOT NULL COMMENT '\'price\' harga invoice berbeda dengan harga purchase order, \'quantity\' quantity invoice melebihi quantity yang diterima';
@@ -0,0 +1,4 @@
+SET FOREIGN_KEY_CHECKS = 0;
+ALTER TABLE `purchase_invoice_mismatch`
+CHANGE COLUMN `purchase_invoice_item_id` `purchase_invoice_item_id` BIGINT(20) UNSIGNED NULL DEFAULT NULL COMMENT 'kosong untuk mismatch amount yang berlaku untuk seluruh invoice',
+CHANGE COLUMN `mismatch_type` `mismatch_type` ENUM('price', 'quantity', 'amount') NOT NULL COMMENT '\'price\' harga invoice berbeda dengan harga purchase order, \'quantity\' quantity invoice melebihi quantity yang diterima, \'amount\' total amount invoice tidak sesuai dengan subtotal item';
@@ -11,6 +11,7 @@ import (
 
 	"git.qasico.com/mj/api/datastore/model"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/cuxs"
 	"git.qasico.com/cuxs/orm"
 	"github.com/dgrijalva/jwt-go"
@@ -171,3 +172,23 @@ func GetUsername(username string) (m *model.User, e error) {
 
 	return nil, e
 }
+
+// SalesPerson mengambil user sales person yang masih aktif dari encrypted id,
+// nil jika id kosong atau user tidak ditemukan.
+func SalesPerson(id string) *model.User {
+	if id == "" {
+		return nil
+	}
+
+	uid, e := common.Decrypt(id)
+	if e != nil {
+		return nil
+	}
+
+	u := &model.User{ID: uid, IsActive: int8(1)}
+	if e = u.Read("ID", "IsActive"); e != nil {
+		return nil
+	}
+
+	return u
+}
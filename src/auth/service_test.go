@@ -13,6 +13,7 @@ import (
 
 	"git.qasico.com/mj/api/datastore/model"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/cuxs"
 	"git.qasico.com/cuxs/orm"
 	"github.com/labstack/echo"
@@ -327,3 +328,19 @@ func TestGetUsername(t *testing.T) {
 	assert.NoError(t, e, "seharusnya tidak error")
 	assert.Equal(t, user.Username, m.Username)
 }
+
+func TestSalesPerson(t *testing.T) {
+	user := model.DummyUser()
+	user.IsActive = int8(1)
+	user.Save()
+
+	sp := SalesPerson(common.Encrypt(user.ID))
+	assert.NotNil(t, sp, "user aktif seharusnya ditemukan")
+	assert.Equal(t, user.ID, sp.ID)
+
+	user.IsActive = int8(0)
+	user.Save("is_active")
+	assert.Nil(t, SalesPerson(common.Encrypt(user.ID)), "user tidak aktif seharusnya tidak ditemukan")
+	assert.Nil(t, SalesPerson(""))
+	assert.Nil(t, SalesPerson("invalid"))
+}
@@ -6,6 +6,7 @@ package bankAccount_test
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"testing"
 
@@ -13,6 +14,7 @@ import (
 	"git.qasico.com/mj/api/src/auth"
 	"git.qasico.com/mj/api/test"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/common/tester"
 	"github.com/stretchr/testify/assert"
 )
@@ -24,7 +26,7 @@ func TestMain(m *testing.M) {
 	res := m.Run()
 
 	// cleanup
-	test.DataCleanUp()
+	test.DataCleanUp("bank_transfer", "payment", "journal_entry_line", "journal_entry")
 
 	os.Exit(res)
 }
@@ -38,6 +40,10 @@ func TestARouting(t *testing.T) {
 	ba.BankName = "BCA"
 	ba.BankNumber = "1872987391723"
 	ba.Save("BankName")
+	id := common.Encrypt(ba.ID)
+
+	bt := model.DummyBankTransfer()
+	btID := common.Encrypt(bt.ID)
 
 	var routers = []struct {
 		endpoint string
@@ -45,6 +51,18 @@ func TestARouting(t *testing.T) {
 		expected int
 	}{
 		{"/v1/bank-account", "GET", 200},
+		{"/v1/bank-account/" + id, "GET", http.StatusOK},
+		{"/v1/bank-account/999999", "GET", http.StatusNotFound},
+		{"/v1/bank-account/" + id + "/ledger", "GET", http.StatusOK},
+		{"/v1/bank-account/" + id + "/default", "PUT", http.StatusOK},
+		{"/v1/bank-account/" + id + "/archive", "PUT", http.StatusOK},
+		{"/v1/bank-account/" + id + "/archive", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/bank-account/" + id + "/default", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/bank-account/transfer", "GET", http.StatusOK},
+		{"/v1/bank-account/transfer/" + btID, "GET", http.StatusOK},
+		{"/v1/bank-account/transfer/999999", "GET", http.StatusNotFound},
+		{"/v1/bank-account/transfer/" + btID + "/cancel", "PUT", http.StatusOK},
+		{"/v1/bank-account/transfer/" + btID + "/cancel", "PUT", http.StatusUnprocessableEntity},
 	}
 
 	ng := tester.New()
@@ -57,3 +75,75 @@ func TestARouting(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_CreateBankAccount(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	used := model.DummyBankAccount()
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"account_type": "bank", "bank_name": "BCA", "bank_number": "8730012345", "bank_holder": "PT MJ", "opening_balance": 2500000, "opening_date": "2019-01-01T00:00:00Z"}, http.StatusOK},
+		// nomor rekening sudah dipakai
+		{tester.D{"account_type": "bank", "bank_name": "BCA", "bank_number": used.BankNumber}, http.StatusUnprocessableEntity},
+		{tester.D{"account_type": "bank", "bank_name": "BCA"}, http.StatusUnprocessableEntity},
+		{tester.D{"account_type": "giro", "bank_name": "BCA", "bank_number": "8730054321"}, http.StatusUnprocessableEntity},
+		{tester.D{"account_type": "bank", "bank_number": "8730054321"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/bank-account").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	// tipe rekening tidak bisa diubah
+	ng.PUT("/v1/bank-account/"+common.Encrypt(used.ID)).
+		SetJSON(tester.D{"account_type": "cash", "bank_name": "Kas"}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+}
+
+func TestHandler_CreateTransfer(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	from := common.Encrypt(model.DummyBankAccount().ID)
+	to := common.Encrypt(model.DummyBankAccount().ID)
+	archived := model.DummyBankAccount()
+	archived.IsArchived = 1
+	archived.Save()
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"from_bank_account_id": from, "to_bank_account_id": to, "transfer_date": "2019-01-10T00:00:00Z", "amount": 150000}, http.StatusOK},
+		// rekening asal dan tujuan sama
+		{tester.D{"from_bank_account_id": from, "to_bank_account_id": from, "transfer_date": "2019-01-10T00:00:00Z", "amount": 150000}, http.StatusUnprocessableEntity},
+		// rekening tujuan sudah diarsipkan
+		{tester.D{"from_bank_account_id": from, "to_bank_account_id": common.Encrypt(archived.ID), "transfer_date": "2019-01-10T00:00:00Z", "amount": 150000}, http.StatusUnprocessableEntity},
+		{tester.D{"from_bank_account_id": from, "to_bank_account_id": to, "transfer_date": "2019-01-10T00:00:00Z", "amount": 0}, http.StatusUnprocessableEntity},
+		{tester.D{"from_bank_account_id": from, "to_bank_account_id": "999999", "transfer_date": "2019-01-10T00:00:00Z", "amount": 150000}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/bank-account/transfer").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
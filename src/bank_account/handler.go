@@ -8,16 +8,27 @@ import (
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/cuxs"
 	"github.com/labstack/echo"
 )
 
-// Handler collection handler for category.
+// Handler collection handler for bank account.
 type Handler struct{}
 
 // URLMapping declare endpoint with handler function.
 func (h *Handler) URLMapping(r *echo.Group) {
-	r.GET("", h.get, auth.CheckPrivilege("category_read"))
+	r.GET("/transfer", h.getTransfer, auth.CheckPrivilege("bank_account_read"))
+	r.GET("/transfer/:id", h.showTransfer, auth.CheckPrivilege("bank_account_read"))
+	r.POST("/transfer", h.createTransfer, auth.CheckPrivilege("bank_account_manage"))
+	r.PUT("/transfer/:id/cancel", h.cancelTransfer, auth.CheckPrivilege("bank_account_manage"))
+	r.GET("", h.get, auth.CheckPrivilege("bank_account_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("bank_account_read"))
+	r.GET("/:id/ledger", h.ledger, auth.CheckPrivilege("bank_account_read"))
+	r.POST("", h.create, auth.CheckPrivilege("bank_account_manage"))
+	r.PUT("/:id", h.update, auth.CheckPrivilege("bank_account_manage"))
+	r.PUT("/:id/archive", h.archive, auth.CheckPrivilege("bank_account_manage"))
+	r.PUT("/:id/default", h.setDefault, auth.CheckPrivilege("bank_account_manage"))
 }
 
 // get endpoint to handle get http method.
@@ -32,3 +43,203 @@ func (h *Handler) get(c echo.Context) (e error) {
 	}
 	return ctx.Serve(e)
 }
+
+// show endpoint to handle get http method with id.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.BankAccount
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowBankAccount("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// ledger endpoint untuk melihat buku rekening beserta saldo berjalan pada periode start_date sampai end_date.
+func (h *Handler) ledger(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.BankAccount
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowBankAccount("id", id); e == nil {
+			param, _ := ctx.FormParams()
+
+			var l *Ledger
+			if l, e = GetLedger(m, param.Get("start_date"), param.Get("end_date")); e == nil {
+				ctx.Data(l)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk menambah rekening bank atau kas.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r accountRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				m, _ = ShowBankAccount("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// update endpoint untuk mengubah rekening yang belum diarsipkan.
+func (h *Handler) update(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r accountRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.BankAccount, e = ShowBankAccount("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save(); e == nil {
+						m, _ = ShowBankAccount("id", m.ID)
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// archive endpoint untuk mengarsipkan rekening yang sudah tidak dipakai.
+func (h *Handler) archive(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	r := statusRequest{Status: "archived"}
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.BankAccount, e = ShowBankAccount("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = Archive(r.BankAccount, r.Session.User); e == nil {
+						ctx.Data(r.BankAccount)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// setDefault endpoint untuk menjadikan rekening sebagai rekening default.
+func (h *Handler) setDefault(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	r := statusRequest{Status: "default"}
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.BankAccount, e = ShowBankAccount("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = SetDefault(r.BankAccount, r.Session.User); e == nil {
+						ctx.Data(r.BankAccount)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// getTransfer endpoint to handle get http method.
+func (h *Handler) getTransfer(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.BankTransfer
+	if data, total, e = GetBankTransfers(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// showTransfer endpoint to handle get http method with id.
+func (h *Handler) showTransfer(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.BankTransfer
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowBankTransfer("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// createTransfer endpoint untuk mencatat pemindahan dana antar rekening termasuk kas di tangan.
+func (h *Handler) createTransfer(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r transferRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = CreateTransfer(m); e == nil {
+				m, _ = ShowBankTransfer("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// cancelTransfer endpoint untuk membatalkan pemindahan dana.
+func (h *Handler) cancelTransfer(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r cancelTransferRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.BankTransfer, e = ShowBankTransfer("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = CancelTransfer(r.BankTransfer, r.Session.User); e == nil {
+						ctx.Data(r.BankTransfer)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
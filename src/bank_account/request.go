@@ -0,0 +1,229 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankAccount
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/cuxs/validation"
+)
+
+// accountRequest data struct that stored request data when requesting an create or update bank account process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type accountRequest struct {
+	AccountType    string             `json:"account_type" valid:"required|in:bank,cash"`
+	BankName       string             `json:"bank_name" valid:"required"`
+	BankNumber     string             `json:"bank_number"`
+	BankHolder     string             `json:"bank_holder"`
+	OpeningBalance float64            `json:"opening_balance"`
+	OpeningDate    time.Time          `json:"opening_date"`
+	Note           string             `json:"note"`
+	Session        *auth.SessionData  `json:"-"`
+	BankAccount    *model.BankAccount `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *accountRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.BankAccount != nil {
+		if r.BankAccount.IsArchived == int8(1) {
+			o.Failure("is_archived", "bank account is already archived")
+		}
+
+		if r.AccountType != r.BankAccount.AccountType {
+			o.Failure("account_type", "account_type can't be changed")
+		}
+	}
+
+	if r.AccountType == "bank" {
+		if r.BankNumber == "" {
+			o.Failure("bank_number", "bank_number is required")
+		} else {
+			q := orm.NewOrm().QueryTable(new(model.BankAccount)).Filter("account_type", "bank").Filter("bank_number", r.BankNumber)
+			if r.BankAccount != nil {
+				q = q.Exclude("id", r.BankAccount.ID)
+			}
+
+			if q.Exist() {
+				o.Failure("bank_number", "bank_number is already used by another bank account")
+			}
+		}
+	} else if r.BankAccount == nil {
+		// kas di tangan hanya boleh satu akun
+		if _, e := CashAccount(); e == nil {
+			o.Failure("account_type", "cash account is already exists")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *accountRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *accountRequest) Transform() *model.BankAccount {
+	m := r.BankAccount
+	if m == nil {
+		m = &model.BankAccount{
+			AccountType: r.AccountType,
+			CreatedBy:   r.Session.User,
+			CreatedAt:   time.Now(),
+		}
+	} else {
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	m.BankName = r.BankName
+	m.BankNumber = r.BankNumber
+	m.BankHolder = r.BankHolder
+	m.OpeningBalance = r.OpeningBalance
+	m.OpeningDate = r.OpeningDate
+	m.Note = r.Note
+
+	return m
+}
+
+// statusRequest data struct that stored request data when requesting an archive
+// or set default bank account process, status adalah status yang dituju.
+type statusRequest struct {
+	Status      string             `json:"-"`
+	Session     *auth.SessionData  `json:"-"`
+	BankAccount *model.BankAccount `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *statusRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.BankAccount.IsArchived == int8(1) {
+		o.Failure("is_archived", "bank account is already archived")
+	}
+
+	switch r.Status {
+	case "archived":
+		if r.BankAccount.AccountType == "cash" {
+			o.Failure("account_type", "cash account can't be archived")
+		} else if r.BankAccount.Balance != 0 {
+			o.Failure("balance", "bank account that still has balance can't be archived")
+		}
+	case "default":
+		if r.BankAccount.AccountType != "bank" {
+			o.Failure("account_type", "only bank account can be set as default")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *statusRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// transferRequest data struct that stored request data when requesting an create bank transfer process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type transferRequest struct {
+	FromBankAccountID string            `json:"from_bank_account_id" valid:"required"`
+	ToBankAccountID   string            `json:"to_bank_account_id" valid:"required"`
+	TransferDate      time.Time         `json:"transfer_date" valid:"required"`
+	Amount            float64           `json:"amount" valid:"required|gt:0"`
+	Note              string            `json:"note"`
+	Session           *auth.SessionData `json:"-"`
+
+	FromBankAccount *model.BankAccount `json:"-"`
+	ToBankAccount   *model.BankAccount `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *transferRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	r.FromBankAccount = validAccount(o, "from_bank_account_id", r.FromBankAccountID)
+	r.ToBankAccount = validAccount(o, "to_bank_account_id", r.ToBankAccountID)
+
+	if r.FromBankAccount != nil && r.ToBankAccount != nil && r.FromBankAccount.ID == r.ToBankAccount.ID {
+		o.Failure("to_bank_account_id", "to_bank_account_id must be different with from_bank_account_id")
+	}
+
+	return o
+}
+
+// validAccount memastikan rekening transfer ada dan belum diarsipkan.
+func validAccount(o *validation.Output, key string, value string) *model.BankAccount {
+	if value == "" {
+		return nil
+	}
+
+	id, e := common.Decrypt(value)
+	if e != nil {
+		o.Failure(key, key+" is not valid")
+		return nil
+	}
+
+	m := &model.BankAccount{ID: id}
+	if e = m.Read(); e != nil || m.IsArchived == int8(1) {
+		o.Failure(key, key+" is already archived or does not exists")
+		return nil
+	}
+
+	return m
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *transferRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *transferRequest) Transform() *model.BankTransfer {
+	return &model.BankTransfer{
+		FromBankAccount: r.FromBankAccount,
+		ToBankAccount:   r.ToBankAccount,
+		TransferDate:    r.TransferDate,
+		Amount:          r.Amount,
+		Note:            r.Note,
+		CreatedBy:       r.Session.User,
+	}
+}
+
+// cancelTransferRequest data struct that stored request data when requesting an cancel bank transfer process.
+type cancelTransferRequest struct {
+	Session      *auth.SessionData   `json:"-"`
+	BankTransfer *model.BankTransfer `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *cancelTransferRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.BankTransfer.DocumentStatus == "cancelled" {
+		o.Failure("document_status", "bank transfer is already cancelled")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *cancelTransferRequest) Messages() map[string]string {
+	return map[string]string{}
+}
@@ -5,11 +5,57 @@
 package bankAccount
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
+	"git.qasico.com/mj/api/src/util"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/orm"
 )
 
+// Transaction baris transaksi pada buku rekening, amount in adalah uang masuk
+// dan amount out adalah uang keluar dari rekening.
+type Transaction struct {
+	Date        time.Time `json:"date"`
+	RefType     string    `json:"ref_type"`
+	RefID       int64     `json:"-"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	AmountIn    float64   `json:"amount_in"`
+	AmountOut   float64   `json:"amount_out"`
+	Balance     float64   `json:"balance"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, ref id akan diencrypt.
+func (m *Transaction) MarshalJSON() ([]byte, error) {
+	type Alias Transaction
+
+	return json.Marshal(&struct {
+		RefID string `json:"ref_id"`
+		*Alias
+	}{
+		RefID: common.Encrypt(m.RefID),
+		Alias: (*Alias)(m),
+	})
+}
+
+// Ledger buku rekening pada periode tertentu beserta saldo awal dan saldo akhir nya.
+type Ledger struct {
+	BankAccount    *model.BankAccount `json:"bank_account"`
+	StartDate      string             `json:"start_date"`
+	EndDate        string             `json:"end_date"`
+	OpeningBalance float64            `json:"opening_balance"`
+	TotalIn        float64            `json:"total_in"`
+	TotalOut       float64            `json:"total_out"`
+	ClosingBalance float64            `json:"closing_balance"`
+	Transactions   []*Transaction     `json:"transactions"`
+}
+
 // GetBankAccounts get all data item_category that matched with query request parameters.
 // returning slices of bank account, total data without limit and error.
 func GetBankAccounts(rq *orm.RequestQuery) (m *[]model.BankAccount, total int64, err error) {
@@ -24,9 +70,214 @@ func GetBankAccounts(rq *orm.RequestQuery) (m *[]model.BankAccount, total int64,
 	// get data requested
 	var mx []model.BankAccount
 	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		for i := range mx {
+			mx[i].Balance, _ = Balance(&mx[i], time.Now())
+		}
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowBankAccount untuk mengambil data detail bank account beserta saldo saat ini berdasarkan param
+func ShowBankAccount(field string, values ...interface{}) (*model.BankAccount, error) {
+	m := new(model.BankAccount)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	m.Balance, _ = Balance(m, time.Now())
+
+	return m, nil
+}
+
+// CashAccount mengambil akun kas di tangan, transaksi dengan metode pembayaran cash dicatat pada akun ini.
+func CashAccount() (*model.BankAccount, error) {
+	return ShowBankAccount("account_type", "cash")
+}
+
+// SetDefault menjadikan rekening sebagai rekening default, rekening default sebelumnya akan dilepas.
+func SetDefault(m *model.BankAccount, user *model.User) (e error) {
+	if _, e = orm.NewOrm().Raw("UPDATE bank_account SET is_default = 0 WHERE id != ?;", m.ID).Exec(); e != nil {
+		return e
+	}
+
+	m.IsDefault = 1
+	m.UpdatedBy = user
+	m.UpdatedAt = time.Now()
+
+	return m.Save("IsDefault", "UpdatedBy", "UpdatedAt")
+}
+
+// Archive mengarsipkan rekening sehingga tidak bisa dipilih lagi pada transaksi baru,
+// riwayat transaksi dan saldo rekening tetap bisa dilihat.
+func Archive(m *model.BankAccount, user *model.User) (e error) {
+	m.IsArchived = 1
+	m.IsDefault = 0
+	m.UpdatedBy = user
+	m.UpdatedAt = time.Now()
+
+	return m.Save("IsArchived", "IsDefault", "UpdatedBy", "UpdatedAt")
+}
+
+// GetBankTransfers get all data bank_transfer that matched with query request parameters.
+// returning slices of bank transfer, total data without limit and error.
+func GetBankTransfers(rq *orm.RequestQuery) (m *[]model.BankTransfer, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.BankTransfer))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.BankTransfer
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
 		return &mx, total, nil
 	}
 
 	// return error some thing went wrong
 	return nil, total, err
-}
\ No newline at end of file
+}
+
+// ShowBankTransfer untuk mengambil data detail bank transfer berdasarkan param
+func ShowBankTransfer(field string, values ...interface{}) (*model.BankTransfer, error) {
+	m := new(model.BankTransfer)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// CreateTransfer menyimpan pemindahan dana antar rekening dan membuat jurnal nya.
+func CreateTransfer(m *model.BankTransfer) (e error) {
+	if m.Code, e = util.CodeGen("code_bank_transfer", "bank_transfer"); e != nil {
+		return e
+	}
+
+	m.DocumentStatus = "active"
+	m.CreatedAt = time.Now()
+	if e = m.Save(); e == nil {
+		generalLedger.AutoPost("bank_transfer", m.ID)
+	}
+
+	return
+}
+
+// CancelTransfer membatalkan pemindahan dana, jurnal transfer akan dibalik.
+func CancelTransfer(m *model.BankTransfer, user *model.User) (e error) {
+	m.DocumentStatus = "cancelled"
+	m.CancelledBy = user
+	m.CancelledAt = time.Now()
+	if e = m.Save("DocumentStatus", "CancelledBy", "CancelledAt"); e == nil {
+		generalLedger.AutoReverse("bank_transfer", m.ID)
+	}
+
+	return
+}
+
+// Balance saldo rekening pada akhir tanggal tersebut, yaitu saldo awal rekening
+// ditambah uang masuk dan dikurangi uang keluar sejak tanggal saldo awal.
+func Balance(m *model.BankAccount, date time.Time) (balance float64, e error) {
+	start, end := m.OpeningDate.Format("2006-01-02"), date.Format("2006-01-02")
+	if start > end {
+		return 0, nil
+	}
+
+	e = orm.NewOrm().Raw("SELECT COALESCE(SUM(t.amount_in - t.amount_out), 0) FROM ("+transactionQuery(m)+") t "+
+		"WHERE t.date BETWEEN ? AND ?;", start, end).QueryRow(&balance)
+
+	return common.FloatPrecision(m.OpeningBalance+balance, 2), e
+}
+
+// GetLedger buku rekening pada periode tersebut dengan saldo berjalan setiap transaksi, periode default
+// adalah awal bulan sampai hari ini. Transaksi sebelum tanggal saldo awal rekening tidak dihitung
+// karena sudah termasuk saldo awal.
+func GetLedger(m *model.BankAccount, startDate string, endDate string) (l *Ledger, e error) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := now
+
+	if startDate != "" {
+		if start, e = time.Parse("2006-01-02", startDate); e != nil {
+			return nil, e
+		}
+	}
+
+	if endDate != "" {
+		if end, e = time.Parse("2006-01-02", endDate); e != nil {
+			return nil, e
+		}
+	}
+
+	l = &Ledger{BankAccount: m, StartDate: start.Format("2006-01-02"), EndDate: end.Format("2006-01-02")}
+	if l.StartDate <= m.OpeningDate.Format("2006-01-02") {
+		start = m.OpeningDate
+		l.OpeningBalance = m.OpeningBalance
+	} else if l.OpeningBalance, e = Balance(m, start.AddDate(0, 0, -1)); e != nil {
+		return nil, e
+	}
+
+	if _, e = orm.NewOrm().Raw("SELECT t.* FROM ("+transactionQuery(m)+") t WHERE t.date BETWEEN ? AND ? "+
+		"ORDER BY t.date, t.ref_type, t.ref_id;", start.Format("2006-01-02"), end.Format("2006-01-02")).QueryRows(&l.Transactions); e != nil {
+		return nil, e
+	}
+
+	balance := l.OpeningBalance
+	for _, t := range l.Transactions {
+		balance = common.FloatPrecision(balance+t.AmountIn-t.AmountOut, 2)
+		t.Balance = balance
+		l.TotalIn += t.AmountIn
+		l.TotalOut += t.AmountOut
+	}
+	l.ClosingBalance = balance
+
+	return l, nil
+}
+
+// transactionQuery query semua transaksi rekening dari finance revenue dan finance expense yang sudah cleared,
+// operating expense yang sudah diapprove, bank transfer serta payment, transaksi kas di tangan adalah transaksi
+// dengan metode pembayaran cash sedangkan transaksi bank adalah transaksi yang mencatat rekening tersebut.
+// Finance revenue dan finance expense hasil alokasi payment tidak dihitung karena uang nya tercatat pada payment.
+func transactionQuery(m *model.BankAccount) string {
+	revenue := fmt.Sprintf("fr.bank_account_id = %d", m.ID)
+	expense := fmt.Sprintf("fe.bank_account_id = %d", m.ID)
+	operating := fmt.Sprintf("oe.payment_method = 'bank' AND oe.bank_account_id = %d", m.ID)
+	payment := fmt.Sprintf("p.bank_account_id = %d", m.ID)
+	if m.AccountType == "cash" {
+		revenue = "fr.payment_method = 'cash'"
+		expense = "fe.payment_method = 'cash'"
+		operating = "oe.payment_method = 'cash'"
+		payment = "p.payment_method = 'cash'"
+	}
+
+	return "SELECT fr.recognition_date AS date, 'finance_revenue' AS ref_type, fr.id AS ref_id, " +
+		"COALESCE(si.code, pr.code, ir.code, '') AS code, COALESCE(fr.note, '') AS description, fr.amount AS amount_in, 0 AS amount_out " +
+		"FROM finance_revenue fr " +
+		"LEFT JOIN sales_invoice si ON fr.ref_type = 'sales_invoice' AND si.id = fr.ref_id " +
+		"LEFT JOIN purchase_return pr ON fr.ref_type = 'purchase_return' AND pr.id = fr.ref_id " +
+		"LEFT JOIN invoice_receipt ir ON fr.ref_type = 'invoice_receipt' AND ir.id = fr.ref_id " +
+		"WHERE fr.is_deleted = 0 AND fr.document_status = 'cleared' AND fr.payment_id IS NULL AND " + revenue + " " +
+		"UNION ALL " +
+		"SELECT fe.recognition_date, 'finance_expense', fe.id, COALESCE(pi.code, sr.code, ''), COALESCE(fe.note, ''), 0, fe.amount " +
+		"FROM finance_expense fe " +
+		"LEFT JOIN purchase_invoice pi ON fe.ref_type = 'purchase_invoice' AND pi.id = fe.ref_id " +
+		"LEFT JOIN sales_return sr ON fe.ref_type = 'sales_return' AND sr.id = fe.ref_id " +
+		"WHERE fe.is_deleted = 0 AND fe.document_status = 'cleared' AND fe.payment_id IS NULL AND " + expense + " " +
+		"UNION ALL " +
+		"SELECT oe.recognition_date, 'operating_expense', oe.id, oe.code, COALESCE(oe.note, ''), 0, oe.amount " +
+		"FROM operating_expense oe WHERE oe.document_status = 'approved' AND " + operating + " " +
+		"UNION ALL " +
+		"SELECT p.recognition_date, 'payment', p.id, p.code, COALESCE(p.note, ''), " +
+		"CASE WHEN p.payment_type = 'receipt' THEN p.amount ELSE 0 END, CASE WHEN p.payment_type = 'disbursement' THEN p.amount ELSE 0 END " +
+		"FROM payment p WHERE " + payment + " " +
+		"UNION ALL " +
+		fmt.Sprintf("SELECT bt.transfer_date, 'bank_transfer', bt.id, bt.code, COALESCE(bt.note, ''), "+
+			"CASE WHEN bt.to_bank_account_id = %d THEN bt.amount ELSE 0 END, CASE WHEN bt.from_bank_account_id = %d THEN bt.amount ELSE 0 END "+
+			"FROM bank_transfer bt WHERE bt.document_status = 'active' AND (bt.from_bank_account_id = %d OR bt.to_bank_account_id = %d)", m.ID, m.ID, m.ID, m.ID)
+}
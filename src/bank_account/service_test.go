@@ -6,6 +6,7 @@ package bankAccount
 
 import (
 	"testing"
+	"time"
 
 	"git.qasico.com/mj/api/datastore/model"
 
@@ -26,3 +27,143 @@ func TestGetBankAccount(t *testing.T) {
 	assert.NoError(t, e, "Data should be exists.")
 	assert.Equal(t, int64(1), tot)
 }
+
+func TestBalanceAndLedger(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2019, 1, d, 0, 0, 0, 0, time.Local) }
+
+	ba := model.DummyBankAccount()
+	ba.OpeningBalance = 1000000
+	ba.OpeningDate = date(1)
+	ba.Save()
+
+	cash := model.DummyBankAccount()
+	cash.AccountType = "cash"
+	cash.Save()
+
+	fr := model.DummyFinanceRevenue()
+	fr.BankAccount = ba
+	fr.PaymentMethod = "transfer"
+	fr.Amount = 500
+	fr.RecognitionDate = date(5)
+	fr.DocumentStatus = "cleared"
+	fr.IsDeleted = 0
+	fr.Save()
+
+	// revenue yang belum cleared belum mempengaruhi saldo
+	uncleared := model.DummyFinanceRevenue()
+	uncleared.BankAccount = ba
+	uncleared.Amount = 9999
+	uncleared.RecognitionDate = date(5)
+	uncleared.DocumentStatus = "uncleared"
+	uncleared.IsDeleted = 0
+	uncleared.Save()
+
+	fe := model.DummyFinanceExpense()
+	fe.BankAccount = ba
+	fe.PaymentMethod = "giro"
+	fe.Amount = 200
+	fe.RecognitionDate = date(6)
+	fe.DocumentStatus = "cleared"
+	fe.IsDeleted = 0
+	fe.Save()
+
+	oe := model.DummyOperatingExpense()
+	oe.BankAccount = ba
+	oe.PaymentMethod = "bank"
+	oe.Amount = 100
+	oe.RecognitionDate = date(7)
+	oe.DocumentStatus = "approved"
+	oe.Save()
+
+	bt := model.DummyBankTransfer()
+	bt.FromBankAccount = ba
+	bt.ToBankAccount = cash
+	bt.Amount = 50
+	bt.TransferDate = date(8)
+	bt.Save()
+
+	balance, e := Balance(ba, date(31))
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1000150), balance)
+
+	balance, _ = Balance(ba, date(5))
+	assert.Equal(t, float64(1000500), balance)
+
+	l, e := GetLedger(ba, "2019-01-06", "2019-01-31")
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1000500), l.OpeningBalance)
+	assert.Equal(t, 3, len(l.Transactions))
+	assert.Equal(t, "finance_expense", l.Transactions[0].RefType)
+	assert.Equal(t, float64(1000300), l.Transactions[0].Balance)
+	assert.Equal(t, float64(350), l.TotalOut)
+	assert.Equal(t, float64(1000150), l.ClosingBalance)
+
+	// transfer masuk ke kas di tangan
+	l, _ = GetLedger(cash, "2019-01-08", "2019-01-08")
+	transfers := 0
+	for _, tr := range l.Transactions {
+		if tr.RefType == "bank_transfer" {
+			transfers++
+			assert.Equal(t, float64(50), tr.AmountIn)
+		}
+	}
+	assert.Equal(t, 1, transfers)
+
+	// transfer yang dibatalkan tidak dihitung
+	assert.NoError(t, CancelTransfer(bt, model.DummyUser()))
+	balance, _ = Balance(ba, date(31))
+	assert.Equal(t, float64(1000200), balance)
+}
+
+func TestBalancePayment(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2019, 2, d, 0, 0, 0, 0, time.Local) }
+
+	ba := model.DummyBankAccount()
+	ba.OpeningBalance = 0
+	ba.OpeningDate = date(1)
+	ba.Save()
+
+	p := model.DummyPayment()
+	p.BankAccount = ba
+	p.Amount = 1000
+	p.RecognitionDate = date(3)
+	p.Save()
+
+	// alokasi payment tidak dihitung lagi karena uang nya sudah tercatat pada payment
+	fr := model.DummyFinanceRevenue()
+	fr.Payment = p
+	fr.BankAccount = ba
+	fr.PaymentMethod = "transfer"
+	fr.Amount = 600
+	fr.RecognitionDate = date(4)
+	fr.DocumentStatus = "cleared"
+	fr.IsDeleted = 0
+	fr.Save()
+
+	balance, e := Balance(ba, date(28))
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1000), balance)
+
+	l, _ := GetLedger(ba, "2019-02-01", "2019-02-28")
+	assert.Equal(t, 1, len(l.Transactions))
+	assert.Equal(t, "payment", l.Transactions[0].RefType)
+	assert.Equal(t, float64(1000), l.Transactions[0].AmountIn)
+}
+
+func TestSetDefaultAndArchive(t *testing.T) {
+	user := model.DummyUser()
+	first := model.DummyBankAccount()
+	second := model.DummyBankAccount()
+
+	assert.NoError(t, SetDefault(first, user))
+	assert.NoError(t, SetDefault(second, user))
+
+	first.Read()
+	assert.Equal(t, int8(0), first.IsDefault)
+	assert.Equal(t, int8(1), second.IsDefault)
+
+	assert.NoError(t, Archive(second, user))
+	second.Read()
+	assert.Equal(t, int8(1), second.IsArchived)
+	assert.Equal(t, int8(0), second.IsDefault)
+}
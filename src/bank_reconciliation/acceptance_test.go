@@ -0,0 +1,106 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankReconciliation_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("bank_statement_line", "bank_statement", "journal_entry_line", "journal_entry")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	l := model.DummyBankStatementLine()
+	id := common.Encrypt(l.BankStatement.ID)
+	lineID := common.Encrypt(l.ID)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/bank-reconciliation", "GET", http.StatusOK},
+		{"/v1/bank-reconciliation/" + id, "GET", http.StatusOK},
+		{"/v1/bank-reconciliation/999999", "GET", http.StatusNotFound},
+		{"/v1/bank-reconciliation/" + id + "/reconciliation", "GET", http.StatusOK},
+		{"/v1/bank-reconciliation/" + id + "/auto-match", "PUT", http.StatusOK},
+		{"/v1/bank-reconciliation/line/" + lineID + "/match", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/bank-reconciliation/line/" + lineID + "/ignore", "PUT", http.StatusOK},
+		{"/v1/bank-reconciliation/line/" + lineID + "/ignore", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/bank-reconciliation/line/999999/ignore", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_Import(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	baID := common.Encrypt(model.DummyBankAccount().ID)
+	mandiri := "Account No,Date,Val. Date,Transaction Code,Description,Description,Reference No.,Debit,Credit\n" +
+		"1230001234567,07/03/19,07/03/19,7001,TRANSFER,PT SUMBER JAYA,FT190307001,0.00,\"2,000,000.00\"\n"
+	generic := "2019-03-09;SETORAN;1.000.000,00\n"
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"bank_account_id": baID, "format": "mandiri", "file_name": "mutasi.csv", "content": mandiri}, http.StatusOK},
+		{tester.D{"bank_account_id": baID, "format": "generic", "content": generic, "mapping": tester.D{"date": 0, "description": []int{1}, "amount": 2, "delimiter": ";", "decimal_separator": ","}}, http.StatusOK},
+		// format generic tanpa mapping kolom nominal
+		{tester.D{"bank_account_id": baID, "format": "generic", "content": generic, "mapping": tester.D{"date": 0}}, http.StatusUnprocessableEntity},
+		// format generic tanpa mapping
+		{tester.D{"bank_account_id": baID, "format": "generic", "content": generic}, http.StatusUnprocessableEntity},
+		// file tidak memiliki baris transaksi
+		{tester.D{"bank_account_id": baID, "format": "mandiri", "content": "Account No,Date\n"}, http.StatusUnprocessableEntity},
+		{tester.D{"bank_account_id": baID, "format": "bri", "content": mandiri}, http.StatusUnprocessableEntity},
+		{tester.D{"bank_account_id": "999999", "format": "mandiri", "content": mandiri}, http.StatusUnprocessableEntity},
+		{tester.D{"format": "mandiri", "content": mandiri}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/bank-reconciliation").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
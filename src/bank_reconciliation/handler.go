@@ -0,0 +1,167 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankReconciliation
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for bank reconciliation.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("bank_reconciliation_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("bank_reconciliation_read"))
+	r.GET("/:id/reconciliation", h.reconciliation, auth.CheckPrivilege("bank_reconciliation_read"))
+	r.POST("", h.upload, auth.CheckPrivilege("bank_reconciliation_manage"))
+	r.PUT("/:id/auto-match", h.autoMatch, auth.CheckPrivilege("bank_reconciliation_manage"))
+	r.PUT("/line/:id/match", h.match, auth.CheckPrivilege("bank_reconciliation_manage"))
+	r.PUT("/line/:id/ignore", h.ignore, auth.CheckPrivilege("bank_reconciliation_manage"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.BankStatement
+	if data, total, e = GetBankStatements(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to handle get http method with id.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.BankStatement
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowBankStatement("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// reconciliation endpoint untuk melihat baris mutasi dan dokumen keuangan yang belum cocok.
+func (h *Handler) reconciliation(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.BankStatement
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowBankStatement("id", id); e == nil {
+			var r *Reconciliation
+			if r, e = GetReconciliation(m); e == nil {
+				ctx.Data(r)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// upload endpoint untuk mengimport file mutasi rekening, isi file dikirim sebagai text csv.
+func (h *Handler) upload(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	// kolom yang tidak dikirim pada mapping tetap dianggap tidak tersedia
+	r := importRequest{Mapping: &Mapping{Date: -1, Reference: -1, Debit: -1, Credit: -1, Amount: -1, Balance: -1}}
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = ImportStatement(m, r.Lines); e == nil {
+				m, _ = ShowBankStatement("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// autoMatch endpoint untuk mencocokkan ulang baris mutasi yang belum cocok secara otomatis.
+func (h *Handler) autoMatch(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.BankStatement
+	var sd *auth.SessionData
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if sd, e = auth.UserSession(ctx); e == nil {
+			if m, e = ShowBankStatement("id", id); e == nil {
+				if _, e = AutoMatch(m, sd.User); e == nil {
+					m, _ = ShowBankStatement("id", m.ID)
+					ctx.Data(m)
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// match endpoint untuk mencocokkan baris mutasi dengan finance revenue atau finance expense secara manual.
+func (h *Handler) match(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r matchRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.BankStatementLine, e = ShowBankStatementLine("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = Match(r.BankStatementLine, r.RefType, r.ID, r.Session.User); e == nil {
+						ctx.Data(r.BankStatementLine)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// ignore endpoint untuk menandai baris mutasi yang tidak perlu dicocokkan.
+func (h *Handler) ignore(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r ignoreRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.BankStatementLine, e = ShowBankStatementLine("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = Ignore(r.BankStatementLine, r.Session.User); e == nil {
+						ctx.Data(r.BankStatementLine)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
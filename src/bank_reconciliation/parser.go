@@ -0,0 +1,193 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankReconciliation
+
+import (
+	"encoding/csv"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+)
+
+// ErrEmptyStatement error apabila file mutasi tidak memiliki baris transaksi yang bisa dibaca.
+var ErrEmptyStatement = errors.New("bank statement does not contain any transaction")
+
+// Mapping posisi kolom pada file csv mutasi rekening, index kolom dimulai dari 0
+// dan kolom yang tidak tersedia diisi -1. Amount dipakai untuk file yang hanya memiliki
+// satu kolom nominal dengan tanda minus atau akhiran DB/CR sebagai penanda debit dan credit.
+type Mapping struct {
+	Date             int    `json:"date"`
+	Description      []int  `json:"description"`
+	Reference        int    `json:"reference"`
+	Debit            int    `json:"debit"`
+	Credit           int    `json:"credit"`
+	Amount           int    `json:"amount"`
+	Balance          int    `json:"balance"`
+	DateFormat       string `json:"date_format"`
+	Delimiter        string `json:"delimiter"`
+	DecimalSeparator string `json:"decimal_separator"`
+	SkipRows         int    `json:"skip_rows"`
+}
+
+// formats mapping bawaan untuk file mutasi yang diexport dari internet banking.
+var formats = map[string]Mapping{
+	// Tanggal Transaksi, Keterangan, Cabang, Jumlah (1,500,000.00 CR), Saldo
+	"bca": {Date: 0, Description: []int{1}, Reference: -1, Debit: -1, Credit: -1, Amount: 3, Balance: 4, DateFormat: "02/01/2006"},
+	// Account No, Date, Val. Date, Transaction Code, Description, Description, Reference No., Debit, Credit
+	"mandiri": {Date: 1, Description: []int{4, 5}, Reference: 6, Debit: 7, Credit: 8, Amount: -1, Balance: -1, DateFormat: "02/01/06"},
+}
+
+// bcaPeriod periode pada header file mutasi BCA, dipakai untuk melengkapi tahun transaksi.
+var bcaPeriod = regexp.MustCompile(`(\d{2})/(\d{2})/(\d{4})`)
+
+// Parse membaca isi file csv mutasi rekening menjadi baris mutasi sesuai format,
+// baris yang kolom tanggalnya tidak bisa dibaca seperti header dan footer akan dilewati.
+func Parse(content string, format string, mapping *Mapping) (lines []*model.BankStatementLine, e error) {
+	m, ok := formats[format]
+	if !ok {
+		if mapping == nil {
+			return nil, errors.New("column mapping is required for generic format")
+		}
+		m = *mapping
+	}
+
+	if m.DateFormat == "" {
+		m.DateFormat = "2006-01-02"
+	}
+
+	r := csv.NewReader(strings.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+	if m.Delimiter != "" {
+		r.Comma = []rune(m.Delimiter)[0]
+	}
+
+	var records [][]string
+	if records, e = r.ReadAll(); e != nil {
+		return nil, e
+	}
+
+	year := time.Now().Year()
+	for i, rec := range records {
+		if i < m.SkipRows {
+			continue
+		}
+
+		if format == "bca" && len(rec) > 0 && strings.Contains(strings.ToLower(strings.Join(rec, " ")), "periode") {
+			if p := bcaPeriod.FindStringSubmatch(strings.Join(rec, " ")); p != nil {
+				year, _ = strconv.Atoi(p[3])
+			}
+			continue
+		}
+
+		date, ok := parseDate(column(rec, m.Date), m.DateFormat, year)
+		if !ok {
+			continue
+		}
+
+		l := &model.BankStatementLine{
+			TransactionDate: date,
+			Reference:       column(rec, m.Reference),
+			Status:          "unmatched",
+		}
+
+		var desc []string
+		for _, d := range m.Description {
+			if v := column(rec, d); v != "" {
+				desc = append(desc, v)
+			}
+		}
+		l.Description = strings.Join(desc, " ")
+
+		if m.Amount >= 0 {
+			amount := parseAmount(column(rec, m.Amount), m.DecimalSeparator)
+			if amount < 0 {
+				l.Debit = -amount
+			} else {
+				l.Credit = amount
+			}
+		} else {
+			l.Debit = parseAmount(column(rec, m.Debit), m.DecimalSeparator)
+			l.Credit = parseAmount(column(rec, m.Credit), m.DecimalSeparator)
+		}
+		l.Balance = parseAmount(column(rec, m.Balance), m.DecimalSeparator)
+
+		if l.Debit == 0 && l.Credit == 0 {
+			continue
+		}
+
+		lines = append(lines, l)
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrEmptyStatement
+	}
+
+	return lines, nil
+}
+
+// column mengambil nilai kolom csv, kolom yang tidak ada dikembalikan kosong.
+func column(rec []string, i int) string {
+	if i < 0 || i >= len(rec) {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.Trim(rec[i], "'"))
+}
+
+// parseDate membaca tanggal transaksi sesuai layout, tanggal tanpa tahun
+// seperti pada mutasi BCA dilengkapi dengan tahun periode mutasi.
+func parseDate(value string, layout string, year int) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if t, e := time.ParseInLocation(layout, value, time.Local); e == nil {
+		return t, true
+	}
+
+	if t, e := time.ParseInLocation("02/01", value, time.Local); e == nil {
+		return time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.Local), true
+	}
+
+	return time.Time{}, false
+}
+
+// parseAmount membaca nominal mutasi, nominal dengan akhiran DB atau tanda minus dianggap negatif.
+// separator desimal default adalah titik, separator ribuan akan diabaikan.
+func parseAmount(value string, decimal string) float64 {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return 0
+	}
+
+	sign := float64(1)
+	if strings.HasSuffix(value, "DB") || strings.HasPrefix(value, "-") || (strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")) {
+		sign = -1
+	}
+
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "DB"), "CR")
+	value = strings.Trim(value, " -()")
+	value = strings.Replace(value, "RP", "", -1)
+	value = strings.Replace(value, " ", "", -1)
+	if decimal == "," {
+		value = strings.Replace(value, ".", "", -1)
+		value = strings.Replace(value, ",", ".", -1)
+	} else {
+		value = strings.Replace(value, ",", "", -1)
+	}
+
+	v, e := strconv.ParseFloat(value, 64)
+	if e != nil {
+		return 0
+	}
+
+	return sign * v
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankReconciliation
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// importRequest data struct that stored request data when requesting an import bank statement process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type importRequest struct {
+	BankAccountID string            `json:"bank_account_id" valid:"required"`
+	Format        string            `json:"format" valid:"required|in:bca,mandiri,generic"`
+	FileName      string            `json:"file_name"`
+	Content       string            `json:"content" valid:"required"`
+	Mapping       *Mapping          `json:"mapping"`
+	Note          string            `json:"note"`
+	Session       *auth.SessionData `json:"-"`
+
+	BankAccount *model.BankAccount         `json:"-"`
+	Lines       []*model.BankStatementLine `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *importRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.BankAccountID != "" {
+		if id, e := common.Decrypt(r.BankAccountID); e == nil {
+			r.BankAccount = &model.BankAccount{ID: id}
+			if e = r.BankAccount.Read(); e != nil {
+				o.Failure("bank_account_id", "bank_account_id does not exists")
+			}
+		} else {
+			o.Failure("bank_account_id", "bank_account_id is not valid")
+		}
+	}
+
+	if r.Format == "generic" {
+		if r.Mapping == nil || r.Mapping.Date < 0 {
+			o.Failure("mapping.date", "date column is required for generic format")
+		} else if r.Mapping.Amount < 0 && r.Mapping.Debit < 0 && r.Mapping.Credit < 0 {
+			o.Failure("mapping.amount", "amount or debit and credit column is required for generic format")
+		}
+	}
+
+	if o.Valid && r.Content != "" {
+		var e error
+		if r.Lines, e = Parse(r.Content, r.Format, r.Mapping); e != nil {
+			o.Failure("content", e.Error())
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *importRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *importRequest) Transform() *model.BankStatement {
+	return &model.BankStatement{
+		BankAccount: r.BankAccount,
+		FileName:    r.FileName,
+		Format:      r.Format,
+		Note:        r.Note,
+		CreatedBy:   r.Session.User,
+	}
+}
+
+// matchRequest data struct that stored request data when requesting an manual match bank statement line process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type matchRequest struct {
+	RefType           string                   `json:"ref_type" valid:"required|in:finance_revenue,finance_expense"`
+	RefID             string                   `json:"ref_id" valid:"required"`
+	Session           *auth.SessionData        `json:"-"`
+	BankStatementLine *model.BankStatementLine `json:"-"`
+
+	ID int64 `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *matchRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.BankStatementLine.Status != "unmatched" {
+		o.Failure("status", "bank statement line is already "+r.BankStatementLine.Status)
+	}
+
+	if r.RefID != "" {
+		id, e := common.Decrypt(r.RefID)
+		if e != nil {
+			o.Failure("ref_id", "ref_id is not valid")
+		} else {
+			r.ID = id
+			switch r.RefType {
+			case "finance_revenue":
+				fr := &model.FinanceRevenue{ID: id}
+				if e = fr.Read(); e != nil || fr.DocumentStatus != "uncleared" {
+					o.Failure("ref_id", "finance revenue is already cleared or does not exists")
+				} else if fr.Amount != r.BankStatementLine.Credit {
+					o.Failure("ref_id", "finance revenue amount does not match with bank statement line")
+				}
+			case "finance_expense":
+				fe := &model.FinanceExpense{ID: id}
+				if e = fe.Read(); e != nil || fe.DocumentStatus != "uncleared" {
+					o.Failure("ref_id", "finance expense is already cleared or does not exists")
+				} else if fe.Amount != r.BankStatementLine.Debit {
+					o.Failure("ref_id", "finance expense amount does not match with bank statement line")
+				}
+			}
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *matchRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// ignoreRequest data struct that stored request data when requesting an ignore bank statement line process.
+type ignoreRequest struct {
+	Session           *auth.SessionData        `json:"-"`
+	BankStatementLine *model.BankStatementLine `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *ignoreRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.BankStatementLine.Status != "unmatched" {
+		o.Failure("status", "bank statement line is already "+r.BankStatementLine.Status)
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *ignoreRequest) Messages() map[string]string {
+	return map[string]string{}
+}
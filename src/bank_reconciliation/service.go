@@ -0,0 +1,347 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankReconciliation
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/finance_expense"
+	"git.qasico.com/mj/api/src/finance_revenue"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/labstack/gommon/log"
+)
+
+// candidate finance revenue atau finance expense uncleared yang bisa dicocokkan dengan baris mutasi,
+// code adalah kode dokumen yang dibayar dan dipakai untuk mencocokkan referensi pada keterangan mutasi.
+type candidate struct {
+	ID              int64
+	RecognitionDate time.Time
+	Code            string
+	BankNumber      string
+}
+
+// GetBankStatements get all data bank_statement that matched with query request parameters.
+// returning slices of bank statement, total data without limit and error.
+func GetBankStatements(rq *orm.RequestQuery) (m *[]model.BankStatement, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.BankStatement))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.BankStatement
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowBankStatement untuk mengambil data detail bank statement beserta baris mutasi berdasarkan param
+func ShowBankStatement(field string, values ...interface{}) (*model.BankStatement, error) {
+	m := new(model.BankStatement)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.BankStatementLine)).Filter("bank_statement_id", m.ID).OrderBy("transaction_date", "id").All(&m.BankStatementLines)
+	for _, l := range m.BankStatementLines {
+		l.BankStatement = m
+	}
+
+	return m, nil
+}
+
+// ShowBankStatementLine untuk mengambil data baris mutasi berdasarkan param
+func ShowBankStatementLine(field string, values ...interface{}) (*model.BankStatementLine, error) {
+	m := new(model.BankStatementLine)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(2).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ImportStatement menyimpan bank statement beserta baris mutasinya,
+// setelah disimpan baris mutasi langsung dicocokkan secara otomatis.
+func ImportStatement(m *model.BankStatement, lines []*model.BankStatementLine) (e error) {
+	m.TotalDebit, m.TotalCredit = 0, 0
+	m.LineCount = len(lines)
+	for _, l := range lines {
+		m.TotalDebit += l.Debit
+		m.TotalCredit += l.Credit
+		if m.PeriodStart.IsZero() || l.TransactionDate.Before(m.PeriodStart) {
+			m.PeriodStart = l.TransactionDate
+		}
+		if l.TransactionDate.After(m.PeriodEnd) {
+			m.PeriodEnd = l.TransactionDate
+		}
+	}
+
+	if m.Code, e = util.CodeGen("code_bank_statement", "bank_statement"); e != nil {
+		return e
+	}
+	m.CreatedAt = time.Now()
+
+	if e = m.Save(); e != nil {
+		return e
+	}
+
+	for _, l := range lines {
+		l.BankStatement = m
+		if e = l.Save(); e != nil {
+			return e
+		}
+	}
+	m.BankStatementLines = lines
+
+	_, e = AutoMatch(m, m.CreatedBy)
+
+	return
+}
+
+// AutoMatch mencocokkan baris mutasi yang belum cocok dengan finance revenue untuk uang masuk
+// dan finance expense untuk uang keluar berdasarkan nominal dan rentang tanggal, apabila terdapat
+// lebih dari satu kandidat maka dipilih yang kode dokumennya tercantum pada keterangan mutasi,
+// baris yang kandidatnya tidak bisa dipastikan dibiarkan untuk dicocokkan secara manual.
+func AutoMatch(m *model.BankStatement, user *model.User) (matched int, e error) {
+	window := dateWindow()
+	for _, l := range m.BankStatementLines {
+		if l.Status != "unmatched" {
+			continue
+		}
+
+		refType := "finance_revenue"
+		var candidates []*candidate
+		if l.Credit > 0 {
+			candidates, e = revenueCandidates(m.BankAccount.ID, l.Credit, l.TransactionDate, window)
+		} else {
+			refType = "finance_expense"
+			candidates, e = expenseCandidates(m.BankAccount.ID, l.Debit, l.TransactionDate, window)
+		}
+
+		if e != nil {
+			return matched, e
+		}
+
+		if c := pick(l, candidates); c != nil {
+			if err := Match(l, refType, c.ID, user); err != nil {
+				log.Errorf("matching bank statement line %d to %s %d failed: %s", l.ID, refType, c.ID, err.Error())
+				continue
+			}
+			matched++
+		}
+	}
+
+	return matched, nil
+}
+
+// pick memilih kandidat yang referensinya tercantum pada mutasi dengan selisih tanggal terkecil,
+// apabila tidak ada referensi yang cocok kandidat hanya dipilih jika jumlahnya tepat satu.
+func pick(l *model.BankStatementLine, candidates []*candidate) (c *candidate) {
+	text := strings.ToLower(l.Description + " " + l.Reference)
+	for _, cd := range candidates {
+		if (cd.Code != "" && strings.Contains(text, strings.ToLower(cd.Code))) ||
+			(cd.BankNumber != "" && strings.Contains(text, strings.ToLower(cd.BankNumber))) {
+			if c == nil || dayDiff(cd.RecognitionDate, l.TransactionDate) < dayDiff(c.RecognitionDate, l.TransactionDate) {
+				c = cd
+			}
+		}
+	}
+
+	if c == nil && len(candidates) == 1 {
+		c = candidates[0]
+	}
+
+	return
+}
+
+// revenueCandidates finance revenue uncleared non tunai ke rekening tersebut dengan nominal sama.
+func revenueCandidates(bankAccountID int64, amount float64, date time.Time, window int) (m []*candidate, e error) {
+	_, e = orm.NewOrm().Raw("SELECT fr.id, fr.recognition_date, fr.bank_number, COALESCE(si.code, pr.code, '') AS code "+
+		"FROM finance_revenue fr "+
+		"LEFT JOIN sales_invoice si ON fr.ref_type = 'sales_invoice' AND si.id = fr.ref_id "+
+		"LEFT JOIN purchase_return pr ON fr.ref_type = 'purchase_return' AND pr.id = fr.ref_id "+
+		"WHERE fr.is_deleted = 0 AND fr.document_status = 'uncleared' AND fr.payment_method NOT IN ('cash', 'credit_note') "+
+		"AND (fr.bank_account_id = ? OR fr.bank_account_id IS NULL) AND fr.amount = ? AND fr.recognition_date BETWEEN ? AND ? "+
+		"ORDER BY fr.recognition_date, fr.id;", bankAccountID, amount,
+		date.AddDate(0, 0, -window).Format("2006-01-02"), date.AddDate(0, 0, window).Format("2006-01-02")).QueryRows(&m)
+
+	return
+}
+
+// expenseCandidates finance expense uncleared non tunai dari rekening tersebut dengan nominal sama.
+func expenseCandidates(bankAccountID int64, amount float64, date time.Time, window int) (m []*candidate, e error) {
+	_, e = orm.NewOrm().Raw("SELECT fe.id, fe.recognition_date, fe.bank_number, COALESCE(pi.code, sr.code, '') AS code "+
+		"FROM finance_expense fe "+
+		"LEFT JOIN purchase_invoice pi ON fe.ref_type = 'purchase_invoice' AND pi.id = fe.ref_id "+
+		"LEFT JOIN sales_return sr ON fe.ref_type = 'sales_return' AND sr.id = fe.ref_id "+
+		"WHERE fe.is_deleted = 0 AND fe.document_status = 'uncleared' AND fe.payment_method NOT IN ('cash', 'debit_note') "+
+		"AND (fe.bank_account_id = ? OR fe.bank_account_id IS NULL) AND fe.amount = ? AND fe.recognition_date BETWEEN ? AND ? "+
+		"ORDER BY fe.recognition_date, fe.id;", bankAccountID, amount,
+		date.AddDate(0, 0, -window).Format("2006-01-02"), date.AddDate(0, 0, window).Format("2006-01-02")).QueryRows(&m)
+
+	return
+}
+
+// Match mencocokkan baris mutasi dengan finance revenue atau finance expense dan mengubahnya menjadi cleared,
+// baris mutasi hanya ditandai cocok apabila dokumen berhasil dicleared.
+func Match(l *model.BankStatementLine, refType string, refID int64, user *model.User) (e error) {
+	switch refType {
+	case "finance_revenue":
+		fr := &model.FinanceRevenue{ID: refID}
+		if e = fr.Read(); e != nil {
+			return e
+		}
+		if fr.DocumentStatus == "cleared" {
+			return errors.New("finance revenue is already cleared")
+		}
+		if fr.BankAccount == nil {
+			fr.BankAccount = l.BankStatement.BankAccount
+			fr.Save("BankAccount")
+		}
+		e = financeRevenue.ApproveRevenue(fr)
+	case "finance_expense":
+		fe := &model.FinanceExpense{ID: refID}
+		if e = fe.Read(); e != nil {
+			return e
+		}
+		if fe.DocumentStatus == "cleared" {
+			return errors.New("finance expense is already cleared")
+		}
+		if fe.BankAccount == nil {
+			fe.BankAccount = l.BankStatement.BankAccount
+			fe.Save("BankAccount")
+		}
+		e = financeExpense.ApproveExpense(fe)
+	default:
+		return errors.New("ref type must be finance_revenue or finance_expense")
+	}
+
+	if e != nil {
+		return e
+	}
+
+	l.Status = "matched"
+	l.RefType = refType
+	l.RefID = uint64(refID)
+	l.MatchedBy = user
+	l.MatchedAt = time.Now()
+	if e = l.Save("Status", "RefType", "RefID", "MatchedBy", "MatchedAt"); e == nil {
+		e = countMatched(l.BankStatement.ID)
+	}
+
+	return
+}
+
+// Ignore menandai baris mutasi yang tidak perlu dicocokkan, misalnya biaya administrasi bank.
+func Ignore(l *model.BankStatementLine, user *model.User) (e error) {
+	l.Status = "ignored"
+	l.MatchedBy = user
+	l.MatchedAt = time.Now()
+
+	return l.Save("Status", "MatchedBy", "MatchedAt")
+}
+
+// countMatched menghitung ulang jumlah baris mutasi yang sudah cocok pada bank statement.
+func countMatched(id int64) (e error) {
+	_, e = orm.NewOrm().Raw("UPDATE bank_statement SET matched_count = (SELECT COUNT(*) FROM bank_statement_line "+
+		"WHERE bank_statement_id = ? AND status = 'matched') WHERE id = ?;", id, id).Exec()
+
+	return
+}
+
+// Reconciliation rekonsiliasi bank statement dengan catatan keuangan, berisi baris mutasi
+// yang belum cocok serta finance revenue dan finance expense uncleared pada periode mutasi.
+type Reconciliation struct {
+	BankStatement     *model.BankStatement       `json:"bank_statement"`
+	UnmatchedLines    []*model.BankStatementLine `json:"unmatched_lines"`
+	UnmatchedRevenues []*model.FinanceRevenue    `json:"unmatched_revenues"`
+	UnmatchedExpenses []*model.FinanceExpense    `json:"unmatched_expenses"`
+	TotalUnmatchedIn  float64                    `json:"total_unmatched_in"`
+	TotalUnmatchedOut float64                    `json:"total_unmatched_out"`
+	TotalUnclearedIn  float64                    `json:"total_uncleared_in"`
+	TotalUnclearedOut float64                    `json:"total_uncleared_out"`
+	MatchedCount      int                        `json:"matched_count"`
+	IgnoredCount      int                        `json:"ignored_count"`
+	IsFullyReconciled bool                       `json:"is_fully_reconciled"`
+}
+
+// GetReconciliation mengambil rekonsiliasi bank statement, finance revenue dan finance expense uncleared
+// diambil pada periode mutasi ditambah rentang tanggal pencocokan.
+func GetReconciliation(m *model.BankStatement) (r *Reconciliation, e error) {
+	r = &Reconciliation{BankStatement: m}
+	for _, l := range m.BankStatementLines {
+		switch l.Status {
+		case "matched":
+			r.MatchedCount++
+		case "ignored":
+			r.IgnoredCount++
+		default:
+			r.UnmatchedLines = append(r.UnmatchedLines, l)
+			r.TotalUnmatchedIn += l.Credit
+			r.TotalUnmatchedOut += l.Debit
+		}
+	}
+
+	window := dateWindow()
+	start := m.PeriodStart.AddDate(0, 0, -window).Format("2006-01-02")
+	end := m.PeriodEnd.AddDate(0, 0, window).Format("2006-01-02")
+
+	o := orm.NewOrm()
+	cond := orm.NewCondition()
+	cond = cond.And("is_deleted", 0).And("document_status", "uncleared").And("recognition_date__gte", start).And("recognition_date__lte", end)
+	bank := orm.NewCondition().And("bank_account_id", m.BankAccount.ID).Or("bank_account_id__isnull", true)
+	if _, e = o.QueryTable(new(model.FinanceRevenue)).SetCond(cond.AndCond(bank)).Exclude("payment_method__in", "cash", "credit_note").
+		OrderBy("recognition_date").All(&r.UnmatchedRevenues); e != nil {
+		return nil, e
+	}
+
+	if _, e = o.QueryTable(new(model.FinanceExpense)).SetCond(cond.AndCond(bank)).Exclude("payment_method__in", "cash", "debit_note").
+		OrderBy("recognition_date").All(&r.UnmatchedExpenses); e != nil {
+		return nil, e
+	}
+
+	for _, fr := range r.UnmatchedRevenues {
+		r.TotalUnclearedIn += fr.Amount
+	}
+	for _, fe := range r.UnmatchedExpenses {
+		r.TotalUnclearedOut += fe.Amount
+	}
+
+	r.IsFullyReconciled = len(r.UnmatchedLines) == 0
+
+	return r, nil
+}
+
+// dateWindow rentang hari antara tanggal mutasi dan tanggal finance revenue atau expense
+// yang masih dianggap cocok, diambil dari application_setting dengan default 3 hari.
+func dateWindow() int {
+	if s, e := util.GetApplicationSetting("application_setting_name", "bank_reconciliation_date_window"); e == nil {
+		if v, e := strconv.Atoi(strings.TrimSpace(s.Value)); e == nil && v >= 0 {
+			return v
+		}
+	}
+
+	return 3
+}
+
+// dayDiff selisih hari antara dua tanggal.
+func dayDiff(a time.Time, b time.Time) float64 {
+	return math.Abs(a.Sub(b).Hours() / 24)
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bankReconciliation
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	assert.Equal(t, float64(1500000), parseAmount("1,500,000.00 CR", ""))
+	assert.Equal(t, float64(-250000), parseAmount("250,000.00 DB", ""))
+	assert.Equal(t, float64(-75000), parseAmount("(75,000)", ""))
+	assert.Equal(t, float64(-1250.5), parseAmount("-1.250,50", ","))
+	assert.Equal(t, float64(0), parseAmount("", ""))
+}
+
+func TestParse(t *testing.T) {
+	bca := "Informasi Rekening - Mutasi Rekening\n" +
+		"No. rekening : ,'0123456789\n" +
+		"Periode : ,01/03/2019 - 31/03/2019\n" +
+		"Tanggal Transaksi,Keterangan,Cabang,Jumlah,Saldo\n" +
+		"'05/03,TRSF E-BANKING CR SI-000012,'0000,\"1,500,000.00 CR\",\"11,500,000.00\"\n" +
+		"'06/03,BIAYA ADM,'0000,\"10,000.00 DB\",\"11,490,000.00\"\n" +
+		"Saldo Awal,,,\"10,000,000.00\"\n"
+
+	lines, e := Parse(bca, "bca", nil)
+	assert.NoError(t, e)
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, "2019-03-05", lines[0].TransactionDate.Format("2006-01-02"))
+	assert.Equal(t, float64(1500000), lines[0].Credit)
+	assert.Equal(t, float64(11500000), lines[0].Balance)
+	assert.Equal(t, float64(10000), lines[1].Debit)
+
+	mandiri := "Account No,Date,Val. Date,Transaction Code,Description,Description,Reference No.,Debit,Credit\n" +
+		"1230001234567,07/03/19,07/03/19,7001,TRANSFER,PT SUMBER JAYA,FT190307001,0.00,\"2,000,000.00\"\n" +
+		"1230001234567,08/03/19,08/03/19,7002,TRANSFER,PI-000004,FT190308002,\"750,000.00\",0.00\n"
+
+	lines, e = Parse(mandiri, "mandiri", nil)
+	assert.NoError(t, e)
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, "TRANSFER PT SUMBER JAYA", lines[0].Description)
+	assert.Equal(t, "FT190307001", lines[0].Reference)
+	assert.Equal(t, float64(2000000), lines[0].Credit)
+	assert.Equal(t, float64(750000), lines[1].Debit)
+
+	generic := "tanggal;keterangan;nominal\n2019-03-09;SETORAN;1.000.000,00\n2019-03-10;TARIK TUNAI;-500.000,00\n"
+	mapping := &Mapping{Date: 0, Description: []int{1}, Reference: -1, Debit: -1, Credit: -1, Amount: 2, Balance: -1, Delimiter: ";", DecimalSeparator: ","}
+
+	lines, e = Parse(generic, "generic", mapping)
+	assert.NoError(t, e)
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, float64(1000000), lines[0].Credit)
+	assert.Equal(t, float64(500000), lines[1].Debit)
+
+	_, e = Parse("tanggal;keterangan;nominal\n", "generic", mapping)
+	assert.Equal(t, ErrEmptyStatement, e)
+
+	_, e = Parse(generic, "generic", nil)
+	assert.Error(t, e)
+}
+
+func TestImportStatement(t *testing.T) {
+	date := time.Date(2019, 3, 5, 0, 0, 0, 0, time.Local)
+	ba := model.DummyBankAccount()
+
+	si := model.DummySalesInvoice()
+	si.IsDeleted = 0
+	si.Save()
+
+	fr := model.DummyFinanceRevenue()
+	fr.RefType = "sales_invoice"
+	fr.RefID = uint64(si.ID)
+	fr.BankAccount = nil
+	fr.PaymentMethod = "transfer"
+	fr.Amount = 1234567
+	fr.RecognitionDate = date.AddDate(0, 0, -1)
+	fr.DocumentStatus = "uncleared"
+	fr.IsDeleted = 0
+	fr.Save()
+
+	m := &model.BankStatement{BankAccount: ba, Format: "generic", CreatedBy: model.DummyUser()}
+	lines := []*model.BankStatementLine{
+		{TransactionDate: date, Description: "TRANSFER MASUK", Credit: 1234567, Status: "unmatched"},
+		{TransactionDate: date.AddDate(0, 0, 1), Description: "BIAYA ADM", Debit: 7654321, Status: "unmatched"},
+	}
+
+	assert.NoError(t, ImportStatement(m, lines))
+	assert.NotEqual(t, "", m.Code)
+	assert.Equal(t, 2, m.LineCount)
+	assert.Equal(t, "2019-03-06", m.PeriodEnd.Format("2006-01-02"))
+
+	// mutasi masuk dicocokkan dengan finance revenue dengan nominal yang sama
+	assert.Equal(t, "matched", lines[0].Status)
+	assert.Equal(t, "finance_revenue", lines[0].RefType)
+	assert.Equal(t, uint64(fr.ID), lines[0].RefID)
+	assert.Equal(t, "unmatched", lines[1].Status)
+
+	fr.Read()
+	assert.Equal(t, "cleared", fr.DocumentStatus)
+	assert.Equal(t, ba.ID, fr.BankAccount.ID)
+
+	m, _ = ShowBankStatement("id", m.ID)
+	assert.Equal(t, 1, m.MatchedCount)
+
+	r, e := GetReconciliation(m)
+	assert.NoError(t, e)
+	assert.Equal(t, 1, r.MatchedCount)
+	assert.Equal(t, float64(7654321), r.TotalUnmatchedOut)
+	assert.False(t, r.IsFullyReconciled)
+
+	assert.NoError(t, Ignore(m.BankStatementLines[1], model.DummyUser()))
+	r, _ = GetReconciliation(m)
+	assert.Equal(t, 1, r.IgnoredCount)
+}
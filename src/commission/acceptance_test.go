@@ -0,0 +1,154 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package commission_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("commission_rule", "commission_rule_tier", "commission_statement", "commission_statement_item")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	rule := model.DummyCommissionRule()
+	st := model.DummyCommissionStatement()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/commission/rule", "GET", http.StatusOK},
+		{"/v1/commission/rule/" + common.Encrypt(rule.ID), "GET", http.StatusOK},
+		{"/v1/commission/rule/999999", "GET", http.StatusNotFound},
+		{"/v1/commission/statement", "GET", http.StatusOK},
+		{"/v1/commission/statement/" + common.Encrypt(st.ID), "GET", http.StatusOK},
+		{"/v1/commission/statement/999999", "GET", http.StatusNotFound},
+		{"/v1/commission/statement/" + common.Encrypt(st.ID) + "/approve", "PUT", http.StatusOK},
+		{"/v1/commission/statement/" + common.Encrypt(st.ID) + "/approve", "PUT", http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_CreateRule(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	category := model.DummyItemCategory()
+	category.IsDeleted = 0
+	category.Save()
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"name": "Komisi Elektronik", "basis": "margin", "item_category_id": common.Encrypt(category.ID), "is_active": 1,
+			"tiers": []tester.D{{"min_target": 0, "rate": 2}, {"min_target": 10000000, "rate": 3}}}, http.StatusOK},
+		{tester.D{"name": "Komisi Sales", "basis": "revenue", "sales_person_id": common.Encrypt(user.ID), "is_active": 1,
+			"tiers": []tester.D{{"min_target": 0, "rate": 1}}}, http.StatusOK},
+		// basis tidak valid
+		{tester.D{"name": "Komisi", "basis": "profit", "tiers": []tester.D{{"min_target": 0, "rate": 1}}}, http.StatusUnprocessableEntity},
+		// target tier duplikat
+		{tester.D{"name": "Komisi", "basis": "revenue", "tiers": []tester.D{{"min_target": 0, "rate": 1}, {"min_target": 0, "rate": 2}}}, http.StatusUnprocessableEntity},
+		// sales person tidak ada
+		{tester.D{"name": "Komisi", "basis": "revenue", "sales_person_id": "999999", "tiers": []tester.D{{"min_target": 0, "rate": 1}}}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/commission/rule").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
+
+func TestHandler_GenerateStatement(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"period": "2018-01", "sales_person_id": common.Encrypt(user.ID)}, http.StatusOK},
+		{tester.D{"period": "2018-01"}, http.StatusOK},
+		{tester.D{"period": "01-2018"}, http.StatusUnprocessableEntity},
+		{tester.D{"period": "2018-01", "sales_person_id": "999999"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/commission/statement").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
+
+func TestCommissionIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/commission/rule", "GET", http.StatusUnauthorized},
+		{"/v1/commission/statement", "GET", http.StatusUnauthorized},
+		{"/v1/commission/statement/999999/approve", "PUT", http.StatusUnauthorized},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package commission
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for commission.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/rule", h.getRule, auth.CheckPrivilege("commission_read"))
+	r.GET("/rule/:id", h.showRule, auth.CheckPrivilege("commission_read"))
+	r.POST("/rule", h.createRule, auth.CheckPrivilege("commission_manage"))
+	r.PUT("/rule/:id", h.updateRule, auth.CheckPrivilege("commission_manage"))
+	r.GET("/statement", h.getStatement, auth.CheckPrivilege("commission_read"))
+	r.GET("/statement/:id", h.showStatement, auth.CheckPrivilege("commission_read"))
+	r.POST("/statement", h.generate, auth.CheckPrivilege("commission_generate"))
+	r.PUT("/statement/:id/approve", h.approve, auth.CheckPrivilege("commission_generate"))
+}
+
+// getRule endpoint untuk mengambil semua commission rule.
+func (h *Handler) getRule(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.CommissionRule
+	if data, total, e = GetCommissionRules(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// showRule endpoint untuk mengambil detail commission rule beserta tier.
+func (h *Handler) showRule(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.CommissionRule
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowCommissionRule("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// createRule endpoint untuk membuat commission rule baru.
+func (h *Handler) createRule(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r ruleRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = SaveRule(m); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// updateRule endpoint untuk mengubah commission rule, tier lama diganti dengan tier pada request.
+func (h *Handler) updateRule(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r ruleRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.CommissionRule, e = ShowCommissionRule("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = SaveRule(m); e == nil {
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// getStatement endpoint untuk mengambil semua commission statement.
+func (h *Handler) getStatement(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.CommissionStatement
+	if data, total, e = GetCommissionStatements(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// showStatement endpoint untuk mengambil detail commission statement beserta item.
+func (h *Handler) showStatement(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.CommissionStatement
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowCommissionStatement("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// generate endpoint untuk membuat commission statement bulanan,
+// jika sales_person_id kosong statement dibuat untuk semua sales person.
+func (h *Handler) generate(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r generateRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			if r.SalesPersonID != "" {
+				var m *model.CommissionStatement
+				if m, e = GenerateStatement(auth.SalesPerson(r.SalesPersonID), r.PeriodDate, r.Session.User); e == nil {
+					ctx.Data(m)
+				}
+			} else {
+				var m []*model.CommissionStatement
+				if m, e = GenerateStatements(r.PeriodDate, r.Session.User); e == nil {
+					ctx.Data(m, int64(len(m)))
+				}
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// approve endpoint untuk approve commission statement.
+func (h *Handler) approve(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r approveRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.Statement, e = ShowCommissionStatement("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = ApproveStatement(r.Statement, r.Session.User); e == nil {
+						ctx.Data(r.Statement)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package commission
+
+import (
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// ruleRequest data struct that stored request data when requesting an create or update commission rule process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type ruleRequest struct {
+	Name           string                `json:"name" valid:"required|lte:45"`
+	Basis          string                `json:"basis" valid:"required|in:revenue,margin"`
+	SalesPersonID  string                `json:"sales_person_id"`
+	ItemCategoryID string                `json:"item_category_id"`
+	IsActive       int8                  `json:"is_active" valid:"in:0,1"`
+	Note           string                `json:"note"`
+	Tiers          []ruleTierRequest     `json:"tiers" valid:"required"`
+	Session        *auth.SessionData     `json:"-"`
+	CommissionRule *model.CommissionRule `json:"-"`
+}
+
+type ruleTierRequest struct {
+	MinTarget float64 `json:"min_target" valid:"gte:0"`
+	Rate      float64 `json:"rate" valid:"required|gt:0|lte:100"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *ruleRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.SalesPersonID != "" && auth.SalesPerson(r.SalesPersonID) == nil {
+		o.Failure("sales_person_id", "sales_person_id doesn't exist")
+	}
+
+	if r.ItemCategoryID != "" && itemCategory(r.ItemCategoryID) == nil {
+		o.Failure("item_category_id", "item_category_id doesn't exist")
+	}
+
+	// target minimal setiap tier tidak boleh sama
+	targets := make(map[float64]bool)
+	for i, t := range r.Tiers {
+		if targets[t.MinTarget] {
+			o.Failure(fmt.Sprintf("tiers.%d.min_target.duplicate", i), "min_target already used by another tier")
+		}
+		targets[t.MinTarget] = true
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *ruleRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *ruleRequest) Transform() *model.CommissionRule {
+	m := r.CommissionRule
+	if m == nil {
+		m = &model.CommissionRule{
+			CreatedBy: r.Session.User,
+			CreatedAt: time.Now(),
+		}
+	} else {
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	m.Name = r.Name
+	m.Basis = r.Basis
+	m.SalesPerson = auth.SalesPerson(r.SalesPersonID)
+	m.ItemCategory = itemCategory(r.ItemCategoryID)
+	m.IsActive = r.IsActive
+	m.Note = r.Note
+
+	m.Tiers = nil
+	for _, t := range r.Tiers {
+		m.Tiers = append(m.Tiers, &model.CommissionRuleTier{
+			MinTarget: common.FloatPrecision(t.MinTarget, 0),
+			Rate:      common.FloatPrecision(t.Rate, 2),
+		})
+	}
+
+	return m
+}
+
+// generateRequest data struct that stored request data when requesting an generate commission statement process.
+// sales_person_id kosong berarti statement dibuat untuk semua sales person.
+type generateRequest struct {
+	Period        string            `json:"period" valid:"required"`
+	SalesPersonID string            `json:"sales_person_id"`
+	Session       *auth.SessionData `json:"-"`
+	PeriodDate    time.Time         `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *generateRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	var e error
+	if r.PeriodDate, e = time.Parse("2006-01", r.Period); e != nil {
+		o.Failure("period", "period must be in YYYY-MM format")
+	}
+
+	if r.SalesPersonID != "" {
+		if sp := auth.SalesPerson(r.SalesPersonID); sp == nil {
+			o.Failure("sales_person_id", "sales_person_id doesn't exist")
+		} else if e == nil {
+			start, _ := PeriodRange(r.PeriodDate)
+			st := &model.CommissionStatement{SalesPerson: sp, Period: start}
+			if st.Read("SalesPerson", "Period") == nil && st.DocumentStatus == "approved" {
+				o.Failure("period", ErrStatementApproved.Error())
+			}
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *generateRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// approveRequest data struct that stored request data when requesting an approve commission statement process.
+type approveRequest struct {
+	Statement *model.CommissionStatement `json:"-"`
+	Session   *auth.SessionData          `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *approveRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.Statement.DocumentStatus != "new" {
+		o.Failure("document_status", "commission statement already approved")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *approveRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// itemCategory mengambil item category dari encrypted id, nil jika id kosong atau tidak ditemukan.
+func itemCategory(id string) *model.ItemCategory {
+	if id == "" {
+		return nil
+	}
+
+	cid, e := common.Decrypt(id)
+	if e != nil {
+		return nil
+	}
+
+	c := &model.ItemCategory{ID: cid, IsDeleted: int8(0)}
+	if e = c.Read("ID", "IsDeleted"); e != nil {
+		return nil
+	}
+
+	return c
+}
@@ -0,0 +1,510 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package commission
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// ErrStatementApproved error ketika statement komisi pada periode tersebut sudah diapprove.
+var ErrStatementApproved = errors.New("commission statement for this period already approved")
+
+// orderLine data sales order item beserta kategori item yang dipakai untuk perhitungan komisi.
+type orderLine struct {
+	ID            int64
+	SalesOrderID  int64
+	ItemVariantID int64
+	CategoryID    int64
+	TaxCodeID     int64
+	Quantity      float32
+	Subtotal      float64
+	TaxBase       float64
+}
+
+// commissionLine nominal revenue dan cost setiap item dari dokumen yang dihitung komisinya,
+// nominal untuk sales return bernilai negatif.
+type commissionLine struct {
+	RefID      uint64
+	RefType    string
+	RefCode    string
+	CategoryID int64
+	Revenue    float64
+	Cost       float64
+}
+
+// GetCommissionRules get all data commission_rule that matched with query request parameters.
+// returning slices of commission rule, total data without limit and error.
+func GetCommissionRules(rq *orm.RequestQuery) (m *[]model.CommissionRule, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.CommissionRule))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.CommissionRule
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowCommissionRule untuk mengambil data detail commission rule beserta tier berdasarkan param
+func ShowCommissionRule(field string, values ...interface{}) (*model.CommissionRule, error) {
+	m := new(model.CommissionRule)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel().Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.CommissionRuleTier)).Filter("commission_rule_id", m.ID).OrderBy("min_target").All(&m.Tiers)
+
+	return m, nil
+}
+
+// GetCommissionStatements get all data commission_statement that matched with query request parameters.
+// returning slices of commission statement, total data without limit and error.
+func GetCommissionStatements(rq *orm.RequestQuery) (m *[]model.CommissionStatement, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.CommissionStatement))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.CommissionStatement
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowCommissionStatement untuk mengambil data detail commission statement beserta item berdasarkan param
+func ShowCommissionStatement(field string, values ...interface{}) (*model.CommissionStatement, error) {
+	m := new(model.CommissionStatement)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel().Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.LoadRelated(m, "CommissionStatementItems", 1)
+
+	return m, nil
+}
+
+// SaveRule menyimpan commission rule beserta tier,
+// tier yang lama akan dihapus dan diganti dengan tier yang baru.
+func SaveRule(m *model.CommissionRule) (e error) {
+	if e = m.Save(); e == nil {
+		o := orm.NewOrm()
+		o.Raw("DELETE FROM commission_rule_tier WHERE commission_rule_id = ?", m.ID).Exec()
+
+		for _, t := range m.Tiers {
+			t.ID = 0
+			t.CommissionRule = &model.CommissionRule{ID: m.ID}
+			if e = t.Save(); e != nil {
+				return e
+			}
+		}
+	}
+
+	return
+}
+
+// PeriodRange mengambil tanggal awal dan akhir bulan dari periode.
+func PeriodRange(period time.Time) (start time.Time, end time.Time) {
+	start = time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, time.Local)
+	end = start.AddDate(0, 1, -1)
+	return
+}
+
+// GenerateStatement membuat statement komisi sales person pada bulan periode,
+// statement yang masih new pada periode yang sama akan dihitung ulang.
+func GenerateStatement(sp *model.User, period time.Time, user *model.User) (st *model.CommissionStatement, e error) {
+	start, end := PeriodRange(period)
+
+	old := &model.CommissionStatement{SalesPerson: sp, Period: start}
+	if e = old.Read("SalesPerson", "Period"); e == nil {
+		if old.DocumentStatus == "approved" {
+			return nil, ErrStatementApproved
+		}
+
+		if e = old.Delete(); e != nil {
+			return nil, e
+		}
+	}
+
+	if st, e = Calculate(sp, start, end); e != nil {
+		return nil, e
+	}
+
+	st.CreatedBy = user
+	st.CreatedAt = time.Now()
+	if e = st.Save(); e == nil {
+		for _, i := range st.CommissionStatementItems {
+			i.CommissionStatement = &model.CommissionStatement{ID: st.ID}
+			if e = i.Save(); e != nil {
+				return nil, e
+			}
+		}
+	}
+
+	return st, e
+}
+
+// GenerateStatements membuat statement komisi untuk semua sales person yang memiliki
+// sales order, sales person yang tidak memiliki transaksi pada periode tersebut dilewati.
+func GenerateStatements(period time.Time, user *model.User) (sts []*model.CommissionStatement, e error) {
+	start, end := PeriodRange(period)
+
+	var ids []int64
+	o := orm.NewOrm()
+	if _, e = o.Raw("SELECT DISTINCT so.sales_person_id FROM sales_order so WHERE so.sales_person_id IS NOT NULL AND so.is_deleted = 0").QueryRows(&ids); e != nil {
+		return nil, e
+	}
+
+	for _, id := range ids {
+		sp := &model.User{ID: id}
+
+		// statement yang sudah diapprove tidak dihitung ulang
+		old := &model.CommissionStatement{SalesPerson: sp, Period: start}
+		if old.Read("SalesPerson", "Period") == nil && old.DocumentStatus == "approved" {
+			continue
+		}
+
+		var lines []*commissionLine
+		if lines, e = salesLines(id, start, end); e != nil {
+			return nil, e
+		}
+
+		if len(lines) == 0 {
+			continue
+		}
+
+		var st *model.CommissionStatement
+		if st, e = GenerateStatement(sp, start, user); e != nil {
+			return nil, e
+		}
+		sts = append(sts, st)
+	}
+
+	return
+}
+
+// ApproveStatement approve statement komisi, statement yang sudah diapprove tidak bisa dihitung ulang.
+func ApproveStatement(st *model.CommissionStatement, user *model.User) error {
+	st.DocumentStatus = "approved"
+	st.ApprovedBy = user
+	st.ApprovedAt = time.Now()
+
+	return st.Save("DocumentStatus", "ApprovedBy", "ApprovedAt")
+}
+
+// Calculate menghitung komisi sales person dari sales invoice yang sudah lunas
+// dan dikurangi sales return pada periode tanggal start sampai end.
+func Calculate(sp *model.User, start time.Time, end time.Time) (st *model.CommissionStatement, e error) {
+	var lines []*commissionLine
+	if lines, e = salesLines(sp.ID, start, end); e != nil {
+		return nil, e
+	}
+
+	var rules []*model.CommissionRule
+	if rules, e = activeRules(sp.ID); e != nil {
+		return nil, e
+	}
+
+	st = &model.CommissionStatement{
+		SalesPerson:    sp,
+		Period:         start,
+		DocumentStatus: "new",
+	}
+
+	// total basis setiap rule dipakai untuk menentukan tier yang tercapai
+	achievement := make(map[int64]float64)
+	lineRules := make([]*model.CommissionRule, len(lines))
+	for x, l := range lines {
+		if lineRules[x] = matchRule(rules, sp.ID, l.CategoryID); lineRules[x] != nil {
+			achievement[lineRules[x].ID] += basisAmount(lineRules[x], l)
+		}
+	}
+
+	items := make(map[string]*model.CommissionStatementItem)
+	var keys []string
+	for x, l := range lines {
+		if l.RefType == "sales_return" {
+			st.TotalReturn += -l.Revenue
+		} else {
+			st.TotalRevenue += l.Revenue
+		}
+		st.TotalMargin += l.Revenue - l.Cost
+
+		// item statement dikelompokkan per dokumen dan rule
+		var ruleID int64
+		var rate, basis float64
+		if r := lineRules[x]; r != nil {
+			ruleID = r.ID
+			rate = tierRate(r, achievement[r.ID])
+			basis = basisAmount(r, l)
+		}
+
+		key := fmt.Sprintf("%s-%d-%d", l.RefType, l.RefID, ruleID)
+		item, ok := items[key]
+		if !ok {
+			item = &model.CommissionStatementItem{
+				RefID:   l.RefID,
+				RefType: l.RefType,
+				RefCode: l.RefCode,
+				Rate:    rate,
+			}
+			if ruleID != 0 {
+				item.CommissionRule = lineRules[x]
+			}
+			items[key] = item
+			keys = append(keys, key)
+		}
+
+		item.Revenue += l.Revenue
+		item.Cost += l.Cost
+		item.BasisAmount += basis
+	}
+
+	for _, key := range keys {
+		item := items[key]
+		item.Revenue = common.FloatPrecision(item.Revenue, 0)
+		item.Cost = common.FloatPrecision(item.Cost, 0)
+		item.BasisAmount = common.FloatPrecision(item.BasisAmount, 0)
+		item.CommissionAmount = common.FloatPrecision((item.BasisAmount*item.Rate)/float64(100), 0)
+
+		st.TotalCommission += item.CommissionAmount
+		st.CommissionStatementItems = append(st.CommissionStatementItems, item)
+	}
+
+	st.TotalRevenue = common.FloatPrecision(st.TotalRevenue, 0)
+	st.TotalReturn = common.FloatPrecision(st.TotalReturn, 0)
+	st.TotalMargin = common.FloatPrecision(st.TotalMargin, 0)
+
+	return st, nil
+}
+
+// activeRules mengambil rule komisi yang aktif untuk sales person beserta tier,
+// rule tanpa sales person berlaku untuk semua sales person.
+func activeRules(salesPersonID int64) (rules []*model.CommissionRule, e error) {
+	o := orm.NewOrm()
+	if _, e = o.Raw("SELECT * FROM commission_rule WHERE is_active = 1 AND (sales_person_id IS NULL OR sales_person_id = ?) ORDER BY id ASC", salesPersonID).QueryRows(&rules); e == nil {
+		for _, r := range rules {
+			o.QueryTable(new(model.CommissionRuleTier)).Filter("commission_rule_id", r.ID).OrderBy("min_target").All(&r.Tiers)
+		}
+	}
+
+	return
+}
+
+// matchRule memilih rule yang paling spesifik untuk sales person dan kategori item,
+// rule khusus sales person diutamakan dibanding rule khusus kategori.
+func matchRule(rules []*model.CommissionRule, salesPersonID int64, categoryID int64) (rule *model.CommissionRule) {
+	best := -1
+	for _, r := range rules {
+		score := 0
+		if r.SalesPerson != nil && r.SalesPerson.ID != 0 {
+			if r.SalesPerson.ID != salesPersonID {
+				continue
+			}
+			score += 2
+		}
+
+		if r.ItemCategory != nil && r.ItemCategory.ID != 0 {
+			if r.ItemCategory.ID != categoryID {
+				continue
+			}
+			score++
+		}
+
+		if score > best {
+			best = score
+			rule = r
+		}
+	}
+
+	return
+}
+
+// tierRate mengambil persentase komisi dari tier tertinggi yang target minimalnya sudah tercapai.
+func tierRate(rule *model.CommissionRule, achievement float64) (rate float64) {
+	var target float64
+	for _, t := range rule.Tiers {
+		if achievement >= t.MinTarget && t.MinTarget >= target {
+			target = t.MinTarget
+			rate = t.Rate
+		}
+	}
+
+	return
+}
+
+// basisAmount nominal dasar perhitungan komisi sesuai basis rule.
+func basisAmount(rule *model.CommissionRule, l *commissionLine) float64 {
+	if rule.Basis == "margin" {
+		return l.Revenue - l.Cost
+	}
+
+	return l.Revenue
+}
+
+// salesLines mengambil revenue dan cost per item dari sales invoice yang lunas
+// dan sales return milik sales person pada periode tanggal start sampai end.
+func salesLines(salesPersonID int64, start time.Time, end time.Time) (lines []*commissionLine, e error) {
+	var invoices []*model.SalesInvoice
+	if invoices, e = paidInvoices(salesPersonID, start, end); e != nil {
+		return nil, e
+	}
+
+	for _, si := range invoices {
+		so := &model.SalesOrder{ID: si.SalesOrder.ID}
+		if e = so.Read(); e != nil {
+			return nil, e
+		}
+
+		var ol []*orderLine
+		if ol, e = orderLines(so.ID); e != nil {
+			return nil, e
+		}
+
+		// invoice bisa hanya sebagian dari sales order
+		ratio := float64(1)
+		if so.TotalCharge > 0 && si.TotalAmount < so.TotalCharge {
+			ratio = si.TotalAmount / so.TotalCharge
+		}
+
+		for _, l := range ol {
+			lines = append(lines, &commissionLine{
+				RefID:      uint64(si.ID),
+				RefType:    "sales_invoice",
+				RefCode:    si.Code,
+				CategoryID: l.CategoryID,
+				Revenue:    lineRevenue(so, l) * ratio,
+				Cost:       unitCost(so.ID, l.ItemVariantID) * float64(l.Quantity) * ratio,
+			})
+		}
+	}
+
+	var returns []*model.SalesReturn
+	o := orm.NewOrm()
+	if _, e = o.Raw("SELECT sr.* FROM sales_return sr "+
+		"INNER JOIN sales_order so ON so.id = sr.sales_order_id "+
+		"WHERE so.sales_person_id = ? AND sr.is_deleted = 0 AND sr.document_status != 'cancelled' "+
+		"AND sr.recognition_date BETWEEN ? AND ?", salesPersonID, start.Format("2006-01-02"), end.Format("2006-01-02")).QueryRows(&returns); e != nil {
+		return nil, e
+	}
+
+	for _, sr := range returns {
+		so := &model.SalesOrder{ID: sr.SalesOrder.ID}
+		if e = so.Read(); e != nil {
+			return nil, e
+		}
+
+		var items []*model.SalesReturnItem
+		if _, e = o.QueryTable(new(model.SalesReturnItem)).Filter("sales_return_id", sr.ID).All(&items); e != nil {
+			return nil, e
+		}
+
+		var ol []*orderLine
+		if ol, e = orderLines(so.ID); e != nil {
+			return nil, e
+		}
+
+		for _, i := range items {
+			for _, l := range ol {
+				if l.ID != i.SalesOrderItem.ID || l.Quantity <= 0 {
+					continue
+				}
+
+				lines = append(lines, &commissionLine{
+					RefID:      uint64(sr.ID),
+					RefType:    "sales_return",
+					RefCode:    sr.Code,
+					CategoryID: l.CategoryID,
+					Revenue:    -(lineRevenue(so, l) / float64(l.Quantity) * float64(i.Quantity)),
+					Cost:       -(unitCost(so.ID, l.ItemVariantID) * float64(i.Quantity)),
+				})
+			}
+		}
+	}
+
+	return
+}
+
+// paidInvoices mengambil sales invoice milik sales person yang lunas pada periode tanggal start sampai end,
+// tanggal lunas diambil dari tanggal pembayaran terakhir yang sudah cleared.
+func paidInvoices(salesPersonID int64, start time.Time, end time.Time) (m []*model.SalesInvoice, e error) {
+	o := orm.NewOrm()
+	_, e = o.Raw("SELECT si.* FROM sales_invoice si "+
+		"INNER JOIN sales_order so ON so.id = si.sales_order_id "+
+		"WHERE so.sales_person_id = ? AND so.is_deleted = 0 AND si.is_deleted = 0 "+
+		"AND si.total_amount > 0 AND si.total_paid >= si.total_amount "+
+		"AND COALESCE((SELECT MAX(fr.recognition_date) FROM finance_revenue fr "+
+		"WHERE fr.is_deleted = 0 AND fr.document_status = 'cleared' "+
+		"AND ((fr.ref_type = 'sales_invoice' AND fr.ref_id = si.id) "+
+		"OR (fr.ref_type = 'invoice_receipt' AND fr.ref_id IN (SELECT iri.invoice_receipt_id FROM invoice_receipt_item iri WHERE iri.sales_invoice_id = si.id)))), si.recognition_date) "+
+		"BETWEEN ? AND ?", salesPersonID, start.Format("2006-01-02"), end.Format("2006-01-02")).QueryRows(&m)
+
+	return
+}
+
+// orderLines mengambil semua item sales order beserta kategori item.
+func orderLines(soID int64) (m []*orderLine, e error) {
+	o := orm.NewOrm()
+	_, e = o.Raw("SELECT soi.id, soi.sales_order_id, soi.item_variant_id, i.category_id, soi.tax_code_id, soi.quantity, soi.subtotal, soi.tax_base "+
+		"FROM sales_order_item soi "+
+		"INNER JOIN item_variant iv ON iv.id = soi.item_variant_id "+
+		"INNER JOIN item i ON i.id = iv.item_id "+
+		"WHERE soi.sales_order_id = ?", soID).QueryRows(&m)
+
+	return
+}
+
+// lineRevenue menghitung revenue item sales order setelah dikurangi porsi diskon order dan tanpa ppn.
+func lineRevenue(so *model.SalesOrder, l *orderLine) float64 {
+	if l.TaxCodeID != 0 {
+		return l.TaxBase
+	}
+
+	amount := l.Subtotal
+	if so.TotalPrice > 0 && so.DiscountAmount > 0 {
+		amount = l.Subtotal - ((l.Subtotal / so.TotalPrice) * so.DiscountAmount)
+	}
+
+	return amount
+}
+
+// unitCost menghitung rata-rata harga pokok per unit item variant dari stock yang keluar
+// pada fulfillment sales order, jika belum ada fulfillment dipakai harga pokok stock terakhir.
+func unitCost(soID int64, itemVariantID int64) (cost float64) {
+	o := orm.NewOrm()
+	o.Raw("SELECT COALESCE(SUM(l.quantity * s.unit_cost) / SUM(l.quantity), 0) FROM item_variant_stock_log l "+
+		"INNER JOIN item_variant_stock s ON s.id = l.item_variant_stock_id "+
+		"INNER JOIN workorder_fulfillment wf ON wf.id = l.ref_id "+
+		"WHERE l.ref_type = 'workorder_fulfillment' AND l.log_type = 'out' AND wf.sales_order_id = ? AND s.item_variant_id = ?", soID, itemVariantID).QueryRow(&cost)
+
+	if cost == 0 {
+		o.Raw("SELECT unit_cost FROM item_variant_stock WHERE item_variant_id = ? ORDER BY created_at DESC LIMIT 1", itemVariantID).QueryRow(&cost)
+	}
+
+	return
+}
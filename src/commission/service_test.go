@@ -0,0 +1,145 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package commission
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dummyPaidSales membuat sales order milik sales person yang invoicenya sudah lunas pada tanggal paidAt.
+func dummyPaidSales(sp *model.User, paidAt time.Time) (*model.SalesOrderItem, *model.SalesInvoice) {
+	so := model.DummySalesOrder()
+	so.SalesPerson = sp
+	so.TotalPrice = 1000000
+	so.DiscountAmount = 0
+	so.TotalCharge = 1000000
+	so.IsDeleted = 0
+	so.Save()
+
+	soi := model.DummySalesOrderItem()
+	soi.SalesOrder = so
+	soi.TaxCode = nil
+	soi.Quantity = 10
+	soi.UnitPrice = 100000
+	soi.Subtotal = 1000000
+	soi.Save()
+
+	stock := model.DummyItemVariantStock()
+	stock.ItemVariant = soi.ItemVariant
+	stock.UnitCost = 60000
+	stock.CreatedAt = time.Now()
+	stock.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = 1000000
+	si.TotalPaid = 1000000
+	si.RecognitionDate = paidAt
+	si.IsDeleted = 0
+	si.Save()
+
+	fr := model.DummyFinanceRevenue()
+	fr.RefType = "sales_invoice"
+	fr.RefID = uint64(si.ID)
+	fr.RecognitionDate = paidAt
+	fr.DocumentStatus = "cleared"
+	fr.IsDeleted = 0
+	fr.Save()
+
+	return soi, si
+}
+
+func TestMatchRule(t *testing.T) {
+	general := &model.CommissionRule{ID: 1}
+	category := &model.CommissionRule{ID: 2, ItemCategory: &model.ItemCategory{ID: 10}}
+	person := &model.CommissionRule{ID: 3, SalesPerson: &model.User{ID: 5}}
+	other := &model.CommissionRule{ID: 4, SalesPerson: &model.User{ID: 6}, ItemCategory: &model.ItemCategory{ID: 10}}
+	rules := []*model.CommissionRule{general, category, person, other}
+
+	assert.Equal(t, person, matchRule(rules, 5, 10))
+	assert.Equal(t, category, matchRule(rules, 7, 10))
+	assert.Equal(t, general, matchRule(rules, 7, 11))
+	assert.Nil(t, matchRule([]*model.CommissionRule{category}, 7, 11))
+}
+
+func TestTierRate(t *testing.T) {
+	rule := &model.CommissionRule{Tiers: []*model.CommissionRuleTier{
+		{MinTarget: 0, Rate: 1},
+		{MinTarget: 10000000, Rate: 2},
+		{MinTarget: 50000000, Rate: 3.5},
+	}}
+
+	assert.Equal(t, float64(1), tierRate(rule, 5000000))
+	assert.Equal(t, float64(2), tierRate(rule, 10000000))
+	assert.Equal(t, float64(3.5), tierRate(rule, 75000000))
+	assert.Equal(t, float64(0), tierRate(&model.CommissionRule{}, 75000000))
+}
+
+func TestLineRevenue(t *testing.T) {
+	so := &model.SalesOrder{TotalPrice: 200000, DiscountAmount: 20000}
+
+	assert.Equal(t, float64(90000), lineRevenue(so, &orderLine{Subtotal: 100000}))
+	assert.Equal(t, float64(81000), lineRevenue(so, &orderLine{Subtotal: 100000, TaxCodeID: 1, TaxBase: 81000}))
+
+	l := &commissionLine{Revenue: 100000, Cost: 60000}
+	assert.Equal(t, float64(40000), basisAmount(&model.CommissionRule{Basis: "margin"}, l))
+	assert.Equal(t, float64(100000), basisAmount(&model.CommissionRule{Basis: "revenue"}, l))
+}
+
+func TestCalculate(t *testing.T) {
+	sp := model.DummyUser()
+	period := time.Date(2018, 3, 1, 0, 0, 0, 0, time.Local)
+
+	soi, si := dummyPaidSales(sp, time.Date(2018, 3, 15, 0, 0, 0, 0, time.Local))
+
+	// invoice yang lunas di bulan lain tidak dihitung
+	dummyPaidSales(sp, time.Date(2018, 4, 2, 0, 0, 0, 0, time.Local))
+
+	rule := model.DummyCommissionRule()
+	rule.SalesPerson = sp
+	rule.ItemCategory = nil
+	rule.Basis = "margin"
+	rule.IsActive = 1
+	rule.Tiers = []*model.CommissionRuleTier{{MinTarget: 0, Rate: 5}, {MinTarget: 1000000, Rate: 10}}
+	SaveRule(rule)
+
+	// return 2 unit, revenue 200000 dan cost 120000
+	sr := model.DummySalesReturn()
+	sr.SalesOrder = soi.SalesOrder
+	sr.RecognitionDate = time.Date(2018, 3, 20, 0, 0, 0, 0, time.Local)
+	sr.DocumentStatus = "active"
+	sr.IsDeleted = 0
+	sr.Save()
+
+	sri := model.DummySalesReturnItem()
+	sri.SalesReturn = sr
+	sri.SalesOrderItem = soi
+	sri.Quantity = 2
+	sri.Save()
+
+	start, end := PeriodRange(period)
+	st, e := Calculate(sp, start, end)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1000000), st.TotalRevenue)
+	assert.Equal(t, float64(200000), st.TotalReturn)
+	assert.Equal(t, float64(320000), st.TotalMargin)
+	// margin 400000 dikurangi 80000 hanya mencapai tier 5%
+	assert.Equal(t, float64(16000), st.TotalCommission)
+	assert.Len(t, st.CommissionStatementItems, 2)
+	assert.Equal(t, uint64(si.ID), st.CommissionStatementItems[0].RefID)
+
+	// statement yang sudah diapprove tidak bisa dihitung ulang
+	m, e := GenerateStatement(sp, period, sp)
+	assert.NoError(t, e)
+	assert.NoError(t, ApproveStatement(m, sp))
+
+	_, e = GenerateStatement(sp, period, sp)
+	assert.Equal(t, ErrStatementApproved, e)
+}
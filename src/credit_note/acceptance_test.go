@@ -0,0 +1,200 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package creditNote_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp()
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	cn := model.DummyCreditNote()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/credit-note", "GET", http.StatusOK},
+		{"/v1/credit-note/" + common.Encrypt(cn.ID), "GET", http.StatusOK},
+		{"/v1/credit-note/999999", "GET", http.StatusNotFound},
+		{"/v1/credit-note/partnership/" + common.Encrypt(cn.Partnership.ID), "GET", http.StatusOK},
+		{"/v1/credit-note/partnership/999999", "GET", http.StatusNotFound},
+		{"/v1/credit-note", "POST", http.StatusUnprocessableEntity},
+		{"/v1/credit-note/999999/cancel", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestCreateCreditNote(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDeleted = 0
+	customer.Save()
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.Save()
+
+	sr := model.DummySalesReturn()
+	sr.SalesOrder = so
+	sr.TotalAmount = 40000
+	sr.DocumentStatus = "active"
+	sr.IsDeleted = 0
+	sr.Save()
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"partnership_id": common.Encrypt(customer.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 15000}, http.StatusOK},
+		{tester.D{"partnership_id": common.Encrypt(customer.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 0}, http.StatusUnprocessableEntity},
+		{tester.D{"partnership_id": common.Encrypt(supplier.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 15000}, http.StatusUnprocessableEntity},
+		{tester.D{"partnership_id": "abc", "recognition_date": "2017-08-21T00:00:00Z", "amount": 15000}, http.StatusUnprocessableEntity},
+		{tester.D{"sales_return_id": common.Encrypt(sr.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 50000}, http.StatusUnprocessableEntity},
+		{tester.D{"sales_return_id": common.Encrypt(sr.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 10000}, http.StatusOK},
+		{tester.D{"sales_return_id": common.Encrypt(sr.ID), "recognition_date": "2017-08-21T00:00:00Z"}, http.StatusOK},
+		{tester.D{"sales_return_id": common.Encrypt(sr.ID), "recognition_date": "2017-08-21T00:00:00Z"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/credit-note").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	// sisa sales return 30000 diterbitkan seluruhnya sebagai credit note
+	sr.Read()
+	assert.Equal(t, "finished", sr.DocumentStatus)
+
+	customer.Read()
+	assert.Equal(t, float64(55000), customer.TotalCredit)
+}
+
+func TestPayInvoiceWithCreditNote(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDeleted = 0
+	customer.Save()
+
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.IsDeleted = 0
+	so.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = 100000
+	si.TotalRevenued = 0
+	si.DocumentStatus = "new"
+	si.IsDeleted = 0
+	si.Save()
+
+	cn := model.DummyCreditNote()
+	cn.Partnership = customer
+	cn.SalesReturn = nil
+	cn.Amount = 25000
+	cn.UsedAmount = 0
+	cn.Balance = 25000
+	cn.DocumentStatus = "active"
+	cn.Save()
+
+	other := model.DummyCreditNote()
+	other.SalesReturn = nil
+	other.Balance = 25000
+	other.DocumentStatus = "active"
+	other.Save()
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"ref_id": common.Encrypt(si.ID), "ref_type": "sales_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "credit_note", "credit_note_id": common.Encrypt(cn.ID), "amount": 30000}, http.StatusUnprocessableEntity},
+		{tester.D{"ref_id": common.Encrypt(si.ID), "ref_type": "sales_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "credit_note", "credit_note_id": common.Encrypt(other.ID), "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"ref_id": common.Encrypt(si.ID), "ref_type": "sales_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "credit_note", "credit_note_id": "abc", "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"ref_id": common.Encrypt(si.ID), "ref_type": "sales_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "credit_note", "credit_note_id": common.Encrypt(cn.ID), "amount": 25000}, http.StatusOK},
+		{tester.D{"ref_id": common.Encrypt(si.ID), "ref_type": "sales_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "credit_note", "credit_note_id": common.Encrypt(cn.ID), "amount": 1000}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/finance-revenue").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	cn.Read()
+	assert.Equal(t, float64(25000), cn.UsedAmount)
+	assert.Equal(t, float64(0), cn.Balance)
+	assert.Equal(t, "used", cn.DocumentStatus)
+
+	// credit note yang sudah digunakan tidak bisa dicancel
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/credit-note/"+common.Encrypt(cn.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/credit-note/"+common.Encrypt(other.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+		})
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package creditNote
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for credit note.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("credit_note_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("credit_note_read"))
+	r.GET("/partnership/:id", h.balance, auth.CheckPrivilege("credit_note_read"))
+	r.POST("", h.create, auth.CheckPrivilege("credit_note_create"))
+	r.PUT("/:id/cancel", h.cancel, auth.CheckPrivilege("credit_note_cancel"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.CreditNote
+	if data, total, e = GetCreditNotes(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to handle get http method with id.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.CreditNote
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowCreditNote("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// balance endpoint untuk mengambil saldo credit note customer.
+func (h *Handler) balance(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var b *Balance
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		p := &model.Partnership{ID: id}
+		if e = p.Read(); e == nil {
+			if b, e = GetPartnershipBalance(p); e == nil {
+				ctx.Data(b)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk menerbitkan credit note dari sales return atau manual.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r createRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = CreateCreditNote(m); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// cancel endpoint untuk membatalkan credit note yang belum digunakan.
+func (h *Handler) cancel(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r cancelRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.CreditNote, e = ShowCreditNote("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = CancelCreditNote(r.CreditNote, r.Session.User); e == nil {
+						ctx.Data(r.CreditNote)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
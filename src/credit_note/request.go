@@ -0,0 +1,134 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package creditNote
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/sales_return"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/cuxs/validation"
+)
+
+// createRequest data struct that stored request data when requesting an create credit note process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	PartnershipID   string             `json:"partnership_id"`
+	SalesReturnID   string             `json:"sales_return_id"`
+	RecognitionDate time.Time          `json:"recognition_date" valid:"required"`
+	Amount          float64            `json:"amount" valid:"gte:0"`
+	Note            string             `json:"note"`
+	Session         *auth.SessionData  `json:"-"`
+	Partnership     *model.Partnership `json:"-"`
+	SalesReturn     *model.SalesReturn `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.SalesReturnID != "" {
+		// credit note dari sales return, customer diambil dari sales order
+		if id, e := common.Decrypt(r.SalesReturnID); e != nil {
+			o.Failure("sales_return_id", "sales return id cannot be decrypt")
+		} else if r.SalesReturn, e = salesReturn.ShowSalesReturn("id", id); e != nil || r.SalesReturn.IsDeleted == 1 || r.SalesReturn.DocumentStatus == "cancelled" {
+			o.Failure("sales_return_id", "sales return doesn't exist")
+		} else if r.SalesReturn.DocumentStatus == "finished" {
+			o.Failure("sales_return_id", "sales return already refunded")
+		} else {
+			r.SalesReturn.SalesOrder.Read()
+			r.Partnership = r.SalesReturn.SalesOrder.Customer
+
+			// sisa sales return yang belum direfund cash maupun credit note
+			var expense float64
+			orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM finance_expense WHERE ref_id = ? AND ref_type = 'sales_return' AND is_deleted = 0", r.SalesReturn.ID).QueryRow(&expense)
+			remaining := r.SalesReturn.TotalAmount - expense - salesReturn.SumCreditNoteAmount(r.SalesReturn.ID)
+
+			if r.Amount == 0 {
+				r.Amount = remaining
+			}
+
+			if remaining <= 0 || r.Amount > remaining {
+				o.Failure("amount", "amount is greater than the unrefunded amount of the sales return")
+			}
+		}
+	} else {
+		// credit note manual
+		if id, e := common.Decrypt(r.PartnershipID); e != nil {
+			o.Failure("partnership_id", "partnership id cannot be decrypt")
+		} else {
+			r.Partnership = &model.Partnership{ID: id}
+			if e = r.Partnership.Read(); e != nil || r.Partnership.IsDeleted == 1 {
+				o.Failure("partnership_id", "partnership doesn't exist")
+			} else if r.Partnership.PartnershipType != "customer" {
+				o.Failure("partnership_id", "credit note can only be issued to customer")
+			}
+		}
+
+		if r.Amount <= 0 {
+			o.Failure("amount", "amount must be greater than 0")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.CreditNote {
+	code, _ := util.CodeGen("code_credit_note", "credit_note")
+
+	return &model.CreditNote{
+		Code:            code,
+		Partnership:     r.Partnership,
+		SalesReturn:     r.SalesReturn,
+		RecognitionDate: r.RecognitionDate,
+		Amount:          r.Amount,
+		Balance:         r.Amount,
+		DocumentStatus:  "active",
+		Note:            r.Note,
+		CreatedBy:       r.Session.User,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// cancelRequest data struct that stored request data when requesting an cancel credit note process.
+type cancelRequest struct {
+	CreditNote *model.CreditNote `json:"-"`
+	Session    *auth.SessionData `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *cancelRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.CreditNote.DocumentStatus == "cancelled" {
+		o.Failure("document_status", "credit note already cancelled")
+	}
+
+	if r.CreditNote.UsedAmount > 0 || len(r.CreditNote.FinanceRevenues) > 0 {
+		o.Failure("document_status", "credit note already used as payment")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *cancelRequest) Messages() map[string]string {
+	return map[string]string{}
+}
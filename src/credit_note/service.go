@@ -0,0 +1,139 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package creditNote
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/partnership"
+	"git.qasico.com/mj/api/src/sales_return"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// Balance saldo credit note customer beserta credit note yang masih bisa digunakan.
+type Balance struct {
+	Partnership *model.Partnership  `json:"partnership"`
+	Balance     float64             `json:"balance"`
+	CreditNotes []*model.CreditNote `json:"credit_notes"`
+}
+
+// GetCreditNotes get all data credit note that matched with query request parameters.
+// returning slices of credit note, total data without limit and error.
+func GetCreditNotes(rq *orm.RequestQuery) (m *[]model.CreditNote, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.CreditNote))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.CreditNote
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowCreditNote find a single data credit note using field and value condition,
+// beserta finance revenue yang menggunakan credit note sebagai pembayaran.
+func ShowCreditNote(field string, values ...interface{}) (*model.CreditNote, error) {
+	m := new(model.CreditNote)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel().Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.Raw("SELECT * FROM finance_revenue WHERE credit_note_id = ? AND is_deleted = 0 ORDER BY id", m.ID).QueryRows(&m.FinanceRevenues)
+
+	return m, nil
+}
+
+// GetPartnershipBalance mengambil saldo credit note customer dari credit note yang masih active.
+func GetPartnershipBalance(p *model.Partnership) (b *Balance, e error) {
+	b = &Balance{Partnership: p}
+	if _, e = orm.NewOrm().QueryTable(new(model.CreditNote)).Filter("partnership_id", p.ID).Filter("document_status", "active").OrderBy("id").All(&b.CreditNotes); e == nil {
+		for _, cn := range b.CreditNotes {
+			b.Balance += cn.Balance
+		}
+	}
+
+	return
+}
+
+// CreateCreditNote menyimpan credit note baru, credit note dari sales return
+// akan memperbarui status refund sales return.
+func CreateCreditNote(cn *model.CreditNote) (e error) {
+	if e = cn.Save(); e == nil {
+		if cn.SalesReturn != nil {
+			var sr *model.SalesReturn
+			if sr, e = salesReturn.ShowSalesReturn("id", cn.SalesReturn.ID); e == nil {
+				e = salesReturn.CheckRefundStatus(sr)
+			}
+		}
+
+		if e == nil {
+			e = partnership.CalculationTotalCredit(cn.Partnership.ID)
+		}
+	}
+
+	return
+}
+
+// CalculateCreditNote menghitung ulang jumlah credit note yang sudah digunakan pada finance revenue,
+// credit note yang saldonya habis berubah status menjadi used.
+func CalculateCreditNote(cn *model.CreditNote) (e error) {
+	if e = cn.Read(); e != nil {
+		return
+	}
+
+	var used float64
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM finance_revenue WHERE credit_note_id = ? AND is_deleted = 0", cn.ID).QueryRow(&used)
+
+	cn.UsedAmount = used
+	cn.Balance = cn.Amount - used
+	if cn.DocumentStatus != "cancelled" {
+		if cn.Balance <= 0 {
+			cn.DocumentStatus = "used"
+		} else {
+			cn.DocumentStatus = "active"
+		}
+	}
+	cn.UpdatedAt = time.Now()
+
+	if e = cn.Save("used_amount", "balance", "document_status", "updated_at"); e == nil {
+		e = partnership.CalculationTotalCredit(cn.Partnership.ID)
+	}
+
+	return
+}
+
+// CancelCreditNote membatalkan credit note yang belum digunakan.
+func CancelCreditNote(cn *model.CreditNote, user *model.User) (e error) {
+	cn.DocumentStatus = "cancelled"
+	cn.Balance = 0
+	cn.UpdatedBy = user
+	cn.UpdatedAt = time.Now()
+
+	if e = cn.Save("document_status", "balance", "updated_by", "updated_at"); e == nil {
+		if cn.SalesReturn != nil {
+			var sr *model.SalesReturn
+			if sr, e = salesReturn.ShowSalesReturn("id", cn.SalesReturn.ID); e == nil {
+				e = salesReturn.CheckRefundStatus(sr)
+			}
+		}
+
+		if e == nil {
+			e = partnership.CalculationTotalCredit(cn.Partnership.ID)
+		}
+	}
+
+	return
+}
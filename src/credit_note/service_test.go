@@ -0,0 +1,153 @@
+package creditNote
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCreditNotesNoData(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("DELETE FROM credit_note").Exec()
+	rq := orm.RequestQuery{}
+
+	m, total, e := GetCreditNotes(&rq)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, m)
+	assert.NoError(t, e)
+}
+
+func TestGetCreditNotes(t *testing.T) {
+	model.DummyCreditNote()
+	qs := orm.RequestQuery{}
+	_, _, e := GetCreditNotes(&qs)
+	assert.NoError(t, e, "Data should be exists.")
+}
+
+func TestShowCreditNote(t *testing.T) {
+	_, e := ShowCreditNote("id", 999999)
+	assert.Error(t, e, "Response should be error, beacuse there are no data yet.")
+
+	c := model.DummyCreditNote()
+	cd, e := ShowCreditNote("id", c.ID)
+	assert.NoError(t, e, "Data should be exists.")
+	assert.Equal(t, c.ID, cd.ID, "ID Response should be a same.")
+}
+
+func dummyCustomer() *model.Partnership {
+	p := model.DummyPartnership()
+	p.PartnershipType = "customer"
+	p.IsDeleted = 0
+	p.Save()
+
+	return p
+}
+
+func TestCreateCreditNoteFromSalesReturn(t *testing.T) {
+	p := dummyCustomer()
+
+	so := model.DummySalesOrder()
+	so.Customer = p
+	so.Save()
+
+	sr := model.DummySalesReturn()
+	sr.SalesOrder = so
+	sr.TotalAmount = 50000
+	sr.DocumentStatus = "active"
+	sr.IsDeleted = 0
+	sr.Save()
+
+	cn := &model.CreditNote{
+		Code:            "CN-TEST-1",
+		Partnership:     p,
+		SalesReturn:     sr,
+		RecognitionDate: time.Now(),
+		Amount:          50000,
+		Balance:         50000,
+		DocumentStatus:  "active",
+		CreatedBy:       model.DummyUser(),
+		CreatedAt:       time.Now(),
+	}
+	e := CreateCreditNote(cn)
+	assert.NoError(t, e)
+
+	// sales return sudah direfund seluruhnya dengan credit note
+	sr.Read()
+	assert.Equal(t, "finished", sr.DocumentStatus)
+
+	b, e := GetPartnershipBalance(p)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(50000), b.Balance)
+	assert.Len(t, b.CreditNotes, 1)
+
+	p.Read()
+	assert.Equal(t, float64(50000), p.TotalCredit)
+
+	// cancel credit note, sales return kembali active
+	e = CancelCreditNote(cn, model.DummyUser())
+	assert.NoError(t, e)
+
+	sr.Read()
+	assert.Equal(t, "active", sr.DocumentStatus)
+
+	p.Read()
+	assert.Equal(t, float64(0), p.TotalCredit)
+}
+
+func TestCalculateCreditNote(t *testing.T) {
+	p := dummyCustomer()
+
+	cn := &model.CreditNote{
+		Code:            "CN-TEST-2",
+		Partnership:     p,
+		RecognitionDate: time.Now(),
+		Amount:          30000,
+		Balance:         30000,
+		DocumentStatus:  "active",
+		CreatedBy:       model.DummyUser(),
+		CreatedAt:       time.Now(),
+	}
+	e := CreateCreditNote(cn)
+	assert.NoError(t, e)
+
+	fr := model.DummyFinanceRevenue()
+	fr.PaymentMethod = "credit_note"
+	fr.CreditNote = cn
+	fr.Amount = 10000
+	fr.IsDeleted = 0
+	fr.Save()
+
+	e = CalculateCreditNote(cn)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(10000), cn.UsedAmount)
+	assert.Equal(t, float64(20000), cn.Balance)
+	assert.Equal(t, "active", cn.DocumentStatus)
+
+	fr2 := model.DummyFinanceRevenue()
+	fr2.PaymentMethod = "credit_note"
+	fr2.CreditNote = cn
+	fr2.Amount = 20000
+	fr2.IsDeleted = 0
+	fr2.Save()
+
+	e = CalculateCreditNote(cn)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(0), cn.Balance)
+	assert.Equal(t, "used", cn.DocumentStatus)
+
+	b, _ := GetPartnershipBalance(p)
+	assert.Equal(t, float64(0), b.Balance)
+
+	// revenue dihapus, saldo credit note kembali
+	fr2.IsDeleted = 1
+	fr2.Save("is_deleted")
+
+	e = CalculateCreditNote(cn)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(20000), cn.Balance)
+	assert.Equal(t, "active", cn.DocumentStatus)
+}
@@ -20,6 +20,7 @@ type DSBData struct {
 	TotalSales              int64                `json:"total_sales"`
 	TotalPurchase           int64                `json:"total_purchases"`
 	TotalReturn             int64                `json:"total_return"`
+	TotalOperatingExpense   float64              `json:"total_operating_expense"`
 	TotalItemTerjual        float32              `json:"item_terjual"`
 	TotalStockIn            float32              `json:"stock_masuk"`
 	TotalStockReturn        float32              `json:"stock_return"`
@@ -59,6 +60,8 @@ type OverView struct {
 	TotalPenjualan float64 `json:"total_penjualan"`
 	TotalCost      float64 `json:"total_cost"`
 	TotalProfit    float64 `json:"total_profit"`
+	BebanOperasi   float64 `json:"beban_operasi"`
+	LabaBersih     float64 `json:"laba_bersih"`
 	PiutangUsaha   float64 `json:"piutang_usaha"`
 	HutangUsaha    float64 `json:"hutang_usaha"`
 }
@@ -66,6 +69,7 @@ type OverView struct {
 // GetDashBoard untuk mengambil semua data dashboard
 func GetDashBoard(pr *ParamURL) (m *DSBData, e error) {
 	var ret int64
+	var expense float64
 	var sales, purchase SOAndPOData
 	var stockIn, StockRet, itemJual float32
 	var graph []*Graphic
@@ -84,18 +88,21 @@ func GetDashBoard(pr *ParamURL) (m *DSBData, e error) {
 								if barangRev, e = GetGraphicTopRevenue(month, year); e == nil {
 									if todo, e = GetTodoList(); e == nil {
 										if needRestock, e = GetNeedToRestock(); e == nil {
-											m = &DSBData{
-												TotalSales:              sales.Total,
-												TotalPurchase:           purchase.Total,
-												TotalReturn:             ret,
-												TotalItemTerjual:        itemJual,
-												TotalStockIn:            stockIn,
-												TotalStockReturn:        StockRet,
-												GraphicReport:           graph,
-												GraphicTopBarangRevenue: barangRev,
-												ToDoListReport:          todo,
-												OverViewReport:          GetOverview(sales, purchase),
-												NeedToRestock:           needRestock,
+											if expense, e = GetTotalOperatingExpense(month, year); e == nil {
+												m = &DSBData{
+													TotalSales:              sales.Total,
+													TotalPurchase:           purchase.Total,
+													TotalReturn:             ret,
+													TotalOperatingExpense:   expense,
+													TotalItemTerjual:        itemJual,
+													TotalStockIn:            stockIn,
+													TotalStockReturn:        StockRet,
+													GraphicReport:           graph,
+													GraphicTopBarangRevenue: barangRev,
+													ToDoListReport:          todo,
+													OverViewReport:          GetOverview(sales, purchase, expense),
+													NeedToRestock:           needRestock,
+												}
 											}
 										}
 									}
@@ -146,6 +153,14 @@ func GetTotalSalesReturn(month, year int) (total int64, e error) {
 	return
 }
 
+// GetTotalOperatingExpense mengambil jumlah operating expense yang sudah diapprove pada bulan tertentu
+func GetTotalOperatingExpense(month, year int) (total float64, e error) {
+	o := orm.NewOrm()
+	e = o.Raw("SELECT COALESCE(SUM(oe.amount), 0) FROM operating_expense oe WHERE oe.document_status = 'approved' "+
+		"AND month(oe.recognition_date) = ? AND year(oe.recognition_date) = ?", month, year).QueryRow(&total)
+	return
+}
+
 // GetTotalItemTerjual mengambil jumlah total item terjual pada bulan tertentu
 func GetTotalItemTerjual(month, year int) (total float32, e error) {
 	o := orm.NewOrm()
@@ -373,12 +388,14 @@ func getCountShipmentNeedDeliv() (total int64, e error) {
 
 //// Get Data OverView /////////////////////////////////////////////////////////////////////////////////////
 
-// GetOverview untuk mendapatkan jumlah overview
-func GetOverview(sales, purchase SOAndPOData) (overview *OverView) {
+// GetOverview untuk mendapatkan jumlah overview, laba bersih adalah total profit dikurangi beban operasi
+func GetOverview(sales, purchase SOAndPOData, expense float64) (overview *OverView) {
 	overview = &OverView{
 		TotalPenjualan: sales.Charge,
 		TotalCost:      sales.Cost,
 		TotalProfit:    sales.Charge - sales.Cost,
+		BebanOperasi:   expense,
+		LabaBersih:     sales.Charge - sales.Cost - expense,
 		PiutangUsaha:   sales.Charge - sales.Paid,
 		HutangUsaha:    purchase.Charge - purchase.Paid,
 	}
@@ -387,22 +404,34 @@ func GetOverview(sales, purchase SOAndPOData) (overview *OverView) {
 
 //// Get Data NeedToRestock ////////////////////////////////////////////////////////////////////////////////
 
-// GetNeedToRestock mengambil item variant yang stock available <= stock minimum
+// GetNeedToRestock mengambil item variant yang stock available ditambah quantity purchase requisition
+// yang masih open <= stock minimum, sehingga item yang sudah diajukan tidak diajukan ulang.
 func GetNeedToRestock() (m []*model.ItemVariant, e error) {
 	// get all id
 	var itemVarID []int64
 	o := orm.NewOrm()
 	if _, e = o.Raw("SELECT iv.id FROM item_variant iv " +
-		"WHERE iv.available_stock <= iv.minimum_stock AND iv.is_deleted= 0 limit 20").QueryRows(&itemVarID); e == nil {
+		"LEFT JOIN (" + openRequisitionQuery + ") rq ON rq.item_variant_id = iv.id " +
+		"WHERE iv.available_stock + COALESCE(rq.quantity, 0) <= iv.minimum_stock AND iv.is_deleted= 0 limit 20").QueryRows(&itemVarID); e == nil {
 		// get detail item variant
 		if len(itemVarID) > int(0) {
-			m, e = getDetailAllItemVariant("id__in", itemVarID)
+			if m, e = getDetailAllItemVariant("id__in", itemVarID); e == nil {
+				for _, iv := range m {
+					o.Raw("SELECT rq.quantity FROM ("+openRequisitionQuery+") rq WHERE rq.item_variant_id = ?", iv.ID).QueryRow(&iv.OpenRequisition)
+				}
+			}
 		}
 	}
 
 	return
 }
 
+// openRequisitionQuery quantity purchase requisition per item variant yang masih menunggu approval
+// atau sudah approved namun belum dibuat purchase order.
+const openRequisitionQuery = "SELECT pri.item_variant_id, SUM(GREATEST(pri.quantity - pri.ordered_quantity, 0)) AS quantity FROM purchase_requisition_item pri " +
+	"INNER JOIN purchase_requisition pr ON pr.id = pri.purchase_requisition_id " +
+	"WHERE pr.is_deleted = 0 AND pr.document_status IN ('pending_approval', 'approved', 'partial') GROUP BY pri.item_variant_id"
+
 // getDetailAllItemVariant mengambil detail dari semua item variant sesuai param, menggunakan All
 func getDetailAllItemVariant(field string, values ...interface{}) ([]*model.ItemVariant, error) {
 	itm := new(model.ItemVariant)
@@ -296,6 +296,39 @@ func TestGetTotalSalesReturnEmpty(t *testing.T) {
 	assert.Equal(t, int64(0), total)
 }
 
+//// GetTotalOperatingExpense ///////////////////////////////////////////////////////////////////////////////
+
+// TestGetTotalOperatingExpense test hanya operating expense approved pada bulan tersebut yang dijumlahkan
+func TestGetTotalOperatingExpense(t *testing.T) {
+	tm := time.Date(2012, time.November, 12, 15, 00, 00, 00, time.Local)
+	tm2 := time.Date(time.Now().Year(), time.Now().Month(), 12, 15, 00, 00, 00, time.Local)
+	// hapus database
+	o := orm.NewOrm()
+	o.Raw("DELETE FROM operating_expense").Exec()
+	// buat dummy
+	oe1 := model.DummyOperatingExpense()
+	oe1.DocumentStatus = "approved"
+	oe1.Amount = 1000
+	oe1.RecognitionDate = tm2
+	oe1.Save()
+
+	oe2 := model.DummyOperatingExpense()
+	oe2.DocumentStatus = "new"
+	oe2.Amount = 2000
+	oe2.RecognitionDate = tm2
+	oe2.Save()
+
+	oe3 := model.DummyOperatingExpense()
+	oe3.DocumentStatus = "approved"
+	oe3.Amount = 4000
+	oe3.RecognitionDate = tm
+	oe3.Save()
+	// test
+	total, e := GetTotalOperatingExpense(int(time.Now().Month()), time.Now().Year())
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1000), total)
+}
+
 //// GetTotalItemTerjual ///////////////////////////////////////////////////////////////////////////////
 
 // TestGetTotalItemTerjual test func get jumlah quantity soi terjual yang tidak di delete
@@ -832,10 +865,12 @@ func TestGetOverview(t *testing.T) {
 		Charge: float64(10000),
 		Paid:   float64(2000),
 	}
-	m := GetOverview(sales, purchase)
+	m := GetOverview(sales, purchase, float64(3000))
 	assert.Equal(t, float64(20000), m.TotalPenjualan)
 	assert.Equal(t, float64(10000), m.TotalCost)
 	assert.Equal(t, float64(10000), m.TotalProfit)
+	assert.Equal(t, float64(3000), m.BebanOperasi)
+	assert.Equal(t, float64(7000), m.LabaBersih)
 	assert.Equal(t, float64(5000), m.PiutangUsaha)
 	assert.Equal(t, float64(8000), m.HutangUsaha)
 }
@@ -887,3 +922,58 @@ func TestGetNeedToRestockEmpty(t *testing.T) {
 	assert.NoError(t, e)
 	assert.Empty(t, m)
 }
+
+func TestGetNeedToRestockOpenRequisition(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("DELETE FROM item_variant").Exec()
+	o.Raw("DELETE FROM purchase_requisition_item").Exec()
+
+	// stock kurang dari minimum, sebagian sudah diajukan pada purchase requisition
+	itm1 := model.DummyItemVariant()
+	itm1.AvailableStock = float32(2)
+	itm1.MinimumStock = float32(10)
+	itm1.IsDeleted = int8(0)
+	itm1.Save()
+
+	// stock kurang dari minimum, kekurangan sudah diajukan seluruhnya
+	itm2 := model.DummyItemVariant()
+	itm2.AvailableStock = float32(2)
+	itm2.MinimumStock = float32(10)
+	itm2.IsDeleted = int8(0)
+	itm2.Save()
+
+	pr := model.DummyPurchaseRequisition()
+	pr.DocumentStatus = "approved"
+	pr.IsDeleted = 0
+	pr.Save()
+
+	pri1 := model.DummyPurchaseRequisitionItem()
+	pri1.PurchaseRequisition = pr
+	pri1.ItemVariant = itm1
+	pri1.Quantity = 5
+	pri1.OrderedQuantity = 0
+	pri1.Save()
+
+	pri2 := model.DummyPurchaseRequisitionItem()
+	pri2.PurchaseRequisition = pr
+	pri2.ItemVariant = itm2
+	pri2.Quantity = 20
+	pri2.OrderedQuantity = 0
+	pri2.Save()
+
+	m, e := GetNeedToRestock()
+	assert.NoError(t, e)
+
+	var found1, found2 bool
+	for _, j := range m {
+		if j.ID == itm1.ID {
+			found1 = true
+			assert.Equal(t, float32(5), j.OpenRequisition)
+		}
+		if j.ID == itm2.ID {
+			found2 = true
+		}
+	}
+	assert.True(t, found1, "item dengan requisition kurang dari kebutuhan tetap perlu restock")
+	assert.False(t, found2, "item yang sudah diajukan seluruhnya tidak perlu restock")
+}
@@ -0,0 +1,295 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package debitNote_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp()
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	dn := model.DummyDebitNote()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/debit-note", "GET", http.StatusOK},
+		{"/v1/debit-note/" + common.Encrypt(dn.ID), "GET", http.StatusOK},
+		{"/v1/debit-note/999999", "GET", http.StatusNotFound},
+		{"/v1/debit-note/partnership/" + common.Encrypt(dn.Partnership.ID), "GET", http.StatusOK},
+		{"/v1/debit-note/partnership/999999", "GET", http.StatusNotFound},
+		{"/v1/debit-note", "POST", http.StatusUnprocessableEntity},
+		{"/v1/debit-note/999999/cancel", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestCreateDebitNote(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.IsDeleted = 0
+	po.Save()
+
+	pr := model.DummyPurchaseReturn()
+	pr.PurchaseOrder = po
+	pr.TotalAmount = 40000
+	pr.DocumentStatus = "active"
+	pr.IsDeleted = 0
+	pr.Save()
+
+	cancelled := model.DummyPurchaseReturn()
+	cancelled.DocumentStatus = "cancelled"
+	cancelled.IsDeleted = 0
+	cancelled.Save()
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"recognition_date": "2017-08-21T00:00:00Z", "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"purchase_return_id": "abc", "recognition_date": "2017-08-21T00:00:00Z", "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"purchase_return_id": common.Encrypt(cancelled.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"purchase_return_id": common.Encrypt(pr.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 50000}, http.StatusUnprocessableEntity},
+		{tester.D{"purchase_return_id": common.Encrypt(pr.ID), "recognition_date": "2017-08-21T00:00:00Z", "amount": 10000}, http.StatusOK},
+		{tester.D{"purchase_return_id": common.Encrypt(pr.ID), "recognition_date": "2017-08-21T00:00:00Z"}, http.StatusOK},
+		{tester.D{"purchase_return_id": common.Encrypt(pr.ID), "recognition_date": "2017-08-21T00:00:00Z"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/debit-note").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	// sisa purchase return 30000 diterbitkan seluruhnya sebagai debit note
+	pr.Read()
+	assert.Equal(t, "finished", pr.DocumentStatus)
+
+	var balance float64
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(balance), 0) FROM debit_note WHERE partnership_id = ? AND document_status = 'active'", supplier.ID).QueryRow(&balance)
+	assert.Equal(t, float64(40000), balance)
+
+	// purchase return yang sudah memiliki debit note tidak bisa dicancel
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/purchase-return/"+common.Encrypt(pr.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+}
+
+func TestOffsetPurchaseInvoiceWithDebitNote(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.TotalCharge = 100000
+	po.TotalPaid = 0
+	po.IsDeleted = 0
+	po.Save()
+
+	pi := model.DummyPurchaseInvoice()
+	pi.PurchaseOrder = po
+	pi.TotalAmount = 100000
+	pi.TotalPaid = 0
+	pi.DocumentStatus = "new"
+	pi.IsDeleted = 0
+	pi.Save()
+
+	dn := model.DummyDebitNote()
+	dn.Partnership = supplier
+	dn.PurchaseReturn = nil
+	dn.Amount = 25000
+	dn.UsedAmount = 0
+	dn.Balance = 25000
+	dn.DocumentStatus = "active"
+	dn.Save()
+
+	other := model.DummyDebitNote()
+	other.PurchaseReturn = nil
+	other.Balance = 25000
+	other.DocumentStatus = "active"
+	other.Save()
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"ref_id": common.Encrypt(pi.ID), "ref_type": "purchase_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "debit_note_id": common.Encrypt(dn.ID), "amount": 30000}, http.StatusUnprocessableEntity},
+		{tester.D{"ref_id": common.Encrypt(pi.ID), "ref_type": "purchase_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "debit_note_id": common.Encrypt(other.ID), "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"ref_id": common.Encrypt(pi.ID), "ref_type": "purchase_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "debit_note_id": "abc", "amount": 10000}, http.StatusUnprocessableEntity},
+		{tester.D{"ref_id": common.Encrypt(pi.ID), "ref_type": "purchase_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "debit_note_id": common.Encrypt(dn.ID), "amount": 25000}, http.StatusOK},
+		{tester.D{"ref_id": common.Encrypt(pi.ID), "ref_type": "purchase_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "debit_note_id": common.Encrypt(dn.ID), "amount": 1000}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/finance-expense").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	dn.Read()
+	assert.Equal(t, float64(25000), dn.UsedAmount)
+	assert.Equal(t, float64(0), dn.Balance)
+	assert.Equal(t, "used", dn.DocumentStatus)
+
+	// approve offset debit note mengurangi hutang purchase invoice
+	var fe model.FinanceExpense
+	orm.NewOrm().QueryTable(new(model.FinanceExpense)).Filter("debit_note_id", dn.ID).One(&fe)
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/finance-expense/"+common.Encrypt(fe.ID)+"/approve").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+		})
+
+	pi.Read()
+	assert.Equal(t, float64(25000), pi.TotalPaid)
+
+	// offset debit note tampil pada report hutang
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.GET("/v1/report/payable/summary?supplier_id="+common.Encrypt(supplier.ID)).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Contains(t, res.Body.String(), `"debit_note_offset":25000`)
+			assert.Contains(t, res.Body.String(), `"outstanding":75000`)
+		})
+
+	// debit note yang sudah dioffset tidak bisa dicancel
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/debit-note/"+common.Encrypt(dn.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/debit-note/"+common.Encrypt(other.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+		})
+}
+
+func TestUpdateOffsetDebitNote(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.TotalCharge = 100000
+	po.TotalPaid = 0
+	po.IsDeleted = 0
+	po.Save()
+
+	pi := model.DummyPurchaseInvoice()
+	pi.PurchaseOrder = po
+	pi.TotalAmount = 100000
+	pi.TotalPaid = 0
+	pi.DocumentStatus = "new"
+	pi.IsDeleted = 0
+	pi.Save()
+
+	dn := model.DummyDebitNote()
+	dn.Partnership = supplier
+	dn.PurchaseReturn = nil
+	dn.Amount = 25000
+	dn.UsedAmount = 0
+	dn.Balance = 25000
+	dn.DocumentStatus = "active"
+	dn.Save()
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.POST("/v1/finance-expense").
+		SetJSON(tester.D{"ref_id": common.Encrypt(pi.ID), "ref_type": "purchase_invoice", "recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "debit_note_id": common.Encrypt(dn.ID), "amount": 25000}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+		})
+
+	dn.Read()
+	assert.Equal(t, float64(0), dn.Balance)
+	assert.Equal(t, "used", dn.DocumentStatus)
+
+	// mengurangi nominal offset mengembalikan saldo debit note
+	var fe model.FinanceExpense
+	orm.NewOrm().QueryTable(new(model.FinanceExpense)).Filter("debit_note_id", dn.ID).One(&fe)
+	scenario := tester.D{"recognition_date": "2017-08-21T00:00:00Z", "payment_method": "debit_note", "amount": 10000}
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/finance-expense/"+common.Encrypt(fe.ID)).
+		SetJSON(scenario).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", scenario, res.Body.String()))
+		})
+
+	dn.Read()
+	assert.Equal(t, float64(10000), dn.UsedAmount)
+	assert.Equal(t, float64(15000), dn.Balance)
+	assert.Equal(t, "active", dn.DocumentStatus)
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package debitNote
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/purchase_return"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/cuxs/validation"
+)
+
+// createRequest data struct that stored request data when requesting an create debit note process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	PurchaseReturnID string                `json:"purchase_return_id" valid:"required"`
+	RecognitionDate  time.Time             `json:"recognition_date" valid:"required"`
+	Amount           float64               `json:"amount" valid:"gte:0"`
+	Note             string                `json:"note"`
+	Session          *auth.SessionData     `json:"-"`
+	Partnership      *model.Partnership    `json:"-"`
+	PurchaseReturn   *model.PurchaseReturn `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if id, e := common.Decrypt(r.PurchaseReturnID); e != nil {
+		o.Failure("purchase_return_id", "purchase return id cannot be decrypt")
+	} else if r.PurchaseReturn, e = purchaseReturn.GetDetailPurchaseReturn("id", id); e != nil || r.PurchaseReturn.DocumentStatus == "cancelled" {
+		o.Failure("purchase_return_id", "purchase return doesn't exist")
+	} else if r.PurchaseReturn.DocumentStatus == "finished" {
+		o.Failure("purchase_return_id", "purchase return already refunded")
+	} else {
+		// supplier diambil dari purchase order
+		r.PurchaseReturn.PurchaseOrder.Read()
+		r.Partnership = r.PurchaseReturn.PurchaseOrder.Supplier
+
+		// sisa purchase return yang belum direfund cash maupun debit note
+		var revenue float64
+		orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM finance_revenue WHERE ref_id = ? AND ref_type = 'purchase_return' AND is_deleted = 0", r.PurchaseReturn.ID).QueryRow(&revenue)
+		remaining := r.PurchaseReturn.TotalAmount - revenue - purchaseReturn.SumDebitNoteAmount(r.PurchaseReturn.ID)
+
+		if r.Amount == 0 {
+			r.Amount = remaining
+		}
+
+		if remaining <= 0 || r.Amount > remaining {
+			o.Failure("amount", "amount is greater than the unrefunded amount of the purchase return")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.DebitNote {
+	code, _ := util.CodeGen("code_debit_note", "debit_note")
+
+	return &model.DebitNote{
+		Code:            code,
+		Partnership:     r.Partnership,
+		PurchaseReturn:  r.PurchaseReturn,
+		RecognitionDate: r.RecognitionDate,
+		Amount:          r.Amount,
+		Balance:         r.Amount,
+		DocumentStatus:  "active",
+		Note:            r.Note,
+		CreatedBy:       r.Session.User,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// cancelRequest data struct that stored request data when requesting an cancel debit note process.
+type cancelRequest struct {
+	DebitNote *model.DebitNote  `json:"-"`
+	Session   *auth.SessionData `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *cancelRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.DebitNote.DocumentStatus == "cancelled" {
+		o.Failure("document_status", "debit note already cancelled")
+	}
+
+	if r.DebitNote.UsedAmount > 0 || len(r.DebitNote.FinanceExpenses) > 0 {
+		o.Failure("document_status", "debit note already offset against purchase invoice")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *cancelRequest) Messages() map[string]string {
+	return map[string]string{}
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package debitNote
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/purchase_return"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// Balance saldo debit note supplier beserta debit note yang masih bisa digunakan.
+type Balance struct {
+	Partnership *model.Partnership `json:"partnership"`
+	Balance     float64            `json:"balance"`
+	DebitNotes  []*model.DebitNote `json:"debit_notes"`
+}
+
+// GetDebitNotes get all data debit note that matched with query request parameters.
+// returning slices of debit note, total data without limit and error.
+func GetDebitNotes(rq *orm.RequestQuery) (m *[]model.DebitNote, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.DebitNote))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.DebitNote
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowDebitNote find a single data debit note using field and value condition,
+// beserta finance expense yang menggunakan debit note sebagai offset purchase invoice.
+func ShowDebitNote(field string, values ...interface{}) (*model.DebitNote, error) {
+	m := new(model.DebitNote)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel().Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.Raw("SELECT * FROM finance_expense WHERE debit_note_id = ? AND is_deleted = 0 ORDER BY id", m.ID).QueryRows(&m.FinanceExpenses)
+
+	return m, nil
+}
+
+// GetPartnershipBalance mengambil saldo debit note supplier dari debit note yang masih active.
+func GetPartnershipBalance(p *model.Partnership) (b *Balance, e error) {
+	b = &Balance{Partnership: p}
+	if _, e = orm.NewOrm().QueryTable(new(model.DebitNote)).Filter("partnership_id", p.ID).Filter("document_status", "active").OrderBy("id").All(&b.DebitNotes); e == nil {
+		for _, dn := range b.DebitNotes {
+			b.Balance += dn.Balance
+		}
+	}
+
+	return
+}
+
+// CreateDebitNote menyimpan debit note baru, debit note dari purchase return
+// akan memperbarui status refund purchase return.
+func CreateDebitNote(dn *model.DebitNote) (e error) {
+	if e = dn.Save(); e == nil {
+		if dn.PurchaseReturn != nil {
+			e = checkPurchaseReturn(dn.PurchaseReturn.ID)
+		}
+	}
+
+	return
+}
+
+// CalculateDebitNote menghitung ulang jumlah debit note yang sudah dioffset ke purchase invoice,
+// debit note yang saldonya habis berubah status menjadi used.
+func CalculateDebitNote(dn *model.DebitNote) (e error) {
+	if e = dn.Read(); e != nil {
+		return
+	}
+
+	var used float64
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM finance_expense WHERE debit_note_id = ? AND is_deleted = 0", dn.ID).QueryRow(&used)
+
+	dn.UsedAmount = used
+	dn.Balance = dn.Amount - used
+	if dn.DocumentStatus != "cancelled" {
+		if dn.Balance <= 0 {
+			dn.DocumentStatus = "used"
+		} else {
+			dn.DocumentStatus = "active"
+		}
+	}
+	dn.UpdatedAt = time.Now()
+
+	return dn.Save("used_amount", "balance", "document_status", "updated_at")
+}
+
+// CancelDebitNote membatalkan debit note yang belum digunakan.
+func CancelDebitNote(dn *model.DebitNote, user *model.User) (e error) {
+	dn.DocumentStatus = "cancelled"
+	dn.Balance = 0
+	dn.UpdatedBy = user
+	dn.UpdatedAt = time.Now()
+
+	if e = dn.Save("document_status", "balance", "updated_by", "updated_at"); e == nil {
+		if dn.PurchaseReturn != nil {
+			e = checkPurchaseReturn(dn.PurchaseReturn.ID)
+		}
+	}
+
+	return
+}
+
+// checkPurchaseReturn memperbarui status refund purchase return asal debit note.
+func checkPurchaseReturn(id int64) (e error) {
+	var pr *model.PurchaseReturn
+	if pr, e = purchaseReturn.GetDetailPurchaseReturn("id", id); e == nil {
+		e = purchaseReturn.CheckRefundStatus(pr)
+	}
+
+	return
+}
@@ -0,0 +1,156 @@
+package debitNote
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDebitNotesNoData(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("DELETE FROM debit_note").Exec()
+	rq := orm.RequestQuery{}
+
+	m, total, e := GetDebitNotes(&rq)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, m)
+	assert.NoError(t, e)
+}
+
+func TestGetDebitNotes(t *testing.T) {
+	model.DummyDebitNote()
+	qs := orm.RequestQuery{}
+	_, _, e := GetDebitNotes(&qs)
+	assert.NoError(t, e, "Data should be exists.")
+}
+
+func TestShowDebitNote(t *testing.T) {
+	_, e := ShowDebitNote("id", 999999)
+	assert.Error(t, e, "Response should be error, beacuse there are no data yet.")
+
+	c := model.DummyDebitNote()
+	cd, e := ShowDebitNote("id", c.ID)
+	assert.NoError(t, e, "Data should be exists.")
+	assert.Equal(t, c.ID, cd.ID, "ID Response should be a same.")
+}
+
+func dummyPurchaseReturn(total float64) (*model.Partnership, *model.PurchaseReturn) {
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.IsDeleted = 0
+	po.Save()
+
+	pr := model.DummyPurchaseReturn()
+	pr.PurchaseOrder = po
+	pr.TotalAmount = total
+	pr.DocumentStatus = "active"
+	pr.IsDeleted = 0
+	pr.Save()
+
+	return supplier, pr
+}
+
+func TestCreateDebitNote(t *testing.T) {
+	supplier, pr := dummyPurchaseReturn(60000)
+
+	dn := &model.DebitNote{
+		Code:            "DN-TEST-1",
+		Partnership:     supplier,
+		PurchaseReturn:  pr,
+		RecognitionDate: time.Now(),
+		Amount:          60000,
+		Balance:         60000,
+		DocumentStatus:  "active",
+		CreatedBy:       model.DummyUser(),
+		CreatedAt:       time.Now(),
+	}
+	e := CreateDebitNote(dn)
+	assert.NoError(t, e)
+
+	// purchase return sudah direfund seluruhnya dengan debit note
+	pr.Read()
+	assert.Equal(t, "finished", pr.DocumentStatus)
+
+	b, e := GetPartnershipBalance(supplier)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(60000), b.Balance)
+	assert.Len(t, b.DebitNotes, 1)
+
+	// cancel debit note, purchase return kembali active
+	e = CancelDebitNote(dn, model.DummyUser())
+	assert.NoError(t, e)
+
+	pr.Read()
+	assert.Equal(t, "active", pr.DocumentStatus)
+
+	b, e = GetPartnershipBalance(supplier)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(0), b.Balance)
+}
+
+func TestCalculateDebitNote(t *testing.T) {
+	supplier, pr := dummyPurchaseReturn(30000)
+
+	dn := &model.DebitNote{
+		Code:            "DN-TEST-2",
+		Partnership:     supplier,
+		PurchaseReturn:  pr,
+		RecognitionDate: time.Now(),
+		Amount:          30000,
+		Balance:         30000,
+		DocumentStatus:  "active",
+		CreatedBy:       model.DummyUser(),
+		CreatedAt:       time.Now(),
+	}
+	e := CreateDebitNote(dn)
+	assert.NoError(t, e)
+
+	fe := model.DummyFinanceExpense()
+	fe.RefType = "purchase_invoice"
+	fe.PaymentMethod = "debit_note"
+	fe.DebitNote = dn
+	fe.Amount = 10000
+	fe.IsDeleted = 0
+	fe.Save()
+
+	e = CalculateDebitNote(dn)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(10000), dn.UsedAmount)
+	assert.Equal(t, float64(20000), dn.Balance)
+	assert.Equal(t, "active", dn.DocumentStatus)
+
+	fe2 := model.DummyFinanceExpense()
+	fe2.RefType = "purchase_invoice"
+	fe2.PaymentMethod = "debit_note"
+	fe2.DebitNote = dn
+	fe2.Amount = 20000
+	fe2.IsDeleted = 0
+	fe2.Save()
+
+	e = CalculateDebitNote(dn)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(0), dn.Balance)
+	assert.Equal(t, "used", dn.DocumentStatus)
+
+	b, e := GetPartnershipBalance(supplier)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(0), b.Balance)
+
+	// expense dihapus, saldo debit note kembali
+	fe2.IsDeleted = 1
+	fe2.Save("is_deleted")
+
+	e = CalculateDebitNote(dn)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(20000), dn.Balance)
+	assert.Equal(t, "active", dn.DocumentStatus)
+}
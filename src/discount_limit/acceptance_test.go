@@ -0,0 +1,113 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package discountLimit_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("discount_limit")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	m := model.DummyDiscountLimit()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/discount-limit", "GET", http.StatusOK},
+		{"/v1/discount-limit/" + common.Encrypt(m.ID), "GET", http.StatusOK},
+		{"/v1/discount-limit/999999", "GET", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_CreateDiscountLimit(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	ug := common.Encrypt(model.DummyUsergroup().ID)
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"usergroup_id": ug, "max_line_discount": 10, "max_order_discount": 5, "min_margin": 15}, http.StatusOK},
+		// satu usergroup hanya boleh memiliki satu batas diskon
+		{tester.D{"usergroup_id": ug, "max_line_discount": 20}, http.StatusUnprocessableEntity},
+		{tester.D{"usergroup_id": common.Encrypt(model.DummyUsergroup().ID), "max_line_discount": 120}, http.StatusUnprocessableEntity},
+		{tester.D{"usergroup_id": "999999", "max_line_discount": 10}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/discount-limit").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
+
+func TestDiscountLimitIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/discount-limit", "GET", http.StatusUnauthorized},
+		{"/v1/discount-limit", "POST", http.StatusUnauthorized},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
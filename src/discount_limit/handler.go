@@ -0,0 +1,97 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package discountLimit
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for discount limit.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("setting_discount_limit"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("setting_discount_limit"))
+	r.POST("", h.create, auth.CheckPrivilege("setting_discount_limit"))
+	r.PUT("/:id", h.update, auth.CheckPrivilege("setting_discount_limit"))
+}
+
+// get endpoint untuk mengambil semua batas diskon usergroup.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.DiscountLimit
+	if data, total, e = GetDiscountLimits(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint untuk mengambil detail batas diskon usergroup.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.DiscountLimit
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowDiscountLimit("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk membuat batas diskon usergroup.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r limitRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// update endpoint untuk mengubah batas diskon usergroup.
+func (h *Handler) update(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r limitRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.DiscountLimit, e = ShowDiscountLimit("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save(); e == nil {
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
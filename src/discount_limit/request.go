@@ -0,0 +1,81 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package discountLimit
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// limitRequest data struct that stored request data when requesting an create or update discount limit process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type limitRequest struct {
+	UsergroupID      string               `json:"usergroup_id" valid:"required"`
+	MaxLineDiscount  float32              `json:"max_line_discount" valid:"gte:0|lte:100"`
+	MaxOrderDiscount float32              `json:"max_order_discount" valid:"gte:0|lte:100"`
+	MinMargin        float32              `json:"min_margin" valid:"gte:0|lte:100"`
+	Note             string               `json:"note"`
+	Session          *auth.SessionData    `json:"-"`
+	DiscountLimit    *model.DiscountLimit `json:"-"`
+
+	Usergroup *model.Usergroup `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *limitRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if id, e := common.Decrypt(r.UsergroupID); e == nil {
+		r.Usergroup = &model.Usergroup{ID: id}
+		if e = r.Usergroup.Read(); e != nil {
+			o.Failure("usergroup_id", "usergroup_id doesn't exist")
+		} else {
+			// satu usergroup hanya memiliki satu batas diskon
+			m := &model.DiscountLimit{Usergroup: r.Usergroup}
+			if e = m.Read("Usergroup"); e == nil && (r.DiscountLimit == nil || r.DiscountLimit.ID != m.ID) {
+				o.Failure("usergroup_id", "usergroup already has discount limit")
+			}
+		}
+	} else {
+		o.Failure("usergroup_id", "usergroup_id cannot be decrypt")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *limitRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *limitRequest) Transform() *model.DiscountLimit {
+	m := r.DiscountLimit
+	if m == nil {
+		m = &model.DiscountLimit{
+			CreatedBy: r.Session.User,
+			CreatedAt: time.Now(),
+		}
+	} else {
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	m.Usergroup = r.Usergroup
+	m.MaxLineDiscount = r.MaxLineDiscount
+	m.MaxOrderDiscount = r.MaxOrderDiscount
+	m.MinMargin = r.MinMargin
+	m.Note = r.Note
+
+	return m
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package discountLimit
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// GetDiscountLimits get all data discount_limit that matched with query request parameters.
+// returning slices of discount limit, total data without limit and error.
+func GetDiscountLimits(rq *orm.RequestQuery) (m *[]model.DiscountLimit, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.DiscountLimit))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.DiscountLimit
+	if _, err = q.RelatedSel("Usergroup").All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowDiscountLimit untuk mengambil data detail discount limit berdasarkan param
+func ShowDiscountLimit(field string, values ...interface{}) (*model.DiscountLimit, error) {
+	m := new(model.DiscountLimit)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel("Usergroup").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
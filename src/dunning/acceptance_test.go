@@ -0,0 +1,123 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dunning_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("dunning_log")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	model.DummyDunningLog()
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.Save("PartnershipType")
+	id := common.Encrypt(customer.ID)
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.Save("PartnershipType")
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/dunning", "GET", http.StatusOK},
+		{"/v1/dunning/customer/" + id + "/pause", "PUT", http.StatusOK},
+		{"/v1/dunning/customer/" + id + "/resume", "PUT", http.StatusOK},
+		{"/v1/dunning/customer/" + common.Encrypt(supplier.ID) + "/pause", "PUT", http.StatusNotFound},
+		{"/v1/dunning/customer/999999/pause", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestPauseDunning(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDunningPaused = 0
+	customer.Save("PartnershipType", "IsDunningPaused")
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/dunning/customer/"+common.Encrypt(customer.ID)+"/pause").Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		customer.Read()
+		assert.Equal(t, int8(1), customer.IsDunningPaused)
+	})
+
+	ng.PUT("/v1/dunning/customer/"+common.Encrypt(customer.ID)+"/resume").Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		customer.Read()
+		assert.Equal(t, int8(0), customer.IsDunningPaused)
+	})
+}
+
+func TestDunningIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/dunning", "GET", http.StatusUnauthorized},
+		{"/v1/dunning/customer/999999/pause", "PUT", http.StatusUnauthorized},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
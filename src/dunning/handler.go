@@ -0,0 +1,67 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dunning
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/partnership"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for dunning.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("dunning_read"))
+	r.PUT("/customer/:id/pause", h.pause, auth.CheckPrivilege("dunning_pause"))
+	r.PUT("/customer/:id/resume", h.resume, auth.CheckPrivilege("dunning_pause"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.DunningLog
+	if data, total, e = GetDunningLogs(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// pause endpoint untuk menghentikan pengiriman email pengingat ke customer.
+func (h *Handler) pause(c echo.Context) (e error) {
+	return h.setPause(c, true)
+}
+
+// resume endpoint untuk melanjutkan pengiriman email pengingat ke customer.
+func (h *Handler) resume(c echo.Context) (e error) {
+	return h.setPause(c, false)
+}
+
+func (h *Handler) setPause(c echo.Context, paused bool) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.Partnership
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = partnership.GetPartnershipByField("id", id); e == nil && m.PartnershipType == "customer" {
+			if e = PauseDunning(m, paused); e == nil {
+				ctx.Data(m)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
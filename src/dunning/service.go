@@ -0,0 +1,261 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dunning
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/mailer"
+	"git.qasico.com/cuxs/orm"
+	"github.com/labstack/gommon/log"
+)
+
+// defaultIntervals interval hari keterlambatan yang dipakai
+// jika application_setting dunning_interval belum diisi.
+var defaultIntervals = []int{3, 7, 14}
+
+// reminderData data yang bisa dipakai pada template email dunning.
+type reminderData struct {
+	CompanyName  string
+	CustomerName string
+	InvoiceCode  string
+	OrderCode    string
+	DueDate      string
+	DaysOverdue  int
+	TotalAmount  string
+	TotalPaid    string
+	Outstanding  string
+}
+
+// Cron menjalankan pengiriman email pengingat pembayaran setiap hari.
+func Cron() {
+	if _, e := SendReminders(time.Now()); e != nil {
+		log.Error(e)
+	}
+}
+
+// GetDunningLogs get all data dunning_log that matched with query request parameters.
+// returning slices of dunning log, total data without limit and error.
+func GetDunningLogs(rq *orm.RequestQuery) (m *[]model.DunningLog, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.DunningLog))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.DunningLog
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// GetIntervals mengambil interval hari keterlambatan dari application_setting dunning_interval,
+// contoh value "3,7,14" artinya email dikirim saat invoice terlambat 3, 7 dan 14 hari.
+func GetIntervals() (intervals []int) {
+	if s, e := util.GetApplicationSetting("application_setting_name", "dunning_interval"); e == nil {
+		for _, v := range strings.Split(s.Value, ",") {
+			if i := common.ToInt(strings.TrimSpace(v)); i > 0 && !util.HasElem(intervals, i) {
+				intervals = append(intervals, i)
+			}
+		}
+	}
+
+	if len(intervals) == 0 {
+		intervals = defaultIntervals
+	}
+
+	sort.Ints(intervals)
+
+	return
+}
+
+// GetOverdueInvoices mengambil semua sales invoice yang belum lunas dan sudah lewat jatuh tempo,
+// customer yang dunning nya di pause atau tidak memiliki email tidak diikutkan.
+func GetOverdueInvoices(now time.Time) (m []*model.SalesInvoice, err error) {
+	o := orm.NewOrm()
+	_, err = o.Raw("SELECT si.* FROM sales_invoice si "+
+		"INNER JOIN sales_order so ON so.id = si.sales_order_id "+
+		"INNER JOIN partnership p ON p.id = so.customer_id "+
+		"WHERE si.is_deleted = 0 AND si.document_status != 'finished' AND si.due_date < ? "+
+		"AND so.is_deleted = 0 AND so.document_status NOT IN ('requested_cancel', 'approved_cancel') "+
+		"AND p.is_deleted = 0 AND p.is_default = 0 AND p.is_dunning_paused = 0 "+
+		"AND p.email IS NOT NULL AND p.email != '' "+
+		"ORDER BY si.due_date ASC;", now.Format("2006-01-02")).QueryRows(&m)
+
+	return
+}
+
+// SendReminders mengirim email pengingat untuk setiap sales invoice yang terlambat,
+// setiap interval hanya dikirim satu kali per invoice dan dicatat pada dunning_log.
+func SendReminders(now time.Time) (logs []*model.DunningLog, err error) {
+	var invoices []*model.SalesInvoice
+	if invoices, err = GetOverdueInvoices(now); err != nil {
+		return nil, err
+	}
+
+	intervals := GetIntervals()
+	for _, si := range invoices {
+		days := DaysOverdue(si.DueDate, now)
+		if interval := nextInterval(si.ID, days, intervals); interval > 0 {
+			if dl, e := sendReminder(si, interval, days); e == nil {
+				logs = append(logs, dl)
+			} else {
+				log.Error(e)
+			}
+		}
+	}
+
+	return
+}
+
+// DaysOverdue menghitung jumlah hari keterlambatan dari tanggal jatuh tempo.
+func DaysOverdue(dueDate time.Time, now time.Time) int {
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(today.Sub(due).Hours() / 24)
+}
+
+// PauseDunning untuk menghentikan sementara atau melanjutkan kembali
+// pengiriman email pengingat ke customer.
+func PauseDunning(p *model.Partnership, paused bool) error {
+	p.IsDunningPaused = int8(0)
+	if paused {
+		p.IsDunningPaused = int8(1)
+	}
+	p.UpdatedAt = time.Now()
+
+	return p.Save("IsDunningPaused", "UpdatedAt")
+}
+
+// nextInterval mengambil interval terbesar yang sudah tercapai dan belum pernah dikirim,
+// sehingga jika cron sempat tidak berjalan pengingat tetap terkirim di hari berikutnya.
+func nextInterval(salesInvoiceID int64, days int, intervals []int) (interval int) {
+	var last int
+	orm.NewOrm().Raw("SELECT MAX(interval_day) FROM dunning_log WHERE sales_invoice_id = ? AND status = 'sent';", salesInvoiceID).QueryRow(&last)
+
+	for _, i := range intervals {
+		if i <= days && i > last {
+			interval = i
+		}
+	}
+
+	return
+}
+
+// sendReminder mengirim email pengingat ke customer lalu menyimpan hasil pengiriman ke dunning_log.
+func sendReminder(si *model.SalesInvoice, interval int, days int) (dl *model.DunningLog, e error) {
+	if e = si.SalesOrder.Read(); e != nil {
+		return nil, e
+	}
+
+	customer := si.SalesOrder.Customer
+	if e = customer.Read(); e != nil {
+		return nil, e
+	}
+
+	outstanding := si.TotalAmount - si.TotalPaid
+	dl = &model.DunningLog{
+		SalesInvoice: si,
+		Partnership:  customer,
+		IntervalDay:  interval,
+		DaysOverdue:  days,
+		Email:        customer.Email,
+		Outstanding:  outstanding,
+		Status:       "sent",
+		SentAt:       time.Now(),
+	}
+
+	data := reminderData{
+		CustomerName: customer.FullName,
+		InvoiceCode:  si.Code,
+		OrderCode:    si.SalesOrder.Code,
+		DueDate:      si.DueDate.Format("02/01/2006"),
+		DaysOverdue:  days,
+		TotalAmount:  formatAmount(si.TotalAmount),
+		TotalPaid:    formatAmount(si.TotalPaid),
+		Outstanding:  formatAmount(outstanding),
+	}
+	if cn, err := util.GetApplicationSetting("application_setting_name", "company_name"); err == nil {
+		data.CompanyName = cn.Value
+	}
+
+	var body string
+	if dl.Subject, e = render("dunning_subject", data); e == nil {
+		if body, e = render("dunning_template", data); e == nil {
+			m := mailer.NewMessage()
+			m.SetRecipient(customer.Email)
+			m.SetSubject(dl.Subject)
+			m.SetBody("text/html", body)
+
+			e = mailer.NewDialer().DialAndSend(m)
+		}
+	}
+
+	// pengiriman yang gagal tetap dicatat agar bisa dilihat pada log
+	if e != nil {
+		dl.Status = "failed"
+		dl.Note = e.Error()
+	}
+
+	if err := dl.Save(); err != nil {
+		return nil, err
+	}
+
+	return dl, e
+}
+
+// render membuat text dari template yang disimpan pada application_setting.
+func render(settingName string, data reminderData) (s string, e error) {
+	var setting *model.ApplicationSetting
+	if setting, e = util.GetApplicationSetting("application_setting_name", settingName); e == nil {
+		var t *template.Template
+		if t, e = template.New(settingName).Parse(setting.Value); e == nil {
+			var buf bytes.Buffer
+			if e = t.Execute(&buf, data); e == nil {
+				s = buf.String()
+			}
+		}
+	}
+
+	return
+}
+
+// formatAmount format angka menjadi format rupiah, contoh 1500000 menjadi 1.500.000
+func formatAmount(amount float64) string {
+	s := fmt.Sprintf("%.0f", amount)
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	var parts []string
+	for len(s) > 3 {
+		parts = append([]string{s[len(s)-3:]}, parts...)
+		s = s[:len(s)-3]
+	}
+	parts = append([]string{s}, parts...)
+
+	s = strings.Join(parts, ".")
+	if neg {
+		s = "-" + s
+	}
+
+	return s
+}
@@ -0,0 +1,181 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dunning
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+// dummyOverdueInvoice membuat sales invoice yang sudah lewat jatuh tempo sesuai jumlah hari.
+func dummyOverdueInvoice(days int) *model.SalesInvoice {
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.Email = strings.ToLower(common.RandomStr(8)) + "@example.com"
+	customer.IsDeleted = 0
+	customer.IsDefault = 0
+	customer.IsDunningPaused = 0
+	customer.Save()
+
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.DocumentStatus = "active"
+	so.IsDeleted = 0
+	so.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.DueDate = time.Now().AddDate(0, 0, -days)
+	si.TotalAmount = 1500000
+	si.TotalPaid = 500000
+	si.DocumentStatus = "active"
+	si.IsDeleted = 0
+	si.Save()
+
+	return si
+}
+
+// receivedBy mengambil email yang diterima smtp server untuk alamat email tertentu.
+func receivedBy(s *test.SMTPServer, email string) (m []test.SMTPMessage) {
+	for _, msg := range s.Messages() {
+		for _, to := range msg.To {
+			if to == email {
+				m = append(m, msg)
+			}
+		}
+	}
+	return
+}
+
+func TestGetIntervals(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("UPDATE application_setting SET value = ? WHERE application_setting_name = 'dunning_interval'", "14, 3,7,3").Exec()
+
+	assert.Equal(t, []int{3, 7, 14}, GetIntervals())
+
+	o.Raw("UPDATE application_setting SET value = ? WHERE application_setting_name = 'dunning_interval'", "").Exec()
+	assert.Equal(t, defaultIntervals, GetIntervals())
+
+	o.Raw("UPDATE application_setting SET value = ? WHERE application_setting_name = 'dunning_interval'", "3,7,14").Exec()
+}
+
+func TestDaysOverdue(t *testing.T) {
+	now := time.Date(2018, 2, 10, 15, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, DaysOverdue(time.Date(2018, 2, 10, 0, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, 3, DaysOverdue(time.Date(2018, 2, 7, 0, 0, 0, 0, time.UTC), now))
+	assert.Equal(t, 14, DaysOverdue(time.Date(2018, 1, 27, 0, 0, 0, 0, time.UTC), now))
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "0", formatAmount(0))
+	assert.Equal(t, "500", formatAmount(500))
+	assert.Equal(t, "1.500.000", formatAmount(1500000))
+	assert.Equal(t, "-25.000", formatAmount(-25000))
+}
+
+func TestGetOverdueInvoices(t *testing.T) {
+	si := dummyOverdueInvoice(5)
+
+	// invoice yang belum jatuh tempo tidak diikutkan
+	notDue := dummyOverdueInvoice(-2)
+
+	// invoice yang sudah lunas tidak diikutkan
+	paid := dummyOverdueInvoice(5)
+	paid.DocumentStatus = "finished"
+	paid.Save("DocumentStatus")
+
+	m, e := GetOverdueInvoices(time.Now())
+	assert.NoError(t, e)
+
+	var ids []int64
+	for _, i := range m {
+		ids = append(ids, i.ID)
+	}
+	assert.Contains(t, ids, si.ID)
+	assert.NotContains(t, ids, notDue.ID)
+	assert.NotContains(t, ids, paid.ID)
+}
+
+func TestSendReminders(t *testing.T) {
+	s, e := test.StartSMTPServer()
+	assert.NoError(t, e)
+	defer s.Close()
+
+	si := dummyOverdueInvoice(8)
+	si.SalesOrder.Customer.Read()
+	email := si.SalesOrder.Customer.Email
+
+	_, e = SendReminders(time.Now())
+	assert.NoError(t, e)
+
+	// terlambat 8 hari, sehingga yang dikirim adalah interval 7 hari
+	msg := receivedBy(s, email)
+	assert.Equal(t, 1, len(msg))
+
+	dl := &model.DunningLog{SalesInvoice: si}
+	assert.NoError(t, dl.Read("SalesInvoice"))
+	assert.Equal(t, 7, dl.IntervalDay)
+	assert.Equal(t, 8, dl.DaysOverdue)
+	assert.Equal(t, "sent", dl.Status)
+	assert.Equal(t, email, dl.Email)
+	assert.Equal(t, float64(1000000), dl.Outstanding)
+
+	// interval yang sama tidak dikirim lagi
+	SendReminders(time.Now())
+	assert.Equal(t, 1, len(receivedBy(s, email)))
+
+	// interval berikutnya dikirim saat sudah terlambat 14 hari
+	SendReminders(time.Now().AddDate(0, 0, 6))
+	assert.Equal(t, 2, len(receivedBy(s, email)))
+
+	var total int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM dunning_log WHERE sales_invoice_id = ?", si.ID).QueryRow(&total)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestSendRemindersPaused(t *testing.T) {
+	s, e := test.StartSMTPServer()
+	assert.NoError(t, e)
+	defer s.Close()
+
+	si := dummyOverdueInvoice(3)
+	customer := si.SalesOrder.Customer
+	customer.Read()
+
+	assert.NoError(t, PauseDunning(customer, true))
+
+	SendReminders(time.Now())
+	assert.Empty(t, receivedBy(s, customer.Email))
+
+	assert.NoError(t, PauseDunning(customer, false))
+
+	SendReminders(time.Now())
+	assert.Equal(t, 1, len(receivedBy(s, customer.Email)))
+}
+
+func TestSendRemindersFailed(t *testing.T) {
+	s, e := test.StartSMTPServer()
+	assert.NoError(t, e)
+	s.Close()
+
+	si := dummyOverdueInvoice(3)
+
+	SendReminders(time.Now())
+
+	// pengiriman yang gagal tetap dicatat
+	dl := &model.DunningLog{SalesInvoice: si}
+	assert.NoError(t, dl.Read("SalesInvoice"))
+	assert.Equal(t, "failed", dl.Status)
+	assert.NotEmpty(t, dl.Note)
+}
@@ -7,6 +7,7 @@ package financeExpense
 import (
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/debit_note"
 
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/cuxs"
@@ -66,6 +67,9 @@ func (h *Handler) create(c echo.Context) (e error) {
 		if e = ctx.Bind(&r); e == nil {
 			fexpense = r.Transform()
 			if e = fexpense.Save(); e == nil {
+				if fexpense.DebitNote != nil {
+					debitNote.CalculateDebitNote(fexpense.DebitNote)
+				}
 				ctx.Data(fexpense)
 			}
 		}
@@ -84,7 +88,10 @@ func (h *Handler) update(c echo.Context) (e error) {
 			if r.FinanceExpense, e = ShowFinanceExpense("id", r.ID); e == nil {
 				if e = ctx.Bind(&r); e == nil {
 					fexpense = r.Transform()
-					if e = fexpense.Save("RecognitionDate", "Amount", "PaymentMethod", "BankNumber", "BankName", "BankHolder", "Note", "UpdatedAt", "UpdatedBy"); e == nil {
+					if e = fexpense.Save("RecognitionDate", "Amount", "PaymentMethod", "BankNumber", "BankName", "BankHolder", "BankAccount", "Note", "UpdatedAt", "UpdatedBy"); e == nil {
+						if fexpense.DebitNote != nil {
+							debitNote.CalculateDebitNote(fexpense.DebitNote)
+						}
 						ctx.Data(fexpense)
 					}
 				}
@@ -5,6 +5,8 @@ import (
 
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/debit_note"
+	"git.qasico.com/mj/api/src/purchase_invoice"
 	"git.qasico.com/mj/api/src/sales_return"
 
 	"git.qasico.com/cuxs/common"
@@ -20,14 +22,18 @@ type createRequest struct {
 	RefType         string    `json:"ref_type" valid:"required|in:purchase_invoice,sales_return"`
 	RefID           string    `json:"ref_id" valid:"required"`
 	RecognitionDate time.Time `json:"recognition_date" valid:"required"`
-	PaymentMethod   string    `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro"`
+	PaymentMethod   string    `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro,debit_note"`
 	BankNumber      string    `json:"bank_number"`
 	BankName        string    `json:"bank_name"`
 	BankHolder      string    `json:"bank_holder"`
 	GiroNumber      string    `json:"giro_number"`
+	DebitNoteID     string    `json:"debit_note_id"`
+	BankAccountID   string    `json:"bank_account_id"`
 	Amount          float64   `json:"amount" valid:"required"`
 	Note            string    `json:"note"`
 	Session         *auth.SessionData
+	DebitNote       *model.DebitNote   `json:"-"`
+	BankAccount     *model.BankAccount `json:"-"`
 }
 
 // Validate implement validation.Requests interfaces.
@@ -63,9 +69,17 @@ func (r *createRequest) Validate() *validation.Output {
 					pinvoice.DocumentStatus = "active"
 					pinvoice.Save("DocumentStatus")
 				}
+
+				if r.PaymentMethod == "debit_note" {
+					r.validDebitNote(o, &pinvoice)
+				}
 			}
 		}
 	} else {
+		if r.PaymentMethod == "debit_note" {
+			o.Failure("payment_method", "Debit note can only be offset against purchase invoice")
+		}
+
 		var sreturn *model.SalesReturn
 		sreturn, err := salesReturn.ShowSalesReturn("id", refID)
 		if err != nil && sreturn == nil || sreturn.IsDeleted == int8(1) || sreturn.DocumentStatus == "cancelled" {
@@ -79,6 +93,8 @@ func (r *createRequest) Validate() *validation.Output {
 				// jumlahkan semua amount dari expense yang memiliki referensi dari sales return yang diinputkan
 				var sumSR float64
 				orm.NewOrm().Raw("SELECT SUM(fe.amount) AS amount FROM finance_expense fe WHERE fe.ref_id = ? AND fe.ref_type = ?;", sreturn.ID, "sales_return").QueryRow(&sumSR)
+				// sales return yang sudah diterbitkan credit note tidak bisa direfund lagi sejumlah credit note
+				sumSR += salesReturn.SumCreditNoteAmount(sreturn.ID)
 				// check jumlah amount diatas > total amount sales return
 				if (sumSR + r.Amount) > sreturn.TotalAmount {
 					o.Failure("amount", "You pay too much from total amount in sales return")
@@ -132,9 +148,61 @@ func (r *createRequest) Validate() *validation.Output {
 		}
 	}
 
+	r.BankAccount = validBankAccount(o, r.PaymentMethod, r.BankAccountID)
+
 	return o
 }
 
+// validDebitNote memastikan debit note masih active, milik supplier purchase invoice
+// dan saldonya mencukupi amount yang dioffset.
+func (r *createRequest) validDebitNote(o *validation.Output, pinvoice *model.PurchaseInvoice) {
+	id, e := common.Decrypt(r.DebitNoteID)
+	if e != nil {
+		o.Failure("debit_note_id", "Debit note id cannot be decrypt")
+		return
+	}
+
+	if r.DebitNote, e = debitNote.ShowDebitNote("id", id); e != nil || r.DebitNote.DocumentStatus != "active" {
+		o.Failure("debit_note_id", "Debit note doesn't exist or already used")
+		return
+	}
+
+	if pinvoice.PurchaseOrder == nil || pinvoice.PurchaseOrder.Supplier == nil || pinvoice.PurchaseOrder.Supplier.ID != r.DebitNote.Partnership.ID {
+		o.Failure("debit_note_id", "Debit note doesn't belong to the supplier of the purchase invoice")
+	}
+
+	if r.Amount > r.DebitNote.Balance {
+		o.Failure("amount", "Amount is greater than the balance of the debit note")
+	}
+}
+
+// validBankAccount memastikan rekening sumber pembayaran non tunai masih aktif,
+// pembayaran cash dan debit note tidak memakai rekening.
+func validBankAccount(o *validation.Output, method string, bankAccountID string) *model.BankAccount {
+	if bankAccountID == "" {
+		return nil
+	}
+
+	if method == "cash" || method == "debit_note" {
+		o.Failure("bank_account_id", "Bank account can't be inputted if payment method is "+method)
+		return nil
+	}
+
+	id, e := common.Decrypt(bankAccountID)
+	if e != nil {
+		o.Failure("bank_account_id", "Bank account id cannot be decrypt")
+		return nil
+	}
+
+	ba := &model.BankAccount{ID: id}
+	if e = ba.Read(); e != nil || ba.IsArchived == int8(1) || ba.AccountType != "bank" {
+		o.Failure("bank_account_id", "Bank account doesn't exist or already archived")
+		return nil
+	}
+
+	return ba
+}
+
 // Messages implement validation.Requests interfaces
 // return custom messages when validation fails.
 func (r *createRequest) Messages() map[string]string {
@@ -154,6 +222,8 @@ func (r *createRequest) Transform() *model.FinanceExpense {
 		PaymentMethod:   r.PaymentMethod,
 		BankName:        r.BankName,
 		BankHolder:      r.BankHolder,
+		DebitNote:       r.DebitNote,
+		BankAccount:     r.BankAccount,
 		DocumentStatus:  "uncleared",
 		Note:            r.Note,
 		CreatedAt:       time.Now(),
@@ -173,15 +243,17 @@ func (r *createRequest) Transform() *model.FinanceExpense {
 type updateRequest struct {
 	ID              int64
 	RecognitionDate time.Time `json:"recognition_date" valid:"required"`
-	PaymentMethod   string    `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro"`
+	PaymentMethod   string    `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro,debit_note"`
 	BankNumber      string    `json:"bank_number"`
 	BankName        string    `json:"bank_name"`
 	BankHolder      string    `json:"bank_holder"`
 	GiroNumber      string    `json:"giro_number"`
+	BankAccountID   string    `json:"bank_account_id"`
 	Amount          float64   `json:"amount" valid:"required"`
 	Note            string    `json:"note"`
 	FinanceExpense  *model.FinanceExpense
 	Session         *auth.SessionData
+	BankAccount     *model.BankAccount `json:"-"`
 }
 
 // Validate implement validation.Requests interfaces.
@@ -192,6 +264,17 @@ func (r *updateRequest) Validate() *validation.Output {
 		o.Failure("document_status", "Finance expense document status already cleared")
 	}
 
+	// offset debit note tidak bisa diubah ke metode pembayaran lain, begitu juga sebaliknya
+	if (r.FinanceExpense.PaymentMethod == "debit_note") != (r.PaymentMethod == "debit_note") {
+		o.Failure("payment_method", "Payment method debit note can't be changed")
+	} else if r.FinanceExpense.DebitNote != nil {
+		dn := &model.DebitNote{ID: r.FinanceExpense.DebitNote.ID}
+		dn.Read()
+		if r.Amount > dn.Balance+r.FinanceExpense.Amount {
+			o.Failure("amount", "Amount is greater than the balance of the debit note")
+		}
+	}
+
 	// Ambil ref_type yang dipilih
 	// ambil document_status dari purchase_invoice yang dipilih
 	if r.FinanceExpense.RefType == "purchase_invoice" {
@@ -286,6 +369,8 @@ func (r *updateRequest) Validate() *validation.Output {
 		}
 	}
 
+	r.BankAccount = validBankAccount(o, r.PaymentMethod, r.BankAccountID)
+
 	return o
 }
 
@@ -307,6 +392,8 @@ func (r *updateRequest) Transform() *model.FinanceExpense {
 		BankNumber:      r.BankNumber,
 		BankName:        r.BankName,
 		BankHolder:      r.BankHolder,
+		BankAccount:     r.BankAccount,
+		DebitNote:       r.FinanceExpense.DebitNote,
 		Note:            r.Note,
 		UpdatedAt:       time.Now(),
 		UpdatedBy:       r.Session.User,
@@ -332,6 +419,11 @@ func (r *approveRequest) Validate() *validation.Output {
 	if r.FinanceExpense.DocumentStatus == "cleared" {
 		o.Failure("document_status", "Finance expense document status already cleared")
 	}
+
+	// invoice yang masih memiliki mismatch three-way match tidak bisa dibayar
+	if r.FinanceExpense.RefType == "purchase_invoice" && purchaseInvoice.HasOpenMismatch(int64(r.FinanceExpense.RefID)) {
+		o.Failure("ref_id", "Purchase invoice has unresolved three-way match mismatch")
+	}
 	return o
 }
 
@@ -8,6 +8,8 @@ import (
 	"errors"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
+	"git.qasico.com/mj/api/src/purchase_invoice"
 	"git.qasico.com/mj/api/src/sales_return"
 
 	"git.qasico.com/cuxs/orm"
@@ -64,6 +66,11 @@ func SumExpenseAmount(refID uint64, refType string) (total float64, err error) {
 
 // ApproveExpense untuk melakukan approve pada finance expense
 func ApproveExpense(exp *model.FinanceExpense) (e error) {
+	// pembayaran purchase invoice ditahan sampai seluruh mismatch three-way match diresolve
+	if exp.RefType == "purchase_invoice" && purchaseInvoice.HasOpenMismatch(int64(exp.RefID)) {
+		return errors.New("purchase invoice has unresolved three-way match mismatch")
+	}
+
 	// ubah status expense menjadi cleared
 	exp.DocumentStatus = "cleared"
 	if e = exp.Save("document_status"); e == nil {
@@ -75,6 +82,10 @@ func ApproveExpense(exp *model.FinanceExpense) (e error) {
 		} else {
 			e = errors.New("refType is wrong")
 		}
+
+		if e == nil {
+			generalLedger.AutoPost("finance_expense", exp.ID)
+		}
 	}
 	return
 }
@@ -129,18 +140,10 @@ func purchaseInvoiceExpenseStatusCheck(exp *model.FinanceExpense, poInvoice *mod
 
 // salesReturnExpense proses update amount pada sales return
 func salesReturnExpense(exp *model.FinanceExpense) (e error) {
-	var sum float64
 	var salesRet *model.SalesReturn
-	if sum, e = SumExpenseAmount(exp.RefID, exp.RefType); e == nil {
-		if salesRet, e = salesReturn.ShowSalesReturn("id", exp.RefID); e == nil {
-			// cek amount sales return dan amount dari semua expense
-			if salesRet.TotalAmount == sum {
-				salesRet.DocumentStatus = "finished"
-			} else {
-				salesRet.DocumentStatus = "active"
-			}
-			e = salesRet.Save("document_status")
-		}
+	if salesRet, e = salesReturn.ShowSalesReturn("id", exp.RefID); e == nil {
+		// cek amount sales return dan amount dari semua expense dan credit note
+		e = salesReturn.CheckRefundStatus(salesRet)
 	}
 	return
 }
@@ -270,3 +270,40 @@ func TestApproveExpense2(t *testing.T) {
 	assert.Equal(t, "finished", ret.DocumentStatus)
 
 }
+
+// TestApproveExpenseOpenMismatch purchase invoice dengan mismatch three-way match yang belum diresolve tidak bisa dibayar
+func TestApproveExpenseOpenMismatch(t *testing.T) {
+	mm := model.DummyPurchaseInvoiceMismatch()
+	mm.Status = "open"
+	mm.Save()
+
+	inv := mm.PurchaseInvoice
+	inv.TotalAmount = float64(50000)
+	inv.DocumentStatus = "active"
+	inv.IsDeleted = int8(0)
+	inv.Save()
+
+	exp := model.DummyFinanceExpense()
+	exp.IsDeleted = int8(0)
+	exp.DocumentStatus = "uncleared"
+	exp.Amount = float64(10000)
+	exp.RefType = "purchase_invoice"
+	exp.RefID = uint64(inv.ID)
+	exp.Save()
+
+	e := ApproveExpense(exp)
+	assert.Error(t, e)
+
+	exp.Read("ID")
+	assert.Equal(t, "uncleared", exp.DocumentStatus)
+
+	// setelah mismatch diresolve invoice bisa dibayar
+	mm.Status = "resolved"
+	mm.Save("Status")
+
+	e = ApproveExpense(exp)
+	assert.NoError(t, e)
+
+	exp.Read("ID")
+	assert.Equal(t, "cleared", exp.DocumentStatus)
+}
@@ -7,6 +7,7 @@ package financeRevenue
 import (
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/credit_note"
 	"git.qasico.com/mj/api/src/sales_invoice"
 
 	"git.qasico.com/cuxs/common"
@@ -65,6 +66,10 @@ func (h *Handler) create(c echo.Context) (e error) {
 		if e = ctx.Bind(&r); e == nil {
 			fr := r.Transform()
 			if e = fr.Save(); e == nil {
+				if fr.CreditNote != nil {
+					creditNote.CalculateCreditNote(fr.CreditNote)
+				}
+
 				if fr.RefType == "sales_invoice" {
 					salesInvoice.SumTotalRevenuedSalesInvoice(int64(fr.RefID))
 					finance, _ := ShowFinanceRevenue("id", fr.ID)
@@ -92,6 +97,10 @@ func (h *Handler) update(c echo.Context) (e error) {
 				if e = ctx.Bind(&r); e == nil {
 					fr := r.Transform()
 					if e = fr.Save(); e == nil {
+						if fr.CreditNote != nil {
+							creditNote.CalculateCreditNote(fr.CreditNote)
+						}
+
 						if fr.RefType == "sales_invoice" {
 							salesInvoice.SumTotalRevenuedSalesInvoice(int64(fr.RefID))
 						}
@@ -9,6 +9,8 @@ import (
 
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/credit_note"
+	"git.qasico.com/mj/api/src/purchase_return"
 
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/validation"
@@ -22,14 +24,16 @@ type createRequest struct {
 	RefID           string    `json:"ref_id" valid:"required"`
 	RefType         string    `json:"ref_type" valid:"required|in:sales_invoice,purchase_return"`
 	RecognitionDate time.Time `json:"recognition_date" valid:"required"`
-	PaymentMethod   string    `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro"`
+	PaymentMethod   string    `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro,transfer,credit_note"`
 	Amount          float64   `json:"amount" valid:"required"`
 	BankName        string    `json:"bank_name"`
 	BankNumber      string    `json:"bank_number"`
 	BankHolder      string    `json:"bank_holder"`
 	BankAccountID   string    `json:"bank_account_id"`
+	CreditNoteID    string    `json:"credit_note_id"`
 	Note            string    `json:"note"`
 	Session         *auth.SessionData
+	CreditNote      *model.CreditNote `json:"-"`
 }
 
 // Validate implement validation.Requests interfaces.
@@ -67,8 +71,14 @@ func (r *createRequest) Validate() *validation.Output {
 						si.Save("DocumentStatus")
 					}
 				}
+
+				if r.PaymentMethod == "credit_note" {
+					r.validCreditNote(o, si)
+				}
 			}
 		}
+	} else if r.PaymentMethod == "credit_note" {
+		o.Failure("payment_method", "credit note can only be used to pay sales_invoice")
 	}
 
 	if r.RefType == "purchase_return" {
@@ -83,6 +93,8 @@ func (r *createRequest) Validate() *validation.Output {
 			} else {
 				// jumlahkan semua amount dari finance_revenue yang memiliki referensi dari purchase return yang diinput
 				amountFr := SumAmountFinanceRevenue(uint64(pr.ID), r.RefType, 0)
+				// purchase return yang sudah diterbitkan debit note tidak bisa direfund lagi sejumlah debit note
+				amountFr += purchaseReturn.SumDebitNoteAmount(pr.ID)
 				amountFr += r.Amount
 				// cek jumlah (sum amount finance revenue + amount inputan) > total amount di purchase return
 				if amountFr > pr.TotalAmount {
@@ -127,6 +139,30 @@ func (r *createRequest) Validate() *validation.Output {
 	return o
 }
 
+// validCreditNote memastikan credit note masih active, milik customer sales invoice
+// dan saldonya mencukupi amount pembayaran.
+func (r *createRequest) validCreditNote(o *validation.Output, si *model.SalesInvoice) {
+	id, e := common.Decrypt(r.CreditNoteID)
+	if e != nil {
+		o.Failure("credit_note_id", "credit note id cannot be decrypt")
+		return
+	}
+
+	if r.CreditNote, e = creditNote.ShowCreditNote("id", id); e != nil || r.CreditNote.DocumentStatus != "active" {
+		o.Failure("credit_note_id", "credit note doesn't exist or already used")
+		return
+	}
+
+	si.SalesOrder.Read()
+	if si.SalesOrder.Customer == nil || si.SalesOrder.Customer.ID != r.CreditNote.Partnership.ID {
+		o.Failure("credit_note_id", "credit note doesn't belong to the customer of the sales_invoice")
+	}
+
+	if r.Amount > r.CreditNote.Balance {
+		o.Failure("amount", "the amount of the pay is greater than the balance of the credit note")
+	}
+}
+
 // Messages implement validation.Requests interfaces
 // return custom messages when validation fails.
 func (r *createRequest) Messages() map[string]string {
@@ -153,6 +189,7 @@ func (r *createRequest) Transform() *model.FinanceRevenue {
 		BankName:        r.BankName,
 		BankNumber:      r.BankNumber,
 		BankHolder:      r.BankHolder,
+		CreditNote:      r.CreditNote,
 		Note:            r.Note,
 		DocumentStatus:  "uncleared",
 		IsDeleted:       int8(0),
@@ -169,7 +206,7 @@ func (r *createRequest) Transform() *model.FinanceRevenue {
 // data keys to the defined json tag.
 type updateRequest struct {
 	RecognitionDate time.Time             `json:"recognition_date" valid:"required"`
-	PaymentMethod   string                `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro"`
+	PaymentMethod   string                `json:"payment_method" valid:"required|in:cash,debit_card,credit_card,giro,transfer,credit_note"`
 	Amount          float64               `json:"amount" valid:"required"`
 	BankName        string                `json:"bank_name"`
 	BankNumber      string                `json:"bank_number"`
@@ -213,6 +250,17 @@ func (r *updateRequest) Validate() *validation.Output {
 		o.Failure("document_status", "Document status has been cleared")
 	}
 
+	// pembayaran credit note tidak bisa diubah ke metode lain, begitu juga sebaliknya
+	if (r.Revenue.PaymentMethod == "credit_note") != (r.PaymentMethod == "credit_note") {
+		o.Failure("payment_method", "payment method credit note can't be changed")
+	} else if r.Revenue.CreditNote != nil {
+		cn := &model.CreditNote{ID: r.Revenue.CreditNote.ID}
+		cn.Read()
+		if r.Amount > cn.Balance+r.Revenue.Amount {
+			o.Failure("amount", "the amount of the pay is greater than the balance of the credit note")
+		}
+	}
+
 	if r.Revenue.RefType == "sales_invoice" {
 		// total Amount finance revenue berdasarkan reftype dan ref id
 		totAmount := SumAmountFinanceRevenue(r.Revenue.RefID, "sales_invoice", r.Revenue.ID)
@@ -10,7 +10,9 @@ import (
 	"git.qasico.com/mj/api/src/util"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/partnership"
+	"git.qasico.com/mj/api/src/purchase_return"
 	"git.qasico.com/mj/api/src/sales"
 	"git.qasico.com/mj/api/src/sales_invoice"
 
@@ -78,6 +80,10 @@ func ApproveRevenue(rev *model.FinanceRevenue) (e error) {
 		} else {
 			e = errors.New("refType is wrong")
 		}
+
+		if e == nil {
+			generalLedger.AutoPost("finance_revenue", rev.ID)
+		}
 	}
 	return
 }
@@ -97,18 +103,10 @@ func salesInvoiceRevenue(rev *model.FinanceRevenue) (e error) {
 
 // purchaseReturnRevenue proses update amount pada purchase return revenue
 func purchaseReturnRevenue(rev *model.FinanceRevenue) (e error) {
-	var sum float64
-	var purchaseRet = &model.PurchaseReturn{ID: int64(rev.RefID), IsDeleted: int8(0)}
-	if sum, e = SumRevenueAmountCleared(rev.RefID, rev.RefType); e == nil {
-		if e = purchaseRet.Read("ID", "IsDeleted"); e == nil {
-			// cek amount purchase return dan amount dari semua revenue
-			if purchaseRet.TotalAmount == sum {
-				purchaseRet.DocumentStatus = "finished"
-			} else {
-				purchaseRet.DocumentStatus = "active"
-			}
-			e = purchaseRet.Save("document_status")
-		}
+	var purchaseRet *model.PurchaseReturn
+	if purchaseRet, e = purchaseReturn.GetDetailPurchaseReturn("id", rev.RefID); e == nil {
+		// cek amount purchase return dan amount dari semua revenue dan debit note
+		e = purchaseReturn.CheckRefundStatus(purchaseRet)
 	}
 	return
 }
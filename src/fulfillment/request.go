@@ -50,6 +50,8 @@ func (r *createRequest) Validate() *validation.Output {
 			o.Failure("sales_order_id", "sales_order_id doesn't exist")
 		} else if e == nil && (so.FulfillmentStatus == "finished" || so.DocumentStatus == "approved_cancel") {
 			o.Failure("sales_order_id", "invalid sales order")
+		} else if so.DocumentStatus == "pending_approval" {
+			o.Failure("sales_order_id", "sales order is waiting for discount approval")
 		}
 
 		var items []int64
@@ -232,6 +234,10 @@ func (r *approveRequest) Validate() *validation.Output {
 		o.Failure("document_status", "status document has finished")
 	}
 
+	if so := (&model.SalesOrder{ID: r.Fulfillment.SalesOrder.ID}); so.Read() == nil && so.DocumentStatus == "pending_approval" {
+		o.Failure("sales_order_id", "sales order is waiting for discount approval")
+	}
+
 	for _, i := range r.Fulfillment.WorkorderFulFillmentItems {
 		ivar := &model.ItemVariant{ID: i.SalesOrderItem.ItemVariant.ID}
 		ivar.Read("ID")
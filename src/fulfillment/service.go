@@ -6,6 +6,7 @@ package fulfillment
 
 import (
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/inventory"
 	"git.qasico.com/mj/api/src/sales"
 	"git.qasico.com/mj/api/src/util"
@@ -127,6 +128,7 @@ func approveFulfillment(fulfillment *model.WorkorderFulfillment) (*model.Workord
 				// update sales order.total_cost
 				fulfillment.SalesOrder.TotalCost += totalCost
 				if e = fulfillment.SalesOrder.Save("total_cost"); e == nil {
+					generalLedger.AutoPost("workorder_fulfillment", fulfillment.ID)
 					return fulfillment, nil
 				}
 			}
@@ -176,7 +178,7 @@ func updateItemVariantStock(fulfillment *model.WorkorderFulfillment) (totalCost
 
 func getItemVariantStockByItemVariant(itemVariant *model.ItemVariant) (ivStocks []*model.ItemVariantStock, e error) {
 	o := orm.NewOrm()
-	if _, e = o.Raw("select * from item_variant_stock where item_variant_id = ? and available_stock > ? order by created_at asc", itemVariant.ID, 0).QueryRows(&ivStocks); e == nil {
+	if _, e = o.Raw("select * from item_variant_stock where item_variant_id = ? and available_stock > ? and location = ? order by created_at asc", itemVariant.ID, 0, "warehouse").QueryRows(&ivStocks); e == nil {
 		return ivStocks, nil
 	}
 	return nil, e
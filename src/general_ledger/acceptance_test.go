@@ -0,0 +1,145 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generalLedger_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/general_ledger"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("journal_entry_line", "journal_entry", "operating_expense", "bank_transfer", "payment")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	coa := model.DummyChartOfAccount()
+	id := common.Encrypt(coa.ID)
+
+	so := model.DummySalesOrder()
+	so.TotalCharge = 1000
+	so.TaxAmount = 0
+	so.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = 1000
+	si.IsDeleted = 0
+	si.Save()
+
+	je, _ := generalLedger.Post("sales_invoice", si.ID)
+	jeID := common.Encrypt(je.ID)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/general-ledger/account", "GET", http.StatusOK},
+		{"/v1/general-ledger/account/" + id, "GET", http.StatusOK},
+		{"/v1/general-ledger/account/999999", "GET", http.StatusNotFound},
+		{"/v1/general-ledger/journal", "GET", http.StatusOK},
+		{"/v1/general-ledger/journal/" + jeID, "GET", http.StatusOK},
+		{"/v1/general-ledger/journal/999999", "GET", http.StatusNotFound},
+		{"/v1/general-ledger/trial-balance", "GET", http.StatusOK},
+		{"/v1/general-ledger/trial-balance?start_date=2018-01-01&end_date=2018-12-31", "GET", http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_URLMappingAccount(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	parent := model.DummyChartOfAccount()
+	eparentID := common.Encrypt(parent.ID)
+	code := common.RandomStr(10)
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		// sukses
+		{tester.D{"parent_id": eparentID, "code": code, "name": "Kas Kecil", "account_type": "asset", "normal_balance": "debit"}, http.StatusOK},
+		// kode sudah dipakai
+		{tester.D{"code": code, "name": "Kas Kecil", "account_type": "asset", "normal_balance": "debit"}, http.StatusUnprocessableEntity},
+		// kode akun yang sudah diseed juga tidak boleh dipakai
+		{tester.D{"code": "1101", "name": "Kas", "account_type": "asset", "normal_balance": "debit"}, http.StatusUnprocessableEntity},
+		// tipe akun tidak valid
+		{tester.D{"code": common.RandomStr(10), "name": "Akun", "account_type": "other", "normal_balance": "debit"}, http.StatusUnprocessableEntity},
+		// saldo normal tidak valid
+		{tester.D{"code": common.RandomStr(10), "name": "Akun", "account_type": "asset", "normal_balance": "other"}, http.StatusUnprocessableEntity},
+		// tipe parent berbeda
+		{tester.D{"parent_id": eparentID, "code": common.RandomStr(10), "name": "Akun", "account_type": "expense", "normal_balance": "debit"}, http.StatusUnprocessableEntity},
+		// parent tidak valid
+		{tester.D{"parent_id": "xxx", "code": common.RandomStr(10), "name": "Akun", "account_type": "asset", "normal_balance": "debit"}, http.StatusUnprocessableEntity},
+		// nama kosong
+		{tester.D{"code": common.RandomStr(10), "name": "", "account_type": "asset", "normal_balance": "debit"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/general-ledger/account").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	// tipe akun yang sudah dijurnal tidak boleh diubah
+	cash := &model.ChartOfAccount{Code: "1101"}
+	cash.Read("Code")
+	jel := model.DummyJournalEntryLine()
+	jel.ChartOfAccount = cash
+	jel.Save()
+
+	ng.PUT("/v1/general-ledger/account/"+common.Encrypt(cash.ID)).
+		SetJSON(tester.D{"code": "1101", "name": "Kas", "account_type": "expense", "normal_balance": "debit"}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+
+	ng.PUT("/v1/general-ledger/account/"+common.Encrypt(cash.ID)).
+		SetJSON(tester.D{"code": "1101", "name": "Kas", "account_type": "asset", "normal_balance": "debit", "note": "kas perusahaan"}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+		})
+
+	jel.Delete()
+	jel.JournalEntry.Delete()
+}
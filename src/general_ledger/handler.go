@@ -0,0 +1,148 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generalLedger
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for general ledger.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/account", h.getAccount, auth.CheckPrivilege("general_ledger_read"))
+	r.GET("/account/:id", h.showAccount, auth.CheckPrivilege("general_ledger_read"))
+	r.POST("/account", h.createAccount, auth.CheckPrivilege("general_ledger_manage"))
+	r.PUT("/account/:id", h.updateAccount, auth.CheckPrivilege("general_ledger_manage"))
+	r.GET("/journal", h.getJournal, auth.CheckPrivilege("general_ledger_read"))
+	r.GET("/journal/:id", h.showJournal, auth.CheckPrivilege("general_ledger_read"))
+	r.GET("/trial-balance", h.trialBalance, auth.CheckPrivilege("general_ledger_read"))
+}
+
+// getAccount endpoint to handle get http method.
+func (h *Handler) getAccount(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.ChartOfAccount
+	if data, total, e = GetChartOfAccounts(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// showAccount endpoint to handle get http method.
+func (h *Handler) showAccount(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.ChartOfAccount
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowChartOfAccount("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// createAccount endpoint untuk menambah chart of account.
+func (h *Handler) createAccount(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r accountRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				m, _ = ShowChartOfAccount("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// updateAccount endpoint untuk mengubah chart of account.
+func (h *Handler) updateAccount(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r accountRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.ChartOfAccount, e = ShowChartOfAccount("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save(); e == nil {
+						m, _ = ShowChartOfAccount("id", m.ID)
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// getJournal endpoint to handle get http method.
+func (h *Handler) getJournal(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.JournalEntry
+	if data, total, e = GetJournalEntries(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// showJournal endpoint to handle get http method.
+func (h *Handler) showJournal(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.JournalEntry
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowJournalEntry("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// trialBalance endpoint untuk menampilkan neraca saldo pada periode start_date sampai end_date.
+func (h *Handler) trialBalance(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data *TrialBalance
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetTrialBalance(param.Get("start_date"), param.Get("end_date")); e == nil {
+		ctx.Data(data)
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,672 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generalLedger
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"github.com/labstack/gommon/log"
+)
+
+// kode chart of account yang dipakai oleh posting rule jurnal otomatis.
+const (
+	accountCash                     = "1101"
+	accountBank                     = "1102"
+	accountReceivable               = "1201"
+	accountPurchaseReturnReceivable = "1202"
+	accountSupplierDebitNote        = "1203"
+	accountSupplierAdvance          = "1204"
+	accountInventory                = "1301"
+	accountVatIn                    = "1401"
+	accountPayable                  = "2101"
+	accountReceivedNotInvoiced      = "2102"
+	accountSalesReturnPayable       = "2103"
+	accountCustomerCreditNote       = "2104"
+	accountCustomerDeposit          = "2105"
+	accountVatOut                   = "2201"
+	accountSales                    = "4101"
+	accountSalesReturn              = "4102"
+	accountCostOfGoodsSold          = "5101"
+)
+
+// ErrUnbalanced error apabila total debit dan credit jurnal tidak sama.
+var ErrUnbalanced = errors.New("journal entry is not balanced")
+
+// rule posting rule setiap tipe dokumen, mengembalikan draft nil
+// apabila dokumen belum mencapai status final sehingga belum perlu dijurnal.
+type rule func(id int64) (*draft, error)
+
+// rules posting rule yang tersedia berdasarkan ref_type jurnal.
+var rules = map[string]rule{
+	"sales_invoice":         salesInvoiceJournal,
+	"purchase_invoice":      purchaseInvoiceJournal,
+	"finance_revenue":       financeRevenueJournal,
+	"finance_expense":       financeExpenseJournal,
+	"workorder_receiving":   receivingJournal,
+	"workorder_fulfillment": fulfillmentJournal,
+	"sales_return":          salesReturnJournal,
+	"purchase_return":       purchaseReturnJournal,
+	"operating_expense":     operatingExpenseJournal,
+	"bank_transfer":         bankTransferJournal,
+	"payment":               paymentJournal,
+	"landed_cost":           landedCostJournal,
+}
+
+// finalConditions kondisi dokumen yang sudah final dan harus dijurnal, dipakai cron untuk
+// memposting dokumen yang jurnalnya belum dibuat, nama tabel sama dengan ref_type jurnal.
+var finalConditions = map[string]string{
+	"sales_invoice":         "is_deleted = 0",
+	"purchase_invoice":      "is_deleted = 0",
+	"finance_revenue":       "is_deleted = 0 AND document_status = 'cleared'",
+	"finance_expense":       "is_deleted = 0 AND document_status = 'cleared'",
+	"workorder_receiving":   "is_deleted = 0",
+	"workorder_fulfillment": "is_deleted = 0 AND document_status = 'finished'",
+	"sales_return":          "is_deleted = 0 AND document_status = 'finished'",
+	"purchase_return":       "is_deleted = 0 AND document_status = 'finished'",
+	"operating_expense":     "document_status = 'approved'",
+	"bank_transfer":         "document_status = 'active'",
+	"payment":               "amount > 0",
+	"landed_cost":           "document_status = 'active'",
+}
+
+// draft jurnal yang belum disimpan.
+type draft struct {
+	date  time.Time
+	note  string
+	lines []*draftLine
+}
+
+// draftLine baris jurnal yang belum disimpan, akun menggunakan kode chart of account.
+type draftLine struct {
+	account     string
+	partnership int64
+	debit       float64
+	credit      float64
+}
+
+// debit menambahkan baris debit, nilai negatif dicatat sebagai credit dan nilai 0 diabaikan.
+func (d *draft) debit(account string, amount float64, partnership int64) {
+	amount = common.FloatPrecision(amount, 2)
+	if amount < 0 {
+		d.credit(account, -amount, partnership)
+	} else if amount > 0 {
+		d.lines = append(d.lines, &draftLine{account: account, partnership: partnership, debit: amount})
+	}
+}
+
+// credit menambahkan baris credit, nilai negatif dicatat sebagai debit dan nilai 0 diabaikan.
+func (d *draft) credit(account string, amount float64, partnership int64) {
+	amount = common.FloatPrecision(amount, 2)
+	if amount < 0 {
+		d.debit(account, -amount, partnership)
+	} else if amount > 0 {
+		d.lines = append(d.lines, &draftLine{account: account, partnership: partnership, credit: amount})
+	}
+}
+
+// totals menjumlahkan debit dan credit draft.
+func (d *draft) totals() (debit float64, credit float64) {
+	for _, l := range d.lines {
+		debit += l.debit
+		credit += l.credit
+	}
+
+	return common.FloatPrecision(debit, 2), common.FloatPrecision(credit, 2)
+}
+
+// Cron memposting jurnal dokumen final yang belum dijurnal,
+// misalnya karena posting pada saat dokumen diproses gagal.
+func Cron() {
+	if _, e := PostPending(); e != nil {
+		log.Error(e)
+	}
+}
+
+// AutoPost memposting jurnal dokumen pada saat dokumen diproses, posting yang gagal hanya dicatat
+// pada log agar tidak menggagalkan proses dokumen, dokumen tersebut akan diposting ulang oleh cron.
+func AutoPost(refType string, refID int64) {
+	if _, e := Post(refType, refID); e != nil {
+		log.Errorf("posting journal %s %d failed: %s", refType, refID, e.Error())
+	}
+}
+
+// AutoReverse membalik jurnal dokumen pada saat dokumen dicancel, kegagalan hanya dicatat pada log.
+func AutoReverse(refType string, refID int64) {
+	if _, e := Reverse(refType, refID); e != nil {
+		log.Errorf("reversing journal %s %d failed: %s", refType, refID, e.Error())
+	}
+}
+
+// Post membuat jurnal otomatis dari dokumen sesuai posting rule ref_type,
+// dokumen yang belum final atau sudah pernah dijurnal tidak akan dijurnal lagi.
+func Post(refType string, refID int64) (je *model.JournalEntry, e error) {
+	r, ok := rules[refType]
+	if !ok {
+		return nil, fmt.Errorf("posting rule for %s is not defined", refType)
+	}
+
+	if IsPosted(refType, refID) {
+		return nil, nil
+	}
+
+	var d *draft
+	if d, e = r(refID); e != nil || d == nil || len(d.lines) == 0 {
+		return nil, e
+	}
+
+	je = &model.JournalEntry{
+		RecognitionDate: d.date,
+		RefType:         refType,
+		RefID:           uint64(refID),
+		Note:            d.note,
+	}
+
+	if e = save(je, d); e != nil {
+		return nil, e
+	}
+
+	return je, nil
+}
+
+// PostPending memposting semua dokumen final yang belum memiliki jurnal.
+func PostPending() (journals []*model.JournalEntry, e error) {
+	o := orm.NewOrm()
+	for refType, cond := range finalConditions {
+		var ids []int64
+		if _, e = o.Raw("SELECT d.id FROM "+refType+" d WHERE "+cond+" AND NOT EXISTS "+
+			"(SELECT je.id FROM journal_entry je WHERE je.ref_type = ? AND je.ref_id = d.id) ORDER BY d.id;", refType).QueryRows(&ids); e != nil {
+			return nil, e
+		}
+
+		for _, id := range ids {
+			if je, err := Post(refType, id); err != nil {
+				log.Errorf("posting journal %s %d failed: %s", refType, id, err.Error())
+			} else if je != nil {
+				journals = append(journals, je)
+			}
+		}
+	}
+
+	return journals, nil
+}
+
+// IsPosted cek apakah dokumen sudah memiliki jurnal yang belum dibalik.
+func IsPosted(refType string, refID int64) bool {
+	var posted, reversed int64
+	o := orm.NewOrm()
+	o.Raw("SELECT COUNT(*) FROM journal_entry WHERE ref_type = ? AND ref_id = ? AND reversal_of_id IS NULL;", refType, refID).QueryRow(&posted)
+	o.Raw("SELECT COUNT(*) FROM journal_entry WHERE ref_type = ? AND ref_id = ? AND reversal_of_id IS NOT NULL;", refType, refID).QueryRow(&reversed)
+
+	return posted > reversed
+}
+
+// Reverse membalik jurnal dokumen yang dicancel dengan jurnal baru yang debit dan credit nya ditukar,
+// dokumen yang belum dijurnal tidak perlu dibalik.
+func Reverse(refType string, refID int64) (je *model.JournalEntry, e error) {
+	if !IsPosted(refType, refID) {
+		return nil, nil
+	}
+
+	origin := new(model.JournalEntry)
+	o := orm.NewOrm()
+	if e = o.QueryTable(origin).Filter("ref_type", refType).Filter("ref_id", refID).Filter("reversal_of_id__isnull", true).OrderBy("-id").Limit(1).One(origin); e != nil {
+		return nil, e
+	}
+
+	var lines []*model.JournalEntryLine
+	if _, e = o.QueryTable(new(model.JournalEntryLine)).Filter("journal_entry_id", origin.ID).RelatedSel("ChartOfAccount").All(&lines); e != nil {
+		return nil, e
+	}
+
+	d := &draft{date: time.Now(), note: "Pembalik jurnal " + origin.Code}
+	for _, l := range lines {
+		var partnership int64
+		if l.Partnership != nil {
+			partnership = l.Partnership.ID
+		}
+		d.lines = append(d.lines, &draftLine{account: l.ChartOfAccount.Code, partnership: partnership, debit: l.Credit, credit: l.Debit})
+	}
+
+	je = &model.JournalEntry{
+		ReversalOf:      origin,
+		RecognitionDate: d.date,
+		RefType:         refType,
+		RefID:           uint64(refID),
+		Note:            d.note,
+	}
+
+	if e = save(je, d); e != nil {
+		return nil, e
+	}
+
+	return je, nil
+}
+
+// save menyimpan jurnal beserta barisnya, jurnal yang tidak balance tidak akan disimpan.
+func save(je *model.JournalEntry, d *draft) (e error) {
+	je.TotalDebit, je.TotalCredit = d.totals()
+	if je.TotalDebit != je.TotalCredit || je.TotalDebit == 0 {
+		return ErrUnbalanced
+	}
+
+	accounts := make(map[string]*model.ChartOfAccount)
+	for _, l := range d.lines {
+		if _, ok := accounts[l.account]; !ok {
+			coa := &model.ChartOfAccount{Code: l.account}
+			if e = coa.Read("Code"); e != nil {
+				return fmt.Errorf("chart of account %s does not exists", l.account)
+			}
+			accounts[l.account] = coa
+		}
+	}
+
+	if je.Code, e = util.CodeGen("code_journal_entry", "journal_entry"); e != nil {
+		return e
+	}
+	if je.RecognitionDate.IsZero() {
+		je.RecognitionDate = time.Now()
+	}
+	je.CreatedAt = time.Now()
+
+	if e = je.Save(); e != nil {
+		return e
+	}
+
+	for _, l := range d.lines {
+		jel := &model.JournalEntryLine{
+			JournalEntry:   &model.JournalEntry{ID: je.ID},
+			ChartOfAccount: accounts[l.account],
+			Debit:          l.debit,
+			Credit:         l.credit,
+		}
+		if l.partnership != 0 {
+			jel.Partnership = &model.Partnership{ID: l.partnership}
+		}
+
+		if e = jel.Save(); e != nil {
+			return e
+		}
+		je.JournalEntryLines = append(je.JournalEntryLines, jel)
+	}
+
+	return nil
+}
+
+// taxPortion menghitung bagian ppn dari total invoice sesuai proporsi tax amount pada order.
+func taxPortion(total float64, taxAmount float64, totalCharge float64) float64 {
+	if totalCharge == 0 {
+		return 0
+	}
+
+	return common.FloatPrecision(total*taxAmount/totalCharge, 2)
+}
+
+// stockValue menghitung nilai persediaan dari stock log berdasarkan unit cost batch.
+func stockValue(refType string, refID int64, logType string) (total float64) {
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(l.quantity * ivs.unit_cost), 0) FROM item_variant_stock_log l "+
+		"INNER JOIN item_variant_stock ivs ON ivs.id = l.item_variant_stock_id "+
+		"WHERE l.ref_type = ? AND l.ref_id = ? AND l.log_type = ?;", refType, refID, logType).QueryRow(&total)
+
+	return
+}
+
+// salesInvoiceJournal piutang usaha pada penjualan dan ppn keluaran saat sales invoice diterbitkan.
+func salesInvoiceJournal(id int64) (d *draft, e error) {
+	si := &model.SalesInvoice{ID: id}
+	if e = si.Read(); e != nil || si.IsDeleted == int8(1) {
+		return nil, e
+	}
+
+	so := &model.SalesOrder{ID: si.SalesOrder.ID}
+	if e = so.Read(); e != nil {
+		return nil, e
+	}
+
+	var customer int64
+	if so.Customer != nil {
+		customer = so.Customer.ID
+	}
+
+	tax := taxPortion(si.TotalAmount, so.TaxAmount, so.TotalCharge)
+
+	d = &draft{date: si.RecognitionDate, note: "Sales invoice " + si.Code}
+	d.debit(accountReceivable, si.TotalAmount, customer)
+	d.credit(accountSales, si.TotalAmount-tax, customer)
+	d.credit(accountVatOut, tax, customer)
+
+	return
+}
+
+// purchaseInvoiceJournal hutang usaha atas barang yang sudah diterima dan ppn masukan saat purchase invoice diterima.
+func purchaseInvoiceJournal(id int64) (d *draft, e error) {
+	pi := &model.PurchaseInvoice{ID: id}
+	if e = pi.Read(); e != nil || pi.IsDeleted == int8(1) {
+		return nil, e
+	}
+
+	po := &model.PurchaseOrder{ID: pi.PurchaseOrder.ID}
+	if e = po.Read(); e != nil {
+		return nil, e
+	}
+
+	tax := taxPortion(pi.TotalAmount, po.TaxAmount, po.TotalCharge)
+
+	d = &draft{date: pi.RecognitionDate, note: "Purchase invoice " + pi.Code}
+	d.debit(accountReceivedNotInvoiced, pi.TotalAmount-tax, po.Supplier.ID)
+	d.debit(accountVatIn, tax, po.Supplier.ID)
+	d.credit(accountPayable, pi.TotalAmount, po.Supplier.ID)
+
+	return
+}
+
+// financeRevenueJournal penerimaan kas atau bank saat finance revenue cleared,
+// pembayaran dengan credit note mengurangi credit note customer.
+func financeRevenueJournal(id int64) (d *draft, e error) {
+	fr := &model.FinanceRevenue{ID: id}
+	if e = fr.Read(); e != nil || fr.IsDeleted == int8(1) || fr.DocumentStatus != "cleared" {
+		return nil, e
+	}
+
+	var credit string
+	var partnership int64
+	o := orm.NewOrm()
+	switch fr.RefType {
+	case "sales_invoice":
+		credit = accountReceivable
+		o.Raw("SELECT so.customer_id FROM sales_invoice si INNER JOIN sales_order so ON so.id = si.sales_order_id WHERE si.id = ?;", fr.RefID).QueryRow(&partnership)
+	case "invoice_receipt":
+		credit = accountReceivable
+		o.Raw("SELECT partnership_id FROM invoice_receipt WHERE id = ?;", fr.RefID).QueryRow(&partnership)
+	case "purchase_return":
+		credit = accountPurchaseReturnReceivable
+		o.Raw("SELECT po.supplier_id FROM purchase_return pr INNER JOIN purchase_order po ON po.id = pr.purchase_order_id WHERE pr.id = ?;", fr.RefID).QueryRow(&partnership)
+	default:
+		return nil, fmt.Errorf("posting rule for finance revenue %s is not defined", fr.RefType)
+	}
+
+	// alokasi payment mengurangi uang muka pelanggan yang sudah dijurnal saat payment diterima
+	debit := paymentAccount(fr.PaymentMethod, accountCustomerCreditNote)
+	if fr.Payment != nil {
+		debit = accountCustomerDeposit
+	}
+
+	d = &draft{date: fr.RecognitionDate, note: fmt.Sprintf("Finance revenue %s %d", fr.RefType, fr.RefID)}
+	d.debit(debit, fr.Amount, partnership)
+	d.credit(credit, fr.Amount, partnership)
+
+	return
+}
+
+// financeExpenseJournal pengeluaran kas atau bank saat finance expense cleared,
+// pembayaran dengan debit note mengurangi debit note supplier.
+func financeExpenseJournal(id int64) (d *draft, e error) {
+	fe := &model.FinanceExpense{ID: id}
+	if e = fe.Read(); e != nil || fe.IsDeleted == int8(1) || fe.DocumentStatus != "cleared" {
+		return nil, e
+	}
+
+	var debit string
+	var partnership int64
+	o := orm.NewOrm()
+	switch fe.RefType {
+	case "purchase_invoice":
+		debit = accountPayable
+		o.Raw("SELECT po.supplier_id FROM purchase_invoice pi INNER JOIN purchase_order po ON po.id = pi.purchase_order_id WHERE pi.id = ?;", fe.RefID).QueryRow(&partnership)
+	case "sales_return":
+		debit = accountSalesReturnPayable
+		o.Raw("SELECT so.customer_id FROM sales_return sr INNER JOIN sales_order so ON so.id = sr.sales_order_id WHERE sr.id = ?;", fe.RefID).QueryRow(&partnership)
+	case "invoice_receipt":
+		// retur yang dipotongkan pada invoice receipt melunasi hutang retur penjualan
+		debit = accountSalesReturnPayable
+		o.Raw("SELECT partnership_id FROM invoice_receipt WHERE id = ?;", fe.RefID).QueryRow(&partnership)
+	default:
+		return nil, fmt.Errorf("posting rule for finance expense %s is not defined", fe.RefType)
+	}
+
+	// alokasi payment mengurangi uang muka pembelian yang sudah dijurnal saat payment dibayarkan
+	credit := paymentAccount(fe.PaymentMethod, accountSupplierDebitNote)
+	if fe.Payment != nil {
+		credit = accountSupplierAdvance
+	}
+
+	d = &draft{date: fe.RecognitionDate, note: fmt.Sprintf("Finance expense %s %d", fe.RefType, fe.RefID)}
+	d.debit(debit, fe.Amount, partnership)
+	d.credit(credit, fe.Amount, partnership)
+
+	return
+}
+
+// paymentAccount akun kas atau bank sesuai metode pembayaran,
+// note adalah akun yang dipakai apabila pembayaran menggunakan credit note atau debit note.
+func paymentAccount(method string, note string) string {
+	switch method {
+	case "cash":
+		return accountCash
+	case "credit_note", "debit_note":
+		return note
+	}
+
+	return accountBank
+}
+
+// receivingJournal persediaan bertambah sebesar harga beli barang yang diterima setelah discount item,
+// sama dengan unit cost batch stock, lawannya barang diterima belum ditagih sampai purchase invoice diterima.
+func receivingJournal(id int64) (d *draft, e error) {
+	wr := &model.WorkorderReceiving{ID: id}
+	if e = wr.Read(); e != nil || wr.IsDeleted == int8(1) || wr.PurchaseOrder == nil {
+		return nil, e
+	}
+
+	var supplier int64
+	var items []*model.WorkorderReceivingItem
+	o := orm.NewOrm()
+	o.Raw("SELECT supplier_id FROM purchase_order WHERE id = ?;", wr.PurchaseOrder.ID).QueryRow(&supplier)
+	if _, e = o.QueryTable(new(model.WorkorderReceivingItem)).Filter("workorder_receiving_id", wr.ID).RelatedSel("PurchaseOrderItem").All(&items); e != nil {
+		return nil, e
+	}
+
+	var total float64
+	for _, i := range items {
+		total += float64(i.Quantity) * i.PurchaseOrderItem.NetUnitPrice()
+	}
+	total = common.FloatPrecision(total, 0)
+
+	d = &draft{date: wr.RecognitionDate, note: "Workorder receiving " + wr.Code}
+	d.debit(accountInventory, total, supplier)
+	d.credit(accountReceivedNotInvoiced, total, supplier)
+
+	return
+}
+
+// fulfillmentJournal harga pokok penjualan sebesar cost fifo batch yang dikeluarkan saat fulfillment finished.
+func fulfillmentJournal(id int64) (d *draft, e error) {
+	wf := &model.WorkorderFulfillment{ID: id}
+	if e = wf.Read(); e != nil || wf.IsDeleted == int8(1) || wf.DocumentStatus != "finished" {
+		return nil, e
+	}
+
+	var customer int64
+	orm.NewOrm().Raw("SELECT customer_id FROM sales_order WHERE id = ?;", wf.SalesOrder.ID).QueryRow(&customer)
+
+	cost := stockValue("workorder_fulfillment", wf.ID, "out")
+
+	date := wf.UpdatedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	d = &draft{date: date, note: "Workorder fulfillment " + wf.Code}
+	d.debit(accountCostOfGoodsSold, cost, customer)
+	d.credit(accountInventory, cost, customer)
+
+	return
+}
+
+// salesReturnJournal retur penjualan saat sales return finished, refund cash menjadi hutang retur penjualan
+// dan refund credit note menjadi credit note customer, barang yang direstock mengembalikan harga pokok penjualan.
+func salesReturnJournal(id int64) (d *draft, e error) {
+	sr := &model.SalesReturn{ID: id}
+	if e = sr.Read(); e != nil || sr.IsDeleted == int8(1) || sr.DocumentStatus != "finished" {
+		return nil, e
+	}
+
+	var customer int64
+	var creditNote float64
+	o := orm.NewOrm()
+	o.Raw("SELECT customer_id FROM sales_order WHERE id = ?;", sr.SalesOrder.ID).QueryRow(&customer)
+	o.Raw("SELECT COALESCE(SUM(amount), 0) FROM credit_note WHERE sales_return_id = ? AND document_status != 'cancelled';", sr.ID).QueryRow(&creditNote)
+
+	restock := stockValue("sales_return", sr.ID, "in")
+
+	d = &draft{date: sr.RecognitionDate, note: "Sales return " + sr.Code}
+	d.debit(accountSalesReturn, sr.TotalAmount, customer)
+	d.credit(accountSalesReturnPayable, sr.TotalAmount-creditNote, customer)
+	d.credit(accountCustomerCreditNote, creditNote, customer)
+	d.debit(accountInventory, restock, customer)
+	d.credit(accountCostOfGoodsSold, restock, customer)
+
+	return
+}
+
+// purchaseReturnJournal persediaan berkurang sebesar cost batch yang dikembalikan saat purchase return finished,
+// selisih antara nilai retur dan cost batch dibebankan ke harga pokok penjualan.
+func purchaseReturnJournal(id int64) (d *draft, e error) {
+	pr := &model.PurchaseReturn{ID: id}
+	if e = pr.Read(); e != nil || pr.IsDeleted == int8(1) || pr.DocumentStatus != "finished" {
+		return nil, e
+	}
+
+	var supplier int64
+	var debitNote float64
+	o := orm.NewOrm()
+	o.Raw("SELECT supplier_id FROM purchase_order WHERE id = ?;", pr.PurchaseOrder.ID).QueryRow(&supplier)
+	o.Raw("SELECT COALESCE(SUM(amount), 0) FROM debit_note WHERE purchase_return_id = ? AND document_status != 'cancelled';", pr.ID).QueryRow(&debitNote)
+
+	cost := stockValue("purchase_return", pr.ID, "out")
+
+	d = &draft{date: pr.RecognitionDate, note: "Purchase return " + pr.Code}
+	d.debit(accountPurchaseReturnReceivable, pr.TotalAmount-debitNote, supplier)
+	d.debit(accountSupplierDebitNote, debitNote, supplier)
+	d.credit(accountInventory, cost, supplier)
+	d.credit(accountCostOfGoodsSold, pr.TotalAmount-cost, supplier)
+
+	return
+}
+
+// operatingExpenseJournal beban sesuai akun kategori expense saat operating expense approved,
+// dibayar dari kas atau bank sesuai metode pembayaran.
+func operatingExpenseJournal(id int64) (d *draft, e error) {
+	oe := &model.OperatingExpense{ID: id}
+	if e = oe.Read(); e != nil || oe.DocumentStatus != "approved" {
+		return nil, e
+	}
+
+	var account string
+	if e = orm.NewOrm().Raw("SELECT coa.code FROM expense_category ec INNER JOIN chart_of_account coa ON coa.id = ec.chart_of_account_id "+
+		"WHERE ec.id = ?;", oe.ExpenseCategory.ID).QueryRow(&account); e != nil {
+		return nil, e
+	}
+
+	d = &draft{date: oe.RecognitionDate, note: "Operating expense " + oe.Code}
+	d.debit(account, oe.Amount, 0)
+	d.credit(paymentAccount(oe.PaymentMethod, accountBank), oe.Amount, 0)
+
+	return
+}
+
+// bankTransferJournal kas atau bank tujuan bertambah dan kas atau bank asal berkurang,
+// transfer antar rekening bank tidak dijurnal karena memakai akun bank yang sama.
+func bankTransferJournal(id int64) (d *draft, e error) {
+	bt := &model.BankTransfer{ID: id}
+	if e = bt.Read(); e != nil || bt.DocumentStatus != "active" {
+		return nil, e
+	}
+
+	from := &model.BankAccount{ID: bt.FromBankAccount.ID}
+	to := &model.BankAccount{ID: bt.ToBankAccount.ID}
+	if e = from.Read(); e != nil {
+		return nil, e
+	}
+	if e = to.Read(); e != nil {
+		return nil, e
+	}
+
+	if from.AccountType == to.AccountType {
+		return nil, nil
+	}
+
+	d = &draft{date: bt.TransferDate, note: "Bank transfer " + bt.Code}
+	d.debit(paymentAccount(to.AccountType, accountBank), bt.Amount, 0)
+	d.credit(paymentAccount(from.AccountType, accountBank), bt.Amount, 0)
+
+	return
+}
+
+// paymentJournal penerimaan dari customer dicatat sebagai uang muka pelanggan dan pembayaran ke supplier
+// sebagai uang muka pembelian, uang muka berkurang saat payment dialokasikan ke invoice.
+func paymentJournal(id int64) (d *draft, e error) {
+	p := &model.Payment{ID: id}
+	if e = p.Read(); e != nil {
+		return nil, e
+	}
+
+	d = &draft{date: p.RecognitionDate, note: "Payment " + p.Code}
+	if p.PaymentType == "receipt" {
+		d.debit(paymentAccount(p.PaymentMethod, accountBank), p.Amount, p.Partnership.ID)
+		d.credit(accountCustomerDeposit, p.Amount, p.Partnership.ID)
+	} else {
+		d.debit(accountSupplierAdvance, p.Amount, p.Partnership.ID)
+		d.credit(paymentAccount(p.PaymentMethod, accountBank), p.Amount, p.Partnership.ID)
+	}
+
+	return
+}
+
+// landedCostJournal biaya landed cost menambah persediaan sebesar alokasi quantity batch yang masih tersedia,
+// bagian quantity yang sudah keluar dibebankan ke harga pokok penjualan. biaya shipment cost purchase order
+// mengurangi barang diterima belum ditagih karena ikut ditagihkan pada purchase invoice,
+// biaya lainnya menjadi hutang usaha kepada partnership yang menagih.
+func landedCostJournal(id int64) (d *draft, e error) {
+	lc := &model.LandedCost{ID: id}
+	if e = lc.Read(); e != nil || lc.DocumentStatus != "active" {
+		return nil, e
+	}
+
+	o := orm.NewOrm()
+	var consumed float64
+	o.Raw("SELECT COALESCE(SUM(consumed_amount), 0) FROM landed_cost_item WHERE landed_cost_id = ?;", lc.ID).QueryRow(&consumed)
+
+	var charges []*model.LandedCostCharge
+	if _, e = o.QueryTable(new(model.LandedCostCharge)).Filter("landed_cost_id", lc.ID).OrderBy("id").All(&charges); e != nil {
+		return nil, e
+	}
+
+	d = &draft{date: lc.RecognitionDate, note: "Landed cost " + lc.Code}
+	d.debit(accountInventory, lc.TotalAmount-consumed, 0)
+	d.debit(accountCostOfGoodsSold, consumed, 0)
+
+	for _, c := range charges {
+		if c.ChargeType == "shipment_cost" && c.PurchaseOrder != nil {
+			var supplier int64
+			o.Raw("SELECT supplier_id FROM purchase_order WHERE id = ?;", c.PurchaseOrder.ID).QueryRow(&supplier)
+			d.credit(accountReceivedNotInvoiced, c.Amount, supplier)
+		} else {
+			var partnership int64
+			if c.Partnership != nil {
+				partnership = c.Partnership.ID
+			}
+			d.credit(accountPayable, c.Amount, partnership)
+		}
+	}
+
+	return
+}
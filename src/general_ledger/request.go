@@ -0,0 +1,101 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generalLedger
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// accountRequest data struct that stored request data when requesting an create or update chart of account process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type accountRequest struct {
+	ParentID       string                `json:"parent_id"`
+	Code           string                `json:"code" valid:"required"`
+	Name           string                `json:"name" valid:"required"`
+	AccountType    string                `json:"account_type" valid:"required|in:asset,liability,equity,revenue,expense"`
+	NormalBalance  string                `json:"normal_balance" valid:"required|in:debit,credit"`
+	IsArchived     int8                  `json:"is_archived"`
+	Note           string                `json:"note"`
+	Session        *auth.SessionData     `json:"-"`
+	ChartOfAccount *model.ChartOfAccount `json:"-"`
+	Parent         *model.ChartOfAccount `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *accountRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	var id int64
+	if r.ChartOfAccount != nil {
+		id = r.ChartOfAccount.ID
+	}
+
+	if r.Code != "" {
+		if coa, e := ShowChartOfAccount("code", r.Code); e == nil && coa.ID != id {
+			o.Failure("code", "code is already used by another account")
+		}
+	}
+
+	if r.ParentID != "" {
+		if parentID, e := common.Decrypt(r.ParentID); e != nil {
+			o.Failure("parent_id", "parent_id is not valid")
+		} else if r.Parent, e = ShowChartOfAccount("id", parentID); e != nil || r.Parent.IsArchived == int8(1) {
+			o.Failure("parent_id", "parent_id is already archived or does not exists")
+		} else if parentID == id {
+			o.Failure("parent_id", "account can not be a parent of itself")
+		} else if r.AccountType != "" && r.Parent.AccountType != r.AccountType {
+			o.Failure("parent_id", "parent account type must be same with account type")
+		}
+	}
+
+	// tipe dan saldo normal akun yang sudah dijurnal tidak boleh berubah karena akan mengubah laporan yang sudah ada
+	if r.ChartOfAccount != nil && hasLines(r.ChartOfAccount.ID) {
+		if r.AccountType != r.ChartOfAccount.AccountType {
+			o.Failure("account_type", "account type can not be changed, account is already used in journal entry")
+		}
+
+		if r.NormalBalance != r.ChartOfAccount.NormalBalance {
+			o.Failure("normal_balance", "normal balance can not be changed, account is already used in journal entry")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *accountRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *accountRequest) Transform() *model.ChartOfAccount {
+	m := &model.ChartOfAccount{
+		Parent:        r.Parent,
+		Code:          r.Code,
+		Name:          r.Name,
+		AccountType:   r.AccountType,
+		NormalBalance: r.NormalBalance,
+		IsArchived:    r.IsArchived,
+		Note:          r.Note,
+		CreatedAt:     time.Now(),
+	}
+
+	if r.ChartOfAccount != nil {
+		m.ID = r.ChartOfAccount.ID
+		m.CreatedAt = r.ChartOfAccount.CreatedAt
+		m.UpdatedAt = time.Now()
+	}
+
+	return m
+}
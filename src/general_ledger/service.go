@@ -0,0 +1,180 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generalLedger
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// TrialBalance neraca saldo seluruh chart of account pada periode tertentu.
+type TrialBalance struct {
+	StartDate   string                 `json:"start_date"`
+	EndDate     string                 `json:"end_date"`
+	Accounts    []*TrialBalanceAccount `json:"accounts"`
+	TotalDebit  float64                `json:"total_debit"`
+	TotalCredit float64                `json:"total_credit"`
+	IsBalanced  bool                   `json:"is_balanced"`
+}
+
+// TrialBalanceAccount saldo awal, mutasi dan saldo akhir satu chart of account,
+// saldo akhir dipisah ke kolom debit atau credit sesuai posisinya.
+type TrialBalanceAccount struct {
+	ID            int64   `json:"-"`
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	AccountType   string  `json:"account_type"`
+	NormalBalance string  `json:"normal_balance"`
+	Opening       float64 `json:"opening"`
+	Debit         float64 `json:"debit"`
+	Credit        float64 `json:"credit"`
+	ClosingDebit  float64 `json:"closing_debit"`
+	ClosingCredit float64 `json:"closing_credit"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, ID chart of account akan diencrypt.
+func (m *TrialBalanceAccount) MarshalJSON() ([]byte, error) {
+	type Alias TrialBalanceAccount
+
+	return json.Marshal(&struct {
+		ID string `json:"id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	})
+}
+
+// GetChartOfAccounts get all data chart of account that matched with query request parameters.
+// returning slices of chart of account, total data without limit and error.
+func GetChartOfAccounts(rq *orm.RequestQuery) (m *[]model.ChartOfAccount, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.ChartOfAccount))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.ChartOfAccount
+	if _, err = q.RelatedSel("Parent").All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowChartOfAccount find a single data chart of account using field and value condition.
+func ShowChartOfAccount(field string, values ...interface{}) (*model.ChartOfAccount, error) {
+	m := new(model.ChartOfAccount)
+	o := orm.NewOrm().QueryTable(m)
+	if err := o.Filter(field, values...).RelatedSel("Parent").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetJournalEntries get all data journal entry that matched with query request parameters.
+// returning slices of journal entry, total data without limit and error.
+func GetJournalEntries(rq *orm.RequestQuery) (m *[]model.JournalEntry, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.JournalEntry))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.JournalEntry
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowJournalEntry find a single data journal entry beserta baris jurnal nya.
+func ShowJournalEntry(field string, values ...interface{}) (*model.JournalEntry, error) {
+	m := new(model.JournalEntry)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel("ReversalOf").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.JournalEntryLine)).Filter("journal_entry_id", m.ID).
+		RelatedSel("ChartOfAccount", "Partnership").OrderBy("id").All(&m.JournalEntryLines)
+
+	return m, nil
+}
+
+// hasLines cek apakah chart of account sudah dipakai pada baris jurnal.
+func hasLines(coaID int64) bool {
+	total, _ := orm.NewOrm().QueryTable(new(model.JournalEntryLine)).Filter("chart_of_account_id", coaID).Count()
+
+	return total > 0
+}
+
+// GetTrialBalance menghitung neraca saldo pada periode recognition date jurnal,
+// tanggal menggunakan format YYYY-MM-DD, default nya dari awal bulan sampai hari ini.
+func GetTrialBalance(startDate string, endDate string) (m *TrialBalance, e error) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := now
+
+	if startDate != "" {
+		if start, e = time.Parse("2006-01-02", startDate); e != nil {
+			return nil, e
+		}
+	}
+
+	if endDate != "" {
+		if end, e = time.Parse("2006-01-02", endDate); e != nil {
+			return nil, e
+		}
+	}
+
+	m = &TrialBalance{StartDate: start.Format("2006-01-02"), EndDate: end.Format("2006-01-02")}
+
+	var accounts []*TrialBalanceAccount
+	if _, e = orm.NewOrm().Raw("SELECT coa.id, coa.code, coa.name, coa.account_type, coa.normal_balance, "+
+		"COALESCE(SUM(CASE WHEN je.recognition_date < ? THEN jel.debit - jel.credit ELSE 0 END), 0) AS opening, "+
+		"COALESCE(SUM(CASE WHEN je.recognition_date >= ? THEN jel.debit ELSE 0 END), 0) AS debit, "+
+		"COALESCE(SUM(CASE WHEN je.recognition_date >= ? THEN jel.credit ELSE 0 END), 0) AS credit "+
+		"FROM chart_of_account coa "+
+		"INNER JOIN journal_entry_line jel ON jel.chart_of_account_id = coa.id "+
+		"INNER JOIN journal_entry je ON je.id = jel.journal_entry_id AND je.recognition_date <= ? "+
+		"GROUP BY coa.id, coa.code, coa.name, coa.account_type, coa.normal_balance ORDER BY coa.code;",
+		m.StartDate, m.StartDate, m.StartDate, m.EndDate).QueryRows(&accounts); e != nil {
+		return nil, e
+	}
+
+	for _, a := range accounts {
+		if closing := common.FloatPrecision(a.Opening+a.Debit-a.Credit, 2); closing >= 0 {
+			a.ClosingDebit = closing
+		} else {
+			a.ClosingCredit = -closing
+		}
+
+		m.TotalDebit += a.ClosingDebit
+		m.TotalCredit += a.ClosingCredit
+	}
+
+	m.Accounts = accounts
+	m.TotalDebit = common.FloatPrecision(m.TotalDebit, 2)
+	m.TotalCredit = common.FloatPrecision(m.TotalCredit, 2)
+	m.IsBalanced = m.TotalDebit == m.TotalCredit
+
+	return m, nil
+}
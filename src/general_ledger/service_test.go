@@ -0,0 +1,366 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package generalLedger
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+// dummySalesInvoice membuat sales invoice dengan total 1100 dan ppn 100.
+func dummySalesInvoice() *model.SalesInvoice {
+	so := model.DummySalesOrder()
+	so.Customer = model.DummyPartnership()
+	so.TaxAmount = 100
+	so.TotalCharge = 1100
+	so.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = 1100
+	si.RecognitionDate = time.Now()
+	si.IsDeleted = 0
+	si.Save()
+
+	return si
+}
+
+// lineAmount mengambil total debit dan credit akun pada jurnal.
+func lineAmount(je *model.JournalEntry, code string) (debit float64, credit float64) {
+	for _, l := range je.JournalEntryLines {
+		if l.ChartOfAccount.Code == code {
+			debit += l.Debit
+			credit += l.Credit
+		}
+	}
+
+	return
+}
+
+func TestPostSalesInvoice(t *testing.T) {
+	si := dummySalesInvoice()
+
+	je, e := Post("sales_invoice", si.ID)
+	assert.NoError(t, e)
+	assert.NotNil(t, je)
+	assert.Equal(t, float64(1100), je.TotalDebit)
+	assert.Equal(t, je.TotalDebit, je.TotalCredit)
+
+	debit, _ := lineAmount(je, accountReceivable)
+	assert.Equal(t, float64(1100), debit)
+	_, credit := lineAmount(je, accountSales)
+	assert.Equal(t, float64(1000), credit)
+	_, credit = lineAmount(je, accountVatOut)
+	assert.Equal(t, float64(100), credit)
+
+	// dokumen yang sudah dijurnal tidak dijurnal ulang
+	je2, e := Post("sales_invoice", si.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, je2)
+	assert.True(t, IsPosted("sales_invoice", si.ID))
+
+	// sales invoice yang dihapus tidak dijurnal
+	deleted := dummySalesInvoice()
+	deleted.IsDeleted = 1
+	deleted.Save("IsDeleted")
+	je3, e := Post("sales_invoice", deleted.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, je3)
+}
+
+func TestReverse(t *testing.T) {
+	si := dummySalesInvoice()
+	je, e := Post("sales_invoice", si.ID)
+	assert.NoError(t, e)
+
+	rev, e := Reverse("sales_invoice", si.ID)
+	assert.NoError(t, e)
+	assert.NotNil(t, rev)
+	assert.Equal(t, je.ID, rev.ReversalOf.ID)
+	assert.False(t, IsPosted("sales_invoice", si.ID))
+
+	_, credit := lineAmount(rev, accountReceivable)
+	assert.Equal(t, float64(1100), credit)
+	debit, _ := lineAmount(rev, accountSales)
+	assert.Equal(t, float64(1000), debit)
+
+	// jurnal yang sudah dibalik tidak dibalik lagi
+	rev2, e := Reverse("sales_invoice", si.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, rev2)
+
+	// dokumen yang sudah dibalik bisa dijurnal ulang
+	je2, e := Post("sales_invoice", si.ID)
+	assert.NoError(t, e)
+	assert.NotNil(t, je2)
+}
+
+func TestPostFinance(t *testing.T) {
+	si := dummySalesInvoice()
+
+	fr := model.DummyFinanceRevenue()
+	fr.RefType = "sales_invoice"
+	fr.RefID = uint64(si.ID)
+	fr.Amount = 500
+	fr.PaymentMethod = "cash"
+	fr.DocumentStatus = "uncleared"
+	fr.IsDeleted = 0
+	fr.Save()
+
+	// revenue yang belum cleared belum dijurnal
+	je, e := Post("finance_revenue", fr.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, je)
+
+	fr.DocumentStatus = "cleared"
+	fr.Save("DocumentStatus")
+	je, e = Post("finance_revenue", fr.ID)
+	assert.NoError(t, e)
+	debit, _ := lineAmount(je, accountCash)
+	assert.Equal(t, float64(500), debit)
+	_, credit := lineAmount(je, accountReceivable)
+	assert.Equal(t, float64(500), credit)
+
+	pi := model.DummyPurchaseInvoice()
+	pi.IsDeleted = 0
+	pi.Save()
+
+	fe := model.DummyFinanceExpense()
+	fe.RefType = "purchase_invoice"
+	fe.RefID = uint64(pi.ID)
+	fe.Amount = 300
+	fe.PaymentMethod = "debit_card"
+	fe.DocumentStatus = "cleared"
+	fe.IsDeleted = 0
+	fe.Save()
+
+	je, e = Post("finance_expense", fe.ID)
+	assert.NoError(t, e)
+	debit, _ = lineAmount(je, accountPayable)
+	assert.Equal(t, float64(300), debit)
+	_, credit = lineAmount(je, accountBank)
+	assert.Equal(t, float64(300), credit)
+}
+
+func TestPostOperatingExpense(t *testing.T) {
+	oe := model.DummyOperatingExpense()
+	oe.Amount = 750
+	oe.PaymentMethod = "bank"
+	oe.DocumentStatus = "new"
+	oe.Save()
+
+	// operating expense yang belum diapprove belum dijurnal
+	je, e := Post("operating_expense", oe.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, je)
+
+	oe.DocumentStatus = "approved"
+	oe.Save("DocumentStatus")
+	je, e = Post("operating_expense", oe.ID)
+	assert.NoError(t, e)
+	debit, _ := lineAmount(je, "6101")
+	assert.Equal(t, float64(750), debit)
+	_, credit := lineAmount(je, accountBank)
+	assert.Equal(t, float64(750), credit)
+}
+
+func TestPostBankTransfer(t *testing.T) {
+	bt := model.DummyBankTransfer()
+	bt.Amount = 500
+	bt.Save()
+
+	// transfer antar rekening bank tidak dijurnal
+	je, e := Post("bank_transfer", bt.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, je)
+
+	// setor kas ke bank
+	bt.FromBankAccount.AccountType = "cash"
+	bt.FromBankAccount.Save("AccountType")
+	je, e = Post("bank_transfer", bt.ID)
+	assert.NoError(t, e)
+	debit, _ := lineAmount(je, accountBank)
+	assert.Equal(t, float64(500), debit)
+	_, credit := lineAmount(je, accountCash)
+	assert.Equal(t, float64(500), credit)
+}
+
+func TestPostPayment(t *testing.T) {
+	p := model.DummyPayment()
+	p.Amount = 1000
+	p.Save()
+
+	// penerimaan dari customer dicatat sebagai uang muka pelanggan
+	je, e := Post("payment", p.ID)
+	assert.NoError(t, e)
+	debit, _ := lineAmount(je, accountBank)
+	assert.Equal(t, float64(1000), debit)
+	_, credit := lineAmount(je, accountCustomerDeposit)
+	assert.Equal(t, float64(1000), credit)
+
+	// alokasi payment ke sales invoice mengurangi uang muka pelanggan, bukan bank
+	si := dummySalesInvoice()
+	fr := model.DummyFinanceRevenue()
+	fr.Payment = p
+	fr.RefType = "sales_invoice"
+	fr.RefID = uint64(si.ID)
+	fr.Amount = 600
+	fr.PaymentMethod = "transfer"
+	fr.DocumentStatus = "cleared"
+	fr.IsDeleted = 0
+	fr.Save()
+
+	je, e = Post("finance_revenue", fr.ID)
+	assert.NoError(t, e)
+	debit, _ = lineAmount(je, accountCustomerDeposit)
+	assert.Equal(t, float64(600), debit)
+	debit, _ = lineAmount(je, accountBank)
+	assert.Zero(t, debit)
+
+	// pembayaran ke supplier dicatat sebagai uang muka pembelian
+	p = model.DummyPayment()
+	p.PaymentType = "disbursement"
+	p.PaymentMethod = "cash"
+	p.Amount = 1000
+	p.Save()
+	je, e = Post("payment", p.ID)
+	assert.NoError(t, e)
+	debit, _ = lineAmount(je, accountSupplierAdvance)
+	assert.Equal(t, float64(1000), debit)
+	_, credit = lineAmount(je, accountCash)
+	assert.Equal(t, float64(1000), credit)
+}
+
+func TestPostReceiving(t *testing.T) {
+	wri := model.DummyWorkorderReceivingItem()
+	wri.Quantity = 10
+	wri.Save()
+
+	poi := wri.PurchaseOrderItem
+	poi.UnitPrice = 1000
+	poi.Discount = 10
+	poi.Save()
+
+	wr := wri.WorkorderReceiving
+	wr.PurchaseOrder = poi.PurchaseOrder
+	wr.IsDeleted = 0
+	wr.Save()
+
+	// persediaan dicatat sebesar harga setelah discount item
+	je, e := Post("workorder_receiving", wr.ID)
+	assert.NoError(t, e)
+	debit, _ := lineAmount(je, accountInventory)
+	assert.Equal(t, float64(9000), debit)
+	_, credit := lineAmount(je, accountReceivedNotInvoiced)
+	assert.Equal(t, float64(9000), credit)
+}
+
+func TestPostLandedCost(t *testing.T) {
+	lc := model.DummyLandedCost()
+	lc.TotalAmount = 5000
+	lc.DocumentStatus = "cancelled"
+	lc.Save()
+
+	item := model.DummyLandedCostItem()
+	item.LandedCost = lc
+	item.AllocatedAmount = 5000
+	item.ConsumedAmount = 800
+	item.Save()
+
+	freight := model.DummyLandedCostCharge()
+	freight.LandedCost = lc
+	freight.ChargeType = "freight"
+	freight.PurchaseOrder = nil
+	freight.Partnership = model.DummyPartnership()
+	freight.Amount = 3000
+	freight.Save()
+
+	shipment := model.DummyLandedCostCharge()
+	shipment.LandedCost = lc
+	shipment.ChargeType = "shipment_cost"
+	shipment.PurchaseOrder = model.DummyPurchaseOrder()
+	shipment.Partnership = nil
+	shipment.Amount = 2000
+	shipment.Save()
+
+	// landed cost yang sudah dibatalkan tidak dijurnal
+	je, e := Post("landed_cost", lc.ID)
+	assert.NoError(t, e)
+	assert.Nil(t, je)
+
+	// quantity yang sudah keluar dibebankan ke harga pokok penjualan
+	lc.DocumentStatus = "active"
+	lc.Save("DocumentStatus")
+	je, e = Post("landed_cost", lc.ID)
+	assert.NoError(t, e)
+	debit, _ := lineAmount(je, accountInventory)
+	assert.Equal(t, float64(4200), debit)
+	debit, _ = lineAmount(je, accountCostOfGoodsSold)
+	assert.Equal(t, float64(800), debit)
+	_, credit := lineAmount(je, accountPayable)
+	assert.Equal(t, float64(3000), credit)
+	_, credit = lineAmount(je, accountReceivedNotInvoiced)
+	assert.Equal(t, float64(2000), credit)
+}
+
+func TestSaveUnbalanced(t *testing.T) {
+	d := &draft{date: time.Now()}
+	d.debit(accountCash, 1000, 0)
+	d.credit(accountSales, 900, 0)
+
+	e := save(&model.JournalEntry{RefType: "sales_invoice"}, d)
+	assert.Equal(t, ErrUnbalanced, e)
+
+	// nilai negatif dipindah ke sisi sebaliknya
+	d = &draft{date: time.Now()}
+	d.debit(accountCash, 1000, 0)
+	d.debit(accountSales, -1000, 0)
+	debit, credit := d.totals()
+	assert.Equal(t, debit, credit)
+}
+
+// TestPostBalanced setiap jurnal yang diposting harus balance, baik total header maupun baris nya.
+func TestPostBalanced(t *testing.T) {
+	dummySalesInvoice()
+	pi := model.DummyPurchaseInvoice()
+	pi.IsDeleted = 0
+	pi.Save()
+
+	_, e := PostPending()
+	assert.NoError(t, e)
+
+	var journals []*model.JournalEntry
+	o := orm.NewOrm()
+	o.QueryTable(new(model.JournalEntry)).Limit(-1).All(&journals)
+	assert.NotEmpty(t, journals)
+
+	for _, je := range journals {
+		var debit, credit float64
+		o.Raw("SELECT COALESCE(SUM(debit), 0), COALESCE(SUM(credit), 0) FROM journal_entry_line WHERE journal_entry_id = ?;", je.ID).QueryRow(&debit, &credit)
+		assert.Equal(t, debit, credit, "journal %s is not balanced", je.Code)
+		assert.Equal(t, je.TotalDebit, debit, "journal %s total debit is not match", je.Code)
+		assert.Equal(t, je.TotalCredit, credit, "journal %s total credit is not match", je.Code)
+	}
+}
+
+func TestGetTrialBalance(t *testing.T) {
+	si := dummySalesInvoice()
+	Post("sales_invoice", si.ID)
+
+	today := time.Now().Format("2006-01-02")
+	m, e := GetTrialBalance("", today)
+	assert.NoError(t, e)
+	assert.True(t, m.IsBalanced)
+	assert.NotEmpty(t, m.Accounts)
+	assert.Equal(t, m.TotalDebit, m.TotalCredit)
+
+	_, e = GetTrialBalance("01-01-2018", today)
+	assert.Error(t, e)
+}
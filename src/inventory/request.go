@@ -149,6 +149,7 @@ type VariantRequest struct {
 	ExternalName      string                `json:"external_name"`
 	VariantName       string                `json:"variant_name"`
 	MinimumStock      float32               `json:"minimum_stock" valid:"gte:0"`
+	Weight            float32               `json:"weight" valid:"gte:0"`
 	BasePrice         float64               `json:"base_price" valid:"required"`
 	Note              string                `json:"note"`
 	Image             string                `json:"image"`
@@ -307,6 +308,7 @@ func (r *createItemRequest) Transform() model.Item {
 			BasePrice:         u.BasePrice,
 			Note:              u.Note,
 			MinimumStock:      float32(common.FloatPrecision(float64(u.MinimumStock), 2)),
+			Weight:            float32(common.FloatPrecision(float64(u.Weight), 2)),
 			AvailableStock:    float32(0),
 			CommitedStock:     float32(0),
 			HasExternalName:   hasExternal,
@@ -592,6 +594,7 @@ func (r *updateItemRequest) Transform() model.Item {
 			BasePrice:         u.BasePrice,
 			Note:              u.Note,
 			MinimumStock:      u.MinimumStock,
+			Weight:            float32(common.FloatPrecision(float64(u.Weight), 2)),
 			HasExternalName:   hasExternal,
 			ItemVariantPrices: Price,
 		}
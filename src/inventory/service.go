@@ -28,7 +28,7 @@ func FifoStockOut(itemVarID int64, quantity float32, refType string, refID uint6
 	var VSstock []model.ItemVariantStock
 	itemVarStock := new(model.ItemVariantStock)
 	o := orm.NewOrm().QueryTable(itemVarStock)
-	if _, e = o.Filter("item_variant_id", itemVarID).Exclude("available_stock", 0).OrderBy("id").RelatedSel().All(&VSstock); e == nil {
+	if _, e = o.Filter("item_variant_id", itemVarID).Filter("location", "warehouse").Exclude("available_stock", 0).OrderBy("id").RelatedSel().All(&VSstock); e == nil {
 		var varStock []*VariantStock
 		if varStock, e = filterTotalItemVariantStock(VSstock, quantity); e == nil {
 			stockLog, e = variantStockOut(varStock, refType, refID)
@@ -39,8 +39,14 @@ func FifoStockOut(itemVarID int64, quantity float32, refType string, refID uint6
 
 // FifoStockIn untuk membuat item variant stock baru dan log nya
 func FifoStockIn(itemVariant *model.ItemVariant, unitCost float64, quantity float32, refType string, refID uint64) (varStock *model.ItemVariantStock, e error) {
+	return StockIn(itemVariant, unitCost, quantity, "warehouse", refType, refID)
+}
+
+// StockIn untuk membuat item variant stock baru pada location warehouse atau quarantine beserta log nya,
+// stock quarantine tidak ikut diambil FIFO dan tidak dihitung sebagai available stock item variant.
+func StockIn(itemVariant *model.ItemVariant, unitCost float64, quantity float32, location string, refType string, refID uint64) (varStock *model.ItemVariantStock, e error) {
 	sc, _ := util.GenerateCodeSKU(itemVariant.ID)
-	varStock = &model.ItemVariantStock{ItemVariant: itemVariant, SkuCode: sc, AvailableStock: quantity, UnitCost: unitCost, CreatedAt: time.Now()}
+	varStock = &model.ItemVariantStock{ItemVariant: itemVariant, SkuCode: sc, AvailableStock: quantity, UnitCost: unitCost, Location: location, CreatedAt: time.Now()}
 	if e = varStock.Save(); e == nil {
 		sLog := &model.ItemVariantStockLog{ItemVariantStock: varStock, RefID: refID, RefType: refType, LogType: "in", Quantity: quantity, FinalStock: quantity}
 		if e = sLog.Save(); e == nil {
@@ -129,7 +135,7 @@ func filterCancelStock(IVLog model.ItemVariantStockLog, refType string) (res boo
 	o := orm.NewOrm()
 	res = false
 	var sLog *model.ItemVariantStockLog
-	if refType == "workorder_receiving" || refType == "direct_placement" {
+	if refType == "workorder_receiving" || refType == "direct_placement" || refType == "sales_return" {
 		if e := o.Raw("SELECT * FROM item_variant_stock_log ivl WHERE ivl.item_variant_stock_id = ? AND ivl.log_type='out' LIMIT 1", IVLog.ItemVariantStock.ID).QueryRow(&sLog); e != nil && sLog == nil {
 			res = true
 		}
@@ -365,7 +371,7 @@ func SavingUpdateItem(itm model.Item) (*model.Item, error) {
 	if e = itm.Save("category_id", "updated_at", "updated_by", "note", "has_variant", "item_name"); e == nil {
 		for _, variant := range itm.ItemVariants {
 			if variant.ID != int64(0) {
-				if e = variant.Save("updated_at", "updated_by", "measurement_id", "external_name", "variant_name", "image", "base_price", "note", "minimum_stock", "has_external_name"); e != nil {
+				if e = variant.Save("updated_at", "updated_by", "measurement_id", "external_name", "variant_name", "image", "base_price", "note", "minimum_stock", "weight", "has_external_name"); e != nil {
 					return nil, e
 				}
 			} else {
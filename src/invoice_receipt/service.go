@@ -6,6 +6,7 @@ package invoiceReceipt
 
 import (
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/sales_invoice"
 
 	"git.qasico.com/cuxs/orm"
@@ -437,6 +438,7 @@ func CreateInvoiceReceiptPayment(InvoiceReceiptID int64, invoiceReceiptItems []*
 		if e := mx.Save(); e != nil {
 			return e
 		}
+		generalLedger.AutoPost("finance_revenue", mx.ID)
 	}
 
 	o := orm.NewOrm()
@@ -515,6 +517,7 @@ func CreateInvoiceReceiptPayment(InvoiceReceiptID int64, invoiceReceiptItems []*
 		financeRevenue.DocumentStatus = "cleared"
 		financeRevenue.Amount = invoiceReceipt.TotalReturn
 		financeRevenue.Save()
+		generalLedger.AutoPost("finance_revenue", financeRevenue.ID)
 
 		// buat data finance expense
 		financeExpense := new(model.FinanceExpense)
@@ -524,6 +527,7 @@ func CreateInvoiceReceiptPayment(InvoiceReceiptID int64, invoiceReceiptItems []*
 		financeExpense.DocumentStatus = "cleared"
 		financeExpense.Amount = invoiceReceipt.TotalReturn
 		financeExpense.Save()
+		generalLedger.AutoPost("finance_expense", financeExpense.ID)
 	}
 
 	var salesReturn = &model.SalesReturn{}
@@ -538,6 +542,7 @@ func CreateInvoiceReceiptPayment(InvoiceReceiptID int64, invoiceReceiptItems []*
 			if sumexp == uu.SalesReturn.TotalAmount {
 				salesReturn.DocumentStatus = "finished"
 				salesReturn.Save("DocumentStatus")
+				generalLedger.AutoPost("sales_return", salesReturn.ID)
 			}
 		}
 	}
@@ -0,0 +1,161 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package landedCost_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/inventory"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("landed_cost_adjustment", "landed_cost_item", "landed_cost_charge", "landed_cost", "journal_entry_line", "journal_entry")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	lc := model.DummyLandedCost()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/landed-cost", "GET", http.StatusOK},
+		{"/v1/landed-cost/" + common.Encrypt(lc.ID), "GET", http.StatusOK},
+		{"/v1/landed-cost/999999", "GET", http.StatusNotFound},
+		{"/v1/landed-cost", "POST", http.StatusUnprocessableEntity},
+		{"/v1/landed-cost/999999/cancel", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestLandedCostAllocation(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	po := model.DummyPurchaseOrder()
+	po.IsDeleted = 0
+	po.DocumentStatus = "active"
+	po.ShipmentCost = 3000
+	po.Save()
+
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.Weight = 0
+	iv.Save()
+
+	poi := model.DummyPurchaseOrderItem()
+	poi.PurchaseOrder = po
+	poi.ItemVariant = iv
+	poi.Quantity = 20
+	poi.UnitPrice = 1000
+	poi.Discount = 0
+	poi.Save()
+
+	wr := model.DummyWorkorderReceiving()
+	wr.PurchaseOrder = po
+	wr.IsDeleted = 0
+	wr.DocumentStatus = "finished"
+	wr.Save()
+
+	wri := model.DummyWorkorderReceivingItem()
+	wri.WorkorderReceiving = wr
+	wri.PurchaseOrderItem = poi
+	wri.Quantity = 20
+	wri.Save()
+
+	batch, _ := inventory.FifoStockIn(iv, poi.UnitPrice, 20, "workorder_receiving", uint64(wr.ID))
+
+	request := func(method string, charges []tester.D) tester.D {
+		return tester.D{
+			"recognition_date":      time.Now(),
+			"allocation_method":     method,
+			"workorder_receivings":  []string{common.Encrypt(wr.ID)},
+			"include_shipment_cost": true,
+			"landed_cost_charges":   charges,
+		}
+	}
+	customs := []tester.D{{"charge_type": "customs", "reference": "PIB-001", "amount": 1000}}
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		// item variant belum memiliki berat
+		{request("weight", customs), http.StatusUnprocessableEntity},
+		{request("value", []tester.D{{"charge_type": "shipment_cost", "amount": 1000}}), http.StatusUnprocessableEntity},
+		{request("quantity", customs), http.StatusOK},
+		// shipment cost purchase order sudah dialokasikan
+		{request("quantity", customs), http.StatusUnprocessableEntity},
+	}
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/landed-cost").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	var lc model.LandedCost
+	orm.NewOrm().QueryTable(new(model.LandedCost)).OrderBy("-id").Limit(1).One(&lc)
+	assert.Equal(t, float64(4000), lc.TotalAmount)
+	assert.Equal(t, "active", lc.DocumentStatus)
+
+	// shipment cost 3000 + customs 1000 dibagi 20 quantity
+	batch.Read()
+	assert.Equal(t, float64(1200), batch.UnitCost)
+
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/landed-cost/"+common.Encrypt(lc.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		})
+
+	batch.Read()
+	assert.Equal(t, float64(1000), batch.UnitCost)
+
+	// landed cost yang sudah dicancel tidak bisa dicancel kembali
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/landed-cost/"+common.Encrypt(lc.ID)+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+}
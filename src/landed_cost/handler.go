@@ -0,0 +1,97 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package landedCost
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for landed cost.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("landed_cost_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("landed_cost_read"))
+	r.POST("", h.create, auth.CheckPrivilege("landed_cost_create"))
+	r.PUT("/:id/cancel", h.cancel, auth.CheckPrivilege("landed_cost_cancel"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.LandedCost
+	if data, total, e = GetLandedCosts(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to handle get http method.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.LandedCost
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowLandedCost("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk membuat landed cost dan mengalokasikannya ke unit cost batch receiving.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r createRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = CreateLandedCost(m); e == nil {
+				m, _ = ShowLandedCost("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// cancel endpoint untuk membatalkan landed cost dan mengembalikan unit cost batch receiving.
+func (h *Handler) cancel(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r cancelRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.LandedCost, e = ShowLandedCost("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = CancelLandedCost(r.LandedCost, r.Session.User); e == nil {
+						ctx.Data(r.LandedCost)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package landedCost
+
+import (
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// createRequest data struct that stored request data when requesting an create landed cost process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	RecognitionDate     time.Time         `json:"recognition_date" valid:"required"`
+	AllocationMethod    string            `json:"allocation_method" valid:"required|in:value,quantity,weight"`
+	WorkorderReceivings []string          `json:"workorder_receivings" valid:"required"`
+	IncludeShipmentCost bool              `json:"include_shipment_cost"`
+	LandedCostCharges   []chargeRequest   `json:"landed_cost_charges"`
+	Note                string            `json:"note"`
+	Session             *auth.SessionData `json:"-"`
+
+	Charges []*model.LandedCostCharge `json:"-"`
+	Lines   []*model.LandedCostItem   `json:"-"`
+}
+
+type chargeRequest struct {
+	ChargeType    string  `json:"charge_type" valid:"required|in:freight,customs,insurance,other"`
+	PartnershipID string  `json:"partnership_id"`
+	Reference     string  `json:"reference"`
+	Amount        float64 `json:"amount" valid:"required|gt:0"`
+	Note          string  `json:"note"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	checkDuplicate := make(map[int64]bool)
+	checkOrder := make(map[int64]bool)
+	for i, id := range r.WorkorderReceivings {
+		wrID, e := common.Decrypt(id)
+		if e != nil {
+			o.Failure(fmt.Sprintf("workorder_receivings.%d.invalid", i), "workorder_receiving_id not valid")
+			continue
+		}
+
+		wr := &model.WorkorderReceiving{ID: wrID}
+		if e = wr.Read(); e != nil || wr.IsDeleted == int8(1) {
+			o.Failure(fmt.Sprintf("workorder_receivings.%d.invalid", i), "workorder receiving is already deleted or does not exists")
+			continue
+		}
+
+		if checkDuplicate[wrID] {
+			o.Failure(fmt.Sprintf("workorder_receivings.%d.invalid", i), "workorder receiving id duplicate")
+			continue
+		}
+		checkDuplicate[wrID] = true
+
+		lines, e := receivingLines(wr)
+		if e != nil {
+			o.Failure(fmt.Sprintf("workorder_receivings.%d.invalid", i), e.Error())
+			continue
+		}
+		r.Lines = append(r.Lines, lines...)
+
+		// shipment cost purchase order hanya dialokasikan satu kali
+		if r.IncludeShipmentCost && wr.PurchaseOrder != nil && !checkOrder[wr.PurchaseOrder.ID] {
+			checkOrder[wr.PurchaseOrder.ID] = true
+
+			po := &model.PurchaseOrder{ID: wr.PurchaseOrder.ID}
+			if e = po.Read(); e == nil && po.ShipmentCost > 0 {
+				if shipmentCostAllocated(po.ID) {
+					o.Failure(fmt.Sprintf("workorder_receivings.%d.invalid", i), "shipment cost of the purchase order is already allocated")
+				} else {
+					r.Charges = append(r.Charges, &model.LandedCostCharge{
+						ChargeType:    "shipment_cost",
+						PurchaseOrder: po,
+						Partnership:   po.Supplier,
+						Reference:     po.Code,
+						Amount:        po.ShipmentCost,
+					})
+				}
+			}
+		}
+	}
+
+	for i, c := range r.LandedCostCharges {
+		charge := &model.LandedCostCharge{
+			ChargeType: c.ChargeType,
+			Reference:  c.Reference,
+			Amount:     common.FloatPrecision(c.Amount, 0),
+			Note:       c.Note,
+		}
+
+		if c.PartnershipID != "" {
+			if pID, e := common.Decrypt(c.PartnershipID); e != nil {
+				o.Failure(fmt.Sprintf("landed_cost_charges.%d.partnership_id.invalid", i), "partnership_id not valid")
+			} else {
+				p := &model.Partnership{ID: pID}
+				if e = p.Read(); e != nil || p.IsDeleted == int8(1) {
+					o.Failure(fmt.Sprintf("landed_cost_charges.%d.partnership_id.invalid", i), "partnership is already deleted or does not exists")
+				}
+				charge.Partnership = p
+			}
+		}
+
+		r.Charges = append(r.Charges, charge)
+	}
+
+	var total, basis float64
+	for _, c := range r.Charges {
+		total += c.Amount
+	}
+	if total <= 0 {
+		o.Failure("landed_cost_charges", "total landed cost must be greater than 0")
+	}
+
+	for _, l := range r.Lines {
+		l.Basis = allocationBasis(l, r.AllocationMethod)
+		basis += l.Basis
+	}
+	if len(r.Lines) > 0 && basis <= 0 {
+		o.Failure("allocation_method", fmt.Sprintf("received items has no %s to allocate the landed cost", r.AllocationMethod))
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.LandedCost {
+	code, _ := util.CodeGen("code_landed_cost", "landed_cost")
+
+	m := &model.LandedCost{
+		Code:              code,
+		RecognitionDate:   r.RecognitionDate,
+		AllocationMethod:  r.AllocationMethod,
+		Note:              r.Note,
+		CreatedBy:         r.Session.User,
+		CreatedAt:         time.Now(),
+		LandedCostCharges: r.Charges,
+		LandedCostItems:   r.Lines,
+	}
+
+	for _, c := range r.Charges {
+		m.TotalAmount += c.Amount
+	}
+
+	return m
+}
+
+// cancelRequest data struct that stored request data when requesting an cancel landed cost process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type cancelRequest struct {
+	Session    *auth.SessionData `json:"-"`
+	LandedCost *model.LandedCost `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *cancelRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.LandedCost.DocumentStatus != "active" {
+		o.Failure("document_status", "landed cost is already cancelled")
+		return o
+	}
+
+	// landed cost tidak bisa dicancel apabila stock batch sudah keluar setelah alokasi
+	for _, l := range r.LandedCost.LandedCostItems {
+		batch := &model.ItemVariantStock{ID: l.ItemVariantStock.ID}
+		if e := batch.Read(); e != nil || consumedQuantity(l, batch) != l.ConsumedQuantity {
+			o.Failure("document_status", "stock batch of the landed cost has been moved since allocated")
+			break
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *cancelRequest) Messages() map[string]string {
+	return map[string]string{}
+}
@@ -0,0 +1,281 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package landedCost
+
+import (
+	"errors"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// consumption quantity batch yang sudah difulfill ke sebuah sales order.
+type consumption struct {
+	SalesOrderID int64   `orm:"column(sales_order_id)"`
+	Quantity     float32 `orm:"column(quantity)"`
+}
+
+// GetLandedCosts get all data landed cost that matched with query request parameters.
+// returning slices of landed cost, total data without limit and error.
+func GetLandedCosts(rq *orm.RequestQuery) (m *[]model.LandedCost, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.LandedCost))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.LandedCost
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowLandedCost find a single data landed cost using field and value condition,
+// beserta biaya, alokasi per item receiving dan penyesuaian cost sales order.
+func ShowLandedCost(field string, values ...interface{}) (*model.LandedCost, error) {
+	m := new(model.LandedCost)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel().Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.LandedCostCharge)).Filter("landed_cost_id", m.ID).RelatedSel("PurchaseOrder", "Partnership").OrderBy("id").All(&m.LandedCostCharges)
+	o.QueryTable(new(model.LandedCostItem)).Filter("landed_cost_id", m.ID).RelatedSel("WorkorderReceivingItem", "ItemVariantStock").OrderBy("id").All(&m.LandedCostItems)
+	o.QueryTable(new(model.LandedCostAdjustment)).Filter("landed_cost_id", m.ID).RelatedSel("SalesOrder").OrderBy("id").All(&m.LandedCostAdjustments)
+
+	return m, nil
+}
+
+// shipmentCostAllocated cek apakah shipment cost purchase order sudah dialokasikan
+// pada landed cost lain yang masih active.
+func shipmentCostAllocated(poID int64) bool {
+	var total int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM landed_cost_charge lcc "+
+		"INNER JOIN landed_cost lc ON lc.id = lcc.landed_cost_id "+
+		"WHERE lcc.purchase_order_id = ? AND lcc.charge_type = 'shipment_cost' AND lc.document_status = 'active';", poID).QueryRow(&total)
+
+	return total > 0
+}
+
+// receivingLines mengambil item receiving beserta batch stock yang masuk dari receiving tersebut,
+// batch dibuat berurutan sesuai urutan item receiving sehingga dipasangkan berdasarkan urutan id.
+func receivingLines(wr *model.WorkorderReceiving) (lines []*model.LandedCostItem, e error) {
+	o := orm.NewOrm()
+
+	var items []*model.WorkorderReceivingItem
+	if _, e = o.QueryTable(new(model.WorkorderReceivingItem)).Filter("workorder_receiving_id", wr.ID).RelatedSel("PurchaseOrderItem").OrderBy("id").All(&items); e != nil {
+		return
+	}
+
+	var batches []*model.ItemVariantStock
+	if _, e = o.Raw("SELECT DISTINCT ivs.* FROM item_variant_stock ivs "+
+		"INNER JOIN item_variant_stock_log l ON l.item_variant_stock_id = ivs.id "+
+		"WHERE l.ref_type = 'workorder_receiving' AND l.ref_id = ? AND l.log_type = 'in' "+
+		"ORDER BY ivs.id;", wr.ID).QueryRows(&batches); e != nil {
+		return
+	}
+
+	if len(items) == 0 || len(batches) != len(items) {
+		return nil, errors.New("stock batch of the receiving is not found")
+	}
+
+	for i, item := range items {
+		if batches[i].ItemVariant == nil || batches[i].ItemVariant.ID != item.PurchaseOrderItem.ItemVariant.ID {
+			return nil, errors.New("stock batch of the receiving is not found")
+		}
+
+		lines = append(lines, &model.LandedCostItem{
+			WorkorderReceivingItem: item,
+			ItemVariantStock:       batches[i],
+			Quantity:               item.Quantity,
+		})
+	}
+
+	return
+}
+
+// allocationBasis menghitung dasar alokasi item receiving sesuai allocation method,
+// value menggunakan harga purchase order setelah discount dan weight menggunakan berat item variant.
+func allocationBasis(line *model.LandedCostItem, method string) float64 {
+	qty := float64(line.Quantity)
+	switch method {
+	case "value":
+		poi := line.WorkorderReceivingItem.PurchaseOrderItem
+		return common.FloatPrecision(qty*(poi.UnitPrice-(poi.UnitPrice*float64(poi.Discount)/float64(100))), 2)
+	case "weight":
+		iv := &model.ItemVariant{ID: line.ItemVariantStock.ItemVariant.ID}
+		iv.Read()
+		return common.FloatPrecision(qty*float64(iv.Weight), 2)
+	}
+
+	return qty
+}
+
+// allocate membagi total biaya ke setiap item sesuai proporsi basis,
+// sisa pembulatan dibebankan ke item terakhir agar total alokasi sama dengan total biaya.
+func allocate(lines []*model.LandedCostItem, total float64) {
+	var basis, allocated float64
+	for _, l := range lines {
+		basis += l.Basis
+	}
+
+	for i, l := range lines {
+		if i == len(lines)-1 {
+			l.AllocatedAmount = total - allocated
+		} else if basis > 0 {
+			l.AllocatedAmount = common.FloatPrecision(total*l.Basis/basis, 0)
+		}
+		allocated += l.AllocatedAmount
+	}
+}
+
+// consumedQuantity quantity batch yang sudah keluar, baik karena fulfillment, purchase return maupun stockopname.
+func consumedQuantity(line *model.LandedCostItem, batch *model.ItemVariantStock) float32 {
+	if consumed := line.Quantity - batch.AvailableStock; consumed > 0 {
+		return consumed
+	}
+
+	return 0
+}
+
+// fulfilledQuantities mengambil quantity batch yang sudah difulfill per sales order.
+func fulfilledQuantities(batchID int64) (m []*consumption, e error) {
+	_, e = orm.NewOrm().Raw("SELECT wf.sales_order_id, SUM(IF(l.log_type = 'out', l.quantity, -l.quantity)) AS quantity "+
+		"FROM item_variant_stock_log l "+
+		"INNER JOIN workorder_fulfillment wf ON wf.id = l.ref_id "+
+		"WHERE l.ref_type = 'workorder_fulfillment' AND l.item_variant_stock_id = ? "+
+		"GROUP BY wf.sales_order_id HAVING quantity > 0;", batchID).QueryRows(&m)
+
+	return
+}
+
+// revalue menaikkan unit cost batch sesuai alokasi biaya, quantity yang sudah keluar
+// dicatat sebagai penyesuaian yang menambah total cost sales order.
+func revalue(lc *model.LandedCost, line *model.LandedCostItem) (e error) {
+	batch := &model.ItemVariantStock{ID: line.ItemVariantStock.ID}
+	if e = batch.Read(); e != nil {
+		return
+	}
+
+	increment := line.AllocatedAmount / float64(line.Quantity)
+	line.LandedCost = &model.LandedCost{ID: lc.ID}
+	line.UnitCostBefore = batch.UnitCost
+	line.UnitCostAfter = common.FloatPrecision(batch.UnitCost+increment, 0)
+	line.ConsumedQuantity = consumedQuantity(line, batch)
+	line.ConsumedAmount = common.FloatPrecision(float64(line.ConsumedQuantity)*increment, 0)
+	if e = line.Save(); e != nil {
+		return
+	}
+
+	batch.UnitCost = line.UnitCostAfter
+	batch.UpdatedAt = time.Now()
+	if e = batch.Save("UnitCost", "UpdatedAt"); e != nil {
+		return
+	}
+
+	var fulfilled []*consumption
+	if fulfilled, e = fulfilledQuantities(batch.ID); e != nil {
+		return
+	}
+
+	for _, f := range fulfilled {
+		adj := &model.LandedCostAdjustment{
+			LandedCost:     &model.LandedCost{ID: lc.ID},
+			LandedCostItem: line,
+			SalesOrder:     &model.SalesOrder{ID: f.SalesOrderID},
+			Quantity:       f.Quantity,
+			Amount:         common.FloatPrecision(float64(f.Quantity)*increment, 0),
+		}
+		if e = adj.Save(); e != nil {
+			return
+		}
+
+		if e = adj.SalesOrder.Read(); e == nil {
+			adj.SalesOrder.TotalCost += adj.Amount
+			e = adj.SalesOrder.Save("TotalCost")
+		}
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// CreateLandedCost menyimpan landed cost beserta biayanya, membagi total biaya ke item receiving,
+// merevaluasi unit cost batch stock yang terkait dan membuat jurnal landed cost.
+func CreateLandedCost(lc *model.LandedCost) (e error) {
+	allocate(lc.LandedCostItems, lc.TotalAmount)
+
+	lc.DocumentStatus = "active"
+	if e = lc.Save(); e != nil {
+		return
+	}
+
+	for _, c := range lc.LandedCostCharges {
+		c.LandedCost = &model.LandedCost{ID: lc.ID}
+		if e = c.Save(); e != nil {
+			return
+		}
+	}
+
+	for _, line := range lc.LandedCostItems {
+		if e = revalue(lc, line); e != nil {
+			return
+		}
+	}
+
+	generalLedger.AutoPost("landed_cost", lc.ID)
+
+	return
+}
+
+// CancelLandedCost membatalkan landed cost, unit cost batch dan total cost sales order
+// dikembalikan sebesar alokasi landed cost tersebut.
+func CancelLandedCost(lc *model.LandedCost, user *model.User) (e error) {
+	for _, line := range lc.LandedCostItems {
+		batch := &model.ItemVariantStock{ID: line.ItemVariantStock.ID}
+		if e = batch.Read(); e != nil {
+			return
+		}
+
+		batch.UnitCost -= line.UnitCostAfter - line.UnitCostBefore
+		batch.UpdatedAt = time.Now()
+		if e = batch.Save("UnitCost", "UpdatedAt"); e != nil {
+			return
+		}
+	}
+
+	for _, adj := range lc.LandedCostAdjustments {
+		so := &model.SalesOrder{ID: adj.SalesOrder.ID}
+		if e = so.Read(); e != nil {
+			return
+		}
+
+		so.TotalCost -= adj.Amount
+		if e = so.Save("TotalCost"); e != nil {
+			return
+		}
+	}
+
+	lc.DocumentStatus = "cancelled"
+	lc.UpdatedBy = user
+	lc.UpdatedAt = time.Now()
+	if e = lc.Save("DocumentStatus", "UpdatedBy", "UpdatedAt"); e == nil {
+		generalLedger.AutoReverse("landed_cost", lc.ID)
+	}
+
+	return
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package landedCost
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/inventory"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+// dummyReceiving membuat receiving purchase order beserta batch stock yang masuk dari receiving tersebut.
+func dummyReceiving(po *model.PurchaseOrder, items ...*model.PurchaseOrderItem) *model.WorkorderReceiving {
+	wr := model.DummyWorkorderReceiving()
+	wr.PurchaseOrder = po
+	wr.IsDeleted = 0
+	wr.DocumentStatus = "finished"
+	wr.Save()
+
+	for _, poi := range items {
+		wri := model.DummyWorkorderReceivingItem()
+		wri.WorkorderReceiving = wr
+		wri.PurchaseOrderItem = poi
+		wri.Quantity = poi.Quantity
+		wri.Save()
+
+		inventory.FifoStockIn(poi.ItemVariant, poi.UnitPrice, poi.Quantity, "workorder_receiving", uint64(wr.ID))
+	}
+
+	return wr
+}
+
+func dummyOrderItem(po *model.PurchaseOrder, quantity float32, price float64, weight float32) *model.PurchaseOrderItem {
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.Weight = weight
+	iv.Save()
+
+	poi := model.DummyPurchaseOrderItem()
+	poi.PurchaseOrder = po
+	poi.ItemVariant = iv
+	poi.Quantity = quantity
+	poi.UnitPrice = price
+	poi.Discount = 0
+	poi.Save()
+
+	return poi
+}
+
+func dummyOrder() *model.PurchaseOrder {
+	po := model.DummyPurchaseOrder()
+	po.IsDeleted = 0
+	po.DocumentStatus = "active"
+	po.ShipmentCost = 0
+	po.Save()
+
+	return po
+}
+
+func TestShowLandedCost(t *testing.T) {
+	_, e := ShowLandedCost("id", 999999)
+	assert.Error(t, e, "Response should be error, beacuse there are no data yet.")
+
+	c := model.DummyLandedCostCharge()
+	m, e := ShowLandedCost("id", c.LandedCost.ID)
+	assert.NoError(t, e, "Data should be exists.")
+	assert.Len(t, m.LandedCostCharges, 1)
+}
+
+func TestAllocate(t *testing.T) {
+	lines := []*model.LandedCostItem{{Basis: 1}, {Basis: 1}, {Basis: 1}}
+	allocate(lines, 1000)
+
+	// sisa pembulatan masuk ke item terakhir
+	assert.Equal(t, float64(333), lines[0].AllocatedAmount)
+	assert.Equal(t, float64(333), lines[1].AllocatedAmount)
+	assert.Equal(t, float64(334), lines[2].AllocatedAmount)
+}
+
+func TestAllocationBasis(t *testing.T) {
+	po := dummyOrder()
+	wr := dummyReceiving(po, dummyOrderItem(po, 10, 2000, 0.5), dummyOrderItem(po, 30, 1000, 2))
+
+	lines, e := receivingLines(wr)
+	assert.NoError(t, e)
+	assert.Len(t, lines, 2)
+
+	assert.Equal(t, float64(20000), allocationBasis(lines[0], "value"))
+	assert.Equal(t, float64(30000), allocationBasis(lines[1], "value"))
+	assert.Equal(t, float64(10), allocationBasis(lines[0], "quantity"))
+	assert.Equal(t, float64(5), allocationBasis(lines[0], "weight"))
+	assert.Equal(t, float64(60), allocationBasis(lines[1], "weight"))
+}
+
+func TestCreateAndCancelLandedCost(t *testing.T) {
+	po := dummyOrder()
+	poi1 := dummyOrderItem(po, 10, 2000, 1)
+	poi2 := dummyOrderItem(po, 30, 1000, 1)
+	wr := dummyReceiving(po, poi1, poi2)
+
+	// sebagian batch item pertama sudah difulfill ke sales order
+	so := model.DummySalesOrder()
+	so.TotalCost = 8000
+	so.Save()
+	wf := model.DummyWorkorderFulfillment()
+	wf.SalesOrder = so
+	wf.Save()
+	inventory.FifoStockOut(poi1.ItemVariant.ID, 4, "workorder_fulfillment", uint64(wf.ID))
+
+	lines, _ := receivingLines(wr)
+	for _, l := range lines {
+		l.Basis = allocationBasis(l, "value")
+	}
+
+	lc := &model.LandedCost{
+		Code:             "LC-TEST",
+		RecognitionDate:  time.Now(),
+		AllocationMethod: "value",
+		TotalAmount:      5000,
+		CreatedBy:        model.DummyUser(),
+		CreatedAt:        time.Now(),
+		LandedCostCharges: []*model.LandedCostCharge{
+			{ChargeType: "freight", Amount: 5000},
+		},
+		LandedCostItems: lines,
+	}
+	assert.NoError(t, CreateLandedCost(lc))
+
+	// 5000 dibagi 20000 : 30000
+	assert.Equal(t, float64(2000), lines[0].AllocatedAmount)
+	assert.Equal(t, float64(3000), lines[1].AllocatedAmount)
+
+	batch := &model.ItemVariantStock{ID: lines[0].ItemVariantStock.ID}
+	batch.Read()
+	assert.Equal(t, float64(2200), batch.UnitCost)
+	assert.Equal(t, float32(4), lines[0].ConsumedQuantity)
+	assert.Equal(t, float64(800), lines[0].ConsumedAmount)
+
+	so.Read()
+	assert.Equal(t, float64(8800), so.TotalCost)
+
+	m, e := ShowLandedCost("id", lc.ID)
+	assert.NoError(t, e)
+	assert.Len(t, m.LandedCostAdjustments, 1)
+
+	// revaluasi dan quantity yang sudah keluar dijurnal
+	var journals int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM journal_entry WHERE ref_type = 'landed_cost' AND ref_id = ?", lc.ID).QueryRow(&journals)
+	assert.Equal(t, int64(1), journals)
+
+	assert.NoError(t, CancelLandedCost(m, model.DummyUser()))
+	batch.Read()
+	assert.Equal(t, float64(2000), batch.UnitCost)
+	so.Read()
+	assert.Equal(t, float64(8000), so.TotalCost)
+	assert.Equal(t, "cancelled", m.DocumentStatus)
+
+	// pembatalan membuat jurnal balik
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM journal_entry WHERE ref_type = 'landed_cost' AND ref_id = ?", lc.ID).QueryRow(&journals)
+	assert.Equal(t, int64(2), journals)
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package operatingExpense_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("journal_entry_line", "journal_entry", "operating_expense")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	oe := model.DummyOperatingExpense()
+	id := common.Encrypt(oe.ID)
+	ecID := common.Encrypt(oe.ExpenseCategory.ID)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/operating-expense", "GET", http.StatusOK},
+		{"/v1/operating-expense/" + id, "GET", http.StatusOK},
+		{"/v1/operating-expense/999999", "GET", http.StatusNotFound},
+		{"/v1/operating-expense/category", "GET", http.StatusOK},
+		{"/v1/operating-expense/category/" + ecID, "GET", http.StatusOK},
+		{"/v1/operating-expense/category/999999", "GET", http.StatusNotFound},
+		{"/v1/operating-expense/" + id + "/stop-recurring", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/operating-expense/" + id + "/approve", "PUT", http.StatusOK},
+		{"/v1/operating-expense/" + id + "/approve", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/operating-expense/" + id + "/cancel", "PUT", http.StatusOK},
+		{"/v1/operating-expense/" + id + "/cancel", "PUT", http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_CreateCategory(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	expense := &model.ChartOfAccount{Code: "6101"}
+	expense.Read("Code")
+	cash := &model.ChartOfAccount{Code: "1101"}
+	cash.Read("Code")
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"chart_of_account_id": common.Encrypt(expense.ID), "name": "Listrik"}, http.StatusOK},
+		// akun bukan akun beban
+		{tester.D{"chart_of_account_id": common.Encrypt(cash.ID), "name": "Listrik"}, http.StatusUnprocessableEntity},
+		{tester.D{"chart_of_account_id": "xxx", "name": "Listrik"}, http.StatusUnprocessableEntity},
+		{tester.D{"chart_of_account_id": common.Encrypt(expense.ID), "name": ""}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/operating-expense/category").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
+
+func TestHandler_CreateOperatingExpense(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	ecID := common.Encrypt(model.DummyExpenseCategory().ID)
+	baID := common.Encrypt(model.DummyBankAccount().ID)
+	archived := model.DummyExpenseCategory()
+	archived.IsArchived = 1
+	archived.Save()
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "cash", "attachment": "/uploads/bensin.jpg"}, http.StatusOK},
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 5000000, "payment_method": "bank", "bank_account_id": baID, "is_recurring": 1, "recurring_interval": "monthly"}, http.StatusOK},
+		// pembayaran bank tanpa rekening
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "bank"}, http.StatusUnprocessableEntity},
+		// pembayaran cash dengan rekening
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "cash", "bank_account_id": baID}, http.StatusUnprocessableEntity},
+		// interval berulang tidak valid
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "cash", "is_recurring": 1, "recurring_interval": "daily"}, http.StatusUnprocessableEntity},
+		// recurring end date sebelum recognition date
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "cash", "is_recurring": 1, "recurring_interval": "weekly", "recurring_end_date": "2018-02-01T00:00:00Z"}, http.StatusUnprocessableEntity},
+		// kategori sudah diarsip
+		{tester.D{"expense_category_id": common.Encrypt(archived.ID), "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "cash"}, http.StatusUnprocessableEntity},
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 0, "payment_method": "cash"}, http.StatusUnprocessableEntity},
+		{tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "giro"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/operating-expense").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	// expense yang sudah diapprove tidak bisa diubah
+	oe := model.DummyOperatingExpense()
+	oe.DocumentStatus = "approved"
+	oe.Save()
+	ng.PUT("/v1/operating-expense/"+common.Encrypt(oe.ID)).
+		SetJSON(tester.D{"expense_category_id": ecID, "recognition_date": "2018-03-01T00:00:00Z", "amount": 150000, "payment_method": "cash"}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+}
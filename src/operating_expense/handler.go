@@ -0,0 +1,249 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package operatingExpense
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for operating expense.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/category", h.getCategory, auth.CheckPrivilege("operating_expense_read"))
+	r.GET("/category/:id", h.showCategory, auth.CheckPrivilege("operating_expense_read"))
+	r.POST("/category", h.createCategory, auth.CheckPrivilege("operating_expense_manage"))
+	r.PUT("/category/:id", h.updateCategory, auth.CheckPrivilege("operating_expense_manage"))
+	r.GET("", h.get, auth.CheckPrivilege("operating_expense_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("operating_expense_read"))
+	r.POST("", h.create, auth.CheckPrivilege("operating_expense_manage"))
+	r.PUT("/:id", h.update, auth.CheckPrivilege("operating_expense_manage"))
+	r.PUT("/:id/approve", h.approve, auth.CheckPrivilege("operating_expense_approve"))
+	r.PUT("/:id/cancel", h.cancel, auth.CheckPrivilege("operating_expense_approve"))
+	r.PUT("/:id/stop-recurring", h.stopRecurring, auth.CheckPrivilege("operating_expense_manage"))
+}
+
+// getCategory endpoint to handle get http method.
+func (h *Handler) getCategory(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.ExpenseCategory
+	if data, total, e = GetExpenseCategories(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// showCategory endpoint to handle get http method with id.
+func (h *Handler) showCategory(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.ExpenseCategory
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowExpenseCategory("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// createCategory endpoint untuk menambah kategori expense.
+func (h *Handler) createCategory(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r categoryRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				m, _ = ShowExpenseCategory("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// updateCategory endpoint untuk mengubah kategori expense.
+func (h *Handler) updateCategory(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r categoryRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.ExpenseCategory, e = ShowExpenseCategory("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save(); e == nil {
+						m, _ = ShowExpenseCategory("id", m.ID)
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.OperatingExpense
+	if data, total, e = GetOperatingExpenses(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to handle get http method with id.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.OperatingExpense
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowOperatingExpense("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk membuat operating expense baru.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r expenseRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				m, _ = ShowOperatingExpense("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// update endpoint untuk mengubah operating expense yang belum diapprove.
+func (h *Handler) update(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r expenseRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.OperatingExpense, e = ShowOperatingExpense("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save(); e == nil {
+						m, _ = ShowOperatingExpense("id", m.ID)
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// approve endpoint untuk menyetujui operating expense, expense yang diapprove akan dijurnal.
+func (h *Handler) approve(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	r := statusRequest{Status: "approved"}
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.OperatingExpense, e = ShowOperatingExpense("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = ApproveOperatingExpense(r.OperatingExpense, r.Session.User); e == nil {
+						ctx.Data(r.OperatingExpense)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// cancel endpoint untuk membatalkan operating expense, jurnal expense yang sudah diapprove akan dibalik.
+func (h *Handler) cancel(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	r := statusRequest{Status: "cancelled"}
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.OperatingExpense, e = ShowOperatingExpense("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = CancelOperatingExpense(r.OperatingExpense, r.Session.User); e == nil {
+						ctx.Data(r.OperatingExpense)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// stopRecurring endpoint untuk menghentikan jadwal berulang operating expense.
+func (h *Handler) stopRecurring(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	r := statusRequest{Status: "stopped"}
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.OperatingExpense, e = ShowOperatingExpense("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = StopRecurring(r.OperatingExpense, r.Session.User); e == nil {
+						ctx.Data(r.OperatingExpense)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
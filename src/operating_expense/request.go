@@ -0,0 +1,217 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package operatingExpense
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// categoryRequest data struct that stored request data when requesting an create or update expense category process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type categoryRequest struct {
+	ChartOfAccountID string                 `json:"chart_of_account_id" valid:"required"`
+	Name             string                 `json:"name" valid:"required"`
+	Note             string                 `json:"note"`
+	IsArchived       int8                   `json:"is_archived" valid:"in:0,1"`
+	Session          *auth.SessionData      `json:"-"`
+	ExpenseCategory  *model.ExpenseCategory `json:"-"`
+	ChartOfAccount   *model.ChartOfAccount  `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *categoryRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.ChartOfAccountID != "" {
+		if id, e := common.Decrypt(r.ChartOfAccountID); e == nil {
+			r.ChartOfAccount = &model.ChartOfAccount{ID: id}
+			if e = r.ChartOfAccount.Read(); e != nil || r.ChartOfAccount.IsArchived == int8(1) {
+				o.Failure("chart_of_account_id", "chart_of_account_id is already archived or does not exists")
+			} else if r.ChartOfAccount.AccountType != "expense" {
+				o.Failure("chart_of_account_id", "chart of account must be an expense account")
+			}
+		} else {
+			o.Failure("chart_of_account_id", "chart_of_account_id is not valid")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *categoryRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *categoryRequest) Transform() *model.ExpenseCategory {
+	m := &model.ExpenseCategory{
+		ChartOfAccount: r.ChartOfAccount,
+		Name:           r.Name,
+		Note:           r.Note,
+		IsArchived:     r.IsArchived,
+		CreatedAt:      time.Now(),
+	}
+
+	if r.ExpenseCategory != nil {
+		m.ID = r.ExpenseCategory.ID
+		m.CreatedAt = r.ExpenseCategory.CreatedAt
+		m.UpdatedAt = time.Now()
+	}
+
+	return m
+}
+
+// expenseRequest data struct that stored request data when requesting an create or update operating expense process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type expenseRequest struct {
+	ExpenseCategoryID string                  `json:"expense_category_id" valid:"required"`
+	RecognitionDate   time.Time               `json:"recognition_date" valid:"required"`
+	Amount            float64                 `json:"amount" valid:"required|gt:0"`
+	PaymentMethod     string                  `json:"payment_method" valid:"required|in:cash,bank"`
+	BankAccountID     string                  `json:"bank_account_id"`
+	Attachment        string                  `json:"attachment"`
+	Note              string                  `json:"note"`
+	IsRecurring       int8                    `json:"is_recurring" valid:"in:0,1"`
+	RecurringInterval string                  `json:"recurring_interval"`
+	RecurringEndDate  time.Time               `json:"recurring_end_date"`
+	Session           *auth.SessionData       `json:"-"`
+	OperatingExpense  *model.OperatingExpense `json:"-"`
+
+	ExpenseCategory *model.ExpenseCategory `json:"-"`
+	BankAccount     *model.BankAccount     `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *expenseRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.OperatingExpense != nil && r.OperatingExpense.DocumentStatus != "new" {
+		o.Failure("document_status", "operating expense is already "+r.OperatingExpense.DocumentStatus)
+	}
+
+	if r.ExpenseCategoryID != "" {
+		if id, e := common.Decrypt(r.ExpenseCategoryID); e != nil {
+			o.Failure("expense_category_id", "expense_category_id is not valid")
+		} else if r.ExpenseCategory, e = ShowExpenseCategory("id", id); e != nil || r.ExpenseCategory.IsArchived == int8(1) {
+			o.Failure("expense_category_id", "expense_category_id is already archived or does not exists")
+		}
+	}
+
+	if r.PaymentMethod == "bank" {
+		if id, e := common.Decrypt(r.BankAccountID); e == nil {
+			r.BankAccount = &model.BankAccount{ID: id}
+			if e = r.BankAccount.Read(); e != nil || r.BankAccount.IsArchived == int8(1) || r.BankAccount.AccountType != "bank" {
+				o.Failure("bank_account_id", "bank_account_id is already archived or does not exists")
+			}
+		} else {
+			o.Failure("bank_account_id", "bank_account_id is required for bank payment")
+		}
+	} else if r.BankAccountID != "" {
+		o.Failure("bank_account_id", "bank_account_id can't be inputted if payment method is cash")
+	}
+
+	if r.IsRecurring == int8(1) {
+		if r.RecurringInterval != "weekly" && r.RecurringInterval != "monthly" && r.RecurringInterval != "yearly" {
+			o.Failure("recurring_interval", "recurring_interval must be weekly, monthly or yearly")
+		}
+
+		if !r.RecurringEndDate.IsZero() && r.RecurringEndDate.Before(r.RecognitionDate) {
+			o.Failure("recurring_end_date", "recurring_end_date must be after recognition_date")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *expenseRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *expenseRequest) Transform() *model.OperatingExpense {
+	m := r.OperatingExpense
+	if m == nil {
+		code, _ := util.CodeGen("code_operating_expense", "operating_expense")
+		m = &model.OperatingExpense{
+			Code:           code,
+			DocumentStatus: "new",
+			CreatedBy:      r.Session.User,
+			CreatedAt:      time.Now(),
+		}
+	} else {
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	m.ExpenseCategory = r.ExpenseCategory
+	m.BankAccount = r.BankAccount
+	m.RecognitionDate = r.RecognitionDate
+	m.Amount = r.Amount
+	m.PaymentMethod = r.PaymentMethod
+	m.Attachment = r.Attachment
+	m.Note = r.Note
+	m.IsRecurring = r.IsRecurring
+	m.RecurringInterval = ""
+	m.NextRecurringDate = time.Time{}
+	m.RecurringEndDate = time.Time{}
+	if r.IsRecurring == int8(1) {
+		m.RecurringInterval = r.RecurringInterval
+		m.NextRecurringDate = NextRecurringDate(r.RecognitionDate, r.RecurringInterval, r.RecognitionDate.Day())
+		m.RecurringEndDate = r.RecurringEndDate
+	}
+
+	return m
+}
+
+// statusRequest data struct that stored request data when requesting an approve, cancel
+// or stop recurring operating expense process, status adalah status yang dituju.
+type statusRequest struct {
+	Status           string                  `json:"-"`
+	Session          *auth.SessionData       `json:"-"`
+	OperatingExpense *model.OperatingExpense `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *statusRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	switch r.Status {
+	case "approved":
+		if r.OperatingExpense.DocumentStatus != "new" {
+			o.Failure("document_status", "operating expense is already "+r.OperatingExpense.DocumentStatus)
+		}
+	case "cancelled":
+		if r.OperatingExpense.DocumentStatus == "cancelled" {
+			o.Failure("document_status", "operating expense is already cancelled")
+		}
+	case "stopped":
+		if r.OperatingExpense.IsRecurring != int8(1) {
+			o.Failure("is_recurring", "operating expense is not recurring")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *statusRequest) Messages() map[string]string {
+	return map[string]string{}
+}
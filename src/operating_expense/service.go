@@ -0,0 +1,212 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package operatingExpense
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/labstack/gommon/log"
+)
+
+// Cron membuat operating expense dari semua expense berulang yang jadwalnya sudah jatuh tempo,
+// dijalankan setiap hari dari cronTask pada main.go.
+func Cron() {
+	if _, e := RunRecurring(time.Now()); e != nil {
+		log.Error(e)
+	}
+}
+
+// GetExpenseCategories get all data expense_category that matched with query request parameters.
+// returning slices of expense category, total data without limit and error.
+func GetExpenseCategories(rq *orm.RequestQuery) (m *[]model.ExpenseCategory, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.ExpenseCategory))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.ExpenseCategory
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowExpenseCategory untuk mengambil data detail expense category berdasarkan param
+func ShowExpenseCategory(field string, values ...interface{}) (*model.ExpenseCategory, error) {
+	m := new(model.ExpenseCategory)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// GetOperatingExpenses get all data operating_expense that matched with query request parameters.
+// returning slices of operating expense, total data without limit and error.
+func GetOperatingExpenses(rq *orm.RequestQuery) (m *[]model.OperatingExpense, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.OperatingExpense))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.OperatingExpense
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowOperatingExpense untuk mengambil data detail operating expense berdasarkan param
+func ShowOperatingExpense(field string, values ...interface{}) (*model.OperatingExpense, error) {
+	m := new(model.OperatingExpense)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(2).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ApproveOperatingExpense menyetujui operating expense dan membuat jurnal beban,
+// expense berulang baru akan dibuatkan expense berikutnya setelah diapprove.
+func ApproveOperatingExpense(m *model.OperatingExpense, user *model.User) (e error) {
+	m.DocumentStatus = "approved"
+	m.ApprovedBy = user
+	m.ApprovedAt = time.Now()
+	if e = m.Save("DocumentStatus", "ApprovedBy", "ApprovedAt"); e == nil {
+		generalLedger.AutoPost("operating_expense", m.ID)
+	}
+
+	return
+}
+
+// CancelOperatingExpense membatalkan operating expense beserta jadwal berulang nya,
+// jurnal expense yang sudah diapprove akan dibalik.
+func CancelOperatingExpense(m *model.OperatingExpense, user *model.User) (e error) {
+	m.DocumentStatus = "cancelled"
+	m.IsRecurring = 0
+	m.UpdatedBy = user
+	m.UpdatedAt = time.Now()
+	if e = m.Save("DocumentStatus", "IsRecurring", "UpdatedBy", "UpdatedAt"); e == nil {
+		generalLedger.AutoReverse("operating_expense", m.ID)
+	}
+
+	return
+}
+
+// StopRecurring menghentikan jadwal berulang operating expense tanpa membatalkan expense nya.
+func StopRecurring(m *model.OperatingExpense, user *model.User) (e error) {
+	m.IsRecurring = 0
+	m.UpdatedBy = user
+	m.UpdatedAt = time.Now()
+
+	return m.Save("IsRecurring", "UpdatedBy", "UpdatedAt")
+}
+
+// RunRecurring membuat operating expense baru dari expense berulang yang sudah diapprove
+// dan jadwal berikutnya sudah jatuh tempo pada tanggal tersebut, expense yang dibuat
+// berstatus new sehingga tetap harus diapprove sebelum dijurnal.
+func RunRecurring(date time.Time) (expenses []*model.OperatingExpense, e error) {
+	var m []*model.OperatingExpense
+	o := orm.NewOrm()
+	if _, e = o.QueryTable(new(model.OperatingExpense)).Filter("is_recurring", 1).Filter("document_status", "approved").
+		Filter("next_recurring_date__lte", date.Format("2006-01-02")).All(&m); e != nil {
+		return nil, e
+	}
+
+	for _, oe := range m {
+		// jadwal yang terlewat tetap dibuatkan expense nya satu per satu
+		for !oe.NextRecurringDate.After(truncate(date)) && oe.IsRecurring == int8(1) {
+			if !oe.RecurringEndDate.IsZero() && oe.NextRecurringDate.After(truncate(oe.RecurringEndDate)) {
+				oe.IsRecurring = 0
+				break
+			}
+
+			var ne *model.OperatingExpense
+			if ne, e = createRecurring(oe, oe.NextRecurringDate); e != nil {
+				return expenses, e
+			}
+			expenses = append(expenses, ne)
+
+			oe.NextRecurringDate = NextRecurringDate(oe.NextRecurringDate, oe.RecurringInterval, oe.RecognitionDate.Day())
+		}
+
+		if e = oe.Save("IsRecurring", "NextRecurringDate"); e != nil {
+			return expenses, e
+		}
+	}
+
+	return
+}
+
+// createRecurring membuat operating expense dari expense berulang pada tanggal tersebut.
+func createRecurring(oe *model.OperatingExpense, date time.Time) (m *model.OperatingExpense, e error) {
+	m = &model.OperatingExpense{
+		RecurringOf:     &model.OperatingExpense{ID: oe.ID},
+		ExpenseCategory: oe.ExpenseCategory,
+		BankAccount:     oe.BankAccount,
+		RecognitionDate: date,
+		Amount:          oe.Amount,
+		PaymentMethod:   oe.PaymentMethod,
+		Note:            oe.Note,
+		DocumentStatus:  "new",
+		CreatedBy:       oe.CreatedBy,
+		CreatedAt:       time.Now(),
+	}
+
+	if m.Code, e = util.CodeGen("code_operating_expense", "operating_expense"); e == nil {
+		e = m.Save()
+	}
+
+	return
+}
+
+// NextRecurringDate tanggal expense berikutnya sesuai interval, day adalah tanggal awal expense
+// sehingga jadwal bulanan dan tahunan tetap mengikuti tanggal tersebut atau akhir bulan
+// apabila bulan tersebut tidak memiliki tanggal itu.
+func NextRecurringDate(date time.Time, interval string, day int) time.Time {
+	date = truncate(date)
+	switch interval {
+	case "weekly":
+		return date.AddDate(0, 0, 7)
+	case "yearly":
+		return monthDay(date.Year()+1, date.Month(), day, date.Location())
+	}
+
+	return monthDay(date.Year(), date.Month()+1, day, date.Location())
+}
+
+// monthDay tanggal pada bulan tersebut, dibatasi sampai akhir bulan.
+func monthDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	if day > last {
+		day = last
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// truncate menghilangkan jam pada tanggal.
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
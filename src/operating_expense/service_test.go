@@ -0,0 +1,91 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package operatingExpense
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRecurringDate(t *testing.T) {
+	jan31 := time.Date(2018, 1, 31, 0, 0, 0, 0, time.Local)
+
+	assert.Equal(t, "2018-02-07", NextRecurringDate(jan31, "weekly", 31).Format("2006-01-02"))
+	assert.Equal(t, "2019-01-31", NextRecurringDate(jan31, "yearly", 31).Format("2006-01-02"))
+
+	// jadwal bulanan tanggal 31 dijalankan pada akhir bulan februari dan kembali ke tanggal 31 bulan berikutnya
+	feb := NextRecurringDate(jan31, "monthly", 31)
+	assert.Equal(t, "2018-02-28", feb.Format("2006-01-02"))
+	assert.Equal(t, "2018-03-31", NextRecurringDate(feb, "monthly", 31).Format("2006-01-02"))
+
+	dec := time.Date(2018, 12, 15, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, "2019-01-15", NextRecurringDate(dec, "monthly", 15).Format("2006-01-02"))
+}
+
+func TestApproveAndCancelOperatingExpense(t *testing.T) {
+	user := model.DummyUser()
+	oe := model.DummyOperatingExpense()
+	oe.Amount = 5000
+	oe.Save()
+
+	var total int64
+	o := orm.NewOrm()
+
+	assert.NoError(t, ApproveOperatingExpense(oe, user))
+	assert.Equal(t, "approved", oe.DocumentStatus)
+	assert.Equal(t, user.ID, oe.ApprovedBy.ID)
+	o.Raw("SELECT COUNT(*) FROM journal_entry WHERE ref_type = 'operating_expense' AND ref_id = ?;", oe.ID).QueryRow(&total)
+	assert.Equal(t, int64(1), total)
+
+	// jurnal expense yang dibatalkan dibalik
+	assert.NoError(t, CancelOperatingExpense(oe, user))
+	assert.Equal(t, "cancelled", oe.DocumentStatus)
+	o.Raw("SELECT COUNT(*) FROM journal_entry WHERE ref_type = 'operating_expense' AND ref_id = ?;", oe.ID).QueryRow(&total)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestRunRecurring(t *testing.T) {
+	start := time.Date(2018, 1, 31, 0, 0, 0, 0, time.Local)
+	oe := model.DummyOperatingExpense()
+	oe.RecognitionDate = start
+	oe.Amount = 2000000
+	oe.DocumentStatus = "approved"
+	oe.IsRecurring = 1
+	oe.RecurringInterval = "monthly"
+	oe.NextRecurringDate = NextRecurringDate(start, "monthly", start.Day())
+	oe.RecurringEndDate = time.Date(2018, 4, 15, 0, 0, 0, 0, time.Local)
+	oe.Save()
+
+	// expense yang belum diapprove tidak dibuat ulang
+	draft := model.DummyOperatingExpense()
+	draft.DocumentStatus = "new"
+	draft.IsRecurring = 1
+	draft.NextRecurringDate = start
+	draft.Save()
+
+	expenses, e := RunRecurring(time.Date(2018, 3, 31, 0, 0, 0, 0, time.Local))
+	assert.NoError(t, e)
+	assert.Len(t, expenses, 2)
+	for _, ne := range expenses {
+		assert.Equal(t, oe.ID, ne.RecurringOf.ID)
+		assert.Equal(t, "new", ne.DocumentStatus)
+		assert.Equal(t, oe.Amount, ne.Amount)
+	}
+	assert.Equal(t, "2018-02-28", expenses[0].RecognitionDate.Format("2006-01-02"))
+	assert.Equal(t, "2018-03-31", expenses[1].RecognitionDate.Format("2006-01-02"))
+
+	// jadwal berikutnya melewati recurring end date sehingga jadwal berulang berhenti
+	expenses, e = RunRecurring(time.Date(2018, 5, 1, 0, 0, 0, 0, time.Local))
+	assert.NoError(t, e)
+	assert.Empty(t, expenses)
+
+	oe.Read()
+	assert.Equal(t, int8(0), oe.IsRecurring)
+}
@@ -108,7 +108,7 @@ func TestHandler_URLMappingPartnershipUpdateSuccessWithPlafon(t *testing.T) {
 	// setting body
 	scenario := tester.D{"order_rule": "plafon", "max_plafon": float64(2), "full_name": "test partner", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -120,6 +120,27 @@ func TestHandler_URLMappingPartnershipUpdateSuccessWithPlafon(t *testing.T) {
 	assert.Equal(t, "plafon", fakePartner.OrderRule)
 	assert.Equal(t, "adds", fakePartner.Address)
 	assert.Equal(t, "0000", fakePartner.Phone)
+	assert.Equal(t, user.ID, fakePartner.SalesPerson.ID)
+}
+
+// TestHandler_URLMappingPartnershipUpdateFailSalesPersonNotFound mengetest update dengan sales person yang tidak ada, fail
+func TestHandler_URLMappingPartnershipUpdateFailSalesPersonNotFound(t *testing.T) {
+	fakePartner := model.DummyPartnership()
+	fakePartner.IsDeleted = int8(0)
+	fakePartner.Save()
+	// melakukan proses login
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	// setting body
+	scenario := tester.D{"order_rule": "none", "max_plafon": float64(0), "full_name": "test partner", "sales_person_id": "999999"}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/partnership/"+common.Encrypt(fakePartner.ID)).SetJSON(scenario).Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.Equal(t, int(422), res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", scenario, res.Body.String()))
+	})
 }
 
 // TestHandler_URLMappingPartnershipUpdateSuccessWithoutPlafon mengetest update dengan order rule bukan plafon dan max_plafon 0,success
@@ -139,7 +160,7 @@ func TestHandler_URLMappingPartnershipUpdateSuccessWithoutPlafon(t *testing.T) {
 	// setting body
 	scenario := tester.D{"order_rule": "one_bill", "max_plafon": float64(0), "full_name": "test partner", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -166,7 +187,7 @@ func TestHandler_URLMappingPartnershipUpdateFailWithoutPlafon(t *testing.T) {
 	// setting body
 	scenario := tester.D{"order_rule": "plafon", "max_plafon": float64(0), "full_name": "test partner", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -225,7 +246,7 @@ func TestHandler_URLMappingPartnershipUpdateFailPartnerIsDeleted(t *testing.T) {
 	// setting body
 	scenario := tester.D{"order_rule": "plafon", "max_plafon": float64(2), "full_name": "test partner", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -247,7 +268,7 @@ func TestHandler_URLMappingPartnershipUpdateFailFullnameIsEmpty(t *testing.T) {
 	// setting body
 	scenario := tester.D{"order_rule": "plafon", "max_plafon": float64(2), "full_name": "", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -266,7 +287,7 @@ func TestHandler_URLMappingPartnershipUpdateFailPartnerNotFound(t *testing.T) {
 	// setting body
 	scenario := tester.D{"order_rule": "plafon", "max_plafon": float64(2), "full_name": "name", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -289,7 +310,7 @@ func TestHandler_URLMappingPartnershipCreateSuccessWithoutPlafon(t *testing.T) {
 	// setting body
 	scenario := tester.D{"partnership_type": "customer", "order_rule": "one_bill", "max_plafon": float64(0), "full_name": "test partner", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -320,7 +341,7 @@ func TestHandler_URLMappingPartnershipCreateSuccessWithPlafon(t *testing.T) {
 	// setting body
 	scenario := tester.D{"partnership_type": "supplier", "order_rule": "plafon", "max_plafon": float64(5), "full_name": "test partner", "email": "@mail.com",
 		"phone": "0000", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -351,7 +372,7 @@ func TestHandler_URLMappingPartnershipCreateFailWithPlafon(t *testing.T) {
 	// setting body
 	scenario := tester.D{"partnership_type": "supplier", "order_rule": "plafon", "max_plafon": float64(0), "full_name": "test partner", "email": "@mail.com",
 		"phone": "11111", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -369,7 +390,7 @@ func TestHandler_URLMappingPartnershipCreateFailNoToken(t *testing.T) {
 	// setting body
 	scenario := tester.D{"partnership_type": "supplier", "order_rule": "one_bill", "max_plafon": float64(0), "full_name": "test partner", "email": "@mail.com",
 		"phone": "11111", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": "", "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.POST("/v1/partnership").SetJSON(scenario).Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
@@ -409,7 +430,7 @@ func TestHandler_URLMappingPartnershipCreateFailNameEmpty(t *testing.T) {
 	// setting body
 	scenario := tester.D{"partnership_type": "supplier", "order_rule": "one_bill", "max_plafon": float64(0), "email": "@mail.com",
 		"phone": "11111", "address": "adds", "city": "city", "province": "prov", "bank_name": "bank", "bank_number": "0bank",
-		"bank_holder": "banker", "sales_person": "sale", "visit_day": "monday", "note": "note test"}
+		"bank_holder": "banker", "sales_person_id": common.Encrypt(user.ID), "visit_day": "monday", "note": "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
@@ -434,7 +455,7 @@ func TestHandler_URLMappingPartnershipFailValidationTooMuchCharacter(t *testing.
 		"full_name": common.RandomStr(46), "email": common.RandomStr(46) + "@mail.com",
 		"phone": common.RandomStr(46) + "0000", "address": common.RandomStr(255) + "adds", "city": common.RandomStr(46) + "city",
 		"province": common.RandomStr(46) + "prov", "bank_name": common.RandomStr(46) + "bank", "bank_number": common.RandomStr(46) + "0bank",
-		"bank_holder": common.RandomStr(46) + "banker", "sales_person": common.RandomStr(46) + "sale", "visit_day": common.RandomStr(46) + "monday", "note": common.RandomStr(255) + "note test"}
+		"bank_holder": common.RandomStr(46) + "banker", "sales_person_id": common.RandomStr(46) + "sale", "visit_day": common.RandomStr(46) + "monday", "note": common.RandomStr(255) + "note test"}
 
 	ng := tester.New()
 	ng.SetHeader(tester.H{"Authorization": token})
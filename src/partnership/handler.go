@@ -86,7 +86,7 @@ func (h *Handler) update(c echo.Context) (e error) {
 				r.PartnerOld = u
 				if e = ctx.Bind(&r); e == nil {
 					m := r.Transform()
-					if m.Save("order_rule", "max_plafon", "full_name", "email", "phone", "address", "city", "province", "bank_name", "bank_holder", "bank_number", "sales_person", "visit_day", "note", "updated_by", "updated_at"); e == nil {
+					if m.Save("order_rule", "max_plafon", "full_name", "email", "phone", "address", "city", "province", "bank_name", "bank_holder", "bank_number", "sales_person_id", "visit_day", "npwp", "note", "updated_by", "updated_at"); e == nil {
 						ctx.Data(m)
 					}
 				}
@@ -32,8 +32,9 @@ type createRequest struct {
 	BankName        string  `json:"bank_name" valid:"lte:45"`
 	BankNumber      string  `json:"bank_number" valid:"lte:45"`
 	BankHolder      string  `json:"bank_holder" valid:"lte:45"`
-	SalesPerson     string  `json:"sales_person" valid:"lte:45"`
+	SalesPersonID   string  `json:"sales_person_id"`
 	VisitDay        string  `json:"visit_day" valid:"lte:45"`
+	Npwp            string  `json:"npwp" valid:"lte:20"`
 	Note            string  `json:"note" valid:"lte:255"`
 }
 
@@ -46,6 +47,10 @@ func (r *createRequest) Validate() *validation.Output {
 			o.Failure("max_plafon", "must be greater than 0")
 		}
 	}
+
+	if r.SalesPersonID != "" && auth.SalesPerson(r.SalesPersonID) == nil {
+		o.Failure("sales_person_id", "sales_person_id doesn't exist")
+	}
 	return o
 }
 
@@ -71,8 +76,9 @@ func (r *createRequest) Transform() *model.Partnership {
 		BankNumber:      r.BankNumber,
 		BankHolder:      r.BankHolder,
 		MaxPlafon:       r.MaxPlafon,
-		SalesPerson:     r.SalesPerson,
+		SalesPerson:     auth.SalesPerson(r.SalesPersonID),
 		VisitDay:        r.VisitDay,
+		Npwp:            r.Npwp,
 		Note:            r.Note,
 		IsArchived:      int8(0),
 		IsDeleted:       int8(0),
@@ -88,22 +94,23 @@ func (r *createRequest) Transform() *model.Partnership {
 // handler function should be bind this with context to matches incoming request
 // data keys to the defined json tag.
 type updateRequest struct {
-	PartnerOld  *model.Partnership
-	Session     *auth.SessionData
-	OrderRule   string  `json:"order_rule" valid:"required|in:none,one_bill,plafon"`
-	MaxPlafon   float64 `json:"max_plafon" valid:"range:0,9999999999999999999"`
-	FullName    string  `json:"full_name" valid:"required|lte:45"`
-	Email       string  `json:"email" valid:"lte:45"`
-	Phone       string  `json:"phone" valid:"lte:45"`
-	Address     string  `json:"address" valid:"lte:255"`
-	City        string  `json:"city" valid:"lte:45"`
-	Province    string  `json:"province" valid:"lte:45"`
-	BankName    string  `json:"bank_name" valid:"lte:45"`
-	BankNumber  string  `json:"bank_number" valid:"lte:45"`
-	BankHolder  string  `json:"bank_holder" valid:"lte:45"`
-	SalesPerson string  `json:"sales_person" valid:"lte:45"`
-	VisitDay    string  `json:"visit_day" valid:"lte:45"`
-	Note        string  `json:"note" valid:"lte:255"`
+	PartnerOld    *model.Partnership
+	Session       *auth.SessionData
+	OrderRule     string  `json:"order_rule" valid:"required|in:none,one_bill,plafon"`
+	MaxPlafon     float64 `json:"max_plafon" valid:"range:0,9999999999999999999"`
+	FullName      string  `json:"full_name" valid:"required|lte:45"`
+	Email         string  `json:"email" valid:"lte:45"`
+	Phone         string  `json:"phone" valid:"lte:45"`
+	Address       string  `json:"address" valid:"lte:255"`
+	City          string  `json:"city" valid:"lte:45"`
+	Province      string  `json:"province" valid:"lte:45"`
+	BankName      string  `json:"bank_name" valid:"lte:45"`
+	BankNumber    string  `json:"bank_number" valid:"lte:45"`
+	BankHolder    string  `json:"bank_holder" valid:"lte:45"`
+	SalesPersonID string  `json:"sales_person_id"`
+	VisitDay      string  `json:"visit_day" valid:"lte:45"`
+	Npwp          string  `json:"npwp" valid:"lte:20"`
+	Note          string  `json:"note" valid:"lte:255"`
 }
 
 // Validate implement validation.Requests interfaces.
@@ -115,6 +122,10 @@ func (r *updateRequest) Validate() *validation.Output {
 			o.Failure("max_plafon", "must be greater than 0")
 		}
 	}
+
+	if r.SalesPersonID != "" && auth.SalesPerson(r.SalesPersonID) == nil {
+		o.Failure("sales_person_id", "sales_person_id doesn't exist")
+	}
 	// tidak bisa diupdate kalau sudah di delete
 	if r.PartnerOld.IsDeleted == int8(1) {
 		o.Failure("is_deleted", "cannot be update")
@@ -142,8 +153,9 @@ func (r *updateRequest) Transform() *model.Partnership {
 	m.BankName = r.BankName
 	m.BankNumber = r.BankNumber
 	m.BankHolder = r.BankHolder
-	m.SalesPerson = r.SalesPerson
+	m.SalesPerson = auth.SalesPerson(r.SalesPersonID)
 	m.VisitDay = r.VisitDay
+	m.Npwp = r.Npwp
 	m.Note = r.Note
 	m.UpdatedBy = &model.User{ID: r.Session.User.ID}
 	m.UpdatedAt = time.Now()
@@ -11,7 +11,8 @@ import (
 )
 
 // CalculationTotalDebt ini untuk kalkulasi total hutang
-//digunakan untuk sales order
+//digunakan untuk sales order, saldo credit note customer dihitung pada total credit
+//dan saldo debit note supplier ditampilkan pada laporan hutang
 func CalculationTotalDebt(partnershipID int64) (err error) {
 	o := orm.NewOrm()
 	_, err = o.Raw("update partnership p set p.total_debt = (SELECT sum(so.total_charge-so.total_paid) as total_debts FROM sales_order so "+
@@ -38,11 +39,12 @@ func CalculationTotalExpenditure(partnershipID int64) (err error) {
 }
 
 // CalculationTotalCredit ini untuk kalkulasi total piutang
-//digunakan untuk purchase order
+//digunakan untuk purchase order, untuk customer berisi saldo credit note yang belum digunakan
 func CalculationTotalCredit(partnershipID int64) (err error) {
 	o := orm.NewOrm()
-	_, err = o.Raw("update partnership p set p.total_credit = (SELECT sum(po.total_charge) as total_credits FROM purchase_order po "+
-		"WHERE po.supplier_id = ? and po.document_status != 'cancelled' and po.is_deleted = 0) where p.id = ?;", partnershipID, partnershipID).Exec()
+	_, err = o.Raw("update partnership p set p.total_credit = COALESCE((SELECT sum(po.total_charge) as total_credits FROM purchase_order po "+
+		"WHERE po.supplier_id = ? and po.document_status != 'cancelled' and po.is_deleted = 0), 0) + "+
+		"COALESCE((SELECT sum(cn.balance) FROM credit_note cn WHERE cn.partnership_id = ? and cn.document_status = 'active'), 0) where p.id = ?;", partnershipID, partnershipID, partnershipID).Exec()
 	return err
 }
 
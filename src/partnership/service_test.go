@@ -646,3 +646,31 @@ func TestGetPartnershipByFieldSuccess(t *testing.T) {
 	assert.Equal(t, fakePartner.Code, m.Code)
 	assert.Equal(t, fakePartner.ID, m.ID)
 }
+
+func TestCalculationTotalWithCreditNote(t *testing.T) {
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.Save()
+
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.TotalCharge = float64(1500)
+	so.TotalPaid = float64(500)
+	so.DocumentStatus = "new"
+	so.IsDeleted = 0
+	so.Save()
+
+	cn := model.DummyCreditNote()
+	cn.Partnership = customer
+	cn.Balance = float64(2000)
+	cn.DocumentStatus = "active"
+	cn.Save()
+
+	assert.NoError(t, CalculationTotalDebt(customer.ID))
+	assert.NoError(t, CalculationTotalCredit(customer.ID))
+
+	// saldo credit note hanya dihitung pada total credit, total debt tetap sisa tagihan sales order
+	customer.Read()
+	assert.Equal(t, float64(1000), customer.TotalDebt)
+	assert.Equal(t, float64(2000), customer.TotalCredit)
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package payment_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("payment", "finance_revenue", "finance_expense", "journal_entry_line", "journal_entry")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	p := model.DummyPayment()
+	id := common.Encrypt(p.ID)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/payment", "GET", http.StatusOK},
+		{"/v1/payment/" + id, "GET", http.StatusOK},
+		{"/v1/payment/999999", "GET", http.StatusNotFound},
+		{"/v1/payment/partnership/" + common.Encrypt(p.Partnership.ID), "GET", http.StatusOK},
+		{"/v1/payment/partnership/999999", "GET", http.StatusNotFound},
+		{"/v1/payment", "POST", http.StatusUnprocessableEntity},
+		{"/v1/payment/" + id + "/allocate", "PUT", http.StatusUnprocessableEntity},
+		{"/v1/payment/999999/allocate", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDeleted = 0
+	customer.Save()
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = 5000
+	si.DocumentStatus = "active"
+	si.IsDeleted = 0
+	si.Save()
+
+	ba := model.DummyBankAccount()
+	ba.IsArchived = 0
+	ba.Save()
+
+	cid, sid, siID, baID := common.Encrypt(customer.ID), common.Encrypt(supplier.ID), common.Encrypt(si.ID), common.Encrypt(ba.ID)
+	date := time.Now().Format(time.RFC3339)
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		// payment tanpa alokasi menjadi saldo yang belum dialokasikan
+		{tester.D{"payment_type": "receipt", "partnership_id": cid, "recognition_date": date, "payment_method": "cash", "amount": 1000}, http.StatusOK},
+		{tester.D{"payment_type": "receipt", "partnership_id": cid, "bank_account_id": baID, "recognition_date": date, "payment_method": "transfer", "amount": 3000,
+			"allocations": []tester.D{{"ref_id": siID, "amount": 2000}}}, http.StatusOK},
+		// total alokasi melebihi nominal payment
+		{tester.D{"payment_type": "receipt", "partnership_id": cid, "bank_account_id": baID, "recognition_date": date, "payment_method": "transfer", "amount": 1000,
+			"allocations": []tester.D{{"ref_id": siID, "amount": 2000}}}, http.StatusUnprocessableEntity},
+		// alokasi melebihi sisa tagihan invoice
+		{tester.D{"payment_type": "receipt", "partnership_id": cid, "bank_account_id": baID, "recognition_date": date, "payment_method": "transfer", "amount": 9000,
+			"allocations": []tester.D{{"ref_id": siID, "amount": 4000}}}, http.StatusUnprocessableEntity},
+		// transfer tanpa rekening bank
+		{tester.D{"payment_type": "receipt", "partnership_id": cid, "recognition_date": date, "payment_method": "transfer", "amount": 1000}, http.StatusUnprocessableEntity},
+		// receipt harus dari customer
+		{tester.D{"payment_type": "receipt", "partnership_id": sid, "recognition_date": date, "payment_method": "cash", "amount": 1000}, http.StatusUnprocessableEntity},
+		// sales invoice bukan tagihan supplier
+		{tester.D{"payment_type": "disbursement", "partnership_id": sid, "recognition_date": date, "payment_method": "cash", "amount": 1000,
+			"allocations": []tester.D{{"ref_id": siID, "amount": 1000}}}, http.StatusUnprocessableEntity},
+		{tester.D{"payment_type": "receipt", "partnership_id": cid, "recognition_date": date, "payment_method": "cash", "amount": 0}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/payment").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	si.Read()
+	assert.Equal(t, float64(2000), si.TotalPaid)
+}
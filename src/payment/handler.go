@@ -0,0 +1,121 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package payment
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for payment.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("payment_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("payment_read"))
+	r.GET("/partnership/:id", h.balance, auth.CheckPrivilege("payment_read"))
+	r.POST("", h.create, auth.CheckPrivilege("payment_manage"))
+	r.PUT("/:id/allocate", h.allocate, auth.CheckPrivilege("payment_manage"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.Payment
+	if data, total, e = GetPayments(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to handle get http method with id.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.Payment
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowPayment("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// balance endpoint untuk melihat saldo payment partnership yang belum dialokasikan.
+func (h *Handler) balance(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var b *Balance
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		p := &model.Partnership{ID: id}
+		if e = p.Read(); e == nil {
+			if b, e = GetPartnershipBalance(p); e == nil {
+				ctx.Data(b)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk mencatat penerimaan dari customer atau pembayaran ke supplier
+// beserta alokasi nya ke beberapa invoice.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r createRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = CreatePayment(m); e == nil {
+				m, _ = ShowPayment("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// allocate endpoint untuk mengalokasikan saldo payment yang belum dialokasikan ke invoice.
+func (h *Handler) allocate(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r allocateRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.Payment, e = ShowPayment("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					revenues, expenses := r.Transform()
+					if e = Allocate(r.Payment, revenues, expenses); e == nil {
+						m, _ := ShowPayment("id", id)
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
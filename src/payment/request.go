@@ -0,0 +1,251 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package payment
+
+import (
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/purchase_invoice"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/cuxs/validation"
+)
+
+// allocationRequest data alokasi payment ke sales invoice atau purchase invoice.
+type allocationRequest struct {
+	RefID  string  `json:"ref_id" valid:"required"`
+	Amount float64 `json:"amount" valid:"required|gt:0"`
+
+	SalesInvoice    *model.SalesInvoice    `json:"-"`
+	PurchaseInvoice *model.PurchaseInvoice `json:"-"`
+}
+
+// createRequest data struct that stored request data when requesting an create payment process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	PaymentType     string               `json:"payment_type" valid:"required|in:receipt,disbursement"`
+	PartnershipID   string               `json:"partnership_id" valid:"required"`
+	BankAccountID   string               `json:"bank_account_id"`
+	RecognitionDate time.Time            `json:"recognition_date" valid:"required"`
+	PaymentMethod   string               `json:"payment_method" valid:"required|in:cash,transfer,giro"`
+	Reference       string               `json:"reference"`
+	Amount          float64              `json:"amount" valid:"required|gt:0"`
+	Note            string               `json:"note"`
+	Allocations     []*allocationRequest `json:"allocations"`
+	Session         *auth.SessionData    `json:"-"`
+	Partnership     *model.Partnership   `json:"-"`
+	BankAccount     *model.BankAccount   `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if id, e := common.Decrypt(r.PartnershipID); e != nil {
+		o.Failure("partnership_id", "partnership id cannot be decrypt")
+	} else {
+		r.Partnership = &model.Partnership{ID: id}
+		if e = r.Partnership.Read(); e != nil || r.Partnership.IsDeleted == int8(1) {
+			o.Failure("partnership_id", "partnership doesn't exist")
+			r.Partnership = nil
+		} else if r.PaymentType == "receipt" && r.Partnership.PartnershipType != "customer" {
+			o.Failure("partnership_id", "payment receipt must be from customer")
+		} else if r.PaymentType == "disbursement" && r.Partnership.PartnershipType != "supplier" {
+			o.Failure("partnership_id", "payment disbursement must be to supplier")
+		}
+	}
+
+	if r.PaymentMethod == "cash" {
+		if r.BankAccountID != "" {
+			o.Failure("bank_account_id", "bank account can't be inputted if payment method is cash")
+		}
+	} else if r.BankAccountID == "" {
+		o.Failure("bank_account_id", "bank account is required")
+	} else if id, e := common.Decrypt(r.BankAccountID); e != nil {
+		o.Failure("bank_account_id", "bank account id cannot be decrypt")
+	} else {
+		r.BankAccount = &model.BankAccount{ID: id}
+		if e = r.BankAccount.Read(); e != nil || r.BankAccount.IsArchived == int8(1) || r.BankAccount.AccountType != "bank" {
+			o.Failure("bank_account_id", "bank account is already archived or does not exists")
+		}
+	}
+
+	if r.Partnership != nil {
+		validAllocations(o, r.PaymentType, r.Partnership, r.Allocations, r.Amount)
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.Payment {
+	m := &model.Payment{
+		PaymentType:     r.PaymentType,
+		Partnership:     r.Partnership,
+		BankAccount:     r.BankAccount,
+		RecognitionDate: r.RecognitionDate,
+		PaymentMethod:   r.PaymentMethod,
+		Reference:       r.Reference,
+		Amount:          r.Amount,
+		Note:            r.Note,
+		CreatedBy:       r.Session.User,
+	}
+
+	m.FinanceRevenues, m.FinanceExpenses = transformAllocations(m, r.Allocations, r.RecognitionDate, r.Session.User)
+
+	return m
+}
+
+// allocateRequest data struct that stored request data when requesting an allocate
+// unapplied balance payment ke invoice.
+type allocateRequest struct {
+	RecognitionDate time.Time            `json:"recognition_date" valid:"required"`
+	Allocations     []*allocationRequest `json:"allocations" valid:"required"`
+	Session         *auth.SessionData    `json:"-"`
+	Payment         *model.Payment       `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *allocateRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.Payment.DocumentStatus == "applied" {
+		o.Failure("document_status", "payment is already fully applied")
+	} else if r.RecognitionDate.Format("2006-01-02") < r.Payment.RecognitionDate.Format("2006-01-02") {
+		o.Failure("recognition_date", "recognition_date can't be before payment recognition date")
+	} else {
+		validAllocations(o, r.Payment.PaymentType, r.Payment.Partnership, r.Allocations, r.Payment.UnappliedAmount)
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *allocateRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into finance revenue atau finance expense alokasi payment.
+func (r *allocateRequest) Transform() ([]*model.FinanceRevenue, []*model.FinanceExpense) {
+	r.Payment.UpdatedBy = r.Session.User
+	r.Payment.UpdatedAt = time.Now()
+
+	return transformAllocations(r.Payment, r.Allocations, r.RecognitionDate, r.Session.User)
+}
+
+// validAllocations memastikan invoice yang dialokasikan milik partnership payment, belum lunas,
+// nominal alokasi tidak melebihi sisa tagihan invoice dan total alokasi tidak melebihi saldo payment.
+func validAllocations(o *validation.Output, paymentType string, p *model.Partnership, allocations []*allocationRequest, available float64) {
+	var total float64
+	refs := make(map[int64]bool)
+	for i, a := range allocations {
+		key := fmt.Sprintf("allocations.%d.ref_id", i)
+		total += a.Amount
+
+		id, e := common.Decrypt(a.RefID)
+		if e != nil {
+			o.Failure(key, "ref id cannot be decrypt")
+			continue
+		}
+
+		if refs[id] {
+			o.Failure(key, "invoice is duplicated")
+			continue
+		}
+		refs[id] = true
+
+		var outstanding float64
+		if paymentType == "receipt" {
+			a.SalesInvoice = new(model.SalesInvoice)
+			if e = orm.NewOrm().QueryTable(a.SalesInvoice).Filter("id", id).Filter("is_deleted", 0).RelatedSel(1).Limit(1).One(a.SalesInvoice); e != nil {
+				o.Failure(key, "sales invoice doesn't exist")
+				continue
+			}
+
+			if a.SalesInvoice.SalesOrder.Customer.ID != p.ID {
+				o.Failure(key, "sales invoice is not belong to partnership")
+				continue
+			}
+
+			outstanding = OutstandingSalesInvoice(a.SalesInvoice)
+		} else {
+			a.PurchaseInvoice = new(model.PurchaseInvoice)
+			if e = orm.NewOrm().QueryTable(a.PurchaseInvoice).Filter("id", id).Filter("is_deleted", 0).RelatedSel(1).Limit(1).One(a.PurchaseInvoice); e != nil {
+				o.Failure(key, "purchase invoice doesn't exist")
+				continue
+			}
+
+			if a.PurchaseInvoice.PurchaseOrder.Supplier.ID != p.ID {
+				o.Failure(key, "purchase invoice is not belong to partnership")
+				continue
+			}
+
+			// pembayaran purchase invoice ditahan sampai seluruh mismatch three-way match diresolve
+			if purchaseInvoice.HasOpenMismatch(a.PurchaseInvoice.ID) {
+				o.Failure(key, "purchase invoice has unresolved three-way match mismatch")
+				continue
+			}
+
+			outstanding = OutstandingPurchaseInvoice(a.PurchaseInvoice)
+		}
+
+		if outstanding <= 0 {
+			o.Failure(key, "invoice already paid")
+		} else if a.Amount > outstanding {
+			o.Failure(fmt.Sprintf("allocations.%d.amount", i), "amount is greater than invoice outstanding")
+		}
+	}
+
+	if total > available {
+		o.Failure("allocations", "total allocation is greater than unapplied amount of payment")
+	}
+}
+
+// transformAllocations membuat finance revenue untuk payment receipt atau finance expense
+// untuk payment disbursement dari setiap alokasi, status nya akan di clear saat dialokasikan.
+func transformAllocations(m *model.Payment, allocations []*allocationRequest, date time.Time, user *model.User) (revenues []*model.FinanceRevenue, expenses []*model.FinanceExpense) {
+	for _, a := range allocations {
+		if m.PaymentType == "receipt" {
+			revenues = append(revenues, &model.FinanceRevenue{
+				RefType:         "sales_invoice",
+				RefID:           uint64(a.SalesInvoice.ID),
+				RecognitionDate: date,
+				Amount:          a.Amount,
+				PaymentMethod:   m.PaymentMethod,
+				BankNumber:      m.Reference,
+				DocumentStatus:  "uncleared",
+				CreatedBy:       user,
+				CreatedAt:       time.Now(),
+			})
+		} else {
+			expenses = append(expenses, &model.FinanceExpense{
+				RefType:         "purchase_invoice",
+				RefID:           uint64(a.PurchaseInvoice.ID),
+				RecognitionDate: date,
+				Amount:          a.Amount,
+				PaymentMethod:   m.PaymentMethod,
+				BankNumber:      m.Reference,
+				DocumentStatus:  "uncleared",
+				CreatedBy:       user,
+				CreatedAt:       time.Now(),
+			})
+		}
+	}
+
+	return
+}
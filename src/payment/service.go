@@ -0,0 +1,174 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package payment
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/finance_expense"
+	"git.qasico.com/mj/api/src/finance_revenue"
+	"git.qasico.com/mj/api/src/general_ledger"
+	"git.qasico.com/mj/api/src/purchase"
+	"git.qasico.com/mj/api/src/sales"
+	"git.qasico.com/mj/api/src/sales_invoice"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// Balance saldo payment partnership yang belum dialokasikan ke invoice.
+type Balance struct {
+	Partnership *model.Partnership `json:"partnership"`
+	PaymentType string             `json:"payment_type"`
+	Balance     float64            `json:"balance"`
+	Payments    []*model.Payment   `json:"payments"`
+}
+
+// GetPayments get all data payment that matched with query request parameters.
+// returning slices of payment, total data without limit and error.
+func GetPayments(rq *orm.RequestQuery) (m *[]model.Payment, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.Payment))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.Payment
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowPayment find a single data payment using field and value condition,
+// beserta finance revenue atau finance expense hasil alokasi payment.
+func ShowPayment(field string, values ...interface{}) (*model.Payment, error) {
+	m := new(model.Payment)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	if m.PaymentType == "receipt" {
+		o.Raw("SELECT * FROM finance_revenue WHERE payment_id = ? AND is_deleted = 0 ORDER BY id", m.ID).QueryRows(&m.FinanceRevenues)
+	} else {
+		o.Raw("SELECT * FROM finance_expense WHERE payment_id = ? AND is_deleted = 0 ORDER BY id", m.ID).QueryRows(&m.FinanceExpenses)
+	}
+
+	return m, nil
+}
+
+// GetPartnershipBalance mengambil saldo payment partnership yang masih bisa dialokasikan ke invoice.
+func GetPartnershipBalance(p *model.Partnership) (b *Balance, e error) {
+	b = &Balance{Partnership: p, PaymentType: "receipt"}
+	if p.PartnershipType == "supplier" {
+		b.PaymentType = "disbursement"
+	}
+
+	if _, e = orm.NewOrm().QueryTable(new(model.Payment)).Filter("partnership_id", p.ID).Filter("payment_type", b.PaymentType).
+		Exclude("document_status", "applied").OrderBy("id").All(&b.Payments); e == nil {
+		for _, pm := range b.Payments {
+			b.Balance += pm.UnappliedAmount
+		}
+	}
+
+	return
+}
+
+// CreatePayment menyimpan payment baru beserta jurnal uang muka nya,
+// kemudian mengalokasikan payment ke invoice yang dipilih.
+func CreatePayment(m *model.Payment) (e error) {
+	if m.Code, e = util.CodeGen("code_payment", "payment"); e != nil {
+		return e
+	}
+
+	revenues, expenses := m.FinanceRevenues, m.FinanceExpenses
+	m.UnappliedAmount = m.Amount
+	m.DocumentStatus = "unapplied"
+	m.CreatedAt = time.Now()
+	if e = m.Save(); e == nil {
+		generalLedger.AutoPost("payment", m.ID)
+		e = Allocate(m, revenues, expenses)
+	}
+
+	return
+}
+
+// Allocate mengalokasikan saldo payment ke invoice, setiap alokasi disimpan sebagai
+// finance revenue atau finance expense yang langsung cleared sehingga total paid invoice,
+// status sales order atau purchase order dan saldo partnership ikut diperbarui.
+func Allocate(m *model.Payment, revenues []*model.FinanceRevenue, expenses []*model.FinanceExpense) (e error) {
+	for _, fr := range revenues {
+		fr.Payment = m
+		fr.Note = m.Code
+		if e = fr.Save(); e != nil {
+			return e
+		}
+
+		salesInvoice.SumTotalRevenuedSalesInvoice(int64(fr.RefID))
+
+		if e = financeRevenue.ApproveRevenue(fr); e != nil {
+			return e
+		}
+	}
+
+	for _, fe := range expenses {
+		fe.Payment = m
+		fe.Note = m.Code
+		if e = fe.Save(); e != nil {
+			return e
+		}
+
+		if e = financeExpense.ApproveExpense(fe); e != nil {
+			return e
+		}
+	}
+
+	return CalculatePayment(m)
+}
+
+// CalculatePayment menghitung ulang jumlah payment yang sudah dialokasikan,
+// payment yang saldonya habis berubah status menjadi applied.
+func CalculatePayment(m *model.Payment) (e error) {
+	table := "finance_revenue"
+	if m.PaymentType == "disbursement" {
+		table = "finance_expense"
+	}
+
+	var allocated float64
+	if e = orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM "+table+" WHERE payment_id = ? AND is_deleted = 0", m.ID).QueryRow(&allocated); e != nil {
+		return e
+	}
+
+	m.AllocatedAmount = allocated
+	m.UnappliedAmount = m.Amount - allocated
+	if allocated == 0 {
+		m.DocumentStatus = "unapplied"
+	} else if m.UnappliedAmount > 0 {
+		m.DocumentStatus = "partial"
+	} else {
+		m.DocumentStatus = "applied"
+	}
+
+	return m.Save("AllocatedAmount", "UnappliedAmount", "DocumentStatus", "UpdatedBy", "UpdatedAt")
+}
+
+// OutstandingSalesInvoice sisa tagihan sales invoice, dihitung dengan dasar yang sama
+// dengan total paid sales invoice.
+func OutstandingSalesInvoice(si *model.SalesInvoice) float64 {
+	return si.TotalAmount - sales.PaidSalesInvoice(si)
+}
+
+// OutstandingPurchaseInvoice sisa tagihan purchase invoice, dihitung dengan dasar yang sama
+// dengan total paid purchase invoice.
+func OutstandingPurchaseInvoice(pi *model.PurchaseInvoice) float64 {
+	return pi.TotalAmount - purchase.PaidPurchaseInvoice(pi)
+}
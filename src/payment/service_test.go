@@ -0,0 +1,247 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package payment
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/purchase"
+	"git.qasico.com/mj/api/src/sales"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/cuxs/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func dummyPartnership(partnershipType string) *model.Partnership {
+	p := model.DummyPartnership()
+	p.PartnershipType = partnershipType
+	p.IsDeleted = 0
+	p.Save()
+
+	return p
+}
+
+func dummySalesInvoice(customer *model.Partnership, amount float64) *model.SalesInvoice {
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.TotalCharge = amount
+	so.TotalPaid = 0
+	so.Save()
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = amount
+	si.TotalPaid = 0
+	si.DocumentStatus = "active"
+	si.IsDeleted = 0
+	si.Save()
+
+	return si
+}
+
+func dummyPurchaseInvoice(supplier *model.Partnership, amount float64) *model.PurchaseInvoice {
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.TotalCharge = amount
+	po.TotalPaid = 0
+	po.Save()
+
+	pi := model.DummyPurchaseInvoice()
+	pi.PurchaseOrder = po
+	pi.TotalAmount = amount
+	pi.TotalPaid = 0
+	pi.DocumentStatus = "active"
+	pi.IsDeleted = 0
+	pi.Save()
+
+	return pi
+}
+
+func TestGetPaymentsNoData(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("DELETE FROM payment").Exec()
+	rq := orm.RequestQuery{}
+
+	m, total, e := GetPayments(&rq)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, m)
+	assert.NoError(t, e)
+}
+
+func TestShowPayment(t *testing.T) {
+	_, e := ShowPayment("id", 999999)
+	assert.Error(t, e, "Response should be error, beacuse there are no data yet.")
+
+	p := model.DummyPayment()
+	pd, e := ShowPayment("id", p.ID)
+	assert.NoError(t, e, "Data should be exists.")
+	assert.Equal(t, p.ID, pd.ID, "ID Response should be a same.")
+}
+
+func TestValidAllocations(t *testing.T) {
+	customer := dummyPartnership("customer")
+	si := dummySalesInvoice(customer, 1000)
+	other := dummySalesInvoice(dummyPartnership("customer"), 1000)
+
+	var data = []struct {
+		allocations []*allocationRequest
+		available   float64
+		expected    bool
+	}{
+		{[]*allocationRequest{{RefID: common.Encrypt(si.ID), Amount: 600}}, 1000, true},
+		// total alokasi melebihi saldo payment
+		{[]*allocationRequest{{RefID: common.Encrypt(si.ID), Amount: 600}}, 500, false},
+		// alokasi melebihi sisa tagihan invoice
+		{[]*allocationRequest{{RefID: common.Encrypt(si.ID), Amount: 1500}}, 2000, false},
+		// invoice yang sama dialokasikan dua kali
+		{[]*allocationRequest{{RefID: common.Encrypt(si.ID), Amount: 100}, {RefID: common.Encrypt(si.ID), Amount: 100}}, 1000, false},
+		// invoice milik customer lain
+		{[]*allocationRequest{{RefID: common.Encrypt(other.ID), Amount: 100}}, 1000, false},
+		{[]*allocationRequest{{RefID: "999999", Amount: 100}}, 1000, false},
+	}
+
+	for _, tes := range data {
+		o := &validation.Output{Valid: true}
+		validAllocations(o, "receipt", customer, tes.allocations, tes.available)
+		assert.Equal(t, tes.expected, o.Valid)
+	}
+}
+
+func TestCreatePaymentReceipt(t *testing.T) {
+	customer := dummyPartnership("customer")
+	si1 := dummySalesInvoice(customer, 1000)
+	si2 := dummySalesInvoice(customer, 2000)
+
+	m := &model.Payment{
+		PaymentType:     "receipt",
+		Partnership:     customer,
+		RecognitionDate: time.Now(),
+		PaymentMethod:   "cash",
+		Amount:          2500,
+		CreatedBy:       model.DummyUser(),
+	}
+	m.FinanceRevenues, _ = transformAllocations(m, []*allocationRequest{
+		{SalesInvoice: si1, Amount: 1000},
+		{SalesInvoice: si2, Amount: 1000},
+	}, m.RecognitionDate, m.CreatedBy)
+
+	assert.NoError(t, CreatePayment(m))
+	assert.NotEqual(t, "", m.Code)
+	assert.Equal(t, "partial", m.DocumentStatus)
+	assert.Equal(t, float64(2000), m.AllocatedAmount)
+	assert.Equal(t, float64(500), m.UnappliedAmount)
+
+	// invoice yang dialokasikan penuh menjadi finished
+	si1.Read()
+	assert.Equal(t, float64(1000), si1.TotalPaid)
+	assert.Equal(t, "finished", si1.DocumentStatus)
+	si2.Read()
+	assert.Equal(t, float64(1000), si2.TotalPaid)
+	assert.Equal(t, float64(1000), OutstandingSalesInvoice(si2))
+
+	b, e := GetPartnershipBalance(customer)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(500), b.Balance)
+
+	// sisa saldo payment dialokasikan kemudian
+	revenues, _ := transformAllocations(m, []*allocationRequest{{SalesInvoice: si2, Amount: 500}}, time.Now(), m.CreatedBy)
+	assert.NoError(t, Allocate(m, revenues, nil))
+	assert.Equal(t, "applied", m.DocumentStatus)
+	assert.Equal(t, float64(0), m.UnappliedAmount)
+
+	si2.Read()
+	assert.Equal(t, float64(1500), si2.TotalPaid)
+
+	m, _ = ShowPayment("id", m.ID)
+	assert.Equal(t, 3, len(m.FinanceRevenues))
+	for _, fr := range m.FinanceRevenues {
+		assert.Equal(t, "cleared", fr.DocumentStatus)
+		assert.Equal(t, m.Code, fr.Note)
+	}
+}
+
+func TestCreatePaymentDisbursement(t *testing.T) {
+	supplier := dummyPartnership("supplier")
+	pi1 := dummyPurchaseInvoice(supplier, 1000)
+	pi2 := dummyPurchaseInvoice(supplier, 3000)
+
+	m := &model.Payment{
+		PaymentType:     "disbursement",
+		Partnership:     supplier,
+		BankAccount:     model.DummyBankAccount(),
+		RecognitionDate: time.Now(),
+		PaymentMethod:   "transfer",
+		Amount:          4000,
+		CreatedBy:       model.DummyUser(),
+	}
+	_, m.FinanceExpenses = transformAllocations(m, []*allocationRequest{
+		{PurchaseInvoice: pi1, Amount: 1000},
+		{PurchaseInvoice: pi2, Amount: 3000},
+	}, m.RecognitionDate, m.CreatedBy)
+
+	assert.NoError(t, CreatePayment(m))
+	assert.Equal(t, "applied", m.DocumentStatus)
+	assert.Equal(t, float64(4000), m.AllocatedAmount)
+
+	pi1.Read()
+	assert.Equal(t, float64(1000), pi1.TotalPaid)
+	assert.Equal(t, "finished", pi1.DocumentStatus)
+	pi2.Read()
+	assert.Equal(t, float64(0), OutstandingPurchaseInvoice(pi2))
+}
+
+func TestOutstandingInvoice(t *testing.T) {
+	customer := dummyPartnership("customer")
+	si := dummySalesInvoice(customer, 1000)
+
+	for status, amount := range map[string]float64{"cleared": 200, "uncleared": 400} {
+		fr := model.DummyFinanceRevenue()
+		fr.RefType = "sales_invoice"
+		fr.RefID = uint64(si.ID)
+		fr.DocumentStatus = status
+		fr.Amount = amount
+		fr.IsDeleted = 0
+		fr.Save()
+	}
+
+	iri := model.DummyInvoiceReceiptItem()
+	iri.InvoiceReceipt.DocumentStatus = "active"
+	iri.InvoiceReceipt.Save("DocumentStatus")
+	iri.SalesInvoice = si
+	iri.Subtotal = 300
+	iri.Save()
+
+	// revenue uncleared dan invoice receipt yang belum finished tidak dihitung, sama seperti total paid
+	assert.NoError(t, sales.CalculateTotalPaidSI(si))
+	si.Read()
+	assert.Equal(t, float64(200), si.TotalPaid)
+	assert.Equal(t, si.TotalAmount-si.TotalPaid, OutstandingSalesInvoice(si))
+
+	o := &validation.Output{Valid: true}
+	validAllocations(o, "receipt", customer, []*allocationRequest{{RefID: common.Encrypt(si.ID), Amount: 800}}, 1000)
+	assert.True(t, o.Valid)
+
+	supplier := dummyPartnership("supplier")
+	pi := dummyPurchaseInvoice(supplier, 1000)
+
+	for status, amount := range map[string]float64{"cleared": 100, "uncleared": 500} {
+		fe := model.DummyFinanceExpense()
+		fe.RefType = "purchase_invoice"
+		fe.RefID = uint64(pi.ID)
+		fe.DocumentStatus = status
+		fe.Amount = amount
+		fe.IsDeleted = 0
+		fe.Save()
+	}
+
+	pi, e := purchase.CalculateTotalPaidPI(pi)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(100), pi.TotalPaid)
+	assert.Equal(t, pi.TotalAmount-pi.TotalPaid, OutstandingPurchaseInvoice(pi))
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pos_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("pos_shift", "pos_transaction", "pos_payment")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	shift := model.DummyPosShift()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/pos/shift", "GET", http.StatusOK},
+		{"/v1/pos/shift/" + common.Encrypt(shift.ID), "GET", http.StatusOK},
+		{"/v1/pos/shift/" + common.Encrypt(shift.ID) + "/report", "GET", http.StatusOK},
+		{"/v1/pos/shift/999999", "GET", http.StatusNotFound},
+		{"/v1/pos/shift/current", "GET", http.StatusNotFound},
+		{"/v1/pos/scan/not-a-barcode", "GET", http.StatusNotFound},
+		{"/v1/pos/transaction/999999/receipt", "GET", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_Shift(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+
+	ng.POST("/v1/pos/shift/open").
+		SetJSON(tester.D{"opening_float": 200000}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		})
+
+	// shift sebelumnya belum ditutup
+	ng.POST("/v1/pos/shift/open").
+		SetJSON(tester.D{"opening_float": 200000}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+
+	shift := &model.PosShift{Cashier: user, Status: "open"}
+	assert.NoError(t, shift.Read("Cashier", "Status"))
+	assert.Equal(t, float64(200000), shift.OpeningFloat)
+
+	ng.PUT("/v1/pos/shift/"+common.Encrypt(shift.ID)+"/close").
+		SetJSON(tester.D{"counted_cash": 190000}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		})
+
+	shift.Read()
+	assert.Equal(t, "closed", shift.Status)
+	assert.Equal(t, float64(-10000), shift.Difference)
+
+	// shift sudah ditutup
+	ng.PUT("/v1/pos/shift/"+common.Encrypt(shift.ID)+"/close").
+		SetJSON(tester.D{"counted_cash": 190000}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+}
+
+func TestPosIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		// laporan shift hanya untuk admin dan owner
+		{"/v1/pos/shift", "GET", http.StatusUnauthorized},
+		{"/v1/pos/shift/999999/report", "GET", http.StatusUnauthorized},
+		{"/v1/pos/shift/current", "GET", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
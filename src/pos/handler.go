@@ -0,0 +1,203 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pos
+
+import (
+	"net/http"
+	"strconv"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/printing"
+	"git.qasico.com/mj/api/src/sales"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for point of sale.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/shift", h.getShift, auth.CheckPrivilege("pos_report"))
+	r.GET("/shift/current", h.currentShift, auth.CheckPrivilege("pos_shift"))
+	r.GET("/shift/:id", h.showShift, auth.CheckPrivilege("pos_report"))
+	r.GET("/shift/:id/report", h.report, auth.CheckPrivilege("pos_report"))
+	r.POST("/shift/open", h.openShift, auth.CheckPrivilege("pos_shift"))
+	r.PUT("/shift/:id/close", h.closeShift, auth.CheckPrivilege("pos_shift"))
+	r.GET("/scan/:barcode", h.scan, auth.CheckPrivilege("pos_checkout"))
+	r.POST("/checkout", h.checkout, auth.CheckPrivilege("pos_checkout"))
+	r.GET("/transaction/:id/receipt", h.receipt, auth.CheckPrivilege("pos_checkout"))
+}
+
+// getShift endpoint untuk mengambil semua shift kasir.
+func (h *Handler) getShift(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.PosShift
+	if data, total, e = GetShifts(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// currentShift endpoint untuk mengambil shift kasir yang sedang open.
+func (h *Handler) currentShift(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var sd *auth.SessionData
+	var m *model.PosShift
+	if sd, e = auth.UserSession(ctx); e == nil {
+		if m, e = CurrentShift(sd.User.ID); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// showShift endpoint untuk mengambil detail shift kasir.
+func (h *Handler) showShift(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.PosShift
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowShift("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// report endpoint untuk laporan rekonsiliasi cash shift kasir.
+func (h *Handler) report(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.PosShift
+	var r *ShiftReport
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowShift("id", id); e == nil {
+			if r, e = GetShiftReport(m); e == nil {
+				ctx.Data(r)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// openShift endpoint untuk membuka shift kasir dengan modal awal cash.
+func (h *Handler) openShift(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r openShiftRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// closeShift endpoint untuk menutup shift kasir dengan cash yang dihitung kasir.
+func (h *Handler) closeShift(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r closeShiftRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.Shift, e = ShowShift("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					var rp *ShiftReport
+					if e = CloseShift(r.Shift, r.CountedCash, r.Note); e == nil {
+						if rp, e = GetShiftReport(r.Shift); e == nil {
+							ctx.Data(rp)
+						}
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// scan endpoint untuk mengambil item dan harga jual dari barcode.
+func (h *Handler) scan(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var l *sales.POSLine
+	if l, e = ScanItem(ctx.Param("barcode")); e == nil {
+		ctx.Data(l)
+	} else {
+		e = echo.ErrNotFound
+	}
+
+	return ctx.Serve(e)
+}
+
+// checkout endpoint untuk menyimpan transaksi kasir, hasilnya berupa transaksi beserta struk.
+func (h *Handler) checkout(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r checkoutRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			var m *model.PosTransaction
+			if m, e = Checkout(&r); e == nil {
+				if m, e = ShowTransaction("id", m.ID); e == nil {
+					ctx.Data(GetReceipt(m))
+				}
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// receipt endpoint untuk mencetak ulang struk transaksi kasir,
+// parameter format=escpos menghasilkan perintah printer thermal sesuai paper (58 / 80).
+func (h *Handler) receipt(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.PosTransaction
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowTransaction("id", id); e == nil {
+			r := GetReceipt(m)
+			if ctx.QueryParam("format") == "escpos" {
+				paper, _ := strconv.Atoi(ctx.QueryParam("paper"))
+				return ctx.Blob(http.StatusOK, "application/octet-stream", printing.ESCPOSText(r.Format(printing.PaperColumns(paper)), paper))
+			}
+
+			ctx.Data(r)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,164 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+)
+
+// Receipt data struk transaksi kasir.
+type Receipt struct {
+	TransactionID string           `json:"transaction_id"`
+	CompanyName   string           `json:"company_name"`
+	Address       string           `json:"address"`
+	Code          string           `json:"code"`
+	ShiftCode     string           `json:"shift_code"`
+	Cashier       string           `json:"cashier"`
+	Date          time.Time        `json:"date"`
+	Lines         []*ReceiptLine   `json:"lines"`
+	TotalCharge   float64          `json:"total_charge"`
+	Payments      []*ReceiptTender `json:"payments"`
+	ChangeAmount  float64          `json:"change_amount"`
+	Text          string           `json:"text"`
+}
+
+// ReceiptLine item yang dibeli pada struk.
+type ReceiptLine struct {
+	Name      string  `json:"name"`
+	Quantity  float32 `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Discount  float32 `json:"discount"`
+	Subtotal  float64 `json:"subtotal"`
+}
+
+// ReceiptTender pembayaran yang diterima pada struk.
+type ReceiptTender struct {
+	PaymentMethod string  `json:"payment_method"`
+	Amount        float64 `json:"amount"`
+}
+
+// receiptWidth jumlah karakter per baris struk untuk kertas 58mm.
+const receiptWidth = 32
+
+// GetReceipt membuat struk dari transaksi kasir, nama dan alamat toko diambil dari application setting.
+func GetReceipt(t *model.PosTransaction) *Receipt {
+	r := &Receipt{
+		TransactionID: common.Encrypt(t.ID),
+		Code:          t.SalesOrder.Code,
+		Date:          t.CreatedAt,
+		TotalCharge:   t.TotalCharge,
+		ChangeAmount:  t.ChangeAmount,
+	}
+
+	if s, e := util.GetApplicationSetting("application_setting_name", "company_name"); e == nil {
+		r.CompanyName = s.Value
+	}
+
+	if s, e := util.GetApplicationSetting("application_setting_name", "address"); e == nil {
+		r.Address = s.Value
+	}
+
+	if t.PosShift != nil {
+		r.ShiftCode = t.PosShift.Code
+	}
+
+	if t.CreatedBy != nil {
+		r.Cashier = t.CreatedBy.FullName
+	}
+
+	for _, i := range t.SalesOrder.SalesOrderItems {
+		l := &ReceiptLine{Quantity: i.Quantity, UnitPrice: i.UnitPrice, Discount: i.Discount, Subtotal: i.Subtotal}
+		if iv := i.ItemVariant; iv != nil {
+			if iv.Item != nil {
+				l.Name = iv.Item.ItemName
+			}
+			l.Name = strings.TrimSpace(l.Name + " " + iv.VariantName)
+		}
+
+		r.Lines = append(r.Lines, l)
+	}
+
+	for _, p := range t.PosPayments {
+		r.Payments = append(r.Payments, &ReceiptTender{PaymentMethod: p.PaymentMethod, Amount: p.Amount})
+	}
+
+	r.Text = r.Format(receiptWidth)
+
+	return r
+}
+
+// Format menyusun struk dalam bentuk teks dengan lebar width karakter per baris.
+func (r *Receipt) Format(width int) string {
+	var b []string
+	line := strings.Repeat("-", width)
+
+	b = append(b, center(r.CompanyName, width), center(r.Address, width), line)
+	b = append(b, r.Code, r.Date.Format("02/01/2006 15:04")+" "+r.Cashier, line)
+
+	for _, l := range r.Lines {
+		b = append(b, truncate(l.Name, width))
+
+		qty := fmt.Sprintf("%v x %s", l.Quantity, money(l.UnitPrice))
+		if l.Discount > 0 {
+			qty += fmt.Sprintf(" -%v%%", l.Discount)
+		}
+		b = append(b, columns(qty, money(l.Subtotal), width))
+	}
+
+	b = append(b, line, columns("TOTAL", money(r.TotalCharge), width))
+	for _, p := range r.Payments {
+		b = append(b, columns(strings.ToUpper(strings.Replace(p.PaymentMethod, "_", " ", -1)), money(p.Amount), width))
+	}
+	b = append(b, columns("KEMBALI", money(r.ChangeAmount), width), line, center("Terima Kasih", width))
+
+	return strings.Join(b, "\n") + "\n"
+}
+
+// money format angka rupiah dengan pemisah ribuan titik.
+func money(v float64) string {
+	s := fmt.Sprintf("%.0f", v)
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "." + s[i:]
+	}
+
+	if neg {
+		return "-" + s
+	}
+
+	return s
+}
+
+func columns(left string, right string, width int) string {
+	space := width - len(left) - len(right)
+	if space < 1 {
+		left = truncate(left, width-len(right)-1)
+		space = 1
+	}
+
+	return left + strings.Repeat(" ", space) + right
+}
+
+func center(s string, width int) string {
+	s = truncate(s, width)
+	return strings.Repeat(" ", (width-len(s))/2) + s
+}
+
+func truncate(s string, width int) string {
+	if width > 0 && len(s) > width {
+		return s[:width]
+	}
+
+	return s
+}
@@ -0,0 +1,220 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/sales"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// openShiftRequest data struct that stored request data when requesting an open cashier shift process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type openShiftRequest struct {
+	OpeningFloat float64           `json:"opening_float" valid:"gte:0"`
+	Note         string            `json:"note"`
+	Session      *auth.SessionData `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *openShiftRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if _, e := CurrentShift(r.Session.User.ID); e == nil {
+		o.Failure("shift", "cashier still has an open shift, close it first")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *openShiftRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *openShiftRequest) Transform() *model.PosShift {
+	code, _ := util.CodeGen("code_pos_shift", "pos_shift")
+
+	return &model.PosShift{
+		Code:         code,
+		Cashier:      r.Session.User,
+		OpeningFloat: r.OpeningFloat,
+		Status:       "open",
+		Note:         r.Note,
+		OpenedAt:     time.Now(),
+	}
+}
+
+// closeShiftRequest data struct that stored request data when requesting an close cashier shift process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type closeShiftRequest struct {
+	CountedCash float64           `json:"counted_cash" valid:"gte:0"`
+	Note        string            `json:"note"`
+	Session     *auth.SessionData `json:"-"`
+	Shift       *model.PosShift   `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *closeShiftRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.Shift.Status != "open" {
+		o.Failure("status", "shift has been closed")
+	}
+
+	if r.Shift.Cashier == nil || r.Shift.Cashier.ID != r.Session.User.ID {
+		o.Failure("shift", "shift can only be closed by the cashier")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *closeShiftRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// checkoutRequest data struct that stored request data when requesting an point of sale checkout process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type checkoutRequest struct {
+	Items    []checkoutItem    `json:"items" valid:"required"`
+	Payments []checkoutPayment `json:"payments" valid:"required"`
+	Note     string            `json:"note"`
+	Session  *auth.SessionData `json:"-"`
+
+	Shift         *model.PosShift  `json:"-"`
+	Lines         []*sales.POSLine `json:"-"`
+	TotalCharge   float64          `json:"-"`
+	TotalTendered float64          `json:"-"`
+	ChangeAmount  float64          `json:"-"`
+}
+
+// checkoutItem item yang discan kasir, bisa menggunakan barcode atau item variant id.
+type checkoutItem struct {
+	Barcode       string  `json:"barcode"`
+	ItemVariantID string  `json:"item_variant_id"`
+	Quantity      float32 `json:"quantity" valid:"required|gt:0"`
+	Discount      float32 `json:"discount" valid:"gte:0|lte:100"`
+}
+
+// checkoutPayment pembayaran yang diterima kasir, pembayaran bisa lebih dari satu (split tender).
+type checkoutPayment struct {
+	PaymentMethod string  `json:"payment_method" valid:"required|in:cash,transfer,debit_card,credit_card"`
+	BankAccountID string  `json:"bank_account_id"`
+	Amount        float64 `json:"amount" valid:"required|gt:0"`
+
+	BankAccount *model.BankAccount `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *checkoutRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+	var e error
+
+	if r.Shift, e = CurrentShift(r.Session.User.ID); e != nil {
+		o.Failure("shift", "cashier doesn't have an open shift")
+	}
+
+	// item yang discan lebih dari sekali digabung menjadi satu line
+	lines := make(map[int64]*sales.POSLine)
+	for i, item := range r.Items {
+		var iv *model.ItemVariant
+		if iv, e = scanItem(item.Barcode, item.ItemVariantID); e != nil {
+			o.Failure(fmt.Sprintf("items.%d.barcode", i), "item is not found")
+			continue
+		}
+
+		if l, ok := lines[iv.ID]; ok {
+			if l.Discount != item.Discount {
+				o.Failure(fmt.Sprintf("items.%d.discount", i), "discount must be the same for the same item")
+			}
+			l.Quantity += item.Quantity
+			continue
+		}
+
+		l := &sales.POSLine{ItemVariant: iv, Quantity: item.Quantity, Discount: item.Discount}
+		if l.PricingType, l.UnitPrice, e = sales.POSUnitPrice(iv); e != nil {
+			o.Failure(fmt.Sprintf("items.%d.barcode", i), e.Error())
+			continue
+		}
+
+		lines[iv.ID] = l
+		r.Lines = append(r.Lines, l)
+	}
+
+	r.TotalCharge = 0
+	for _, l := range r.Lines {
+		if l.ItemVariant.AvailableStock-l.ItemVariant.CommitedStock < l.Quantity {
+			o.Failure("items."+common.Encrypt(l.ItemVariant.ID), "stock item is not enough to be sold")
+		}
+
+		l.Subtotal = sales.POSSubtotal(l)
+		r.TotalCharge += l.Subtotal
+	}
+
+	// diskon kasir tidak boleh melewati batas diskon usergroup kasir
+	if reasons := sales.POSApprovalReasons(r.Lines, r.Session.User); len(reasons) > 0 {
+		o.Failure("items", strings.Join(reasons, "; "))
+	}
+
+	var nonCash float64
+	r.TotalTendered = 0
+	for i := range r.Payments {
+		p := &r.Payments[i]
+		if p.BankAccountID != "" {
+			if id, e := common.Decrypt(p.BankAccountID); e == nil {
+				p.BankAccount = &model.BankAccount{ID: id}
+				if e = p.BankAccount.Read(); e != nil {
+					o.Failure(fmt.Sprintf("payments.%d.bank_account_id", i), "bank_account_id doesn't exist")
+				}
+			} else {
+				o.Failure(fmt.Sprintf("payments.%d.bank_account_id", i), "bank_account_id cannot be decrypt")
+			}
+		} else if p.PaymentMethod == "transfer" {
+			o.Failure(fmt.Sprintf("payments.%d.bank_account_id", i), "bank_account_id is required for transfer")
+		}
+
+		if p.PaymentMethod != "cash" {
+			nonCash += p.Amount
+		}
+		r.TotalTendered += p.Amount
+	}
+
+	// kembalian hanya bisa diberikan dari pembayaran cash
+	if nonCash > r.TotalCharge {
+		o.Failure("payments", "non cash payment exceeds the total charge")
+	}
+
+	if r.TotalTendered < r.TotalCharge {
+		o.Failure("payments", fmt.Sprintf("payment is not enough, total charge is %.0f", r.TotalCharge))
+	}
+
+	r.ChangeAmount = r.TotalTendered - r.TotalCharge
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *checkoutRequest) Messages() map[string]string {
+	return map[string]string{}
+}
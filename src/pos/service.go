@@ -0,0 +1,280 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pos
+
+import (
+	"errors"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/sales"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// ShiftReport laporan penutupan shift kasir, berisi rekap pembayaran per metode
+// dan selisih antara cash yang seharusnya ada dengan cash yang dihitung kasir.
+type ShiftReport struct {
+	Shift        *model.PosShift         `json:"shift"`
+	Payments     []*PaymentSummary       `json:"payments"`
+	Transactions []*model.PosTransaction `json:"transactions"`
+}
+
+// PaymentSummary rekap pembayaran shift per metode pembayaran dan rekening bank.
+type PaymentSummary struct {
+	PaymentMethod string             `json:"payment_method"`
+	BankAccount   *model.BankAccount `json:"bank_account,omitempty"`
+	Amount        float64            `json:"amount"`
+	Total         int                `json:"total"`
+}
+
+// GetShifts get all data pos_shift that matched with query request parameters.
+// returning slices of pos shift, total data without limit and error.
+func GetShifts(rq *orm.RequestQuery) (m *[]model.PosShift, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.PosShift))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.PosShift
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowShift untuk mengambil data shift kasir berdasarkan param
+func ShowShift(field string, values ...interface{}) (*model.PosShift, error) {
+	m := new(model.PosShift)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// CurrentShift mengambil shift kasir yang masih open.
+func CurrentShift(cashierID int64) (*model.PosShift, error) {
+	m := new(model.PosShift)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter("cashier_id", cashierID).Filter("status", "open").RelatedSel(1).OrderBy("-id").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// scanItem mencari item variant yang bisa dijual berdasarkan barcode atau item variant id.
+func scanItem(barcode string, itemVariantID string) (*model.ItemVariant, error) {
+	q := orm.NewOrm().QueryTable(new(model.ItemVariant)).Filter("is_deleted", 0).Filter("is_archived", 0)
+	if barcode != "" {
+		q = q.Filter("barcode", barcode)
+	} else if id, e := common.Decrypt(itemVariantID); e == nil {
+		q = q.Filter("id", id)
+	} else {
+		return nil, errors.New("barcode or item_variant_id is required")
+	}
+
+	m := new(model.ItemVariant)
+	if e := q.RelatedSel("Item").Limit(1).One(m); e != nil {
+		return nil, e
+	}
+
+	return m, nil
+}
+
+// ScanItem mengambil item variant dari barcode beserta harga jual untuk ditampilkan di layar kasir.
+func ScanItem(barcode string) (*sales.POSLine, error) {
+	iv, e := scanItem(barcode, "")
+	if e != nil {
+		return nil, e
+	}
+
+	l := &sales.POSLine{ItemVariant: iv, Quantity: 1}
+	if l.PricingType, l.UnitPrice, e = sales.POSUnitPrice(iv); e != nil {
+		return nil, e
+	}
+	l.Subtotal = sales.POSSubtotal(l)
+
+	return l, nil
+}
+
+// Checkout membuat sales order walk-in customer dari transaksi kasir, setiap pembayaran
+// dicatat sebagai finance revenue dan pembayaran cash dikurangi kembalian.
+func Checkout(r *checkoutRequest) (m *model.PosTransaction, e error) {
+	change := r.ChangeAmount
+
+	var revenues []*model.FinanceRevenue
+	payments := make([]*model.PosPayment, len(r.Payments))
+	for i, p := range r.Payments {
+		payments[i] = &model.PosPayment{PaymentMethod: p.PaymentMethod, BankAccount: p.BankAccount, Amount: p.Amount}
+
+		amount := p.Amount
+		if p.PaymentMethod == "cash" && change > 0 {
+			if change >= amount {
+				change -= amount
+				continue
+			}
+			amount -= change
+			change = 0
+		}
+
+		fr := &model.FinanceRevenue{PaymentMethod: p.PaymentMethod, Amount: amount, BankAccount: p.BankAccount}
+		if p.BankAccount != nil {
+			fr.BankName = p.BankAccount.BankName
+			fr.BankNumber = p.BankAccount.BankNumber
+		}
+
+		payments[i].FinanceRevenue = fr
+		revenues = append(revenues, fr)
+	}
+
+	var so *model.SalesOrder
+	if so, e = sales.CreatePOSSalesOrder(r.Lines, revenues, r.Session.User, r.Note); e != nil {
+		return nil, e
+	}
+
+	m = &model.PosTransaction{
+		PosShift:      r.Shift,
+		SalesOrder:    so,
+		TotalCharge:   so.TotalCharge,
+		TotalTendered: r.TotalTendered,
+		ChangeAmount:  r.TotalTendered - so.TotalCharge,
+		CreatedBy:     r.Session.User,
+		CreatedAt:     time.Now(),
+	}
+
+	if e = m.Save(); e != nil {
+		return nil, e
+	}
+
+	for _, p := range payments {
+		p.PosTransaction = &model.PosTransaction{ID: m.ID}
+		if e = p.Save(); e != nil {
+			return nil, e
+		}
+	}
+	m.PosPayments = payments
+
+	return
+}
+
+// ShowTransaction mengambil transaksi kasir beserta pembayaran dan item sales order.
+func ShowTransaction(field string, values ...interface{}) (*model.PosTransaction, error) {
+	m := new(model.PosTransaction)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.PosPayment)).Filter("pos_transaction_id", m.ID).RelatedSel("BankAccount").OrderBy("id").All(&m.PosPayments)
+	o.QueryTable(new(model.SalesOrderItem)).Filter("sales_order_id", m.SalesOrder.ID).RelatedSel("ItemVariant__Item").OrderBy("id").All(&m.SalesOrder.SalesOrderItems)
+
+	return m, nil
+}
+
+// calculateShift menghitung penjualan cash dan non cash shift, penjualan cash sudah dikurangi kembalian
+// dan expected cash adalah modal awal ditambah penjualan cash.
+func calculateShift(m *model.PosShift) (e error) {
+	o := orm.NewOrm()
+
+	var tendered, change float64
+	if e = o.Raw("SELECT COALESCE(SUM(pp.amount), 0) FROM pos_payment pp "+
+		"JOIN pos_transaction pt ON pt.id = pp.pos_transaction_id "+
+		"WHERE pt.pos_shift_id = ? AND pp.payment_method = 'cash'", m.ID).QueryRow(&tendered); e != nil {
+		return
+	}
+
+	if e = o.Raw("SELECT COALESCE(SUM(change_amount), 0) FROM pos_transaction WHERE pos_shift_id = ?", m.ID).QueryRow(&change); e != nil {
+		return
+	}
+
+	if e = o.Raw("SELECT COALESCE(SUM(pp.amount), 0) FROM pos_payment pp "+
+		"JOIN pos_transaction pt ON pt.id = pp.pos_transaction_id "+
+		"WHERE pt.pos_shift_id = ? AND pp.payment_method != 'cash'", m.ID).QueryRow(&m.NonCashSales); e != nil {
+		return
+	}
+
+	if e = o.Raw("SELECT COUNT(*) FROM pos_transaction WHERE pos_shift_id = ?", m.ID).QueryRow(&m.TotalTransaction); e != nil {
+		return
+	}
+
+	m.CashSales = tendered - change
+	m.ExpectedCash = m.OpeningFloat + m.CashSales
+
+	return
+}
+
+// CloseShift menutup shift kasir, cash yang dihitung kasir dibandingkan dengan expected cash,
+// selisih positif berarti cash lebih dan selisih negatif berarti cash kurang.
+func CloseShift(m *model.PosShift, countedCash float64, note string) (e error) {
+	if e = calculateShift(m); e != nil {
+		return
+	}
+
+	m.CountedCash = countedCash
+	m.Difference = countedCash - m.ExpectedCash
+	m.Status = "closed"
+	m.ClosedAt = time.Now()
+	if note != "" {
+		m.Note = note
+	}
+
+	return m.Save("CashSales", "NonCashSales", "ExpectedCash", "CountedCash", "Difference", "TotalTransaction", "Status", "Note", "ClosedAt")
+}
+
+// GetShiftReport membuat laporan rekonsiliasi shift, untuk shift yang masih open
+// perhitungan dilakukan sampai transaksi terakhir.
+func GetShiftReport(m *model.PosShift) (r *ShiftReport, e error) {
+	if m.Status == "open" {
+		if e = calculateShift(m); e != nil {
+			return nil, e
+		}
+	}
+
+	r = &ShiftReport{Shift: m}
+	o := orm.NewOrm()
+	if _, e = o.QueryTable(new(model.PosTransaction)).Filter("pos_shift_id", m.ID).RelatedSel("SalesOrder").OrderBy("id").All(&r.Transactions); e != nil {
+		return nil, e
+	}
+
+	summary := make(map[string]*PaymentSummary)
+	for _, t := range r.Transactions {
+		o.QueryTable(new(model.PosPayment)).Filter("pos_transaction_id", t.ID).RelatedSel("BankAccount").OrderBy("id").All(&t.PosPayments)
+
+		for _, p := range t.PosPayments {
+			key := p.PaymentMethod
+			if p.BankAccount != nil && p.PaymentMethod != "cash" {
+				key += "." + common.Encrypt(p.BankAccount.ID)
+			}
+
+			if summary[key] == nil {
+				summary[key] = &PaymentSummary{PaymentMethod: p.PaymentMethod, BankAccount: p.BankAccount}
+				r.Payments = append(r.Payments, summary[key])
+			}
+			summary[key].Amount += p.Amount
+			summary[key].Total++
+		}
+
+		// kembalian mengurangi penerimaan cash
+		if t.ChangeAmount > 0 {
+			if summary["cash"] == nil {
+				summary["cash"] = &PaymentSummary{PaymentMethod: "cash"}
+				r.Payments = append(r.Payments, summary["cash"])
+			}
+			summary["cash"].Amount -= t.ChangeAmount
+		}
+	}
+
+	return
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package pos
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// dummyCheckout menyiapkan walk-in customer, pricing type default, item variant dengan barcode
+// seharga 10000 dan shift kasir yang masih open dengan modal awal 100000.
+func dummyCheckout() (*model.ItemVariant, *model.PosShift) {
+	c := model.DummyPartnership()
+	c.PartnershipType = "customer"
+	c.IsDefault = 1
+	c.IsDeleted = 0
+	c.IsArchived = 0
+	c.OrderRule = "none"
+	c.Save()
+
+	pt := model.DummyPricingType()
+	pt.ParentType = nil
+	pt.IsDefault = 1
+	pt.Save()
+
+	iv := model.DummyItemVariant()
+	iv.Barcode = common.RandomStr(13)
+	iv.AvailableStock = 100
+	iv.CommitedStock = 0
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save()
+
+	ivp := model.DummyItemVariantPrice()
+	ivp.ItemVariant = iv
+	ivp.PricingType = pt
+	ivp.UnitPrice = 10000
+	ivp.Save()
+
+	shift := model.DummyPosShift()
+	shift.Cashier = model.DummyUser()
+	shift.OpeningFloat = 100000
+	shift.Status = "open"
+	shift.Save()
+
+	return iv, shift
+}
+
+func TestScanItem(t *testing.T) {
+	iv, _ := dummyCheckout()
+
+	l, e := ScanItem(iv.Barcode)
+	assert.NoError(t, e)
+	assert.Equal(t, iv.ID, l.ItemVariant.ID)
+	assert.Equal(t, float64(10000), l.UnitPrice)
+
+	_, e = ScanItem("not-a-barcode")
+	assert.Error(t, e)
+}
+
+func TestCheckout(t *testing.T) {
+	iv, shift := dummyCheckout()
+	ba := model.DummyBankAccount()
+
+	r := &checkoutRequest{
+		Items: []checkoutItem{
+			{Barcode: iv.Barcode, Quantity: 2},
+			{Barcode: iv.Barcode, Quantity: 1},
+		},
+		Payments: []checkoutPayment{
+			{PaymentMethod: "transfer", BankAccountID: common.Encrypt(ba.ID), Amount: 10000},
+			{PaymentMethod: "cash", Amount: 50000},
+		},
+		Session: &auth.SessionData{User: shift.Cashier},
+	}
+
+	o := r.Validate()
+	assert.True(t, o.Valid)
+	assert.Len(t, r.Lines, 1)
+	assert.Equal(t, float64(30000), r.TotalCharge)
+	assert.Equal(t, float64(30000), r.ChangeAmount)
+
+	m, e := Checkout(r)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(30000), m.TotalCharge)
+	assert.Equal(t, float64(30000), m.ChangeAmount)
+	assert.Len(t, m.PosPayments, 2)
+
+	// revenue cash sudah dikurangi kembalian
+	assert.Equal(t, float64(20000), m.PosPayments[1].FinanceRevenue.Amount)
+	assert.Equal(t, "transfer", m.PosPayments[0].FinanceRevenue.PaymentMethod)
+
+	so := &model.SalesOrder{ID: m.SalesOrder.ID}
+	so.Read()
+	assert.Equal(t, "finished", so.InvoiceStatus)
+
+	m, e = ShowTransaction("id", m.ID)
+	assert.NoError(t, e)
+	rc := GetReceipt(m)
+	assert.Len(t, rc.Lines, 1)
+	assert.Contains(t, rc.Text, "30.000")
+
+	// rekonsiliasi: modal 100000 + cash 20000, kasir menghitung 115000
+	assert.NoError(t, CloseShift(shift, 115000, ""))
+	assert.Equal(t, "closed", shift.Status)
+	assert.Equal(t, float64(20000), shift.CashSales)
+	assert.Equal(t, float64(10000), shift.NonCashSales)
+	assert.Equal(t, float64(120000), shift.ExpectedCash)
+	assert.Equal(t, float64(-5000), shift.Difference)
+	assert.Equal(t, 1, shift.TotalTransaction)
+
+	rp, e := GetShiftReport(shift)
+	assert.NoError(t, e)
+	assert.Len(t, rp.Transactions, 1)
+	assert.Len(t, rp.Payments, 2)
+}
+
+func TestCheckoutInvalidPayment(t *testing.T) {
+	iv, shift := dummyCheckout()
+
+	var data = []struct {
+		payments []checkoutPayment
+	}{
+		// pembayaran kurang
+		{[]checkoutPayment{{PaymentMethod: "cash", Amount: 5000}}},
+		// kembalian dari pembayaran non cash
+		{[]checkoutPayment{{PaymentMethod: "debit_card", Amount: 20000}}},
+		// transfer tanpa rekening bank
+		{[]checkoutPayment{{PaymentMethod: "transfer", Amount: 10000}}},
+	}
+
+	for _, d := range data {
+		r := &checkoutRequest{
+			Items:    []checkoutItem{{ItemVariantID: common.Encrypt(iv.ID), Quantity: 1}},
+			Payments: d.payments,
+			Session:  &auth.SessionData{User: shift.Cashier},
+		}
+		assert.False(t, r.Validate().Valid)
+	}
+
+	// kasir tanpa shift open
+	r := &checkoutRequest{
+		Items:    []checkoutItem{{ItemVariantID: common.Encrypt(iv.ID), Quantity: 1}},
+		Payments: []checkoutPayment{{PaymentMethod: "cash", Amount: 10000}},
+		Session:  &auth.SessionData{User: model.DummyUser()},
+	}
+	assert.False(t, r.Validate().Valid)
+}
+
+func TestCheckoutDiscountLimit(t *testing.T) {
+	iv, shift := dummyCheckout()
+
+	limit := model.DummyDiscountLimit()
+	limit.Usergroup = shift.Cashier.Usergroup
+	limit.MaxLineDiscount = 5
+	limit.MaxOrderDiscount = 100
+	limit.MinMargin = 0
+	limit.Save()
+
+	r := &checkoutRequest{
+		Items:    []checkoutItem{{Barcode: iv.Barcode, Quantity: 1, Discount: 10}},
+		Payments: []checkoutPayment{{PaymentMethod: "cash", Amount: 10000}},
+		Session:  &auth.SessionData{User: shift.Cashier},
+	}
+	assert.False(t, r.Validate().Valid)
+
+	// diskon melewati batas tidak boleh tersimpan sebagai transaksi kasir
+	_, e := Checkout(r)
+	assert.Error(t, e)
+	calculateShift(shift)
+	assert.Equal(t, 0, shift.TotalTransaction)
+
+	r = &checkoutRequest{
+		Items:    []checkoutItem{{Barcode: iv.Barcode, Quantity: 1, Discount: 5}},
+		Payments: []checkoutPayment{{PaymentMethod: "cash", Amount: 10000}},
+		Session:  &auth.SessionData{User: shift.Cashier},
+	}
+	assert.True(t, r.Validate().Valid)
+}
+
+func TestReceiptFormat(t *testing.T) {
+	r := &Receipt{
+		CompanyName:  "QASICO",
+		Code:         "SO-0001",
+		Date:         time.Date(2018, 3, 5, 10, 30, 0, 0, time.Local),
+		Lines:        []*ReceiptLine{{Name: "Kopi Bubuk 250gr", Quantity: 2, UnitPrice: 12500, Subtotal: 25000}},
+		TotalCharge:  25000,
+		Payments:     []*ReceiptTender{{PaymentMethod: "cash", Amount: 50000}},
+		ChangeAmount: 25000,
+	}
+
+	text := r.Format(32)
+	assert.Contains(t, text, "05/03/2018 10:30")
+	assert.Contains(t, text, "TOTAL                     25.000")
+	assert.Contains(t, text, "KEMBALI                   25.000")
+	assert.Equal(t, "1.250.000", money(1250000))
+	assert.Equal(t, "-500", money(-500))
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp()
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	si := model.DummySalesInvoice()
+	wf := model.DummyWorkorderFulfillment()
+	ws := model.DummyWorkorderShipment()
+	po := model.DummyPurchaseOrder()
+	ir := model.DummyInvoiceReceipt()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/print/sales-invoice/" + common.Encrypt(si.ID), "GET", http.StatusOK},
+		{"/v1/print/sales-invoice/" + common.Encrypt(si.ID) + "?format=escpos&paper=58", "GET", http.StatusOK},
+		{"/v1/print/sales-invoice/999999", "GET", http.StatusNotFound},
+		{"/v1/print/delivery-note/" + common.Encrypt(wf.ID), "GET", http.StatusOK},
+		{"/v1/print/delivery-note/" + common.Encrypt(wf.ID) + "?format=escpos&paper=80", "GET", http.StatusOK},
+		{"/v1/print/delivery-note/999999", "GET", http.StatusNotFound},
+		{"/v1/print/shipment-manifest/" + common.Encrypt(ws.ID), "GET", http.StatusOK},
+		{"/v1/print/shipment-manifest/999999", "GET", http.StatusNotFound},
+		{"/v1/print/purchase-order/" + common.Encrypt(po.ID), "GET", http.StatusOK},
+		{"/v1/print/purchase-order/999999", "GET", http.StatusNotFound},
+		{"/v1/print/invoice-receipt/" + common.Encrypt(ir.ID), "GET", http.StatusOK},
+		{"/v1/print/invoice-receipt/999999", "GET", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/src/util"
+)
+
+// Company identitas perusahaan yang dicetak pada kop dokumen,
+// diambil dari application setting company_name, address dan application_logo.
+type Company struct {
+	Name    string
+	Address string
+	Logo    string
+}
+
+// Column kolom tabel item pada dokumen.
+// Thermal menentukan posisi kolom pada struk thermal: "title" dicetak sebagai baris pertama,
+// "left" dan "right" dicetak pada baris kedua, kosong berarti kolom tidak dicetak.
+type Column struct {
+	Title   string
+	Width   float64
+	Align   string
+	Thermal string
+}
+
+// Document isi dokumen yang akan dicetak, satu template menghasilkan satu document
+// yang bisa dirender ke pdf maupun esc/pos.
+type Document struct {
+	Title      string
+	Code       string
+	Date       time.Time
+	Company    *Company
+	To         []string
+	Info       [][2]string
+	Columns    []Column
+	Rows       [][]string
+	Totals     [][2]string
+	Note       string
+	Signatures []string
+}
+
+// LoadCompany mengambil identitas perusahaan dari application setting.
+func LoadCompany() *Company {
+	c := new(Company)
+	if s, e := util.GetApplicationSetting("application_setting_name", "company_name"); e == nil {
+		c.Name = s.Value
+	}
+
+	if s, e := util.GetApplicationSetting("application_setting_name", "address"); e == nil {
+		c.Address = s.Value
+	}
+
+	if s, e := util.GetApplicationSetting("application_setting_name", "application_logo"); e == nil {
+		c.Logo = s.Value
+	}
+
+	return c
+}
+
+// Money format angka rupiah dengan pemisah ribuan titik.
+func Money(v float64) string {
+	s := fmt.Sprintf("%.0f", v)
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "." + s[i:]
+	}
+
+	if neg {
+		return "-" + s
+	}
+
+	return s
+}
+
+// Quantity format quantity tanpa angka desimal yang tidak perlu.
+func Quantity(v float32) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+// Date format tanggal pada dokumen, tanggal kosong dicetak sebagai "-".
+func Date(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Format("02/01/2006")
+}
+
+// Render merender document sesuai format "pdf" atau "escpos", paper hanya digunakan untuk esc/pos.
+// Hasilnya berupa isi file, content type dan nama file.
+func Render(d *Document, format string, paper int) (b []byte, contentType string, filename string, e error) {
+	switch format {
+	case "", "pdf":
+		if b, e = PDF(d); e == nil {
+			return b, "application/pdf", d.Code + ".pdf", nil
+		}
+	case "escpos":
+		return ESCPOS(d, paper), "application/octet-stream", d.Code + ".bin", nil
+	default:
+		e = errors.New("format is not supported")
+	}
+
+	return
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"bytes"
+	"strings"
+)
+
+// perintah esc/pos yang digunakan printer thermal.
+var (
+	escInit        = []byte{0x1b, 0x40}
+	escAlignLeft   = []byte{0x1b, 0x61, 0x00}
+	escAlignCenter = []byte{0x1b, 0x61, 0x01}
+	escBoldOn      = []byte{0x1b, 0x45, 0x01}
+	escBoldOff     = []byte{0x1b, 0x45, 0x00}
+	escDoubleOn    = []byte{0x1d, 0x21, 0x11}
+	escDoubleOff   = []byte{0x1d, 0x21, 0x00}
+	escFeed        = []byte{0x1b, 0x64, 0x04}
+	escCut         = []byte{0x1d, 0x56, 0x41, 0x00}
+)
+
+// PaperColumns jumlah karakter per baris font A untuk kertas thermal 58mm dan 80mm.
+func PaperColumns(paper int) int {
+	if paper == 80 {
+		return 48
+	}
+
+	return 32
+}
+
+// ESCPOS merender document ke perintah esc/pos untuk printer thermal 58mm atau 80mm.
+func ESCPOS(d *Document, paper int) []byte {
+	if d.Company == nil {
+		d.Company = LoadCompany()
+	}
+
+	width := PaperColumns(paper)
+	line := strings.Repeat("-", width)
+
+	var b bytes.Buffer
+	b.Write(escInit)
+
+	b.Write(escAlignCenter)
+	b.Write(escBoldOn)
+	b.Write(escDoubleOn)
+	writeLine(&b, truncate(d.Company.Name, width/2))
+	b.Write(escDoubleOff)
+	b.Write(escBoldOff)
+	for _, l := range wrap(d.Company.Address, width) {
+		writeLine(&b, l)
+	}
+	writeLine(&b, "")
+
+	b.Write(escBoldOn)
+	writeLine(&b, truncate(d.Title, width))
+	b.Write(escBoldOff)
+	writeLine(&b, truncate(d.Code, width))
+
+	b.Write(escAlignLeft)
+	writeLine(&b, line)
+	writeLine(&b, columns("Tanggal", Date(d.Date), width))
+	for _, i := range d.Info {
+		writeLine(&b, columns(i[0], i[1], width))
+	}
+	for _, l := range d.To {
+		for _, w := range wrap(l, width) {
+			writeLine(&b, w)
+		}
+	}
+	writeLine(&b, line)
+
+	for _, row := range d.Rows {
+		var title, left, right []string
+		for i, c := range d.Columns {
+			if i >= len(row) || row[i] == "" {
+				continue
+			}
+
+			switch c.Thermal {
+			case "title":
+				title = append(title, row[i])
+			case "left":
+				left = append(left, row[i])
+			case "right":
+				right = append(right, row[i])
+			}
+		}
+
+		if len(title) > 0 {
+			writeLine(&b, truncate(strings.Join(title, " "), width))
+		}
+		if len(left) > 0 || len(right) > 0 {
+			writeLine(&b, columns("  "+strings.Join(left, " x "), strings.Join(right, " "), width))
+		}
+	}
+
+	if len(d.Totals) > 0 {
+		writeLine(&b, line)
+		b.Write(escBoldOn)
+		for _, t := range d.Totals {
+			writeLine(&b, columns(t[0], t[1], width))
+		}
+		b.Write(escBoldOff)
+	}
+
+	if d.Note != "" {
+		writeLine(&b, line)
+		for _, l := range wrap(d.Note, width) {
+			writeLine(&b, l)
+		}
+	}
+
+	b.Write(escFeed)
+	b.Write(escCut)
+
+	return b.Bytes()
+}
+
+// ESCPOSText membungkus teks yang sudah diformat seperti struk kasir dengan perintah esc/pos.
+func ESCPOSText(text string, paper int) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+	b.Write(escAlignLeft)
+	for _, l := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		writeLine(&b, truncate(l, PaperColumns(paper)))
+	}
+	b.Write(escFeed)
+	b.Write(escCut)
+
+	return b.Bytes()
+}
+
+func writeLine(b *bytes.Buffer, s string) {
+	b.WriteString(s)
+	b.WriteByte('\n')
+}
+
+func columns(left string, right string, width int) string {
+	space := width - len(left) - len(right)
+	if space < 1 {
+		left = truncate(left, width-len(right)-1)
+		space = 1
+	}
+
+	return left + strings.Repeat(" ", space) + right
+}
+
+func truncate(s string, width int) string {
+	if width > 0 && len(s) > width {
+		return s[:width]
+	}
+
+	return s
+}
+
+// wrap memecah teks menjadi beberapa baris sesuai lebar kertas.
+func wrap(s string, width int) (lines []string) {
+	var cur string
+	for _, w := range strings.Fields(s) {
+		if cur != "" && len(cur)+1+len(w) > width {
+			lines = append(lines, cur)
+			cur = ""
+		}
+
+		if cur != "" {
+			cur += " "
+		}
+		cur += truncate(w, width)
+	}
+
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+
+	return
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"net/http"
+	"strconv"
+
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for printing.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/sales-invoice/:id", h.salesInvoice, auth.CheckPrivilege("sales_invoice_show"))
+	r.GET("/delivery-note/:id", h.deliveryNote, auth.CheckPrivilege("fulfillment_show"))
+	r.GET("/shipment-manifest/:id", h.shipmentManifest, auth.CheckPrivilege("shipment_show"))
+	r.GET("/purchase-order/:id", h.purchaseOrder, auth.CheckPrivilege("purchase_order_show"))
+	r.GET("/invoice-receipt/:id", h.invoiceReceipt, auth.CheckPrivilege("invoice_receipt_show"))
+}
+
+// salesInvoice endpoint untuk mencetak sales invoice.
+func (h *Handler) salesInvoice(c echo.Context) error {
+	return printDocument(c, SalesInvoiceDocument)
+}
+
+// deliveryNote endpoint untuk mencetak surat jalan workorder fulfillment.
+func (h *Handler) deliveryNote(c echo.Context) error {
+	return printDocument(c, DeliveryNoteDocument)
+}
+
+// shipmentManifest endpoint untuk mencetak manifest workorder shipment.
+func (h *Handler) shipmentManifest(c echo.Context) error {
+	return printDocument(c, ShipmentManifestDocument)
+}
+
+// purchaseOrder endpoint untuk mencetak purchase order.
+func (h *Handler) purchaseOrder(c echo.Context) error {
+	return printDocument(c, PurchaseOrderDocument)
+}
+
+// invoiceReceipt endpoint untuk mencetak tanda terima invoice.
+func (h *Handler) invoiceReceipt(c echo.Context) error {
+	return printDocument(c, InvoiceReceiptDocument)
+}
+
+// printDocument mengambil document dari template lalu merender sesuai parameter format (pdf / escpos)
+// dan paper (58 / 80) untuk printer thermal.
+func printDocument(c echo.Context, template func(int64) (*Document, error)) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var d *Document
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if d, e = template(id); e == nil {
+			paper, _ := strconv.Atoi(ctx.QueryParam("paper"))
+
+			var b []byte
+			var contentType, filename string
+			if b, contentType, filename, e = Render(d, ctx.QueryParam("format"), paper); e == nil {
+				ctx.Response().Header().Set(echo.HeaderContentDisposition, "inline; filename=\""+filename+"\"")
+				return ctx.Blob(http.StatusOK, contentType, b)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
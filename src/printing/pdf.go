@@ -0,0 +1,164 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// pdfWidth lebar area cetak kertas A4 portrait dengan margin 15mm.
+const pdfWidth = 180
+
+// PDF merender document ke file pdf ukuran A4.
+func PDF(d *Document) ([]byte, error) {
+	if d.Company == nil {
+		d.Company = LoadCompany()
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	pdfHeader(pdf, d)
+	pdfTable(pdf, d)
+	pdfFooter(pdf, d)
+
+	var b bytes.Buffer
+	if e := pdf.Output(&b); e != nil {
+		return nil, e
+	}
+
+	return b.Bytes(), nil
+}
+
+// pdfHeader mencetak kop perusahaan, judul dokumen, tujuan dan informasi dokumen.
+func pdfHeader(pdf *gofpdf.Fpdf, d *Document) {
+	x := 15.0
+	if logo := d.Company.Logo; isImage(logo) {
+		pdf.ImageOptions(logo, 15, 15, 20, 0, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+		x = 40
+	}
+
+	pdf.SetXY(x, 15)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 7, d.Company.Name, "", 1, "L", false, 0, "")
+	pdf.SetX(x)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(0, 4.5, d.Company.Address, "", "L", false)
+
+	pdf.SetY(37)
+	pdf.Line(15, pdf.GetY(), 15+pdfWidth, pdf.GetY())
+	pdf.Ln(3)
+
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 7, d.Title, "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, d.Code, "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	top := pdf.GetY()
+	if len(d.To) > 0 {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(90, 5, "Kepada:", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, l := range d.To {
+			pdf.MultiCell(90, 4.5, l, "", "L", false)
+		}
+	}
+	left := pdf.GetY()
+
+	info := append([][2]string{{"Tanggal", Date(d.Date)}}, d.Info...)
+	pdf.SetY(top)
+	for _, i := range info {
+		pdf.SetX(115)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(30, 5, i[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 5, ": "+i[1], "", 1, "L", false, 0, "")
+	}
+
+	if pdf.GetY() < left {
+		pdf.SetY(left)
+	}
+	pdf.Ln(4)
+}
+
+// pdfTable mencetak tabel item, lebar kolom disesuaikan dengan lebar area cetak.
+func pdfTable(pdf *gofpdf.Fpdf, d *Document) {
+	if len(d.Columns) == 0 {
+		return
+	}
+
+	var total float64
+	for _, c := range d.Columns {
+		total += c.Width
+	}
+	ratio := pdfWidth / total
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for _, c := range d.Columns {
+		pdf.CellFormat(c.Width*ratio, 7, c.Title, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, row := range d.Rows {
+		for i, c := range d.Columns {
+			var v string
+			if i < len(row) {
+				v = row[i]
+			}
+			pdf.CellFormat(c.Width*ratio, 6, v, "1", 0, c.Align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	for _, t := range d.Totals {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(pdfWidth-45, 6, t[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(45, 6, t[1], "1", 1, "R", false, 0, "")
+	}
+}
+
+// pdfFooter mencetak catatan dan kolom tanda tangan.
+func pdfFooter(pdf *gofpdf.Fpdf, d *Document) {
+	pdf.Ln(4)
+	if d.Note != "" {
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.MultiCell(0, 4.5, "Catatan: "+d.Note, "", "L", false)
+		pdf.Ln(4)
+	}
+
+	if n := len(d.Signatures); n > 0 {
+		w := float64(pdfWidth) / float64(n)
+		pdf.SetFont("Helvetica", "", 9)
+		for _, s := range d.Signatures {
+			pdf.CellFormat(w, 5, s, "", 0, "C", false, 0, "")
+		}
+		pdf.Ln(22)
+		for range d.Signatures {
+			pdf.CellFormat(w, 5, "(____________________)", "", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// isImage logo hanya dicetak apabila application_logo berupa path file gambar yang ada.
+func isImage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+	default:
+		return false
+	}
+
+	_, e := os.Stat(path)
+	return e == nil
+}
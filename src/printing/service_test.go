@@ -0,0 +1,124 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dummyDocument() *Document {
+	return &Document{
+		Title:   "SALES INVOICE",
+		Code:    "SI-00001",
+		Date:    time.Date(2017, 3, 1, 0, 0, 0, 0, time.Local),
+		Company: &Company{Name: "Toko Maju Jaya", Address: "Jl. Pemuda No. 10 Semarang"},
+		To:      []string{"Budi", "Jl. Pandanaran 5"},
+		Info:    [][2]string{{"Sales Order", "SO-00001"}},
+		Columns: itemColumns,
+		Rows: [][]string{
+			{"1", "Kemeja Putih L", "2", Money(150000), "-", Money(300000)},
+			{"2", "Celana Hitam 32", "1", Money(200000), "10%", Money(180000)},
+		},
+		Totals:     [][2]string{{"Total", Money(480000)}},
+		Note:       "terima kasih",
+		Signatures: []string{"Hormat Kami", "Penerima"},
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "0", Money(0))
+	assert.Equal(t, "999", Money(999))
+	assert.Equal(t, "1.000", Money(1000))
+	assert.Equal(t, "1.250.000", Money(1250000))
+	assert.Equal(t, "-15.000", Money(-15000))
+
+	assert.Equal(t, "2", Quantity(2))
+	assert.Equal(t, "2.5", Quantity(2.5))
+
+	assert.Equal(t, "-", Date(time.Time{}))
+	assert.Equal(t, "01/03/2017", Date(time.Date(2017, 3, 1, 0, 0, 0, 0, time.Local)))
+}
+
+func TestESCPOS(t *testing.T) {
+	for paper, width := range map[int]int{58: 32, 80: 48, 0: 32} {
+		b := ESCPOS(dummyDocument(), paper)
+
+		assert.True(t, bytes.HasPrefix(b, escInit))
+		assert.True(t, bytes.HasSuffix(b, escCut))
+		assert.Contains(t, string(b), "Kemeja Putih L")
+
+		var found bool
+		for _, l := range strings.Split(string(b), "\n") {
+			if strings.HasPrefix(l, "Total") {
+				found = true
+				assert.Equal(t, width, len(l))
+				assert.True(t, strings.HasSuffix(l, "480.000"))
+			}
+		}
+		assert.True(t, found)
+	}
+
+	b := ESCPOSText("TOKO\nTOTAL 10.000\n", 58)
+	assert.True(t, bytes.HasPrefix(b, escInit))
+	assert.True(t, bytes.HasSuffix(b, escCut))
+	assert.Contains(t, string(b), "TOTAL 10.000\n")
+}
+
+func TestPDF(t *testing.T) {
+	b, e := PDF(dummyDocument())
+	assert.NoError(t, e)
+	assert.True(t, bytes.HasPrefix(b, []byte("%PDF")))
+
+	b, ct, fn, e := Render(dummyDocument(), "escpos", 80)
+	assert.NoError(t, e)
+	assert.Equal(t, "application/octet-stream", ct)
+	assert.Equal(t, "SI-00001.bin", fn)
+	assert.NotEmpty(t, b)
+
+	_, _, _, e = Render(dummyDocument(), "docx", 0)
+	assert.Error(t, e)
+}
+
+func TestTemplates(t *testing.T) {
+	soi := model.DummySalesOrderItem()
+	si := model.DummySalesInvoice()
+	si.SalesOrder = soi.SalesOrder
+	si.Save()
+
+	d, e := SalesInvoiceDocument(si.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, si.Code, d.Code)
+	assert.Equal(t, 1, len(d.Rows))
+
+	wfi := model.DummyWorkorderFulfillmentItem()
+	d, e = DeliveryNoteDocument(wfi.WorkorderFulfillment.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, 1, len(d.Rows))
+
+	wsi := model.DummyWorkorderShipmentItem()
+	d, e = ShipmentManifestDocument(wsi.WorkorderShipment.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, wsi.WorkorderFulfillment.Code, d.Rows[0][1])
+
+	poi := model.DummyPurchaseOrderItem()
+	d, e = PurchaseOrderDocument(poi.PurchaseOrder.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, 1, len(d.Rows))
+
+	iri := model.DummyInvoiceReceiptItem()
+	d, e = InvoiceReceiptDocument(iri.InvoiceReceipt.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, iri.SalesInvoice.Code, d.Rows[0][1])
+
+	_, e = SalesInvoiceDocument(999999)
+	assert.Error(t, e)
+}
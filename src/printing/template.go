@@ -0,0 +1,291 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printing
+
+import (
+	"fmt"
+	"strings"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// itemColumns kolom item untuk dokumen yang memiliki harga seperti sales invoice dan purchase order.
+var itemColumns = []Column{
+	{Title: "No", Width: 10, Align: "C"},
+	{Title: "Item", Width: 70, Align: "L", Thermal: "title"},
+	{Title: "Qty", Width: 20, Align: "R", Thermal: "left"},
+	{Title: "Harga", Width: 30, Align: "R", Thermal: "left"},
+	{Title: "Disc", Width: 15, Align: "R"},
+	{Title: "Subtotal", Width: 35, Align: "R", Thermal: "right"},
+}
+
+// itemName nama item variant yang dicetak, gabungan nama item dan nama variant.
+func itemName(iv *model.ItemVariant) string {
+	if iv == nil {
+		return ""
+	}
+
+	if iv.Item == nil || iv.Item.ItemName == "" {
+		iv.Read()
+		if iv.Item != nil {
+			iv.Item.Read()
+		}
+	}
+
+	name := iv.VariantName
+	if iv.Item != nil {
+		name = strings.TrimSpace(iv.Item.ItemName + " " + iv.VariantName)
+	}
+
+	return name
+}
+
+func discount(d float32) string {
+	if d == 0 {
+		return "-"
+	}
+
+	return Quantity(d) + "%"
+}
+
+// partyLines nama dan alamat partnership yang dicetak pada bagian kepada.
+func partyLines(p *model.Partnership, address string) (lines []string) {
+	if p == nil {
+		return
+	}
+
+	p.Read()
+	if address == "" {
+		address = p.Address
+	}
+
+	lines = append(lines, p.FullName)
+	if address != "" {
+		lines = append(lines, address)
+	}
+	if p.Phone != "" {
+		lines = append(lines, p.Phone)
+	}
+
+	return
+}
+
+// SalesInvoiceDocument template cetak sales invoice beserta item sales order.
+func SalesInvoiceDocument(id int64) (d *Document, e error) {
+	si := &model.SalesInvoice{ID: id}
+	if e = si.Read(); e != nil {
+		return nil, e
+	}
+
+	so := si.SalesOrder
+	if e = so.Read(); e != nil {
+		return nil, e
+	}
+
+	d = &Document{
+		Title:   "SALES INVOICE",
+		Code:    si.Code,
+		Date:    si.RecognitionDate,
+		To:      partyLines(so.Customer, si.BillingAddress),
+		Columns: itemColumns,
+		Note:    si.Note,
+		Info: [][2]string{
+			{"Sales Order", so.Code},
+			{"Jatuh Tempo", Date(si.DueDate)},
+		},
+		Signatures: []string{"Hormat Kami", "Penerima"},
+	}
+
+	if si.TaxInvoiceNumber != "" {
+		d.Info = append(d.Info, [2]string{"No. Faktur Pajak", si.TaxInvoiceNumber})
+	}
+
+	var items []*model.SalesOrderItem
+	orm.NewOrm().QueryTable(new(model.SalesOrderItem)).Filter("sales_order_id", so.ID).RelatedSel("ItemVariant").OrderBy("id").All(&items)
+	for i, item := range items {
+		d.Rows = append(d.Rows, []string{fmt.Sprint(i + 1), itemName(item.ItemVariant), Quantity(item.Quantity), Money(item.UnitPrice), discount(item.Discount), Money(item.Subtotal)})
+	}
+
+	d.Totals = [][2]string{{"Subtotal", Money(so.TotalPrice)}}
+	if so.DiscountAmount > 0 {
+		d.Totals = append(d.Totals, [2]string{"Diskon", "-" + Money(so.DiscountAmount)})
+	}
+	if so.TaxAmount > 0 {
+		d.Totals = append(d.Totals, [2]string{"PPN", Money(so.TaxAmount)})
+	}
+	if so.ShipmentCost > 0 {
+		d.Totals = append(d.Totals, [2]string{"Ongkos Kirim", Money(so.ShipmentCost)})
+	}
+	d.Totals = append(d.Totals, [2]string{"Total", Money(si.TotalAmount)}, [2]string{"Dibayar", Money(si.TotalPaid)}, [2]string{"Sisa", Money(si.TotalAmount - si.TotalPaid)})
+
+	return
+}
+
+// DeliveryNoteDocument template cetak surat jalan dari workorder fulfillment.
+func DeliveryNoteDocument(id int64) (d *Document, e error) {
+	wf := &model.WorkorderFulfillment{ID: id}
+	if e = wf.Read(); e != nil {
+		return nil, e
+	}
+
+	so := wf.SalesOrder
+	if e = so.Read(); e != nil {
+		return nil, e
+	}
+
+	d = &Document{
+		Title: "SURAT JALAN",
+		Code:  wf.Code,
+		Date:  wf.CreatedAt,
+		To:    partyLines(so.Customer, wf.ShippingAddress),
+		Note:  wf.Note,
+		Info: [][2]string{
+			{"Sales Order", so.Code},
+			{"Prioritas", wf.Priority},
+		},
+		Columns: []Column{
+			{Title: "No", Width: 10, Align: "C"},
+			{Title: "Item", Width: 100, Align: "L", Thermal: "title"},
+			{Title: "Qty", Width: 25, Align: "R", Thermal: "right"},
+			{Title: "Keterangan", Width: 45, Align: "L", Thermal: "left"},
+		},
+		Signatures: []string{"Gudang", "Pengirim", "Penerima"},
+	}
+
+	var items []*model.WorkorderFulfillmentItem
+	orm.NewOrm().QueryTable(new(model.WorkorderFulfillmentItem)).Filter("workorder_fulfillment_id", wf.ID).RelatedSel("SalesOrderItem__ItemVariant").OrderBy("id").All(&items)
+	for i, item := range items {
+		d.Rows = append(d.Rows, []string{fmt.Sprint(i + 1), itemName(item.SalesOrderItem.ItemVariant), Quantity(item.Quantity), item.Note})
+	}
+
+	return
+}
+
+// ShipmentManifestDocument template cetak manifest pengiriman berisi semua surat jalan dalam satu shipment.
+func ShipmentManifestDocument(id int64) (d *Document, e error) {
+	ws := &model.WorkorderShipment{ID: id}
+	if e = ws.Read(); e != nil {
+		return nil, e
+	}
+
+	d = &Document{
+		Title: "MANIFEST PENGIRIMAN",
+		Code:  ws.Code,
+		Date:  ws.CreatedAt,
+		Note:  ws.Note,
+		Info: [][2]string{
+			{"No. Truk", ws.TruckNumber},
+			{"Prioritas", ws.Priority},
+		},
+		Columns: []Column{
+			{Title: "No", Width: 10, Align: "C"},
+			{Title: "Surat Jalan", Width: 35, Align: "L", Thermal: "left"},
+			{Title: "Sales Order", Width: 35, Align: "L", Thermal: "right"},
+			{Title: "Customer", Width: 100, Align: "L", Thermal: "title"},
+		},
+		Signatures: []string{"Gudang", "Supir"},
+	}
+
+	var items []*model.WorkorderShipmentItem
+	orm.NewOrm().QueryTable(new(model.WorkorderShipmentItem)).Filter("workorder_shipment_id", ws.ID).RelatedSel("WorkorderFulfillment__SalesOrder").OrderBy("id").All(&items)
+	for i, item := range items {
+		wf := item.WorkorderFulfillment
+		customer := ""
+		if c := wf.SalesOrder.Customer; c != nil {
+			c.Read()
+			customer = strings.TrimSpace(c.FullName + " - " + wf.ShippingAddress)
+		}
+
+		d.Rows = append(d.Rows, []string{fmt.Sprint(i + 1), wf.Code, wf.SalesOrder.Code, customer})
+	}
+
+	d.Totals = [][2]string{{"Total Surat Jalan", fmt.Sprint(len(items))}}
+
+	return
+}
+
+// PurchaseOrderDocument template cetak purchase order untuk dikirim ke supplier.
+func PurchaseOrderDocument(id int64) (d *Document, e error) {
+	po := &model.PurchaseOrder{ID: id}
+	if e = po.Read(); e != nil {
+		return nil, e
+	}
+
+	d = &Document{
+		Title:   "PURCHASE ORDER",
+		Code:    po.Code,
+		Date:    po.RecognitionDate,
+		To:      partyLines(po.Supplier, ""),
+		Columns: itemColumns,
+		Note:    po.Note,
+		Info: [][2]string{
+			{"Tanggal Kirim", Date(po.EtaDate)},
+		},
+		Signatures: []string{"Dibuat Oleh", "Disetujui Oleh"},
+	}
+
+	var total float64
+	var items []*model.PurchaseOrderItem
+	orm.NewOrm().QueryTable(new(model.PurchaseOrderItem)).Filter("purchase_order_id", po.ID).RelatedSel("ItemVariant").OrderBy("id").All(&items)
+	for i, item := range items {
+		d.Rows = append(d.Rows, []string{fmt.Sprint(i + 1), itemName(item.ItemVariant), Quantity(item.Quantity), Money(item.UnitPrice), discount(item.Discount), Money(item.Subtotal)})
+		total += item.Subtotal
+	}
+
+	d.Totals = [][2]string{{"Subtotal", Money(total)}}
+	if po.DiscountAmount > 0 {
+		d.Totals = append(d.Totals, [2]string{"Diskon", "-" + Money(po.DiscountAmount)})
+	}
+	if po.TaxAmount > 0 {
+		d.Totals = append(d.Totals, [2]string{"PPN", Money(po.TaxAmount)})
+	}
+	if po.ShipmentCost > 0 {
+		d.Totals = append(d.Totals, [2]string{"Ongkos Kirim", Money(po.ShipmentCost)})
+	}
+	d.Totals = append(d.Totals, [2]string{"Total", Money(po.TotalCharge)})
+
+	return
+}
+
+// InvoiceReceiptDocument template cetak tanda terima invoice berisi daftar sales invoice yang ditagihkan.
+func InvoiceReceiptDocument(id int64) (d *Document, e error) {
+	ir := &model.InvoiceReceipt{ID: id}
+	if e = ir.Read(); e != nil {
+		return nil, e
+	}
+
+	d = &Document{
+		Title: "TANDA TERIMA INVOICE",
+		Code:  ir.Code,
+		Date:  ir.RecognitionDate,
+		To:    partyLines(ir.Partnership, ""),
+		Note:  ir.Note,
+		Columns: []Column{
+			{Title: "No", Width: 10, Align: "C"},
+			{Title: "Invoice", Width: 50, Align: "L", Thermal: "title"},
+			{Title: "Tanggal", Width: 35, Align: "C", Thermal: "left"},
+			{Title: "Jatuh Tempo", Width: 35, Align: "C"},
+			{Title: "Jumlah", Width: 50, Align: "R", Thermal: "right"},
+		},
+		Signatures: []string{"Penagih", "Penerima"},
+	}
+
+	var items []*model.InvoiceReceiptItem
+	orm.NewOrm().QueryTable(new(model.InvoiceReceiptItem)).Filter("invoice_receipt_id", ir.ID).RelatedSel("SalesInvoice").OrderBy("id").All(&items)
+	for i, item := range items {
+		si := item.SalesInvoice
+		d.Rows = append(d.Rows, []string{fmt.Sprint(i + 1), si.Code, Date(si.RecognitionDate), Date(si.DueDate), Money(item.Subtotal)})
+	}
+
+	d.Totals = [][2]string{{"Total Invoice", Money(ir.TotalInvoice)}}
+	if ir.TotalReturn > 0 {
+		d.Totals = append(d.Totals, [2]string{"Total Retur", "-" + Money(ir.TotalReturn)})
+	}
+	d.Totals = append(d.Totals, [2]string{"Total Tagihan", Money(ir.TotalAmount)})
+
+	return
+}
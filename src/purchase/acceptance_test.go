@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -133,6 +134,12 @@ func TestHandler_URLMappingPOST(t *testing.T) {
 	iv.Save("IsDeleted", "IsArchived")
 	eiv := common.Encrypt(iv.ID)
 
+	iv2 := model.DummyItemVariant()
+	iv2.IsDeleted = 0
+	iv2.IsArchived = 0
+	iv2.Save("IsDeleted", "IsArchived")
+	eiv2 := common.Encrypt(iv2.ID)
+
 	var data = []struct {
 		req      tester.D
 		expected int
@@ -205,9 +212,13 @@ func TestHandler_URLMappingPOST(t *testing.T) {
 		{tester.D{"recognition_date": time.Now(), "reference_id": erefId, "auto_invoiced": 1, "eta_date": time.Now(), "supplier_id": esupplierID, "tax": 10, "is_percentage": int8(1), "discount": 10, "shipment_cost": 5000, "note": "catatan",
 			"purchase_order_items": []tester.D{{"item_variant_id": eiv, "quantity": 0, "unit_price": 1000, "discount": 0, "note": "catatan PO Items"}},
 		}, http.StatusUnprocessableEntity},
-		//unit price kosong
+		//unit price kosong diisi harga beli terakhir dari supplier
 		{tester.D{"recognition_date": time.Now(), "reference_id": erefId, "auto_invoiced": 1, "eta_date": time.Now(), "supplier_id": esupplierID, "tax": 10, "is_percentage": int8(1), "discount": 10, "shipment_cost": 5000, "note": "catatan",
 			"purchase_order_items": []tester.D{{"item_variant_id": eiv, "quantity": 12, "unit_price": 0, "discount": 1, "note": "catatan PO Items"}},
+		}, http.StatusOK},
+		//unit price kosong dan belum ada pembelian dari supplier
+		{tester.D{"recognition_date": time.Now(), "reference_id": erefId, "auto_invoiced": 1, "eta_date": time.Now(), "supplier_id": esupplierID, "tax": 10, "is_percentage": int8(1), "discount": 10, "shipment_cost": 5000, "note": "catatan",
+			"purchase_order_items": []tester.D{{"item_variant_id": eiv2, "quantity": 12, "unit_price": 0, "discount": 1, "note": "catatan PO Items"}},
 		}, http.StatusUnprocessableEntity},
 		//poitems diskon kosong
 		{tester.D{"recognition_date": time.Now(), "reference_id": erefId, "auto_invoiced": 1, "eta_date": time.Now(), "supplier_id": esupplierID, "tax": 10, "discount": 10, "is_percentage": int8(1), "shipment_cost": 5000, "note": "catatan",
@@ -1171,3 +1182,216 @@ func TestCancelPurchaseCheckDataIfInvoiceStatusIsNew(t *testing.T) {
 		assert.Equal(t, int8(0), r.IsDeleted)
 	}
 }
+
+func TestHandler_URLMappingPriceHistory(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save("PartnershipType", "IsDeleted")
+	esupplierID := common.Encrypt(supplier.ID)
+
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save("IsDeleted", "IsArchived")
+	eiv := common.Encrypt(iv.ID)
+
+	c := model.DummySupplierCatalogue()
+	c.Partnership = supplier
+	c.ItemVariant = iv
+	c.SupplierItemCode = "SUP-001"
+	c.IsDeleted = 0
+	c.Save("Partnership", "ItemVariant", "SupplierItemCode", "IsDeleted")
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		// pembelian pertama dengan kode barang supplier
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "supplier_id": esupplierID, "is_percentage": int8(1),
+			"purchase_order_items": []tester.D{{"supplier_item_code": "SUP-001", "quantity": 10, "unit_price": 1000}},
+		}, http.StatusOK},
+		// kode barang supplier tidak sesuai dengan item variant
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "supplier_id": esupplierID, "is_percentage": int8(1),
+			"purchase_order_items": []tester.D{{"supplier_item_code": "SUP-001", "item_variant_id": common.Encrypt(int64(999999)), "quantity": 10, "unit_price": 1000}},
+		}, http.StatusUnprocessableEntity},
+		// kode barang supplier tidak terdaftar
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "supplier_id": esupplierID, "is_percentage": int8(1),
+			"purchase_order_items": []tester.D{{"supplier_item_code": "SUP-XXX", "quantity": 10, "unit_price": 1000}},
+		}, http.StatusUnprocessableEntity},
+		// unit price kosong diisi dari pembelian terakhir
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "supplier_id": esupplierID, "is_percentage": int8(1),
+			"purchase_order_items": []tester.D{{"item_variant_id": eiv, "quantity": 10}},
+		}, http.StatusOK},
+		// harga naik melebihi batas price_deviation_alert
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "supplier_id": esupplierID, "is_percentage": int8(1),
+			"purchase_order_items": []tester.D{{"item_variant_id": eiv, "quantity": 10, "unit_price": 1500}},
+		}, http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/purchase-order").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	var prices []float64
+	orm.NewOrm().Raw("SELECT poi.unit_price FROM purchase_order_item poi INNER JOIN purchase_order po ON po.id = poi.purchase_order_id "+
+		"WHERE po.supplier_id = ? ORDER BY poi.id", supplier.ID).QueryRows(&prices)
+	assert.Equal(t, []float64{1000, 1000, 1500}, prices)
+
+	total, _ := orm.NewOrm().QueryTable(new(model.PurchasePriceAlert)).Filter("partnership_id", supplier.ID).Count()
+	assert.Equal(t, int64(1), total)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/purchase-order/price?item_variant_id=" + eiv + "&supplier_id=" + esupplierID, "GET", http.StatusOK},
+		{"/v1/purchase-order/price?item_variant_id=" + eiv, "GET", http.StatusOK},
+		{"/v1/purchase-order/price-alert", "GET", http.StatusOK},
+	}
+
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_URLMappingSend(t *testing.T) {
+	s, e := test.StartSMTPServer()
+	assert.NoError(t, e)
+	defer s.Close()
+
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.Email = strings.ToLower(common.RandomStr(8)) + "@example.com"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	poi := model.DummyPurchaseOrderItem()
+	po := poi.PurchaseOrder
+	po.Supplier = supplier
+	po.DocumentStatus = "active"
+	po.IsDeleted = 0
+	po.IsSent = 0
+	po.Save()
+	epo := common.Encrypt(po.ID)
+
+	noEmail := model.DummyPartnership()
+	noEmail.Email = ""
+	noEmail.IsDeleted = 0
+	noEmail.Save()
+
+	po2 := model.DummyPurchaseOrder()
+	po2.Supplier = noEmail
+	po2.DocumentStatus = "active"
+	po2.IsDeleted = 0
+	po2.Save()
+
+	cancelled := model.DummyPurchaseOrder()
+	cancelled.Supplier = supplier
+	cancelled.DocumentStatus = "cancelled"
+	cancelled.IsDeleted = 0
+	cancelled.Save()
+
+	var data = []struct {
+		endpoint string
+		expected int
+	}{
+		// sukses
+		{"/v1/purchase-order/" + epo + "/send", http.StatusOK},
+		// supplier tidak memiliki email
+		{"/v1/purchase-order/" + common.Encrypt(po2.ID) + "/send", http.StatusUnprocessableEntity},
+		// purchase order sudah dicancel
+		{"/v1/purchase-order/" + common.Encrypt(cancelled.ID) + "/send", http.StatusUnprocessableEntity},
+		// purchase order tidak ada
+		{"/v1/purchase-order/999999/send", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST(tes.endpoint).
+			SetJSON(tester.D{}).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\nendpoint: %v , \nresponse: %v", tes.endpoint, res.Body.String()))
+			})
+	}
+
+	ng.Method = "GET"
+	ng.Path = "/v1/purchase-order/" + epo + "/email"
+	ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	// email dikirim ke supplier dengan lampiran pdf purchase order
+	msg := s.Messages()
+	assert.Equal(t, 1, len(msg))
+	assert.Equal(t, []string{supplier.Email}, msg[0].To)
+	assert.Contains(t, msg[0].Data, "application/pdf")
+	assert.Contains(t, msg[0].Data, po.Code)
+
+	po.Read()
+	assert.Equal(t, int8(1), po.IsSent)
+	assert.False(t, po.SentAt.IsZero())
+
+	pe := &model.PurchaseOrderEmail{PurchaseOrder: po}
+	assert.NoError(t, pe.Read("PurchaseOrder"))
+	assert.Equal(t, "sent", pe.Status)
+	assert.Equal(t, supplier.Email, pe.Email)
+	assert.Equal(t, user.ID, pe.SentBy.ID)
+}
+
+func TestHandler_URLMappingSendFailed(t *testing.T) {
+	s, e := test.StartSMTPServer()
+	assert.NoError(t, e)
+	s.Close()
+
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.Email = strings.ToLower(common.RandomStr(8)) + "@example.com"
+	supplier.IsDeleted = 0
+	supplier.Save()
+
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.DocumentStatus = "active"
+	po.IsDeleted = 0
+	po.IsSent = 0
+	po.Save()
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.POST("/v1/purchase-order/"+common.Encrypt(po.ID)+"/send").SetJSON(tester.D{}).Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.NotEqual(t, http.StatusOK, res.Code)
+	})
+
+	// pengiriman yang gagal tetap dicatat dan purchase order tidak ditandai terkirim
+	pe := &model.PurchaseOrderEmail{PurchaseOrder: po}
+	assert.NoError(t, pe.Read("PurchaseOrder"))
+	assert.Equal(t, "failed", pe.Status)
+	assert.NotEmpty(t, pe.Note)
+
+	po.Read()
+	assert.Equal(t, int8(0), po.IsSent)
+}
@@ -0,0 +1,141 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchase
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/printing"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/env"
+	"git.qasico.com/cuxs/mailer"
+	"git.qasico.com/cuxs/orm"
+)
+
+// emailData data yang bisa dipakai pada template email purchase order.
+type emailData struct {
+	CompanyName  string
+	SupplierName string
+	OrderCode    string
+	OrderDate    string
+	EtaDate      string
+	TotalCharge  string
+}
+
+// SendPurchaseOrder mengirim purchase order dalam bentuk pdf ke email supplier,
+// setiap pengiriman dicatat pada purchase_order_email dan purchase order ditandai sudah dikirim.
+func SendPurchaseOrder(po *model.PurchaseOrder, user *model.User) (m *model.PurchaseOrderEmail, e error) {
+	supplier := &model.Partnership{ID: po.Supplier.ID}
+	if e = supplier.Read(); e != nil {
+		return nil, e
+	}
+
+	m = &model.PurchaseOrderEmail{
+		PurchaseOrder: po,
+		Partnership:   supplier,
+		Email:         supplier.Email,
+		Status:        "sent",
+		SentBy:        user,
+		SentAt:        time.Now(),
+	}
+
+	var file string
+	if file, e = purchaseOrderPDF(po); e == nil {
+		defer os.Remove(file)
+
+		data := emailData{
+			CompanyName:  printing.LoadCompany().Name,
+			SupplierName: supplier.FullName,
+			OrderCode:    po.Code,
+			OrderDate:    printing.Date(po.RecognitionDate),
+			EtaDate:      printing.Date(po.EtaDate),
+			TotalCharge:  printing.Money(po.TotalCharge),
+		}
+
+		var body string
+		if m.Subject, e = renderEmail("purchase_order_email_subject", data); e == nil {
+			if body, e = renderEmail("purchase_order_email_template", data); e == nil {
+				msg := mailer.NewMessage()
+				msg.SetRecipient(m.Email)
+				msg.SetSubject(m.Subject)
+				msg.SetBody("text/html", body)
+				msg.Attach(file)
+
+				e = mailer.NewDialer().DialAndSend(msg)
+			}
+		}
+	}
+
+	// pengiriman yang gagal tetap dicatat agar bisa dilihat pada histori pengiriman
+	if e != nil {
+		m.Status = "failed"
+		m.Note = e.Error()
+	}
+
+	if err := m.Save(); err != nil {
+		return nil, err
+	}
+
+	if e == nil {
+		po.IsSent = int8(1)
+		po.SentAt = m.SentAt
+		e = po.Save("IsSent", "SentAt")
+	}
+
+	return m, e
+}
+
+// GetPurchaseOrderEmails mengambil histori pengiriman email purchase order, diurutkan dari yang terbaru.
+func GetPurchaseOrderEmails(poID int64) (m []*model.PurchaseOrderEmail, e error) {
+	_, e = orm.NewOrm().QueryTable(new(model.PurchaseOrderEmail)).
+		Filter("purchase_order_id", poID).
+		RelatedSel("SentBy").OrderBy("-id").All(&m)
+
+	return
+}
+
+// purchaseOrderPDF merender purchase order ke file pdf pada direktori export,
+// nama file menggunakan kode purchase order agar mudah dikenali supplier.
+func purchaseOrderPDF(po *model.PurchaseOrder) (file string, e error) {
+	var d *printing.Document
+	if d, e = printing.PurchaseOrderDocument(po.ID); e != nil {
+		return
+	}
+
+	var b []byte
+	if b, e = printing.PDF(d); e != nil {
+		return
+	}
+
+	name := strings.NewReplacer("/", "-", " ", "-").Replace(po.Code)
+	file = filepath.Join(env.GetString("EXPORT_DIRECTORY", os.TempDir()), name+".pdf")
+	e = ioutil.WriteFile(file, b, 0644)
+
+	return
+}
+
+// renderEmail membuat text dari template yang disimpan pada application_setting.
+func renderEmail(settingName string, data emailData) (s string, e error) {
+	var setting *model.ApplicationSetting
+	if setting, e = util.GetApplicationSetting("application_setting_name", settingName); e == nil {
+		var t *template.Template
+		if t, e = template.New(settingName).Parse(setting.Value); e == nil {
+			var buf bytes.Buffer
+			if e = t.Execute(&buf, data); e == nil {
+				s = buf.String()
+			}
+		}
+	}
+
+	return
+}
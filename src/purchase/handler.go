@@ -19,10 +19,14 @@ type Handler struct{}
 // URLMapping declare endpoint with handler function.
 func (h *Handler) URLMapping(r *echo.Group) {
 	r.GET("", h.get, auth.CheckPrivilege("purchase_order_read"))
+	r.GET("/price", h.price, auth.CheckPrivilege("purchase_order_read"))
+	r.GET("/price-alert", h.priceAlert, auth.CheckPrivilege("purchase_order_read"))
 	r.GET("/:id", h.show, auth.CheckPrivilege("purchase_order_show"))
 	r.POST("", h.create, auth.CheckPrivilege("purchase_order_create"))
 	r.PUT("/:id", h.update, auth.CheckPrivilege("purchase_order_update"))
 	r.PUT("/:id/cancel", h.cancel, auth.CheckPrivilege("purchase_order_cancel"))
+	r.POST("/:id/send", h.send, auth.CheckPrivilege("purchase_order_send"))
+	r.GET("/:id/email", h.email, auth.CheckPrivilege("purchase_order_show"))
 }
 
 // get endpoint to handle get http method.
@@ -117,3 +121,77 @@ func (h *Handler) cancel(c echo.Context) (e error) {
 	}
 	return ctx.Serve(e)
 }
+
+// send endpoint untuk mengirim purchase order dalam bentuk pdf ke email supplier.
+func (h *Handler) send(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var r sendRequest
+	var id int64
+	var data *model.PurchaseOrderEmail
+
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+			if r.PurchaseOrder, e = GetDetailPurchaseOrder("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if data, e = SendPurchaseOrder(r.PurchaseOrder, r.Session.User); e == nil {
+						ctx.Data(data)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// email endpoint untuk mengambil histori pengiriman email purchase order.
+func (h *Handler) email(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var data []*model.PurchaseOrderEmail
+
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if data, e = GetPurchaseOrderEmails(id); e == nil {
+			ctx.Data(data, int64(len(data)))
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// price endpoint untuk mengambil saran harga beli terakhir dan tren harga item variant,
+// parameter supplier_id opsional untuk membatasi histori dari supplier tertentu.
+func (h *Handler) price(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var ivID, suppID int64
+	var m *PriceSuggestion
+	if ivID, e = common.Decrypt(ctx.QueryParam("item_variant_id")); e == nil {
+		if s := ctx.QueryParam("supplier_id"); s != "" {
+			suppID, e = common.Decrypt(s)
+		}
+		if e == nil {
+			if m, e = GetPriceSuggestion(ivID, suppID); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// priceAlert endpoint untuk mengambil alert harga purchase order yang menyimpang dari pembelian terakhir.
+func (h *Handler) priceAlert(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var t int64
+	var m *[]model.PurchasePriceAlert
+	if m, t, e = GetPriceAlerts(rq); e == nil {
+		ctx.Data(m, t)
+	}
+
+	return ctx.Serve(e)
+}
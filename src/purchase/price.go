@@ -0,0 +1,245 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchase
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// PriceHistory harga beli item variant pada sebuah purchase order,
+// net price adalah harga setelah discount item.
+type PriceHistory struct {
+	PurchaseOrderItemID int64     `json:"-"`
+	PurchaseOrderID     int64     `json:"-"`
+	PurchaseOrderCode   string    `json:"purchase_order_code"`
+	ItemVariantID       int64     `json:"-"`
+	ItemVariantName     string    `json:"item_variant_name"`
+	SupplierID          int64     `json:"-"`
+	SupplierName        string    `json:"supplier_name"`
+	RecognitionDate     time.Time `json:"recognition_date"`
+	Quantity            float32   `json:"quantity"`
+	UnitPrice           float64   `json:"unit_price"`
+	Discount            float32   `json:"discount"`
+	NetPrice            float64   `json:"net_price"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, all Primary key & Foreign key will be encrypted.
+func (m *PriceHistory) MarshalJSON() ([]byte, error) {
+	type Alias PriceHistory
+
+	return json.Marshal(&struct {
+		PurchaseOrderItemID string `json:"purchase_order_item_id"`
+		PurchaseOrderID     string `json:"purchase_order_id"`
+		ItemVariantID       string `json:"item_variant_id"`
+		SupplierID          string `json:"supplier_id"`
+		*Alias
+	}{
+		PurchaseOrderItemID: common.Encrypt(m.PurchaseOrderItemID),
+		PurchaseOrderID:     common.Encrypt(m.PurchaseOrderID),
+		ItemVariantID:       common.Encrypt(m.ItemVariantID),
+		SupplierID:          common.Encrypt(m.SupplierID),
+		Alias:               (*Alias)(m),
+	})
+}
+
+// PriceSuggestion saran harga beli dari pembelian terakhir beserta tren harga,
+// trend bernilai up, down atau flat dibandingkan dengan pembelian sebelumnya.
+type PriceSuggestion struct {
+	LastPrice         float64         `json:"last_price"`
+	LastPurchaseDate  time.Time       `json:"last_purchase_date"`
+	LastPurchaseOrder string          `json:"last_purchase_order"`
+	AveragePrice      float64         `json:"average_price"`
+	MinPrice          float64         `json:"min_price"`
+	MaxPrice          float64         `json:"max_price"`
+	Trend             string          `json:"trend"`
+	Histories         []*PriceHistory `json:"histories"`
+}
+
+// priceHistories mengambil histori harga beli dari purchase order yang tidak dicancel sesuai kondisi,
+// diurutkan dari pembelian terbaru. Limit 0 berarti seluruh histori.
+func priceHistories(where string, args []interface{}, limit int) (m []*PriceHistory, e error) {
+	where = "po.is_deleted = 0 AND po.document_status IN ('new', 'active', 'finished') AND " + where
+	order := " ORDER BY po.recognition_date DESC, po.id DESC"
+	if limit > 0 {
+		order += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	_, e = orm.NewOrm().Raw("SELECT poi.id AS purchase_order_item_id, po.id AS purchase_order_id, po.code AS purchase_order_code, "+
+		"poi.item_variant_id, iv.external_name AS item_variant_name, "+
+		"po.supplier_id, p.full_name AS supplier_name, po.recognition_date, poi.quantity, poi.unit_price, poi.discount, "+
+		"ROUND(poi.unit_price - (poi.unit_price * COALESCE(poi.discount, 0) / 100), 2) AS net_price "+
+		"FROM purchase_order_item poi "+
+		"INNER JOIN purchase_order po ON po.id = poi.purchase_order_id "+
+		"INNER JOIN partnership p ON p.id = po.supplier_id "+
+		"INNER JOIN item_variant iv ON iv.id = poi.item_variant_id "+
+		"WHERE "+where+order+";", args...).QueryRows(&m)
+
+	return
+}
+
+// GetPriceHistories mengambil histori harga beli item variant dari purchase order yang tidak dicancel,
+// diurutkan dari pembelian terbaru. Supplier yang kosong berarti seluruh supplier.
+func GetPriceHistories(ivID int64, suppID int64, excludePO int64, limit int) (m []*PriceHistory, e error) {
+	where := "poi.item_variant_id = ? AND po.id != ?"
+	args := []interface{}{ivID, excludePO}
+	if suppID != 0 {
+		where += " AND po.supplier_id = ?"
+		args = append(args, suppID)
+	}
+
+	return priceHistories(where, args, limit)
+}
+
+// GetSupplierPriceHistories mengambil histori harga beli seluruh item variant dari supplier
+// pada periode recognition date purchase order, tanggal yang kosong tidak difilter.
+func GetSupplierPriceHistories(suppID int64, start time.Time, end time.Time) (m []*PriceHistory, e error) {
+	where := "po.supplier_id = ? AND poi.quantity > 0"
+	args := []interface{}{suppID}
+	if !start.IsZero() {
+		where += " AND po.recognition_date >= ?"
+		args = append(args, start.Format("2006-01-02"))
+	}
+	if !end.IsZero() {
+		where += " AND po.recognition_date <= ?"
+		args = append(args, end.Format("2006-01-02"))
+	}
+
+	return priceHistories(where, args, 0)
+}
+
+// GetPriceSuggestion mengambil harga beli terakhir item variant sebagai saran harga purchase order
+// beserta rata-rata, harga terendah, tertinggi dan tren dari 12 pembelian terakhir.
+func GetPriceSuggestion(ivID int64, suppID int64) (m *PriceSuggestion, e error) {
+	m = &PriceSuggestion{Trend: "flat"}
+	if m.Histories, e = GetPriceHistories(ivID, suppID, 0, 12); e != nil || len(m.Histories) == 0 {
+		return
+	}
+
+	last := m.Histories[0]
+	m.LastPrice = last.NetPrice
+	m.LastPurchaseDate = last.RecognitionDate
+	m.LastPurchaseOrder = last.PurchaseOrderCode
+	m.MinPrice = last.NetPrice
+
+	var total float64
+	for _, h := range m.Histories {
+		total += h.NetPrice
+		m.MinPrice = math.Min(m.MinPrice, h.NetPrice)
+		m.MaxPrice = math.Max(m.MaxPrice, h.NetPrice)
+	}
+	m.AveragePrice = common.FloatPrecision(total/float64(len(m.Histories)), 2)
+
+	if len(m.Histories) > 1 {
+		if prev := m.Histories[1]; last.NetPrice > prev.NetPrice {
+			m.Trend = "up"
+		} else if last.NetPrice < prev.NetPrice {
+			m.Trend = "down"
+		}
+	}
+
+	return
+}
+
+// priceDeviationThreshold mengambil batas selisih harga (dalam persen) dari application_setting,
+// batas yang belum diisi atau 0 berarti alert tidak diaktifkan.
+func priceDeviationThreshold() float64 {
+	if s, e := util.GetApplicationSetting("application_setting_name", "price_deviation_alert"); e == nil {
+		if v, e := strconv.ParseFloat(strings.TrimSpace(s.Value), 64); e == nil && v > 0 {
+			return v
+		}
+	}
+
+	return 0
+}
+
+// CheckPriceDeviation membandingkan harga item purchase order dengan pembelian terakhir dari supplier yang sama,
+// selisih yang melebihi batas price_deviation_alert disimpan sebagai purchase price alert.
+func CheckPriceDeviation(po *model.PurchaseOrder) (alerts []*model.PurchasePriceAlert, e error) {
+	threshold := priceDeviationThreshold()
+	if threshold == 0 {
+		return
+	}
+
+	for _, item := range po.PurchaseOrderItems {
+		var histories []*PriceHistory
+		if histories, e = GetPriceHistories(item.ItemVariant.ID, po.Supplier.ID, po.ID, 1); e != nil {
+			return nil, e
+		}
+		if len(histories) == 0 || histories[0].NetPrice == 0 {
+			continue
+		}
+
+		last := histories[0]
+		price := common.FloatPrecision(item.UnitPrice-(item.UnitPrice*float64(item.Discount)/float64(100)), 2)
+		deviation := common.FloatPrecision((price-last.NetPrice)/last.NetPrice*100, 2)
+		if math.Abs(deviation) <= threshold {
+			continue
+		}
+
+		alert := &model.PurchasePriceAlert{
+			PurchaseOrder:         &model.PurchaseOrder{ID: po.ID},
+			PurchaseOrderItem:     &model.PurchaseOrderItem{ID: item.ID},
+			LastPurchaseOrderItem: &model.PurchaseOrderItem{ID: last.PurchaseOrderItemID},
+			ItemVariant:           &model.ItemVariant{ID: item.ItemVariant.ID},
+			Partnership:           &model.Partnership{ID: po.Supplier.ID},
+			LastUnitPrice:         last.NetPrice,
+			UnitPrice:             price,
+			Deviation:             deviation,
+			Threshold:             threshold,
+			CreatedAt:             time.Now(),
+		}
+		if e = alert.Save(); e != nil {
+			return nil, e
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return
+}
+
+// GetPriceAlerts get all data purchase price alert that matched with query request parameters.
+// returning slices of purchase price alert, total data without limit and error.
+func GetPriceAlerts(rq *orm.RequestQuery) (m *[]model.PurchasePriceAlert, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.PurchasePriceAlert))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.PurchasePriceAlert
+	if _, err = q.RelatedSel("PurchaseOrder", "ItemVariant", "Partnership").All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// supplierCatalogue mengambil katalog supplier yang aktif berdasarkan kode barang supplier.
+func supplierCatalogue(suppID int64, code string) (*model.SupplierCatalogue, error) {
+	m := new(model.SupplierCatalogue)
+	e := orm.NewOrm().QueryTable(m).
+		Filter("partnership_id", suppID).
+		Filter("supplier_item_code", code).
+		Filter("is_deleted", 0).Limit(1).One(m)
+
+	return m, e
+}
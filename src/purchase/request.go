@@ -6,14 +6,17 @@ package purchase
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
 	"git.qasico.com/mj/api/src/util"
 
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/validation"
 	"git.qasico.com/mj/api/src/inventory"
+	"git.qasico.com/mj/api/src/tax"
 )
 
 // createRequest data struct that stored request data when requesting an create purchase order process.
@@ -36,12 +39,14 @@ type createRequest struct {
 }
 
 type purchaseOrderItemRequest struct {
-	ID            string  `json:"id"`
-	ItemVariantID string  `json:"item_variant_id" valid:"required"`
-	Quantity      float32 `json:"quantity" valid:"required|gt:0"`
-	UnitPrice     float64 `json:"unit_price" valid:"required|gt:0"`
-	Discount      float32 `json:"discount" valid:"lte:100"`
-	Note          string  `json:"note"`
+	ID               string  `json:"id"`
+	ItemVariantID    string  `json:"item_variant_id"`
+	SupplierItemCode string  `json:"supplier_item_code"`
+	Quantity         float32 `json:"quantity" valid:"required|gt:0"`
+	UnitPrice        float64 `json:"unit_price" valid:"gte:0"`
+	Discount         float32 `json:"discount" valid:"lte:100"`
+	TaxCodeID        string  `json:"tax_code_id"`
+	Note             string  `json:"note"`
 }
 
 // Validate implement validation.Requests interfaces.
@@ -77,6 +82,8 @@ func (r *createRequest) Validate() *validation.Output {
 		}
 	}
 
+	resolveItems(o, r.SupplierID, r.PurchaseOrderItems)
+
 	checkDuplicate := make(map[string]bool)
 	for i, item := range r.PurchaseOrderItems {
 		// validasi itemm variant
@@ -101,6 +108,10 @@ func (r *createRequest) Validate() *validation.Output {
 		}
 	}
 
+	for i, item := range r.PurchaseOrderItems {
+		tax.ValidateTaxCode(o, fmt.Sprintf("purchase_order_items.%d.tax_code_id.invalid", i), item.TaxCodeID, r.Tax)
+	}
+
 	return o
 }
 
@@ -113,6 +124,7 @@ func (r *createRequest) Messages() map[string]string {
 func (r *createRequest) Transform(user *model.User) *model.PurchaseOrder {
 
 	var items []*model.PurchaseOrderItem
+	var lines []tax.Line
 	var total float64
 	for _, i := range r.PurchaseOrderItems {
 		ivID, _ := common.Decrypt(i.ItemVariantID)
@@ -126,10 +138,12 @@ func (r *createRequest) Transform(user *model.User) *model.PurchaseOrder {
 			Quantity:    float32(common.FloatPrecision(float64(i.Quantity), 2)),
 			UnitPrice:   i.UnitPrice,
 			Discount:    i.Discount,
+			TaxCode:     tax.TaxCodeByID(i.TaxCodeID),
 			Subtotal:    common.FloatPrecision(subtotal, 0),
 			Note:        i.Note,
 		}
 		items = append(items, poItem)
+		lines = append(lines, poItem)
 		total += subtotal
 	}
 	var po = new(model.PurchaseOrder)
@@ -178,6 +192,12 @@ func (r *createRequest) Transform(user *model.User) *model.PurchaseOrder {
 		}
 	}
 
+	// jika item memiliki tax code maka ppn dihitung per item
+	if taxAmount, exclusive, ok := tax.ApplyLineTax(lines, total, po.DiscountAmount); ok {
+		po.TaxAmount = taxAmount
+		po.TotalCharge = common.FloatPrecision(total-po.DiscountAmount+exclusive+r.ShipmentCost, 0)
+	}
+
 	if r.AutoInvoiced == int8(1) {
 		po.DocumentStatus = "active"
 		po.InvoiceStatus = "active"
@@ -226,6 +246,8 @@ func (r *updateRequest) Validate() *validation.Output {
 		}
 	}
 
+	resolveItems(o, r.SupplierID, r.PurchaseOrderItems)
+
 	checkDuplicate := make(map[string]bool)
 	for i, item := range r.PurchaseOrderItems {
 		// validasi itemm variant
@@ -249,6 +271,10 @@ func (r *updateRequest) Validate() *validation.Output {
 		}
 	}
 
+	for i, item := range r.PurchaseOrderItems {
+		tax.ValidateTaxCode(o, fmt.Sprintf("purchase_order_items.%d.tax_code_id.invalid", i), item.TaxCodeID, r.Tax)
+	}
+
 	return o
 }
 
@@ -260,6 +286,7 @@ func (r *updateRequest) Messages() map[string]string {
 
 func (r *updateRequest) Transform(user *model.User) (*model.PurchaseOrder, []*model.PurchaseOrderItem) {
 	var items []*model.PurchaseOrderItem
+	var lines []tax.Line
 	var total float64
 	var currentTotal, currentDiscount float64
 
@@ -274,10 +301,12 @@ func (r *updateRequest) Transform(user *model.User) (*model.PurchaseOrder, []*mo
 			Quantity:    float32(common.FloatPrecision(float64(i.Quantity), 2)),
 			UnitPrice:   i.UnitPrice,
 			Discount:    float32(common.FloatPrecision(float64(i.Discount), 2)),
+			TaxCode:     tax.TaxCodeByID(i.TaxCodeID),
 			Subtotal:    common.FloatPrecision(tempSubTotal-tempDiscount, 0),
 			Note:        i.Note,
 		}
 		items = append(items, poItem)
+		lines = append(lines, poItem)
 		total += common.FloatPrecision(tempSubTotal-tempDiscount, 0)
 	}
 
@@ -315,9 +344,51 @@ func (r *updateRequest) Transform(user *model.User) (*model.PurchaseOrder, []*mo
 		}
 	}
 
+	// jika item memiliki tax code maka ppn dihitung per item
+	if taxAmount, exclusive, ok := tax.ApplyLineTax(lines, total, po.DiscountAmount); ok {
+		po.TaxAmount = taxAmount
+		po.TotalCharge = common.FloatPrecision(total-po.DiscountAmount+exclusive+r.ShipmentCost, 0)
+	}
+
 	return po, items
 }
 
+// resolveItems mengisi item variant dari katalog supplier apabila item menggunakan supplier_item_code
+// dan mengisi unit price dengan harga beli terakhir dari supplier apabila unit price tidak diisi.
+func resolveItems(o *validation.Output, supplierID string, items []purchaseOrderItemRequest) {
+	suppID, _ := common.Decrypt(supplierID)
+
+	for i := range items {
+		item := &items[i]
+		if item.SupplierItemCode != "" {
+			c, e := supplierCatalogue(suppID, item.SupplierItemCode)
+			if e != nil {
+				o.Failure(fmt.Sprintf("purchase_order_items.%d.supplier_item_code.invalid", i), "supplier_item_code is not found in supplier catalogue")
+				continue
+			}
+
+			if item.ItemVariantID == "" {
+				item.ItemVariantID = common.Encrypt(c.ItemVariant.ID)
+			} else if ivID, _ := common.Decrypt(item.ItemVariantID); ivID != c.ItemVariant.ID {
+				o.Failure(fmt.Sprintf("purchase_order_items.%d.supplier_item_code.invalid", i), "supplier_item_code does not match with item_variant_id")
+			}
+		}
+
+		if item.UnitPrice == 0 {
+			if ivID, e := common.Decrypt(item.ItemVariantID); e == nil {
+				if h, e := GetPriceHistories(ivID, suppID, 0, 1); e == nil && len(h) > 0 {
+					item.UnitPrice = h[0].UnitPrice
+					item.Discount = h[0].Discount
+				}
+			}
+
+			if item.UnitPrice == 0 {
+				o.Failure(fmt.Sprintf("purchase_order_items.%d.unit_price.invalid", i), "unit_price is required, there is no last purchase price from the supplier")
+			}
+		}
+	}
+}
+
 // cancelRequest data struct that stored request data when requesting an cancel purchase order process.
 // All data must be provided and must be match with specification validation below.
 // handler function should be bind this with context to matches incoming request
@@ -354,3 +425,36 @@ func (r *cancelRequest) Transform() *model.PurchaseOrder {
 
 	return r.PurchaseOrder
 }
+
+// sendRequest data struct that stored request data when requesting an send purchase order process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type sendRequest struct {
+	Session       *auth.SessionData    `json:"-"`
+	PurchaseOrder *model.PurchaseOrder `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *sendRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.PurchaseOrder.DocumentStatus == "cancelled" {
+		o.Failure("document_status", "document status is cancelled")
+	}
+
+	supplier := &model.Partnership{ID: r.PurchaseOrder.Supplier.ID}
+	if e := supplier.Read(); e != nil || supplier.IsDeleted == int8(1) {
+		o.Failure("supplier_id", "supplier is already deleted or does not exists")
+	} else if strings.TrimSpace(supplier.Email) == "" {
+		o.Failure("supplier_id", "supplier doesn't have email address")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *sendRequest) Messages() map[string]string {
+	return map[string]string{}
+}
@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/inventory"
+	"git.qasico.com/mj/api/src/purchase_invoice"
 	"git.qasico.com/mj/api/src/util"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/orm"
 )
 
@@ -22,11 +25,18 @@ func calculateTotalPaidPO(PurchaseInvoice *model.PurchaseInvoice) (err error) {
 	return err
 }
 
+// PaidPurchaseInvoice total pembayaran purchase invoice dari finance expense yang sudah cleared.
+func PaidPurchaseInvoice(PurchaseInvoice *model.PurchaseInvoice) (paid float64) {
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(fe.amount), 0) AS total_paid from finance_expense fe "+
+		"WHERE fe.ref_id = ? AND fe.ref_type = 'purchase_invoice' AND fe.document_status = 'cleared' AND fe.is_deleted = 0;", uint64(PurchaseInvoice.ID)).QueryRow(&paid)
+
+	return
+}
+
 // CalculateTotalPaidPI Core library untuk menghitung total paid purchase invoice
 func CalculateTotalPaidPI(PurchaseInvoice *model.PurchaseInvoice) (pi *model.PurchaseInvoice, err error) {
 	o := orm.NewOrm()
-	_, err = o.Raw("UPDATE purchase_invoice pi SET pi.total_paid = (SELECT SUM(fe.amount) AS total_paid from finance_expense fe "+
-		"WHERE fe.ref_id = ? AND fe.ref_type = 'purchase_invoice' AND fe.document_status = 'cleared') WHERE pi.id = ?;", uint64(PurchaseInvoice.ID), PurchaseInvoice.ID).Exec()
+	_, err = o.Raw("UPDATE purchase_invoice pi SET pi.total_paid = ? WHERE pi.id = ?;", PaidPurchaseInvoice(PurchaseInvoice), PurchaseInvoice.ID).Exec()
 	calculateTotalPaidPO(PurchaseInvoice)
 	PurchaseInvoice.Read()
 	pi = PurchaseInvoice
@@ -157,6 +167,8 @@ func GetDetailPurchaseOrder(field string, values ...interface{}) (*model.Purchas
 	o.LoadRelated(m, "PurchaseInvoices", 3)
 	o.LoadRelated(m, "PurchaseReturns", 3)
 	o.LoadRelated(m, "WorkorderReceivings", 3)
+	o.LoadRelated(m, "PurchasePriceAlerts", 1)
+	o.LoadRelated(m, "PurchaseOrderEmails", 1)
 
 	return m, nil
 }
@@ -178,6 +190,9 @@ func CreatePurchaseOrder(po *model.PurchaseOrder) (*model.PurchaseOrder, error)
 				}
 			}
 		}
+		if e == nil {
+			po.PurchasePriceAlerts, e = CheckPriceDeviation(po)
+		}
 		//check is auto invoice
 		if po.AutoInvoiced == 1 {
 			// create purchase invoice
@@ -187,11 +202,28 @@ func CreatePurchaseOrder(po *model.PurchaseOrder) (*model.PurchaseOrder, error)
 				Code:           code,
 				TotalAmount:    po.TotalCharge,
 				DocumentStatus: "new",
+				MatchStatus:    "mismatch",
 				CreatedBy:      po.CreatedBy,
 				CreatedAt:      time.Now(),
 			}
 			if e = pi.Save(); e == nil {
-				return po, e
+				// item invoice mengikuti item purchase order supaya tetap melalui three-way match
+				for _, item := range po.PurchaseOrderItems {
+					pii := &model.PurchaseInvoiceItem{
+						PurchaseInvoice:   &model.PurchaseInvoice{ID: pi.ID},
+						PurchaseOrderItem: item,
+						Quantity:          item.Quantity,
+						UnitPrice:         common.FloatPrecision(item.UnitPrice-(item.UnitPrice*float64(item.Discount)/float64(100)), 2),
+						Subtotal:          item.Subtotal,
+					}
+					if e = pii.Save(); e != nil {
+						return nil, e
+					}
+				}
+
+				if _, e = purchaseInvoice.MatchPurchaseInvoice(pi); e == nil {
+					return po, e
+				}
 			}
 		} else {
 			return po, e
@@ -222,9 +254,12 @@ func UpdatePurchaseOrder(po *model.PurchaseOrder, itemsReq []*model.PurchaseOrde
 				item.Quantity = req.Quantity
 				item.UnitPrice = req.UnitPrice
 				item.Discount = req.Discount
+				item.TaxCode = req.TaxCode
+				item.TaxBase = req.TaxBase
+				item.TaxAmount = req.TaxAmount
 				item.Subtotal = req.Subtotal
 				item.Note = req.Note
-				item.Save("ItemVariant", "Quantity", "UnitPrice", "Discount", "Subtotal", "Note")
+				item.Save("ItemVariant", "Quantity", "UnitPrice", "Discount", "TaxCode", "TaxBase", "TaxAmount", "Subtotal", "Note")
 			} else {
 				req.PurchaseOrder = &model.PurchaseOrder{ID: po.ID}
 				req.Save()
@@ -244,6 +279,11 @@ func UpdatePurchaseOrder(po *model.PurchaseOrder, itemsReq []*model.PurchaseOrde
 		po.Supplier.TotalExpenditure += po.TotalCharge
 		po.Supplier.Save("TotalExpenditure")
 
+		// quantity purchase requisition yang sudah dipesan ikut berubah
+		if oldPo.PurchaseRequisition != nil {
+			CalculatePurchaseRequisition(oldPo.PurchaseRequisition.ID)
+		}
+
 		po, _ = GetDetailPurchaseOrder("id", po.ID)
 		return po, nil
 	}
@@ -251,6 +291,42 @@ func UpdatePurchaseOrder(po *model.PurchaseOrder, itemsReq []*model.PurchaseOrde
 	return nil, e
 }
 
+// CalculatePurchaseRequisition menghitung ulang quantity purchase requisition yang sudah dibuat purchase order,
+// purchase order yang dicancel tidak dihitung. status requisition menjadi partial apabila
+// sebagian quantity sudah dipesan dan finished apabila seluruh quantity sudah dipesan.
+func CalculatePurchaseRequisition(prID int64) (e error) {
+	pr := &model.PurchaseRequisition{ID: prID}
+	if e = pr.Read(); e != nil {
+		return
+	}
+
+	o := orm.NewOrm()
+	if _, e = o.Raw("UPDATE purchase_requisition_item pri SET pri.ordered_quantity = (SELECT COALESCE(SUM(poi.quantity), 0) FROM purchase_order_item poi "+
+		"INNER JOIN purchase_order po ON po.id = poi.purchase_order_id "+
+		"WHERE poi.purchase_requisition_item_id = pri.id AND po.is_deleted = 0 AND po.document_status != 'cancelled') "+
+		"WHERE pri.purchase_requisition_id = ?", prID).Exec(); e != nil {
+		return
+	}
+
+	if pr.DocumentStatus != "approved" && pr.DocumentStatus != "partial" && pr.DocumentStatus != "finished" {
+		return
+	}
+
+	var ordered, open float64
+	o.Raw("SELECT COALESCE(SUM(ordered_quantity), 0), COALESCE(SUM(GREATEST(quantity - ordered_quantity, 0)), 0) FROM purchase_requisition_item "+
+		"WHERE purchase_requisition_id = ?", prID).QueryRow(&ordered, &open)
+
+	pr.DocumentStatus = "approved"
+	if ordered > 0 && open > 0 {
+		pr.DocumentStatus = "partial"
+	} else if ordered > 0 {
+		pr.DocumentStatus = "finished"
+	}
+	pr.UpdatedAt = time.Now()
+
+	return pr.Save("DocumentStatus", "UpdatedAt")
+}
+
 // getItemVariantStockByPurchaseOrder untuk get data item variant stock
 // return []*item variant stock dan error
 func getItemVariantStockByPurchaseOrder(po *model.PurchaseOrder) ([]*model.ItemVariantStock, error) {
@@ -272,6 +348,11 @@ func cancelPurchaseOrder(po *model.PurchaseOrder) (*model.PurchaseOrder, error)
 
 	// update
 	if e = po.Save("DocumentStatus", "CancelledNote"); e == nil {
+		// quantity purchase requisition yang dicancel bisa dibuat purchase order kembali
+		if po.PurchaseRequisition != nil {
+			CalculatePurchaseRequisition(po.PurchaseRequisition.ID)
+		}
+
 		po.Supplier.TotalExpenditure = po.Supplier.TotalExpenditure - po.TotalCharge
 		po.Supplier.Save("TotalExpenditure")
 
@@ -285,6 +366,7 @@ func cancelPurchaseOrder(po *model.PurchaseOrder) (*model.PurchaseOrder, error)
 					}
 					i.IsDeleted = 1
 					i.Save("IsDeleted")
+					generalLedger.AutoReverse("workorder_receiving", i.ID)
 				}
 			}
 		}
@@ -300,6 +382,7 @@ func cancelPurchaseOrder(po *model.PurchaseOrder) (*model.PurchaseOrder, error)
 				for _, i := range purchaseInvoices {
 					i.IsDeleted = 1
 					i.Save("IsDeleted")
+					generalLedger.AutoReverse("purchase_invoice", i.ID)
 					if i.DocumentStatus != "new" {
 						// update finance
 						var finances []*model.FinanceExpense
@@ -307,6 +390,7 @@ func cancelPurchaseOrder(po *model.PurchaseOrder) (*model.PurchaseOrder, error)
 							for _, fx := range finances {
 								fx.IsDeleted = 1
 								fx.Save("IsDeleted")
+								generalLedger.AutoReverse("finance_expense", fx.ID)
 							}
 						}
 					}
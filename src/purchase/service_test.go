@@ -1806,3 +1806,77 @@ func TestCreateRequest_Validate_Transform(t *testing.T) {
 	// Testing Total Charge
 	assert.Equal(t, float64(10000+1000+5000), POrderHasil.TotalCharge, "Harus sama")
 }
+
+func dummyPurchasePrice(supplier *model.Partnership, iv *model.ItemVariant, price float64, date time.Time) *model.PurchaseOrderItem {
+	po := model.DummyPurchaseOrder()
+	po.Supplier = supplier
+	po.DocumentStatus = "active"
+	po.RecognitionDate = date
+	po.IsDeleted = 0
+	po.Save()
+
+	poi := model.DummyPurchaseOrderItem()
+	poi.PurchaseOrder = po
+	poi.ItemVariant = iv
+	poi.UnitPrice = price
+	poi.Discount = 0
+	poi.Save()
+
+	return poi
+}
+
+func TestGetPriceSuggestion(t *testing.T) {
+	supplier := model.DummyPartnership()
+	iv := model.DummyItemVariant()
+
+	m, e := GetPriceSuggestion(iv.ID, supplier.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(0), m.LastPrice)
+	assert.Equal(t, "flat", m.Trend)
+
+	dummyPurchasePrice(supplier, iv, 1000, time.Now().AddDate(0, -2, 0))
+	dummyPurchasePrice(supplier, iv, 1200, time.Now().AddDate(0, -1, 0))
+	last := dummyPurchasePrice(supplier, iv, 1100, time.Now())
+	dummyPurchasePrice(model.DummyPartnership(), iv, 500, time.Now())
+
+	m, e = GetPriceSuggestion(iv.ID, supplier.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, 3, len(m.Histories))
+	assert.Equal(t, float64(1100), m.LastPrice)
+	assert.Equal(t, last.PurchaseOrder.Code, m.LastPurchaseOrder)
+	assert.Equal(t, float64(1100), m.AveragePrice)
+	assert.Equal(t, float64(1000), m.MinPrice)
+	assert.Equal(t, float64(1200), m.MaxPrice)
+	assert.Equal(t, "down", m.Trend)
+
+	// tanpa supplier menghitung seluruh supplier
+	m, e = GetPriceSuggestion(iv.ID, 0)
+	assert.NoError(t, e)
+	assert.Equal(t, 4, len(m.Histories))
+	assert.Equal(t, float64(500), m.MinPrice)
+}
+
+func TestCheckPriceDeviation(t *testing.T) {
+	supplier := model.DummyPartnership()
+	iv1 := model.DummyItemVariant()
+	iv2 := model.DummyItemVariant()
+
+	dummyPurchasePrice(supplier, iv1, 1000, time.Now().AddDate(0, -1, 0))
+	dummyPurchasePrice(supplier, iv2, 1000, time.Now().AddDate(0, -1, 0))
+
+	poi1 := dummyPurchasePrice(supplier, iv1, 1050, time.Now())
+	poi2 := dummyPurchasePrice(supplier, iv2, 1250, time.Now())
+	poi2.PurchaseOrder = poi1.PurchaseOrder
+	poi2.Save("PurchaseOrder")
+
+	po := poi1.PurchaseOrder
+	po.PurchaseOrderItems = []*model.PurchaseOrderItem{poi1, poi2}
+
+	alerts, e := CheckPriceDeviation(po)
+	assert.NoError(t, e)
+	assert.Equal(t, 1, len(alerts), "selisih 5% masih di bawah batas 10%")
+	assert.Equal(t, poi2.ID, alerts[0].PurchaseOrderItem.ID)
+	assert.Equal(t, float64(1000), alerts[0].LastUnitPrice)
+	assert.Equal(t, float64(25), alerts[0].Deviation)
+	assert.Equal(t, float64(10), alerts[0].Threshold)
+}
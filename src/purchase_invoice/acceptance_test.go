@@ -28,7 +28,7 @@ func TestMain(m *testing.M) {
 	res := m.Run()
 
 	// cleanup
-	test.DataCleanUp()
+	test.DataCleanUp("purchase_invoice_mismatch", "purchase_invoice_item")
 
 	os.Exit(res)
 }
@@ -499,3 +499,125 @@ func TestUpdatePurchaseInvoiceIdNotFound(t *testing.T) {
 	}
 
 }
+
+func TestThreeWayMatchPurchaseInvoice(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("UPDATE application_setting SET value = '5' WHERE application_setting_name = 'match_price_tolerance'").Exec()
+	o.Raw("UPDATE application_setting SET value = '0' WHERE application_setting_name = 'match_quantity_tolerance'").Exec()
+
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	po := model.DummyPurchaseOrder()
+	po.InvoiceStatus = "active"
+	po.TotalPrice = 100000
+	po.TotalCharge = 100000
+	po.IsDeleted = 0
+	po.Save()
+
+	poi1 := model.DummyPurchaseOrderItem()
+	poi1.PurchaseOrder = po
+	poi1.Quantity = 10
+	poi1.UnitPrice = 5000
+	poi1.Discount = 0
+	poi1.Save()
+
+	poi2 := model.DummyPurchaseOrderItem()
+	poi2.PurchaseOrder = po
+	poi2.Quantity = 10
+	poi2.UnitPrice = 5000
+	poi2.Discount = 0
+	poi2.Save()
+
+	// item pertama baru diterima 6
+	wri := model.DummyWorkorderReceivingItem()
+	wri.WorkorderReceiving.PurchaseOrder = po
+	wri.WorkorderReceiving.IsDeleted = 0
+	wri.WorkorderReceiving.Save()
+	wri.PurchaseOrderItem = poi1
+	wri.Quantity = 6
+	wri.Save()
+
+	// item kedua sudah diterima semua
+	wri2 := model.DummyWorkorderReceivingItem()
+	wri2.WorkorderReceiving = wri.WorkorderReceiving
+	wri2.PurchaseOrderItem = poi2
+	wri2.Quantity = 10
+	wri2.Save()
+
+	invoice := func(poi *model.PurchaseOrderItem, qty float32, price float64) tester.D {
+		return tester.D{"purchase_order_item_id": common.Encrypt(poi.ID), "quantity": qty, "unit_price": price}
+	}
+	other := model.DummyPurchaseOrderItem()
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		// item bukan milik purchase order
+		{tester.D{"recognition_date": time.Now(), "purchase_order": common.Encrypt(po.ID), "due_date": time.Now(), "total_amount": 50000, "billing_address": "THE POK",
+			"purchase_invoice_items": []tester.D{invoice(other, 10, 5000)}}, http.StatusUnprocessableEntity},
+		{tester.D{"recognition_date": time.Now(), "purchase_order": common.Encrypt(po.ID), "due_date": time.Now(), "total_amount": 50000, "billing_address": "THE POK",
+			"purchase_invoice_items": []tester.D{invoice(poi1, 5, 5000), invoice(poi1, 5, 5000)}}, http.StatusUnprocessableEntity},
+		// quantity item pertama melebihi receiving, harga item kedua masih dalam toleransi 5%
+		{tester.D{"recognition_date": time.Now(), "purchase_order": common.Encrypt(po.ID), "due_date": time.Now(), "total_amount": 76000, "billing_address": "THE POK",
+			"purchase_invoice_items": []tester.D{invoice(poi1, 10, 5000), invoice(poi2, 5, 5200)}}, http.StatusOK},
+	}
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/purchase-invoice").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	var pi model.PurchaseInvoice
+	o.QueryTable(new(model.PurchaseInvoice)).Filter("purchase_order_id", po.ID).OrderBy("-id").Limit(1).One(&pi)
+	assert.Equal(t, "mismatch", pi.MatchStatus)
+
+	var mismatches []*model.PurchaseInvoiceMismatch
+	o.QueryTable(new(model.PurchaseInvoiceMismatch)).Filter("purchase_invoice_id", pi.ID).All(&mismatches)
+	assert.Len(t, mismatches, 1)
+	assert.Equal(t, "quantity", mismatches[0].MismatchType)
+
+	// laporan mismatch
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.GET("/v1/purchase-invoice/mismatch?status=open").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Contains(t, res.Body.String(), common.Encrypt(mismatches[0].ID))
+		})
+
+	path := "/v1/purchase-invoice/mismatch/" + common.Encrypt(mismatches[0].ID) + "/resolve"
+	var resolve = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{}, http.StatusUnprocessableEntity},
+		{tester.D{"note": "sisa barang dikirim minggu depan"}, http.StatusOK},
+		{tester.D{"note": "sisa barang dikirim minggu depan"}, http.StatusUnprocessableEntity},
+	}
+	for _, tes := range resolve {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.PUT(path).
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, res.Body.String())
+			})
+	}
+
+	pi.Read()
+	assert.Equal(t, "resolved", pi.MatchStatus)
+
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/purchase-invoice/mismatch/999999/resolve").
+		SetJSON(tester.D{"note": "ok"}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusNotFound, res.Code)
+		})
+
+	o.Raw("UPDATE application_setting SET value = '0' WHERE application_setting_name = 'match_price_tolerance'").Exec()
+}
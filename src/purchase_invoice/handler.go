@@ -22,6 +22,9 @@ func (h *Handler) URLMapping(r *echo.Group) {
 	r.GET("", h.get, auth.CheckPrivilege("purchase_invoice_read"))
 	r.GET("/:id", h.show, auth.CheckPrivilege("purchase_invoice_show"))
 	r.PUT("/:id", h.put, auth.CheckPrivilege("purchase_invoice_update"))
+	r.PUT("/:id/match", h.match, auth.CheckPrivilege("purchase_invoice_update"))
+	r.GET("/mismatch", h.mismatch, auth.CheckPrivilege("purchase_invoice_mismatch_read"))
+	r.PUT("/mismatch/:id/resolve", h.resolve, auth.CheckPrivilege("purchase_invoice_mismatch_resolve"))
 }
 
 // create endpoint to handle put http method with id.
@@ -100,3 +103,60 @@ func (h *Handler) put(c echo.Context) (e error) {
 
 	return ctx.Serve(e)
 }
+
+// match endpoint untuk mengulang three-way match, misalnya setelah ada receiving baru.
+func (h *Handler) match(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var pi *model.PurchaseInvoice
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if pi, e = ShowPurchaseInvoice("id", id); e == nil {
+			if _, e = MatchPurchaseInvoice(pi); e == nil {
+				pi, _ = ShowPurchaseInvoice("id", id)
+				ctx.Data(pi)
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// mismatch endpoint untuk laporan mismatch three-way match.
+func (h *Handler) mismatch(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.PurchaseInvoiceMismatch
+	if data, total, e = GetMismatches(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// resolve endpoint untuk menerima selisih mismatch sehingga invoice bisa dibayar.
+func (h *Handler) resolve(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r resolveRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.Mismatch, e = ShowMismatch("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = ResolveMismatch(r.Mismatch, r.Note, r.Session.User); e == nil {
+						ctx.Data(r.Mismatch)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,281 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchaseInvoice
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// matchTolerance mengambil toleransi selisih (dalam persen) dari application_setting,
+// toleransi yang belum diisi dianggap 0 artinya harus sama persis.
+func matchTolerance(settingName string) float64 {
+	if s, e := util.GetApplicationSetting("application_setting_name", settingName); e == nil {
+		if v, e := strconv.ParseFloat(strings.TrimSpace(s.Value), 64); e == nil && v > 0 {
+			return v
+		}
+	}
+
+	return 0
+}
+
+// variance menghitung selisih invoiced terhadap expected dalam persen.
+func variance(expected float64, invoiced float64) float64 {
+	if expected == 0 {
+		if invoiced == 0 {
+			return 0
+		}
+		return 100
+	}
+
+	return common.FloatPrecision(math.Abs(invoiced-expected)/expected*100, 2)
+}
+
+// receivedQuantity menghitung total quantity purchase order item yang sudah diterima pada receiving.
+func receivedQuantity(poItemID int64) (total float64, e error) {
+	e = orm.NewOrm().Raw("SELECT COALESCE(SUM(wri.quantity), 0) FROM workorder_receiving_item wri "+
+		"INNER JOIN workorder_receiving wr ON wr.id = wri.workorder_receiving_id "+
+		"WHERE wri.purchase_order_item_id = ? AND wr.is_deleted = 0;", poItemID).QueryRow(&total)
+
+	return
+}
+
+// invoicedQuantity menghitung total quantity purchase order item yang sudah ditagihkan
+// pada seluruh purchase invoice yang belum dihapus.
+func invoicedQuantity(poItemID int64) (total float64, e error) {
+	e = orm.NewOrm().Raw("SELECT COALESCE(SUM(pii.quantity), 0) FROM purchase_invoice_item pii "+
+		"INNER JOIN purchase_invoice pi ON pi.id = pii.purchase_invoice_id "+
+		"WHERE pii.purchase_order_item_id = ? AND pi.is_deleted = 0;", poItemID).QueryRow(&total)
+
+	return
+}
+
+// uninvoicedAmount menghitung nilai barang purchase order yang sudah diterima pada receiving
+// termasuk porsi discount, pajak dan ongkos kirim, dikurangi total amount purchase invoice
+// lain pada purchase order yang sama yang belum dihapus.
+func uninvoicedAmount(po *model.PurchaseOrder, piID int64) (total float64, e error) {
+	o := orm.NewOrm()
+
+	var received, invoiced float64
+	if e = o.Raw("SELECT COALESCE(SUM(wri.quantity * (poi.unit_price - (poi.unit_price * poi.discount / 100))), 0) "+
+		"FROM workorder_receiving_item wri "+
+		"INNER JOIN workorder_receiving wr ON wr.id = wri.workorder_receiving_id "+
+		"INNER JOIN purchase_order_item poi ON poi.id = wri.purchase_order_item_id "+
+		"WHERE poi.purchase_order_id = ? AND wr.is_deleted = 0;", po.ID).QueryRow(&received); e != nil {
+		return
+	}
+	if po.TotalPrice > 0 {
+		received = received / po.TotalPrice * po.TotalCharge
+	}
+
+	if e = o.Raw("SELECT COALESCE(SUM(total_amount), 0) FROM purchase_invoice "+
+		"WHERE purchase_order_id = ? AND id != ? AND is_deleted = 0;", po.ID, piID).QueryRow(&invoiced); e != nil {
+		return
+	}
+
+	total = common.FloatPrecision(received-invoiced, 0)
+
+	return
+}
+
+// isResolved cek apakah mismatch yang sama pada item invoice sudah pernah diresolve,
+// mismatch yang sudah diterima tidak dibuka kembali ketika matching diulang.
+func isResolved(itemID int64, mismatchType string) bool {
+	total, _ := orm.NewOrm().QueryTable(new(model.PurchaseInvoiceMismatch)).
+		Filter("purchase_invoice_item_id", itemID).
+		Filter("mismatch_type", mismatchType).
+		Filter("status", "resolved").Count()
+
+	return total > 0
+}
+
+// isAmountResolved cek apakah selisih total amount invoice terhadap subtotal item sudah pernah diresolve.
+func isAmountResolved(piID int64) bool {
+	total, _ := orm.NewOrm().QueryTable(new(model.PurchaseInvoiceMismatch)).
+		Filter("purchase_invoice_id", piID).
+		Filter("mismatch_type", "amount").
+		Filter("status", "resolved").Count()
+
+	return total > 0
+}
+
+// MatchPurchaseInvoice melakukan three-way match setiap item purchase invoice terhadap
+// harga purchase order item dan quantity yang sudah diterima pada receiving,
+// serta total amount invoice terhadap subtotal item, invoice tanpa item dicocokkan total amount-nya
+// terhadap nilai barang purchase order yang sudah diterima dan belum ditagihkan.
+// Selisih yang melebihi toleransi disimpan sebagai mismatch yang harus diresolve sebelum invoice dibayar.
+func MatchPurchaseInvoice(pi *model.PurchaseInvoice) (mismatches []*model.PurchaseInvoiceMismatch, e error) {
+	o := orm.NewOrm()
+
+	// hasil matching sebelumnya yang belum diresolve dihitung ulang
+	if _, e = o.Raw("DELETE FROM purchase_invoice_mismatch WHERE purchase_invoice_id = ? AND status = 'open'", pi.ID).Exec(); e != nil {
+		return
+	}
+
+	var items []*model.PurchaseInvoiceItem
+	if _, e = o.QueryTable(new(model.PurchaseInvoiceItem)).Filter("purchase_invoice_id", pi.ID).RelatedSel("PurchaseOrderItem").All(&items); e != nil {
+		return
+	}
+
+	priceTolerance := matchTolerance("match_price_tolerance")
+	qtyTolerance := matchTolerance("match_quantity_tolerance")
+
+	for _, item := range items {
+		poi := item.PurchaseOrderItem
+
+		// harga purchase order setelah discount
+		price := common.FloatPrecision(poi.UnitPrice-(poi.UnitPrice*float64(poi.Discount)/float64(100)), 2)
+		if v := variance(price, item.UnitPrice); v > priceTolerance && !isResolved(item.ID, "price") {
+			mismatches = append(mismatches, &model.PurchaseInvoiceMismatch{
+				PurchaseInvoiceItem: item,
+				MismatchType:        "price",
+				ExpectedValue:       price,
+				InvoicedValue:       item.UnitPrice,
+				Variance:            v,
+			})
+		}
+
+		var received, invoiced float64
+		if received, e = receivedQuantity(poi.ID); e != nil {
+			return nil, e
+		}
+		if invoiced, e = invoicedQuantity(poi.ID); e != nil {
+			return nil, e
+		}
+
+		// quantity yang ditagihkan hanya boleh melebihi quantity diterima sebesar toleransi
+		if invoiced > received && variance(received, invoiced) > qtyTolerance && !isResolved(item.ID, "quantity") {
+			mismatches = append(mismatches, &model.PurchaseInvoiceMismatch{
+				PurchaseInvoiceItem: item,
+				MismatchType:        "quantity",
+				ExpectedValue:       received,
+				InvoicedValue:       invoiced,
+				Variance:            variance(received, invoiced),
+			})
+		}
+	}
+
+	// total amount invoice harus sesuai dengan subtotal item ditambah porsi discount, pajak dan
+	// ongkos kirim purchase order yang sebanding dengan subtotal item yang ditagihkan
+	po := &model.PurchaseOrder{ID: pi.PurchaseOrder.ID}
+	if e = po.Read(); e != nil {
+		return nil, e
+	}
+
+	var expected float64
+	if len(items) == 0 {
+		// invoice tanpa item hanya dicocokkan nilainya terhadap purchase order
+		if expected, e = uninvoicedAmount(po, pi.ID); e != nil {
+			return nil, e
+		}
+	} else {
+		for _, item := range items {
+			expected += item.Subtotal
+		}
+		if po.TotalPrice > 0 {
+			expected = common.FloatPrecision(expected/po.TotalPrice*po.TotalCharge, 0)
+		}
+	}
+
+	// invoice tanpa item boleh menagih sebagian dari nilai barang yang diterima
+	partial := len(items) == 0 && pi.TotalAmount <= expected
+	if v := variance(expected, pi.TotalAmount); v > priceTolerance && !partial && !isAmountResolved(pi.ID) {
+		mismatches = append(mismatches, &model.PurchaseInvoiceMismatch{
+			MismatchType:  "amount",
+			ExpectedValue: expected,
+			InvoicedValue: pi.TotalAmount,
+			Variance:      v,
+		})
+	}
+
+	for _, m := range mismatches {
+		m.PurchaseInvoice = &model.PurchaseInvoice{ID: pi.ID}
+		m.Status = "open"
+		m.CreatedAt = time.Now()
+		if e = m.Save(); e != nil {
+			return nil, e
+		}
+	}
+
+	e = calculateMatchStatus(pi)
+
+	return
+}
+
+// calculateMatchStatus update match status purchase invoice sesuai mismatch yang dimiliki.
+func calculateMatchStatus(pi *model.PurchaseInvoice) error {
+	q := orm.NewOrm().QueryTable(new(model.PurchaseInvoiceMismatch)).Filter("purchase_invoice_id", pi.ID)
+
+	pi.MatchStatus = "matched"
+	if open, _ := q.Filter("status", "open").Count(); open > 0 {
+		pi.MatchStatus = "mismatch"
+	} else if total, _ := q.Count(); total > 0 {
+		pi.MatchStatus = "resolved"
+	}
+
+	return pi.Save("MatchStatus")
+}
+
+// HasOpenMismatch cek apakah purchase invoice masih memiliki mismatch yang belum diresolve.
+func HasOpenMismatch(piID int64) bool {
+	total, _ := orm.NewOrm().QueryTable(new(model.PurchaseInvoiceMismatch)).
+		Filter("purchase_invoice_id", piID).
+		Filter("status", "open").Count()
+
+	return total > 0
+}
+
+// ResolveMismatch menandai mismatch sudah diterima beserta alasannya,
+// purchase invoice bisa dibayar apabila seluruh mismatch sudah diresolve.
+func ResolveMismatch(m *model.PurchaseInvoiceMismatch, note string, user *model.User) (e error) {
+	m.Status = "resolved"
+	m.ResolutionNote = note
+	m.ResolvedBy = user
+	m.ResolvedAt = time.Now()
+	if e = m.Save("Status", "ResolutionNote", "ResolvedBy", "ResolvedAt"); e == nil {
+		e = calculateMatchStatus(&model.PurchaseInvoice{ID: m.PurchaseInvoice.ID})
+	}
+
+	return
+}
+
+// GetMismatches get all data purchase invoice mismatch that matched with query request parameters,
+// digunakan sebagai laporan mismatch three-way match.
+func GetMismatches(rq *orm.RequestQuery) (m *[]model.PurchaseInvoiceMismatch, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.PurchaseInvoiceMismatch))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.PurchaseInvoiceMismatch
+	if _, err = q.RelatedSel("PurchaseInvoice", "PurchaseInvoiceItem").All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowMismatch find a single data purchase invoice mismatch using field and value condition.
+func ShowMismatch(field string, values ...interface{}) (*model.PurchaseInvoiceMismatch, error) {
+	m := new(model.PurchaseInvoiceMismatch)
+	o := orm.NewOrm().QueryTable(m)
+	if err := o.Filter(field, values...).RelatedSel("PurchaseInvoice", "PurchaseInvoiceItem").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
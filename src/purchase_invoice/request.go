@@ -5,6 +5,8 @@
 package purchaseInvoice
 
 import (
+	"fmt"
+	"regexp"
 	"time"
 
 	"git.qasico.com/mj/api/datastore/model"
@@ -20,14 +22,23 @@ import (
 // handler function should be bind this with context to matches incoming request
 // data keys to the defined json tag.
 type createRequest struct {
-	SessionData     *auth.SessionData `json:"-"`
-	PurchaseOrder   string            `json:"purchase_order" valid:"required"`
-	RecognitionDate time.Time         `json:"recognition_date" valid:"required"`
-	DueDate         time.Time         `json:"due_date" valid:"required"`
-	TotalAmount     float64           `json:"total_amount" valid:"required|gte:0"`
-	Note            string            `json:"note"`
-	BillingAddress  string            `json:"billing_address" valid:"required"`
-	TotalDiff       float64
+	SessionData      *auth.SessionData `json:"-"`
+	PurchaseOrder    string            `json:"purchase_order" valid:"required"`
+	RecognitionDate  time.Time         `json:"recognition_date" valid:"required"`
+	DueDate          time.Time         `json:"due_date" valid:"required"`
+	TotalAmount      float64           `json:"total_amount" valid:"required|gte:0"`
+	Note             string            `json:"note"`
+	BillingAddress   string            `json:"billing_address" valid:"required"`
+	TaxInvoiceNumber string            `json:"tax_invoice_number"`
+	TotalDiff        float64
+	Items            []invoiceItemRequest `json:"purchase_invoice_items"`
+}
+
+type invoiceItemRequest struct {
+	PurchaseOrderItemID string  `json:"purchase_order_item_id" valid:"required"`
+	Quantity            float32 `json:"quantity" valid:"required|gt:0"`
+	UnitPrice           float64 `json:"unit_price" valid:"gte:0"`
+	Note                string  `json:"note"`
 }
 
 // Validate implement validation.Requests interfaces.
@@ -57,14 +68,39 @@ func (r *createRequest) Validate() *validation.Output {
 				}
 			}
 
+			r.validItems(o, po)
 		}
 	} else {
 		o.Failure("purchase_order", "purchase_order id cannot be decrypt")
 	}
 
+	if r.TaxInvoiceNumber != "" && !validTaxInvoiceNumber(r.TaxInvoiceNumber) {
+		o.Failure("tax_invoice_number", "tax_invoice_number must be 16 digits")
+	}
+
 	return o
 }
 
+// validItems memastikan item invoice merupakan item dari purchase order yang ditagihkan.
+func (r *createRequest) validItems(o *validation.Output, po *model.PurchaseOrder) {
+	checkDuplicate := make(map[int64]bool)
+	for i, item := range r.Items {
+		field := fmt.Sprintf("purchase_invoice_items.%d.purchase_order_item_id.invalid", i)
+		if id, e := common.Decrypt(item.PurchaseOrderItemID); e != nil {
+			o.Failure(field, "purchase_order_item_id cannot be decrypt")
+		} else {
+			poi := &model.PurchaseOrderItem{ID: id}
+			if e = poi.Read(); e != nil || poi.PurchaseOrder.ID != po.ID {
+				o.Failure(field, "item is not part of the purchase order")
+			} else if checkDuplicate[id] {
+				o.Failure(field, "purchase_order_item_id duplicate")
+			} else {
+				checkDuplicate[id] = true
+			}
+		}
+	}
+}
+
 // Messages implement validation.Requests interfaces
 // return custom messages when validation fails.
 func (r *createRequest) Messages() map[string]string {
@@ -78,16 +114,29 @@ func (r *createRequest) Transform() *model.PurchaseInvoice {
 	code, _ := util.CodeGen("code_purchase_invoice", "purchase_invoice")
 
 	pi := &model.PurchaseInvoice{
-		Code:            code,
-		RecognitionDate: r.RecognitionDate,
-		PurchaseOrder:   &model.PurchaseOrder{ID: poID},
-		DueDate:         r.DueDate,
-		TotalAmount:     r.TotalAmount,
-		Note:            r.Note,
-		CreatedBy:       r.SessionData.User,
-		CreatedAt:       time.Now(),
-		DocumentStatus:  "new",
-		BillingAddress:  r.BillingAddress,
+		Code:             code,
+		RecognitionDate:  r.RecognitionDate,
+		PurchaseOrder:    &model.PurchaseOrder{ID: poID},
+		DueDate:          r.DueDate,
+		TotalAmount:      r.TotalAmount,
+		Note:             r.Note,
+		CreatedBy:        r.SessionData.User,
+		CreatedAt:        time.Now(),
+		DocumentStatus:   "new",
+		MatchStatus:      "mismatch", // dihitung ulang oleh MatchPurchaseInvoice setelah item disimpan
+		BillingAddress:   r.BillingAddress,
+		TaxInvoiceNumber: r.TaxInvoiceNumber,
+	}
+
+	for _, i := range r.Items {
+		poiID, _ := common.Decrypt(i.PurchaseOrderItemID)
+		pi.PurchaseInvoiceItems = append(pi.PurchaseInvoiceItems, &model.PurchaseInvoiceItem{
+			PurchaseOrderItem: &model.PurchaseOrderItem{ID: poiID},
+			Quantity:          float32(common.FloatPrecision(float64(i.Quantity), 2)),
+			UnitPrice:         i.UnitPrice,
+			Subtotal:          common.FloatPrecision(float64(i.Quantity)*i.UnitPrice, 0),
+			Note:              i.Note,
+		})
 	}
 
 	return pi
@@ -98,12 +147,13 @@ func (r *createRequest) Transform() *model.PurchaseInvoice {
 // handler function should be bind this with context to matches incoming request
 // data keys to the defined json tag.
 type updateRequest struct {
-	PI              *model.PurchaseInvoice
-	SessionData     *auth.SessionData `json:"-"`
-	RecognitionDate time.Time         `json:"recognition_date" valid:"required"`
-	TotalAmount     float64           `json:"total_amount" valid:"required|gte:0"`
-	Note            string            `json:"note"`
-	TotalDiff       float64
+	PI               *model.PurchaseInvoice
+	SessionData      *auth.SessionData `json:"-"`
+	RecognitionDate  time.Time         `json:"recognition_date" valid:"required"`
+	TotalAmount      float64           `json:"total_amount" valid:"required|gte:0"`
+	Note             string            `json:"note"`
+	TaxInvoiceNumber string            `json:"tax_invoice_number"`
+	TotalDiff        float64
 }
 
 // Validate implement validation.Requests interfaces.
@@ -134,6 +184,10 @@ func (r *updateRequest) Validate() *validation.Output {
 		o.Failure("purchase_invoice", "can't update document")
 	}
 
+	if r.TaxInvoiceNumber != "" && !validTaxInvoiceNumber(r.TaxInvoiceNumber) {
+		o.Failure("tax_invoice_number", "tax_invoice_number must be 16 digits")
+	}
+
 	return o
 }
 
@@ -147,13 +201,47 @@ func (r *updateRequest) Messages() map[string]string {
 func (r *updateRequest) Transform(piID *model.PurchaseInvoice) *model.PurchaseInvoice {
 
 	pi := &model.PurchaseInvoice{
-		ID:              piID.ID,
-		RecognitionDate: r.RecognitionDate,
-		Note:            r.Note,
-		TotalAmount:     r.TotalAmount,
-		UpdatedAt:       time.Now(),
-		UpdatedBy:       r.SessionData.User,
+		ID:               piID.ID,
+		RecognitionDate:  r.RecognitionDate,
+		Note:             r.Note,
+		TotalAmount:      r.TotalAmount,
+		UpdatedAt:        time.Now(),
+		UpdatedBy:        r.SessionData.User,
+		TaxInvoiceNumber: r.TaxInvoiceNumber,
 	}
 
 	return pi
 }
+
+// resolveRequest data struct that stored request data when requesting an resolve purchase invoice mismatch process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type resolveRequest struct {
+	Note     string                         `json:"note" valid:"required"`
+	Session  *auth.SessionData              `json:"-"`
+	Mismatch *model.PurchaseInvoiceMismatch `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *resolveRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.Mismatch.Status != "open" {
+		o.Failure("status", "mismatch already resolved")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *resolveRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// validTaxInvoiceNumber cek nomor faktur pajak dari supplier harus 16 digit,
+// tanda baca seperti titik dan strip diabaikan.
+func validTaxInvoiceNumber(n string) bool {
+	return len(regexp.MustCompile(`\D`).ReplaceAllString(n, "")) == 16
+}
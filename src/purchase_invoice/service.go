@@ -6,6 +6,7 @@ package purchaseInvoice
 
 import (
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/orm"
@@ -23,7 +24,17 @@ func CreatePurchaseInvoice(r *createRequest) (pi *model.PurchaseInvoice, err err
 		po.Save("InvoiceStatus", "DocumentStatus")
 
 		pi = r.Transform()
-		err = pi.Save()
+		if err = pi.Save(); err == nil {
+			for _, item := range pi.PurchaseInvoiceItems {
+				item.PurchaseInvoice = &model.PurchaseInvoice{ID: pi.ID}
+				if err = item.Save(); err != nil {
+					return
+				}
+			}
+
+			pi.PurchaseInvoiceMismatches, err = MatchPurchaseInvoice(pi)
+			generalLedger.AutoPost("purchase_invoice", pi.ID)
+		}
 	}
 
 	return
@@ -61,13 +72,18 @@ func GetPurchaseInvoice(rq *orm.RequestQuery) (m *[]model.PurchaseInvoice, total
 	return nil, total, err
 }
 
-// ShowPurchaseInvoice find a single data Purchase Invoice using field and value condition.
+// ShowPurchaseInvoice find a single data Purchase Invoice using field and value condition,
+// beserta item invoice dan hasil three-way match.
 func ShowPurchaseInvoice(field string, values ...interface{}) (*model.PurchaseInvoice, error) {
 	m := new(model.PurchaseInvoice)
-	o := orm.NewOrm().QueryTable(m)
-	if err := o.Filter(field, values...).Filter("is_deleted", 0).RelatedSel().Limit(1).One(m); err != nil {
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).Filter("is_deleted", 0).RelatedSel().Limit(1).One(m); err != nil {
 		return nil, err
 	}
+
+	o.QueryTable(new(model.PurchaseInvoiceItem)).Filter("purchase_invoice_id", m.ID).RelatedSel("PurchaseOrderItem").OrderBy("id").All(&m.PurchaseInvoiceItems)
+	o.QueryTable(new(model.PurchaseInvoiceMismatch)).Filter("purchase_invoice_id", m.ID).OrderBy("id").All(&m.PurchaseInvoiceMismatches)
+
 	return m, nil
 }
 
@@ -75,8 +91,9 @@ func ShowPurchaseInvoice(field string, values ...interface{}) (*model.PurchaseIn
 func UpdatePurchaseInvoice(r *updateRequest) (pi *model.PurchaseInvoice, err error) {
 
 	pi = r.Transform(r.PI)
-	if err = pi.Save("recognition_date", "note", "updated_by", "updated_at", "total_amount"); err == nil {
-
+	if err = pi.Save("recognition_date", "note", "updated_by", "updated_at", "total_amount", "tax_invoice_number"); err == nil {
+		// total amount yang berubah harus di match ulang terhadap subtotal item
+		pi.PurchaseInvoiceMismatches, err = MatchPurchaseInvoice(pi)
 	}
-	return pi, nil
+	return pi, err
 }
@@ -146,3 +146,164 @@ func TestUpdatePurchaseInvoice(t *testing.T) {
 	assert.NotEqual(t, r.TotalAmount, pi.TotalAmount)
 	assert.Equal(t, r.Note, pix.Note)
 }
+
+// dummyMatchInvoice membuat purchase invoice dengan satu item untuk purchase order item
+// seharga 1000 dan quantity 10 dengan total charge purchase order 10000,
+// receiving yang diterima sebanyak received.
+func dummyMatchInvoice(received float32, qty float32, price float64) (*model.PurchaseInvoice, *model.PurchaseInvoiceItem) {
+	poi := model.DummyPurchaseOrderItem()
+	poi.Quantity = 10
+	poi.UnitPrice = 1000
+	poi.Discount = 0
+	poi.Save()
+
+	poi.PurchaseOrder.TotalPrice = 10000
+	poi.PurchaseOrder.TotalCharge = 10000
+	poi.PurchaseOrder.Save()
+
+	if received > 0 {
+		wri := model.DummyWorkorderReceivingItem()
+		wri.WorkorderReceiving.IsDeleted = 0
+		wri.WorkorderReceiving.Save()
+		wri.PurchaseOrderItem = poi
+		wri.Quantity = received
+		wri.Save()
+	}
+
+	pi := model.DummyPurchaseInvoice()
+	pi.PurchaseOrder = poi.PurchaseOrder
+	pi.IsDeleted = 0
+	pi.TotalAmount = float64(qty) * price
+	pi.MatchStatus = "matched"
+	pi.Save()
+
+	item := &model.PurchaseInvoiceItem{
+		PurchaseInvoice:   pi,
+		PurchaseOrderItem: poi,
+		Quantity:          qty,
+		UnitPrice:         price,
+		Subtotal:          float64(qty) * price,
+	}
+	item.Save()
+
+	return pi, item
+}
+
+func TestMatchPurchaseInvoice(t *testing.T) {
+	o := orm.NewOrm()
+	o.Raw("UPDATE application_setting SET value = '0' WHERE application_setting_name IN ('match_price_tolerance', 'match_quantity_tolerance')").Exec()
+
+	// harga dan quantity sesuai purchase order dan receiving
+	pi, _ := dummyMatchInvoice(10, 10, 1000)
+	m, e := MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Empty(t, m)
+	assert.Equal(t, "matched", pi.MatchStatus)
+	assert.False(t, HasOpenMismatch(pi.ID))
+
+	// harga lebih mahal dan quantity melebihi yang diterima
+	pi, item := dummyMatchInvoice(8, 10, 1100)
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Len(t, m, 2)
+	assert.Equal(t, "price", m[0].MismatchType)
+	assert.Equal(t, float64(1000), m[0].ExpectedValue)
+	assert.Equal(t, float64(10), m[0].Variance)
+	assert.Equal(t, "quantity", m[1].MismatchType)
+	assert.Equal(t, float64(8), m[1].ExpectedValue)
+	assert.Equal(t, float64(10), m[1].InvoicedValue)
+	assert.Equal(t, "mismatch", pi.MatchStatus)
+	assert.True(t, HasOpenMismatch(pi.ID))
+
+	// matching diulang tidak menduplikasi mismatch
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	var total int64
+	o.Raw("SELECT COUNT(*) FROM purchase_invoice_mismatch WHERE purchase_invoice_item_id = ?", item.ID).QueryRow(&total)
+	assert.Equal(t, int64(2), total)
+
+	// selisih masih dalam toleransi
+	o.Raw("UPDATE application_setting SET value = '10' WHERE application_setting_name = 'match_price_tolerance'").Exec()
+	o.Raw("UPDATE application_setting SET value = '25' WHERE application_setting_name = 'match_quantity_tolerance'").Exec()
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Empty(t, m)
+	assert.Equal(t, "matched", pi.MatchStatus)
+
+	o.Raw("UPDATE application_setting SET value = '0' WHERE application_setting_name IN ('match_price_tolerance', 'match_quantity_tolerance')").Exec()
+}
+
+func TestMatchPurchaseInvoiceAmount(t *testing.T) {
+	orm.NewOrm().Raw("UPDATE application_setting SET value = '0' WHERE application_setting_name IN ('match_price_tolerance', 'match_quantity_tolerance')").Exec()
+
+	// total amount invoice tidak sesuai dengan subtotal item
+	pi, _ := dummyMatchInvoice(10, 10, 1000)
+	pi.TotalAmount = 12000
+	pi.Save()
+
+	m, e := MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Len(t, m, 1)
+	assert.Equal(t, "amount", m[0].MismatchType)
+	assert.Equal(t, float64(10000), m[0].ExpectedValue)
+	assert.Equal(t, float64(12000), m[0].InvoicedValue)
+	assert.Equal(t, "mismatch", pi.MatchStatus)
+	assert.True(t, HasOpenMismatch(pi.ID))
+
+	// invoice tanpa item dicocokkan terhadap nilai barang yang diterima dikurangi invoice lain,
+	// diterima 5 x 1000 dan sudah ditagihkan 2000 pada invoice lain
+	other, _ := dummyMatchInvoice(5, 2, 1000)
+	pi = model.DummyPurchaseInvoice()
+	pi.PurchaseOrder = other.PurchaseOrder
+	pi.IsDeleted = 0
+	pi.TotalAmount = 3000
+	pi.Save()
+
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Len(t, m, 0)
+	assert.Equal(t, "matched", pi.MatchStatus)
+
+	// tagihan sebagian tetap matched
+	pi.TotalAmount = 1000
+	pi.Save()
+
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Len(t, m, 0)
+	assert.Equal(t, "matched", pi.MatchStatus)
+
+	// tagihan melebihi nilai barang yang diterima
+	pi.TotalAmount = 5000
+	pi.Save()
+
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Len(t, m, 1)
+	assert.Equal(t, "amount", m[0].MismatchType)
+	assert.Equal(t, float64(3000), m[0].ExpectedValue)
+	assert.Equal(t, float64(5000), m[0].InvoicedValue)
+	assert.Equal(t, "mismatch", pi.MatchStatus)
+}
+
+func TestResolveMismatch(t *testing.T) {
+	orm.NewOrm().Raw("UPDATE application_setting SET value = '0' WHERE application_setting_name IN ('match_price_tolerance', 'match_quantity_tolerance')").Exec()
+
+	pi, _ := dummyMatchInvoice(10, 10, 1200)
+	m, e := MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Len(t, m, 1)
+
+	assert.NoError(t, ResolveMismatch(m[0], "harga naik sesuai konfirmasi supplier", model.DummyUser()))
+	assert.Equal(t, "resolved", m[0].Status)
+	assert.False(t, HasOpenMismatch(pi.ID))
+
+	pi.Read()
+	assert.Equal(t, "resolved", pi.MatchStatus)
+
+	// mismatch yang sudah diresolve tidak dibuka kembali
+	m, e = MatchPurchaseInvoice(pi)
+	assert.NoError(t, e)
+	assert.Empty(t, m)
+	assert.Equal(t, "resolved", pi.MatchStatus)
+}
@@ -0,0 +1,267 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchaseRequisition_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("requisition_approval_level")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	pr := model.DummyPurchaseRequisition()
+	pr.IsDeleted = 0
+	pr.Save()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/purchase-requisition", "GET", http.StatusOK},
+		{"/v1/purchase-requisition/" + common.Encrypt(pr.ID), "GET", http.StatusOK},
+		{"/v1/purchase-requisition/999999", "GET", http.StatusNotFound},
+		{"/v1/purchase-requisition", "POST", http.StatusUnprocessableEntity},
+		{"/v1/purchase-requisition/999999/approve", "PUT", http.StatusNotFound},
+		{"/v1/purchase-requisition/999999/reject", "PUT", http.StatusNotFound},
+		{"/v1/purchase-requisition/999999/convert", "POST", http.StatusNotFound},
+		{"/v1/purchase-requisition/999999/cancel", "PUT", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestPurchaseRequisitionToPurchaseOrder(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+
+	sysadmin := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(sysadmin)
+	token := "Bearer " + sd.Token
+
+	owner := model.DummyUserPriviledgeWithUsergroup(2)
+	sd, _ = auth.Login(owner)
+	ownerToken := "Bearer " + sd.Token
+
+	// level 1 sysadmin untuk semua requisition, level 2 owner untuk requisition >= 1.000.000
+	for i, ug := range []int64{1, 2} {
+		l := model.DummyRequisitionApprovalLevel()
+		l.Usergroup = &model.Usergroup{ID: ug}
+		l.Level = i + 1
+		l.MinAmount = float64(i) * 1000000
+		l.Save()
+	}
+
+	iv1 := model.DummyItemVariant()
+	iv1.IsDeleted = 0
+	iv1.IsArchived = 0
+	iv1.Save()
+
+	iv2 := model.DummyItemVariant()
+	iv2.IsDeleted = 0
+	iv2.IsArchived = 0
+	iv2.Save()
+
+	var pr model.PurchaseRequisition
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.POST("/v1/purchase-requisition").
+		SetJSON(tester.D{
+			"recognition_date": time.Now(),
+			"needed_by_date":   time.Now().AddDate(0, 0, 7),
+			"justification":    "stock menipis menjelang akhir bulan",
+			"purchase_requisition_items": []tester.D{
+				{"item_variant_id": common.Encrypt(iv1.ID), "quantity": 100, "unit_price": 10000},
+				{"item_variant_id": common.Encrypt(iv2.ID), "quantity": 50, "unit_price": 5000},
+			},
+		}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		})
+
+	orm.NewOrm().QueryTable(new(model.PurchaseRequisition)).OrderBy("-id").Limit(1).One(&pr)
+	assert.Equal(t, "pending_approval", pr.DocumentStatus)
+	assert.Equal(t, float64(1250000), pr.TotalAmount)
+
+	var items []*model.PurchaseRequisitionItem
+	orm.NewOrm().QueryTable(new(model.PurchaseRequisitionItem)).Filter("purchase_requisition_id", pr.ID).OrderBy("id").All(&items)
+	assert.Len(t, items, 2)
+
+	path := "/v1/purchase-requisition/" + common.Encrypt(pr.ID)
+
+	// requisition yang belum approved tidak bisa dibuat purchase order
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.POST(path+"/convert").
+		SetJSON(tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "purchase_orders": []tester.D{}}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+
+	var approvals = []struct {
+		token    string
+		expected int
+	}{
+		// level 1 harus approve terlebih dahulu oleh sysadmin
+		{ownerToken, http.StatusUnprocessableEntity},
+		{token, http.StatusOK},
+		// level 2 approve oleh owner
+		{token, http.StatusUnprocessableEntity},
+		{ownerToken, http.StatusOK},
+		{ownerToken, http.StatusUnprocessableEntity},
+	}
+	for _, tes := range approvals {
+		ng.SetHeader(tester.H{"Authorization": tes.token})
+		ng.PUT(path+"/approve").
+			SetJSON(tester.D{"note": "ok"}).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, res.Body.String())
+			})
+	}
+
+	pr.Read()
+	assert.Equal(t, "approved", pr.DocumentStatus)
+
+	supplier1 := model.DummyPartnership()
+	supplier1.PartnershipType = "supplier"
+	supplier1.IsDeleted = 0
+	supplier1.Save()
+
+	supplier2 := model.DummyPartnership()
+	supplier2.PartnershipType = "supplier"
+	supplier2.IsDeleted = 0
+	supplier2.Save()
+
+	convert := func(supplier *model.Partnership, item *model.PurchaseRequisitionItem, qty float32) tester.D {
+		return tester.D{
+			"supplier_id": common.Encrypt(supplier.ID),
+			"purchase_order_items": []tester.D{
+				{"purchase_requisition_item_id": common.Encrypt(item.ID), "quantity": qty, "unit_price": 9500},
+			},
+		}
+	}
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		// quantity melebihi quantity requisition
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "purchase_orders": []tester.D{convert(supplier1, items[0], 60), convert(supplier2, items[0], 50)}}, http.StatusUnprocessableEntity},
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "purchase_orders": []tester.D{convert(supplier1, &model.PurchaseRequisitionItem{ID: 999999}, 10)}}, http.StatusUnprocessableEntity},
+		// item pertama dipecah ke dua supplier
+		{tester.D{"recognition_date": time.Now(), "eta_date": time.Now(), "purchase_orders": []tester.D{convert(supplier1, items[0], 60), convert(supplier2, items[0], 40)}}, http.StatusOK},
+	}
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST(path+"/convert").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	pr.Read()
+	assert.Equal(t, "partial", pr.DocumentStatus)
+
+	var orders []*model.PurchaseOrder
+	orm.NewOrm().QueryTable(new(model.PurchaseOrder)).Filter("purchase_requisition_id", pr.ID).All(&orders)
+	assert.Len(t, orders, 2)
+
+	// requisition yang sudah memiliki purchase order tidak bisa dicancel
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT(path+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		})
+
+	// purchase order menampilkan link ke requisition
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.GET("/v1/purchase-order/"+common.Encrypt(orders[0].ID)).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Contains(t, res.Body.String(), common.Encrypt(pr.ID))
+		})
+}
+
+func TestRejectPurchaseRequisition(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	l := model.DummyRequisitionApprovalLevel()
+	l.Usergroup = &model.Usergroup{ID: 1}
+	l.Level = 1
+	l.MinAmount = 0
+	l.Save()
+
+	pr := model.DummyPurchaseRequisition()
+	pr.DocumentStatus = "pending_approval"
+	pr.IsDeleted = 0
+	pr.Save()
+
+	a := model.DummyPurchaseRequisitionApproval()
+	a.PurchaseRequisition = pr
+	a.Usergroup = l.Usergroup
+	a.Level = 1
+	a.Status = "pending"
+	a.DecidedBy = nil
+	a.Save()
+
+	path := "/v1/purchase-requisition/" + common.Encrypt(pr.ID)
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT(path+"/reject").
+		SetJSON(tester.D{"note": "tidak perlu"}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		})
+
+	pr.Read()
+	assert.Equal(t, "rejected", pr.DocumentStatus)
+
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT(path+"/cancel").
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		})
+
+	pr.Read()
+	assert.Equal(t, "cancelled", pr.DocumentStatus)
+}
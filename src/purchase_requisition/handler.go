@@ -0,0 +1,170 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchaseRequisition
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for purchase requisition.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("purchase_requisition_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("purchase_requisition_read"))
+	r.POST("", h.create, auth.CheckPrivilege("purchase_requisition_create"))
+	r.PUT("/:id/approve", h.approve, auth.CheckPrivilege("purchase_requisition_approve"))
+	r.PUT("/:id/reject", h.reject, auth.CheckPrivilege("purchase_requisition_approve"))
+	r.POST("/:id/convert", h.convert, auth.CheckPrivilege("purchase_requisition_convert"))
+	r.PUT("/:id/cancel", h.cancel, auth.CheckPrivilege("purchase_requisition_cancel"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.PurchaseRequisition
+	if data, total, e = GetPurchaseRequisitions(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to handle get http method.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.PurchaseRequisition
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowPurchaseRequisition("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk membuat purchase requisition beserta urutan approval sesuai total requisition.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r createRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = CreatePurchaseRequisition(m); e == nil {
+				m, _ = ShowPurchaseRequisition("id", m.ID)
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// approve endpoint untuk approve level approval purchase requisition yang sedang pending.
+func (h *Handler) approve(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r approvalRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.PurchaseRequisition, e = ShowPurchaseRequisition("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = ApprovePurchaseRequisition(r.PurchaseRequisition, r.Note, r.Session.User); e == nil {
+						ctx.Data(r.PurchaseRequisition)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// reject endpoint untuk reject purchase requisition pada level approval yang sedang pending.
+func (h *Handler) reject(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r approvalRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.PurchaseRequisition, e = ShowPurchaseRequisition("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = RejectPurchaseRequisition(r.PurchaseRequisition, r.Note, r.Session.User); e == nil {
+						ctx.Data(r.PurchaseRequisition)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// convert endpoint untuk membuat satu atau lebih purchase order dari purchase requisition yang sudah approved.
+func (h *Handler) convert(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r convertRequest
+	var orders []*model.PurchaseOrder
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.PurchaseRequisition, e = ShowPurchaseRequisition("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if orders, e = ConvertPurchaseRequisition(r.PurchaseRequisition, r.Transform()); e == nil {
+						ctx.Data(orders, int64(len(orders)))
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// cancel endpoint untuk membatalkan purchase requisition yang belum dibuat purchase order.
+func (h *Handler) cancel(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r cancelRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.PurchaseRequisition, e = ShowPurchaseRequisition("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = CancelPurchaseRequisition(r.PurchaseRequisition, r.Session.User); e == nil {
+						ctx.Data(r.PurchaseRequisition)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,294 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchaseRequisition
+
+import (
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// createRequest data struct that stored request data when requesting an create purchase requisition process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	RecognitionDate          time.Time                `json:"recognition_date" valid:"required"`
+	NeededByDate             time.Time                `json:"needed_by_date" valid:"required"`
+	Justification            string                   `json:"justification" valid:"required"`
+	Note                     string                   `json:"note"`
+	PurchaseRequisitionItems []requisitionItemRequest `json:"purchase_requisition_items" valid:"required"`
+	Session                  *auth.SessionData        `json:"-"`
+}
+
+type requisitionItemRequest struct {
+	ItemVariantID string  `json:"item_variant_id" valid:"required"`
+	Quantity      float32 `json:"quantity" valid:"required|gt:0"`
+	UnitPrice     float64 `json:"unit_price" valid:"gte:0"`
+	Note          string  `json:"note"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if !r.NeededByDate.IsZero() && r.NeededByDate.Before(r.RecognitionDate) {
+		o.Failure("needed_by_date", "needed by date cannot be earlier than recognition date")
+	}
+
+	checkDuplicate := make(map[int64]bool)
+	for i, item := range r.PurchaseRequisitionItems {
+		if ivID, e := common.Decrypt(item.ItemVariantID); e != nil {
+			o.Failure(fmt.Sprintf("purchase_requisition_items.%d.item_variant_id.invalid", i), "item_variant_id not valid")
+		} else {
+			iv := &model.ItemVariant{ID: ivID}
+			if e = iv.Read(); e != nil || iv.IsArchived == int8(1) || iv.IsDeleted == int8(1) {
+				o.Failure(fmt.Sprintf("purchase_requisition_items.%d.item_variant_id.invalid", i), "item_variant_id is already archived or does not exists")
+			} else if checkDuplicate[ivID] {
+				o.Failure(fmt.Sprintf("purchase_requisition_items.%d.item_variant_id.invalid", i), "item variant id duplicate")
+			} else {
+				checkDuplicate[ivID] = true
+			}
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.PurchaseRequisition {
+	code, _ := util.CodeGen("code_purchase_requisition", "purchase_requisition")
+
+	m := &model.PurchaseRequisition{
+		Code:            code,
+		RecognitionDate: r.RecognitionDate,
+		NeededByDate:    r.NeededByDate,
+		Justification:   r.Justification,
+		Note:            r.Note,
+		CreatedBy:       r.Session.User,
+		CreatedAt:       time.Now(),
+	}
+
+	for _, i := range r.PurchaseRequisitionItems {
+		ivID, _ := common.Decrypt(i.ItemVariantID)
+		item := &model.PurchaseRequisitionItem{
+			ItemVariant: &model.ItemVariant{ID: ivID},
+			Quantity:    float32(common.FloatPrecision(float64(i.Quantity), 2)),
+			UnitPrice:   i.UnitPrice,
+			Subtotal:    common.FloatPrecision(float64(i.Quantity)*i.UnitPrice, 0),
+			Note:        i.Note,
+		}
+
+		m.TotalAmount += item.Subtotal
+		m.PurchaseRequisitionItems = append(m.PurchaseRequisitionItems, item)
+	}
+
+	return m
+}
+
+// approvalRequest data struct that stored request data when requesting an approve or reject purchase requisition process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type approvalRequest struct {
+	Note                string                     `json:"note"`
+	Session             *auth.SessionData          `json:"-"`
+	PurchaseRequisition *model.PurchaseRequisition `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *approvalRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.PurchaseRequisition.DocumentStatus != "pending_approval" {
+		o.Failure("document_status", "purchase requisition is not waiting for approval")
+	} else if m, e := pendingApproval(r.PurchaseRequisition.ID); e != nil {
+		o.Failure("document_status", "purchase requisition is not waiting for approval")
+	} else {
+		// hanya usergroup pada level yang sedang pending yang bisa approve
+		user := &model.User{ID: r.Session.User.ID}
+		if e = user.Read(); e != nil || user.Usergroup == nil || user.Usergroup.ID != m.Usergroup.ID {
+			o.Failure("usergroup", fmt.Sprintf("purchase requisition is waiting for approval level %d", m.Level))
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *approvalRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// convertRequest data struct that stored request data when requesting an convert purchase requisition into purchase order process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type convertRequest struct {
+	RecognitionDate     time.Time                  `json:"recognition_date" valid:"required"`
+	EtaDate             time.Time                  `json:"eta_date" valid:"required"`
+	PurchaseOrders      []convertOrderRequest      `json:"purchase_orders" valid:"required"`
+	Session             *auth.SessionData          `json:"-"`
+	PurchaseRequisition *model.PurchaseRequisition `json:"-"`
+}
+
+type convertOrderRequest struct {
+	SupplierID   string               `json:"supplier_id" valid:"required"`
+	Tax          float32              `json:"tax" valid:"gte:0|lte:100"`
+	ShipmentCost float64              `json:"shipment_cost" valid:"gte:0"`
+	Note         string               `json:"note"`
+	Items        []convertItemRequest `json:"purchase_order_items" valid:"required"`
+}
+
+type convertItemRequest struct {
+	PurchaseRequisitionItemID string  `json:"purchase_requisition_item_id" valid:"required"`
+	Quantity                  float32 `json:"quantity" valid:"required|gt:0"`
+	UnitPrice                 float64 `json:"unit_price" valid:"required|gt:0"`
+	Discount                  float32 `json:"discount" valid:"gte:0|lte:100"`
+	Note                      string  `json:"note"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *convertRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.PurchaseRequisition.DocumentStatus != "approved" && r.PurchaseRequisition.DocumentStatus != "partial" {
+		o.Failure("document_status", "purchase requisition should be approved")
+		return o
+	}
+
+	items := make(map[int64]*model.PurchaseRequisitionItem)
+	for _, i := range r.PurchaseRequisition.PurchaseRequisitionItems {
+		items[i.ID] = i
+	}
+
+	ordered := make(map[int64]float32)
+	for x, po := range r.PurchaseOrders {
+		if supID, e := common.Decrypt(po.SupplierID); e != nil {
+			o.Failure(fmt.Sprintf("purchase_orders.%d.supplier_id.invalid", x), "supplier_id is not valid")
+		} else {
+			supplier := &model.Partnership{ID: supID}
+			if e = supplier.Read(); e != nil || supplier.PartnershipType != "supplier" || supplier.IsDeleted == int8(1) {
+				o.Failure(fmt.Sprintf("purchase_orders.%d.supplier_id.invalid", x), "supplier_id doesn't exist")
+			}
+		}
+
+		checkDuplicate := make(map[int64]bool)
+		for y, item := range po.Items {
+			field := fmt.Sprintf("purchase_orders.%d.purchase_order_items.%d.purchase_requisition_item_id.invalid", x, y)
+			if id, e := common.Decrypt(item.PurchaseRequisitionItemID); e != nil {
+				o.Failure(field, "purchase_requisition_item_id is not valid")
+			} else if ri, ok := items[id]; !ok {
+				o.Failure(field, "item is not part of the purchase requisition")
+			} else if checkDuplicate[id] {
+				o.Failure(field, "purchase_requisition_item_id duplicate")
+			} else {
+				checkDuplicate[id] = true
+				ordered[id] += item.Quantity
+				if ordered[id] > ri.Quantity-ri.OrderedQuantity {
+					o.Failure(fmt.Sprintf("purchase_orders.%d.purchase_order_items.%d.quantity.invalid", x, y), "quantity is greater than the open quantity of the purchase requisition")
+				}
+			}
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *convertRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into purchase order per supplier,
+// code purchase order digenerate ketika purchase order disimpan.
+func (r *convertRequest) Transform() (orders []*model.PurchaseOrder) {
+	items := make(map[int64]*model.PurchaseRequisitionItem)
+	for _, i := range r.PurchaseRequisition.PurchaseRequisitionItems {
+		items[i.ID] = i
+	}
+
+	for _, req := range r.PurchaseOrders {
+		supID, _ := common.Decrypt(req.SupplierID)
+		po := &model.PurchaseOrder{
+			Supplier:        &model.Partnership{ID: supID},
+			RecognitionDate: r.RecognitionDate,
+			EtaDate:         r.EtaDate,
+			Tax:             float32(common.FloatPrecision(float64(req.Tax), 2)),
+			ShipmentCost:    common.FloatPrecision(req.ShipmentCost, 0),
+			Note:            req.Note,
+			DocumentStatus:  "new",
+			InvoiceStatus:   "new",
+			ReceivingStatus: "new",
+			CreatedBy:       r.Session.User,
+			CreatedAt:       time.Now(),
+		}
+
+		var total float64
+		for _, i := range req.Items {
+			riID, _ := common.Decrypt(i.PurchaseRequisitionItemID)
+			// subtotal = quantity * unit_price * 1 – discount
+			discount := ((i.UnitPrice * float64(i.Quantity)) * float64(i.Discount)) / float64(100)
+			subtotal := (float64(i.Quantity) * i.UnitPrice) - discount
+
+			po.PurchaseOrderItems = append(po.PurchaseOrderItems, &model.PurchaseOrderItem{
+				ItemVariant:             items[riID].ItemVariant,
+				PurchaseRequisitionItem: &model.PurchaseRequisitionItem{ID: riID},
+				Quantity:                float32(common.FloatPrecision(float64(i.Quantity), 2)),
+				UnitPrice:               i.UnitPrice,
+				Discount:                i.Discount,
+				Subtotal:                common.FloatPrecision(subtotal, 0),
+				Note:                    i.Note,
+			})
+			total += subtotal
+		}
+
+		po.TaxAmount = common.FloatPrecision(total*float64(req.Tax)/float64(100), 0)
+		po.TotalCharge = common.FloatPrecision(total+po.TaxAmount+po.ShipmentCost, 0)
+		orders = append(orders, po)
+	}
+
+	return
+}
+
+// cancelRequest data struct that stored request data when requesting an cancel purchase requisition process.
+type cancelRequest struct {
+	Session             *auth.SessionData          `json:"-"`
+	PurchaseRequisition *model.PurchaseRequisition `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *cancelRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	switch r.PurchaseRequisition.DocumentStatus {
+	case "cancelled":
+		o.Failure("document_status", "purchase requisition already cancelled")
+	case "partial", "finished":
+		o.Failure("document_status", "purchase requisition already has purchase order, cancel the purchase order first")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *cancelRequest) Messages() map[string]string {
+	return map[string]string{}
+}
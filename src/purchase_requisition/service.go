@@ -0,0 +1,176 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchaseRequisition
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/purchase"
+	"git.qasico.com/mj/api/src/util"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// GetPurchaseRequisitions get all data purchase requisition that matched with query request parameters.
+// returning slices of purchase requisition, total data without limit and error.
+func GetPurchaseRequisitions(rq *orm.RequestQuery) (m *[]model.PurchaseRequisition, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.PurchaseRequisition))
+
+	// get total data
+	if total, err = q.Filter("is_deleted", int8(0)).Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.PurchaseRequisition
+	if _, err = q.Filter("is_deleted", int8(0)).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowPurchaseRequisition find a single data purchase requisition using field and value condition,
+// beserta item, urutan approval dan purchase order yang dibuat dari requisition.
+func ShowPurchaseRequisition(field string, values ...interface{}) (*model.PurchaseRequisition, error) {
+	m := new(model.PurchaseRequisition)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).Filter("is_deleted", int8(0)).RelatedSel().Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.PurchaseRequisitionItem)).Filter("purchase_requisition_id", m.ID).RelatedSel("ItemVariant").OrderBy("id").All(&m.PurchaseRequisitionItems)
+	o.QueryTable(new(model.PurchaseRequisitionApproval)).Filter("purchase_requisition_id", m.ID).RelatedSel("Usergroup", "DecidedBy").OrderBy("level").All(&m.PurchaseRequisitionApprovals)
+	o.QueryTable(new(model.PurchaseOrder)).Filter("purchase_requisition_id", m.ID).Filter("is_deleted", int8(0)).RelatedSel("Supplier").OrderBy("id").All(&m.PurchaseOrders)
+
+	return m, nil
+}
+
+// approvalLevels mengambil urutan level approval yang dibutuhkan sesuai total requisition.
+func approvalLevels(amount float64) (m []*model.RequisitionApprovalLevel, e error) {
+	_, e = orm.NewOrm().QueryTable(new(model.RequisitionApprovalLevel)).Filter("min_amount__lte", amount).OrderBy("level").All(&m)
+
+	return
+}
+
+// pendingApproval mengambil approval requisition dengan level terkecil yang masih pending.
+func pendingApproval(prID int64) (*model.PurchaseRequisitionApproval, error) {
+	m := new(model.PurchaseRequisitionApproval)
+	o := orm.NewOrm()
+	if e := o.QueryTable(m).Filter("purchase_requisition_id", prID).Filter("status", "pending").OrderBy("level").Limit(1).One(m); e != nil {
+		return nil, e
+	}
+
+	return m, nil
+}
+
+// CreatePurchaseRequisition menyimpan purchase requisition beserta item dan urutan approval,
+// requisition yang tidak membutuhkan approval langsung berstatus approved.
+func CreatePurchaseRequisition(pr *model.PurchaseRequisition) (e error) {
+	var levels []*model.RequisitionApprovalLevel
+	if levels, e = approvalLevels(pr.TotalAmount); e != nil {
+		return
+	}
+
+	pr.DocumentStatus = "pending_approval"
+	if len(levels) == 0 {
+		pr.DocumentStatus = "approved"
+	}
+
+	if e = pr.Save(); e == nil {
+		for _, item := range pr.PurchaseRequisitionItems {
+			item.PurchaseRequisition = &model.PurchaseRequisition{ID: pr.ID}
+			if e = item.Save(); e != nil {
+				return
+			}
+		}
+
+		for _, l := range levels {
+			m := &model.PurchaseRequisitionApproval{
+				PurchaseRequisition: &model.PurchaseRequisition{ID: pr.ID},
+				Usergroup:           l.Usergroup,
+				Level:               l.Level,
+				Status:              "pending",
+			}
+			if e = m.Save(); e != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// ApprovePurchaseRequisition approve level approval yang sedang pending,
+// requisition berstatus approved apabila seluruh level sudah approve.
+func ApprovePurchaseRequisition(pr *model.PurchaseRequisition, note string, user *model.User) (e error) {
+	var m *model.PurchaseRequisitionApproval
+	if m, e = pendingApproval(pr.ID); e == nil {
+		m.Status = "approved"
+		m.Note = note
+		m.DecidedBy = user
+		m.DecidedAt = time.Now()
+		if e = m.Save("Status", "Note", "DecidedBy", "DecidedAt"); e == nil {
+			if _, err := pendingApproval(pr.ID); err != nil {
+				pr.DocumentStatus = "approved"
+				pr.UpdatedBy = user
+				pr.UpdatedAt = time.Now()
+				e = pr.Save("DocumentStatus", "UpdatedBy", "UpdatedAt")
+			}
+		}
+	}
+
+	return
+}
+
+// RejectPurchaseRequisition reject level approval yang sedang pending,
+// requisition yang direject tidak bisa dibuat purchase order.
+func RejectPurchaseRequisition(pr *model.PurchaseRequisition, note string, user *model.User) (e error) {
+	var m *model.PurchaseRequisitionApproval
+	if m, e = pendingApproval(pr.ID); e == nil {
+		m.Status = "rejected"
+		m.Note = note
+		m.DecidedBy = user
+		m.DecidedAt = time.Now()
+		if e = m.Save("Status", "Note", "DecidedBy", "DecidedAt"); e == nil {
+			pr.DocumentStatus = "rejected"
+			pr.UpdatedBy = user
+			pr.UpdatedAt = time.Now()
+			e = pr.Save("DocumentStatus", "UpdatedBy", "UpdatedAt")
+		}
+	}
+
+	return
+}
+
+// ConvertPurchaseRequisition membuat purchase order dari requisition yang sudah approved,
+// setiap purchase order dapat berasal dari supplier yang berbeda.
+func ConvertPurchaseRequisition(pr *model.PurchaseRequisition, orders []*model.PurchaseOrder) (res []*model.PurchaseOrder, e error) {
+	for _, po := range orders {
+		// code digenerate satu per satu karena mengambil code terakhir purchase order
+		po.Code, _ = util.CodeGen("code_purchase_order", "purchase_order")
+		po.PurchaseRequisition = &model.PurchaseRequisition{ID: pr.ID}
+		if po, e = purchase.CreatePurchaseOrder(po); e != nil {
+			return nil, e
+		}
+		res = append(res, po)
+	}
+
+	e = purchase.CalculatePurchaseRequisition(pr.ID)
+
+	return
+}
+
+// CancelPurchaseRequisition membatalkan purchase requisition yang belum dibuat purchase order.
+func CancelPurchaseRequisition(pr *model.PurchaseRequisition, user *model.User) (e error) {
+	pr.DocumentStatus = "cancelled"
+	pr.UpdatedBy = user
+	pr.UpdatedAt = time.Now()
+
+	return pr.Save("DocumentStatus", "UpdatedBy", "UpdatedAt")
+}
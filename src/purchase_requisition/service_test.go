@@ -0,0 +1,183 @@
+package purchaseRequisition
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/purchase"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func dummyApprovalLevel(usergroup int64, level int, amount float64) *model.RequisitionApprovalLevel {
+	m := model.DummyRequisitionApprovalLevel()
+	m.Usergroup = &model.Usergroup{ID: usergroup}
+	m.Level = level
+	m.MinAmount = amount
+	m.Save()
+
+	return m
+}
+
+func dummyRequisition(quantity float32, price float64) *model.PurchaseRequisition {
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save()
+
+	return &model.PurchaseRequisition{
+		Code:            "RQ-TEST",
+		RecognitionDate: time.Now(),
+		NeededByDate:    time.Now().AddDate(0, 0, 7),
+		Justification:   "stock menipis",
+		TotalAmount:     float64(quantity) * price,
+		CreatedBy:       model.DummyUser(),
+		CreatedAt:       time.Now(),
+		PurchaseRequisitionItems: []*model.PurchaseRequisitionItem{
+			{ItemVariant: iv, Quantity: quantity, UnitPrice: price, Subtotal: float64(quantity) * price},
+		},
+	}
+}
+
+func TestGetPurchaseRequisitionsNoData(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM purchase_requisition").Exec()
+
+	m, total, e := GetPurchaseRequisitions(&orm.RequestQuery{})
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, m)
+	assert.NoError(t, e)
+}
+
+func TestShowPurchaseRequisition(t *testing.T) {
+	_, e := ShowPurchaseRequisition("id", 999999)
+	assert.Error(t, e, "Response should be error, beacuse there are no data yet.")
+
+	pri := model.DummyPurchaseRequisitionItem()
+	pri.PurchaseRequisition.IsDeleted = 0
+	pri.PurchaseRequisition.Save()
+
+	m, e := ShowPurchaseRequisition("id", pri.PurchaseRequisition.ID)
+	assert.NoError(t, e, "Data should be exists.")
+	assert.Len(t, m.PurchaseRequisitionItems, 1)
+}
+
+func TestCreatePurchaseRequisitionWithoutApproval(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+
+	pr := dummyRequisition(10, 1000)
+	e := CreatePurchaseRequisition(pr)
+	assert.NoError(t, e)
+	assert.Equal(t, "approved", pr.DocumentStatus)
+
+	_, e = pendingApproval(pr.ID)
+	assert.Error(t, e, "tidak ada approval yang pending")
+}
+
+func TestApprovePurchaseRequisition(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+	dummyApprovalLevel(1, 1, 0)
+	dummyApprovalLevel(2, 2, 1000000)
+
+	// total kecil hanya membutuhkan approval level 1
+	small := dummyRequisition(10, 1000)
+	assert.NoError(t, CreatePurchaseRequisition(small))
+	assert.Equal(t, "pending_approval", small.DocumentStatus)
+
+	assert.NoError(t, ApprovePurchaseRequisition(small, "ok", model.DummyUser()))
+	small.Read()
+	assert.Equal(t, "approved", small.DocumentStatus)
+
+	// total besar membutuhkan approval level 1 dan level 2 secara berurutan
+	big := dummyRequisition(100, 20000)
+	assert.NoError(t, CreatePurchaseRequisition(big))
+
+	m, e := pendingApproval(big.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, 1, m.Level)
+
+	assert.NoError(t, ApprovePurchaseRequisition(big, "ok", model.DummyUser()))
+	big.Read()
+	assert.Equal(t, "pending_approval", big.DocumentStatus)
+
+	m, e = pendingApproval(big.ID)
+	assert.NoError(t, e)
+	assert.Equal(t, 2, m.Level)
+	assert.Equal(t, int64(2), m.Usergroup.ID)
+
+	assert.NoError(t, ApprovePurchaseRequisition(big, "ok", model.DummyUser()))
+	big.Read()
+	assert.Equal(t, "approved", big.DocumentStatus)
+}
+
+func TestRejectPurchaseRequisition(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+	dummyApprovalLevel(1, 1, 0)
+
+	pr := dummyRequisition(10, 1000)
+	assert.NoError(t, CreatePurchaseRequisition(pr))
+
+	assert.NoError(t, RejectPurchaseRequisition(pr, "tidak perlu", model.DummyUser()))
+	pr.Read()
+	assert.Equal(t, "rejected", pr.DocumentStatus)
+}
+
+func TestConvertPurchaseRequisition(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+
+	pr := dummyRequisition(10, 1000)
+	assert.NoError(t, CreatePurchaseRequisition(pr))
+	item := pr.PurchaseRequisitionItems[0]
+
+	order := func(qty float32) *model.PurchaseOrder {
+		supplier := model.DummyPartnership()
+		supplier.PartnershipType = "supplier"
+		supplier.Save()
+
+		return &model.PurchaseOrder{
+			Supplier:        supplier,
+			RecognitionDate: time.Now(),
+			EtaDate:         time.Now(),
+			DocumentStatus:  "new",
+			InvoiceStatus:   "new",
+			ReceivingStatus: "new",
+			TotalCharge:     float64(qty) * 1000,
+			CreatedBy:       model.DummyUser(),
+			CreatedAt:       time.Now(),
+			PurchaseOrderItems: []*model.PurchaseOrderItem{
+				{ItemVariant: item.ItemVariant, PurchaseRequisitionItem: item, Quantity: qty, UnitPrice: 1000, Subtotal: float64(qty) * 1000},
+			},
+		}
+	}
+
+	// sebagian quantity dipesan ke dua supplier
+	orders, e := ConvertPurchaseRequisition(pr, []*model.PurchaseOrder{order(3), order(4)})
+	assert.NoError(t, e)
+	assert.Len(t, orders, 2)
+
+	pr.Read()
+	assert.Equal(t, "partial", pr.DocumentStatus)
+	item.Read()
+	assert.Equal(t, float32(7), item.OrderedQuantity)
+
+	var total int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM purchase_order WHERE purchase_requisition_id = ?", pr.ID).QueryRow(&total)
+	assert.Equal(t, int64(2), total)
+
+	// sisa quantity dipesan
+	_, e = ConvertPurchaseRequisition(pr, []*model.PurchaseOrder{order(3)})
+	assert.NoError(t, e)
+	pr.Read()
+	assert.Equal(t, "finished", pr.DocumentStatus)
+
+	// purchase order dicancel, quantity kembali open
+	orders[0].DocumentStatus = "cancelled"
+	orders[0].Save("DocumentStatus")
+	assert.NoError(t, purchase.CalculatePurchaseRequisition(pr.ID))
+
+	pr.Read()
+	assert.Equal(t, "partial", pr.DocumentStatus)
+	item.Read()
+	assert.Equal(t, float32(7), item.OrderedQuantity)
+}
@@ -13,6 +13,7 @@ import (
 
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/inventory"
 	"git.qasico.com/mj/api/src/purchase_return"
 	"git.qasico.com/mj/api/test"
 
@@ -86,6 +87,9 @@ func TestHandler_URLMappingPOSTCreatePurchaseReturn(t *testing.T) {
 	woReceiveItem2.PurchaseOrderItem = dpOrderItem
 	woReceiveItem2.Save()
 
+	// batch stock yang masuk dari receiving
+	batch, _ := inventory.FifoStockIn(dpOrderItem.ItemVariant, dpOrderItem.UnitPrice, float32(45), "workorder_receiving", uint64(woReceive1.ID))
+
 	// setting body
 	scenario := tester.D{
 		"recognition_date":  time.Now(),
@@ -93,6 +97,7 @@ func TestHandler_URLMappingPOSTCreatePurchaseReturn(t *testing.T) {
 		"purchase_return_item": []tester.D{
 			{
 				"purchase_order_item_id": common.Encrypt(dpOrderItem.ID),
+				"item_variant_stock_id":  common.Encrypt(batch.ID),
 				"quantity":               float32(45),
 				"unit_price":             dpOrderItem.UnitPrice,
 				"discount":               dpOrderItem.Discount,
@@ -156,6 +161,9 @@ func TestHandler_URLMappingPOSTCreatePurchaseReturn1(t *testing.T) {
 	woReceiveItem.PurchaseOrderItem = dpOrderItem
 	woReceiveItem.Save()
 
+	// batch stock yang masuk dari receiving
+	batch, _ := inventory.FifoStockIn(dpOrderItem.ItemVariant, dpOrderItem.UnitPrice, float32(45), "workorder_receiving", uint64(woReceive.ID))
+
 	// setting body
 	scenario := tester.D{
 		"recognition_date":  time.Now(),
@@ -163,6 +171,7 @@ func TestHandler_URLMappingPOSTCreatePurchaseReturn1(t *testing.T) {
 		"purchase_return_item": []tester.D{
 			{
 				"purchase_order_item_id": common.Encrypt(dpOrderItem.ID),
+				"item_variant_stock_id":  common.Encrypt(batch.ID),
 				"quantity":               float32(40),
 				"unit_price":             dpOrderItem.UnitPrice,
 				"discount":               dpOrderItem.Discount,
@@ -184,6 +193,108 @@ func TestHandler_URLMappingPOSTCreatePurchaseReturn1(t *testing.T) {
 	})
 }
 
+// TestHandler_URLMappingPOSTCreatePurchaseReturnBatch quantity return tidak boleh melebihi sisa batch receiving
+func TestHandler_URLMappingPOSTCreatePurchaseReturnBatch(t *testing.T) {
+	// melakukan proses login
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	dporder := model.DummyPurchaseOrder()
+	dporder.Supplier = model.DummyPartnership()
+	dporder.IsDeleted = int8(0)
+	dporder.DocumentStatus = "active"
+	dporder.TaxAmount = float64(0)
+	dporder.DiscountAmount = float64(0)
+	dporder.ShipmentCost = float64(0)
+	dporder.TotalCharge = float64(45000)
+	dporder.Save()
+
+	dpOrderItem := model.DummyPurchaseOrderItem()
+	dpOrderItem.UnitPrice = float64(1000)
+	dpOrderItem.Discount = float32(0)
+	dpOrderItem.Quantity = float32(45)
+	dpOrderItem.PurchaseOrder = dporder
+	dpOrderItem.Save()
+
+	woReceive := model.DummyWorkorderReceiving()
+	woReceive.PurchaseOrder = dporder
+	woReceive.IsDeleted = 0
+	woReceive.DocumentStatus = "finished"
+	woReceive.Save()
+
+	woReceiveItem := model.DummyWorkorderReceivingItem()
+	woReceiveItem.Quantity = float32(45)
+	woReceiveItem.WorkorderReceiving = woReceive
+	woReceiveItem.PurchaseOrderItem = dpOrderItem
+	woReceiveItem.Save()
+
+	// batch receiving yang sebagian sudah terjual, sisa 30
+	batch, _ := inventory.FifoStockIn(dpOrderItem.ItemVariant, dpOrderItem.UnitPrice, float32(30), "workorder_receiving", uint64(woReceive.ID))
+
+	// batch yang tidak berasal dari receiving purchase order
+	other, _ := inventory.FifoStockIn(dpOrderItem.ItemVariant, dpOrderItem.UnitPrice, float32(30), "stockopname", uint64(1))
+
+	scenario := func(batchID int64, qty float32) tester.D {
+		return tester.D{
+			"recognition_date":  time.Now(),
+			"purchase_order_id": common.Encrypt(dporder.ID),
+			"purchase_return_item": []tester.D{
+				{
+					"purchase_order_item_id": common.Encrypt(dpOrderItem.ID),
+					"item_variant_stock_id":  common.Encrypt(batchID),
+					"quantity":               qty,
+					"unit_price":             dpOrderItem.UnitPrice,
+					"discount":               dpOrderItem.Discount,
+					"subtotal":               dpOrderItem.UnitPrice * float64(qty),
+				},
+			},
+			"tax_amount":      dporder.TaxAmount,
+			"discount_amount": dporder.DiscountAmount,
+			"shipment_cost":   dporder.ShipmentCost,
+			"total_amount":    dpOrderItem.UnitPrice * float64(qty),
+		}
+	}
+
+	var create = []struct {
+		req      tester.D
+		expected int
+	}{
+		{scenario(other.ID, 10), http.StatusUnprocessableEntity},
+		{scenario(batch.ID, 40), http.StatusUnprocessableEntity},
+		{scenario(batch.ID, 30), http.StatusOK},
+	}
+
+	ng := tester.New()
+	for _, tes := range create {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.POST("/v1/purchase-return").SetJSON(tes.req).Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+		})
+	}
+
+	batch.Read()
+	assert.Equal(t, float32(0), batch.AvailableStock, "stock batch keluar sesuai quantity return")
+
+	var pr model.PurchaseReturn
+	orm.NewOrm().QueryTable(new(model.PurchaseReturn)).Filter("purchase_order_id", dporder.ID).Filter("is_deleted", 0).One(&pr)
+
+	var log model.ItemVariantStockLog
+	e := orm.NewOrm().QueryTable(new(model.ItemVariantStockLog)).Filter("ref_type", "purchase_return").Filter("ref_id", pr.ID).One(&log)
+	assert.NoError(t, e)
+	assert.Equal(t, "out", log.LogType)
+	assert.Equal(t, float32(30), log.Quantity)
+
+	// cancel purchase return mengembalikan stock batch
+	ng.SetHeader(tester.H{"Authorization": token})
+	ng.PUT("/v1/purchase-return/"+common.Encrypt(pr.ID)+"/cancel").Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	})
+
+	batch.Read()
+	assert.Equal(t, float32(30), batch.AvailableStock, "stock batch kembali setelah cancel")
+}
+
 // TestHandler_URLMappingPOSTCreatePurchaseReturnGagal1 Test validation
 func TestHandler_URLMappingPOSTCreatePurchaseReturnGagal1(t *testing.T) {
 	// melakukan proses login
@@ -111,12 +111,14 @@ func (h *Handler) cancel(c echo.Context) (e error) {
 
 	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
 		if preturn, e = GetDetailPurchaseReturn("id", id); e == nil {
-			if preturn.DocumentStatus != "cancelled" {
+			if preturn.DocumentStatus == "cancelled" {
+				e = validation.SetError("document_status", "Document status is already canceled")
+			} else if SumDebitNoteAmount(preturn.ID) > 0 {
+				e = validation.SetError("document_status", "Purchase return already has debit note, cancel the debit note first")
+			} else if e = CancelStockPurchaseReturn(preturn); e == nil {
 				if purchase, e = cancelPurchaseReturn(preturn); e == nil {
 					ctx.Data(purchase)
 				}
-			} else {
-				e = validation.SetError("document_status", "Document status is already canceled")
 			}
 		} else {
 			e = echo.ErrNotFound
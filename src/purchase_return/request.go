@@ -34,6 +34,7 @@ type createRequest struct {
 // ItemPurchaseReturn Digunakan untuk create request
 type ItemPurchaseReturn struct {
 	PurchaseOrderItemID string  `json:"purchase_order_item_id" valid:"required"`
+	ItemVariantStockID  string  `json:"item_variant_stock_id" valid:"required"`
 	Quantity            float32 `json:"quantity" valid:"gt:0|lte:100"`
 	Note                string  `json:"note"`
 }
@@ -60,6 +61,8 @@ func (r *createRequest) Validate() *validation.Output {
 			porderitems := purchase.ReturningPurchaseOrder(porder, 0)
 			// untuk mengecek duplicate poi id
 			poiID := make(map[string]bool)
+			// untuk menjumlahkan quantity yang dikeluarkan dari setiap batch
+			batchQty := make(map[int64]float32)
 			// check validasi purchase return item
 			for i, row := range r.PurchaseReturnItems {
 				// check if puchase order item id decrypt able
@@ -82,6 +85,20 @@ func (r *createRequest) Validate() *validation.Output {
 							o.Failure(fmt.Sprintf("purchase_return_item.%d.purchase_order_item_id.invalid", i), "Purchase order item id is invalid")
 						}
 
+						// cek batch stock hasil receiving purchase order yang akan dikembalikan
+						if batchID, err := common.Decrypt(row.ItemVariantStockID); err != nil {
+							o.Failure(fmt.Sprintf("purchase_return_item.%d.item_variant_stock_id.invalid", i), "Item variant stock id is invalid")
+						} else if batch, err := receivingBatch(porder.ID, batchID); err != nil {
+							o.Failure(fmt.Sprintf("purchase_return_item.%d.item_variant_stock_id.invalid", i), "Item variant stock is not received from the purchase order")
+						} else if batch.ItemVariant.ID != orderItem.ItemVariant.ID {
+							o.Failure(fmt.Sprintf("purchase_return_item.%d.item_variant_stock_id.invalid", i), "Item variant stock doesn't match the purchase order item")
+						} else {
+							batchQty[batchID] += row.Quantity
+							if batchQty[batchID] > batch.AvailableStock {
+								o.Failure(fmt.Sprintf("purchase_return_item.%d.quantity", i), "Quantity inputted is greater than the remaining stock of the batch")
+							}
+						}
+
 						// cek apakah qty yg mau di return tidak melebihi yang bisa direturn
 						for _, itemPOI := range *porderitems {
 							if itemPOI.ID == orderItem.ID {
@@ -121,8 +138,10 @@ func (r *createRequest) Transform() *model.PurchaseReturn {
 
 	for _, row := range r.PurchaseReturnItems {
 		decryptID, _ = common.Decrypt(row.PurchaseOrderItemID)
+		batchID, _ := common.Decrypt(row.ItemVariantStockID)
 		pritem := model.PurchaseReturnItem{
 			PurchaseOrderItem: &model.PurchaseOrderItem{ID: decryptID},
+			ItemVariantStock:  &model.ItemVariantStock{ID: batchID},
 			Quantity:          row.Quantity,
 			Note:              row.Note,
 		}
@@ -191,6 +210,8 @@ func (r *updateRequest) Validate() *validation.Output {
 
 			// untuk mengecek duplicate poi id
 			poiID := make(map[string]bool)
+			// untuk menjumlahkan quantity yang dikeluarkan dari setiap batch
+			batchQty := make(map[int64]float32)
 			for i, row := range r.PurchaseReturnItems {
 				// check if puchase order item id decrypt able
 				if porderitemID, err = common.Decrypt(row.PurchaseOrderItemID); err != nil {
@@ -213,6 +234,17 @@ func (r *updateRequest) Validate() *validation.Output {
 							o.Failure(fmt.Sprintf("purchase_return_item.%d.purchase_order_item_id.invalid", i), "Purchase order item id is invalid")
 						}
 
+						// quantity tidak boleh melebihi sisa batch ditambah yang sudah dikeluarkan purchase return ini
+						if i < len(r.PurchaseReturn.PurchaseReturnItems) && r.PurchaseReturn.PurchaseReturnItems[i].ItemVariantStock != nil {
+							batch := &model.ItemVariantStock{ID: r.PurchaseReturn.PurchaseReturnItems[i].ItemVariantStock.ID}
+							if batch.Read() == nil {
+								batchQty[batch.ID] += row.Quantity
+								if batchQty[batch.ID] > batch.AvailableStock+returnedQuantity(r.PurchaseReturn.ID, batch.ID) {
+									o.Failure(fmt.Sprintf("purchase_return_item.%d.quantity", i), "Quantity inputted is greater than the remaining stock of the batch")
+								}
+							}
+						}
+
 						// cek apakah qty yg mau di return tidak melebihi yang bisa direturn
 						for _, itemPOI := range *porderitems {
 							if itemPOI.ID == orderItem.ID {
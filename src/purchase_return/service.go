@@ -6,20 +6,26 @@ package purchaseReturn
 
 import (
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 
 	"git.qasico.com/cuxs/orm"
 )
 
 // CreatePurchaseReturn untuk simpan data purchase return ke database
+// dan mengeluarkan stock dari batch receiving yang dikembalikan
 func CreatePurchaseReturn(purchaseReturn *model.PurchaseReturn) (pReturn *model.PurchaseReturn, err error) {
 
 	if err = purchaseReturn.Save(); err == nil {
 		for _, row := range purchaseReturn.PurchaseReturnItems {
 			row.PurchaseReturn = &model.PurchaseReturn{ID: purchaseReturn.ID}
-			if err = row.Save(); err == nil {
-				return purchaseReturn, err
+			if err = row.Save(); err != nil {
+				return nil, err
 			}
 		}
+
+		if err = StockOutPurchaseReturn(purchaseReturn); err == nil {
+			return purchaseReturn, err
+		}
 	}
 
 	return nil, err
@@ -59,15 +65,20 @@ func GetDetailPurchaseReturn(field string, value ...interface{}) (preturn *model
 }
 
 // updatePurchaseReturn untuk simpan data purchase return ke database
+// dan menyesuaikan stock batch receiving dengan quantity yang baru
 func updatePurchaseReturn(purchaseReturn *model.PurchaseReturn) (pReturn *model.PurchaseReturn, err error) {
 
 	if err = purchaseReturn.Save(); err == nil {
 		for _, row := range purchaseReturn.PurchaseReturnItems {
 			row.PurchaseReturn = &model.PurchaseReturn{ID: purchaseReturn.ID}
-			if err = row.Save(); err == nil {
-				return purchaseReturn, err
+			if err = row.Save(); err != nil {
+				return nil, err
 			}
 		}
+
+		if err = StockOutPurchaseReturn(purchaseReturn); err == nil {
+			return purchaseReturn, err
+		}
 	}
 
 	return nil, err
@@ -79,6 +90,7 @@ func cancelPurchaseReturn(purchaseReturn *model.PurchaseReturn) (purchase *model
 	var total int64
 	purchaseReturn.DocumentStatus = "cancelled"
 	if err = purchaseReturn.Save(); err == nil {
+		generalLedger.AutoReverse("purchase_return", purchaseReturn.ID)
 		err = purchaseReturn.Read()
 		if purchaseReturn.DocumentStatus == "cancelled" {
 			if total, err = orm.NewOrm().Raw("SELECT * FROM finance_revenue WHERE ref_id = ? AND ref_type = 'purchase_return' AND is_deleted = 0;", purchaseReturn.ID).QueryRows(&finance); err == nil && total != 0 {
@@ -87,6 +99,7 @@ func cancelPurchaseReturn(purchaseReturn *model.PurchaseReturn) (purchase *model
 					if err = row.Save("IsDeleted"); err != nil {
 						return nil, err
 					}
+					generalLedger.AutoReverse("finance_revenue", row.ID)
 				}
 				return purchaseReturn, err
 			}
@@ -94,3 +107,33 @@ func cancelPurchaseReturn(purchaseReturn *model.PurchaseReturn) (purchase *model
 	}
 	return nil, err
 }
+
+// SumDebitNoteAmount total debit note yang diterbitkan dari purchase return, debit note yang dicancel tidak dihitung.
+func SumDebitNoteAmount(prID int64) (total float64) {
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM debit_note WHERE purchase_return_id = ? AND document_status != 'cancelled'", prID).QueryRow(&total)
+	return
+}
+
+// CheckRefundStatus memperbarui document status purchase return berdasarkan total refund cash (finance revenue cleared)
+// dan debit note yang diterbitkan, finished apabila sudah sama dengan total amount purchase return.
+func CheckRefundStatus(pr *model.PurchaseReturn) (e error) {
+	var revenue float64
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM finance_revenue WHERE ref_id = ? AND ref_type = 'purchase_return' AND document_status = 'cleared' AND is_deleted = 0", pr.ID).QueryRow(&revenue)
+
+	if pr.TotalAmount == revenue+SumDebitNoteAmount(pr.ID) {
+		pr.DocumentStatus = "finished"
+	} else {
+		pr.DocumentStatus = "active"
+	}
+
+	if e = pr.Save("document_status"); e == nil {
+		// jurnal dibalik apabila purchase return tidak lagi finished, misalnya debit note nya dicancel
+		if pr.DocumentStatus == "finished" {
+			generalLedger.AutoPost("purchase_return", pr.ID)
+		} else {
+			generalLedger.AutoReverse("purchase_return", pr.ID)
+		}
+	}
+
+	return
+}
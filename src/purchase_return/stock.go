@@ -0,0 +1,80 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package purchaseReturn
+
+import (
+	"errors"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/inventory"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// receivingBatch mengambil batch item variant stock yang masuk dari workorder receiving purchase order.
+func receivingBatch(poID int64, batchID int64) (ivs *model.ItemVariantStock, e error) {
+	ivs = &model.ItemVariantStock{ID: batchID}
+	if e = ivs.Read(); e != nil {
+		return nil, e
+	}
+
+	var total int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM item_variant_stock_log l "+
+		"INNER JOIN workorder_receiving wr ON wr.id = l.ref_id "+
+		"WHERE l.item_variant_stock_id = ? AND l.ref_type = 'workorder_receiving' AND l.log_type = 'in' "+
+		"AND wr.purchase_order_id = ? AND wr.is_deleted = 0", ivs.ID, poID).QueryRow(&total)
+
+	if total == 0 || ivs.Location != "warehouse" {
+		return nil, errors.New("batch is not received from the purchase order")
+	}
+
+	return
+}
+
+// returnedQuantity jumlah quantity batch yang sudah dikeluarkan oleh purchase return.
+func returnedQuantity(prID int64, batchID int64) (total float32) {
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(IF(log_type = 'out', quantity, -quantity)), 0) FROM item_variant_stock_log "+
+		"WHERE ref_type = 'purchase_return' AND ref_id = ? AND item_variant_stock_id = ?", prID, batchID).QueryRow(&total)
+
+	return
+}
+
+// StockOutPurchaseReturn mengeluarkan stock batch receiving sesuai quantity purchase return item,
+// apabila purchase return diubah hanya selisih quantity yang dicatat pada log.
+func StockOutPurchaseReturn(pr *model.PurchaseReturn) (e error) {
+	for _, item := range pr.PurchaseReturnItems {
+		if item.ItemVariantStock == nil || item.ItemVariantStock.ID == 0 {
+			continue
+		}
+
+		ivs := &model.ItemVariantStock{ID: item.ItemVariantStock.ID}
+		if e = ivs.Read(); e != nil {
+			return
+		}
+
+		diff := item.Quantity - returnedQuantity(pr.ID, ivs.ID)
+		if diff > 0 {
+			_, e = inventory.SaveLog(ivs, uint64(pr.ID), "purchase_return", "out", diff)
+		} else if diff < 0 {
+			_, e = inventory.SaveLog(ivs, uint64(pr.ID), "purchase_return", "in", -diff)
+		}
+
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// CancelStockPurchaseReturn mengembalikan stock batch receiving yang dikeluarkan purchase return.
+func CancelStockPurchaseReturn(pr *model.PurchaseReturn) (e error) {
+	if e = inventory.CancelStock(uint64(pr.ID), "purchase_return"); e != nil && e.Error() == "data empty" {
+		// purchase return tanpa batch, tidak ada stock yang keluar
+		e = nil
+	}
+
+	return
+}
@@ -6,6 +6,7 @@ package receiving
 
 import (
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/inventory"
 
 	"git.qasico.com/cuxs/orm"
@@ -58,7 +59,7 @@ func CreateReceiving(r *createRequest) (wr *model.WorkorderReceiving, e error) {
 			//ambil id variant
 			rix.PurchaseOrderItem.Read("ID")
 
-			if _, e = inventory.FifoStockIn(rix.PurchaseOrderItem.ItemVariant, rix.PurchaseOrderItem.UnitPrice, rix.Quantity, "workorder_receiving", uint64(wr.ID)); e != nil {
+			if _, e = inventory.FifoStockIn(rix.PurchaseOrderItem.ItemVariant, rix.PurchaseOrderItem.NetUnitPrice(), rix.Quantity, "workorder_receiving", uint64(wr.ID)); e != nil {
 				return
 			}
 
@@ -84,6 +85,8 @@ func CreateReceiving(r *createRequest) (wr *model.WorkorderReceiving, e error) {
 			e = wr.PurchaseOrder.Save()
 		}
 
+		generalLedger.AutoPost("workorder_receiving", wr.ID)
+
 	}
 
 	return
@@ -0,0 +1,140 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recurringOrder_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("recurring_order", "recurring_order_item", "recurring_order_log")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	ro := model.DummyRecurringOrder()
+	model.DummyRecurringOrderLog()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/recurring-order", "GET", http.StatusOK},
+		{"/v1/recurring-order/log", "GET", http.StatusOK},
+		{"/v1/recurring-order/" + common.Encrypt(ro.ID), "GET", http.StatusOK},
+		{"/v1/recurring-order/999999", "GET", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_CreateRecurringOrder(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDefault = 0
+	customer.IsDeleted = 0
+	customer.IsArchived = 0
+	customer.Save()
+
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save()
+
+	cid := common.Encrypt(customer.ID)
+	items := []tester.D{{"item_variant_id": common.Encrypt(iv.ID), "pricing_type_id": common.Encrypt(model.DummyPricingType().ID), "quantity": 5, "unit_price": 10000}}
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"customer_id": cid, "schedule_type": "weekly", "schedule_days": "monday,thursday", "start_date": "2018-03-01T00:00:00Z", "is_active": 1, "recurring_order_items": items}, http.StatusOK},
+		{tester.D{"customer_id": cid, "schedule_type": "monthly", "day_of_month": 31, "start_date": "2018-03-01T00:00:00Z", "end_date": "2018-12-31T00:00:00Z", "is_active": 1, "recurring_order_items": items}, http.StatusOK},
+		// hari tidak dikenali
+		{tester.D{"customer_id": cid, "schedule_type": "weekly", "schedule_days": "everyday", "start_date": "2018-03-01T00:00:00Z", "recurring_order_items": items}, http.StatusUnprocessableEntity},
+		// tanggal bulanan tidak valid
+		{tester.D{"customer_id": cid, "schedule_type": "monthly", "day_of_month": 32, "start_date": "2018-03-01T00:00:00Z", "recurring_order_items": items}, http.StatusUnprocessableEntity},
+		// end date sebelum start date
+		{tester.D{"customer_id": cid, "schedule_type": "weekly", "schedule_days": "monday", "start_date": "2018-03-01T00:00:00Z", "end_date": "2018-02-01T00:00:00Z", "recurring_order_items": items}, http.StatusUnprocessableEntity},
+		// tanpa item
+		{tester.D{"customer_id": cid, "schedule_type": "weekly", "schedule_days": "monday", "start_date": "2018-03-01T00:00:00Z"}, http.StatusUnprocessableEntity},
+		{tester.D{"customer_id": "999999", "schedule_type": "weekly", "schedule_days": "monday", "start_date": "2018-03-01T00:00:00Z", "recurring_order_items": items}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/recurring-order").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	ro := &model.RecurringOrder{Customer: customer, ScheduleType: "monthly"}
+	assert.NoError(t, ro.Read("Customer", "ScheduleType"))
+	assert.Equal(t, int8(31), ro.DayOfMonth)
+	assert.Equal(t, customer.Address, ro.ShipmentAddress)
+}
+
+func TestRecurringOrderIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/recurring-order", "GET", http.StatusUnauthorized},
+		{"/v1/recurring-order", "POST", http.StatusUnauthorized},
+		{"/v1/recurring-order/log", "GET", http.StatusUnauthorized},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
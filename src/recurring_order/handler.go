@@ -0,0 +1,137 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recurringOrder
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for recurring order.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("recurring_read"))
+	r.GET("/log", h.getLog, auth.CheckPrivilege("recurring_read"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("recurring_read"))
+	r.POST("", h.create, auth.CheckPrivilege("recurring_manage"))
+	r.PUT("/:id", h.update, auth.CheckPrivilege("recurring_manage"))
+	r.POST("/:id/run", h.run, auth.CheckPrivilege("recurring_run"))
+}
+
+// get endpoint untuk mengambil semua recurring order.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.RecurringOrder
+	if data, total, e = GetRecurringOrders(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// getLog endpoint untuk mengambil riwayat pembuatan sales order dari recurring order,
+// gunakan filter status failed untuk melihat recurring order yang gagal dibuat.
+func (h *Handler) getLog(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.RecurringOrderLog
+	if data, total, e = GetRecurringOrderLogs(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint untuk mengambil detail recurring order beserta item.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.RecurringOrder
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowRecurringOrder("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk membuat recurring order baru.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r recurringRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = SaveRecurringOrder(m); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// update endpoint untuk mengubah recurring order, item lama diganti dengan item pada request.
+func (h *Handler) update(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r recurringRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.RecurringOrder, e = ShowRecurringOrder("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = SaveRecurringOrder(m); e == nil {
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// run endpoint untuk membuat sales order dari recurring order secara manual,
+// hasilnya dikembalikan berupa recurring order log baik berhasil maupun gagal.
+func (h *Handler) run(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r runRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.RecurringOrder, e = ShowRecurringOrder("id", id); e == nil {
+			if e = ctx.Bind(&r); e == nil {
+				var l *model.RecurringOrderLog
+				if l, e = Run(r.RecurringOrder, r.Date); e == nil {
+					ctx.Data(l)
+				}
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,222 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recurringOrder
+
+import (
+	"fmt"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/util"
+	"git.qasico.com/mj/api/src/visit"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// recurringRequest data struct that stored request data when requesting an create or update recurring order process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type recurringRequest struct {
+	CustomerID           string                 `json:"customer_id" valid:"required"`
+	ScheduleType         string                 `json:"schedule_type" valid:"required|in:weekly,monthly"`
+	ScheduleDays         string                 `json:"schedule_days"`
+	DayOfMonth           int8                   `json:"day_of_month"`
+	StartDate            time.Time              `json:"start_date" valid:"required"`
+	EndDate              time.Time              `json:"end_date"`
+	EtaDays              int8                   `json:"eta_days" valid:"gte:0"`
+	ShipmentAddress      string                 `json:"shipment_address"`
+	ShipmentCost         float64                `json:"shipment_cost" valid:"gte:0"`
+	Discount             float32                `json:"discount" valid:"gte:0|lte:100"`
+	DiscountAmount       float64                `json:"discount_amount" valid:"gte:0"`
+	IsPercentageDiscount int8                   `json:"is_percentage_discount" valid:"in:0,1"`
+	Tax                  float32                `json:"tax" valid:"gte:0|lte:100"`
+	Note                 string                 `json:"note"`
+	IsActive             int8                   `json:"is_active" valid:"in:0,1"`
+	Items                []recurringItemRequest `json:"recurring_order_items" valid:"required"`
+	Session              *auth.SessionData      `json:"-"`
+	RecurringOrder       *model.RecurringOrder  `json:"-"`
+
+	Customer *model.Partnership `json:"-"`
+}
+
+type recurringItemRequest struct {
+	ItemVariantID string  `json:"item_variant_id" valid:"required"`
+	PricingTypeID string  `json:"pricing_type_id" valid:"required"`
+	TaxCodeID     string  `json:"tax_code_id"`
+	Quantity      float32 `json:"quantity" valid:"required|gt:0"`
+	UnitPrice     float64 `json:"unit_price" valid:"required|gt:0"`
+	Discount      float32 `json:"discount" valid:"gte:0|lte:100"`
+	Note          string  `json:"note"`
+
+	ItemVariant *model.ItemVariant `json:"-"`
+	PricingType *model.PricingType `json:"-"`
+	TaxCode     *model.TaxCode     `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *recurringRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if id, e := common.Decrypt(r.CustomerID); e == nil {
+		r.Customer = &model.Partnership{ID: id, PartnershipType: "customer", IsDeleted: int8(0)}
+		if e = r.Customer.Read("ID", "PartnershipType", "IsDeleted"); e != nil || r.Customer.IsArchived == int8(1) {
+			o.Failure("customer_id", "customer_id doesn't exist")
+		} else if r.Customer.IsDefault == int8(1) {
+			o.Failure("customer_id", "recurring order can't be made for walk in customer")
+		}
+	} else {
+		o.Failure("customer_id", "customer_id cannot be decrypt")
+	}
+
+	if r.ScheduleType == "weekly" && len(visit.VisitDays(r.ScheduleDays)) == 0 {
+		o.Failure("schedule_days", "schedule_days must contain at least one day name")
+	}
+
+	if r.ScheduleType == "monthly" && (r.DayOfMonth < 1 || r.DayOfMonth > 31) {
+		o.Failure("day_of_month", "day_of_month must be between 1 and 31")
+	}
+
+	if !r.EndDate.IsZero() && r.EndDate.Before(r.StartDate) {
+		o.Failure("end_date", "end_date must be after start_date")
+	}
+
+	checkDuplicate := make(map[string]bool)
+	for i := range r.Items {
+		item := &r.Items[i]
+		if id, e := common.Decrypt(item.ItemVariantID); e == nil {
+			item.ItemVariant = &model.ItemVariant{ID: id, IsDeleted: int8(0), IsArchived: int8(0)}
+			if e = item.ItemVariant.Read("ID", "IsDeleted", "IsArchived"); e != nil {
+				o.Failure(fmt.Sprintf("recurring_order_items.%d.item_variant_id.invalid", i), "Item variant id not found")
+			}
+		} else {
+			o.Failure(fmt.Sprintf("recurring_order_items.%d.item_variant_id.invalid", i), "Item Variant id is invalid")
+		}
+
+		if checkDuplicate[item.ItemVariantID] {
+			o.Failure(fmt.Sprintf("recurring_order_items.%d.item_variant_id.invalid", i), "item variant id duplicate")
+		}
+		checkDuplicate[item.ItemVariantID] = true
+
+		if id, e := common.Decrypt(item.PricingTypeID); e == nil {
+			item.PricingType = &model.PricingType{ID: id}
+			if e = item.PricingType.Read(); e != nil {
+				o.Failure(fmt.Sprintf("recurring_order_items.%d.pricing_type_id.invalid", i), "Pricing type id not found")
+			}
+		} else {
+			o.Failure(fmt.Sprintf("recurring_order_items.%d.pricing_type_id.invalid", i), "Pricing type id is invalid")
+		}
+
+		if item.TaxCodeID != "" {
+			if id, e := common.Decrypt(item.TaxCodeID); e == nil {
+				item.TaxCode = &model.TaxCode{ID: id}
+				if e = item.TaxCode.Read(); e != nil {
+					o.Failure(fmt.Sprintf("recurring_order_items.%d.tax_code_id.invalid", i), "Tax code id not found")
+				}
+			} else {
+				o.Failure(fmt.Sprintf("recurring_order_items.%d.tax_code_id.invalid", i), "Tax code id is invalid")
+			}
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *recurringRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *recurringRequest) Transform() *model.RecurringOrder {
+	m := r.RecurringOrder
+	if m == nil {
+		code, _ := util.CodeGen("code_recurring_order", "recurring_order")
+		m = &model.RecurringOrder{
+			Code:      code,
+			CreatedBy: r.Session.User,
+			CreatedAt: time.Now(),
+		}
+	} else {
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	m.Customer = r.Customer
+	m.ScheduleType = r.ScheduleType
+	m.ScheduleDays = ""
+	m.DayOfMonth = 0
+	if r.ScheduleType == "weekly" {
+		m.ScheduleDays = r.ScheduleDays
+	} else {
+		m.DayOfMonth = r.DayOfMonth
+	}
+
+	m.StartDate = r.StartDate
+	m.EndDate = r.EndDate
+	m.EtaDays = r.EtaDays
+	m.ShipmentAddress = r.ShipmentAddress
+	if m.ShipmentAddress == "" {
+		m.ShipmentAddress = r.Customer.Address
+	}
+
+	m.ShipmentCost = r.ShipmentCost
+	m.Discount = r.Discount
+	m.DiscountAmount = r.DiscountAmount
+	m.IsPercentageDiscount = r.IsPercentageDiscount
+	m.Tax = r.Tax
+	m.Note = r.Note
+	m.IsActive = r.IsActive
+
+	m.RecurringOrderItems = nil
+	for _, i := range r.Items {
+		m.RecurringOrderItems = append(m.RecurringOrderItems, &model.RecurringOrderItem{
+			ItemVariant: i.ItemVariant,
+			PricingType: i.PricingType,
+			TaxCode:     i.TaxCode,
+			Quantity:    i.Quantity,
+			UnitPrice:   i.UnitPrice,
+			Discount:    i.Discount,
+			Note:        i.Note,
+		})
+	}
+
+	return m
+}
+
+// runRequest data struct that stored request data when requesting an run recurring order process.
+// date kosong berarti sales order dibuat untuk hari ini.
+type runRequest struct {
+	Date           time.Time             `json:"date"`
+	RecurringOrder *model.RecurringOrder `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *runRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.RecurringOrder.IsActive != int8(1) {
+		o.Failure("is_active", "recurring order is not active")
+	}
+
+	if r.Date.IsZero() {
+		r.Date = time.Now()
+	}
+
+	if !r.RecurringOrder.EndDate.IsZero() && truncate(r.Date).After(truncate(r.RecurringOrder.EndDate)) {
+		o.Failure("date", "recurring order already ended")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *runRequest) Messages() map[string]string {
+	return map[string]string{}
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recurringOrder
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/sales"
+	"git.qasico.com/mj/api/src/visit"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/labstack/gommon/log"
+)
+
+// Cron membuat sales order dari semua recurring order yang jadwalnya jatuh pada hari ini,
+// dijalankan setiap hari dari cronTask pada main.go.
+func Cron() {
+	logs, e := RunDue(time.Now())
+	if e != nil {
+		log.Error(e)
+	}
+
+	for _, l := range logs {
+		if l.Status == "failed" {
+			log.Errorf("recurring order %s failed: %s", l.RecurringOrder.Code, l.Message)
+		}
+	}
+}
+
+// GetRecurringOrders get all data recurring_order that matched with query request parameters.
+// returning slices of recurring order, total data without limit and error.
+func GetRecurringOrders(rq *orm.RequestQuery) (m *[]model.RecurringOrder, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.RecurringOrder))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.RecurringOrder
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowRecurringOrder untuk mengambil data detail recurring order beserta item berdasarkan param
+func ShowRecurringOrder(field string, values ...interface{}) (*model.RecurringOrder, error) {
+	m := new(model.RecurringOrder)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel(1).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	o.QueryTable(new(model.RecurringOrderItem)).Filter("recurring_order_id", m.ID).RelatedSel(1).OrderBy("id").All(&m.RecurringOrderItems)
+
+	return m, nil
+}
+
+// GetRecurringOrderLogs get all data recurring_order_log that matched with query request parameters.
+// returning slices of recurring order log, total data without limit and error.
+func GetRecurringOrderLogs(rq *orm.RequestQuery) (m *[]model.RecurringOrderLog, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.RecurringOrderLog))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.RecurringOrderLog
+	if _, err = q.RelatedSel(1).All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// SaveRecurringOrder menyimpan recurring order beserta item,
+// item yang lama akan dihapus dan diganti dengan item yang baru.
+func SaveRecurringOrder(m *model.RecurringOrder) (e error) {
+	if e = m.Save(); e == nil {
+		o := orm.NewOrm()
+		o.Raw("DELETE FROM recurring_order_item WHERE recurring_order_id = ?", m.ID).Exec()
+
+		for _, i := range m.RecurringOrderItems {
+			i.ID = 0
+			i.RecurringOrder = &model.RecurringOrder{ID: m.ID}
+			if e = i.Save(); e != nil {
+				return e
+			}
+		}
+	}
+
+	return
+}
+
+// IsDue cek apakah recurring order harus dibuatkan sales order pada tanggal tersebut,
+// jadwal weekly mengikuti nama hari pada schedule_days, jadwal monthly mengikuti day_of_month
+// dan akan dijalankan pada akhir bulan jika bulan tersebut tidak memiliki tanggal itu.
+func IsDue(m *model.RecurringOrder, date time.Time) bool {
+	day := truncate(date)
+	if m.IsActive != int8(1) || day.Before(truncate(m.StartDate)) {
+		return false
+	}
+
+	if !m.EndDate.IsZero() && day.After(truncate(m.EndDate)) {
+		return false
+	}
+
+	// sales order untuk tanggal tersebut sudah pernah dibuat
+	if !m.LastRunDate.IsZero() && !day.After(truncate(m.LastRunDate)) {
+		return false
+	}
+
+	switch m.ScheduleType {
+	case "weekly":
+		for _, d := range visit.VisitDays(m.ScheduleDays) {
+			if d == day.Weekday() {
+				return true
+			}
+		}
+	case "monthly":
+		last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		dom := int(m.DayOfMonth)
+		if dom > last {
+			dom = last
+		}
+
+		return day.Day() == dom
+	}
+
+	return false
+}
+
+// Run membuat sales order dari recurring order pada tanggal tersebut melalui sales.CreateRecurringSalesOrder,
+// hasilnya baik berhasil maupun gagal dicatat pada recurring_order_log beserta alasan kegagalan.
+func Run(m *model.RecurringOrder, date time.Time) (l *model.RecurringOrderLog, e error) {
+	if len(m.RecurringOrderItems) == 0 {
+		o := orm.NewOrm()
+		o.QueryTable(new(model.RecurringOrderItem)).Filter("recurring_order_id", m.ID).OrderBy("id").All(&m.RecurringOrderItems)
+	}
+
+	if e = m.Customer.Read(); e != nil {
+		return nil, e
+	}
+
+	if m.CreatedBy != nil {
+		m.CreatedBy.Read()
+	}
+
+	l = &model.RecurringOrderLog{
+		RecurringOrder: m,
+		RunDate:        date,
+		Status:         "success",
+		CreatedAt:      time.Now(),
+	}
+
+	var so *model.SalesOrder
+	if so, e = sales.CreateRecurringSalesOrder(m, date); e == nil {
+		l.SalesOrder = so
+		l.Message = "sales order " + so.Code + " has been created"
+	} else {
+		l.Status = "failed"
+		l.Message = e.Error()
+	}
+
+	if e = l.Save(); e != nil {
+		return nil, e
+	}
+
+	// run yang gagal tidak memajukan last run date sehingga bisa dijalankan ulang
+	if l.Status == "success" && truncate(date).After(truncate(m.LastRunDate)) {
+		m.LastRunDate = date
+		e = m.Save("LastRunDate")
+	}
+
+	return
+}
+
+// RunDue menjalankan semua recurring order aktif yang jadwalnya jatuh pada tanggal tersebut,
+// recurring order yang gagal tetap dicatat dan tidak menghentikan recurring order lainnya.
+func RunDue(date time.Time) (logs []*model.RecurringOrderLog, e error) {
+	var m []*model.RecurringOrder
+	o := orm.NewOrm()
+	if _, e = o.QueryTable(new(model.RecurringOrder)).Filter("is_active", 1).Filter("start_date__lte", date.Format("2006-01-02")).All(&m); e != nil {
+		return nil, e
+	}
+
+	for _, ro := range m {
+		if !IsDue(ro, date) {
+			continue
+		}
+
+		var l *model.RecurringOrderLog
+		if l, e = Run(ro, date); e != nil {
+			return logs, e
+		}
+
+		logs = append(logs, l)
+	}
+
+	return
+}
+
+// truncate menghilangkan jam pada tanggal.
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,165 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package recurringOrder
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dummyRecurringOrder membuat recurring order mingguan setiap senin dengan satu item
+// yang memiliki harga dan stock cukup.
+func dummyRecurringOrder() *model.RecurringOrder {
+	c := model.DummyPartnership()
+	c.PartnershipType = "customer"
+	c.OrderRule = "none"
+	c.IsDefault = 0
+	c.IsDeleted = 0
+	c.IsArchived = 0
+	c.TotalDebt = 0
+	c.Save()
+
+	iv := model.DummyItemVariant()
+	iv.AvailableStock = 100
+	iv.CommitedStock = 0
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save()
+
+	pt := model.DummyPricingType()
+	pt.ParentType = nil
+	pt.Save()
+
+	ivp := model.DummyItemVariantPrice()
+	ivp.ItemVariant = iv
+	ivp.PricingType = pt
+	ivp.UnitPrice = 10000
+	ivp.Save()
+
+	ro := model.DummyRecurringOrder()
+	ro.Customer = c
+	ro.ScheduleType = "weekly"
+	ro.ScheduleDays = "monday"
+	ro.StartDate = time.Date(2018, 1, 1, 0, 0, 0, 0, time.Local)
+	ro.EndDate = time.Time{}
+	ro.LastRunDate = time.Time{}
+	ro.ShipmentAddress = "Jl. Pemuda No. 1"
+	ro.Discount = 0
+	ro.DiscountAmount = 0
+	ro.IsPercentageDiscount = 0
+	ro.Tax = 0
+	ro.ShipmentCost = 0
+	ro.IsActive = 1
+	ro.Save()
+
+	roi := model.DummyRecurringOrderItem()
+	roi.RecurringOrder = ro
+	roi.ItemVariant = iv
+	roi.PricingType = pt
+	roi.TaxCode = nil
+	roi.Quantity = 5
+	roi.UnitPrice = 10000
+	roi.Discount = 0
+	roi.Save()
+
+	ro.RecurringOrderItems = []*model.RecurringOrderItem{roi}
+
+	return ro
+}
+
+func TestIsDue(t *testing.T) {
+	// 2018-03-05 adalah hari senin
+	monday := time.Date(2018, 3, 5, 0, 0, 0, 0, time.Local)
+	m := &model.RecurringOrder{
+		ScheduleType: "weekly",
+		ScheduleDays: "monday,thursday",
+		StartDate:    time.Date(2018, 1, 1, 0, 0, 0, 0, time.Local),
+		IsActive:     1,
+	}
+
+	assert.True(t, IsDue(m, monday))
+	assert.True(t, IsDue(m, monday.AddDate(0, 0, 3)))
+	assert.False(t, IsDue(m, monday.AddDate(0, 0, 1)))
+
+	// sudah dijalankan pada hari yang sama
+	m.LastRunDate = monday
+	assert.False(t, IsDue(m, monday))
+
+	// melewati end date
+	m.LastRunDate = time.Time{}
+	m.EndDate = monday.AddDate(0, 0, -1)
+	assert.False(t, IsDue(m, monday))
+
+	m.EndDate = time.Time{}
+	m.IsActive = 0
+	assert.False(t, IsDue(m, monday))
+
+	// jadwal bulanan tanggal 31 dijalankan pada akhir bulan februari
+	m = &model.RecurringOrder{ScheduleType: "monthly", DayOfMonth: 31, StartDate: m.StartDate, IsActive: 1}
+	assert.True(t, IsDue(m, time.Date(2018, 2, 28, 0, 0, 0, 0, time.Local)))
+	assert.False(t, IsDue(m, time.Date(2018, 3, 30, 0, 0, 0, 0, time.Local)))
+	assert.True(t, IsDue(m, time.Date(2018, 3, 31, 0, 0, 0, 0, time.Local)))
+}
+
+func TestRun(t *testing.T) {
+	monday := time.Date(2018, 3, 5, 0, 0, 0, 0, time.Local)
+	ro := dummyRecurringOrder()
+
+	l, e := Run(ro, monday)
+	assert.NoError(t, e)
+	assert.Equal(t, "success", l.Status, l.Message)
+	assert.NotNil(t, l.SalesOrder)
+
+	so := &model.SalesOrder{ID: l.SalesOrder.ID}
+	assert.NoError(t, so.Read())
+	assert.Equal(t, ro.Customer.ID, so.Customer.ID)
+	assert.Equal(t, float64(50000), so.TotalCharge)
+
+	ro.Read()
+	assert.Equal(t, monday.Format("2006-01-02"), ro.LastRunDate.Format("2006-01-02"))
+	assert.False(t, IsDue(ro, monday))
+}
+
+func TestRunFailed(t *testing.T) {
+	monday := time.Date(2018, 3, 5, 0, 0, 0, 0, time.Local)
+
+	// item variant sudah diarsipkan
+	ro := dummyRecurringOrder()
+	iv := ro.RecurringOrderItems[0].ItemVariant
+	iv.IsArchived = 1
+	iv.Save("IsArchived")
+
+	l, e := Run(ro, monday)
+	assert.NoError(t, e)
+	assert.Equal(t, "failed", l.Status)
+	assert.Nil(t, l.SalesOrder)
+	assert.Contains(t, l.Message, "is archived")
+
+	// run yang gagal bisa dijalankan ulang pada tanggal yang sama
+	ro.Read()
+	assert.True(t, ro.LastRunDate.IsZero())
+	assert.True(t, IsDue(ro, monday))
+
+	// plafon customer tidak cukup
+	ro = dummyRecurringOrder()
+	ro.Customer.OrderRule = "plafon"
+	ro.Customer.TotalDebt = 980000
+	ro.Customer.MaxPlafon = 1000000
+	ro.Customer.Save("OrderRule", "TotalDebt", "MaxPlafon")
+
+	l, e = Run(ro, monday)
+	assert.NoError(t, e)
+	assert.Equal(t, "failed", l.Status)
+	assert.Contains(t, l.Message, "max plafon")
+
+	// kegagalan tetap tercatat pada log
+	rl := &model.RecurringOrderLog{ID: l.ID}
+	assert.NoError(t, rl.Read())
+	assert.Equal(t, "failed", rl.Status)
+}
@@ -5,10 +5,18 @@
 package report_test
 
 import (
+	"fmt"
+	"net/http"
 	"os"
 	"testing"
 
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
 	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMain(m *testing.M) {
@@ -18,7 +26,73 @@ func TestMain(m *testing.M) {
 	res := m.Run()
 
 	// cleanup
-	test.DataCleanUp()
+	test.DataCleanUp("journal_entry_line", "journal_entry", "operating_expense")
 
 	os.Exit(res)
 }
+
+func TestSupplierScorecardRouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	po := model.DummyPurchaseOrder()
+	po.DocumentStatus = "active"
+	po.IsDeleted = 0
+	po.Save()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/report/supplier-scorecard?start_date=2019-01-01&end_date=2019-12-31", "GET", http.StatusOK},
+		{"/v1/report/supplier-scorecard/" + common.Encrypt(po.Supplier.ID), "GET", http.StatusOK},
+		{"/v1/report/supplier-scorecard/999999", "GET", http.StatusNotFound},
+		{"/v1/report/supplier-scorecard/export", "GET", http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestFinancialStatementRouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/report/profit-loss", "GET", http.StatusOK},
+		{"/v1/report/profit-loss?period=2019-03&mode=ytd", "GET", http.StatusOK},
+		{"/v1/report/profit-loss/export?period=2019-03&format=xlsx", "GET", http.StatusOK},
+		{"/v1/report/profit-loss/export?period=2019-03&format=pdf", "GET", http.StatusOK},
+		{"/v1/report/balance-sheet", "GET", http.StatusOK},
+		{"/v1/report/balance-sheet?period=2019-03&mode=month", "GET", http.StatusOK},
+		{"/v1/report/balance-sheet/export?period=2019-03&format=xlsx", "GET", http.StatusOK},
+		{"/v1/report/balance-sheet/export?period=2019-03&format=pdf", "GET", http.StatusOK},
+		{"/v1/report/operating-expense", "GET", http.StatusOK},
+		{"/v1/report/operating-expense/summary", "GET", http.StatusOK},
+		{"/v1/report/operating-expense/summary?start_date=2019-03-01&end_date=2019-03-31", "GET", http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
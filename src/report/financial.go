@@ -0,0 +1,474 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/bank_account"
+	"git.qasico.com/mj/api/src/printing"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/env"
+	"git.qasico.com/cuxs/orm"
+	"github.com/tealeg/xlsx"
+)
+
+// prefix kode chart of account harga pokok penjualan, akun expense lainnya adalah beban operasional.
+const cogsPrefix = "5"
+
+// StatementLine satu baris laporan keuangan, nilai periode berjalan dibandingkan dengan periode pembanding
+// dan perubahan dalam persen, details berisi rincian saldo bank per rekening.
+type StatementLine struct {
+	Code     string           `json:"code"`
+	Name     string           `json:"name"`
+	Amount   float64          `json:"amount"`
+	Previous float64          `json:"previous"`
+	Change   float64          `json:"change"`
+	Details  []*StatementLine `json:"details,omitempty"`
+	header   bool
+}
+
+// StatementSection kelompok akun pada laporan keuangan beserta totalnya.
+type StatementSection struct {
+	Name  string           `json:"name"`
+	Lines []*StatementLine `json:"lines"`
+	Total *StatementLine   `json:"total"`
+}
+
+// ProfitLoss laporan laba rugi periode berjalan dan periode pembanding,
+// mode month dibandingkan dengan bulan sebelumnya, mode ytd dibandingkan dengan periode yang sama tahun lalu.
+type ProfitLoss struct {
+	Mode              string            `json:"mode"`
+	StartDate         string            `json:"start_date"`
+	EndDate           string            `json:"end_date"`
+	PreviousStartDate string            `json:"previous_start_date"`
+	PreviousEndDate   string            `json:"previous_end_date"`
+	Revenue           *StatementSection `json:"revenue"`
+	CostOfGoodsSold   *StatementSection `json:"cost_of_goods_sold"`
+	GrossProfit       *StatementLine    `json:"gross_profit"`
+	OperatingExpenses *StatementSection `json:"operating_expenses"`
+	NetProfit         *StatementLine    `json:"net_profit"`
+}
+
+// BalanceSheet laporan neraca per tanggal akhir periode dan tanggal pembanding.
+type BalanceSheet struct {
+	Mode                   string            `json:"mode"`
+	Date                   string            `json:"date"`
+	PreviousDate           string            `json:"previous_date"`
+	Assets                 *StatementSection `json:"assets"`
+	Liabilities            *StatementSection `json:"liabilities"`
+	Equity                 *StatementSection `json:"equity"`
+	TotalLiabilitiesEquity *StatementLine    `json:"total_liabilities_equity"`
+	IsBalanced             bool              `json:"is_balanced"`
+}
+
+// accountBalance mutasi debit dikurangi credit per chart of account.
+type accountBalance struct {
+	Code        string
+	Name        string
+	AccountType string
+	Balance     float64
+}
+
+// statementPeriod menentukan periode laporan dari parameter period (YYYY-MM, default bulan ini) dan mode,
+// tanggal akhir periode bulan berjalan adalah hari ini.
+func statementPeriod(period string, mode string) (start, end, prevStart, prevEnd time.Time, e error) {
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if period != "" {
+		if month, e = time.Parse("2006-01", period); e != nil {
+			return
+		}
+	}
+
+	end = month.AddDate(0, 1, -1)
+	if today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC); end.After(today) && !month.After(today) {
+		end = today
+	}
+
+	switch mode {
+	case "", "month":
+		start = month
+		prevStart = month.AddDate(0, -1, 0)
+		prevEnd = month.AddDate(0, 0, -1)
+	case "ytd":
+		start = time.Date(month.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		prevStart = start.AddDate(-1, 0, 0)
+		prevEnd = end.AddDate(-1, 0, 0)
+	default:
+		e = errors.New("mode is not valid")
+	}
+
+	return
+}
+
+// accountBalances mengambil saldo debit dikurangi credit setiap chart of account dari jurnal
+// dengan recognition date pada periode, tanggal start yang kosong berarti sejak awal.
+func accountBalances(start time.Time, end time.Time) (m map[string]*accountBalance, codes []string, e error) {
+	where := "je.recognition_date <= ?"
+	args := []interface{}{end.Format("2006-01-02")}
+	if !start.IsZero() {
+		where += " AND je.recognition_date >= ?"
+		args = append(args, start.Format("2006-01-02"))
+	}
+
+	var balances []*accountBalance
+	if _, e = orm.NewOrm().Raw("SELECT coa.code, coa.name, coa.account_type, COALESCE(SUM(jel.debit - jel.credit), 0) AS balance "+
+		"FROM chart_of_account coa "+
+		"INNER JOIN journal_entry_line jel ON jel.chart_of_account_id = coa.id "+
+		"INNER JOIN journal_entry je ON je.id = jel.journal_entry_id "+
+		"WHERE "+where+" GROUP BY coa.code, coa.name, coa.account_type ORDER BY coa.code;", args...).QueryRows(&balances); e != nil {
+		return
+	}
+
+	m = make(map[string]*accountBalance)
+	for _, b := range balances {
+		m[b.Code] = b
+		codes = append(codes, b.Code)
+	}
+
+	return
+}
+
+// change menghitung persentase perubahan nilai periode berjalan terhadap periode pembanding.
+func change(amount float64, previous float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+
+	return common.FloatPrecision((amount-previous)/math.Abs(previous)*100, 2)
+}
+
+// line membuat baris laporan beserta persentase perubahannya.
+func line(code string, name string, amount float64, previous float64) *StatementLine {
+	amount = common.FloatPrecision(amount, 2)
+	previous = common.FloatPrecision(previous, 2)
+
+	return &StatementLine{Code: code, Name: name, Amount: amount, Previous: previous, Change: change(amount, previous)}
+}
+
+// section mengelompokkan akun yang sesuai filter ke dalam section laporan,
+// sign -1 dipakai untuk akun bersaldo normal credit agar nilainya positif.
+func section(name string, current map[string]*accountBalance, previous map[string]*accountBalance, codes []string, sign float64, filter func(*accountBalance) bool) *StatementSection {
+	s := &StatementSection{Name: name}
+
+	var amount, prev float64
+	for _, code := range codes {
+		a := current[code]
+		if a == nil {
+			a = previous[code]
+		}
+		if !filter(a) {
+			continue
+		}
+
+		var cur, pre float64
+		if b := current[code]; b != nil {
+			cur = b.Balance * sign
+		}
+		if b := previous[code]; b != nil {
+			pre = b.Balance * sign
+		}
+
+		s.Lines = append(s.Lines, line(a.Code, a.Name, cur, pre))
+		amount += cur
+		prev += pre
+	}
+
+	s.Total = line("", "Total "+name, amount, prev)
+
+	return s
+}
+
+// mergeCodes menggabungkan kode akun periode berjalan dan pembanding dengan urutan kode.
+func mergeCodes(a []string, b []string) (codes []string) {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j >= len(b) || (i < len(a) && a[i] < b[j]):
+			codes = append(codes, a[i])
+			i++
+		case i >= len(a) || b[j] < a[i]:
+			codes = append(codes, b[j])
+			j++
+		default:
+			codes = append(codes, a[i])
+			i++
+			j++
+		}
+	}
+
+	return
+}
+
+// GetProfitLoss menghitung laporan laba rugi dari jurnal general ledger, pendapatan penjualan dari sales invoice,
+// retur penjualan, harga pokok penjualan dari cost fifo fulfillment dan beban operasional.
+func GetProfitLoss(period string, mode string) (m *ProfitLoss, e error) {
+	var start, end, prevStart, prevEnd time.Time
+	if start, end, prevStart, prevEnd, e = statementPeriod(period, mode); e != nil {
+		return nil, e
+	}
+
+	var current, previous map[string]*accountBalance
+	var codes, prevCodes []string
+	if current, codes, e = accountBalances(start, end); e != nil {
+		return nil, e
+	}
+	if previous, prevCodes, e = accountBalances(prevStart, prevEnd); e != nil {
+		return nil, e
+	}
+	codes = mergeCodes(codes, prevCodes)
+
+	if mode == "" {
+		mode = "month"
+	}
+
+	m = &ProfitLoss{
+		Mode:              mode,
+		StartDate:         start.Format("2006-01-02"),
+		EndDate:           end.Format("2006-01-02"),
+		PreviousStartDate: prevStart.Format("2006-01-02"),
+		PreviousEndDate:   prevEnd.Format("2006-01-02"),
+	}
+
+	m.Revenue = section("Pendapatan", current, previous, codes, -1, func(a *accountBalance) bool {
+		return a.AccountType == "revenue"
+	})
+	m.CostOfGoodsSold = section("Harga Pokok Penjualan", current, previous, codes, 1, func(a *accountBalance) bool {
+		return a.AccountType == "expense" && strings.HasPrefix(a.Code, cogsPrefix)
+	})
+	m.OperatingExpenses = section("Beban Operasional", current, previous, codes, 1, func(a *accountBalance) bool {
+		return a.AccountType == "expense" && !strings.HasPrefix(a.Code, cogsPrefix)
+	})
+
+	m.GrossProfit = line("", "Laba Kotor", m.Revenue.Total.Amount-m.CostOfGoodsSold.Total.Amount,
+		m.Revenue.Total.Previous-m.CostOfGoodsSold.Total.Previous)
+	m.NetProfit = line("", "Laba Bersih", m.GrossProfit.Amount-m.OperatingExpenses.Total.Amount,
+		m.GrossProfit.Previous-m.OperatingExpenses.Total.Previous)
+
+	return m, nil
+}
+
+// GetBalanceSheet menghitung neraca per tanggal akhir periode dari jurnal general ledger,
+// laba rugi yang belum ditutup ke laba ditahan ditampilkan sebagai laba tahun berjalan pada ekuitas.
+func GetBalanceSheet(period string, mode string) (m *BalanceSheet, e error) {
+	var end, prevEnd time.Time
+	if _, end, _, prevEnd, e = statementPeriod(period, mode); e != nil {
+		return nil, e
+	}
+
+	var current, previous map[string]*accountBalance
+	var codes, prevCodes []string
+	if current, codes, e = accountBalances(time.Time{}, end); e != nil {
+		return nil, e
+	}
+	if previous, prevCodes, e = accountBalances(time.Time{}, prevEnd); e != nil {
+		return nil, e
+	}
+	codes = mergeCodes(codes, prevCodes)
+
+	if mode == "" {
+		mode = "month"
+	}
+
+	m = &BalanceSheet{Mode: mode, Date: end.Format("2006-01-02"), PreviousDate: prevEnd.Format("2006-01-02")}
+
+	m.Assets = section("Aset", current, previous, codes, 1, func(a *accountBalance) bool {
+		return a.AccountType == "asset"
+	})
+	m.Liabilities = section("Kewajiban", current, previous, codes, -1, func(a *accountBalance) bool {
+		return a.AccountType == "liability"
+	})
+	m.Equity = section("Ekuitas", current, previous, codes, -1, func(a *accountBalance) bool {
+		return a.AccountType == "equity"
+	})
+
+	// laba tahun berjalan adalah saldo seluruh akun pendapatan dan beban
+	var earning, prevEarning float64
+	for _, code := range codes {
+		if a := current[code]; a != nil && (a.AccountType == "revenue" || a.AccountType == "expense") {
+			earning -= a.Balance
+		}
+		if a := previous[code]; a != nil && (a.AccountType == "revenue" || a.AccountType == "expense") {
+			prevEarning -= a.Balance
+		}
+	}
+	m.Equity.Lines = append(m.Equity.Lines, line("", "Laba Tahun Berjalan", earning, prevEarning))
+	m.Equity.Total = line("", m.Equity.Total.Name, m.Equity.Total.Amount+earning, m.Equity.Total.Previous+prevEarning)
+
+	m.TotalLiabilitiesEquity = line("", "Total Kewajiban dan Ekuitas", m.Liabilities.Total.Amount+m.Equity.Total.Amount,
+		m.Liabilities.Total.Previous+m.Equity.Total.Previous)
+	m.IsBalanced = m.Assets.Total.Amount == m.TotalLiabilitiesEquity.Amount
+
+	// rincian saldo bank per rekening
+	for _, l := range m.Assets.Lines {
+		if l.Code == "1102" {
+			l.Details = bankBalances(l, end, prevEnd)
+		}
+	}
+
+	return m, nil
+}
+
+// bankBalances rincian saldo bank per rekening dari buku rekening, rekening yang sudah diarsipkan
+// hanya ditampilkan apabila masih memiliki saldo, selisih dengan saldo akun bank ditampilkan sebagai bank lainnya.
+func bankBalances(bank *StatementLine, end time.Time, prevEnd time.Time) (lines []*StatementLine) {
+	var accounts []*model.BankAccount
+	orm.NewOrm().QueryTable(new(model.BankAccount)).Filter("account_type", "bank").OrderBy("id").All(&accounts)
+
+	amount, previous := bank.Amount, bank.Previous
+	for _, a := range accounts {
+		current, _ := bankAccount.Balance(a, end)
+		before, _ := bankAccount.Balance(a, prevEnd)
+		if a.IsArchived == int8(1) && current == 0 && before == 0 {
+			continue
+		}
+
+		lines = append(lines, line("", strings.TrimSpace(a.BankName+" "+a.BankNumber), current, before))
+		amount -= current
+		previous -= before
+	}
+
+	return append(lines, line("", "Bank Lainnya", amount, previous))
+}
+
+// statementRows menyusun baris laporan untuk export, section diawali judul dan diakhiri total,
+// baris nil adalah baris kosong pemisah antar section.
+func statementRows(items ...interface{}) (rows []*StatementLine) {
+	for _, i := range items {
+		switch v := i.(type) {
+		case *StatementSection:
+			rows = append(rows, &StatementLine{Name: strings.ToUpper(v.Name), header: true})
+			for _, l := range v.Lines {
+				rows = append(rows, l)
+				for _, d := range l.Details {
+					rows = append(rows, &StatementLine{Name: "    " + d.Name, Amount: d.Amount, Previous: d.Previous, Change: d.Change})
+				}
+			}
+			rows = append(rows, v.Total, nil)
+		case *StatementLine:
+			rows = append(rows, v, nil)
+		}
+	}
+
+	return
+}
+
+// ExportProfitLoss export laporan laba rugi ke file xlsx atau pdf.
+func ExportProfitLoss(period string, mode string, format string) (fileDir string, e error) {
+	var m *ProfitLoss
+	if m, e = GetProfitLoss(period, mode); e != nil {
+		return
+	}
+
+	title := "Laba Rugi"
+	periods := [2]string{m.StartDate + " - " + m.EndDate, m.PreviousStartDate + " - " + m.PreviousEndDate}
+	rows := statementRows(m.Revenue, m.CostOfGoodsSold, m.GrossProfit, m.OperatingExpenses, m.NetProfit)
+
+	return exportStatement(title, periods, rows, format)
+}
+
+// ExportBalanceSheet export laporan neraca ke file xlsx atau pdf.
+func ExportBalanceSheet(period string, mode string, format string) (fileDir string, e error) {
+	var m *BalanceSheet
+	if m, e = GetBalanceSheet(period, mode); e != nil {
+		return
+	}
+
+	title := "Neraca"
+	periods := [2]string{m.Date, m.PreviousDate}
+	rows := statementRows(m.Assets, m.Liabilities, m.Equity, m.TotalLiabilitiesEquity)
+
+	return exportStatement(title, periods, rows, format)
+}
+
+// exportStatement menulis baris laporan keuangan ke file xlsx atau pdf pada direktori export.
+func exportStatement(title string, periods [2]string, rows []*StatementLine, format string) (fileDir string, e error) {
+	dir := env.GetString("EXPORT_DIRECTORY", os.TempDir())
+	name := fmt.Sprintf("%s/%s-%s", dir, strings.Replace(title, " ", "", -1), time.Now().Format("200601021504"))
+
+	switch format {
+	case "", "xlsx":
+		file := xlsx.NewFile()
+
+		var sheet *xlsx.Sheet
+		if sheet, e = file.AddSheet(title); e != nil {
+			return
+		}
+
+		row := sheet.AddRow()
+		row.AddCell().Value = title
+		sheet.AddRow()
+
+		row = sheet.AddRow()
+		for _, h := range []string{"Kode", "Akun", periods[0], periods[1], "Perubahan (%)"} {
+			row.AddCell().Value = h
+		}
+
+		for _, l := range rows {
+			row = sheet.AddRow()
+			if l == nil {
+				continue
+			}
+
+			row.AddCell().Value = l.Code
+			row.AddCell().Value = l.Name
+			if !l.header {
+				row.AddCell().SetFloat(l.Amount)
+				row.AddCell().SetFloat(l.Previous)
+				row.AddCell().SetFloat(l.Change)
+			}
+		}
+
+		fileDir = name + ".xlsx"
+		e = file.Save(fileDir)
+	case "pdf":
+		d := &printing.Document{
+			Title: strings.ToUpper(title),
+			Code:  periods[0],
+			Date:  time.Now(),
+			Info:  [][2]string{{"Pembanding", periods[1]}},
+			Columns: []printing.Column{
+				{Title: "Kode", Width: 12, Align: "L"},
+				{Title: "Akun", Width: 40, Align: "L"},
+				{Title: periods[0], Width: 25, Align: "R"},
+				{Title: periods[1], Width: 25, Align: "R"},
+				{Title: "%", Width: 10, Align: "R"},
+			},
+		}
+
+		for _, l := range rows {
+			if l == nil {
+				continue
+			}
+
+			r := []string{l.Code, l.Name}
+			if !l.header {
+				r = append(r, printing.Money(l.Amount), printing.Money(l.Previous), fmt.Sprintf("%.2f", l.Change))
+			}
+			d.Rows = append(d.Rows, r)
+		}
+
+		var b []byte
+		if b, e = printing.PDF(d); e != nil {
+			return
+		}
+
+		fileDir = name + ".pdf"
+		e = ioutil.WriteFile(fileDir, b, 0644)
+	default:
+		e = errors.New("format is not supported")
+	}
+
+	return
+}
@@ -5,11 +5,15 @@
 package report
 
 import (
+	"path/filepath"
+
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/operating_expense"
 	"git.qasico.com/mj/api/src/purchase"
 	"git.qasico.com/mj/api/src/sales"
 
+	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/cuxs"
 	"github.com/labstack/echo"
 	"git.qasico.com/mj/api/src/finance_revenue"
@@ -31,6 +35,17 @@ func (h *Handler) URLMapping(r *echo.Group) {
 	r.GET("/purchase/summary", h.purchaseSummary, auth.CheckPrivilege("report_purchase"))
 	r.GET("/purchase-item/summary", h.purchaseItemSummary, auth.CheckPrivilege("report_purchase_item"))
 	r.GET("/bank/summary", h.bankSummary, auth.CheckPrivilege("report_bank"))
+	r.GET("/payable", h.payable, auth.CheckPrivilege("report_purchase"))
+	r.GET("/payable/summary", h.payableSummary, auth.CheckPrivilege("report_purchase"))
+	r.GET("/supplier-scorecard", h.supplierScorecard, auth.CheckPrivilege("report_supplier_scorecard"))
+	r.GET("/supplier-scorecard/export", h.supplierScorecardExport, auth.CheckPrivilege("report_supplier_scorecard"))
+	r.GET("/supplier-scorecard/:id", h.supplierScorecardDetail, auth.CheckPrivilege("report_supplier_scorecard"))
+	r.GET("/profit-loss", h.profitLoss, auth.CheckPrivilege("report_financial_statement"))
+	r.GET("/profit-loss/export", h.profitLossExport, auth.CheckPrivilege("report_financial_statement"))
+	r.GET("/balance-sheet", h.balanceSheet, auth.CheckPrivilege("report_financial_statement"))
+	r.GET("/balance-sheet/export", h.balanceSheetExport, auth.CheckPrivilege("report_financial_statement"))
+	r.GET("/operating-expense", h.operatingExpense, auth.CheckPrivilege("report_operating_expense"))
+	r.GET("/operating-expense/summary", h.operatingExpenseSummary, auth.CheckPrivilege("report_operating_expense"))
 }
 
 // salesItem endpoint to handle get http method.
@@ -209,3 +224,176 @@ func (h *Handler) bankSummary(c echo.Context) (e error) {
 
 	return ctx.Serve(e)
 }
+
+// payable endpoint untuk mengambil hutang purchase invoice beserta offset debit note.
+func (h *Handler) payable(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data []*PayableData
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetAccountPayables(param.Get("supplier_id")); e == nil {
+		ctx.Data(data, int64(len(data)))
+	}
+
+	return ctx.Serve(e)
+}
+
+// payableSummary endpoint untuk mengambil total hutang supplier.
+func (h *Handler) payableSummary(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data *PayableSummary
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetTotalPayable(param.Get("supplier_id")); e == nil {
+		ctx.Data(data)
+	}
+
+	return ctx.Serve(e)
+}
+
+// supplierScorecard endpoint untuk mengambil scorecard supplier pada periode start_date sampai end_date.
+func (h *Handler) supplierScorecard(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data []*SupplierScorecard
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetSupplierScorecards(param.Get("supplier_id"), param.Get("start_date"), param.Get("end_date")); e == nil {
+		ctx.Data(data, int64(len(data)))
+	}
+
+	return ctx.Serve(e)
+}
+
+// supplierScorecardDetail endpoint untuk drill-down scorecard supplier per purchase order dan histori harga.
+func (h *Handler) supplierScorecardDetail(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var data *ScorecardDetail
+
+	param, _ := ctx.FormParams()
+
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if data, e = GetSupplierScorecard(id, param.Get("start_date"), param.Get("end_date")); e == nil {
+			if data.Scorecard.SupplierName != "" {
+				ctx.Data(data)
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// supplierScorecardExport endpoint untuk download xlsx scorecard supplier.
+func (h *Handler) supplierScorecardExport(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var file string
+
+	param, _ := ctx.FormParams()
+
+	if file, e = ExportSupplierScorecard(param.Get("supplier_id"), param.Get("start_date"), param.Get("end_date")); e == nil {
+		return ctx.Attachment(file, filepath.Base(file))
+	}
+
+	return ctx.Serve(e)
+}
+
+// profitLoss endpoint untuk laporan laba rugi periode (YYYY-MM) dengan mode month atau ytd.
+func (h *Handler) profitLoss(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data *ProfitLoss
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetProfitLoss(param.Get("period"), param.Get("mode")); e == nil {
+		ctx.Data(data)
+	}
+
+	return ctx.Serve(e)
+}
+
+// profitLossExport endpoint untuk download laporan laba rugi dalam format xlsx atau pdf.
+func (h *Handler) profitLossExport(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var file string
+
+	param, _ := ctx.FormParams()
+
+	if file, e = ExportProfitLoss(param.Get("period"), param.Get("mode"), param.Get("format")); e == nil {
+		return ctx.Attachment(file, filepath.Base(file))
+	}
+
+	return ctx.Serve(e)
+}
+
+// balanceSheet endpoint untuk laporan neraca per akhir periode (YYYY-MM) dengan mode month atau ytd.
+func (h *Handler) balanceSheet(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data *BalanceSheet
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetBalanceSheet(param.Get("period"), param.Get("mode")); e == nil {
+		ctx.Data(data)
+	}
+
+	return ctx.Serve(e)
+}
+
+// balanceSheetExport endpoint untuk download laporan neraca dalam format xlsx atau pdf.
+func (h *Handler) balanceSheetExport(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var file string
+
+	param, _ := ctx.FormParams()
+
+	if file, e = ExportBalanceSheet(param.Get("period"), param.Get("mode"), param.Get("format")); e == nil {
+		return ctx.Attachment(file, filepath.Base(file))
+	}
+
+	return ctx.Serve(e)
+}
+
+// operatingExpense endpoint untuk mengambil daftar operating expense.
+func (h *Handler) operatingExpense(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data *[]model.OperatingExpense
+	var t int64
+	// get query string from request
+	rq := ctx.RequestQuery()
+	if data, t, e = operatingExpense.GetOperatingExpenses(rq); e == nil {
+		ctx.Data(data, t)
+	}
+
+	return ctx.Serve(e)
+}
+
+// operatingExpenseSummary endpoint untuk mengambil total operating expense per kategori
+// pada periode start_date sampai end_date.
+func (h *Handler) operatingExpenseSummary(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var data *OperatingExpenseSummary
+
+	param, _ := ctx.FormParams()
+
+	if data, e = GetOperatingExpenseSummary(param.Get("expense_category_id"), param.Get("start_date"), param.Get("end_date")); e == nil {
+		ctx.Data(data)
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,430 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/src/purchase"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/env"
+	"git.qasico.com/cuxs/orm"
+	"github.com/tealeg/xlsx"
+)
+
+// SupplierScorecard penilaian performa supplier pada periode tertentu,
+// seluruh rate dalam persen dan lead time dalam hari.
+type SupplierScorecard struct {
+	SupplierID       int64   `json:"-"`
+	SupplierName     string  `json:"supplier_name"`
+	Orders           int64   `json:"orders"`
+	OrderedQuantity  float64 `json:"ordered_quantity"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	FillRate         float64 `json:"fill_rate"`
+	Receivings       int64   `json:"receivings"`
+	OnTimeReceivings int64   `json:"on_time_receivings"`
+	OnTimeRate       float64 `json:"on_time_rate"`
+	PurchaseValue    float64 `json:"purchase_value"`
+	ReturnValue      float64 `json:"return_value"`
+	ReturnRate       float64 `json:"return_rate"`
+	LeadDays         float64 `json:"-"`
+	AverageLeadTime  float64 `json:"average_lead_time"`
+	PriceVariance    float64 `json:"price_variance"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, ID supplier akan diencrypt.
+func (m *SupplierScorecard) MarshalJSON() ([]byte, error) {
+	type Alias SupplierScorecard
+
+	return json.Marshal(&struct {
+		SupplierID string `json:"supplier_id"`
+		*Alias
+	}{
+		SupplierID: common.Encrypt(m.SupplierID),
+		Alias:      (*Alias)(m),
+	})
+}
+
+// ScorecardOrder rincian pengiriman, quantity diterima dan retur per purchase order supplier.
+type ScorecardOrder struct {
+	ID               int64     `json:"-"`
+	SupplierID       int64     `json:"-"`
+	SupplierName     string    `json:"-"`
+	Code             string    `json:"code"`
+	RecognitionDate  time.Time `json:"recognition_date"`
+	EtaDate          time.Time `json:"eta_date"`
+	TotalCharge      float64   `json:"total_charge"`
+	OrderedQuantity  float64   `json:"ordered_quantity"`
+	ReceivedQuantity float64   `json:"received_quantity"`
+	Receivings       int64     `json:"receivings"`
+	OnTimeReceivings int64     `json:"on_time_receivings"`
+	LeadDays         float64   `json:"lead_days"`
+	ReturnValue      float64   `json:"return_value"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, ID purchase order akan diencrypt.
+func (m *ScorecardOrder) MarshalJSON() ([]byte, error) {
+	type Alias ScorecardOrder
+
+	return json.Marshal(&struct {
+		ID string `json:"id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	})
+}
+
+// ScorecardDetail drill-down scorecard satu supplier beserta purchase order dan histori harga.
+type ScorecardDetail struct {
+	Scorecard      *SupplierScorecard       `json:"scorecard"`
+	PurchaseOrders []*ScorecardOrder        `json:"purchase_orders"`
+	PriceHistories []*purchase.PriceHistory `json:"price_histories"`
+}
+
+// scorecardPeriod parsing periode scorecard dengan format YYYY-MM-DD,
+// tanggal yang kosong dikembalikan sebagai zero time.
+func scorecardPeriod(startDate string, endDate string) (start time.Time, end time.Time, e error) {
+	if startDate != "" {
+		if start, e = time.Parse("2006-01-02", startDate); e != nil {
+			return
+		}
+	}
+
+	if endDate != "" {
+		end, e = time.Parse("2006-01-02", endDate)
+	}
+
+	return
+}
+
+// scorecardCondition membuat kondisi supplier dan periode recognition date purchase order,
+// tanggal menggunakan format YYYY-MM-DD dan tanggal yang kosong tidak difilter.
+func scorecardCondition(suppID int64, startDate string, endDate string) (where string, args []interface{}, e error) {
+	where = "po.is_deleted = 0 AND po.document_status IN ('new', 'active', 'finished')"
+
+	if suppID != 0 {
+		where += " AND po.supplier_id = ?"
+		args = append(args, suppID)
+	}
+
+	var start, end time.Time
+	if start, end, e = scorecardPeriod(startDate, endDate); e != nil {
+		return
+	}
+
+	if !start.IsZero() {
+		where += " AND po.recognition_date >= ?"
+		args = append(args, start.Format("2006-01-02"))
+	}
+
+	if !end.IsZero() {
+		where += " AND po.recognition_date <= ?"
+		args = append(args, end.Format("2006-01-02"))
+	}
+
+	return
+}
+
+// scorecardOrders mengambil purchase order supplier pada periode beserta receiving dan retur,
+// receiving dianggap tepat waktu apabila diterima paling lambat pada eta date.
+func scorecardOrders(suppID int64, startDate string, endDate string) (m []*ScorecardOrder, e error) {
+	var where string
+	var args []interface{}
+	if where, args, e = scorecardCondition(suppID, startDate, endDate); e != nil {
+		return
+	}
+
+	receiving := "FROM workorder_receiving wr WHERE wr.purchase_order_id = po.id AND wr.is_deleted = 0"
+	_, e = orm.NewOrm().Raw("SELECT po.id, po.supplier_id, p.full_name AS supplier_name, po.code, po.recognition_date, po.eta_date, po.total_charge, "+
+		"(SELECT COALESCE(SUM(poi.quantity), 0) FROM purchase_order_item poi WHERE poi.purchase_order_id = po.id) AS ordered_quantity, "+
+		"(SELECT COALESCE(SUM(wri.quantity), 0) FROM workorder_receiving_item wri INNER JOIN workorder_receiving wr ON wr.id = wri.workorder_receiving_id "+
+		"WHERE wr.purchase_order_id = po.id AND wr.is_deleted = 0) AS received_quantity, "+
+		"(SELECT COUNT(*) "+receiving+") AS receivings, "+
+		"(SELECT COUNT(*) "+receiving+" AND wr.recognition_date <= po.eta_date) AS on_time_receivings, "+
+		"(SELECT COALESCE(SUM(DATEDIFF(wr.recognition_date, po.recognition_date)), 0) "+receiving+") AS lead_days, "+
+		"(SELECT COALESCE(SUM(pr.total_amount), 0) FROM purchase_return pr WHERE pr.purchase_order_id = po.id AND pr.is_deleted = 0 "+
+		"AND pr.document_status != 'cancelled') AS return_value "+
+		"FROM purchase_order po INNER JOIN partnership p ON p.id = po.supplier_id "+
+		"WHERE "+where+" ORDER BY p.full_name, po.supplier_id, po.recognition_date, po.id;", args...).QueryRows(&m)
+
+	return
+}
+
+// priceHistories mengambil histori harga beli supplier pada periode dari purchase order.
+func priceHistories(suppID int64, startDate string, endDate string) (m []*purchase.PriceHistory, e error) {
+	var start, end time.Time
+	if start, end, e = scorecardPeriod(startDate, endDate); e != nil {
+		return
+	}
+
+	return purchase.GetSupplierPriceHistories(suppID, start, end)
+}
+
+// monthlyPrice total quantity dan nilai pembelian item variant dalam satu bulan.
+type monthlyPrice struct {
+	quantity float64
+	amount   float64
+}
+
+// priceVariance menghitung rata-rata perubahan harga (persen) dari bulan pertama ke bulan terakhir
+// setiap item variant, harga bulanan adalah rata-rata net price tertimbang quantity.
+// item yang hanya dibeli pada satu bulan tidak dihitung.
+func priceVariance(histories []*purchase.PriceHistory) float64 {
+	months := make(map[int64]map[string]*monthlyPrice)
+	for _, h := range histories {
+		if h.Quantity <= 0 {
+			continue
+		}
+
+		if months[h.ItemVariantID] == nil {
+			months[h.ItemVariantID] = make(map[string]*monthlyPrice)
+		}
+
+		period := h.RecognitionDate.Format("2006-01")
+		mp, ok := months[h.ItemVariantID][period]
+		if !ok {
+			mp = new(monthlyPrice)
+			months[h.ItemVariantID][period] = mp
+		}
+		mp.quantity += float64(h.Quantity)
+		mp.amount += float64(h.Quantity) * h.NetPrice
+	}
+
+	var total float64
+	var items int
+	for _, periods := range months {
+		var first, last string
+		for p := range periods {
+			if first == "" || p < first {
+				first = p
+			}
+			if p > last {
+				last = p
+			}
+		}
+
+		if first == last {
+			continue
+		}
+
+		firstPrice := periods[first].amount / periods[first].quantity
+		lastPrice := periods[last].amount / periods[last].quantity
+		if firstPrice > 0 {
+			total += (lastPrice - firstPrice) / firstPrice * 100
+			items++
+		}
+	}
+
+	if items == 0 {
+		return 0
+	}
+
+	return common.FloatPrecision(total/float64(items), 2)
+}
+
+// rate menghitung persentase value terhadap total.
+func rate(value float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return common.FloatPrecision(value/total*100, 2)
+}
+
+// summarize menghitung rate scorecard dari total purchase order.
+func (m *SupplierScorecard) summarize() {
+	m.FillRate = rate(m.ReceivedQuantity, m.OrderedQuantity)
+	m.OnTimeRate = rate(float64(m.OnTimeReceivings), float64(m.Receivings))
+	m.ReturnRate = rate(m.ReturnValue, m.PurchaseValue)
+	if m.Receivings > 0 {
+		m.AverageLeadTime = common.FloatPrecision(m.LeadDays/float64(m.Receivings), 2)
+	}
+}
+
+// scorecards mengelompokkan purchase order per supplier sesuai urutan nama supplier.
+func scorecards(orders []*ScorecardOrder) (m []*SupplierScorecard) {
+	index := make(map[int64]*SupplierScorecard)
+	for _, o := range orders {
+		s, ok := index[o.SupplierID]
+		if !ok {
+			s = &SupplierScorecard{SupplierID: o.SupplierID, SupplierName: o.SupplierName}
+			index[o.SupplierID] = s
+			m = append(m, s)
+		}
+
+		s.Orders++
+		s.OrderedQuantity += o.OrderedQuantity
+		s.ReceivedQuantity += o.ReceivedQuantity
+		s.Receivings += o.Receivings
+		s.OnTimeReceivings += o.OnTimeReceivings
+		s.PurchaseValue += o.TotalCharge
+		s.ReturnValue += o.ReturnValue
+		s.LeadDays += o.LeadDays
+	}
+
+	for _, s := range m {
+		s.summarize()
+	}
+
+	return
+}
+
+// GetSupplierScorecards mengambil scorecard seluruh supplier yang memiliki purchase order pada periode,
+// apabila supplier_id diisi hanya scorecard supplier tersebut yang diambil.
+func GetSupplierScorecards(suppID string, startDate string, endDate string) (m []*SupplierScorecard, e error) {
+	var sID int64
+	if suppID != "" {
+		if sID, e = common.Decrypt(suppID); e != nil {
+			return
+		}
+	}
+
+	var orders []*ScorecardOrder
+	if orders, e = scorecardOrders(sID, startDate, endDate); e != nil {
+		return
+	}
+
+	m = scorecards(orders)
+	for _, s := range m {
+		var histories []*PriceHistory
+		if histories, e = priceHistories(s.SupplierID, startDate, endDate); e != nil {
+			return nil, e
+		}
+		s.PriceVariance = priceVariance(histories)
+	}
+
+	return
+}
+
+// GetSupplierScorecard mengambil drill-down scorecard supplier berupa purchase order dan histori harga.
+func GetSupplierScorecard(suppID int64, startDate string, endDate string) (m *ScorecardDetail, e error) {
+	m = new(ScorecardDetail)
+	if m.PurchaseOrders, e = scorecardOrders(suppID, startDate, endDate); e != nil {
+		return nil, e
+	}
+	if m.PriceHistories, e = priceHistories(suppID, startDate, endDate); e != nil {
+		return nil, e
+	}
+
+	if s := scorecards(m.PurchaseOrders); len(s) > 0 {
+		m.Scorecard = s[0]
+	} else {
+		m.Scorecard = &SupplierScorecard{SupplierID: suppID}
+		orm.NewOrm().Raw("SELECT full_name FROM partnership WHERE id = ?", suppID).QueryRow(&m.Scorecard.SupplierName)
+	}
+	m.Scorecard.PriceVariance = priceVariance(m.PriceHistories)
+
+	return
+}
+
+// ExportSupplierScorecard membuat file xlsx scorecard supplier beserta rincian purchase order
+// dan histori harga sebagai drill-down.
+func ExportSupplierScorecard(suppID string, startDate string, endDate string) (fileDir string, e error) {
+	var data []*SupplierScorecard
+	if data, e = GetSupplierScorecards(suppID, startDate, endDate); e != nil {
+		return
+	}
+
+	file := xlsx.NewFile()
+
+	var sheet *xlsx.Sheet
+	if sheet, e = file.AddSheet("Scorecard"); e != nil {
+		return
+	}
+
+	row := sheet.AddRow()
+	row.AddCell().Value = "Scorecard Supplier"
+	row = sheet.AddRow()
+	row.AddCell().Value = strings.TrimSpace(fmt.Sprintf("%s - %s", startDate, endDate))
+	sheet.AddRow()
+
+	row = sheet.AddRow()
+	for _, h := range []string{"Supplier", "Jumlah PO", "Qty Order", "Qty Diterima", "Fill Rate (%)", "Receiving", "Tepat Waktu", "On-Time Rate (%)",
+		"Nilai Pembelian", "Nilai Retur", "Return Rate (%)", "Rata-rata Lead Time (hari)", "Price Variance (%)"} {
+		row.AddCell().Value = h
+	}
+
+	for _, s := range data {
+		row = sheet.AddRow()
+		row.AddCell().Value = s.SupplierName
+		row.AddCell().SetInt64(s.Orders)
+		row.AddCell().SetFloat(s.OrderedQuantity)
+		row.AddCell().SetFloat(s.ReceivedQuantity)
+		row.AddCell().SetFloat(s.FillRate)
+		row.AddCell().SetInt64(s.Receivings)
+		row.AddCell().SetInt64(s.OnTimeReceivings)
+		row.AddCell().SetFloat(s.OnTimeRate)
+		row.AddCell().SetFloat(s.PurchaseValue)
+		row.AddCell().SetFloat(s.ReturnValue)
+		row.AddCell().SetFloat(s.ReturnRate)
+		row.AddCell().SetFloat(s.AverageLeadTime)
+		row.AddCell().SetFloat(s.PriceVariance)
+	}
+
+	var orderSheet, priceSheet *xlsx.Sheet
+	if orderSheet, e = file.AddSheet("Purchase Order"); e != nil {
+		return
+	}
+
+	row = orderSheet.AddRow()
+	for _, h := range []string{"Supplier", "Kode PO", "Tanggal", "ETA", "Total", "Qty Order", "Qty Diterima", "Receiving", "Tepat Waktu", "Lead Time (hari)", "Nilai Retur"} {
+		row.AddCell().Value = h
+	}
+
+	if priceSheet, e = file.AddSheet("Histori Harga"); e != nil {
+		return
+	}
+
+	row = priceSheet.AddRow()
+	for _, h := range []string{"Supplier", "Kode PO", "Tanggal", "Item", "Qty", "Harga Net"} {
+		row.AddCell().Value = h
+	}
+
+	for _, s := range data {
+		var detail *ScorecardDetail
+		if detail, e = GetSupplierScorecard(s.SupplierID, startDate, endDate); e != nil {
+			return
+		}
+
+		for _, o := range detail.PurchaseOrders {
+			row = orderSheet.AddRow()
+			row.AddCell().Value = s.SupplierName
+			row.AddCell().Value = o.Code
+			row.AddCell().Value = o.RecognitionDate.Format("02/01/2006")
+			row.AddCell().Value = o.EtaDate.Format("02/01/2006")
+			row.AddCell().SetFloat(o.TotalCharge)
+			row.AddCell().SetFloat(o.OrderedQuantity)
+			row.AddCell().SetFloat(o.ReceivedQuantity)
+			row.AddCell().SetInt64(o.Receivings)
+			row.AddCell().SetInt64(o.OnTimeReceivings)
+			row.AddCell().SetFloat(o.LeadDays)
+			row.AddCell().SetFloat(o.ReturnValue)
+		}
+
+		for _, h := range detail.PriceHistories {
+			row = priceSheet.AddRow()
+			row.AddCell().Value = s.SupplierName
+			row.AddCell().Value = h.PurchaseOrderCode
+			row.AddCell().Value = h.RecognitionDate.Format("02/01/2006")
+			row.AddCell().Value = h.ItemVariantName
+			row.AddCell().SetFloat(float64(h.Quantity))
+			row.AddCell().SetFloat(h.NetPrice)
+		}
+	}
+
+	dir := env.GetString("EXPORT_DIRECTORY", os.TempDir())
+	fileDir = fmt.Sprintf("%s/ScorecardSupplier-%s.xlsx", dir, time.Now().Format("200601021504"))
+	e = file.Save(fileDir)
+
+	return
+}
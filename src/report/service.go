@@ -5,6 +5,7 @@
 package report
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -329,3 +330,165 @@ func GetTotalRevenueBank(custID string, bankID string) (*RevenueData, error) {
 	}
 	return nil, e
 }
+
+// PayableData hutang per purchase invoice beserta pembayaran cash dan offset debit note.
+type PayableData struct {
+	ID              int64     `json:"-"`
+	Code            string    `json:"code"`
+	SupplierName    string    `json:"supplier_name"`
+	DueDate         time.Time `json:"due_date"`
+	TotalAmount     float64   `json:"total_amount"`
+	CashPaid        float64   `json:"cash_paid"`
+	DebitNoteOffset float64   `json:"debit_note_offset"`
+	Outstanding     float64   `json:"outstanding"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, ID purchase invoice akan diencrypt.
+func (m *PayableData) MarshalJSON() ([]byte, error) {
+	type Alias PayableData
+
+	return json.Marshal(&struct {
+		ID string `json:"id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	})
+}
+
+// PayableSummary total hutang supplier, pembayaran cash, offset debit note
+// dan saldo debit note yang belum dioffset.
+type PayableSummary struct {
+	TotalAmount      float64 `json:"total_amount"`
+	CashPaid         float64 `json:"cash_paid"`
+	DebitNoteOffset  float64 `json:"debit_note_offset"`
+	Outstanding      float64 `json:"outstanding"`
+	DebitNoteBalance float64 `json:"debit_note_balance"`
+}
+
+// GetAccountPayables mengambil hutang purchase invoice, pembayaran yang dihitung
+// hanya finance expense yang sudah cleared.
+func GetAccountPayables(suppID string) (m []*PayableData, e error) {
+	qb, _ := orm.NewQueryBuilder("mysql")
+	qb = qb.Select("purchase_invoice.id", "purchase_invoice.code", "partnership.full_name AS supplier_name", "purchase_invoice.due_date", "purchase_invoice.total_amount",
+		"COALESCE(SUM(IF(finance_expense.payment_method != 'debit_note', finance_expense.amount, 0)), 0) AS cash_paid",
+		"COALESCE(SUM(IF(finance_expense.payment_method = 'debit_note', finance_expense.amount, 0)), 0) AS debit_note_offset").
+		From("purchase_invoice").
+		InnerJoin("purchase_order").On("purchase_order.id = purchase_invoice.purchase_order_id").
+		InnerJoin("partnership").On("partnership.id = purchase_order.supplier_id").
+		LeftJoin("finance_expense").On("finance_expense.ref_id = purchase_invoice.id AND finance_expense.ref_type = 'purchase_invoice' " +
+		"AND finance_expense.document_status = 'cleared' AND finance_expense.is_deleted = 0").
+		Where("purchase_invoice.is_deleted = 0")
+
+	if suppID != "" {
+		sID, _ := common.Decrypt(suppID)
+		qb.And(fmt.Sprintf("purchase_order.supplier_id = %d", sID))
+	}
+
+	qb.GroupBy("purchase_invoice.id").OrderBy("purchase_invoice.due_date").Asc()
+
+	if _, e = orm.NewOrm().Raw(qb.String()).QueryRows(&m); e == nil {
+		for _, p := range m {
+			p.Outstanding = p.TotalAmount - p.CashPaid - p.DebitNoteOffset
+		}
+	}
+
+	return
+}
+
+// GetTotalPayable mengambil total hutang supplier beserta saldo debit note yang belum dioffset.
+func GetTotalPayable(suppID string) (s *PayableSummary, e error) {
+	var payables []*PayableData
+	if payables, e = GetAccountPayables(suppID); e != nil {
+		return nil, e
+	}
+
+	s = new(PayableSummary)
+	for _, p := range payables {
+		s.TotalAmount += p.TotalAmount
+		s.CashPaid += p.CashPaid
+		s.DebitNoteOffset += p.DebitNoteOffset
+		s.Outstanding += p.Outstanding
+	}
+
+	q := "SELECT COALESCE(SUM(balance), 0) FROM debit_note WHERE document_status = 'active'"
+	if suppID != "" {
+		sID, _ := common.Decrypt(suppID)
+		q += fmt.Sprintf(" AND partnership_id = %d", sID)
+	}
+	orm.NewOrm().Raw(q).QueryRow(&s.DebitNoteBalance)
+
+	return s, nil
+}
+
+// OperatingExpenseData total operating expense per kategori expense.
+type OperatingExpenseData struct {
+	ID           int64   `json:"-"`
+	Name         string  `json:"name"`
+	AccountCode  string  `json:"account_code"`
+	AccountName  string  `json:"account_name"`
+	Transactions int64   `json:"transactions"`
+	Total        float64 `json:"total"`
+}
+
+// MarshalJSON customized data struct when marshaling data
+// into JSON format, ID expense category akan diencrypt.
+func (m *OperatingExpenseData) MarshalJSON() ([]byte, error) {
+	type Alias OperatingExpenseData
+
+	return json.Marshal(&struct {
+		ID string `json:"id"`
+		*Alias
+	}{
+		ID:    common.Encrypt(m.ID),
+		Alias: (*Alias)(m),
+	})
+}
+
+// OperatingExpenseSummary total operating expense beserta rincian per kategori.
+type OperatingExpenseSummary struct {
+	Categories   []*OperatingExpenseData `json:"categories"`
+	Transactions int64                   `json:"transactions"`
+	Total        float64                 `json:"total"`
+}
+
+// GetOperatingExpenseSummary mengambil total operating expense yang sudah diapprove per kategori.
+func GetOperatingExpenseSummary(categoryID string, gteDate string, lteDate string) (s *OperatingExpenseSummary, e error) {
+	qb, _ := orm.NewQueryBuilder("mysql")
+	qb = qb.Select("expense_category.id", "expense_category.name", "chart_of_account.code AS account_code", "chart_of_account.name AS account_name",
+		"COUNT(operating_expense.id) AS transactions", "COALESCE(SUM(operating_expense.amount), 0) AS total").
+		From("operating_expense").
+		InnerJoin("expense_category").On("expense_category.id = operating_expense.expense_category_id").
+		InnerJoin("chart_of_account").On("chart_of_account.id = expense_category.chart_of_account_id").
+		Where("operating_expense.document_status = 'approved'")
+
+	if gteDate != "" {
+		gte, _ := util.FormatDateToTimestamp(time.RFC3339, gteDate)
+		qb.And(fmt.Sprintf("operating_expense.recognition_date >= '%s'", gte.Format("2006-01-02")))
+	}
+
+	if lteDate != "" {
+		lte, _ := util.FormatDateToTimestamp(time.RFC3339, lteDate)
+		qb.And(fmt.Sprintf("operating_expense.recognition_date <= '%s'", lte.Format("2006-01-02")))
+	}
+
+	if categoryID != "" {
+		cID, _ := common.Decrypt(categoryID)
+		qb.And(fmt.Sprintf("operating_expense.expense_category_id = %d", cID))
+	}
+
+	qb.GroupBy("expense_category.id").OrderBy("total").Desc()
+
+	s = new(OperatingExpenseSummary)
+	if _, e = orm.NewOrm().Raw(qb.String()).QueryRows(&s.Categories); e != nil {
+		return nil, e
+	}
+
+	for _, c := range s.Categories {
+		s.Transactions += c.Transactions
+		s.Total += c.Total
+	}
+
+	return s, nil
+}
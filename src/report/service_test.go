@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/purchase"
 
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/orm"
@@ -243,3 +244,284 @@ func TestGetTotalPurchaseSummary(t *testing.T) {
 	assert.Equal(t, float64(100000), ts2.Expenses)
 
 }
+
+func TestGetAccountPayables(t *testing.T) {
+	supp := model.DummyPartnership()
+	supp.Save()
+
+	po := model.DummyPurchaseOrder()
+	po.IsDeleted = 0
+	po.Supplier = supp
+	po.Save()
+
+	pi := model.DummyPurchaseInvoice()
+	pi.PurchaseOrder = po
+	pi.TotalAmount = 100000
+	pi.IsDeleted = 0
+	pi.Save()
+
+	for pm, amount := range map[string]float64{"cash": 30000, "debit_note": 20000} {
+		fe := model.DummyFinanceExpense()
+		fe.RefType = "purchase_invoice"
+		fe.RefID = uint64(pi.ID)
+		fe.PaymentMethod = pm
+		fe.Amount = amount
+		fe.DocumentStatus = "cleared"
+		fe.IsDeleted = 0
+		fe.Save()
+	}
+
+	dn := model.DummyDebitNote()
+	dn.Partnership = supp
+	dn.Balance = 15000
+	dn.DocumentStatus = "active"
+	dn.Save()
+
+	s := common.Encrypt(supp.ID)
+
+	m, e := GetAccountPayables(s)
+	assert.NoError(t, e)
+	assert.Len(t, m, 1)
+	assert.Equal(t, float64(30000), m[0].CashPaid)
+	assert.Equal(t, float64(20000), m[0].DebitNoteOffset)
+	assert.Equal(t, float64(50000), m[0].Outstanding)
+
+	ts, e := GetTotalPayable(s)
+	assert.NoError(t, e)
+	assert.Equal(t, float64(100000), ts.TotalAmount)
+	assert.Equal(t, float64(50000), ts.Outstanding)
+	assert.Equal(t, float64(15000), ts.DebitNoteBalance)
+}
+
+func TestGetOperatingExpenseSummary(t *testing.T) {
+	ec := model.DummyExpenseCategory()
+	date := time.Date(2003, time.March, 10, 0, 0, 0, 0, time.Local)
+
+	for status, amount := range map[string]float64{"approved": 30000, "new": 20000, "cancelled": 10000} {
+		oe := model.DummyOperatingExpense()
+		oe.ExpenseCategory = ec
+		oe.RecognitionDate = date
+		oe.DocumentStatus = status
+		oe.Amount = amount
+		oe.Save()
+	}
+
+	c := common.Encrypt(ec.ID)
+
+	s, e := GetOperatingExpenseSummary(c, "2003-03-01", "2003-03-31")
+	assert.NoError(t, e)
+	assert.Len(t, s.Categories, 1)
+	assert.Equal(t, "6101", s.Categories[0].AccountCode)
+	assert.Equal(t, int64(1), s.Transactions)
+	assert.Equal(t, float64(30000), s.Total)
+
+	s, e = GetOperatingExpenseSummary(c, "2003-04-01", "2003-04-30")
+	assert.NoError(t, e)
+	assert.Equal(t, float64(0), s.Total)
+}
+
+func TestPriceVariance(t *testing.T) {
+	month := func(m time.Month) time.Time {
+		return time.Date(2019, m, 10, 0, 0, 0, 0, time.Local)
+	}
+	histories := []*purchase.PriceHistory{
+		{ItemVariantID: 1, RecognitionDate: month(2), Quantity: 10, NetPrice: 1100},
+		{ItemVariantID: 1, RecognitionDate: month(1), Quantity: 10, NetPrice: 1000},
+		// harga bulanan dirata-rata sesuai quantity
+		{ItemVariantID: 2, RecognitionDate: month(3), Quantity: 10, NetPrice: 1900},
+		{ItemVariantID: 2, RecognitionDate: month(1), Quantity: 30, NetPrice: 1800},
+		{ItemVariantID: 2, RecognitionDate: month(1), Quantity: 10, NetPrice: 2600},
+		// item yang hanya dibeli satu bulan tidak dihitung
+		{ItemVariantID: 3, RecognitionDate: month(2), Quantity: 10, NetPrice: 5000},
+	}
+
+	assert.Equal(t, float64(2.5), priceVariance(histories))
+	assert.Equal(t, float64(0), priceVariance(nil))
+}
+
+func TestGetSupplierScorecards(t *testing.T) {
+	supp := model.DummyPartnership()
+	supp.PartnershipType = "supplier"
+	supp.IsDeleted = 0
+	supp.Save()
+
+	order := func(date time.Time, price float64) *model.PurchaseOrderItem {
+		po := model.DummyPurchaseOrder()
+		po.Supplier = supp
+		po.RecognitionDate = date
+		po.EtaDate = date.AddDate(0, 0, 3)
+		po.TotalCharge = 10 * price
+		po.DocumentStatus = "active"
+		po.IsDeleted = 0
+		po.Save()
+
+		poi := model.DummyPurchaseOrderItem()
+		poi.PurchaseOrder = po
+		poi.Quantity = 10
+		poi.UnitPrice = price
+		poi.Discount = 0
+		poi.Save()
+
+		return poi
+	}
+
+	receive := func(poi *model.PurchaseOrderItem, date time.Time, qty float32) {
+		wr := model.DummyWorkorderReceiving()
+		wr.PurchaseOrder = poi.PurchaseOrder
+		wr.RecognitionDate = date
+		wr.IsDeleted = 0
+		wr.Save()
+
+		wri := model.DummyWorkorderReceivingItem()
+		wri.WorkorderReceiving = wr
+		wri.PurchaseOrderItem = poi
+		wri.Quantity = qty
+		wri.Save()
+	}
+
+	jan := time.Date(2019, 1, 10, 0, 0, 0, 0, time.Local)
+	feb := time.Date(2019, 2, 10, 0, 0, 0, 0, time.Local)
+
+	// order pertama diterima tepat waktu, order kedua terlambat dan kurang
+	poi1 := order(jan, 1000)
+	receive(poi1, jan.AddDate(0, 0, 2), 10)
+	poi2 := order(feb, 1100)
+	poi2.ItemVariant = poi1.ItemVariant
+	poi2.Save()
+	receive(poi2, feb.AddDate(0, 0, 6), 6)
+
+	pr := model.DummyPurchaseReturn()
+	pr.PurchaseOrder = poi1.PurchaseOrder
+	pr.TotalAmount = 2100
+	pr.DocumentStatus = "finished"
+	pr.IsDeleted = 0
+	pr.Save()
+
+	m, e := GetSupplierScorecards(common.Encrypt(supp.ID), "2019-01-01", "2019-02-28")
+	assert.NoError(t, e)
+	assert.Len(t, m, 1)
+	assert.Equal(t, int64(2), m[0].Orders)
+	assert.Equal(t, float64(80), m[0].FillRate)
+	assert.Equal(t, float64(50), m[0].OnTimeRate)
+	assert.Equal(t, float64(10), m[0].ReturnRate)
+	assert.Equal(t, float64(4), m[0].AverageLeadTime)
+	assert.Equal(t, float64(10), m[0].PriceVariance)
+
+	d, e := GetSupplierScorecard(supp.ID, "2019-02-01", "2019-02-28")
+	assert.NoError(t, e)
+	assert.Len(t, d.PurchaseOrders, 1)
+	assert.Len(t, d.PriceHistories, 1)
+	assert.Equal(t, float64(60), d.Scorecard.FillRate)
+
+	_, e = GetSupplierScorecards("", "01-2019", "")
+	assert.Error(t, e)
+
+	f, e := ExportSupplierScorecard(common.Encrypt(supp.ID), "2019-01-01", "2019-02-28")
+	assert.NoError(t, e)
+	assert.Contains(t, f, ".xlsx")
+}
+
+// dummyJournal membuat jurnal dengan satu baris debit dan satu baris credit pada akun yang sudah diseed.
+func dummyJournal(date string, debit string, credit string, amount float64) {
+	d, _ := time.Parse("2006-01-02", date)
+	je := &model.JournalEntry{Code: common.RandomStr(10), RecognitionDate: d, RefType: "test", TotalDebit: amount, TotalCredit: amount, CreatedAt: time.Now()}
+	je.Save()
+
+	for _, l := range []struct {
+		code   string
+		debit  float64
+		credit float64
+	}{{debit, amount, 0}, {credit, 0, amount}} {
+		coa := &model.ChartOfAccount{Code: l.code}
+		coa.Read("Code")
+		jel := &model.JournalEntryLine{JournalEntry: je, ChartOfAccount: coa, Debit: l.debit, Credit: l.credit}
+		jel.Save()
+	}
+}
+
+func TestStatementPeriod(t *testing.T) {
+	start, end, prevStart, prevEnd, e := statementPeriod("2001-03", "month")
+	assert.NoError(t, e)
+	assert.Equal(t, "2001-03-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2001-03-31", end.Format("2006-01-02"))
+	assert.Equal(t, "2001-02-01", prevStart.Format("2006-01-02"))
+	assert.Equal(t, "2001-02-28", prevEnd.Format("2006-01-02"))
+
+	start, end, prevStart, prevEnd, e = statementPeriod("2001-03", "ytd")
+	assert.NoError(t, e)
+	assert.Equal(t, "2001-01-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2001-03-31", end.Format("2006-01-02"))
+	assert.Equal(t, "2000-01-01", prevStart.Format("2006-01-02"))
+	assert.Equal(t, "2000-03-31", prevEnd.Format("2006-01-02"))
+
+	// bulan berjalan berakhir hari ini
+	_, end, _, _, e = statementPeriod("", "")
+	assert.NoError(t, e)
+	assert.Equal(t, time.Now().Format("2006-01-02"), end.Format("2006-01-02"))
+
+	_, _, _, _, e = statementPeriod("2001-13", "month")
+	assert.Error(t, e)
+	_, _, _, _, e = statementPeriod("2001-03", "week")
+	assert.Error(t, e)
+}
+
+func TestGetProfitLoss(t *testing.T) {
+	dummyJournal("2001-04-10", "1201", "4101", 500)
+	dummyJournal("2001-05-10", "1201", "4101", 1000)
+	dummyJournal("2001-05-10", "5101", "1301", 600)
+	dummyJournal("2001-05-15", "6101", "1101", 100)
+
+	m, e := GetProfitLoss("2001-05", "month")
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1000), m.Revenue.Total.Amount)
+	assert.Equal(t, float64(500), m.Revenue.Total.Previous)
+	assert.Equal(t, float64(100), m.Revenue.Total.Change)
+	assert.Equal(t, float64(600), m.CostOfGoodsSold.Total.Amount)
+	assert.Equal(t, float64(400), m.GrossProfit.Amount)
+	assert.Equal(t, float64(100), m.OperatingExpenses.Total.Amount)
+	assert.Equal(t, float64(300), m.NetProfit.Amount)
+	assert.Equal(t, float64(500), m.NetProfit.Previous)
+
+	m, e = GetProfitLoss("2001-05", "ytd")
+	assert.NoError(t, e)
+	assert.Equal(t, float64(1500), m.Revenue.Total.Amount)
+	assert.Equal(t, float64(0), m.Revenue.Total.Previous)
+	assert.Equal(t, float64(800), m.NetProfit.Amount)
+}
+
+func TestGetBalanceSheet(t *testing.T) {
+	dummyJournal("2002-04-10", "1301", "2101", 2000)
+	dummyJournal("2002-05-10", "1201", "4101", 1000)
+	dummyJournal("2002-05-10", "5101", "1301", 600)
+
+	m, e := GetBalanceSheet("2002-05", "month")
+	assert.NoError(t, e)
+	assert.True(t, m.IsBalanced)
+	assert.Equal(t, "2002-05-31", m.Date)
+	assert.Equal(t, "2002-04-30", m.PreviousDate)
+	assert.Equal(t, m.Assets.Total.Amount, m.TotalLiabilitiesEquity.Amount)
+	assert.Equal(t, m.Assets.Total.Previous, m.TotalLiabilitiesEquity.Previous)
+
+	var earning *StatementLine
+	for _, l := range m.Equity.Lines {
+		if l.Name == "Laba Tahun Berjalan" {
+			earning = l
+		}
+	}
+	assert.NotNil(t, earning)
+}
+
+func TestExportStatement(t *testing.T) {
+	for _, format := range []string{"xlsx", "pdf"} {
+		file, e := ExportProfitLoss("2001-05", "month", format)
+		assert.NoError(t, e)
+		assert.NotEmpty(t, file)
+
+		file, e = ExportBalanceSheet("2001-05", "month", format)
+		assert.NoError(t, e)
+		assert.NotEmpty(t, file)
+	}
+
+	_, e := ExportProfitLoss("2001-05", "month", "doc")
+	assert.Error(t, e)
+}
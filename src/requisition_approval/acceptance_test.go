@@ -0,0 +1,119 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package requisitionApproval_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("requisition_approval_level")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	m := model.DummyRequisitionApprovalLevel()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/requisition-approval", "GET", http.StatusOK},
+		{"/v1/requisition-approval/" + common.Encrypt(m.ID), "GET", http.StatusOK},
+		{"/v1/requisition-approval/999999", "GET", http.StatusNotFound},
+		{"/v1/requisition-approval/999999", "DELETE", http.StatusNotFound},
+		{"/v1/requisition-approval/" + common.Encrypt(m.ID), "DELETE", http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_CreateApprovalLevel(t *testing.T) {
+	orm.NewOrm().Raw("DELETE FROM requisition_approval_level").Exec()
+
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	ug := common.Encrypt(model.DummyUsergroup().ID)
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"usergroup_id": ug, "level": 1, "min_amount": 0}, http.StatusOK},
+		// satu level hanya dimiliki satu usergroup
+		{tester.D{"usergroup_id": ug, "level": 1, "min_amount": 1000000}, http.StatusUnprocessableEntity},
+		{tester.D{"usergroup_id": ug, "level": 0}, http.StatusUnprocessableEntity},
+		{tester.D{"usergroup_id": "999999", "level": 2}, http.StatusUnprocessableEntity},
+		{tester.D{"usergroup_id": ug, "level": 2, "min_amount": 5000000}, http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/requisition-approval").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
+
+func TestApprovalLevelIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/requisition-approval", "GET", http.StatusUnauthorized},
+		{"/v1/requisition-approval", "POST", http.StatusUnauthorized},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package requisitionApproval
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for requisition approval level.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("", h.get, auth.CheckPrivilege("setting_requisition_approval"))
+	r.GET("/:id", h.show, auth.CheckPrivilege("setting_requisition_approval"))
+	r.POST("", h.create, auth.CheckPrivilege("setting_requisition_approval"))
+	r.PUT("/:id", h.update, auth.CheckPrivilege("setting_requisition_approval"))
+	r.DELETE("/:id", h.delete, auth.CheckPrivilege("setting_requisition_approval"))
+}
+
+// get endpoint untuk mengambil semua level approval purchase requisition.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.RequisitionApprovalLevel
+	if data, total, e = GetApprovalLevels(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint untuk mengambil detail level approval purchase requisition.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.RequisitionApprovalLevel
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowApprovalLevel("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk membuat level approval purchase requisition.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r levelRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// update endpoint untuk mengubah level approval purchase requisition.
+func (h *Handler) update(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r levelRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.ApprovalLevel, e = ShowApprovalLevel("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save(); e == nil {
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// delete endpoint untuk menghapus level approval purchase requisition,
+// requisition yang sudah dibuat tetap menggunakan urutan approval sebelumnya.
+func (h *Handler) delete(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.RequisitionApprovalLevel
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowApprovalLevel("id", id); e == nil {
+			e = m.Delete()
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package requisitionApproval
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// levelRequest data struct that stored request data when requesting an create or update requisition approval level process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type levelRequest struct {
+	UsergroupID   string                          `json:"usergroup_id" valid:"required"`
+	Level         int                             `json:"level" valid:"required|gt:0"`
+	MinAmount     float64                         `json:"min_amount" valid:"gte:0"`
+	Note          string                          `json:"note"`
+	Session       *auth.SessionData               `json:"-"`
+	ApprovalLevel *model.RequisitionApprovalLevel `json:"-"`
+
+	Usergroup *model.Usergroup `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *levelRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if id, e := common.Decrypt(r.UsergroupID); e == nil {
+		r.Usergroup = &model.Usergroup{ID: id}
+		if e = r.Usergroup.Read(); e != nil {
+			o.Failure("usergroup_id", "usergroup_id doesn't exist")
+		}
+	} else {
+		o.Failure("usergroup_id", "usergroup_id cannot be decrypt")
+	}
+
+	// satu level hanya dimiliki satu usergroup
+	m := &model.RequisitionApprovalLevel{Level: r.Level}
+	if e := m.Read("Level"); e == nil && (r.ApprovalLevel == nil || r.ApprovalLevel.ID != m.ID) {
+		o.Failure("level", "approval level already exists")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *levelRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *levelRequest) Transform() *model.RequisitionApprovalLevel {
+	m := r.ApprovalLevel
+	if m == nil {
+		m = &model.RequisitionApprovalLevel{
+			CreatedBy: r.Session.User,
+			CreatedAt: time.Now(),
+		}
+	} else {
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	m.Usergroup = r.Usergroup
+	m.Level = r.Level
+	m.MinAmount = r.MinAmount
+	m.Note = r.Note
+
+	return m
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package requisitionApproval
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// GetApprovalLevels get all data requisition_approval_level that matched with query request parameters.
+// returning slices of approval level, total data without limit and error.
+func GetApprovalLevels(rq *orm.RequestQuery) (m *[]model.RequisitionApprovalLevel, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.RequisitionApprovalLevel))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.RequisitionApprovalLevel
+	if _, err = q.RelatedSel("Usergroup").OrderBy("level").All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowApprovalLevel untuk mengambil data detail level approval requisition berdasarkan param
+func ShowApprovalLevel(field string, values ...interface{}) (*model.RequisitionApprovalLevel, error) {
+	m := new(model.RequisitionApprovalLevel)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).RelatedSel("Usergroup").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package sales
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/partnership"
+)
+
+// approvalCheck hasil pengecekan diskon dan margin sales order terhadap batas diskon usergroup,
+// sales order perlu diapprove jika Reasons tidak kosong.
+type approvalCheck struct {
+	LineDiscount  float32
+	OrderDiscount float32
+	Margin        float32
+	TotalCost     float64
+	Reasons       []string
+}
+
+// discountLimit mengambil batas diskon usergroup user, nil jika usergroup tidak memiliki batas diskon.
+func discountLimit(user *model.User) *model.DiscountLimit {
+	if user == nil {
+		return nil
+	}
+
+	if user.Usergroup == nil {
+		u := &model.User{ID: user.ID}
+		if u.Read() != nil || u.Usergroup == nil {
+			return nil
+		}
+		user = u
+	}
+
+	m := &model.DiscountLimit{Usergroup: &model.Usergroup{ID: user.Usergroup.ID}}
+	if e := m.Read("Usergroup"); e != nil {
+		return nil
+	}
+
+	return m
+}
+
+// checkApproval cek diskon setiap item, diskon order dan margin sales order terhadap
+// batas diskon usergroup user yang membuat atau mengubah sales order.
+// margin dihitung dari total harga setelah diskon terhadap perkiraan total cost item.
+func checkApproval(so *model.SalesOrder, items []*model.SalesOrderItem, user *model.User) (c *approvalCheck) {
+	c = &approvalCheck{OrderDiscount: so.Discount}
+
+	for _, i := range items {
+		if i.Discount > c.LineDiscount {
+			c.LineDiscount = i.Discount
+		}
+
+		if i.ItemVariant != nil {
+			c.TotalCost += float64(i.Quantity) * estimateUnitCost(i.ItemVariant.ID)
+		}
+	}
+
+	c.TotalCost = common.FloatPrecision(c.TotalCost, 0)
+	if net := so.TotalPrice - so.DiscountAmount; net > 0 {
+		c.Margin = float32(common.FloatPrecision((net-c.TotalCost)/net*100, 2))
+	}
+
+	limit := discountLimit(user)
+	if limit == nil {
+		return
+	}
+
+	if c.LineDiscount > limit.MaxLineDiscount {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("line discount %v%% exceeds the limit of %v%%", c.LineDiscount, limit.MaxLineDiscount))
+	}
+
+	if c.OrderDiscount > limit.MaxOrderDiscount {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("order discount %v%% exceeds the limit of %v%%", c.OrderDiscount, limit.MaxOrderDiscount))
+	}
+
+	if limit.MinMargin > 0 && c.Margin < limit.MinMargin {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("margin %v%% is below the minimum of %v%%", c.Margin, limit.MinMargin))
+	}
+
+	return
+}
+
+// estimateUnitCost menghitung perkiraan cost item variant dari rata-rata unit cost stock yang masih tersedia,
+// jika stock sudah habis menggunakan unit cost stock warehouse terakhir, stock quarantine tidak dihitung.
+func estimateUnitCost(itemVariantID int64) (cost float64) {
+	o := orm.NewOrm()
+	o.Raw("SELECT COALESCE(SUM(available_stock * unit_cost) / SUM(available_stock), 0) FROM item_variant_stock "+
+		"WHERE item_variant_id = ? AND available_stock > 0 AND location = 'warehouse'", itemVariantID).QueryRow(&cost)
+
+	if cost == 0 {
+		o.Raw("SELECT unit_cost FROM item_variant_stock WHERE item_variant_id = ? AND location = 'warehouse' "+
+			"ORDER BY created_at DESC LIMIT 1", itemVariantID).QueryRow(&cost)
+	}
+
+	return
+}
+
+// requestApproval mencatat permintaan approval sales order beserta alasan melewati batas diskon.
+func requestApproval(so *model.SalesOrder, c *approvalCheck, user *model.User) error {
+	m := &model.SalesOrderApproval{
+		SalesOrder:    so,
+		Reason:        strings.Join(c.Reasons, "; "),
+		LineDiscount:  c.LineDiscount,
+		OrderDiscount: c.OrderDiscount,
+		Margin:        c.Margin,
+		TotalCost:     c.TotalCost,
+		Status:        "pending",
+		RequestedBy:   user,
+		RequestedAt:   time.Now(),
+	}
+
+	return m.Save()
+}
+
+// pendingApproval mengambil permintaan approval sales order yang masih pending.
+func pendingApproval(soID int64) (*model.SalesOrderApproval, error) {
+	m := new(model.SalesOrderApproval)
+	o := orm.NewOrm()
+	if e := o.QueryTable(m).Filter("sales_order_id", soID).Filter("status", "pending").OrderBy("-id").Limit(1).One(m); e != nil {
+		return nil, e
+	}
+
+	return m, nil
+}
+
+// GetSalesOrderApprovals mengambil riwayat permintaan approval diskon sales order.
+func GetSalesOrderApprovals(soID int64) (m []*model.SalesOrderApproval, e error) {
+	o := orm.NewOrm()
+	_, e = o.QueryTable(new(model.SalesOrderApproval)).Filter("sales_order_id", soID).RelatedSel("RequestedBy", "DecidedBy").OrderBy("-id").All(&m)
+
+	return
+}
+
+// ApproveSalesOrder approve sales order yang melewati batas diskon,
+// auto invoice dan auto fulfillment yang tertunda akan diproses.
+func ApproveSalesOrder(so *model.SalesOrder, note string, user *model.User) (e error) {
+	var m *model.SalesOrderApproval
+	if m, e = pendingApproval(so.ID); e == nil {
+		m.Status = "approved"
+		m.Note = note
+		m.DecidedBy = user
+		m.DecidedAt = time.Now()
+		if e = m.Save("Status", "Note", "DecidedBy", "DecidedAt"); e == nil {
+			so.DocumentStatus = "active"
+			so.UpdatedBy = user
+			so.UpdatedAt = time.Now()
+			if e = so.Save("DocumentStatus", "UpdatedBy", "UpdatedAt"); e == nil {
+				o := orm.NewOrm()
+				o.QueryTable(new(model.SalesOrderItem)).Filter("sales_order_id", so.ID).RelatedSel("ItemVariant").All(&so.SalesOrderItems)
+
+				e = processSalesOrder(so, user)
+			}
+		}
+	}
+
+	return
+}
+
+// RejectSalesOrder reject sales order yang melewati batas diskon,
+// sales order akan dibatalkan dan commited stock dikembalikan.
+func RejectSalesOrder(so *model.SalesOrder, note string, user *model.User) (e error) {
+	var m *model.SalesOrderApproval
+	if m, e = pendingApproval(so.ID); e == nil {
+		m.Status = "rejected"
+		m.Note = note
+		m.DecidedBy = user
+		m.DecidedAt = time.Now()
+		if e = m.Save("Status", "Note", "DecidedBy", "DecidedAt"); e == nil {
+			so.CancelledNote = note
+			if e = CancelSalesOrder(so, user); e == nil {
+				e = partnership.CalculationTotalDebt(so.Customer.ID)
+			}
+		}
+	}
+
+	return
+}
@@ -5,6 +5,7 @@
 package sales
 
 import (
+	"strconv"
 	"strings"
 
 	"git.qasico.com/mj/api/datastore/model"
@@ -29,6 +30,12 @@ func (h *Handler) URLMapping(r *echo.Group) {
 	r.PUT("/:id/cancel/request", h.cancelReq, auth.CheckPrivilege("sales_order_request_cancel"))
 	r.PUT("/:id/cancel/reject", h.rejectCancelReq, auth.CheckPrivilege("sales_order_reject_cancel"))
 	r.GET("/fulfillment/:id", h.fulfillment, auth.CheckPrivilege("sales_order_show"))
+	r.GET("/:id/approval", h.approval, auth.CheckPrivilege("sales_order_show"))
+	r.PUT("/:id/approve", h.approve, auth.CheckPrivilege("sales_order_approve_discount"))
+	r.PUT("/:id/reject", h.reject, auth.CheckPrivilege("sales_order_approve_discount"))
+	r.GET("/:id/revision", h.revision, auth.CheckPrivilege("sales_order_show"))
+	r.GET("/:id/revision/diff", h.revisionDiff, auth.CheckPrivilege("sales_order_show"))
+	r.POST("/:id/amend", h.amend, auth.CheckPrivilege("sales_order_amend"))
 }
 
 // get endpoint to handle get http method.
@@ -224,3 +231,132 @@ func (h *Handler) fulfillment(c echo.Context) (e error) {
 	}
 	return ctx.Serve(e)
 }
+
+// approval endpoint untuk mengambil riwayat approval diskon sales order.
+func (h *Handler) approval(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var m []*model.SalesOrderApproval
+
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = GetSalesOrderApprovals(id); e == nil {
+			ctx.Data(m, int64(len(m)))
+		}
+	}
+	return ctx.Serve(e)
+}
+
+// approve endpoint untuk approve sales order yang melewati batas diskon usergroup.
+func (h *Handler) approve(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var r approvalRequest
+	var session *auth.SessionData
+
+	if session, e = auth.UserSession(ctx); e == nil {
+		if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+			if r.SalesOrder, e = GetDetailSalesOrder(id, nil); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = ApproveSalesOrder(r.SalesOrder, r.Note, session.User); e == nil {
+						ctx.Data(r.SalesOrder)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+	return ctx.Serve(e)
+}
+
+// reject endpoint untuk reject sales order yang melewati batas diskon usergroup,
+// sales order yang direject akan dibatalkan.
+func (h *Handler) reject(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var r approvalRequest
+	var session *auth.SessionData
+
+	if session, e = auth.UserSession(ctx); e == nil {
+		if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+			if r.SalesOrder, e = GetDetailSalesOrder(id, nil); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = RejectSalesOrder(r.SalesOrder, r.Note, session.User); e == nil {
+						ctx.Data(r.SalesOrder)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+	return ctx.Serve(e)
+}
+
+// revision endpoint untuk mengambil riwayat revisi sales order beserta perubahan setiap revisi,
+// revisi sales order yang diamandemen ikut ditampilkan.
+func (h *Handler) revision(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var so *model.SalesOrder
+	var m []*model.SalesOrderRevision
+
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if so, e = GetDetailSalesOrder(id, nil); e == nil {
+			if m, e = GetSalesOrderRevisions(so); e == nil {
+				ctx.Data(m, int64(len(m)))
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+	return ctx.Serve(e)
+}
+
+// revisionDiff endpoint untuk membandingkan dua revisi sales order,
+// nomor revisi dikirim melalui query param from dan to.
+func (h *Handler) revisionDiff(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var so *model.SalesOrder
+	var m []*FieldChange
+
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if so, e = GetDetailSalesOrder(id, nil); e == nil {
+			from, _ := strconv.Atoi(ctx.QueryParam("from"))
+			to, _ := strconv.Atoi(ctx.QueryParam("to"))
+			if m, e = CompareRevision(so, from, to); e == nil {
+				ctx.Data(m)
+			} else {
+				e = echo.ErrNotFound
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+	return ctx.Serve(e)
+}
+
+// amend endpoint untuk amandemen sales order yang fulfillment-nya sudah berjalan,
+// sales order lama dibatalkan dan sales order baru dibuat dengan referensi sales order lama.
+func (h *Handler) amend(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var id int64
+	var r amendRequest
+	var sorder *model.SalesOrder
+
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+			if r.SalesOrder, e = GetDetailSalesOrder(id, nil); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if sorder, e = AmendSalesOrder(r.SalesOrder, &r, r.Session.User); e == nil {
+						ctx.Data(sorder)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+	return ctx.Serve(e)
+}
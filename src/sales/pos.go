@@ -0,0 +1,133 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package sales
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+)
+
+// POSLine item yang discan kasir pada transaksi point of sale.
+type POSLine struct {
+	ItemVariant *model.ItemVariant `json:"item_variant"`
+	PricingType *model.PricingType `json:"pricing_type"`
+	Quantity    float32            `json:"quantity"`
+	UnitPrice   float64            `json:"unit_price"`
+	Discount    float32            `json:"discount"`
+	Subtotal    float64            `json:"subtotal"`
+}
+
+// WalkInCustomer mengambil partnership walk-in customer yang digunakan untuk transaksi point of sale.
+func WalkInCustomer() (*model.Partnership, error) {
+	m := new(model.Partnership)
+	o := orm.NewOrm()
+	if e := o.QueryTable(m).Filter("is_default", 1).Filter("partnership_type", "customer").Filter("is_deleted", 0).OrderBy("-id").Limit(1).One(m); e != nil {
+		return nil, e
+	}
+
+	return m, nil
+}
+
+// POSUnitPrice menghitung harga jual item variant berdasarkan pricing type default,
+// aturan increment dan decrement pricing type turunan sama seperti validasi sales order.
+func POSUnitPrice(iv *model.ItemVariant) (pt *model.PricingType, price float64, e error) {
+	pt = new(model.PricingType)
+	if e = orm.NewOrm().QueryTable(pt).Filter("is_default", 1).RelatedSel().OrderBy("-id").Limit(1).One(pt); e != nil {
+		return nil, 0, errors.New("default pricing type is not set")
+	}
+
+	var ivp *model.ItemVariantPrice
+	if ivp, e = getItemVariantPricing(pt, iv); e != nil {
+		return nil, 0, errors.New("item variant price doesn't exist")
+	}
+
+	price = ivp.UnitPrice
+	if pt.ParentType != nil {
+		nominal := pt.Nominal
+		if pt.IsPercentage == int8(1) {
+			nominal = (pt.Nominal * ivp.UnitPrice) / float64(100)
+		}
+
+		if pt.RuleType == "increment" {
+			price += nominal
+		} else if pt.RuleType == "decrement" {
+			price -= nominal
+		}
+	}
+
+	if price <= 0 {
+		return nil, 0, errors.New("item variant price is not valid")
+	}
+
+	return pt, common.FloatPrecision(price, 0), nil
+}
+
+// POSSubtotal menghitung subtotal line dengan cara yang sama seperti sales order item.
+func POSSubtotal(l *POSLine) float64 {
+	amount := l.UnitPrice * float64(l.Quantity)
+	return common.FloatPrecision(amount-(amount*float64(l.Discount))/float64(100), 0)
+}
+
+// CreatePOSSalesOrder membuat sales order walk-in customer dari transaksi point of sale,
+// sales order langsung di-invoice, di-fulfill dan dibayar dengan payments yang diberikan.
+func CreatePOSSalesOrder(lines []*POSLine, payments []*model.FinanceRevenue, user *model.User, note string) (*model.SalesOrder, error) {
+	customer, e := WalkInCustomer()
+	if e != nil {
+		return nil, errors.New("walk-in customer is not found")
+	}
+
+	now := time.Now()
+	r := &createRequest{
+		RecognitionDate: now,
+		EtaDate:         now,
+		CustomerID:      common.Encrypt(customer.ID),
+		ShipmentAddress: customer.Address,
+		AutoInvoice:     int8(1),
+		AutoFullfilment: int8(1),
+		IsPaid:          int8(1),
+		Note:            strings.TrimSpace(note),
+		Session:         &auth.SessionData{User: user},
+		Payments:        payments,
+	}
+
+	for _, l := range lines {
+		r.SalesOrderItem = append(r.SalesOrderItem, salesOrderItem{
+			ItemVariantID: common.Encrypt(l.ItemVariant.ID),
+			PricingType:   common.Encrypt(l.PricingType.ID),
+			Quantity:      l.Quantity,
+			UnitPrice:     l.UnitPrice,
+			Discount:      l.Discount,
+		})
+	}
+
+	if o := r.Validate(); !o.Valid {
+		return nil, errors.New("sales order is not valid, please check the price and stock of the items")
+	}
+
+	if reasons := POSApprovalReasons(lines, user); len(reasons) > 0 {
+		return nil, errors.New("sales order needs approval: " + strings.Join(reasons, "; "))
+	}
+
+	return CreateSalesOrder(r)
+}
+
+// POSApprovalReasons cek diskon dan margin transaksi kasir terhadap batas diskon usergroup kasir,
+// transaksi kasir langsung dibayar sehingga tidak bisa menunggu approval sales order.
+func POSApprovalReasons(lines []*POSLine, user *model.User) []string {
+	so := new(model.SalesOrder)
+	items := make([]*model.SalesOrderItem, len(lines))
+	for i, l := range lines {
+		items[i] = &model.SalesOrderItem{ItemVariant: l.ItemVariant, Quantity: l.Quantity, Discount: l.Discount}
+		so.TotalPrice += POSSubtotal(l)
+	}
+
+	return checkApproval(so, items, user).Reasons
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package sales
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+)
+
+// CreateRecurringSalesOrder membuat sales order dari template recurring order pada tanggal date
+// melalui CreateSalesOrder, template divalidasi sama seperti sales order yang diinput manual.
+// Jika gagal, error berisi semua alasan kegagalan, misal plafon customer tidak cukup
+// atau item variant sudah diarsipkan.
+func CreateRecurringSalesOrder(ro *model.RecurringOrder, date time.Time) (so *model.SalesOrder, e error) {
+	r := recurringRequest(ro, date)
+
+	var failures []string
+	if ro.Customer.IsDeleted == int8(1) || ro.Customer.IsArchived == int8(1) {
+		failures = append(failures, fmt.Sprintf("customer %s is archived", ro.Customer.FullName))
+	}
+
+	for _, i := range ro.RecurringOrderItems {
+		iv := &model.ItemVariant{ID: i.ItemVariant.ID}
+		if iv.Read() != nil {
+			failures = append(failures, "item variant is not found")
+			continue
+		}
+
+		iv.Item.Read()
+		name := strings.TrimSpace(iv.Item.ItemName + " " + iv.VariantName)
+		if iv.IsDeleted == int8(1) || iv.IsArchived == int8(1) {
+			failures = append(failures, fmt.Sprintf("item variant %s is archived", name))
+		} else if iv.AvailableStock-iv.CommitedStock < i.Quantity {
+			failures = append(failures, fmt.Sprintf("stock item variant %s is not enough, available %v of %v", name, iv.AvailableStock-iv.CommitedStock, i.Quantity))
+		}
+	}
+
+	// plafon dicek dengan perkiraan total order, karena total charge baru dihitung saat transform
+	o := r.Validate()
+	if c := ro.Customer; c.OrderRule == "plafon" {
+		if charge := estimateCharge(r); c.TotalDebt+charge >= c.MaxPlafon {
+			failures = append(failures, fmt.Sprintf("customer has reached max plafon, total debt %.0f and order %.0f exceed plafon %.0f", c.TotalDebt, charge, c.MaxPlafon))
+		}
+	}
+
+	// pesan validasi sales order ikut dicatat supaya penyebab kegagalan bisa ditelusuri dari log
+	if !o.Valid {
+		msg := "sales order is not valid"
+		if b, e := json.Marshal(o); e == nil {
+			msg += ": " + string(b)
+		}
+		failures = append(failures, msg)
+	}
+
+	if len(failures) > 0 {
+		return nil, errors.New(strings.Join(failures, "; "))
+	}
+
+	return CreateSalesOrder(r)
+}
+
+// recurringRequest membuat request sales order dari template recurring order,
+// sales order dibuat atas nama user yang membuat template.
+func recurringRequest(ro *model.RecurringOrder, date time.Time) *createRequest {
+	r := &createRequest{
+		RecognitionDate:      date,
+		EtaDate:              date.AddDate(0, 0, int(ro.EtaDays)),
+		ShipmentAddress:      ro.ShipmentAddress,
+		CustomerID:           common.Encrypt(ro.Customer.ID),
+		Discount:             ro.Discount,
+		DiscountAmount:       ro.DiscountAmount,
+		IsPercentageDiscount: ro.IsPercentageDiscount,
+		Tax:                  ro.Tax,
+		ShipmentCost:         ro.ShipmentCost,
+		Note:                 strings.TrimSpace(fmt.Sprintf("Recurring order %s. %s", ro.Code, ro.Note)),
+		Session:              &auth.SessionData{User: ro.CreatedBy},
+	}
+
+	for _, i := range ro.RecurringOrderItems {
+		item := salesOrderItem{
+			ItemVariantID: common.Encrypt(i.ItemVariant.ID),
+			PricingType:   common.Encrypt(i.PricingType.ID),
+			Quantity:      i.Quantity,
+			UnitPrice:     i.UnitPrice,
+			Discount:      i.Discount,
+			Note:          i.Note,
+		}
+
+		if i.TaxCode != nil && i.TaxCode.ID != int64(0) {
+			item.TaxCodeID = common.Encrypt(i.TaxCode.ID)
+		}
+
+		r.SalesOrderItem = append(r.SalesOrderItem, item)
+	}
+
+	return r
+}
+
+// estimateCharge menghitung perkiraan total charge dari request yang sudah divalidasi.
+func estimateCharge(r *createRequest) float64 {
+	discount := r.DiscountAmount
+	if r.IsPercentageDiscount == int8(1) {
+		discount = (r.TotalPrice * float64(r.Discount)) / float64(100)
+	}
+
+	amount := r.TotalPrice - discount
+	return common.FloatPrecision(amount+(amount*float64(r.Tax))/float64(100)+r.ShipmentCost, 0)
+}
@@ -16,30 +16,34 @@ import (
 	"git.qasico.com/mj/api/src/inventory"
 	"git.qasico.com/mj/api/src/partnership"
 	"git.qasico.com/mj/api/src/pricing_type"
+	"git.qasico.com/mj/api/src/tax"
 )
 
 type createRequest struct {
-	Code                 string            `json:"code"`
-	RecognitionDate      time.Time         `json:"recognition_date" valid:"required"`
-	ReferencesID         string            `json:"references_id"`
-	EtaDate              time.Time         `json:"eta_date"`
-	ShipmentAddress      string            `json:"shipment_address"`
-	CustomerID           string            `json:"customer_id" valid:"required"`
-	AutoInvoice          int8              `json:"auto_invoice"`
-	AutoFullfilment      int8              `json:"auto_fullfilment"`
-	SalesOrderItem       []salesOrderItem  `json:"sales_order_item" valid:"required"`
-	Discount             float32           `json:"discount"`
-	DiscountAmount       float64           `json:"discount_amount" valid:"gte:0"`
-	IsPaid               int8              `json:"is_paid"`
-	Tax                  float32           `json:"tax" valid:"gte:0|lte:100"`
-	TaxAmount            float64           `json:"-"`
-	ShipmentCost         float64           `json:"shipment_cost" valid:"gte:0"`
-	TotalPrice           float64           `json:"-"`
-	TotalCharge          float64           `json:"-"`
-	TotalCost            float64           `json:"-"`
-	Note                 string            `json:"note"`
-	IsPercentageDiscount int8              `json:"is_percentage_discount" valid:"in:0,1"`
-	Session              *auth.SessionData `json:"-"`
+	Code                 string                  `json:"code"`
+	RecognitionDate      time.Time               `json:"recognition_date" valid:"required"`
+	ReferencesID         string                  `json:"references_id"`
+	EtaDate              time.Time               `json:"eta_date"`
+	ShipmentAddress      string                  `json:"shipment_address"`
+	CustomerID           string                  `json:"customer_id" valid:"required"`
+	SalesPersonID        string                  `json:"sales_person_id"`
+	AutoInvoice          int8                    `json:"auto_invoice"`
+	AutoFullfilment      int8                    `json:"auto_fullfilment"`
+	SalesOrderItem       []salesOrderItem        `json:"sales_order_item" valid:"required"`
+	Discount             float32                 `json:"discount"`
+	DiscountAmount       float64                 `json:"discount_amount" valid:"gte:0"`
+	IsPaid               int8                    `json:"is_paid"`
+	Tax                  float32                 `json:"tax" valid:"gte:0|lte:100"`
+	TaxAmount            float64                 `json:"-"`
+	ShipmentCost         float64                 `json:"shipment_cost" valid:"gte:0"`
+	TotalPrice           float64                 `json:"-"`
+	TotalCharge          float64                 `json:"-"`
+	TotalCost            float64                 `json:"-"`
+	Note                 string                  `json:"note"`
+	IsPercentageDiscount int8                    `json:"is_percentage_discount" valid:"in:0,1"`
+	Session              *auth.SessionData       `json:"-"`
+	RevisionNote         string                  `json:"-"`
+	Payments             []*model.FinanceRevenue `json:"-"`
 }
 
 type salesOrderItem struct {
@@ -49,6 +53,7 @@ type salesOrderItem struct {
 	Discount      float32 `json:"discount" valid:"gte:0|lte:100"`
 	UnitPrice     float64 `json:"unit_price" valid:"required|gt:0"`
 	PricingType   string  `json:"pricing_type" valid:"required"`
+	TaxCodeID     string  `json:"tax_code_id"`
 	Subtotal      float64 `json:"-"`
 	Note          string  `json:"note"`
 }
@@ -252,6 +257,14 @@ func (r *createRequest) Validate() *validation.Output {
 		r.TotalPrice += subtotal
 	}
 
+	for i, row := range r.SalesOrderItem {
+		tax.ValidateTaxCode(o, fmt.Sprintf("sales_order_item.%d.tax_code_id.invalid", i), row.TaxCodeID, r.Tax)
+	}
+
+	if r.SalesPersonID != "" && auth.SalesPerson(r.SalesPersonID) == nil {
+		o.Failure("sales_person_id", "sales_person_id doesn't exist")
+	}
+
 	if r.IsPercentageDiscount == int8(1) {
 		if r.Discount < 0 || r.Discount > float32(100) {
 			o.Failure("discount", "discount is less than and equal 0 or greater than 100")
@@ -292,6 +305,7 @@ func (r *createRequest) Transform() (sorder *model.SalesOrder) {
 	r.TotalCharge = common.FloatPrecision(curamount+r.TaxAmount+r.ShipmentCost, 0)
 	sorder = &model.SalesOrder{
 		Customer:             partner,
+		SalesPerson:          partner.SalesPerson,
 		Code:                 r.Code,
 		RecognitionDate:      r.RecognitionDate,
 		EtaDate:              r.EtaDate,
@@ -316,6 +330,17 @@ func (r *createRequest) Transform() (sorder *model.SalesOrder) {
 		CreatedAt:            time.Now(),
 	}
 
+	// sales person default mengikuti sales person dari customer
+	if sp := auth.SalesPerson(r.SalesPersonID); sp != nil {
+		sorder.SalesPerson = sp
+	}
+
+	if r.ReferencesID != "" {
+		if refID, e := common.Decrypt(r.ReferencesID); e == nil {
+			sorder.Reference = &model.SalesOrder{ID: refID}
+		}
+	}
+
 	if partner.IsDefault == int8(1) {
 		sorder.DocumentStatus = "active"
 		sorder.InvoiceStatus = "active"
@@ -336,6 +361,7 @@ func (r *createRequest) Transform() (sorder *model.SalesOrder) {
 		sorder.FulfillmentStatus = "active"
 	}
 
+	var lines []tax.Line
 	for _, row := range r.SalesOrderItem {
 		idItemVar, _ := common.Decrypt(row.ItemVariantID)
 		itemvar, _ := inventory.GetDetailItemVariant("id", idItemVar)
@@ -350,10 +376,20 @@ func (r *createRequest) Transform() (sorder *model.SalesOrder) {
 			Quantity:    row.Quantity,
 			UnitPrice:   row.UnitPrice,
 			Discount:    row.Discount,
+			TaxCode:     tax.TaxCodeByID(row.TaxCodeID),
 			Subtotal:    row.Subtotal,
 		}
 
 		sorder.SalesOrderItems = append(sorder.SalesOrderItems, &soitem)
+		lines = append(lines, &soitem)
+	}
+
+	// jika item memiliki tax code maka ppn dihitung per item
+	if taxAmount, exclusive, ok := tax.ApplyLineTax(lines, r.TotalPrice, discAmount); ok {
+		r.TaxAmount = taxAmount
+		r.TotalCharge = common.FloatPrecision(curamount+exclusive+r.ShipmentCost, 0)
+		sorder.TaxAmount = r.TaxAmount
+		sorder.TotalCharge = r.TotalCharge
 	}
 
 	return sorder
@@ -370,6 +406,7 @@ type updateRequest struct {
 	ShipmentCost         float64          `json:"shipment_cost" valid:"gte:0"`
 	Note                 string           `json:"note"`
 	IsPercentageDiscount int8             `json:"is_percentage_discount" valid:"in:0,1"`
+	SalesPersonID        string           `json:"sales_person_id"`
 	TaxAmount            float64          `json:"-"`
 	TotalPrice           float64          `json:"-"`
 	TotalCharge          float64          `json:"-"`
@@ -530,6 +567,14 @@ func (r *updateRequest) Validate() *validation.Output {
 		r.TotalPrice += subtotal
 	}
 
+	for i, row := range r.SalesOrderItem {
+		tax.ValidateTaxCode(o, fmt.Sprintf("sales_order_item.%d.tax_code_id.invalid", i), row.TaxCodeID, r.Tax)
+	}
+
+	if r.SalesPersonID != "" && auth.SalesPerson(r.SalesPersonID) == nil {
+		o.Failure("sales_person_id", "sales_person_id doesn't exist")
+	}
+
 	if r.IsPercentageDiscount == int8(1) {
 		if r.Discount < 0 || r.Discount > float32(100) {
 			o.Failure("discount", "discount is less than and equal 0 or greater than 100")
@@ -577,10 +622,14 @@ func (r *updateRequest) Transform(user *model.User) (*model.SalesOrder, []*model
 	so.TotalCost = r.TotalCost
 	so.IsPercentageDiscount = r.IsPercentageDiscount
 	so.Note = r.Note
+	if sp := auth.SalesPerson(r.SalesPersonID); sp != nil {
+		so.SalesPerson = sp
+	}
 	so.UpdatedAt = time.Now()
 	so.UpdatedBy = user
 
 	var items []*model.SalesOrderItem
+	var lines []tax.Line
 	for _, row := range r.SalesOrderItem {
 		var ID int64
 		if row.ID != "" {
@@ -600,10 +649,18 @@ func (r *updateRequest) Transform(user *model.User) (*model.SalesOrder, []*model
 			Quantity:    row.Quantity,
 			UnitPrice:   row.UnitPrice,
 			Discount:    row.Discount,
+			TaxCode:     tax.TaxCodeByID(row.TaxCodeID),
 			Subtotal:    row.Subtotal,
 			Note:        row.Note,
 		}
 		items = append(items, &soitem)
+		lines = append(lines, &soitem)
+	}
+
+	// jika item memiliki tax code maka ppn dihitung per item
+	if taxAmount, exclusive, ok := tax.ApplyLineTax(lines, r.TotalPrice, discAmount); ok {
+		so.TaxAmount = taxAmount
+		so.TotalCharge = common.FloatPrecision(curamount+exclusive+r.ShipmentCost, 0)
 	}
 
 	return so, items
@@ -694,3 +751,78 @@ func (r *rejectCancelRequest) Transform() {
 	// ubah isi model sales order
 	r.SalesOrder.DocumentStatus = "active"
 }
+
+// approvalRequest data struct that stored request data when requesting an approve or reject discount sales order process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type approvalRequest struct {
+	Note       string            `json:"note"`
+	SalesOrder *model.SalesOrder `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *approvalRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+	if r.SalesOrder.DocumentStatus != "pending_approval" {
+		o.Failure("document_status", "sales order is not waiting for approval")
+	}
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *approvalRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// amendRequest data struct that stored request data when requesting an amend sales order process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type amendRequest struct {
+	createRequest
+	Reason     string            `json:"reason"`
+	SalesOrder *model.SalesOrder `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *amendRequest) Validate() *validation.Output {
+	// customer sales order amandemen harus sama dengan sales order yang diamandemen,
+	// references id diisi oleh AmendSalesOrder karena sales order lama belum dibatalkan
+	r.CustomerID = common.Encrypt(r.SalesOrder.Customer.ID)
+	r.ReferencesID = ""
+
+	o := r.createRequest.Validate()
+
+	if r.SalesOrder.DocumentStatus != "new" && r.SalesOrder.DocumentStatus != "active" {
+		o.Failure("document_status", "sales order can not be amended")
+	}
+
+	if r.SalesOrder.FulfillmentStatus == "new" {
+		o.Failure("fulfillment_status", "fulfillment has not been started, update the sales order instead")
+	}
+
+	if r.SalesOrder.FulfillmentStatus == "finished" {
+		o.Failure("fulfillment_status", "fulfillment has been finished")
+	}
+
+	// item yang sudah terkirim tidak ikut dibatalkan, sehingga amandemen akan memesan ulang quantity tersebut
+	var fulfilled int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM sales_order_item WHERE sales_order_id = ? AND quantity_fulfillment > 0", r.SalesOrder.ID).QueryRow(&fulfilled)
+	if fulfilled > 0 {
+		o.Failure("fulfillment_status", "sales order already has fulfilled items and can not be amended")
+	}
+
+	if r.Reason == "" {
+		o.Failure("reason", "Reason is required")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *amendRequest) Messages() map[string]string {
+	return map[string]string{}
+}
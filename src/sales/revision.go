@@ -0,0 +1,371 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package sales
+
+import (
+	"encoding/json"
+	"time"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/partnership"
+	"git.qasico.com/mj/api/src/util"
+)
+
+// RevisionSnapshot data sales order beserta item pada saat revisi dibuat,
+// disimpan dalam bentuk json pada sales_order_revision.snapshot.
+type RevisionSnapshot struct {
+	Code                 string          `json:"code"`
+	RecognitionDate      string          `json:"recognition_date"`
+	EtaDate              string          `json:"eta_date"`
+	ShipmentAddress      string          `json:"shipment_address"`
+	SalesPersonID        string          `json:"sales_person_id"`
+	Discount             float32         `json:"discount"`
+	DiscountAmount       float64         `json:"discount_amount"`
+	IsPercentageDiscount int8            `json:"is_percentage_discount"`
+	Tax                  float32         `json:"tax"`
+	TaxAmount            float64         `json:"tax_amount"`
+	ShipmentCost         float64         `json:"shipment_cost"`
+	TotalPrice           float64         `json:"total_price"`
+	TotalCharge          float64         `json:"total_charge"`
+	Note                 string          `json:"note"`
+	Items                []*RevisionItem `json:"items"`
+}
+
+// RevisionItem data sales order item pada snapshot revisi.
+type RevisionItem struct {
+	ItemVariantID string  `json:"item_variant_id"`
+	ItemName      string  `json:"item_name"`
+	Quantity      float32 `json:"quantity"`
+	UnitPrice     float64 `json:"unit_price"`
+	Discount      float32 `json:"discount"`
+	TaxCodeID     string  `json:"tax_code_id"`
+	Subtotal      float64 `json:"subtotal"`
+	Note          string  `json:"note"`
+}
+
+// FieldChange perubahan satu field antara dua revisi, field item ditulis dengan format
+// sales_order_item.<item_variant_id>.<field>, item yang ditambah atau dihapus ditulis
+// dengan format sales_order_item.<item_variant_id> dengan old atau new bernilai null.
+type FieldChange struct {
+	Field    string      `json:"field"`
+	ItemName string      `json:"item_name,omitempty"`
+	Old      interface{} `json:"old"`
+	New      interface{} `json:"new"`
+}
+
+// snapshot membuat snapshot dari sales order dan item yang diberikan.
+func snapshot(so *model.SalesOrder, items []*model.SalesOrderItem) *RevisionSnapshot {
+	s := &RevisionSnapshot{
+		Code:                 so.Code,
+		RecognitionDate:      formatDate(so.RecognitionDate),
+		EtaDate:              formatDate(so.EtaDate),
+		ShipmentAddress:      so.ShipmentAddress,
+		Discount:             so.Discount,
+		DiscountAmount:       so.DiscountAmount,
+		IsPercentageDiscount: so.IsPercentageDiscount,
+		Tax:                  so.Tax,
+		TaxAmount:            so.TaxAmount,
+		ShipmentCost:         so.ShipmentCost,
+		TotalPrice:           so.TotalPrice,
+		TotalCharge:          so.TotalCharge,
+		Note:                 so.Note,
+		Items:                []*RevisionItem{},
+	}
+
+	if so.SalesPerson != nil && so.SalesPerson.ID != 0 {
+		s.SalesPersonID = common.Encrypt(so.SalesPerson.ID)
+	}
+
+	for _, i := range items {
+		ri := &RevisionItem{
+			Quantity:  i.Quantity,
+			UnitPrice: i.UnitPrice,
+			Discount:  i.Discount,
+			Subtotal:  i.Subtotal,
+			Note:      i.Note,
+		}
+
+		if i.ItemVariant != nil {
+			ri.ItemVariantID = common.Encrypt(i.ItemVariant.ID)
+			ri.ItemName = itemVariantName(i.ItemVariant)
+		}
+
+		if i.TaxCode != nil && i.TaxCode.ID != 0 {
+			ri.TaxCodeID = common.Encrypt(i.TaxCode.ID)
+		}
+
+		s.Items = append(s.Items, ri)
+	}
+
+	return s
+}
+
+// itemVariantName mengambil nama item variant seperti alias_name pada json item variant.
+func itemVariantName(iv *model.ItemVariant) string {
+	if iv.Item == nil || (iv.VariantName == "" && iv.ExternalName == "") {
+		iv.Read()
+	}
+
+	if iv.VariantName != "" {
+		return iv.VariantName
+	} else if iv.ExternalName != "" {
+		return iv.ExternalName
+	} else if iv.Item != nil {
+		iv.Item.Read()
+		return iv.Item.ItemName
+	}
+
+	return ""
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format("2006-01-02")
+}
+
+// lastRevision mengambil nomor revisi terakhir dari rangkaian sales order,
+// sales order hasil amandemen melanjutkan nomor revisi sales order referensinya.
+func lastRevision(so *model.SalesOrder) int {
+	m := new(model.SalesOrderRevision)
+	o := orm.NewOrm()
+	if e := o.QueryTable(m).Filter("sales_order_id__in", revisionChain(so)).OrderBy("-revision").Limit(1).One(m); e != nil {
+		return 0
+	}
+
+	return m.Revision
+}
+
+// revisionChain mengambil id sales order beserta seluruh sales order referensinya.
+func revisionChain(so *model.SalesOrder) (ids []int64) {
+	ids = append(ids, so.ID)
+
+	ref := so.Reference
+	for ref != nil && ref.ID != 0 && !util.HasElem(ids, ref.ID) {
+		ids = append(ids, ref.ID)
+
+		m := &model.SalesOrder{ID: ref.ID}
+		if m.Read() != nil {
+			break
+		}
+		ref = m.Reference
+	}
+
+	return
+}
+
+// createRevision menyimpan snapshot sales order dan item sebagai revisi baru.
+func createRevision(so *model.SalesOrder, items []*model.SalesOrderItem, user *model.User, note string) (*model.SalesOrderRevision, error) {
+	data, e := json.Marshal(snapshot(so, items))
+	if e != nil {
+		return nil, e
+	}
+
+	m := &model.SalesOrderRevision{
+		SalesOrder: &model.SalesOrder{ID: so.ID},
+		Revision:   lastRevision(so) + 1,
+		Snapshot:   string(data),
+		Note:       note,
+		CreatedBy:  user,
+		CreatedAt:  time.Now(),
+	}
+
+	if e = m.Save(); e != nil {
+		return nil, e
+	}
+
+	return m, nil
+}
+
+// ensureRevision membuat revisi awal dari data sales order pada database
+// untuk sales order yang dibuat sebelum revisi dicatat.
+func ensureRevision(soID int64) (e error) {
+	o := orm.NewOrm()
+	if o.QueryTable(new(model.SalesOrderRevision)).Filter("sales_order_id", soID).Exist() {
+		return nil
+	}
+
+	so := &model.SalesOrder{ID: soID}
+	if e = so.Read(); e != nil {
+		return e
+	}
+
+	var items []*model.SalesOrderItem
+	o.QueryTable(new(model.SalesOrderItem)).Filter("sales_order_id", soID).RelatedSel("ItemVariant").OrderBy("id").All(&items)
+
+	_, e = createRevision(so, items, so.CreatedBy, "initial revision")
+
+	return
+}
+
+// GetSalesOrderRevisions mengambil seluruh revisi sales order beserta revisi dari sales order
+// yang diamandemen, diurutkan dari revisi pertama dan dilengkapi perubahan terhadap revisi sebelumnya.
+func GetSalesOrderRevisions(so *model.SalesOrder) (m []*model.SalesOrderRevision, e error) {
+	o := orm.NewOrm()
+	if _, e = o.QueryTable(new(model.SalesOrderRevision)).Filter("sales_order_id__in", revisionChain(so)).RelatedSel("SalesOrder", "CreatedBy").OrderBy("revision").All(&m); e != nil {
+		return nil, e
+	}
+
+	var prev *RevisionSnapshot
+	for _, r := range m {
+		data := new(RevisionSnapshot)
+		if e = json.Unmarshal([]byte(r.Snapshot), data); e != nil {
+			return nil, e
+		}
+
+		r.Data = data
+		r.Changes = []*FieldChange{}
+		if prev != nil {
+			r.Changes = DiffRevision(prev, data)
+		}
+
+		prev = data
+	}
+
+	return
+}
+
+// DiffRevision membandingkan dua snapshot revisi dan mengembalikan field yang berubah,
+// item dicocokkan berdasarkan item variant.
+func DiffRevision(old *RevisionSnapshot, cur *RevisionSnapshot) (c []*FieldChange) {
+	c = []*FieldChange{}
+
+	of, nf := old.fields(), cur.fields()
+	for _, k := range revisionFields {
+		if of[k] != nf[k] {
+			c = append(c, &FieldChange{Field: k, Old: of[k], New: nf[k]})
+		}
+	}
+
+	oi := make(map[string]*RevisionItem)
+	for _, i := range old.Items {
+		oi[i.ItemVariantID] = i
+	}
+
+	ni := make(map[string]*RevisionItem)
+	for _, i := range cur.Items {
+		ni[i.ItemVariantID] = i
+
+		o, ok := oi[i.ItemVariantID]
+		if !ok {
+			c = append(c, &FieldChange{Field: "sales_order_item." + i.ItemVariantID, ItemName: i.ItemName, Old: nil, New: i})
+			continue
+		}
+
+		oif, nif := o.fields(), i.fields()
+		for _, k := range revisionItemFields {
+			if oif[k] != nif[k] {
+				c = append(c, &FieldChange{Field: "sales_order_item." + i.ItemVariantID + "." + k, ItemName: i.ItemName, Old: oif[k], New: nif[k]})
+			}
+		}
+	}
+
+	for _, i := range old.Items {
+		if _, ok := ni[i.ItemVariantID]; !ok {
+			c = append(c, &FieldChange{Field: "sales_order_item." + i.ItemVariantID, ItemName: i.ItemName, Old: i, New: nil})
+		}
+	}
+
+	return
+}
+
+var revisionFields = []string{"code", "recognition_date", "eta_date", "shipment_address", "sales_person_id", "discount", "discount_amount",
+	"is_percentage_discount", "tax", "tax_amount", "shipment_cost", "total_price", "total_charge", "note"}
+
+var revisionItemFields = []string{"quantity", "unit_price", "discount", "tax_code_id", "subtotal", "note"}
+
+func (s *RevisionSnapshot) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"code":                   s.Code,
+		"recognition_date":       s.RecognitionDate,
+		"eta_date":               s.EtaDate,
+		"shipment_address":       s.ShipmentAddress,
+		"sales_person_id":        s.SalesPersonID,
+		"discount":               s.Discount,
+		"discount_amount":        s.DiscountAmount,
+		"is_percentage_discount": s.IsPercentageDiscount,
+		"tax":                    s.Tax,
+		"tax_amount":             s.TaxAmount,
+		"shipment_cost":          s.ShipmentCost,
+		"total_price":            s.TotalPrice,
+		"total_charge":           s.TotalCharge,
+		"note":                   s.Note,
+	}
+}
+
+func (i *RevisionItem) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"quantity":    i.Quantity,
+		"unit_price":  i.UnitPrice,
+		"discount":    i.Discount,
+		"tax_code_id": i.TaxCodeID,
+		"subtotal":    i.Subtotal,
+		"note":        i.Note,
+	}
+}
+
+// CompareRevision membandingkan dua nomor revisi pada rangkaian sales order.
+func CompareRevision(so *model.SalesOrder, from int, to int) (c []*FieldChange, e error) {
+	var rev []*model.SalesOrderRevision
+	if rev, e = GetSalesOrderRevisions(so); e != nil {
+		return nil, e
+	}
+
+	var old, cur *RevisionSnapshot
+	for _, r := range rev {
+		if r.Revision == from {
+			old = r.Data.(*RevisionSnapshot)
+		}
+		if r.Revision == to {
+			cur = r.Data.(*RevisionSnapshot)
+		}
+	}
+
+	if old == nil || cur == nil {
+		return nil, orm.ErrNoRows
+	}
+
+	return DiffRevision(old, cur), nil
+}
+
+// AmendSalesOrder membuat sales order baru sebagai amandemen sales order yang fulfillment-nya
+// sudah berjalan, sales order lama dibatalkan dan menjadi referensi sales order baru
+// sehingga riwayat revisi tetap tersambung.
+// sales order baru dibuat terlebih dahulu, jika gagal sales order lama tidak ikut dibatalkan.
+func AmendSalesOrder(old *model.SalesOrder, r *amendRequest, user *model.User) (so *model.SalesOrder, e error) {
+	if e = ensureRevision(old.ID); e != nil {
+		return nil, e
+	}
+
+	r.ReferencesID = common.Encrypt(old.ID)
+	r.RevisionNote = "amendment of " + old.Code + ": " + r.Reason
+	if so, e = CreateSalesOrder(&r.createRequest); e != nil {
+		return nil, e
+	}
+
+	// total spend customer sudah berubah oleh sales order baru
+	if e = old.Customer.Read(); e != nil {
+		return nil, e
+	}
+
+	old.CancelledNote = "amended: " + r.Reason
+	if e = CancelSalesOrder(old, user); e != nil {
+		// batalkan kembali sales order baru agar tidak ada dua sales order aktif
+		so.CancelledNote = "amendment failed: " + e.Error()
+		so.Customer.Read()
+		CancelSalesOrder(so, user)
+		return nil, e
+	}
+
+	if e = partnership.CalculationTotalDebt(old.Customer.ID); e != nil {
+		return nil, e
+	}
+
+	return so, nil
+}
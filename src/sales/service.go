@@ -13,19 +13,30 @@ import (
 	"git.qasico.com/cuxs/common"
 	"git.qasico.com/cuxs/orm"
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/inventory"
 	"git.qasico.com/mj/api/src/partnership"
 	"git.qasico.com/mj/api/src/stock"
+	"git.qasico.com/mj/api/src/tax"
 	"git.qasico.com/mj/api/src/util"
+	"github.com/labstack/gommon/log"
 )
 
+// PaidSalesInvoice total pembayaran sales invoice, yaitu finance revenue yang cleared termasuk alokasi payment
+// ditambah pembayaran melalui invoice receipt yang sudah finished.
+func PaidSalesInvoice(si *model.SalesInvoice) (paid float64) {
+	orm.NewOrm().Raw("SELECT COALESCE((SELECT sum(amount) from finance_revenue where ref_id = ? AND ref_type = 'sales_invoice' AND document_status = 'cleared' AND is_deleted = 0), 0) + "+
+		"COALESCE((SELECT sum(iri.subtotal) from invoice_receipt_item iri INNER JOIN invoice_receipt ir ON ir.id = iri.invoice_receipt_id where iri.sales_invoice_id = ? AND ir.document_status = 'finished'), 0)", uint64(si.ID), si.ID).QueryRow(&paid)
+
+	return
+}
+
 // CalculateTotalPaidSI by Sales Invoice
 func CalculateTotalPaidSI(si *model.SalesInvoice) error {
 
 	o := orm.NewOrm()
 
-	// sum amount
-	_, err := o.Raw("update sales_invoice si set si.total_paid = (SELECT sum(amount) from finance_revenue where ref_id = ? AND ref_type = 'sales_invoice' AND document_status = 'cleared') where si.id = ?; ", uint64(si.ID), si.ID).Exec()
+	_, err := o.Raw("update sales_invoice si set si.total_paid = ? where si.id = ?; ", PaidSalesInvoice(si), si.ID).Exec()
 
 	si.Read()
 
@@ -243,13 +254,17 @@ func GetDetailSalesOrder(id int64, loadRelated []string) (*model.SalesOrder, err
 // CreateSalesOrder untuk simpan sales order
 func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error) {
 	sales = order.Transform()
-	
+
+	// sales order yang melewati batas diskon usergroup harus diapprove terlebih dahulu
+	check := checkApproval(sales, sales.SalesOrderItems, order.Session.User)
+	if len(check.Reasons) > 0 {
+		sales.DocumentStatus = "pending_approval"
+	}
+
 	// Masukkan data ke dalam database sesuai dengan list inputan
 	if err = sales.Save(); err != nil {
 		return nil, err
 	}
-	// wofulfillmentitem
-	var wofulfillmentitems []model.WorkorderFulfillmentItem
 	// Simpan Sales Order Items
 	for _, row := range sales.SalesOrderItems {
 		row.SalesOrder = &model.SalesOrder{ID: sales.ID}
@@ -272,6 +287,40 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 		} else {
 			return nil, err
 		}
+	}
+
+	// simpan revisi pertama sales order
+	note := order.RevisionNote
+	if note == "" {
+		note = "initial revision"
+	}
+	if _, err = createRevision(sales, sales.SalesOrderItems, order.Session.User, note); err != nil {
+		return nil, err
+	}
+
+	// auto invoice dan auto fulfillment baru diproses setelah sales order diapprove
+	if sales.DocumentStatus == "pending_approval" {
+		if err = requestApproval(sales, check, order.Session.User); err != nil {
+			return nil, err
+		}
+
+		return
+	}
+
+	if err = processSalesOrder(sales, order.Session.User, order.Payments...); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// processSalesOrder membuat sales invoice dan workorder fulfillment untuk sales order
+// yang auto invoice, auto fulfillment atau walk-in customer, lalu update document status sales order.
+// payments adalah pembayaran walk-in customer, jika kosong dibuat satu pembayaran cash sebesar total invoice.
+func processSalesOrder(sales *model.SalesOrder, user *model.User, payments ...*model.FinanceRevenue) (err error) {
+	// wofulfillmentitem
+	var wofulfillmentitems []model.WorkorderFulfillmentItem
+	for _, row := range sales.SalesOrderItems {
 		wofulfillmentitem := model.WorkorderFulfillmentItem{
 			SalesOrderItem: row,
 			Quantity:       row.Quantity,
@@ -282,7 +331,7 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 	var partner *model.Partnership
 	partner, err = partnership.GetPartnershipByField("id", sales.Customer.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if sales.AutoInvoice == 1 || partner.IsDefault == 1 || sales.AutoPaid == 1 {
@@ -290,7 +339,7 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 		// (untuk data yang diinput dapat melihat list inputan)
 		code, err := util.CodeGen("code_sales_invoice", "sales_invoice")
 		if err != nil {
-			return nil, err
+			return err
 		}
 		sales.Customer.Read()
 		sales.Read()
@@ -302,17 +351,23 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 			TotalAmount:     sales.TotalCharge,
 			DocumentStatus:  "new",
 			CreatedAt:       sales.CreatedAt,
-			CreatedBy:       order.Session.User,
+			CreatedBy:       user,
 		}
 		if err = sinvoice.Save(); err != nil {
-			return nil, err
+			return err
+		}
+		generalLedger.AutoPost("sales_invoice", sinvoice.ID)
+
+		// nomor faktur pajak yang gagal diberikan bisa diberikan manual dari menu tax
+		if e := tax.AssignTaxInvoiceNumber(&sinvoice); e != nil {
+			log.Error(e)
 		}
 
 		// cek apakah yang membuat sales order walk-in customer
 		if partner.IsDefault == int8(1) || sales.AutoPaid == int8(1) {
 			// buat kan finance revenue untuk sales invoice yang tealah dibuat pada auto-invoice
-			if err = createSOWalkInCustomerAutoInvoice(sales, &sinvoice); err != nil {
-				return nil, err
+			if err = createSOWalkInCustomerAutoInvoice(sales, &sinvoice, payments); err != nil {
+				return err
 			}
 		}
 
@@ -322,7 +377,7 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 		// (untuk data yang diinput dapat melihat list inputan)
 		code, err := util.CodeGen("code_fullfilment", "workorder_fulfillment")
 		if err != nil {
-			return nil, err
+			return err
 		}
 		sales.Customer.Read()
 		// Update fullfilment  status menjadi finished
@@ -332,18 +387,18 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 			ShippingAddress: sales.ShipmentAddress,
 			DocumentStatus:  "new",
 			CreatedAt:       sales.CreatedAt,
-			CreatedBy:       order.Session.User,
+			CreatedBy:       user,
 			Priority:        "routine",
 		}
 
 		if err = wofulfillment.Save(); err != nil {
-			return nil, err
+			return err
 		}
 
 		for _, row := range wofulfillmentitems {
 			row.WorkorderFulfillment = &wofulfillment
 			if err = row.Save(); err != nil {
-				return nil, err
+				return err
 			}
 		}
 		wofulfillmentID := wofulfillment.ID
@@ -355,16 +410,11 @@ func CreateSalesOrder(order *createRequest) (sales *model.SalesOrder, err error)
 		sales.DocumentStatus = "new"
 	}
 
-	if err = sales.Save("DocumentStatus"); err != nil {
-		return nil, err
-	}
-
-	return
+	return sales.Save("DocumentStatus")
 }
 
 // createSOWalkInCustomerAutoInvoice membuat finance revenue untuk sales order yang walk-in customer dan auto-invoice
-func createSOWalkInCustomerAutoInvoice(so *model.SalesOrder, si *model.SalesInvoice) (e error) {
-	var fr *model.FinanceRevenue
+func createSOWalkInCustomerAutoInvoice(so *model.SalesOrder, si *model.SalesInvoice, payments []*model.FinanceRevenue) (e error) {
 	// ubah status sales order invoice menjadi 'finished'
 	so.InvoiceStatus = "finished"
 	// ubah status sales invoice manjadi 'finished'
@@ -374,11 +424,15 @@ func createSOWalkInCustomerAutoInvoice(so *model.SalesOrder, si *model.SalesInvo
 	if e = so.Save(); e == nil {
 		if e = si.Save(); e == nil {
 			// buat finance revenue dari sales invoice
-			if fr, e = createFinanceRevenue(si); e == nil {
+			if payments, e = createPaymentRevenues(si, payments); e == nil {
 				// jumlahkan semua total revenue dari sales invoice
 				if _, e = sumTotalRevenuedSalesInvoice(si.ID); e == nil {
 					// approve-kan finance revenue yang telah dibuat
-					e = approveRevenue(fr)
+					for _, fr := range payments {
+						if e = approveRevenue(fr); e != nil {
+							return
+						}
+					}
 				}
 			}
 		}
@@ -386,6 +440,30 @@ func createSOWalkInCustomerAutoInvoice(so *model.SalesOrder, si *model.SalesInvo
 	return
 }
 
+// createPaymentRevenues membuat finance revenue dari pembayaran sales invoice,
+// jika tidak ada pembayaran maka dibuat satu finance revenue cash sebesar total invoice.
+func createPaymentRevenues(si *model.SalesInvoice, payments []*model.FinanceRevenue) ([]*model.FinanceRevenue, error) {
+	if len(payments) == 0 {
+		fr, e := createFinanceRevenue(si)
+		return []*model.FinanceRevenue{fr}, e
+	}
+
+	for _, fr := range payments {
+		fr.RefID = uint64(si.ID)
+		fr.RefType = "sales_invoice"
+		fr.RecognitionDate = si.RecognitionDate
+		fr.DocumentStatus = "uncleared"
+		fr.IsDeleted = int8(0)
+		fr.CreatedAt = time.Now()
+		fr.CreatedBy = si.CreatedBy
+		if e := fr.Save(); e != nil {
+			return nil, e
+		}
+	}
+
+	return payments, nil
+}
+
 // createFinanceRevenue untuk membuat finance revenue dari sales invoice
 func createFinanceRevenue(si *model.SalesInvoice) (fr *model.FinanceRevenue, e error) {
 	fr = &model.FinanceRevenue{
@@ -438,6 +516,10 @@ func approveRevenue(rev *model.FinanceRevenue) (e error) {
 		} else {
 			e = errors.New("refType is wrong")
 		}
+
+		if e == nil {
+			generalLedger.AutoPost("finance_revenue", rev.ID)
+		}
 	}
 	return
 }
@@ -489,6 +571,7 @@ func CancelSalesOrder(so *model.SalesOrder, user *model.User) (e error) {
 				//update is delete pada seluruh sales invoice yg memiliki referensi  SO, menjadi is_delete = 1
 				six.IsDeleted = 1
 				six.Save("IsDeleted")
+				generalLedger.AutoReverse("sales_invoice", six.ID)
 				//check document status
 				if six.DocumentStatus != "new" {
 
@@ -506,6 +589,7 @@ func CancelSalesOrder(so *model.SalesOrder, user *model.User) (e error) {
 							for _, frx := range fr {
 								frx.IsDeleted = 1
 								frx.Save("IsDeleted")
+								generalLedger.AutoReverse("finance_revenue", frx.ID)
 							}
 						}
 						// jika is bundled = 1
@@ -538,6 +622,7 @@ func CancelSalesOrder(so *model.SalesOrder, user *model.User) (e error) {
 				if e = ffx.Save("IsDeleted"); e != nil {
 					return
 				}
+				generalLedger.AutoReverse("workorder_fulfillment", ffx.ID)
 			}
 		}
 	} else {
@@ -642,6 +727,18 @@ func UpdateSalesOrder(so *model.SalesOrder, itemsReq []*model.SalesOrderItem) (*
 	oldSo := &model.SalesOrder{ID: so.ID}
 	oldSo.Read()
 	diff := so.TotalCharge - oldSo.TotalCharge
+
+	// sales order lama yang belum memiliki revisi dicatat dulu sebagai revisi awal
+	if e = ensureRevision(so.ID); e != nil {
+		return nil, e
+	}
+
+	// perubahan yang melewati batas diskon usergroup harus diapprove terlebih dahulu
+	check := checkApproval(so, itemsReq, so.UpdatedBy)
+	if len(check.Reasons) > 0 {
+		so.DocumentStatus = "pending_approval"
+	}
+
 	//save dulu perubahan di so
 	if e = so.Save(); e == nil {
 		var itemsReqID []int64
@@ -657,9 +754,12 @@ func UpdateSalesOrder(so *model.SalesOrder, itemsReq []*model.SalesOrderItem) (*
 				item.Quantity = req.Quantity
 				item.UnitPrice = req.UnitPrice
 				item.Discount = req.Discount
+				item.TaxCode = req.TaxCode
+				item.TaxBase = req.TaxBase
+				item.TaxAmount = req.TaxAmount
 				item.Subtotal = req.Subtotal
 				item.Note = req.Note
-				item.Save("ItemVariant", "Quantity", "UnitPrice", "Discount", "Subtotal", "Note")
+				item.Save("ItemVariant", "Quantity", "UnitPrice", "Discount", "TaxCode", "TaxBase", "TaxAmount", "Subtotal", "Note")
 			} else {
 				req.SalesOrder = &model.SalesOrder{ID: so.ID}
 				req.Save()
@@ -683,6 +783,17 @@ func UpdateSalesOrder(so *model.SalesOrder, itemsReq []*model.SalesOrderItem) (*
 		customer.TotalDebt += diff
 		customer.TotalSpend += diff
 		customer.Save("TotalDebt", "TotalSpend")
+
+		if so.DocumentStatus == "pending_approval" {
+			if e = requestApproval(so, check, so.UpdatedBy); e != nil {
+				return nil, e
+			}
+		}
+
+		if _, e = createRevision(so, itemsReq, so.UpdatedBy, ""); e != nil {
+			return nil, e
+		}
+
 		var emptyLoad []string
 		emptyLoad = append(emptyLoad, "sales_order_items")
 		so, _ = GetDetailSalesOrder(so.ID, emptyLoad)
@@ -901,6 +1012,7 @@ func approveFulfillment(fulfillment *model.WorkorderFulfillment) (*model.Workord
 				// update sales order.total_cost
 				fulfillment.SalesOrder.TotalCost += totalCost
 				if e = fulfillment.SalesOrder.Save("total_cost"); e == nil {
+					generalLedger.AutoPost("workorder_fulfillment", fulfillment.ID)
 					return fulfillment, nil
 				}
 			}
@@ -29,6 +29,7 @@ func dummyFinanceRevenue() *model.SalesInvoice {
 	financeRevenue.RefID = uint64(si.ID)
 	financeRevenue.DocumentStatus = "cleared"
 	financeRevenue.RefType = "sales_invoice"
+	financeRevenue.IsDeleted = 0
 	financeRevenue.RecognitionDate = time.Now()
 	if e := financeRevenue.Save(); e != nil {
 		fmt.Printf("error saving %s", e.Error())
@@ -156,6 +157,28 @@ func TestCalculateTotalPaidSI3(t *testing.T) {
 
 }
 
+// TestCalculateTotalPaidSI4 Test Calculate Total Paid Sales Invoice
+// dengan pembayaran finance revenue dan invoice receipt yang sudah finished
+func TestCalculateTotalPaidSI4(t *testing.T) {
+	si := dummyFinanceRevenue()
+
+	iri := model.DummyInvoiceReceiptItem()
+	iri.SalesInvoice = si
+	iri.Subtotal = 5000
+	iri.Save()
+
+	// invoice receipt yang belum dibayar tidak dihitung
+	iri.InvoiceReceipt.DocumentStatus = "active"
+	iri.InvoiceReceipt.Save("DocumentStatus")
+	assert.NoError(t, CalculateTotalPaidSI(si))
+	assert.Equal(t, float64(10000), si.TotalPaid)
+
+	iri.InvoiceReceipt.DocumentStatus = "finished"
+	iri.InvoiceReceipt.Save("DocumentStatus")
+	assert.NoError(t, CalculateTotalPaidSI(si))
+	assert.Equal(t, float64(15000), si.TotalPaid)
+}
+
 func TestCalculateTotalPaidSO(t *testing.T) {
 	si := dummySalesInvoice()
 
@@ -1401,3 +1424,294 @@ func TestGetWorkorderFulfillmentByID(t *testing.T) {
 	assert.Error(t, e)
 	assert.Empty(t, data)
 }
+
+// dummyDiscountLimit membuat user dengan batas diskon pada usergroup-nya.
+func dummyDiscountLimit(maxLine float32, maxOrder float32, minMargin float32) *model.User {
+	user := model.DummyUser()
+
+	limit := model.DummyDiscountLimit()
+	limit.Usergroup = user.Usergroup
+	limit.MaxLineDiscount = maxLine
+	limit.MaxOrderDiscount = maxOrder
+	limit.MinMargin = minMargin
+	limit.Save()
+
+	return user
+}
+
+func TestCheckApproval(t *testing.T) {
+	iv := model.DummyItemVariant()
+	ivs := model.DummyItemVariantStock()
+	ivs.ItemVariant = iv
+	ivs.AvailableStock = 5
+	ivs.UnitCost = 8000
+	ivs.Save()
+
+	so := &model.SalesOrder{TotalPrice: 100000, Discount: 10, DiscountAmount: 10000}
+	items := []*model.SalesOrderItem{{ItemVariant: iv, Quantity: 10, Discount: 15}}
+
+	// usergroup tanpa batas diskon tidak perlu approval
+	c := checkApproval(so, items, model.DummyUser())
+	assert.Empty(t, c.Reasons)
+	assert.Equal(t, float64(80000), c.TotalCost)
+	assert.Equal(t, float32(11.11), c.Margin)
+
+	c = checkApproval(so, items, dummyDiscountLimit(10, 5, 20))
+	assert.Len(t, c.Reasons, 3)
+	assert.Equal(t, float32(15), c.LineDiscount)
+	assert.Equal(t, float32(10), c.OrderDiscount)
+
+	c = checkApproval(so, items, dummyDiscountLimit(15, 10, 10))
+	assert.Empty(t, c.Reasons)
+}
+
+func TestEstimateUnitCost(t *testing.T) {
+	iv := model.DummyItemVariant()
+	newStock := func(location string, available float32, cost float64, createdAt time.Time) {
+		ivs := model.DummyItemVariantStock()
+		ivs.ItemVariant = iv
+		ivs.Location = location
+		ivs.AvailableStock = available
+		ivs.UnitCost = cost
+		ivs.CreatedAt = createdAt
+		ivs.Save()
+	}
+
+	// stock warehouse sudah habis, stock quarantine terbaru tidak dipakai
+	newStock("warehouse", 0, 7000, time.Now().Add(-time.Hour))
+	newStock("quarantine", 0, 9000, time.Now())
+	assert.Equal(t, float64(7000), estimateUnitCost(iv.ID))
+
+	// stock quarantine yang masih tersedia juga tidak dihitung
+	newStock("quarantine", 5, 12000, time.Now())
+	assert.Equal(t, float64(7000), estimateUnitCost(iv.ID))
+
+	newStock("warehouse", 5, 8000, time.Now())
+	assert.Equal(t, float64(8000), estimateUnitCost(iv.ID))
+}
+
+func TestCreateSalesOrderPendingApproval(t *testing.T) {
+	iv := model.DummyItemVariant()
+	iv.BasePrice = 1000
+	iv.Save("BasePrice")
+	partner := model.DummyPartnership()
+	partner.IsDefault = int8(0)
+	partner.Save("IsDefault")
+
+	session := new(auth.SessionData)
+	session.User = dummyDiscountLimit(5, 100, 0)
+
+	newOrder := func() *model.SalesOrder {
+		code, _ := util.CodeGen("code_sales_order", "sales_order")
+		r := createRequest{
+			Code:            code,
+			RecognitionDate: time.Now(),
+			EtaDate:         time.Now(),
+			CustomerID:      common.Encrypt(partner.ID),
+			ShipmentAddress: "Every corner in the world",
+			SalesOrderItem: []salesOrderItem{
+				{
+					ItemVariantID: common.Encrypt(iv.ID),
+					Quantity:      float32(10),
+					Discount:      float32(10),
+					UnitPrice:     iv.BasePrice,
+					PricingType:   common.Encrypt(model.DummyPricingType().ID),
+				},
+			},
+			TotalPrice: float64(9000),
+			Session:    session,
+		}
+
+		so, e := CreateSalesOrder(&r)
+		assert.NoError(t, e)
+		assert.Equal(t, "pending_approval", so.DocumentStatus)
+
+		m, e := pendingApproval(so.ID)
+		assert.NoError(t, e)
+		assert.Contains(t, m.Reason, "line discount")
+
+		return so
+	}
+
+	// approve mengembalikan sales order menjadi new
+	so := newOrder()
+	so, _ = GetDetailSalesOrder(so.ID, nil)
+	assert.NoError(t, ApproveSalesOrder(so, "pelanggan lama", model.DummyUser()))
+	so.Read()
+	assert.Equal(t, "new", so.DocumentStatus)
+
+	approvals, _ := GetSalesOrderApprovals(so.ID)
+	assert.Equal(t, "approved", approvals[0].Status)
+
+	// reject membatalkan sales order
+	so = newOrder()
+	so, _ = GetDetailSalesOrder(so.ID, nil)
+	assert.NoError(t, RejectSalesOrder(so, "diskon terlalu besar", model.DummyUser()))
+	so.Read()
+	assert.Equal(t, "approved_cancel", so.DocumentStatus)
+
+	_, e := pendingApproval(so.ID)
+	assert.Error(t, e)
+}
+
+func TestDiffRevision(t *testing.T) {
+	old := &RevisionSnapshot{
+		EtaDate:    "2018-03-01",
+		TotalPrice: 30000,
+		Items: []*RevisionItem{
+			{ItemVariantID: "a", ItemName: "Kopi", Quantity: 2, UnitPrice: 10000, Subtotal: 20000},
+			{ItemVariantID: "b", ItemName: "Teh", Quantity: 1, UnitPrice: 10000, Subtotal: 10000},
+		},
+	}
+	cur := &RevisionSnapshot{
+		EtaDate:    "2018-03-05",
+		TotalPrice: 45000,
+		Items: []*RevisionItem{
+			{ItemVariantID: "a", ItemName: "Kopi", Quantity: 3, UnitPrice: 10000, Subtotal: 30000},
+			{ItemVariantID: "c", ItemName: "Gula", Quantity: 1, UnitPrice: 15000, Subtotal: 15000},
+		},
+	}
+
+	changes := make(map[string]*FieldChange)
+	for _, c := range DiffRevision(old, cur) {
+		changes[c.Field] = c
+	}
+
+	assert.Len(t, changes, 6)
+	assert.Equal(t, "2018-03-01", changes["eta_date"].Old)
+	assert.Equal(t, float64(45000), changes["total_price"].New)
+	assert.Equal(t, float32(3), changes["sales_order_item.a.quantity"].New)
+	assert.Equal(t, "Kopi", changes["sales_order_item.a.subtotal"].ItemName)
+	assert.Nil(t, changes["sales_order_item.b"].New)
+	assert.Nil(t, changes["sales_order_item.c"].Old)
+
+	assert.Empty(t, DiffRevision(cur, cur))
+}
+
+func TestSalesOrderRevision(t *testing.T) {
+	iv := model.DummyItemVariant()
+	iv.BasePrice = 1000
+	iv.Save("BasePrice")
+	partner := model.DummyPartnership()
+	partner.IsDefault = int8(0)
+	partner.Save("IsDefault")
+
+	session := new(auth.SessionData)
+	session.User = model.DummyUser()
+
+	code, _ := util.CodeGen("code_sales_order", "sales_order")
+	r := createRequest{
+		Code:            code,
+		RecognitionDate: time.Now(),
+		EtaDate:         time.Now(),
+		CustomerID:      common.Encrypt(partner.ID),
+		ShipmentAddress: "Every corner in the world",
+		SalesOrderItem: []salesOrderItem{
+			{
+				ItemVariantID: common.Encrypt(iv.ID),
+				Quantity:      float32(10),
+				UnitPrice:     iv.BasePrice,
+				PricingType:   common.Encrypt(model.DummyPricingType().ID),
+			},
+		},
+		TotalPrice: float64(10000),
+		Session:    session,
+	}
+
+	so, e := CreateSalesOrder(&r)
+	assert.NoError(t, e)
+
+	so, _ = GetDetailSalesOrder(so.ID, []string{"sales_order_items"})
+	items := so.SalesOrderItems
+	items[0].Quantity = 12
+	items[0].Subtotal = 12000
+	so.TotalPrice = 12000
+	so.UpdatedBy = session.User
+	_, e = UpdateSalesOrder(so, items)
+	assert.NoError(t, e)
+
+	rev, e := GetSalesOrderRevisions(so)
+	assert.NoError(t, e)
+	assert.Len(t, rev, 2)
+	assert.Equal(t, 1, rev[0].Revision)
+	assert.Equal(t, "initial revision", rev[0].Note)
+	assert.Equal(t, 2, rev[1].Revision)
+
+	changes, e := CompareRevision(so, 1, 2)
+	assert.NoError(t, e)
+	var fields []string
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	assert.Contains(t, fields, "total_price")
+	assert.Contains(t, fields, "sales_order_item."+common.Encrypt(iv.ID)+".quantity")
+
+	_, e = CompareRevision(so, 1, 5)
+	assert.Error(t, e)
+
+	// sales order hasil amandemen melanjutkan nomor revisi sales order referensinya
+	amend := &model.SalesOrder{ID: 0, Reference: so}
+	assert.Equal(t, 2, lastRevision(amend))
+}
+
+func TestAmendSalesOrder(t *testing.T) {
+	iv := model.DummyItemVariant()
+	iv.BasePrice = 1000
+	iv.Save("BasePrice")
+	partner := model.DummyPartnership()
+	partner.IsDefault = int8(0)
+	partner.Save("IsDefault")
+
+	session := new(auth.SessionData)
+	session.User = model.DummyUser()
+
+	newRequest := func(qty float32) createRequest {
+		code, _ := util.CodeGen("code_sales_order", "sales_order")
+		return createRequest{
+			Code:            code,
+			RecognitionDate: time.Now(),
+			EtaDate:         time.Now(),
+			CustomerID:      common.Encrypt(partner.ID),
+			ShipmentAddress: "Every corner in the world",
+			SalesOrderItem: []salesOrderItem{
+				{
+					ItemVariantID: common.Encrypt(iv.ID),
+					Quantity:      qty,
+					UnitPrice:     iv.BasePrice,
+					PricingType:   common.Encrypt(model.DummyPricingType().ID),
+				},
+			},
+			TotalPrice: float64(qty) * iv.BasePrice,
+			Session:    session,
+		}
+	}
+
+	cr := newRequest(10)
+	old, e := CreateSalesOrder(&cr)
+	assert.NoError(t, e)
+	old.FulfillmentStatus = "active"
+	old.Save("FulfillmentStatus")
+
+	old, _ = GetDetailSalesOrder(old.ID, nil)
+	r := &amendRequest{createRequest: newRequest(12), Reason: "tambah quantity", SalesOrder: old}
+	assert.True(t, r.Validate().Valid)
+
+	so, e := AmendSalesOrder(old, r, session.User)
+	assert.NoError(t, e)
+	assert.Equal(t, old.ID, so.Reference.ID)
+
+	old.Read()
+	assert.Equal(t, "approved_cancel", old.DocumentStatus)
+	assert.Equal(t, "amended: tambah quantity", old.CancelledNote)
+
+	// sales order yang sebagian item nya sudah terkirim tidak bisa diamandemen
+	cr = newRequest(10)
+	partial, _ := CreateSalesOrder(&cr)
+	partial.FulfillmentStatus = "active"
+	partial.Save("FulfillmentStatus")
+	orm.NewOrm().Raw("UPDATE sales_order_item SET quantity_fulfillment = 4 WHERE sales_order_id = ?", partial.ID).Exec()
+
+	partial, _ = GetDetailSalesOrder(partial.ID, nil)
+	r = &amendRequest{createRequest: newRequest(12), Reason: "tambah quantity", SalesOrder: partial}
+	assert.False(t, r.Validate().Valid)
+}
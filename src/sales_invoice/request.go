@@ -43,6 +43,8 @@ func (r *createRequest) Validate() *validation.Output {
 			// check status
 			if sales.InvoiceStatus == "finished" || sales.DocumentStatus == "approved_cancel" || sales.DocumentStatus == "requested_cancel" {
 				o.Failure("sales_order_id", "cannot create invoice")
+			} else if sales.DocumentStatus == "pending_approval" {
+				o.Failure("sales_order_id", "sales order is waiting for discount approval")
 			} else {
 				// check total amount from all SI in sales order
 				totalAmountInvoice, _ := GetSumTotalAmountSalesInvoiceBySalesOrder(id)
@@ -109,7 +111,7 @@ type updateRequest struct {
 func (r *updateRequest) Validate() *validation.Output {
 	o := &validation.Output{Valid: true}
 	// check status sales invoice
-	if r.SalesInvoiceOld.DocumentStatus != "new" || r.SalesInvoiceOld.SalesOrder.DocumentStatus == "approved_cancel" || r.SalesInvoiceOld.SalesOrder.DocumentStatus == "requested_cancel" {
+	if r.SalesInvoiceOld.DocumentStatus != "new" || r.SalesInvoiceOld.SalesOrder.DocumentStatus == "approved_cancel" || r.SalesInvoiceOld.SalesOrder.DocumentStatus == "requested_cancel" || r.SalesInvoiceOld.SalesOrder.DocumentStatus == "pending_approval" {
 		o.Failure("sales_invoice", "cannot be update")
 	} else {
 		// check total amount from all SI in sales order
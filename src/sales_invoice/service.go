@@ -6,10 +6,13 @@ package salesInvoice
 
 import (
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 	"git.qasico.com/mj/api/src/util"
 
 	"git.qasico.com/cuxs/orm"
 	"git.qasico.com/mj/api/src/sales"
+	"git.qasico.com/mj/api/src/tax"
+	"github.com/labstack/gommon/log"
 )
 
 // GetSalesInvoice untuk mengambil semua data sales invoice dari database
@@ -62,6 +65,12 @@ func CreateSalesInvoice(SI *model.SalesInvoice) (e error) {
 		SI.SalesOrder.InvoiceStatus = "active"
 		SI.SalesOrder.DocumentStatus = "active"
 		e = SI.SalesOrder.Save("invoice_status", "DocumentStatus")
+		generalLedger.AutoPost("sales_invoice", SI.ID)
+
+		// nomor faktur pajak yang gagal diberikan bisa diberikan manual dari menu tax
+		if err := tax.AssignTaxInvoiceNumber(SI); err != nil {
+			log.Error(err)
+		}
 	}
 
 	sales.CalculateTotalPaidSI(SI)
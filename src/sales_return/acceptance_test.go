@@ -887,3 +887,72 @@ func TestCreateSalesReturnFailNoSalesOrder(t *testing.T) {
 			})
 	}
 }
+
+func TestRestockSalesReturn(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	sr := model.DummySalesReturn()
+	sr.IsDeleted = 0
+	sr.DocumentStatus = "new"
+	sr.Save()
+
+	sri := model.DummySalesReturnItem()
+	sri.SalesReturn = sr
+	sri.Quantity = 2
+	sri.Save()
+
+	sri2 := model.DummySalesReturnItem()
+	sri2.SalesReturn = sr
+	sri2.Quantity = 1
+	sri2.Save()
+
+	id := common.Encrypt(sr.ID)
+
+	var restock = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"sales_return_items": []tester.D{
+			{"id": common.Encrypt(sri.ID), "condition": "resellable"},
+		}}, http.StatusUnprocessableEntity},
+		{tester.D{"sales_return_items": []tester.D{
+			{"id": common.Encrypt(sri.ID), "condition": "resellable"},
+			{"id": common.Encrypt(sri2.ID), "condition": "broken"},
+		}}, http.StatusUnprocessableEntity},
+		{tester.D{"sales_return_items": []tester.D{
+			{"id": common.Encrypt(sri.ID), "condition": "resellable"},
+			{"id": "999999", "condition": "scrap"},
+		}}, http.StatusUnprocessableEntity},
+		{tester.D{"sales_return_items": []tester.D{
+			{"id": common.Encrypt(sri.ID), "condition": "resellable"},
+			{"id": common.Encrypt(sri2.ID), "condition": "scrap"},
+		}}, http.StatusOK},
+		// sudah di restock
+		{tester.D{"sales_return_items": []tester.D{
+			{"id": common.Encrypt(sri.ID), "condition": "resellable"},
+			{"id": common.Encrypt(sri2.ID), "condition": "scrap"},
+		}}, http.StatusUnprocessableEntity},
+	}
+	ng := tester.New()
+	for _, tes := range restock {
+		ng.SetHeader(tester.H{"Authorization": token})
+		ng.PUT("/v1/sales-return/"+id+"/restock").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	sri.Read()
+	assert.Equal(t, "resellable", sri.Condition)
+
+	ng.PUT("/v1/sales-return/"+id).
+		SetJSON(tester.D{"recognition_date": "2017-08-21T00:00:00Z", "sales_return_items": []tester.D{
+			{"id": common.Encrypt(sri.ID), "sales_order_item": common.Encrypt(sri.SalesOrderItem.ID), "quantity": 1},
+		}}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnprocessableEntity, res.Code, res.Body.String())
+		})
+}
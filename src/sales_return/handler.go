@@ -23,6 +23,7 @@ func (h *Handler) URLMapping(r *echo.Group) {
 	r.POST("", h.create, auth.CheckPrivilege("sales_return_create"))
 	r.PUT("/:id", h.put, auth.CheckPrivilege("sales_return_update"))
 	r.PUT("/:id/cancel", h.cancel, auth.CheckPrivilege("sales_return_cancel"))
+	r.PUT("/:id/restock", h.restock, auth.CheckPrivilege("sales_return_restock"))
 }
 
 // get endpoint to handle get http method.
@@ -126,9 +127,11 @@ func (h *Handler) cancel(c echo.Context) (e error) {
 		if sr, e = ShowSalesReturn("id", id); e == nil {
 			r.SR = sr
 			if e = ctx.Bind(&r); e == nil {
-				if e = CancelSalesReturn(sr); e == nil {
-					if e = UpdateExpense(sr); e == nil {
-						ctx.Data(sr)
+				if e = CancelRestockSalesReturn(sr); e == nil {
+					if e = CancelSalesReturn(sr); e == nil {
+						if e = UpdateExpense(sr); e == nil {
+							ctx.Data(sr)
+						}
 					}
 				}
 			}
@@ -139,3 +142,26 @@ func (h *Handler) cancel(c echo.Context) (e error) {
 
 	return ctx.Serve(e)
 }
+
+// restock endpoint untuk menerima barang sales return dengan kondisi resellable, damaged atau scrap.
+func (h *Handler) restock(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	var r restockRequest
+	var id int64
+	if r.SessionData, e = auth.UserSession(ctx); e == nil {
+		if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+			if r.SR, e = ShowSalesReturn("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					if e = RestockSalesReturn(r.SR, r.Transform(), r.SessionData.User); e == nil {
+						r.SR, e = ShowSalesReturn("id", id)
+						ctx.Data(r.SR)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
@@ -6,6 +6,7 @@ import (
 
 	"git.qasico.com/mj/api/datastore/model"
 	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/src/inventory"
 	"git.qasico.com/mj/api/src/sales"
 	"git.qasico.com/mj/api/src/util"
 
@@ -106,6 +107,7 @@ func (r *createRequest) Transform() *model.SalesReturn {
 			SalesOrderItem: soi,
 			Note:           srItem.Note,
 			Quantity:       float32(qtyPres),
+			Condition:      "ungraded",
 		}
 
 		item = append(item, rItem)
@@ -157,6 +159,10 @@ func (r *updateRequest) Validate() *validation.Output {
 		o.Failure("document_status", "can't update document")
 	}
 
+	if r.SR.IsRestocked == 1 {
+		o.Failure("is_restocked", "can't update document, returned items already restocked")
+	}
+
 	CanBeReturn, _ := sales.CanBeReturnSales(r.SO, r.SR)
 	// check item return
 	for i, item := range r.ReturnItem {
@@ -214,6 +220,7 @@ func (r *updateRequest) Transform(sr *model.SalesReturn) *model.SalesReturn {
 			SalesOrderItem: soi,
 			Note:           srItem.Note,
 			Quantity:       float32(qtyPres),
+			Condition:      "ungraded",
 			SalesReturn:    &model.SalesReturn{ID: r.SR.ID},
 		}
 
@@ -254,6 +261,12 @@ func (r *cancelRequest) Validate() *validation.Output {
 		o.Valid = false
 		o.Failure("document_status", "can't cancel document")
 	}
+
+	if r.SR.IsRestocked == 1 {
+		if _, _, e := inventory.CheckCancelStock(uint64(r.SR.ID), "sales_return"); e != nil && e.Error() != "data empty" {
+			o.Failure("is_restocked", "can't cancel document, restocked items already used")
+		}
+	}
 	return o
 }
 
@@ -266,3 +279,85 @@ func (r *cancelRequest) Messages() map[string]string {
 // Transform transforming request into model.
 func (r *cancelRequest) Transform() {
 }
+
+// restockRequest data struct that stored request data when requesting an restock sales return process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type restockRequest struct {
+	SR          *model.SalesReturn `json:"-"`
+	RestockItem []restockItem      `json:"sales_return_items" valid:"required"`
+	SessionData *auth.SessionData  `json:"-"`
+}
+
+type restockItem struct {
+	ID        string `json:"id" valid:"required"`
+	Condition string `json:"condition" valid:"required|in:resellable,damaged,scrap"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *restockRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.SR.DocumentStatus == "cancelled" || r.SR.IsDeleted == 1 {
+		o.Failure("document_status", "can't restock cancelled document")
+	}
+
+	if r.SR.IsRestocked == 1 {
+		o.Failure("is_restocked", "returned items already restocked")
+	}
+
+	graded := make(map[int64]bool)
+	for i, item := range r.RestockItem {
+		id, e := common.Decrypt(item.ID)
+		if e != nil {
+			o.Failure(fmt.Sprintf("sales_return_items.%d.id.invalid", i), "sales return item id cannot be decrypt")
+			continue
+		}
+
+		var found bool
+		for _, sri := range r.SR.SalesReturnItems {
+			if sri.ID == id {
+				found = true
+			}
+		}
+
+		if !found {
+			o.Failure(fmt.Sprintf("sales_return_items.%d.id.invalid", i), "sales return item must from the sales return")
+		} else if graded[id] {
+			o.Failure(fmt.Sprintf("sales_return_items.%d.id.invalid", i), "sales return item already graded")
+		}
+
+		graded[id] = true
+	}
+
+	for _, sri := range r.SR.SalesReturnItems {
+		if !graded[sri.ID] {
+			o.Failure("sales_return_items", "all sales return items must be graded")
+			break
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *restockRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into sales return items with their condition.
+func (r *restockRequest) Transform() (items []*model.SalesReturnItem) {
+	for _, item := range r.RestockItem {
+		id, _ := common.Decrypt(item.ID)
+		for _, sri := range r.SR.SalesReturnItems {
+			if sri.ID == id {
+				sri.Condition = item.Condition
+				items = append(items, sri)
+			}
+		}
+	}
+
+	return
+}
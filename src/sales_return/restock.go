@@ -0,0 +1,150 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package salesReturn
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/inventory"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// batchCost quantity dan unit cost batch item variant stock yang dikeluarkan fulfillment.
+type batchCost struct {
+	UnitCost float64
+	Quantity float32
+}
+
+// consumedBatches mengambil batch item variant stock yang dikeluarkan fulfillment sales order untuk item variant,
+// quantity sudah dikurangi fulfillment yang dicancel dan diurutkan dari batch yang terakhir dikeluarkan.
+func consumedBatches(soID int64, itemVariantID int64) (batches []*batchCost) {
+	o := orm.NewOrm()
+	o.Raw("SELECT s.unit_cost, SUM(IF(l.log_type = 'out', l.quantity, -l.quantity)) AS quantity FROM item_variant_stock_log l "+
+		"INNER JOIN item_variant_stock s ON s.id = l.item_variant_stock_id "+
+		"INNER JOIN workorder_fulfillment wf ON wf.id = l.ref_id "+
+		"WHERE l.ref_type = 'workorder_fulfillment' AND wf.sales_order_id = ? AND s.item_variant_id = ? "+
+		"GROUP BY s.id, s.unit_cost HAVING quantity > 0 ORDER BY MAX(l.id) DESC", soID, itemVariantID).QueryRows(&batches)
+
+	return
+}
+
+// restockedQuantity jumlah item variant dari sales order yang sudah dikembalikan oleh sales return lain.
+func restockedQuantity(sr *model.SalesReturn, itemVariantID int64) (total float32) {
+	o := orm.NewOrm()
+	o.Raw("SELECT COALESCE(SUM(sri.quantity), 0) FROM sales_return_item sri "+
+		"INNER JOIN sales_return sr ON sr.id = sri.sales_return_id "+
+		"INNER JOIN sales_order_item soi ON soi.id = sri.sales_order_item_id "+
+		"WHERE sr.sales_order_id = ? AND sr.id != ? AND sr.is_restocked = 1 AND sr.document_status != 'cancelled' AND soi.item_variant_id = ?",
+		sr.SalesOrder.ID, sr.ID, itemVariantID).QueryRow(&total)
+
+	return
+}
+
+// allocateBatches membagi quantity return ke batch yang dikeluarkan fulfillment,
+// batch yang sudah dikembalikan sejumlah skip dilewati. Jika batch tidak mencukupi
+// sisa quantity menggunakan unit cost stock terakhir item variant.
+func allocateBatches(batches []*batchCost, skip float32, quantity float32, itemVariantID int64) (res []*batchCost) {
+	for _, b := range batches {
+		if quantity <= 0 {
+			break
+		}
+
+		available := b.Quantity
+		if skip > 0 {
+			if skip >= available {
+				skip -= available
+				continue
+			}
+
+			available -= skip
+			skip = 0
+		}
+
+		qty := available
+		if quantity < qty {
+			qty = quantity
+		}
+
+		res = append(res, &batchCost{UnitCost: b.UnitCost, Quantity: qty})
+		quantity -= qty
+	}
+
+	if quantity > 0 {
+		var cost float64
+		orm.NewOrm().Raw("SELECT unit_cost FROM item_variant_stock WHERE item_variant_id = ? ORDER BY created_at DESC LIMIT 1", itemVariantID).QueryRow(&cost)
+		res = append(res, &batchCost{UnitCost: cost, Quantity: quantity})
+	}
+
+	return
+}
+
+// RestockSalesReturn memproses barang yang dikembalikan customer sesuai kondisi masing-masing item,
+// resellable masuk kembali ke stock warehouse dengan unit cost batch FIFO yang dikeluarkan fulfillment,
+// damaged masuk ke stock quarantine dan scrap dihapus dengan nilai cost dicatat sebagai total write off.
+func RestockSalesReturn(sr *model.SalesReturn, items []*model.SalesReturnItem, user *model.User) (e error) {
+	var writeOff float64
+	used := make(map[int64]float32)
+	for _, item := range items {
+		soi := item.SalesOrderItem
+		if e = soi.Read(); e != nil {
+			return
+		}
+
+		ivID := soi.ItemVariant.ID
+		if _, ok := used[ivID]; !ok {
+			used[ivID] = restockedQuantity(sr, ivID)
+		}
+
+		batches := allocateBatches(consumedBatches(sr.SalesOrder.ID, ivID), used[ivID], item.Quantity, ivID)
+		used[ivID] += item.Quantity
+
+		var cost float64
+		for _, b := range batches {
+			cost += b.UnitCost * float64(b.Quantity)
+
+			switch item.Condition {
+			case "resellable":
+				_, e = inventory.StockIn(soi.ItemVariant, b.UnitCost, b.Quantity, "warehouse", "sales_return", uint64(sr.ID))
+			case "damaged":
+				_, e = inventory.StockIn(soi.ItemVariant, b.UnitCost, b.Quantity, "quarantine", "sales_return", uint64(sr.ID))
+			}
+
+			if e != nil {
+				return
+			}
+		}
+
+		if item.Condition == "scrap" {
+			writeOff += cost
+		}
+
+		item.UnitCost = common.FloatPrecision(cost/float64(item.Quantity), 0)
+		if e = item.Save("condition", "unit_cost"); e != nil {
+			return
+		}
+	}
+
+	sr.IsRestocked = 1
+	sr.TotalWriteOff = common.FloatPrecision(writeOff, 0)
+	sr.RestockedBy = user
+	sr.RestockedAt = time.Now()
+
+	return sr.Save("is_restocked", "total_write_off", "restocked_by", "restocked_at")
+}
+
+// CancelRestockSalesReturn mengembalikan stock yang masuk dari proses restock sales return.
+func CancelRestockSalesReturn(sr *model.SalesReturn) (e error) {
+	if sr.IsRestocked == 1 {
+		if e = inventory.CancelStock(uint64(sr.ID), "sales_return"); e != nil && e.Error() == "data empty" {
+			// semua item scrap, tidak ada stock yang masuk
+			e = nil
+		}
+	}
+
+	return
+}
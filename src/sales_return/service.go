@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/general_ledger"
 
 	"git.qasico.com/cuxs/orm"
 )
@@ -86,6 +87,7 @@ func CancelSalesReturn(sr *model.SalesReturn) (err error) {
 	if _, err = o.Update(sr, "document_status"); err != nil {
 		return err
 	}
+	generalLedger.AutoReverse("sales_return", sr.ID)
 	return nil
 }
 
@@ -98,3 +100,33 @@ func UpdateExpense(sr *model.SalesReturn) (err error) {
 
 	return err
 }
+
+// SumCreditNoteAmount total credit note yang diterbitkan dari sales return, credit note yang dicancel tidak dihitung.
+func SumCreditNoteAmount(srID int64) (total float64) {
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM credit_note WHERE sales_return_id = ? AND document_status != 'cancelled'", srID).QueryRow(&total)
+	return
+}
+
+// CheckRefundStatus memperbarui document status sales return berdasarkan total refund cash (finance expense cleared)
+// dan credit note yang diterbitkan, finished apabila sudah sama dengan total amount sales return.
+func CheckRefundStatus(sr *model.SalesReturn) (e error) {
+	var expense float64
+	orm.NewOrm().Raw("SELECT COALESCE(SUM(amount), 0) FROM finance_expense WHERE ref_id = ? AND ref_type = 'sales_return' AND document_status = 'cleared' AND is_deleted = 0", sr.ID).QueryRow(&expense)
+
+	if sr.TotalAmount == expense+SumCreditNoteAmount(sr.ID) {
+		sr.DocumentStatus = "finished"
+	} else {
+		sr.DocumentStatus = "active"
+	}
+
+	if e = sr.Save("document_status"); e == nil {
+		// jurnal dibalik apabila sales return tidak lagi finished, misalnya credit note nya dicancel
+		if sr.DocumentStatus == "finished" {
+			generalLedger.AutoPost("sales_return", sr.ID)
+		} else {
+			generalLedger.AutoReverse("sales_return", sr.ID)
+		}
+	}
+
+	return
+}
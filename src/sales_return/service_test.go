@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/inventory"
 
 	"git.qasico.com/cuxs/orm"
 	"github.com/stretchr/testify/assert"
@@ -70,3 +71,102 @@ func TestUpdateExpense(t *testing.T) {
 	assert.NoError(t, e, "Data should be exists.")
 
 }
+
+func TestAllocateBatches(t *testing.T) {
+	batches := []*batchCost{{UnitCost: 2000, Quantity: 3}, {UnitCost: 1000, Quantity: 5}}
+
+	res := allocateBatches(batches, 0, 4, 0)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, float64(2000), res[0].UnitCost)
+	assert.Equal(t, float32(3), res[0].Quantity)
+	assert.Equal(t, float64(1000), res[1].UnitCost)
+	assert.Equal(t, float32(1), res[1].Quantity)
+
+	// 4 quantity sudah dikembalikan sales return sebelumnya
+	res = allocateBatches(batches, 4, 2, 0)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, float64(1000), res[0].UnitCost)
+	assert.Equal(t, float32(2), res[0].Quantity)
+}
+
+func TestRestockSalesReturn(t *testing.T) {
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.CommitedStock = 0
+	iv.Save()
+
+	inventory.FifoStockIn(iv, 1000, 5, "workorder_receiving", uint64(1))
+	inventory.FifoStockIn(iv, 2000, 5, "workorder_receiving", uint64(1))
+
+	so := model.DummySalesOrder()
+	so.DocumentStatus = "active"
+	so.IsDeleted = 0
+	so.Save()
+
+	soi := model.DummySalesOrderItem()
+	soi.SalesOrder = so
+	soi.ItemVariant = iv
+	soi.Quantity = 8
+	soi.Save()
+
+	wf := model.DummyWorkorderFulfillment()
+	wf.SalesOrder = so
+	wf.Save()
+	_, e := inventory.FifoStockOut(iv.ID, 8, "workorder_fulfillment", uint64(wf.ID))
+	assert.NoError(t, e)
+
+	sr := model.DummySalesReturn()
+	sr.SalesOrder = so
+	sr.DocumentStatus = "new"
+	sr.IsDeleted = 0
+	sr.Save()
+
+	var items []*model.SalesReturnItem
+	for cond, qty := range map[string]float32{"resellable": 4, "damaged": 2, "scrap": 1} {
+		sri := model.DummySalesReturnItem()
+		sri.SalesReturn = sr
+		sri.SalesOrderItem = soi
+		sri.Quantity = qty
+		sri.Condition = "ungraded"
+		sri.Save()
+
+		sri.Condition = cond
+		items = append(items, sri)
+	}
+
+	user := model.DummyUser()
+	e = RestockSalesReturn(sr, items, user)
+	assert.NoError(t, e)
+
+	for _, item := range items {
+		switch item.Condition {
+		case "resellable":
+			// 3 dari batch 2000 dan 1 dari batch 1000
+			assert.Equal(t, float64(1750), item.UnitCost)
+		default:
+			assert.Equal(t, float64(1000), item.UnitCost)
+		}
+	}
+
+	sr.Read()
+	assert.Equal(t, int8(1), sr.IsRestocked)
+	assert.Equal(t, float64(1000), sr.TotalWriteOff)
+
+	// stock warehouse 2 sisa batch 2000 ditambah 4 resellable, damaged tidak dihitung
+	iv.Read()
+	assert.Equal(t, float32(6), iv.AvailableStock)
+
+	var quarantine float32
+	o := orm.NewOrm()
+	o.Raw("SELECT SUM(available_stock) FROM item_variant_stock WHERE item_variant_id = ? AND location = 'quarantine'", iv.ID).QueryRow(&quarantine)
+	assert.Equal(t, float32(2), quarantine)
+
+	var logs int64
+	o.Raw("SELECT COUNT(*) FROM item_variant_stock_log WHERE ref_type = 'sales_return' AND ref_id = ?", sr.ID).QueryRow(&logs)
+	assert.Equal(t, int64(3), logs)
+
+	e = CancelRestockSalesReturn(sr)
+	assert.NoError(t, e)
+	iv.Read()
+	assert.Equal(t, float32(2), iv.AvailableStock)
+}
@@ -5,6 +5,8 @@
 package setting
 
 import (
+	"time"
+
 	"git.qasico.com/mj/api/datastore/model"
 
 	"git.qasico.com/cuxs/orm"
@@ -58,16 +60,19 @@ func UpdateApplicationSetting(r *updateRequest) (*model.ApplicationSetting, erro
 						BankNumber: i.BankNumber,
 						IsDefault:  i.IsDefault,
 					}
+					// field lain dikelola dari modul bank account
+					ba.Save("BankName", "BankNumber", "IsDefault")
 				} else {
 					ba = &model.BankAccount{
-						BankName:   i.BankName,
-						BankNumber: i.BankNumber,
-						IsDefault:  i.IsDefault,
+						AccountType: "bank",
+						BankName:    i.BankName,
+						BankNumber:  i.BankNumber,
+						IsDefault:   i.IsDefault,
+						CreatedAt:   time.Now(),
 					}
+					ba.Save()
 				}
 
-				ba.Save()
-
 			}
 		}
 
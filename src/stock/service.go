@@ -50,7 +50,7 @@ func sumAvailableStockItemVariantStock(m *model.ItemVariant) (total float32, e e
 	o := orm.NewOrm()
 
 	e = o.Raw("select sum(available_stock) from item_variant_stock "+
-		"where item_variant_id = ?", m.ID).QueryRow(&total)
+		"where item_variant_id = ? and location = ?", m.ID, "warehouse").QueryRow(&total)
 
 	return
 }
@@ -0,0 +1,120 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package supplierCatalogue_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("supplier_catalogue")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	c := model.DummySupplierCatalogue()
+	c.IsDeleted = 0
+	c.Save("IsDeleted")
+	id := common.Encrypt(c.ID)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/supplier-catalogue", "GET", http.StatusOK},
+		{"/v1/supplier-catalogue/" + id, "GET", http.StatusOK},
+		{"/v1/supplier-catalogue/999999", "GET", http.StatusNotFound},
+		{"/v1/supplier-catalogue/" + id, "DELETE", http.StatusOK},
+		{"/v1/supplier-catalogue/" + id, "GET", http.StatusNotFound},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_URLMappingPOST(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	supplier := model.DummyPartnership()
+	supplier.PartnershipType = "supplier"
+	supplier.IsDeleted = 0
+	supplier.Save("PartnershipType", "IsDeleted")
+	esupplierID := common.Encrypt(supplier.ID)
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDeleted = 0
+	customer.Save("PartnershipType", "IsDeleted")
+
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save("IsDeleted", "IsArchived")
+
+	iv2 := model.DummyItemVariant()
+	iv2.IsDeleted = 0
+	iv2.IsArchived = 0
+	iv2.Save("IsDeleted", "IsArchived")
+
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		// sukses
+		{tester.D{"partnership_id": esupplierID, "item_variant_id": common.Encrypt(iv.ID), "supplier_item_code": "KODE-01", "supplier_item_name": "Barang 01"}, http.StatusOK},
+		// kode barang supplier sudah terdaftar
+		{tester.D{"partnership_id": esupplierID, "item_variant_id": common.Encrypt(iv2.ID), "supplier_item_code": "KODE-01"}, http.StatusUnprocessableEntity},
+		// item variant sudah terdaftar
+		{tester.D{"partnership_id": esupplierID, "item_variant_id": common.Encrypt(iv.ID), "supplier_item_code": "KODE-02"}, http.StatusUnprocessableEntity},
+		// partnership bukan supplier
+		{tester.D{"partnership_id": common.Encrypt(customer.ID), "item_variant_id": common.Encrypt(iv.ID), "supplier_item_code": "KODE-01"}, http.StatusUnprocessableEntity},
+		// kode barang supplier kosong
+		{tester.D{"partnership_id": esupplierID, "item_variant_id": common.Encrypt(iv2.ID), "supplier_item_code": ""}, http.StatusUnprocessableEntity},
+		// sukses item variant lain
+		{tester.D{"partnership_id": esupplierID, "item_variant_id": common.Encrypt(iv2.ID), "supplier_item_code": "KODE-02"}, http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/supplier-catalogue").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package supplierCatalogue
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// catalogueRequest data struct that stored request data when requesting an create or update supplier catalogue process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type catalogueRequest struct {
+	PartnershipID     string                   `json:"partnership_id" valid:"required"`
+	ItemVariantID     string                   `json:"item_variant_id" valid:"required"`
+	SupplierItemCode  string                   `json:"supplier_item_code" valid:"required"`
+	SupplierItemName  string                   `json:"supplier_item_name"`
+	Note              string                   `json:"note"`
+	Session           *auth.SessionData        `json:"-"`
+	SupplierCatalogue *model.SupplierCatalogue `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *catalogueRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	var exclude int64
+	if r.SupplierCatalogue != nil {
+		exclude = r.SupplierCatalogue.ID
+	}
+
+	suppID, e := common.Decrypt(r.PartnershipID)
+	if e != nil {
+		o.Failure("partnership_id", "partnership_id is not valid")
+	} else {
+		supplier := &model.Partnership{ID: suppID}
+		if e = supplier.Read(); e != nil || supplier.IsDeleted == int8(1) {
+			o.Failure("partnership_id", "partnership_id doesn't exist")
+		} else if supplier.PartnershipType != "supplier" {
+			o.Failure("partnership_id", "Partnership inputted supplier is not valid")
+		} else if r.SupplierItemCode != "" && isRegistered(suppID, "supplier_item_code", r.SupplierItemCode, exclude) {
+			o.Failure("supplier_item_code", "supplier item code is already registered for the supplier")
+		}
+	}
+
+	if ivID, e := common.Decrypt(r.ItemVariantID); e != nil {
+		o.Failure("item_variant_id", "item_variant_id not valid")
+	} else {
+		iv := &model.ItemVariant{ID: ivID}
+		if e = iv.Read(); e != nil || iv.IsArchived == int8(1) || iv.IsDeleted == int8(1) {
+			o.Failure("item_variant_id", "item_variant_id is already archived or does not exists")
+		} else if suppID != 0 && isRegistered(suppID, "item_variant_id", ivID, exclude) {
+			o.Failure("item_variant_id", "item variant is already registered for the supplier")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *catalogueRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *catalogueRequest) Transform() *model.SupplierCatalogue {
+	suppID, _ := common.Decrypt(r.PartnershipID)
+	ivID, _ := common.Decrypt(r.ItemVariantID)
+
+	m := &model.SupplierCatalogue{
+		Partnership:      &model.Partnership{ID: suppID},
+		ItemVariant:      &model.ItemVariant{ID: ivID},
+		SupplierItemCode: r.SupplierItemCode,
+		SupplierItemName: r.SupplierItemName,
+		Note:             r.Note,
+		CreatedBy:        r.Session.User,
+		CreatedAt:        time.Now(),
+	}
+
+	if r.SupplierCatalogue != nil {
+		m.ID = r.SupplierCatalogue.ID
+		m.CreatedBy = r.SupplierCatalogue.CreatedBy
+		m.CreatedAt = r.SupplierCatalogue.CreatedAt
+		m.UpdatedBy = r.Session.User
+		m.UpdatedAt = time.Now()
+	}
+
+	return m
+}
+
+// deleteRequest data struct that stored request data when requesting an delete supplier catalogue process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type deleteRequest struct {
+	Session           *auth.SessionData        `json:"-"`
+	SupplierCatalogue *model.SupplierCatalogue `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *deleteRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.SupplierCatalogue.IsDeleted == int8(1) {
+		o.Failure("is_deleted", "already deleted")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *deleteRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *deleteRequest) Transform() *model.SupplierCatalogue {
+	r.SupplierCatalogue.IsDeleted = int8(1)
+	r.SupplierCatalogue.UpdatedBy = r.Session.User
+	r.SupplierCatalogue.UpdatedAt = time.Now()
+
+	return r.SupplierCatalogue
+}
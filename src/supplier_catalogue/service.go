@@ -0,0 +1,55 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package supplierCatalogue
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+)
+
+// GetSupplierCatalogues get all data supplier catalogue that matched with query request parameters.
+// returning slices of supplier catalogue, total data without limit and error.
+func GetSupplierCatalogues(rq *orm.RequestQuery) (m *[]model.SupplierCatalogue, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.SupplierCatalogue))
+	q = q.Filter("is_deleted", 0)
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.SupplierCatalogue
+	if _, err = q.RelatedSel("Partnership", "ItemVariant").All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowSupplierCatalogue find a single data supplier catalogue using field and value condition.
+func ShowSupplierCatalogue(field string, values ...interface{}) (*model.SupplierCatalogue, error) {
+	m := new(model.SupplierCatalogue)
+	o := orm.NewOrm().QueryTable(m)
+	if err := o.Filter(field, values...).Filter("is_deleted", 0).RelatedSel("Partnership", "ItemVariant").Limit(1).One(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// isRegistered cek apakah kode barang atau item variant sudah terdaftar pada katalog supplier,
+// id katalog yang sedang diupdate tidak ikut dihitung.
+func isRegistered(suppID int64, field string, value interface{}, exclude int64) bool {
+	total, _ := orm.NewOrm().QueryTable(new(model.SupplierCatalogue)).
+		Filter("partnership_id", suppID).
+		Filter(field, value).
+		Filter("is_deleted", 0).
+		Exclude("id", exclude).Count()
+
+	return total > 0
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package supplierCatalogue
+
+import (
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRegistered(t *testing.T) {
+	c := model.DummySupplierCatalogue()
+	c.SupplierItemCode = "SC-001"
+	c.IsDeleted = 0
+	c.Save("SupplierItemCode", "IsDeleted")
+
+	assert.True(t, isRegistered(c.Partnership.ID, "supplier_item_code", "SC-001", 0))
+	assert.True(t, isRegistered(c.Partnership.ID, "item_variant_id", c.ItemVariant.ID, 0))
+	assert.False(t, isRegistered(c.Partnership.ID, "supplier_item_code", "SC-001", c.ID), "katalog yang diupdate tidak ikut dihitung")
+	assert.False(t, isRegistered(model.DummyPartnership().ID, "supplier_item_code", "SC-001", 0))
+
+	c.IsDeleted = 1
+	c.Save("IsDeleted")
+	assert.False(t, isRegistered(c.Partnership.ID, "supplier_item_code", "SC-001", 0))
+
+	_, e := ShowSupplierCatalogue("id", c.ID)
+	assert.Error(t, e, "katalog yang sudah dihapus tidak bisa ditampilkan")
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("tax_invoice_range")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	tc := model.DummyTaxCode()
+	id := common.Encrypt(tc.ID)
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/tax/code", "GET", http.StatusOK},
+		{"/v1/tax/code/" + id, "GET", http.StatusOK},
+		{"/v1/tax/code/999999", "GET", http.StatusNotFound},
+		{"/v1/tax/code/" + id + "/archive", "PUT", http.StatusOK},
+		{"/v1/tax/code/" + id + "/unarchive", "PUT", http.StatusOK},
+		{"/v1/tax/invoice-range", "GET", http.StatusOK},
+		{"/v1/tax/efaktur/output?period=2019-02", "GET", http.StatusOK},
+		{"/v1/tax/efaktur/input?period=2019-02", "GET", http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestCashierCanOnlyReadTax(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+
+	ng.GET("/v1/tax/code").Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	ng.POST("/v1/tax/code").
+		SetJSON(tester.D{"code": "PPN10", "name": "PPN 10%", "rate": 10}).
+		Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+		})
+}
+
+func TestHandler_CreateTaxCode(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	code := common.RandomStr(6)
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"code": code, "name": "PPN", "rate": 11}, http.StatusOK},
+		// code sudah dipakai
+		{tester.D{"code": code, "name": "PPN", "rate": 11}, http.StatusUnprocessableEntity},
+		// exempt tidak boleh memiliki rate
+		{tester.D{"code": common.RandomStr(6), "name": "Bebas", "rate": 11, "is_exempt": 1}, http.StatusUnprocessableEntity},
+		// non exempt harus memiliki rate
+		{tester.D{"code": common.RandomStr(6), "name": "PPN", "rate": 0}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/tax/code").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
+
+func TestHandler_CreateInvoiceRange(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	prefix := "010.000-" + common.RandomNumeric(2)
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"code_prefix": prefix, "start_number": 1, "end_number": 100}, http.StatusOK},
+		// range overlap dengan range sebelumnya
+		{tester.D{"code_prefix": prefix, "start_number": 50, "end_number": 150}, http.StatusUnprocessableEntity},
+		// end number lebih kecil dari start number
+		{tester.D{"code_prefix": prefix, "start_number": 300, "end_number": 200}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/tax/invoice-range").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/env"
+	"git.qasico.com/cuxs/orm"
+)
+
+// taxLine dpp dan ppn per baris item untuk export e-faktur.
+type taxLine struct {
+	Code      string
+	Name      string
+	UnitPrice float64
+	Quantity  float32
+	Total     float64
+	Discount  float64
+	Base      float64
+	Tax       float64
+}
+
+// headerOutput header csv e-faktur untuk pajak keluaran.
+var headerOutput = [][]string{
+	{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN", "FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI"},
+	{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN", "PROPINSI", "KODE_POS", "NOMOR_TELEPON"},
+	{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN", "TARIF_PPNBM", "PPNBM"},
+}
+
+// headerInput header csv e-faktur untuk pajak masukan.
+var headerInput = [][]string{
+	{"FM", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "IS_CREDITABLE"},
+}
+
+// ParsePeriod mengubah periode format YYYY-MM menjadi tanggal awal dan akhir bulan.
+func ParsePeriod(period string) (start time.Time, end time.Time, e error) {
+	if start, e = time.Parse("2006-01", period); e == nil {
+		end = start.AddDate(0, 1, -1)
+	}
+	return
+}
+
+// ExportOutputVAT membuat file csv e-faktur pajak keluaran dari sales invoice
+// yang sudah memiliki nomor faktur pajak pada periode yang dipilih.
+func ExportOutputVAT(period string) (fileDir string, e error) {
+	var start, end time.Time
+	if start, end, e = ParsePeriod(period); e != nil {
+		return "", e
+	}
+
+	var invoices []*model.SalesInvoice
+	if _, e = orm.NewOrm().Raw("SELECT * FROM sales_invoice WHERE is_deleted = 0 AND tax_invoice_number IS NOT NULL AND tax_invoice_number != '' "+
+		"AND recognition_date BETWEEN ? AND ? ORDER BY tax_invoice_number ASC", start.Format("2006-01-02"), end.Format("2006-01-02")).QueryRows(&invoices); e != nil {
+		return "", e
+	}
+
+	rows := append([][]string{}, headerOutput...)
+	for _, si := range invoices {
+		si.SalesOrder.Read()
+		customer := &model.Partnership{}
+		if si.SalesOrder.Customer != nil {
+			customer = si.SalesOrder.Customer
+			customer.Read()
+		}
+
+		kd, fg, number := splitTaxInvoiceNumber(si.TaxInvoiceNumber)
+		lines, base, tax := salesLines(si)
+
+		rows = append(rows, []string{
+			"FK", kd, fg, number,
+			fmt.Sprintf("%d", int(si.RecognitionDate.Month())),
+			fmt.Sprintf("%d", si.RecognitionDate.Year()),
+			si.RecognitionDate.Format("02/01/2006"),
+			formatNpwp(customer.Npwp), customer.FullName, customer.Address,
+			formatNumber(base), formatNumber(tax), "0", "", "0", "0", "0", "0", si.Code,
+		})
+		rows = append(rows, []string{"LT", formatNpwp(customer.Npwp), customer.FullName, customer.Address, "", "", "", "", "", "", customer.City, customer.Province, "", customer.Phone})
+
+		for _, l := range lines {
+			rows = append(rows, []string{
+				"OF", l.Code, l.Name, formatNumber(l.UnitPrice), fmt.Sprintf("%g", l.Quantity), formatNumber(l.Total),
+				formatNumber(l.Discount), formatNumber(l.Base), formatNumber(l.Tax), "0", "0",
+			})
+		}
+	}
+
+	return writeCsv(fmt.Sprintf("PajakKeluaran-%s.csv", period), rows)
+}
+
+// ExportInputVAT membuat file csv e-faktur pajak masukan dari purchase invoice
+// yang memiliki nomor faktur pajak dari supplier pada periode yang dipilih.
+func ExportInputVAT(period string) (fileDir string, e error) {
+	var start, end time.Time
+	if start, end, e = ParsePeriod(period); e != nil {
+		return "", e
+	}
+
+	var invoices []*model.PurchaseInvoice
+	if _, e = orm.NewOrm().Raw("SELECT * FROM purchase_invoice WHERE is_deleted = 0 AND tax_invoice_number IS NOT NULL AND tax_invoice_number != '' "+
+		"AND recognition_date BETWEEN ? AND ? ORDER BY recognition_date ASC", start.Format("2006-01-02"), end.Format("2006-01-02")).QueryRows(&invoices); e != nil {
+		return "", e
+	}
+
+	rows := append([][]string{}, headerInput...)
+	for _, pi := range invoices {
+		pi.PurchaseOrder.Read()
+		supplier := pi.PurchaseOrder.Supplier
+		supplier.Read()
+
+		kd, fg, number := splitTaxInvoiceNumber(pi.TaxInvoiceNumber)
+		base, tax := purchaseTotal(pi)
+
+		rows = append(rows, []string{
+			"FM", kd, fg, number,
+			fmt.Sprintf("%d", int(pi.RecognitionDate.Month())),
+			fmt.Sprintf("%d", pi.RecognitionDate.Year()),
+			pi.RecognitionDate.Format("02/01/2006"),
+			formatNpwp(supplier.Npwp), supplier.FullName, supplier.Address,
+			formatNumber(base), formatNumber(tax), "0", "1",
+		})
+	}
+
+	return writeCsv(fmt.Sprintf("PajakMasukan-%s.csv", period), rows)
+}
+
+// salesLines menghitung dpp dan ppn per item sales order yang ditagihkan pada sales invoice,
+// jika sales invoice hanya menagihkan sebagian dari sales order maka nilai dibagi proporsional.
+func salesLines(si *model.SalesInvoice) (lines []*taxLine, base float64, tax float64) {
+	so := si.SalesOrder
+
+	ratio := float64(1)
+	if so.TotalCharge > 0 && si.TotalAmount < so.TotalCharge {
+		ratio = si.TotalAmount / so.TotalCharge
+	}
+
+	var items []*model.SalesOrderItem
+	orm.NewOrm().QueryTable(new(model.SalesOrderItem)).Filter("sales_order_id", so.ID).RelatedSel("ItemVariant__Item", "TaxCode").All(&items)
+
+	for _, i := range items {
+		var lb, lt float64
+		if i.TaxCode != nil {
+			lb, lt = i.TaxBase, i.TaxAmount
+		} else {
+			// sales order lama yang masih memakai persentase tax pada order
+			lb = i.Subtotal
+			if so.TotalPrice > 0 {
+				lb = i.Subtotal - ((i.Subtotal / so.TotalPrice) * so.DiscountAmount)
+			}
+			lt = (lb * float64(so.Tax)) / float64(100)
+		}
+
+		l := &taxLine{
+			Code:      i.ItemVariant.Barcode,
+			Name:      i.ItemVariant.Item.ItemName + " " + i.ItemVariant.VariantName,
+			Quantity:  i.Quantity,
+			UnitPrice: i.UnitPrice,
+			Total:     common.FloatPrecision(i.UnitPrice*float64(i.Quantity), 0),
+			Base:      common.FloatPrecision(lb*ratio, 0),
+			Tax:       common.FloatPrecision(lt*ratio, 0),
+		}
+
+		// untuk harga yang sudah termasuk ppn, harga satuan yang dilaporkan adalah harga sebelum ppn
+		if IsInclusive(i.TaxCode) && i.Quantity > 0 {
+			l.UnitPrice, _ = Calculate(i.TaxCode, i.UnitPrice)
+			l.Total = common.FloatPrecision(l.UnitPrice*float64(i.Quantity), 0)
+		}
+		l.Discount = l.Total - l.Base
+		if l.Discount < 0 {
+			l.Discount = 0
+		}
+
+		lines = append(lines, l)
+		base += l.Base
+		tax += l.Tax
+	}
+
+	return
+}
+
+// purchaseTotal menghitung dpp dan ppn dari purchase order yang ditagihkan pada purchase invoice.
+func purchaseTotal(pi *model.PurchaseInvoice) (base float64, tax float64) {
+	po := pi.PurchaseOrder
+
+	ratio := float64(1)
+	if po.TotalCharge > 0 && pi.TotalAmount < po.TotalCharge {
+		ratio = pi.TotalAmount / po.TotalCharge
+	}
+
+	var items []*model.PurchaseOrderItem
+	orm.NewOrm().QueryTable(new(model.PurchaseOrderItem)).Filter("purchase_order_id", po.ID).All(&items)
+
+	var hasTaxCode bool
+	for _, i := range items {
+		if i.TaxCode != nil {
+			hasTaxCode = true
+			base += i.TaxBase
+			tax += i.TaxAmount
+		}
+	}
+
+	// purchase order lama yang masih memakai persentase tax pada order
+	if !hasTaxCode {
+		tax = po.TaxAmount
+		base = po.TotalCharge - po.TaxAmount - po.ShipmentCost
+	}
+
+	return common.FloatPrecision(base*ratio, 0), common.FloatPrecision(tax*ratio, 0)
+}
+
+// splitTaxInvoiceNumber memisahkan nomor faktur pajak menjadi kode jenis transaksi,
+// flag pengganti dan nomor seri faktur, contoh 010.000-19.00000001 menjadi 01, 0 dan 0001900000001
+func splitTaxInvoiceNumber(n string) (kd string, fg string, number string) {
+	digits := regexp.MustCompile(`\D`).ReplaceAllString(n, "")
+	if len(digits) != 16 {
+		return "", "", digits
+	}
+
+	return digits[0:2], digits[2:3], digits[3:]
+}
+
+// formatNpwp mengambil digit npwp, npwp kosong diisi dengan 15 digit nol.
+func formatNpwp(npwp string) string {
+	if digits := regexp.MustCompile(`\D`).ReplaceAllString(npwp, ""); digits != "" {
+		return digits
+	}
+
+	return strings.Repeat("0", 15)
+}
+
+func formatNumber(n float64) string {
+	return fmt.Sprintf("%.0f", n)
+}
+
+func writeCsv(filename string, rows [][]string) (fileDir string, e error) {
+	dir := env.GetString("EXPORT_DIRECTORY", os.TempDir())
+	fileDir = fmt.Sprintf("%s/%s", dir, filename)
+
+	var f *os.File
+	if f, e = os.Create(fileDir); e == nil {
+		defer f.Close()
+
+		w := csv.NewWriter(f)
+		if e = w.WriteAll(rows); e == nil {
+			w.Flush()
+			e = w.Error()
+		}
+	}
+
+	return
+}
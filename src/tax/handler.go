@@ -0,0 +1,218 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax
+
+import (
+	"path/filepath"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for tax.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/code", h.get, auth.CheckPrivilege("tax_read"))
+	r.GET("/code/:id", h.show, auth.CheckPrivilege("tax_read"))
+	r.POST("/code", h.create, auth.CheckPrivilege("tax_manage"))
+	r.PUT("/code/:id", h.update, auth.CheckPrivilege("tax_manage"))
+	r.PUT("/code/:id/archive", h.archive, auth.CheckPrivilege("tax_manage"))
+	r.PUT("/code/:id/unarchive", h.unarchive, auth.CheckPrivilege("tax_manage"))
+	r.GET("/invoice-range", h.getRange, auth.CheckPrivilege("tax_read"))
+	r.POST("/invoice-range", h.createRange, auth.CheckPrivilege("tax_manage"))
+	r.PUT("/sales-invoice/:id/assign", h.assign, auth.CheckPrivilege("tax_invoice_allocate"))
+	r.GET("/efaktur/output", h.exportOutput, auth.CheckPrivilege("tax_export"))
+	r.GET("/efaktur/input", h.exportInput, auth.CheckPrivilege("tax_export"))
+}
+
+// get endpoint to handle get http method.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.TaxCode
+	if data, total, e = GetTaxCodes(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// show endpoint to get detail tax code.
+func (h *Handler) show(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.TaxCode
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if m, e = ShowTaxCode("id", id); e == nil {
+			ctx.Data(m)
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint to handle post http method.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r createRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// update endpoint to handle put http method.
+func (h *Handler) update(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r updateRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.Session, e = auth.UserSession(ctx); e == nil {
+			if r.TaxCode, e = ShowTaxCode("id", id); e == nil {
+				if e = ctx.Bind(&r); e == nil {
+					m := r.Transform()
+					if e = m.Save("name", "rate", "is_inclusive", "is_exempt", "note", "updated_by", "updated_at"); e == nil {
+						ctx.Data(m)
+					}
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// archive endpoint untuk mengarsipkan tax code agar tidak bisa dipilih lagi.
+func (h *Handler) archive(c echo.Context) (e error) {
+	return h.setArchived(c, int8(1))
+}
+
+// unarchive endpoint untuk mengaktifkan kembali tax code.
+func (h *Handler) unarchive(c echo.Context) (e error) {
+	return h.setArchived(c, int8(0))
+}
+
+func (h *Handler) setArchived(c echo.Context, archived int8) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var m *model.TaxCode
+	var session *auth.SessionData
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if session, e = auth.UserSession(ctx); e == nil {
+			if m, e = ShowTaxCode("id", id); e == nil {
+				m.IsArchived = archived
+				m.UpdatedBy = session.User
+				m.UpdatedAt = time.Now()
+				if e = m.Save("is_archived", "updated_by", "updated_at"); e == nil {
+					ctx.Data(m)
+				}
+			} else {
+				e = echo.ErrNotFound
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// getRange endpoint untuk mengambil semua range nomor faktur pajak.
+func (h *Handler) getRange(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.TaxInvoiceRange
+	if data, total, e = GetTaxInvoiceRanges(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// createRange endpoint untuk menyimpan range nomor faktur pajak yang diberikan oleh DJP.
+func (h *Handler) createRange(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r rangeRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// assign endpoint untuk memberikan nomor faktur pajak pada sales invoice
+// yang belum mendapatkan nomor, misalnya karena range nomor sempat habis.
+func (h *Handler) assign(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var id int64
+	var r assignRequest
+	if id, e = common.Decrypt(ctx.Param("id")); e == nil {
+		if r.SalesInvoice, e = showSalesInvoice("id", id); e == nil {
+			if e = ctx.Bind(&r); e == nil {
+				if e = AssignTaxInvoiceNumber(r.SalesInvoice); e == nil {
+					ctx.Data(r.SalesInvoice)
+				}
+			}
+		} else {
+			e = echo.ErrNotFound
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// exportOutput endpoint untuk download csv e-faktur pajak keluaran, parameter period format YYYY-MM.
+func (h *Handler) exportOutput(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var file string
+	if file, e = ExportOutputVAT(ctx.QueryParam("period")); e == nil {
+		return ctx.Attachment(file, filepath.Base(file))
+	}
+
+	return ctx.Serve(e)
+}
+
+// exportInput endpoint untuk download csv e-faktur pajak masukan, parameter period format YYYY-MM.
+func (h *Handler) exportInput(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var file string
+	if file, e = ExportInputVAT(ctx.QueryParam("period")); e == nil {
+		return ctx.Attachment(file, filepath.Base(file))
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax
+
+import (
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// Line item dokumen yang ppn nya dihitung per baris,
+// diimplementasikan oleh sales order item dan purchase order item.
+type Line interface {
+	LineTax() (*model.TaxCode, float64)
+	SetLineTax(base float64, tax float64)
+}
+
+// ValidateTaxCode validasi tax code pada item dokumen dengan key field,
+// tax per item tidak bisa digabung dengan persentase tax pada order.
+func ValidateTaxCode(o *validation.Output, field string, id string, orderTax float32) {
+	if id == "" {
+		return
+	}
+
+	if tcID, e := common.Decrypt(id); e != nil {
+		o.Failure(field, "tax_code_id not valid")
+	} else if tc, e := ShowTaxCode("id", tcID); e != nil || tc.IsArchived == int8(1) {
+		o.Failure(field, "tax_code_id doesn't exist")
+	}
+
+	if orderTax > 0 {
+		o.Failure("tax", "tax can't be filled if item already has tax code")
+	}
+}
+
+// TaxCodeByID mengambil tax code dari encrypted id, nil jika id kosong.
+func TaxCodeByID(id string) *model.TaxCode {
+	if id == "" {
+		return nil
+	}
+
+	tcID, _ := common.Decrypt(id)
+	tc, _ := ShowTaxCode("id", tcID)
+
+	return tc
+}
+
+// ApplyLineTax menghitung dpp dan ppn setiap item yang memiliki tax code,
+// mengembalikan total ppn dan total ppn yang belum termasuk pada harga (exclusive).
+func ApplyLineTax(lines []Line, totalPrice float64, discountAmount float64) (taxAmount float64, exclusive float64, applied bool) {
+	for _, l := range lines {
+		tc, subtotal := l.LineTax()
+		if tc == nil {
+			l.SetLineTax(0, 0)
+			continue
+		}
+
+		applied = true
+		base, amount := Allocate(tc, subtotal, totalPrice, discountAmount)
+		l.SetLineTax(base, amount)
+		taxAmount += amount
+		if !IsInclusive(tc) {
+			exclusive += amount
+		}
+	}
+
+	return
+}
@@ -0,0 +1,202 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/orm"
+	"git.qasico.com/cuxs/validation"
+)
+
+// createRequest data struct that stored request data when requesting an create tax code process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	Code        string            `json:"code" valid:"required|lte:20"`
+	Name        string            `json:"name" valid:"required|lte:45"`
+	Rate        float64           `json:"rate" valid:"gte:0|lte:100"`
+	IsInclusive int8              `json:"is_inclusive" valid:"in:0,1"`
+	IsExempt    int8              `json:"is_exempt" valid:"in:0,1"`
+	Note        string            `json:"note"`
+	Session     *auth.SessionData `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if _, e := ShowTaxCode("code", r.Code); e == nil {
+		o.Failure("code", "This tax code already exist")
+	}
+
+	if r.IsExempt == int8(1) && r.Rate > 0 {
+		o.Failure("rate", "Rate must be 0 for exempt tax code")
+	}
+
+	if r.IsExempt == int8(0) && r.Rate <= 0 {
+		o.Failure("rate", "Rate must be greater than 0")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.TaxCode {
+	return &model.TaxCode{
+		Code:        r.Code,
+		Name:        r.Name,
+		Rate:        r.Rate,
+		IsInclusive: r.IsInclusive,
+		IsExempt:    r.IsExempt,
+		IsArchived:  int8(0),
+		Note:        r.Note,
+		CreatedBy:   r.Session.User,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// updateRequest data struct that stored request data when requesting an update tax code process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type updateRequest struct {
+	Name        string            `json:"name" valid:"required|lte:45"`
+	Rate        float64           `json:"rate" valid:"gte:0|lte:100"`
+	IsInclusive int8              `json:"is_inclusive" valid:"in:0,1"`
+	IsExempt    int8              `json:"is_exempt" valid:"in:0,1"`
+	Note        string            `json:"note"`
+	Session     *auth.SessionData `json:"-"`
+	TaxCode     *model.TaxCode    `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *updateRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.IsExempt == int8(1) && r.Rate > 0 {
+		o.Failure("rate", "Rate must be 0 for exempt tax code")
+	}
+
+	if r.IsExempt == int8(0) && r.Rate <= 0 {
+		o.Failure("rate", "Rate must be greater than 0")
+	}
+
+	// tax code yang sudah dipakai tidak bisa diubah tarifnya
+	// agar perhitungan pada dokumen lama tidak berubah
+	if r.Rate != r.TaxCode.Rate || r.IsInclusive != r.TaxCode.IsInclusive || r.IsExempt != r.TaxCode.IsExempt {
+		var used int64
+		orm.NewOrm().Raw("SELECT (SELECT COUNT(*) FROM sales_order_item WHERE tax_code_id = ?) + (SELECT COUNT(*) FROM purchase_order_item WHERE tax_code_id = ?)", r.TaxCode.ID, r.TaxCode.ID).QueryRow(&used)
+		if used > 0 {
+			o.Failure("rate", "Tax code already used, create a new tax code instead")
+		}
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *updateRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *updateRequest) Transform() *model.TaxCode {
+	tc := r.TaxCode
+	tc.Name = r.Name
+	tc.Rate = r.Rate
+	tc.IsInclusive = r.IsInclusive
+	tc.IsExempt = r.IsExempt
+	tc.Note = r.Note
+	tc.UpdatedBy = r.Session.User
+	tc.UpdatedAt = time.Now()
+
+	return tc
+}
+
+// rangeRequest data struct that stored request data when requesting an create tax invoice range process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type rangeRequest struct {
+	CodePrefix  string            `json:"code_prefix" valid:"required|lte:20"`
+	StartNumber int64             `json:"start_number" valid:"required|gt:0"`
+	EndNumber   int64             `json:"end_number" valid:"required|gt:0"`
+	Note        string            `json:"note"`
+	Session     *auth.SessionData `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *rangeRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.EndNumber < r.StartNumber {
+		o.Failure("end_number", "End number must be greater than start number")
+	}
+
+	// range nomor faktur pajak tidak boleh beririsan dengan range lain dengan prefix yang sama
+	var overlap int64
+	orm.NewOrm().Raw("SELECT COUNT(*) FROM tax_invoice_range WHERE code_prefix = ? AND start_number <= ? AND end_number >= ?", r.CodePrefix, r.EndNumber, r.StartNumber).QueryRow(&overlap)
+	if overlap > 0 {
+		o.Failure("start_number", "Tax invoice number range overlaps with existing range")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *rangeRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *rangeRequest) Transform() *model.TaxInvoiceRange {
+	return &model.TaxInvoiceRange{
+		CodePrefix:  r.CodePrefix,
+		StartNumber: r.StartNumber,
+		EndNumber:   r.EndNumber,
+		LastNumber:  r.StartNumber - 1,
+		IsActive:    int8(1),
+		Note:        r.Note,
+		CreatedBy:   r.Session.User,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// assignRequest data struct that stored request data when requesting an assign tax invoice number process.
+type assignRequest struct {
+	SalesInvoice *model.SalesInvoice `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *assignRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if r.SalesInvoice.TaxInvoiceNumber != "" {
+		o.Failure("tax_invoice_number", "Sales invoice already has tax invoice number")
+	} else if !IsTaxable(r.SalesInvoice) {
+		o.Failure("sales_invoice", "Sales invoice doesn't require tax invoice number")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *assignRequest) Messages() map[string]string {
+	return map[string]string{}
+}
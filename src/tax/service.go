@@ -0,0 +1,193 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax
+
+import (
+	"errors"
+	"fmt"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// ErrRangeExhausted error ketika tidak ada lagi nomor faktur pajak yang bisa dipakai.
+var ErrRangeExhausted = errors.New("tax invoice number range is exhausted")
+
+// GetTaxCodes get all data tax_code that matched with query request parameters.
+// returning slices of tax code, total data without limit and error.
+func GetTaxCodes(rq *orm.RequestQuery) (m *[]model.TaxCode, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.TaxCode))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.TaxCode
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// ShowTaxCode untuk mengambil data detail tax code berdasarkan param
+func ShowTaxCode(field string, values ...interface{}) (*model.TaxCode, error) {
+	m := new(model.TaxCode)
+	o := orm.NewOrm()
+	if err := o.QueryTable(m).Filter(field, values...).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetTaxInvoiceRanges get all data tax_invoice_range that matched with query request parameters.
+// returning slices of tax invoice range, total data without limit and error.
+func GetTaxInvoiceRanges(rq *orm.RequestQuery) (m *[]model.TaxInvoiceRange, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.TaxInvoiceRange))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.TaxInvoiceRange
+	if _, err = q.All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// showSalesInvoice untuk mengambil data sales invoice berdasarkan param
+func showSalesInvoice(field string, values ...interface{}) (*model.SalesInvoice, error) {
+	m := new(model.SalesInvoice)
+	o := orm.NewOrm().QueryTable(m)
+	if err := o.Filter(field, values...).Filter("is_deleted", int8(0)).Limit(1).One(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Calculate menghitung dasar pengenaan pajak (dpp) dan ppn dari nominal,
+// jika tax code inclusive maka nominal dianggap sudah termasuk ppn.
+func Calculate(tc *model.TaxCode, amount float64) (base float64, tax float64) {
+	if tc == nil || tc.IsExempt == int8(1) || tc.Rate <= 0 {
+		return common.FloatPrecision(amount, 0), 0
+	}
+
+	if tc.IsInclusive == int8(1) {
+		base = common.FloatPrecision((amount*float64(100))/(float64(100)+tc.Rate), 0)
+		tax = common.FloatPrecision(amount-base, 0)
+	} else {
+		base = common.FloatPrecision(amount, 0)
+		tax = common.FloatPrecision((amount*tc.Rate)/float64(100), 0)
+	}
+
+	return
+}
+
+// Allocate menghitung dpp dan ppn per baris item setelah dikurangi porsi diskon order,
+// diskon order dibagi secara proporsional sesuai subtotal baris terhadap total price.
+func Allocate(tc *model.TaxCode, subtotal float64, totalPrice float64, discountAmount float64) (base float64, tax float64) {
+	amount := subtotal
+	if totalPrice > 0 && discountAmount > 0 {
+		amount = subtotal - ((subtotal / totalPrice) * discountAmount)
+	}
+
+	return Calculate(tc, amount)
+}
+
+// IsInclusive cek apakah tax code menggunakan harga yang sudah termasuk ppn.
+func IsInclusive(tc *model.TaxCode) bool {
+	return tc != nil && tc.IsInclusive == int8(1)
+}
+
+// FormatTaxInvoiceNumber membuat nomor faktur pajak dari prefix dan nomor urut,
+// contoh prefix 010.000-19 dan nomor 1 menjadi 010.000-19.00000001
+func FormatTaxInvoiceNumber(prefix string, number int64) string {
+	return fmt.Sprintf("%s.%08d", prefix, number)
+}
+
+// NextTaxInvoiceNumber mengambil nomor faktur pajak berikutnya dari range yang masih aktif,
+// nomor diambil secara berurutan dari range yang paling lama dibuat.
+func NextTaxInvoiceNumber() (number string, e error) {
+	var ranges []*model.TaxInvoiceRange
+	if _, e = orm.NewOrm().Raw("SELECT * FROM tax_invoice_range WHERE is_active = 1 AND last_number < end_number ORDER BY id ASC").QueryRows(&ranges); e != nil {
+		return "", e
+	}
+
+	for _, r := range ranges {
+		// range yang habis dipakai request lain dilewati
+		if number, e = takeTaxInvoiceNumber(r.ID); e == nil || e != orm.ErrNoRows {
+			return
+		}
+	}
+
+	return "", ErrRangeExhausted
+}
+
+// takeTaxInvoiceNumber memajukan nomor urut range dalam satu transaksi, row range dikunci
+// dengan SELECT ... FOR UPDATE sehingga nomor yang sama tidak bisa diambil oleh request lain.
+func takeTaxInvoiceNumber(rangeID int64) (number string, e error) {
+	o := orm.NewOrm()
+	if e = o.Begin(); e != nil {
+		return
+	}
+
+	r := new(model.TaxInvoiceRange)
+	if e = o.Raw("SELECT * FROM tax_invoice_range WHERE id = ? AND is_active = 1 AND last_number < end_number FOR UPDATE", rangeID).QueryRow(r); e != nil {
+		o.Rollback()
+		return
+	}
+
+	r.LastNumber++
+	if _, e = o.Raw("UPDATE tax_invoice_range SET last_number = ? WHERE id = ?", r.LastNumber, r.ID).Exec(); e != nil {
+		o.Rollback()
+		return
+	}
+
+	if e = o.Commit(); e == nil {
+		number = FormatTaxInvoiceNumber(r.CodePrefix, r.LastNumber)
+	}
+
+	return
+}
+
+// IsTaxable cek apakah sales invoice memerlukan faktur pajak,
+// sales order yang tidak memiliki ppn atau walk in customer tidak memerlukan faktur pajak.
+func IsTaxable(si *model.SalesInvoice) bool {
+	if si.SalesOrder == nil || si.SalesOrder.Read() != nil || si.SalesOrder.TaxAmount <= 0 {
+		return false
+	}
+
+	if si.SalesOrder.Customer == nil || si.SalesOrder.Customer.Read() != nil {
+		return false
+	}
+
+	return si.SalesOrder.Customer.IsDefault != int8(1)
+}
+
+// AssignTaxInvoiceNumber memberikan nomor faktur pajak pada sales invoice,
+// sales invoice yang sudah memiliki nomor faktur pajak tidak akan diubah.
+func AssignTaxInvoiceNumber(si *model.SalesInvoice) (e error) {
+	if si.TaxInvoiceNumber != "" || !IsTaxable(si) {
+		return nil
+	}
+
+	if si.TaxInvoiceNumber, e = NextTaxInvoiceNumber(); e == nil {
+		e = si.Save("TaxInvoiceNumber")
+	}
+
+	return
+}
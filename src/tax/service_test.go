@@ -0,0 +1,145 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tax
+
+import (
+	"sync"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/orm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculate(t *testing.T) {
+	exclusive := &model.TaxCode{Rate: 11}
+	base, tax := Calculate(exclusive, 100000)
+	assert.Equal(t, float64(100000), base)
+	assert.Equal(t, float64(11000), tax)
+
+	inclusive := &model.TaxCode{Rate: 11, IsInclusive: 1}
+	base, tax = Calculate(inclusive, 111000)
+	assert.Equal(t, float64(100000), base)
+	assert.Equal(t, float64(11000), tax)
+
+	exempt := &model.TaxCode{IsExempt: 1}
+	base, tax = Calculate(exempt, 100000)
+	assert.Equal(t, float64(100000), base)
+	assert.Equal(t, float64(0), tax)
+
+	base, tax = Calculate(nil, 100000)
+	assert.Equal(t, float64(100000), base)
+	assert.Equal(t, float64(0), tax)
+}
+
+func TestAllocate(t *testing.T) {
+	tc := &model.TaxCode{Rate: 10}
+
+	// diskon order 20000 dari total 200000, baris ini menanggung setengahnya
+	base, tax := Allocate(tc, 100000, 200000, 20000)
+	assert.Equal(t, float64(90000), base)
+	assert.Equal(t, float64(9000), tax)
+
+	base, tax = Allocate(tc, 100000, 200000, 0)
+	assert.Equal(t, float64(100000), base)
+	assert.Equal(t, float64(10000), tax)
+}
+
+func TestApplyLineTax(t *testing.T) {
+	so := &model.SalesOrderItem{TaxCode: &model.TaxCode{Rate: 10}, Subtotal: 100000}
+	po := &model.PurchaseOrderItem{TaxCode: &model.TaxCode{Rate: 10, IsInclusive: 1}, Subtotal: 110000}
+	none := &model.SalesOrderItem{Subtotal: 50000, TaxBase: 1, TaxAmount: 1}
+
+	taxAmount, exclusive, applied := ApplyLineTax([]Line{so, po, none}, 260000, 0)
+	assert.True(t, applied)
+	assert.Equal(t, float64(20000), taxAmount)
+	assert.Equal(t, float64(10000), exclusive)
+	assert.Equal(t, float64(100000), so.TaxBase)
+	assert.Equal(t, float64(100000), po.TaxBase)
+	assert.Equal(t, float64(0), none.TaxAmount)
+
+	_, _, applied = ApplyLineTax([]Line{none}, 50000, 0)
+	assert.False(t, applied)
+}
+
+func TestTaxCodeByID(t *testing.T) {
+	assert.Nil(t, TaxCodeByID(""))
+}
+
+func TestFormatTaxInvoiceNumber(t *testing.T) {
+	assert.Equal(t, "010.000-19.00000001", FormatTaxInvoiceNumber("010.000-19", 1))
+
+	kd, fg, number := splitTaxInvoiceNumber("010.000-19.00000001")
+	assert.Equal(t, "01", kd)
+	assert.Equal(t, "0", fg)
+	assert.Equal(t, "0001900000001", number)
+
+	kd, _, _ = splitTaxInvoiceNumber("123")
+	assert.Equal(t, "", kd)
+}
+
+func TestParsePeriod(t *testing.T) {
+	start, end, e := ParsePeriod("2019-02")
+	assert.NoError(t, e)
+	assert.Equal(t, "2019-02-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2019-02-28", end.Format("2006-01-02"))
+
+	_, _, e = ParsePeriod("02-2019")
+	assert.Error(t, e)
+}
+
+func TestNextTaxInvoiceNumber(t *testing.T) {
+	orm.NewOrm().Raw("UPDATE tax_invoice_range SET is_active = 0").Exec()
+
+	r := model.DummyTaxInvoiceRange()
+	r.CodePrefix = "010.000-19"
+	r.StartNumber = 5
+	r.EndNumber = 6
+	r.LastNumber = 4
+	r.Save()
+
+	n, e := NextTaxInvoiceNumber()
+	assert.NoError(t, e)
+	assert.Equal(t, "010.000-19.00000005", n)
+
+	n, e = NextTaxInvoiceNumber()
+	assert.NoError(t, e)
+	assert.Equal(t, "010.000-19.00000006", n)
+
+	_, e = NextTaxInvoiceNumber()
+	assert.Equal(t, ErrRangeExhausted, e)
+}
+
+func TestNextTaxInvoiceNumberConcurrent(t *testing.T) {
+	orm.NewOrm().Raw("UPDATE tax_invoice_range SET is_active = 0").Exec()
+
+	r := model.DummyTaxInvoiceRange()
+	r.CodePrefix = "010.000-19"
+	r.StartNumber = 1
+	r.EndNumber = 10
+	r.LastNumber = 0
+	r.IsActive = 1
+	r.Save()
+
+	// nomor yang diambil bersamaan tidak boleh sama
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	numbers := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if n, e := NextTaxInvoiceNumber(); e == nil {
+				mu.Lock()
+				numbers[n] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, numbers, 10)
+}
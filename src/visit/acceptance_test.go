@@ -0,0 +1,143 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package visit_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+	"git.qasico.com/mj/api/test"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/common/tester"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	test.Setup()
+
+	// run tests
+	res := m.Run()
+
+	// cleanup
+	test.DataCleanUp("visit_log")
+
+	os.Exit(res)
+}
+
+func TestARouting(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	model.DummyVisitLog()
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/visit", "GET", http.StatusOK},
+		{"/v1/visit/plan", "GET", http.StatusOK},
+		{"/v1/visit/plan?date=2018-03-05&sales_person_id=" + common.Encrypt(user.ID), "GET", http.StatusOK},
+		{"/v1/visit/plan?sales_person_id=xxx", "GET", http.StatusNotFound},
+		{"/v1/visit/report", "GET", http.StatusOK},
+		{"/v1/visit/report?start=2018-03-01&end=2018-03-31", "GET", http.StatusOK},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
+
+func TestHandler_CreateVisitLog(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(1)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	customer := model.DummyPartnership()
+	customer.PartnershipType = "customer"
+	customer.IsDeleted = 0
+	customer.Save()
+
+	so := model.DummySalesOrder()
+	so.Customer = customer
+	so.IsDeleted = 0
+	so.Save()
+
+	other := model.DummySalesOrder()
+	other.IsDeleted = 0
+	other.Save()
+
+	cid := common.Encrypt(customer.ID)
+	var data = []struct {
+		req      tester.D
+		expected int
+	}{
+		{tester.D{"customer_id": cid, "outcome": "no_order", "note": "toko tutup"}, http.StatusOK},
+		{tester.D{"customer_id": cid, "outcome": "order_taken", "sales_order_id": common.Encrypt(so.ID)}, http.StatusOK},
+		{tester.D{"customer_id": cid, "outcome": "payment_collected", "amount_collected": 250000}, http.StatusOK},
+		// sales order milik customer lain
+		{tester.D{"customer_id": cid, "outcome": "order_taken", "sales_order_id": common.Encrypt(other.ID)}, http.StatusUnprocessableEntity},
+		// order taken tanpa sales order
+		{tester.D{"customer_id": cid, "outcome": "order_taken"}, http.StatusUnprocessableEntity},
+		// payment collected tanpa nominal
+		{tester.D{"customer_id": cid, "outcome": "payment_collected"}, http.StatusUnprocessableEntity},
+		// outcome tidak valid
+		{tester.D{"customer_id": cid, "outcome": "closed"}, http.StatusUnprocessableEntity},
+		{tester.D{"customer_id": "999999", "outcome": "no_order"}, http.StatusUnprocessableEntity},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, tes := range data {
+		ng.POST("/v1/visit").
+			SetJSON(tes.req).
+			Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+				assert.Equal(t, tes.expected, res.Code, fmt.Sprintf("\nreason: Validation Not Matched,\ndata: %v , \nresponse: %v", tes.req, res.Body.String()))
+			})
+	}
+
+	vl := &model.VisitLog{Partnership: customer, Outcome: "payment_collected"}
+	assert.NoError(t, vl.Read("Partnership", "Outcome"))
+	assert.Equal(t, float64(250000), vl.AmountCollected)
+	assert.Equal(t, user.ID, vl.SalesPerson.ID)
+}
+
+func TestVisitIsCashier(t *testing.T) {
+	user := model.DummyUserPriviledgeWithUsergroup(4)
+	sd, _ := auth.Login(user)
+	token := "Bearer " + sd.Token
+
+	var routers = []struct {
+		endpoint string
+		method   string
+		expected int
+	}{
+		{"/v1/visit/plan", "GET", http.StatusUnauthorized},
+		{"/v1/visit", "POST", http.StatusUnauthorized},
+		{"/v1/visit/report", "GET", http.StatusUnauthorized},
+	}
+
+	ng := tester.New()
+	ng.SetHeader(tester.H{"Authorization": token})
+	for _, ep := range routers {
+		ng.Method = ep.method
+		ng.Path = ep.endpoint
+		ng.Run(test.Router(), func(res tester.HTTPResponse, req tester.HTTPRequest) {
+			assert.Equal(t, ep.expected, res.Code, fmt.Sprintf("Should has 'endpoint %s' with method '%s'", ep.endpoint, ep.method))
+		})
+	}
+}
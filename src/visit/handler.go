@@ -0,0 +1,125 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package visit
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/cuxs"
+	"github.com/labstack/echo"
+)
+
+// Handler collection handler for visit.
+type Handler struct{}
+
+// URLMapping declare endpoint with handler function.
+func (h *Handler) URLMapping(r *echo.Group) {
+	r.GET("/plan", h.plan, auth.CheckPrivilege("visit_read"))
+	r.GET("", h.get, auth.CheckPrivilege("visit_read"))
+	r.POST("", h.create, auth.CheckPrivilege("visit_log"))
+	r.GET("/report", h.report, auth.CheckPrivilege("visit_report"))
+}
+
+// plan endpoint untuk mengambil rencana kunjungan harian sales person,
+// parameter date format YYYY-MM-DD dan sales_person_id, default hari ini dan user yang sedang login.
+func (h *Handler) plan(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var session *auth.SessionData
+	if session, e = auth.UserSession(ctx); e != nil {
+		return ctx.Serve(e)
+	}
+
+	date := time.Now()
+	if d := ctx.QueryParam("date"); d != "" {
+		if date, e = time.Parse("2006-01-02", d); e != nil {
+			return ctx.Serve(e)
+		}
+	}
+
+	salesPersonID := session.User.ID
+	if sp := ctx.QueryParam("sales_person_id"); sp != "" {
+		if salesPersonID, e = common.Decrypt(sp); e != nil {
+			return echo.ErrNotFound
+		}
+	}
+
+	var data []*Plan
+	if data, e = GetPlan(salesPersonID, date); e == nil {
+		ctx.Data(data, int64(len(data)))
+	}
+
+	return ctx.Serve(e)
+}
+
+// get endpoint untuk mengambil semua visit log.
+func (h *Handler) get(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+	rq := ctx.RequestQuery()
+
+	var total int64
+	var data *[]model.VisitLog
+	if data, total, e = GetVisitLogs(rq); e == nil {
+		ctx.Data(data, total)
+	}
+
+	return ctx.Serve(e)
+}
+
+// create endpoint untuk mencatat hasil kunjungan ke customer.
+func (h *Handler) create(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	var r createRequest
+	if r.Session, e = auth.UserSession(ctx); e == nil {
+		if e = ctx.Bind(&r); e == nil {
+			m := r.Transform()
+			if e = m.Save(); e == nil {
+				ctx.Data(m)
+			}
+		}
+	}
+
+	return ctx.Serve(e)
+}
+
+// report endpoint untuk ringkasan hasil kunjungan per sales person,
+// parameter start dan end format YYYY-MM-DD, default bulan berjalan.
+func (h *Handler) report(c echo.Context) (e error) {
+	ctx := c.(*cuxs.Context)
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	end := now
+	if s := ctx.QueryParam("start"); s != "" {
+		if start, e = time.Parse("2006-01-02", s); e != nil {
+			return ctx.Serve(e)
+		}
+	}
+
+	if s := ctx.QueryParam("end"); s != "" {
+		if end, e = time.Parse("2006-01-02", s); e != nil {
+			return ctx.Serve(e)
+		}
+	}
+
+	var salesPersonID int64
+	if sp := ctx.QueryParam("sales_person_id"); sp != "" {
+		if salesPersonID, e = common.Decrypt(sp); e != nil {
+			return echo.ErrNotFound
+		}
+	}
+
+	var data []*Report
+	if data, e = GetReport(start, end, salesPersonID); e == nil {
+		ctx.Data(data, int64(len(data)))
+	}
+
+	return ctx.Serve(e)
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package visit
+
+import (
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+	"git.qasico.com/mj/api/src/auth"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/validation"
+)
+
+// createRequest data struct that stored request data when requesting an create visit log process.
+// All data must be provided and must be match with specification validation below.
+// handler function should be bind this with context to matches incoming request
+// data keys to the defined json tag.
+type createRequest struct {
+	CustomerID      string            `json:"customer_id" valid:"required"`
+	SalesPersonID   string            `json:"sales_person_id"`
+	SalesOrderID    string            `json:"sales_order_id"`
+	VisitDate       time.Time         `json:"visit_date"`
+	Outcome         string            `json:"outcome" valid:"required|in:order_taken,no_order,payment_collected"`
+	AmountCollected float64           `json:"amount_collected" valid:"gte:0"`
+	Note            string            `json:"note"`
+	Session         *auth.SessionData `json:"-"`
+
+	Customer    *model.Partnership `json:"-"`
+	SalesPerson *model.User        `json:"-"`
+	SalesOrder  *model.SalesOrder  `json:"-"`
+}
+
+// Validate implement validation.Requests interfaces.
+func (r *createRequest) Validate() *validation.Output {
+	o := &validation.Output{Valid: true}
+
+	if id, e := common.Decrypt(r.CustomerID); e == nil {
+		r.Customer = &model.Partnership{ID: id, PartnershipType: "customer", IsDeleted: int8(0)}
+		if e = r.Customer.Read("ID", "PartnershipType", "IsDeleted"); e != nil {
+			o.Failure("customer_id", "customer_id doesn't exist")
+		}
+	} else {
+		o.Failure("customer_id", "customer_id cannot be decrypt")
+	}
+
+	// sales person default adalah user yang sedang login
+	r.SalesPerson = r.Session.User
+	if r.SalesPersonID != "" {
+		if id, e := common.Decrypt(r.SalesPersonID); e == nil {
+			r.SalesPerson = &model.User{ID: id, IsActive: int8(1)}
+			if e = r.SalesPerson.Read("ID", "IsActive"); e != nil {
+				o.Failure("sales_person_id", "sales_person_id doesn't exist")
+			}
+		} else {
+			o.Failure("sales_person_id", "sales_person_id cannot be decrypt")
+		}
+	}
+
+	if r.Outcome == "order_taken" {
+		if id, e := common.Decrypt(r.SalesOrderID); e == nil {
+			r.SalesOrder = &model.SalesOrder{ID: id, IsDeleted: int8(0)}
+			if e = r.SalesOrder.Read("ID", "IsDeleted"); e != nil {
+				o.Failure("sales_order_id", "sales_order_id doesn't exist")
+			} else if r.Customer != nil && r.SalesOrder.Customer != nil && r.SalesOrder.Customer.ID != r.Customer.ID {
+				o.Failure("sales_order_id", "sales order doesn't belong to the customer")
+			}
+		} else {
+			o.Failure("sales_order_id", "sales_order_id is required when order is taken")
+		}
+	}
+
+	if r.Outcome == "payment_collected" && r.AmountCollected <= 0 {
+		o.Failure("amount_collected", "amount_collected must be greater than 0 when payment is collected")
+	}
+
+	return o
+}
+
+// Messages implement validation.Requests interfaces
+// return custom messages when validation fails.
+func (r *createRequest) Messages() map[string]string {
+	return map[string]string{}
+}
+
+// Transform transforming request into model.
+func (r *createRequest) Transform() *model.VisitLog {
+	m := &model.VisitLog{
+		Partnership: r.Customer,
+		SalesPerson: r.SalesPerson,
+		VisitDate:   r.VisitDate,
+		Outcome:     r.Outcome,
+		Note:        r.Note,
+		CreatedBy:   r.Session.User,
+		CreatedAt:   time.Now(),
+	}
+
+	if m.VisitDate.IsZero() {
+		m.VisitDate = time.Now()
+	}
+
+	if r.Outcome == "order_taken" {
+		m.SalesOrder = r.SalesOrder
+	}
+
+	if r.Outcome == "payment_collected" {
+		m.AmountCollected = r.AmountCollected
+	}
+
+	return m
+}
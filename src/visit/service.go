@@ -0,0 +1,286 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package visit
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"git.qasico.com/cuxs/common"
+	"git.qasico.com/cuxs/orm"
+)
+
+// reorderLookback jumlah hari riwayat order yang dipakai untuk menentukan saran reorder.
+const reorderLookback = 180
+
+// weekdays nama hari yang bisa dipakai pada partnership.visit_day,
+// bisa menggunakan bahasa inggris maupun bahasa indonesia.
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"minggu":    time.Sunday,
+	"senin":     time.Monday,
+	"selasa":    time.Tuesday,
+	"rabu":      time.Wednesday,
+	"kamis":     time.Thursday,
+	"jumat":     time.Friday,
+	"sabtu":     time.Saturday,
+}
+
+// Plan data kunjungan satu customer pada rencana kunjungan harian.
+type Plan struct {
+	Customer       *model.Partnership    `json:"customer"`
+	OpenInvoices   []*model.SalesInvoice `json:"open_invoices"`
+	Outstanding    float64               `json:"outstanding"`
+	LastOrder      *model.SalesOrder     `json:"last_order"`
+	SuggestedItems []*SuggestedItem      `json:"suggested_items"`
+	VisitLogs      []*model.VisitLog     `json:"visit_logs"`
+}
+
+// SuggestedItem item yang disarankan untuk diorder ulang oleh customer,
+// berdasarkan rata-rata jarak hari antar order item tersebut.
+type SuggestedItem struct {
+	ItemVariant       *model.ItemVariant `json:"item_variant"`
+	OrderCount        int64              `json:"order_count"`
+	AverageInterval   int64              `json:"average_interval"`
+	DaysSinceLast     int64              `json:"days_since_last"`
+	SuggestedQuantity float32            `json:"suggested_quantity"`
+}
+
+// Report ringkasan hasil kunjungan per sales person.
+type Report struct {
+	SalesPersonID    int64   `json:"-"`
+	SalesPerson      string  `json:"sales_person_id"`
+	FullName         string  `json:"full_name"`
+	TotalVisit       int64   `json:"total_visit"`
+	OrderTaken       int64   `json:"order_taken"`
+	NoOrder          int64   `json:"no_order"`
+	PaymentCollected int64   `json:"payment_collected"`
+	OrderAmount      float64 `json:"order_amount"`
+	AmountCollected  float64 `json:"amount_collected"`
+}
+
+// reorderRow hasil query riwayat order item variant customer.
+type reorderRow struct {
+	ItemVariantID int64
+	OrderCount    int64
+	Quantity      float32
+	SpanDays      int64
+	DaysSinceLast int64
+}
+
+// VisitDays mengubah isi partnership.visit_day menjadi daftar hari,
+// beberapa hari bisa dipisahkan dengan koma, contoh "monday, thursday".
+func VisitDays(visitDay string) (days []time.Weekday) {
+	for _, d := range strings.Split(visitDay, ",") {
+		if wd, ok := weekdays[strings.ToLower(strings.TrimSpace(d))]; ok {
+			days = append(days, wd)
+		}
+	}
+
+	return
+}
+
+// IsScheduled cek apakah customer dijadwalkan untuk dikunjungi pada tanggal tersebut.
+func IsScheduled(customer *model.Partnership, date time.Time) bool {
+	for _, d := range VisitDays(customer.VisitDay) {
+		if d == date.Weekday() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScheduledCustomers mengambil customer milik sales person yang dijadwalkan dikunjungi pada tanggal tersebut.
+func ScheduledCustomers(salesPersonID int64, date time.Time) (m []*model.Partnership, e error) {
+	var customers []*model.Partnership
+	o := orm.NewOrm()
+	if _, e = o.QueryTable(new(model.Partnership)).Filter("partnership_type", "customer").Filter("sales_person_id", salesPersonID).
+		Filter("is_deleted", 0).Filter("is_archived", 0).OrderBy("full_name").All(&customers); e != nil {
+		return nil, e
+	}
+
+	for _, c := range customers {
+		if IsScheduled(c, date) {
+			m = append(m, c)
+		}
+	}
+
+	return
+}
+
+// GetPlan membuat rencana kunjungan harian sales person beserta tagihan yang belum lunas,
+// order terakhir dan saran reorder setiap customer.
+func GetPlan(salesPersonID int64, date time.Time) (plans []*Plan, e error) {
+	var customers []*model.Partnership
+	if customers, e = ScheduledCustomers(salesPersonID, date); e != nil {
+		return nil, e
+	}
+
+	plans = []*Plan{}
+	for _, c := range customers {
+		p := &Plan{Customer: c}
+		if p.OpenInvoices, e = openInvoices(c.ID); e != nil {
+			return nil, e
+		}
+
+		for _, si := range p.OpenInvoices {
+			p.Outstanding += si.TotalAmount - si.TotalPaid
+		}
+
+		p.LastOrder = lastOrder(c.ID)
+		if p.SuggestedItems, e = SuggestedItems(c.ID, date); e != nil {
+			return nil, e
+		}
+
+		o := orm.NewOrm()
+		o.QueryTable(new(model.VisitLog)).Filter("partnership_id", c.ID).Filter("visit_date", date.Format("2006-01-02")).All(&p.VisitLogs)
+
+		plans = append(plans, p)
+	}
+
+	return
+}
+
+// SuggestedItems mengambil item yang sudah waktunya diorder ulang oleh customer,
+// item dianggap perlu diorder ulang jika jarak hari dari order terakhir sudah melewati
+// rata-rata jarak order sebelumnya, item yang baru sekali diorder tidak disarankan.
+func SuggestedItems(customerID int64, date time.Time) (m []*SuggestedItem, e error) {
+	var rows []*reorderRow
+	o := orm.NewOrm()
+	if _, e = o.Raw("SELECT soi.item_variant_id, COUNT(DISTINCT so.id) AS order_count, SUM(soi.quantity) AS quantity, "+
+		"DATEDIFF(MAX(so.recognition_date), MIN(so.recognition_date)) AS span_days, "+
+		"DATEDIFF(?, MAX(so.recognition_date)) AS days_since_last "+
+		"FROM sales_order_item soi "+
+		"INNER JOIN sales_order so ON so.id = soi.sales_order_id "+
+		"WHERE so.customer_id = ? AND so.is_deleted = 0 AND so.document_status IN ('new', 'active', 'finished') "+
+		"AND so.recognition_date BETWEEN ? AND ? "+
+		"GROUP BY soi.item_variant_id", date.Format("2006-01-02"), customerID,
+		date.AddDate(0, 0, -reorderLookback).Format("2006-01-02"), date.Format("2006-01-02")).QueryRows(&rows); e != nil {
+		return nil, e
+	}
+
+	m = []*SuggestedItem{}
+	for _, r := range rows {
+		if r.OrderCount < 2 {
+			continue
+		}
+
+		interval := r.SpanDays / (r.OrderCount - 1)
+		if r.DaysSinceLast < interval {
+			continue
+		}
+
+		iv := &model.ItemVariant{ID: r.ItemVariantID}
+		if iv.Read() != nil || iv.IsDeleted == int8(1) || iv.IsArchived == int8(1) {
+			continue
+		}
+
+		m = append(m, &SuggestedItem{
+			ItemVariant:       iv,
+			OrderCount:        r.OrderCount,
+			AverageInterval:   interval,
+			DaysSinceLast:     r.DaysSinceLast,
+			SuggestedQuantity: float32(common.FloatPrecision(float64(r.Quantity)/float64(r.OrderCount), 2)),
+		})
+	}
+
+	// item yang paling lama terlambat diorder ditampilkan lebih dulu
+	sort.SliceStable(m, func(i, j int) bool {
+		return m[i].DaysSinceLast-m[i].AverageInterval > m[j].DaysSinceLast-m[j].AverageInterval
+	})
+
+	return
+}
+
+// openInvoices mengambil sales invoice customer yang belum lunas.
+func openInvoices(customerID int64) (m []*model.SalesInvoice, e error) {
+	o := orm.NewOrm()
+	_, e = o.Raw("SELECT si.* FROM sales_invoice si "+
+		"INNER JOIN sales_order so ON so.id = si.sales_order_id "+
+		"WHERE so.customer_id = ? AND so.is_deleted = 0 AND si.is_deleted = 0 "+
+		"AND si.document_status != 'finished' AND si.total_paid < si.total_amount "+
+		"ORDER BY si.due_date ASC", customerID).QueryRows(&m)
+
+	if m == nil {
+		m = []*model.SalesInvoice{}
+	}
+
+	return
+}
+
+// lastOrder mengambil sales order terakhir customer, nil jika belum pernah order.
+func lastOrder(customerID int64) *model.SalesOrder {
+	m := new(model.SalesOrder)
+	o := orm.NewOrm()
+	if e := o.QueryTable(m).Filter("customer_id", customerID).Filter("is_deleted", 0).
+		Exclude("document_status", "approved_cancel").OrderBy("-recognition_date", "-id").Limit(1).One(m); e != nil {
+		return nil
+	}
+
+	return m
+}
+
+// GetVisitLogs get all data visit_log that matched with query request parameters.
+// returning slices of visit log, total data without limit and error.
+func GetVisitLogs(rq *orm.RequestQuery) (m *[]model.VisitLog, total int64, err error) {
+	// make new orm query
+	q, _ := rq.Query(new(model.VisitLog))
+
+	// get total data
+	if total, err = q.Count(); err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	// get data requested
+	var mx []model.VisitLog
+	if _, err = q.RelatedSel().All(&mx, rq.Fields...); err == nil {
+		return &mx, total, nil
+	}
+
+	// return error some thing went wrong
+	return nil, total, err
+}
+
+// GetReport mengambil ringkasan hasil kunjungan per sales person pada tanggal start sampai end,
+// salesPersonID 0 berarti semua sales person.
+func GetReport(start time.Time, end time.Time, salesPersonID int64) (m []*Report, e error) {
+	var args []interface{}
+	args = append(args, start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	where := ""
+	if salesPersonID != 0 {
+		where = "AND vl.sales_person_id = ? "
+		args = append(args, salesPersonID)
+	}
+
+	o := orm.NewOrm()
+	if _, e = o.Raw("SELECT vl.sales_person_id, u.full_name, COUNT(*) AS total_visit, "+
+		"SUM(vl.outcome = 'order_taken') AS order_taken, SUM(vl.outcome = 'no_order') AS no_order, "+
+		"SUM(vl.outcome = 'payment_collected') AS payment_collected, "+
+		"COALESCE(SUM(so.total_charge), 0) AS order_amount, COALESCE(SUM(vl.amount_collected), 0) AS amount_collected "+
+		"FROM visit_log vl "+
+		"INNER JOIN user u ON u.id = vl.sales_person_id "+
+		"LEFT JOIN sales_order so ON so.id = vl.sales_order_id "+
+		"WHERE vl.visit_date BETWEEN ? AND ? "+where+
+		"GROUP BY vl.sales_person_id, u.full_name ORDER BY u.full_name", args...).QueryRows(&m); e != nil {
+		return nil, e
+	}
+
+	for _, r := range m {
+		r.SalesPerson = common.Encrypt(r.SalesPersonID)
+	}
+
+	return
+}
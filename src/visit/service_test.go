@@ -0,0 +1,128 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package visit
+
+import (
+	"testing"
+	"time"
+
+	"git.qasico.com/mj/api/datastore/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dummyCustomer membuat customer milik sales person dengan jadwal kunjungan visitDay.
+func dummyCustomer(sp *model.User, visitDay string) *model.Partnership {
+	c := model.DummyPartnership()
+	c.PartnershipType = "customer"
+	c.SalesPerson = sp
+	c.VisitDay = visitDay
+	c.IsDeleted = 0
+	c.IsArchived = 0
+	c.Save()
+
+	return c
+}
+
+// dummyOrder membuat sales order customer untuk item variant pada tanggal tersebut.
+func dummyOrder(c *model.Partnership, iv *model.ItemVariant, quantity float32, date time.Time) *model.SalesOrder {
+	so := model.DummySalesOrder()
+	so.Customer = c
+	so.RecognitionDate = date
+	so.DocumentStatus = "finished"
+	so.IsDeleted = 0
+	so.Save()
+
+	soi := model.DummySalesOrderItem()
+	soi.SalesOrder = so
+	soi.ItemVariant = iv
+	soi.Quantity = quantity
+	soi.Save()
+
+	return so
+}
+
+func TestVisitDays(t *testing.T) {
+	assert.Equal(t, []time.Weekday{time.Monday, time.Thursday}, VisitDays("Monday, thursday"))
+	assert.Equal(t, []time.Weekday{time.Tuesday}, VisitDays("selasa"))
+	assert.Empty(t, VisitDays("setiap hari"))
+
+	// 2018-03-05 adalah hari senin
+	monday := time.Date(2018, 3, 5, 0, 0, 0, 0, time.Local)
+	assert.True(t, IsScheduled(&model.Partnership{VisitDay: "senin,kamis"}, monday))
+	assert.False(t, IsScheduled(&model.Partnership{VisitDay: "friday"}, monday))
+}
+
+func TestGetPlan(t *testing.T) {
+	sp := model.DummyUser()
+	monday := time.Date(2018, 3, 5, 0, 0, 0, 0, time.Local)
+
+	c := dummyCustomer(sp, "monday")
+	dummyCustomer(sp, "friday")
+
+	iv := model.DummyItemVariant()
+	iv.IsDeleted = 0
+	iv.IsArchived = 0
+	iv.Save()
+
+	// order setiap 14 hari, order terakhir 20 hari yang lalu
+	dummyOrder(c, iv, 10, monday.AddDate(0, 0, -48))
+	dummyOrder(c, iv, 20, monday.AddDate(0, 0, -34))
+	so := dummyOrder(c, iv, 30, monday.AddDate(0, 0, -20))
+
+	si := model.DummySalesInvoice()
+	si.SalesOrder = so
+	si.TotalAmount = 500000
+	si.TotalPaid = 200000
+	si.DocumentStatus = "active"
+	si.IsDeleted = 0
+	si.Save()
+
+	plans, e := GetPlan(sp.ID, monday)
+	assert.NoError(t, e)
+	assert.Len(t, plans, 1)
+	assert.Equal(t, c.ID, plans[0].Customer.ID)
+	assert.Equal(t, so.ID, plans[0].LastOrder.ID)
+	assert.Len(t, plans[0].OpenInvoices, 1)
+	assert.Equal(t, float64(300000), plans[0].Outstanding)
+
+	assert.Len(t, plans[0].SuggestedItems, 1)
+	assert.Equal(t, iv.ID, plans[0].SuggestedItems[0].ItemVariant.ID)
+	assert.Equal(t, int64(14), plans[0].SuggestedItems[0].AverageInterval)
+	assert.Equal(t, float32(20), plans[0].SuggestedItems[0].SuggestedQuantity)
+
+	// belum waktunya reorder
+	items, e := SuggestedItems(c.ID, monday.AddDate(0, 0, -10))
+	assert.NoError(t, e)
+	assert.Empty(t, items)
+}
+
+func TestGetReport(t *testing.T) {
+	sp := model.DummyUser()
+	c := dummyCustomer(sp, "monday")
+	date := time.Date(2018, 3, 5, 0, 0, 0, 0, time.Local)
+
+	for _, outcome := range []string{"no_order", "payment_collected", "payment_collected"} {
+		vl := model.DummyVisitLog()
+		vl.Partnership = c
+		vl.SalesPerson = sp
+		vl.SalesOrder = nil
+		vl.VisitDate = date
+		vl.Outcome = outcome
+		vl.AmountCollected = 0
+		if outcome == "payment_collected" {
+			vl.AmountCollected = 150000
+		}
+		vl.Save()
+	}
+
+	m, e := GetReport(date, date, sp.ID)
+	assert.NoError(t, e)
+	assert.Len(t, m, 1)
+	assert.Equal(t, int64(3), m[0].TotalVisit)
+	assert.Equal(t, int64(1), m[0].NoOrder)
+	assert.Equal(t, int64(2), m[0].PaymentCollected)
+	assert.Equal(t, float64(300000), m[0].AmountCollected)
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 PT. Qasico Teknologi Indonesia. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SMTPMessage email yang diterima oleh SMTPServer.
+type SMTPMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// SMTPServer smtp server lokal sebagai pengganti smtp server asli
+// pada saat testing, semua email yang dikirim akan ditampung di memory.
+type SMTPServer struct {
+	Addr     string
+	listener net.Listener
+	mutex    sync.Mutex
+	messages []SMTPMessage
+}
+
+// StartSMTPServer menjalankan smtp server lokal dan mengarahkan
+// environment SMTP_HOST dan SMTP_PORT ke server tersebut.
+func StartSMTPServer() (s *SMTPServer, e error) {
+	s = new(SMTPServer)
+	if s.listener, e = net.Listen("tcp", "127.0.0.1:0"); e != nil {
+		return nil, e
+	}
+
+	s.Addr = s.listener.Addr().String()
+	host, port, _ := net.SplitHostPort(s.Addr)
+	os.Setenv("SMTP_HOST", host)
+	os.Setenv("SMTP_PORT", port)
+	os.Setenv("SMTP_USERNAME", "")
+	os.Setenv("SMTP_PASSWORD", "")
+
+	go s.serve()
+
+	return s, nil
+}
+
+// Messages mengambil semua email yang sudah diterima.
+func (s *SMTPServer) Messages() []SMTPMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]SMTPMessage{}, s.messages...)
+}
+
+// Reset menghapus semua email yang sudah diterima.
+func (s *SMTPServer) Reset() {
+	s.mutex.Lock()
+	s.messages = nil
+	s.mutex.Unlock()
+}
+
+// Close menghentikan smtp server.
+func (s *SMTPServer) Close() error {
+	return s.listener.Close()
+}
+
+// Port mengambil port yang dipakai smtp server.
+func (s *SMTPServer) Port() int {
+	_, port, _ := net.SplitHostPort(s.Addr)
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, e := s.listener.Accept()
+		if e != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	var msg SMTPMessage
+	reply("220 localhost ESMTP")
+	for {
+		line, e := r.ReadString('\n')
+		if e != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg = SMTPMessage{From: strings.Trim(line[10:], " <>")}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.To = append(msg.To, strings.Trim(line[8:], " <>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data []string
+			for {
+				l, e := r.ReadString('\n')
+				if e != nil {
+					return
+				}
+				l = strings.TrimRight(l, "\r\n")
+				if l == "." {
+					break
+				}
+				data = append(data, strings.TrimPrefix(l, "."))
+			}
+			msg.Data = strings.Join(data, "\n")
+
+			s.mutex.Lock()
+			s.messages = append(s.messages, msg)
+			s.mutex.Unlock()
+
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
@@ -62,11 +62,14 @@ func DataCleanUp(tables ...string) {
 		ID    int
 	}{
 		{"application_menu", 34},
-		{"application_privilege", 485},
-		{"application_module", 161},
+		{"application_privilege", 648},
+		{"application_module", 233},
 		{"usergroup", 4},
 		{"user", 1},
-		{"application_setting", 16},
+		{"application_setting", 36},
+		{"tax_code", 3},
+		{"chart_of_account", 29},
+		{"expense_category", 4},
 	}
 
 	orm := orm.NewOrm()